@@ -78,6 +78,60 @@ func main() {
 		}
 	}
 
+	// ADR sub-subcommand man pages
+	for _, cmd := range help.AdrSubcommands {
+		filename := cmd.ManName() + ".1"
+		if err := write(dir, filename, help.FormatRoff(cmd, date)); err != nil {
+			fmt.Fprintf(os.Stderr, "gen-man: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Files sub-subcommand man pages
+	for _, cmd := range help.FilesSubcommands {
+		filename := cmd.ManName() + ".1"
+		if err := write(dir, filename, help.FormatRoff(cmd, date)); err != nil {
+			fmt.Fprintf(os.Stderr, "gen-man: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// LLM sub-subcommand man pages
+	for _, cmd := range help.LlmSubcommands {
+		filename := cmd.ManName() + ".1"
+		if err := write(dir, filename, help.FormatRoff(cmd, date)); err != nil {
+			fmt.Fprintf(os.Stderr, "gen-man: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Archive sub-subcommand man pages
+	for _, cmd := range help.ArchiveSubcommands {
+		filename := cmd.ManName() + ".1"
+		if err := write(dir, filename, help.FormatRoff(cmd, date)); err != nil {
+			fmt.Fprintf(os.Stderr, "gen-man: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Daemon sub-subcommand man pages
+	for _, cmd := range help.DaemonSubcommands {
+		filename := cmd.ManName() + ".1"
+		if err := write(dir, filename, help.FormatRoff(cmd, date)); err != nil {
+			fmt.Fprintf(os.Stderr, "gen-man: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Enrich sub-subcommand man pages
+	for _, cmd := range help.EnrichSubcommands {
+		filename := cmd.ManName() + ".1"
+		if err := write(dir, filename, help.FormatRoff(cmd, date)); err != nil {
+			fmt.Fprintf(os.Stderr, "gen-man: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Command sub-subcommand man pages
 	for _, cmd := range help.CommandSubcommands {
 		filename := cmd.ManName() + ".1"
@@ -0,0 +1,246 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/suykerbuyk/vibe-vault/internal/adr"
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/help"
+	"github.com/suykerbuyk/vibe-vault/internal/index"
+	"github.com/suykerbuyk/vibe-vault/internal/session"
+	"github.com/suykerbuyk/vibe-vault/internal/surface"
+)
+
+// runAdr dispatches `vv adr <list|promote|supersede>`.
+func runAdr(args []string) {
+	if len(args) > 0 {
+		sub, rest := args[0], args[1:]
+		var cmd help.Command
+		var run func(config.Config, *index.Index, string, []string)
+		switch sub {
+		case "list":
+			cmd, run = help.CmdAdrList, adrList
+		case "promote":
+			cmd, run = help.CmdAdrPromote, adrPromote
+		case "supersede":
+			cmd, run = help.CmdAdrSupersede, adrSupersede
+		}
+		if run != nil {
+			if wantsHelp(rest) {
+				fmt.Fprint(os.Stderr, help.FormatTerminal(cmd))
+				return
+			}
+			cfg := mustLoadConfig()
+			surface.EnforceWarnOnly(cfg.VaultPath)
+			idx, err := index.Load(cfg.StateDir())
+			if err != nil {
+				fatal("load index: %v", err)
+			}
+			project := flagValue(rest, "--project")
+			if project == "" {
+				cwd, _ := os.Getwd()
+				project = session.DetectProject(cwd)
+			}
+			if project == "" || project == "_unknown" {
+				fatal("cannot determine project; pass --project <name>")
+			}
+			run(cfg, idx, project, rest)
+			return
+		}
+	}
+
+	if wantsHelp(args) {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdAdr))
+		return
+	}
+	fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdAdr))
+	os.Exit(1)
+}
+
+// adrPositional returns args with flags (and the values of valueFlags)
+// removed.
+func adrPositional(args []string, valueFlags ...string) []string {
+	var out []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if strings.HasPrefix(a, "--") {
+			for _, f := range valueFlags {
+				if a == f {
+					i++
+					break
+				}
+			}
+			continue
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+func adrOptions(cfg config.Config, idx *index.Index, project string, args []string) adr.Options {
+	return adr.Options{
+		Sessions: flagValues(args, "--session"),
+		Flavor:   cfg.Flavor(),
+		Links:    idx.NoteLinks(project),
+	}
+}
+
+// adrRefresh regenerates history.md so the Architecture Decisions section
+// reflects the change.
+func adrRefresh(cfg config.Config, idx *index.Index) {
+	if _, err := index.GenerateContext(idx, cfg.VaultPath, index.ContextOptionsFor(cfg)); err != nil {
+		fmt.Fprintf(os.Stderr, "vv: warning: regenerate history: %v\n", err)
+	}
+}
+
+func adrList(cfg config.Config, idx *index.Index, project string, args []string) {
+	records, err := adr.List(cfg.VaultPath, project)
+	if err != nil {
+		fatal("list ADRs: %v", err)
+	}
+	jsonOut := hasFlag(args, "--json")
+
+	if hasFlag(args, "--candidates") {
+		cands := adr.Candidates(idx.ADRSources(project), records)
+		if jsonOut {
+			printJSON(cands)
+			return
+		}
+		if len(cands) == 0 {
+			fmt.Printf("No ADR candidates in %s.\n", project)
+			return
+		}
+		for _, c := range cands {
+			fmt.Printf("%s  %s  (%s)\n", c.Date, c.Text, c.Reason)
+		}
+		return
+	}
+
+	if jsonOut {
+		printJSON(records)
+		return
+	}
+	if len(records) == 0 {
+		fmt.Printf("No ADRs in %s. Try: vv adr list --candidates\n", project)
+		return
+	}
+	for _, r := range records {
+		line := fmt.Sprintf("%s  %-10s  %s", r.ID(), r.Status, r.Title)
+		if r.SupersededBy > 0 {
+			line += fmt.Sprintf("  → ADR-%04d", r.SupersededBy)
+		}
+		fmt.Println(line)
+	}
+}
+
+func adrPromote(cfg config.Config, idx *index.Index, project string, args []string) {
+	opts := adrOptions(cfg, idx, project, args)
+	pos := adrPositional(args, "--project", "--status", "--session")
+
+	if hasFlag(args, "--auto") {
+		records, err := adr.List(cfg.VaultPath, project)
+		if err != nil {
+			fatal("list ADRs: %v", err)
+		}
+		cands := adr.Candidates(idx.ADRSources(project), records)
+		if len(cands) == 0 {
+			fmt.Printf("No ADR candidates in %s.\n", project)
+			return
+		}
+		if hasFlag(args, "--dry-run") {
+			for _, c := range cands {
+				fmt.Printf("would propose  %s  (%s)\n", c.Text, c.Reason)
+			}
+			return
+		}
+		for _, c := range cands {
+			o := opts
+			o.Status, o.Sessions, o.Date = adr.Proposed, c.Sessions, c.Date
+			r, created, err := adr.Promote(cfg.VaultPath, project, c.Text, o)
+			if err != nil {
+				fatal("promote: %v", err)
+			}
+			if created {
+				fmt.Printf("proposed  %s  %s\n", r.ID(), r.Title)
+			}
+		}
+		adrRefresh(cfg, idx)
+		return
+	}
+
+	if len(pos) != 1 {
+		fatal("usage: vv adr promote \"<decision>\" | <number> | --auto [--project <name>]")
+	}
+
+	// A bare number accepts an existing proposed ADR.
+	if n, err := strconv.Atoi(pos[0]); err == nil {
+		r, err := adr.Accept(cfg.VaultPath, project, n, opts)
+		if err != nil {
+			fatal("%v", err)
+		}
+		fmt.Printf("accepted  %s  %s\n", r.ID(), r.Title)
+		adrRefresh(cfg, idx)
+		return
+	}
+
+	status, err := adr.ParseStatus(flagValue(args, "--status"))
+	if err != nil {
+		fatal("%v", err)
+	}
+	opts.Status = status
+	r, created, err := adr.Promote(cfg.VaultPath, project, pos[0], opts)
+	if err != nil {
+		fatal("promote: %v", err)
+	}
+	if created {
+		fmt.Printf("created  %s  %s\n  %s\n", r.ID(), r.Title, r.Path)
+	} else {
+		fmt.Printf("already recorded as %s  %s\n", r.ID(), r.Title)
+	}
+	adrRefresh(cfg, idx)
+}
+
+func adrSupersede(cfg config.Config, idx *index.Index, project string, args []string) {
+	pos := adrPositional(args, "--project", "--by", "--session")
+	usage := "usage: vv adr supersede <number> (\"<new decision>\" | --by <number>) [--project <name>]"
+	if len(pos) == 0 {
+		fatal(usage)
+	}
+	old, err := strconv.Atoi(pos[0])
+	if err != nil {
+		fatal(usage)
+	}
+	by := 0
+	if s := flagValue(args, "--by"); s != "" {
+		if by, err = strconv.Atoi(s); err != nil {
+			fatal("--by must be an ADR number")
+		}
+	}
+	text := strings.Join(pos[1:], " ")
+	if (by == 0) == (text == "") {
+		fatal(usage)
+	}
+
+	next, prev, err := adr.Supersede(cfg.VaultPath, project, old, text, by, adrOptions(cfg, idx, project, args))
+	if err != nil {
+		fatal("%v", err)
+	}
+	fmt.Printf("superseded  %s  %s\n", prev.ID(), prev.Title)
+	fmt.Printf("by          %s  %s\n", next.ID(), next.Title)
+	adrRefresh(cfg, idx)
+}
+
+func printJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fatal("encode: %v", err)
+	}
+}
@@ -0,0 +1,553 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/suykerbuyk/vibe-vault/internal/archive"
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/discover"
+	"github.com/suykerbuyk/vibe-vault/internal/help"
+	"github.com/suykerbuyk/vibe-vault/internal/index"
+	"github.com/suykerbuyk/vibe-vault/internal/surface"
+)
+
+const (
+	defaultDictSamples = 500
+	// maxSampleBytes caps each training sample; transcript structure
+	// repeats early, and the builder's cost grows with total input.
+	maxSampleBytes = 256 << 10
+)
+
+// runArchive handles `vv archive [--level N] [--no-dict]` and dispatches
+// `vv archive train-dict|pack|verify|restore|prune`. With [archive] pack set, new
+// archives go to the month's pack file instead of a loose file.
+func runArchive() {
+	args := os.Args[2:]
+	if len(args) > 0 {
+		switch args[0] {
+		case "train-dict":
+			runArchiveTrainDict(args[1:])
+			return
+		case "pack":
+			runArchivePack(args[1:])
+			return
+		case "verify":
+			runArchiveVerify(args[1:])
+			return
+		case "restore":
+			runArchiveRestore(args[1:])
+			return
+		case "prune":
+			runArchivePrune(args[1:])
+			return
+		}
+	}
+	if wantsHelp(args) {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdArchive))
+		return
+	}
+
+	cfg := mustLoadConfig()
+	if err := surface.EnforceFailStop(cfg.VaultPath); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	archiveDir := filepath.Join(cfg.StateDir(), "archive")
+
+	opts := archive.Options{Level: archiveLevel(args), Key: archiveKey(cfg)}
+	if !hasFlag(args, "--no-dict") {
+		d, err := archive.LatestDict(archiveDir)
+		if err != nil {
+			fatal("load dictionary: %v", err)
+		}
+		opts.Dict = d
+	}
+
+	st, err := archiveTranscripts(cfg, opts)
+	if err != nil {
+		fatal("%v", err)
+	}
+	fmt.Println(st)
+	if opts.Dict != nil && st.archived > 0 {
+		fmt.Printf("dictionary: v%04d (id %d)\n", opts.Dict.Version, opts.Dict.ID)
+	}
+}
+
+// archiveStats summarizes one archiveTranscripts pass.
+type archiveStats struct {
+	archived, skipped   int
+	totalSrc, totalArch int64
+}
+
+func (s archiveStats) String() string {
+	return fmt.Sprintf("archived: %d (%s → %s%s), skipped: %d",
+		s.archived, humanBytes(s.totalSrc), humanBytes(s.totalArch), ratio(s.totalSrc, s.totalArch), s.skipped)
+}
+
+// archiveTranscripts archives every indexed session's transcript that is
+// not archived yet; `vv archive` and the daemon's archive job share it.
+// Per-session failures are logged and skipped.
+func archiveTranscripts(cfg config.Config, opts archive.Options) (archiveStats, error) {
+	var st archiveStats
+	archiveDir := filepath.Join(cfg.StateDir(), "archive")
+	idx, err := index.Load(cfg.StateDir())
+	if err != nil {
+		return st, fmt.Errorf("load index: %w", err)
+	}
+
+	for _, entry := range idx.Entries {
+		transcriptPath := entry.TranscriptPath
+
+		// Fallback: try to discover if no TranscriptPath stored
+		if transcriptPath == "" {
+			defaultDir := defaultTranscriptDir()
+			if defaultDir != "" {
+				found, err := discover.FindBySessionID(defaultDir, entry.SessionID)
+				if err == nil {
+					transcriptPath = found
+				}
+			}
+		}
+
+		if transcriptPath == "" {
+			st.skipped++
+			continue
+		}
+
+		if archive.IsArchived(entry.SessionID, archiveDir) {
+			st.skipped++
+			continue
+		}
+
+		srcInfo, err := os.Stat(transcriptPath)
+		if err != nil {
+			st.skipped++
+			continue
+		}
+
+		var archSize int64
+		if cfg.Archive.Pack {
+			e, err := archive.PackWith(transcriptPath, archiveDir, entryMonth(entry, srcInfo.ModTime()), opts)
+			if err != nil {
+				log.Printf("error archiving %s: %v", entry.SessionID, err)
+				continue
+			}
+			archSize = e.Length
+		} else {
+			archPath, err := archive.ArchiveWith(transcriptPath, archiveDir, opts)
+			if err != nil {
+				log.Printf("error archiving %s: %v", entry.SessionID, err)
+				continue
+			}
+			archInfo, _ := os.Stat(archPath)
+			archSize = archInfo.Size()
+		}
+
+		st.totalSrc += srcInfo.Size()
+		st.totalArch += archSize
+		st.archived++
+	}
+	return st, nil
+}
+
+// runArchivePack handles `vv archive pack`: it moves every loose
+// {session-id}.jsonl.zst archive into the pack for its session's month.
+// Frames are copied as-is, so dictionaries and levels are kept.
+func runArchivePack(args []string) {
+	if wantsHelp(args) {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdArchivePack))
+		return
+	}
+
+	cfg := mustLoadConfig()
+	if err := surface.EnforceFailStop(cfg.VaultPath); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	archiveDir := filepath.Join(cfg.StateDir(), "archive")
+
+	idx, err := index.Load(cfg.StateDir())
+	if err != nil {
+		fatal("load index: %v", err)
+	}
+	loose, err := filepath.Glob(filepath.Join(archiveDir, "*.jsonl.zst"))
+	if err != nil {
+		fatal("list archives: %v", err)
+	}
+
+	var packed, failed int
+	var total int64
+	months := make(map[string]bool)
+	for _, path := range loose {
+		sessionID := strings.TrimSuffix(filepath.Base(path), ".jsonl.zst")
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		month := entryMonth(idx.Entries[sessionID], info.ModTime())
+		e, err := archive.MigrateToPack(sessionID, archiveDir, month)
+		if err != nil {
+			log.Printf("error packing %s: %v", sessionID, err)
+			failed++
+			continue
+		}
+		total += e.Length
+		months[month] = true
+		packed++
+	}
+
+	fmt.Printf("packed: %d (%s) into %d monthly packs, errors: %d\n",
+		packed, humanBytes(total), len(months), failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// runArchiveVerify handles `vv archive verify [--json]`: it decodes every
+// packed and loose archive and exits 1 if any fail.
+func runArchiveVerify(args []string) {
+	if wantsHelp(args) {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdArchiveVerify))
+		return
+	}
+
+	cfg := mustLoadConfig()
+	archiveDir := filepath.Join(cfg.StateDir(), "archive")
+	report, err := archive.Verify(archiveDir)
+	if err != nil {
+		fatal("verify: %v", err)
+	}
+
+	if hasFlag(args, "--json") {
+		printJSON(report)
+	} else {
+		fmt.Printf("packs: %d (%d sessions, %s), loose: %d (%s)\n",
+			report.Packs, report.Packed, humanBytes(report.PackedBytes),
+			report.Loose, humanBytes(report.LooseBytes))
+		for _, p := range report.Problems {
+			fmt.Printf("  FAIL %s: %s\n", p.Location, p.Error)
+		}
+		if len(report.Problems) == 0 {
+			fmt.Println("all archives verified")
+		}
+	}
+	if len(report.Problems) > 0 {
+		os.Exit(1)
+	}
+}
+
+// runArchiveRestore handles `vv archive restore <session-id> [--to
+// <path>] [--force]`. By default the transcript goes back to the path it
+// was captured from, so `claude --resume <session-id>` finds it again.
+func runArchiveRestore(args []string) {
+	if wantsHelp(args) {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdArchiveRestore))
+		return
+	}
+	to := flagValue(args, "--to")
+	force := hasFlag(args, "--force")
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--to":
+			i++
+		case "--force":
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	if len(rest) != 1 {
+		fatal("usage: vv archive restore <session-id> [--to <path>] [--force]")
+	}
+	sessionID := rest[0]
+
+	cfg := mustLoadConfig()
+	archiveDir := filepath.Join(cfg.StateDir(), "archive")
+	if !archive.IsArchived(sessionID, archiveDir) {
+		fatal("session %s is not archived", sessionID)
+	}
+
+	dest := to
+	if dest == "" {
+		idx, err := index.Load(cfg.StateDir())
+		if err != nil {
+			fatal("load index: %v", err)
+		}
+		dest = idx.Entries[sessionID].TranscriptPath
+		if dest == "" || strings.HasPrefix(dest, "zed:") {
+			fatal("no original transcript path recorded for %s; pass --to <path>", sessionID)
+		}
+	} else if fi, err := os.Stat(dest); (err == nil && fi.IsDir()) || strings.HasSuffix(dest, string(filepath.Separator)) {
+		dest = filepath.Join(dest, sessionID+".jsonl")
+	}
+	if _, err := os.Stat(dest); err == nil && !force {
+		fatal("%s already exists; pass --force to overwrite", dest)
+	}
+
+	n, err := archive.Restore(sessionID, archiveDir, dest)
+	if err != nil {
+		fatal("restore %s: %v", sessionID, err)
+	}
+	fmt.Printf("restored %s → %s (%s)\n", sessionID, dest, humanBytes(n))
+}
+
+// runArchivePrune handles `vv archive prune [--dry-run]`, applying the
+// [archive] retention settings (with per-project overrides) to each
+// indexed session by note date. An original under ~/.claude/projects is
+// only deleted when its archive decodes to exactly the same bytes.
+func runArchivePrune(args []string) {
+	if wantsHelp(args) {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdArchivePrune))
+		return
+	}
+	dryRun := hasFlag(args, "--dry-run")
+
+	cfg := mustLoadConfig()
+	if err := surface.EnforceFailStop(cfg.VaultPath); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	archiveDir := filepath.Join(cfg.StateDir(), "archive")
+	idx, err := index.Load(cfg.StateDir())
+	if err != nil {
+		fatal("load index: %v", err)
+	}
+
+	entries := make([]index.SessionEntry, 0, len(idx.Entries))
+	for _, e := range idx.Entries {
+		if e.Source == "zed" || strings.HasPrefix(e.TranscriptPath, "zed:") || e.Date == "" {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Date < entries[j].Date })
+
+	now := time.Now()
+	retention := make(map[string]config.ArchiveConfig)
+	originalsRoot := defaultTranscriptDir()
+
+	var originals, unverified int
+	var originalBytes, archiveBytes int64
+	var prune []string
+	for _, e := range entries {
+		rc, ok := retention[e.Project]
+		if !ok {
+			rc = cfg.WithProjectOverlay(e.Project).Archive
+			retention[e.Project] = rc
+		}
+
+		if expired(e.Date, rc.KeepOriginalsMonths, now) && isUnder(e.TranscriptPath, originalsRoot) {
+			if fi, err := os.Stat(e.TranscriptPath); err == nil {
+				if err := archive.MatchesOriginal(e.SessionID, archiveDir, e.TranscriptPath); err != nil {
+					log.Printf("keeping original %s: %v", e.SessionID, err)
+					unverified++
+				} else {
+					if dryRun {
+						fmt.Printf("would delete original %s (%s, %s)\n", e.TranscriptPath, e.Date, e.Project)
+					} else if err := os.Remove(e.TranscriptPath); err != nil {
+						log.Printf("error deleting %s: %v", e.TranscriptPath, err)
+						continue
+					}
+					originals++
+					originalBytes += fi.Size()
+				}
+			}
+		}
+
+		if expired(e.Date, rc.KeepMonths, now) && archive.IsArchived(e.SessionID, archiveDir) {
+			if dryRun {
+				fmt.Printf("would prune archive %s (%s, %s)\n", e.SessionID, e.Date, e.Project)
+			}
+			archiveBytes += archive.StoredSize(e.SessionID, archiveDir)
+			prune = append(prune, e.SessionID)
+		}
+	}
+
+	if !dryRun && len(prune) > 0 {
+		if archiveBytes, err = archive.Remove(archiveDir, prune); err != nil {
+			fatal("prune archives: %v", err)
+		}
+	}
+
+	verb, prefix := "deleted", ""
+	if dryRun {
+		verb, prefix = "to delete", "dry run — "
+	}
+	fmt.Printf("%soriginals %s: %d (%s), archives %s: %d (%s), originals kept unverified: %d\n",
+		prefix, verb, originals, humanBytes(originalBytes), verb, len(prune), humanBytes(archiveBytes), unverified)
+}
+
+// expired reports whether a session dated date (YYYY-MM-DD) is older than
+// keepMonths months; keepMonths 0 never expires.
+func expired(date string, keepMonths int, now time.Time) bool {
+	if keepMonths <= 0 {
+		return false
+	}
+	return date < now.AddDate(0, -keepMonths, 0).Format("2006-01-02")
+}
+
+// isUnder reports whether path lies inside root.
+func isUnder(path, root string) bool {
+	if path == "" || root == "" {
+		return false
+	}
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// entryMonth picks the pack month for a session: its note date when
+// indexed, else the transcript or archive mtime.
+func entryMonth(entry index.SessionEntry, mtime time.Time) string {
+	if len(entry.Date) >= 7 {
+		return entry.Date[:7]
+	}
+	return mtime.Format("2006-01")
+}
+
+// runArchiveTrainDict handles `vv archive train-dict [--samples N]
+// [--max-size BYTES] [--level N]`. It samples the most recent indexed
+// transcripts, trains the next dictionary version, and reports how much
+// smaller a held-out fifth of the samples compresses with it.
+func runArchiveTrainDict(args []string) {
+	if wantsHelp(args) {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdArchiveTrainDict))
+		return
+	}
+
+	cfg := mustLoadConfig()
+	if err := surface.EnforceFailStop(cfg.VaultPath); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	archiveDir := filepath.Join(cfg.StateDir(), "archive")
+
+	n := intFlag(args, "--samples")
+	if n == 0 {
+		n = defaultDictSamples
+	}
+	level := archiveLevel(args)
+
+	samples := transcriptSamples(cfg, archiveDir, n)
+	if len(samples) == 0 {
+		fatal("no transcripts to sample; run vv backfill first")
+	}
+	train, eval := splitSamples(samples)
+
+	d, err := archive.TrainDict(archiveDir, train, archive.TrainOptions{
+		MaxSize: intFlag(args, "--max-size"),
+		Level:   level,
+	})
+	if err != nil {
+		fatal("%v", err)
+	}
+	plain, withDict, err := archive.Savings(eval, d, level)
+	if err != nil {
+		fatal("measure savings: %v", err)
+	}
+
+	fmt.Printf("trained dictionary v%04d (id %d, %s) from %d transcripts\n",
+		d.Version, d.ID, humanBytes(int64(len(d.Data))), len(train))
+	fmt.Printf("  %s\n", d.Path)
+	saved := 0.0
+	if plain > 0 {
+		saved = 100 * float64(plain-withDict) / float64(plain)
+	}
+	fmt.Printf("held-out %d transcripts: %s without dictionary → %s with (%.1f%% smaller)\n",
+		len(eval), humanBytes(plain), humanBytes(withDict), saved)
+	fmt.Println("New archives use the newest dictionary; existing archives keep theirs.")
+}
+
+// archiveLevel parses --level as a zstd level.
+func archiveLevel(args []string) int {
+	level := intFlag(args, "--level")
+	if level > 22 {
+		fatal("--level must be between 1 and 22, got %d", level)
+	}
+	return level
+}
+
+// transcriptSamples reads up to n of the most recently captured Claude
+// Code transcripts, from their original path or the archive.
+func transcriptSamples(cfg config.Config, archiveDir string, n int) [][]byte {
+	idx, err := index.Load(cfg.StateDir())
+	if err != nil {
+		fatal("load index: %v", err)
+	}
+	entries := make([]index.SessionEntry, 0, len(idx.Entries))
+	for _, e := range idx.Entries {
+		if e.Source == "zed" || strings.HasPrefix(e.TranscriptPath, "zed:") {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].CreatedAt.After(entries[j].CreatedAt) })
+
+	var samples [][]byte
+	for _, e := range entries {
+		if len(samples) == n {
+			break
+		}
+		path, cleanup := e.TranscriptPath, func() {}
+		if _, err := os.Stat(path); path == "" || err != nil {
+			if !archive.IsArchived(e.SessionID, archiveDir) {
+				continue
+			}
+			var derr error
+			path, cleanup, derr = archive.Decompress(archive.ArchivePath(e.SessionID, archiveDir))
+			if derr != nil {
+				log.Printf("warning: decompress %s: %v", e.SessionID, derr)
+				continue
+			}
+		}
+		data, err := readPrefix(path, maxSampleBytes)
+		cleanup()
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		samples = append(samples, data)
+	}
+	return samples
+}
+
+func readPrefix(path string, n int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, n))
+}
+
+// splitSamples holds out every fifth sample for measuring savings. Too
+// few samples to spare any are used for both.
+func splitSamples(samples [][]byte) (train, eval [][]byte) {
+	if len(samples) < 10 {
+		return samples, samples
+	}
+	for i, s := range samples {
+		if i%5 == 4 {
+			eval = append(eval, s)
+		} else {
+			train = append(train, s)
+		}
+	}
+	return train, eval
+}
+
+// ratio formats a compression ratio suffix, e.g. ", 9.8:1".
+func ratio(src, dst int64) string {
+	if src == 0 || dst == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", %.1f:1", float64(src)/float64(dst))
+}
@@ -0,0 +1,41 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExpired(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		date string
+		keep int
+		want bool
+	}{
+		{"2026-04-17", 6, true},
+		{"2026-04-18", 6, false},
+		{"2020-01-01", 0, false},
+		{"2026-10-01", 1, false},
+	}
+	for _, c := range cases {
+		if got := expired(c.date, c.keep, now); got != c.want {
+			t.Errorf("expired(%s, %d) = %v, want %v", c.date, c.keep, got, c.want)
+		}
+	}
+}
+
+func TestIsUnder(t *testing.T) {
+	root := filepath.Join("/home", "dev", ".claude", "projects")
+	if !isUnder(filepath.Join(root, "-home-dev-x", "s.jsonl"), root) {
+		t.Error("transcript under root not recognized")
+	}
+	for _, p := range []string{"", "/tmp/s.jsonl", root + "-other/s.jsonl", filepath.Join(root, "..", "s.jsonl")} {
+		if isUnder(p, root) {
+			t.Errorf("isUnder(%q) = true", p)
+		}
+	}
+}
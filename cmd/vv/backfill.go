@@ -0,0 +1,158 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/suykerbuyk/vibe-vault/internal/backfill"
+	"github.com/suykerbuyk/vibe-vault/internal/discover"
+	"github.com/suykerbuyk/vibe-vault/internal/help"
+	"github.com/suykerbuyk/vibe-vault/internal/llm"
+	"github.com/suykerbuyk/vibe-vault/internal/staging"
+	"github.com/suykerbuyk/vibe-vault/internal/surface"
+)
+
+// runBackfill handles `vv backfill [path] [--workers N] [--llm-concurrency
+// N] [--no-enrich] [--json]`. Ctrl-C stops dispatching new transcripts
+// and commits what is already prepared; the next run skips everything
+// indexed so far.
+func runBackfill() {
+	args := os.Args[2:]
+	if wantsHelp(args) {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdBackfill))
+		return
+	}
+
+	cfg := mustLoadConfig()
+	if err := surface.EnforceFailStop(cfg.VaultPath); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	basePath := defaultTranscriptDir()
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; {
+		case a == "--workers" || a == "--llm-concurrency":
+			i++
+		case strings.HasPrefix(a, "-"):
+		default:
+			basePath = a
+		}
+	}
+	if basePath == "" {
+		fatal("cannot determine transcript directory")
+	}
+	opts := backfill.Options{
+		Workers:        intFlag(args, "--workers"),
+		LLMConcurrency: intFlag(args, "--llm-concurrency"),
+		StagingRoot:    staging.ResolveRoot(cfg.Staging.Root),
+	}
+	asJSON := hasFlag(args, "--json")
+
+	if !hasFlag(args, "--no-enrich") {
+		provider, err := llm.NewConfiguredProvider(cfg)
+		if err != nil {
+			log.Printf("warning: LLM provider init failed: %v", err)
+		}
+		opts.Provider = provider
+	}
+
+	out := os.Stdout
+	if asJSON {
+		out = os.Stderr
+	}
+	fmt.Fprintf(out, "Discovering transcripts in %s\n", basePath)
+	transcripts, err := discover.Discover(basePath)
+	if err != nil {
+		fatal("discover: %v", err)
+	}
+	fmt.Fprintf(out, "Found %d transcripts\n", len(transcripts))
+
+	// Redraw one progress line on a terminal; elsewhere (CI logs, pipes)
+	// print a line every 10% so the log stays readable.
+	var lastDraw time.Time
+	lastDecile := 0
+	tty := isTerminal(os.Stderr)
+	opts.OnProgress = func(p backfill.Progress) {
+		if tty {
+			if p.Done < p.Total && time.Since(lastDraw) < 200*time.Millisecond {
+				return
+			}
+			lastDraw = time.Now()
+			fmt.Fprintf(os.Stderr, "\r\033[K%s", p)
+			return
+		}
+		if decile := p.Done * 10 / max(p.Total, 1); decile > lastDecile {
+			lastDecile = decile
+			fmt.Fprintln(os.Stderr, p)
+		}
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer cancel()
+	report, err := backfill.Run(ctx, transcripts, opts, cfg)
+	if tty && report != nil && report.Found > report.Skipped {
+		fmt.Fprintln(os.Stderr)
+	}
+	if err != nil {
+		fatal("backfill: %v", err)
+	}
+
+	if asJSON {
+		printJSON(report)
+	} else {
+		printBackfillReport(report)
+	}
+	if report.Interrupted || len(report.Failures) > 0 {
+		os.Exit(1)
+	}
+}
+
+func printBackfillReport(r *backfill.Report) {
+	fmt.Printf("\nprocessed: %d, skipped: %d (already indexed or trivial), errors: %d\n",
+		r.Processed, r.Skipped, len(r.Failures))
+	if r.Enriched > 0 {
+		fmt.Printf("enriched: %d\n", r.Enriched)
+	}
+	if r.Patched > 0 {
+		fmt.Printf("patched: %d (added transcript paths to existing entries)\n", r.Patched)
+	}
+	fmt.Printf("elapsed: %s\n", r.Elapsed.Round(time.Second))
+	if len(r.Failures) > 0 {
+		fmt.Printf("\nFailures:\n")
+		for _, f := range r.Failures {
+			fmt.Printf("  %s  [%s] %s\n    %s\n", f.SessionID, f.Stage, f.Error, f.Path)
+		}
+	}
+	if r.Interrupted {
+		fmt.Printf("\ninterrupted; run vv backfill again to resume\n")
+	}
+}
+
+// intFlag parses the integer value of flag, or 0 when it is absent.
+func intFlag(args []string, flag string) int {
+	v := flagValue(args, flag)
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		fatal("%s must be a positive integer, got %q", flag, v)
+	}
+	return n
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/suykerbuyk/vibe-vault/internal/changelog"
+	"github.com/suykerbuyk/vibe-vault/internal/help"
+	"github.com/suykerbuyk/vibe-vault/internal/index"
+	"github.com/suykerbuyk/vibe-vault/internal/narrative"
+	"github.com/suykerbuyk/vibe-vault/internal/surface"
+)
+
+// runChangelog handles `vv changelog <from-ref>..<to-ref>`. Commits are
+// resolved in the current working directory's repository; sessions are
+// joined through the index. Output is markdown on stdout.
+func runChangelog(args []string) {
+	if wantsHelp(args) {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdChangelog))
+		return
+	}
+
+	var revRange string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--project" || a == "--version" {
+			i++
+			continue
+		}
+		if strings.HasPrefix(a, "-") {
+			fatal("unknown flag: %s\nusage: vv changelog <from-ref>..<to-ref> [--project <name>] [--version <label>]", a)
+		}
+		revRange = a
+	}
+	if revRange == "" {
+		fatal("usage: vv changelog <from-ref>..<to-ref> [--project <name>] [--version <label>]")
+	}
+	from, to, err := changelog.ParseRange(revRange)
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	cfg := mustLoadConfig()
+	surface.EnforceWarnOnly(cfg.VaultPath)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		fatal("getwd: %v", err)
+	}
+	commits, err := gitRangeLog(cwd, from+".."+to)
+	if err != nil {
+		fatal("resolve %s..%s: %v", from, to, err)
+	}
+
+	idx, err := index.Load(cfg.StateDir())
+	if err != nil {
+		fatal("load index: %v", err)
+	}
+
+	version := flagValue(args, "--version")
+	if version == "" && to != "HEAD" {
+		version = to
+	}
+	archiveDir := filepath.Join(cfg.StateDir(), "archive")
+
+	release := changelog.Build(commits, idx.Entries, changelog.Options{
+		Version: version,
+		Date:    gitCommitDate(cwd, to),
+		Project: flagValue(args, "--project"),
+		Intent: func(e index.SessionEntry) string {
+			narr := sessionNarrative(e, archiveDir)
+			if narr == nil {
+				return ""
+			}
+			return narrative.IntentPrefix(narr.Segments, narr.Commits)
+		},
+	})
+	fmt.Print(changelog.Render(release))
+	fmt.Fprintf(os.Stderr, "vv: %d commits, %d linked to sessions\n", release.Commits, release.Linked)
+}
+
+// gitRangeLog returns the non-merge commits in revRange, newest first.
+func gitRangeLog(dir, revRange string) ([]changelog.Commit, error) {
+	out, err := exec.Command("git", "-C", dir, "log", "--no-merges", "--format=%H%x00%s", revRange).Output()
+	if err != nil {
+		var ee *exec.ExitError
+		if errors.As(err, &ee) && len(ee.Stderr) > 0 {
+			return nil, fmt.Errorf("%s", strings.TrimSpace(string(ee.Stderr)))
+		}
+		return nil, err
+	}
+	var commits []changelog.Commit
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		sha, subject, ok := strings.Cut(line, "\x00")
+		if !ok {
+			continue
+		}
+		commits = append(commits, changelog.Commit{SHA: sha, Subject: subject})
+	}
+	return commits, nil
+}
+
+// gitCommitDate returns the committer date (YYYY-MM-DD) of ref, or "" on
+// failure.
+func gitCommitDate(dir, ref string) string {
+	out, err := exec.Command("git", "-C", dir, "log", "-1", "--format=%cs", ref).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -35,6 +35,15 @@ func runConfig() {
 				fatal("%v", err)
 			}
 			return
+		case "gen-encryption-key":
+			if wantsHelp(args[1:]) {
+				fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdConfigGenEncryptionKey))
+				return
+			}
+			if err := runConfigGenEncryptionKey(args[1:]); err != nil {
+				fatal("%v", err)
+			}
+			return
 		}
 	}
 
@@ -0,0 +1,324 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/suykerbuyk/vibe-vault/internal/archive"
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/daemon"
+	"github.com/suykerbuyk/vibe-vault/internal/help"
+	"github.com/suykerbuyk/vibe-vault/internal/hook"
+	"github.com/suykerbuyk/vibe-vault/internal/index"
+	"github.com/suykerbuyk/vibe-vault/internal/lockfile"
+	"github.com/suykerbuyk/vibe-vault/internal/sessionsource"
+	"github.com/suykerbuyk/vibe-vault/internal/staging"
+	"github.com/suykerbuyk/vibe-vault/internal/surface"
+	"github.com/suykerbuyk/vibe-vault/internal/worktreegc"
+	"github.com/suykerbuyk/vibe-vault/internal/zed"
+)
+
+// runDaemon dispatches `vv daemon [status|run|install|uninstall]`;
+// with no subcommand it runs the daemon in the foreground.
+func runDaemon() {
+	args := os.Args[2:]
+	if len(args) > 0 {
+		sub := map[string]struct {
+			cmd help.Command
+			run func([]string)
+		}{
+			"status":    {help.CmdDaemonStatus, runDaemonStatus},
+			"run":       {help.CmdDaemonRun, runDaemonRun},
+			"install":   {help.CmdDaemonInstall, runDaemonInstall},
+			"uninstall": {help.CmdDaemonUninstall, runDaemonUninstall},
+		}
+		if s, ok := sub[args[0]]; ok {
+			if wantsHelp(args[1:]) {
+				fmt.Fprint(os.Stderr, help.FormatTerminal(s.cmd))
+				return
+			}
+			s.run(args[1:])
+			return
+		}
+	}
+	if wantsHelp(args) || len(args) > 0 {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdDaemon))
+		if len(args) > 0 && !wantsHelp(args) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	cfg := mustLoadConfig()
+	if err := surface.EnforceFailStop(cfg.VaultPath); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer cancel()
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+
+	err := daemon.Run(ctx, daemon.Options{
+		Load: func() (config.Config, error) {
+			cfg, err := config.Load()
+			if err == nil {
+				registerEncryptionKey(cfg)
+			}
+			return cfg, err
+		},
+		Sources:    daemonSources,
+		Jobs:       daemonJobs,
+		Sink:       sessionsource.CaptureSink{},
+		SocketPath: daemonSocket(cfg),
+		Reload:     reload,
+		Logger:     log.New(os.Stderr, "", log.LstdFlags),
+	})
+	if err != nil {
+		fatal("daemon: %v", err)
+	}
+}
+
+// daemonSocket returns the socket the daemon serves status on.
+func daemonSocket(cfg config.Config) string {
+	if cfg.Daemon.Socket != "" {
+		return cfg.Daemon.Socket
+	}
+	return daemon.DefaultSocketPath()
+}
+
+// daemonSources lists every session source the daemon supervises; the
+// daemon starts the ones that report Enabled.
+func daemonSources(cfg config.Config) []sessionsource.SessionSource {
+	dbPath := cfg.Zed.DBPath
+	if dbPath == "" {
+		dbPath = zed.DefaultDBPath()
+	}
+	return []sessionsource.SessionSource{
+		hook.NewSource(),
+		zed.NewSource(zed.SourceConfig{
+			DBPath:   dbPath,
+			Debounce: time.Duration(cfg.Zed.DebounceMinutes) * time.Minute,
+			Cfg:      cfg,
+			Logger:   log.Default(),
+		}),
+	}
+}
+
+// daemonJobs returns the maintenance jobs enabled under [daemon].
+// There is no digest job: Dashboards/weekly-digest.md is a Dataview
+// query over session note frontmatter that Obsidian evaluates on open,
+// so there is nothing to regenerate.
+func daemonJobs(cfg config.Config) []daemon.Job {
+	every := func(minutes int) time.Duration { return time.Duration(minutes) * time.Minute }
+	all := []daemon.Job{
+		{Name: "archive", Interval: every(cfg.Daemon.ArchiveMinutes), Run: archiveJob},
+		{Name: "index", Interval: every(cfg.Daemon.IndexMinutes), Run: indexJob},
+		{Name: "sync-sessions", Interval: every(cfg.Daemon.SyncMinutes), Run: syncSessionsJob},
+	}
+	if len(cfg.Daemon.WorktreeRepos) > 0 {
+		all = append(all, daemon.Job{Name: "worktree-gc", Interval: every(cfg.Daemon.WorktreeGCMinutes), Run: worktreeGCJob})
+	}
+	var jobs []daemon.Job
+	for _, j := range all {
+		if j.Interval > 0 {
+			jobs = append(jobs, j)
+		}
+	}
+	return jobs
+}
+
+// archiveJob is `vv archive` with default options.
+func archiveJob(_ context.Context, cfg config.Config) (string, error) {
+	if err := surface.EnforceFailStop(cfg.VaultPath); err != nil {
+		return "", err
+	}
+	key, err := loadArchiveKey(cfg)
+	if err != nil {
+		return "", err
+	}
+	dict, err := archive.LatestDict(filepath.Join(cfg.StateDir(), "archive"))
+	if err != nil {
+		return "", fmt.Errorf("load dictionary: %w", err)
+	}
+	st, err := archiveTranscripts(cfg, archive.Options{Dict: dict, Key: key})
+	if err != nil {
+		return "", err
+	}
+	return st.String(), nil
+}
+
+// indexJob is `vv index`: rebuild the index from notes and regenerate
+// the history.md context docs. It holds the index lock across the
+// rebuild and save so a concurrent capture or backfill cannot have its
+// entry overwritten by the rebuilt index.
+func indexJob(_ context.Context, cfg config.Config) (string, error) {
+	if err := surface.EnforceFailStop(cfg.VaultPath); err != nil {
+		return "", err
+	}
+	fl, err := lockfile.Acquire(filepath.Join(cfg.StateDir(), "session-index.json") + ".lock")
+	if err != nil {
+		return "", fmt.Errorf("acquire index lock: %w", err)
+	}
+	defer func() { _ = fl.Release() }()
+	idx, count, err := index.Rebuild(cfg.ProjectsDir(), cfg.StateDir())
+	if err != nil {
+		return "", err
+	}
+	if err := idx.Save(); err != nil {
+		return "", fmt.Errorf("save index: %w", err)
+	}
+	if _, err := index.GenerateContext(idx, cfg.VaultPath, index.ContextOptionsFor(cfg)); err != nil {
+		return "", fmt.Errorf("indexed %d sessions; generate context: %w", count, err)
+	}
+	return fmt.Sprintf("indexed %d sessions", count), nil
+}
+
+// syncSessionsJob is `vv vault sync-sessions --all-projects`. It commits
+// locally; publishing stays with `vv vault push`.
+func syncSessionsJob(_ context.Context, cfg config.Config) (string, error) {
+	res, err := staging.SyncSessions(cfg.VaultPath, staging.SyncSessionsOpts{})
+	if err != nil {
+		return "", err
+	}
+	if res == nil {
+		return "no staging projects", nil
+	}
+	commits := 0
+	for _, p := range res.Projects {
+		if p.CommitSHA != "" {
+			commits++
+		}
+	}
+	return fmt.Sprintf("%d of %d project(s) committed locally", commits, len(res.Projects)), nil
+}
+
+// worktreeGCJob is `vv worktree gc` over each of [daemon] worktree_repos.
+// A repository another gc holds the lock on is skipped this round.
+func worktreeGCJob(ctx context.Context, cfg config.Config) (string, error) {
+	var reaped, busy int
+	var errs []error
+	for _, repo := range cfg.Daemon.WorktreeRepos {
+		res, err := worktreegc.RunContext(ctx, repo, worktreegc.Options{})
+		switch {
+		case errors.Is(err, lockfile.ErrLocked):
+			busy++
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", repo, err))
+		default:
+			reaped += res.Reaped
+		}
+	}
+	summary := fmt.Sprintf("reaped %d worktree(s) in %d repo(s)", reaped, len(cfg.Daemon.WorktreeRepos))
+	if busy > 0 {
+		summary += fmt.Sprintf(", %d busy", busy)
+	}
+	return summary, errors.Join(errs...)
+}
+
+// runDaemonStatus handles `vv daemon status [--json]`.
+func runDaemonStatus(args []string) {
+	cfg := mustLoadConfig()
+	st, err := daemon.GetStatus(daemonSocket(cfg))
+	if err != nil {
+		fatal("%v", err)
+	}
+	if hasFlag(args, "--json") {
+		printJSON(st)
+		return
+	}
+
+	fmt.Printf("vv daemon: pid %d, up %s\n", st.PID, time.Since(st.Started).Round(time.Second))
+	if st.Reloads > 0 {
+		fmt.Printf("config reloaded %d time(s), last at %s\n", st.Reloads, st.LastReload.Format("2006-01-02 15:04:05"))
+	}
+	fmt.Println("\nsources:")
+	for _, s := range st.Sources {
+		state := "disabled"
+		switch {
+		case s.Error != "":
+			state = "failed: " + s.Error
+		case s.Running:
+			state = "running"
+		}
+		fmt.Printf("  %-18s %s\n", s.Name, state)
+	}
+	fmt.Println("\njobs:")
+	if len(st.Jobs) == 0 {
+		fmt.Println("  (none enabled)")
+	}
+	for _, j := range st.Jobs {
+		last := "never"
+		if !j.LastRun.IsZero() {
+			last = j.LastRun.Format("15:04:05") + " (" + j.LastDuration + ")"
+		}
+		next := j.NextRun.Format("15:04:05")
+		if j.Running {
+			next = "running now"
+		}
+		fmt.Printf("  %-14s every %-9s last %-22s next %s\n", j.Name, j.Every, last, next)
+		switch {
+		case j.LastError != "":
+			fmt.Printf("  %-14s error: %s\n", "", j.LastError)
+		case j.LastResult != "":
+			fmt.Printf("  %-14s %s\n", "", j.LastResult)
+		}
+	}
+}
+
+// runDaemonRun handles `vv daemon run <job>`.
+func runDaemonRun(args []string) {
+	if len(args) != 1 {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdDaemonRun))
+		os.Exit(1)
+	}
+	cfg := mustLoadConfig()
+	if err := daemon.Trigger(daemonSocket(cfg), args[0]); err != nil {
+		fatal("%v", err)
+	}
+	fmt.Printf("queued %s (see vv daemon status)\n", args[0])
+}
+
+// runDaemonInstall handles `vv daemon install [--print]`.
+func runDaemonInstall(args []string) {
+	exe, err := os.Executable()
+	if err != nil {
+		fatal("locate vv binary: %v", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	if hasFlag(args, "--print") {
+		fmt.Print(daemon.Unit(exe))
+		return
+	}
+	path, err := daemon.Install(exe)
+	if err != nil {
+		fatal("install unit: %v", err)
+	}
+	fmt.Printf("wrote %s\n\nEnable it with:\n  systemctl --user daemon-reload\n  systemctl --user enable --now %s\n", path, daemon.UnitName)
+}
+
+// runDaemonUninstall handles `vv daemon uninstall`.
+func runDaemonUninstall(_ []string) {
+	path, removed, err := daemon.Uninstall()
+	if err != nil {
+		fatal("remove unit: %v", err)
+	}
+	if !removed {
+		fmt.Printf("%s not installed\n", path)
+		return
+	}
+	fmt.Printf("removed %s\nRun: systemctl --user daemon-reload\n", path)
+}
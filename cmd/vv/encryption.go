@@ -0,0 +1,143 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suykerbuyk/vibe-vault/internal/archive"
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/help"
+	"github.com/suykerbuyk/vibe-vault/internal/render"
+	"github.com/suykerbuyk/vibe-vault/internal/session"
+	"github.com/suykerbuyk/vibe-vault/internal/vaultcrypt"
+)
+
+// registerEncryptionKey installs the configured key for readers that
+// decrypt transparently, such as archive.Decompress. An unavailable key
+// is not fatal here; a command that meets a sealed file reports it then.
+func registerEncryptionKey(cfg config.Config) {
+	key, err := vaultcrypt.Load(cfg.Encryption.KeyFile, cfg.Encryption.PassphraseEnv)
+	if err != nil && !errors.Is(err, vaultcrypt.ErrNoKey) {
+		log.Printf("warning: encryption key: %v", err)
+	}
+	vaultcrypt.SetDefault(key)
+}
+
+// archiveKey returns the key new archives are sealed with: nil unless
+// [encryption] archives is set, in which case a missing key is fatal
+// rather than a silent fallback to plaintext.
+func archiveKey(cfg config.Config) *vaultcrypt.Key {
+	key, err := loadArchiveKey(cfg)
+	if err != nil {
+		fatal("%v", err)
+	}
+	return key
+}
+
+// loadArchiveKey is archiveKey for callers that must not exit, such as
+// the daemon's archive job.
+func loadArchiveKey(cfg config.Config) (*vaultcrypt.Key, error) {
+	if !cfg.Encryption.Archives {
+		return nil, nil
+	}
+	key, err := vaultcrypt.Load(cfg.Encryption.KeyFile, cfg.Encryption.PassphraseEnv)
+	if err == nil && key == nil {
+		err = fmt.Errorf("%w: set key_file or passphrase_env under [encryption]", vaultcrypt.ErrNoKey)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[encryption] archives is on: %w", err)
+	}
+	return key, nil
+}
+
+// runDecrypt handles `vv decrypt <file>`: it writes the plaintext of an
+// encrypted session note (its stub or .enc payload), a loose transcript
+// archive (decompressed), or any other sealed file to stdout.
+func runDecrypt() {
+	args := os.Args[2:]
+	if wantsHelp(args) || len(args) != 1 {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdDecrypt))
+		if len(args) != 1 {
+			os.Exit(1)
+		}
+		return
+	}
+	path := args[0]
+	cfg := mustLoadConfig()
+
+	if p, ok := strings.CutSuffix(path, render.EncryptedPayloadExt); ok && strings.HasSuffix(p, ".md") {
+		path = p
+	}
+	if strings.HasSuffix(path, ".md") {
+		note, err := session.ReadNote(path, cfg)
+		if err != nil {
+			fatal("%v", err)
+		}
+		fmt.Print(note)
+		return
+	}
+
+	if strings.HasSuffix(path, ".jsonl.zst") {
+		tmp, cleanup, err := archive.Decompress(path)
+		if err != nil {
+			fatal("%v", err)
+		}
+		defer cleanup()
+		f, err := os.Open(tmp)
+		if err != nil {
+			fatal("%v", err)
+		}
+		defer f.Close()
+		io.Copy(os.Stdout, f)
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fatal("%v", err)
+	}
+	if !vaultcrypt.IsSealed(data) {
+		fatal("%s is not encrypted", path)
+	}
+	key := vaultcrypt.Default()
+	if key == nil {
+		fatal("%s is encrypted: %v (set key_file or passphrase_env under [encryption])", path, vaultcrypt.ErrNoKey)
+	}
+	plain, err := key.Open(data)
+	if err != nil {
+		fatal("%s: %v", path, err)
+	}
+	os.Stdout.Write(plain)
+}
+
+// runConfigGenEncryptionKey implements `vv config gen-encryption-key
+// [<path>]`, writing a random 32-byte key, hex-encoded, with mode 0600.
+func runConfigGenEncryptionKey(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: vv config gen-encryption-key [<path>]")
+	}
+	path := filepath.Join(config.ConfigDir(), "vault.key")
+	if len(args) == 1 {
+		path = args[0]
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	if err := vaultcrypt.GenerateKeyFile(path); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("%s already exists; refusing to replace a key that may still be needed", path)
+		}
+		return err
+	}
+	fmt.Printf("wrote %s\n\nAdd to config.toml:\n\n[encryption]\nkey_file = %q\narchives = true\n\n", path, config.CompressHome(path))
+	fmt.Println("Back this key up somewhere safe: encrypted archives and notes cannot be read without it.")
+	return nil
+}
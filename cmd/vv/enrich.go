@@ -0,0 +1,128 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/suykerbuyk/vibe-vault/internal/enricheval"
+	"github.com/suykerbuyk/vibe-vault/internal/help"
+	"github.com/suykerbuyk/vibe-vault/internal/llm"
+)
+
+// runEnrich dispatches `vv enrich <eval>`.
+func runEnrich(args []string) {
+	if len(args) > 0 && args[0] == "eval" {
+		runEnrichEval(args[1:])
+		return
+	}
+	if wantsHelp(args) {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdEnrich))
+		return
+	}
+	fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdEnrich))
+	os.Exit(1)
+}
+
+// runEnrichEval handles `vv enrich eval <fixtures-dir> [--record]
+// [--baseline <file>] [--out <file>] [--json]`. Replay is the default and
+// never touches the network; --record sends cassette misses to the
+// configured enrichment provider (bypassing the response cache, so the
+// recording holds live replies) and writes them back to each case.
+func runEnrichEval(args []string) {
+	if wantsHelp(args) {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdEnrichEval))
+		return
+	}
+	var dir string
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; {
+		case a == "--baseline" || a == "--out":
+			i++
+		case strings.HasPrefix(a, "-"):
+		default:
+			if dir != "" {
+				fatal("enrich eval takes one fixtures directory")
+			}
+			dir = a
+		}
+	}
+	if dir == "" {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdEnrichEval))
+		os.Exit(1)
+	}
+
+	cfg := mustLoadConfig()
+	opts := enricheval.Options{Mode: llm.CassetteReplay, Config: cfg}
+	if hasFlag(args, "--record") {
+		p, err := llm.NewProvider(cfg.Enrichment, cfg.Providers)
+		if err != nil {
+			fatal("%v", err)
+		}
+		if p == nil {
+			fatal("--record needs an enrichment provider; set [enrichment] enabled = true")
+		}
+		opts.Mode = llm.CassetteRecord
+		opts.Provider = llm.WithLedger(p, llm.OpenLedger(cfg), cfg.Enrichment.Model)
+	}
+
+	ctx := llm.WithUsageLabels(context.Background(), llm.UsageLabels{Purpose: llm.PurposeEval})
+	report, err := enricheval.Run(ctx, dir, opts)
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	if out := flagValue(args, "--out"); out != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fatal("%v", err)
+		}
+		if err := os.WriteFile(out, append(data, '\n'), 0o644); err != nil {
+			fatal("write report: %v", err)
+		}
+	}
+
+	var diff []string
+	baselinePath := flagValue(args, "--baseline")
+	if baselinePath != "" {
+		base, err := enricheval.LoadReport(baselinePath)
+		if err != nil {
+			fatal("%v", err)
+		}
+		diff = enricheval.Diff(base, report)
+	}
+
+	switch {
+	case hasFlag(args, "--json") && baselinePath != "":
+		if diff == nil {
+			diff = []string{}
+		}
+		printJSON(struct {
+			*enricheval.Report
+			Diff []string `json:"diff"`
+		}{report, diff})
+	case hasFlag(args, "--json"):
+		printJSON(report)
+	default:
+		fmt.Print(enricheval.Render(report))
+		if baselinePath != "" {
+			fmt.Printf("\nChanges since %s\n", baselinePath)
+			if len(diff) == 0 {
+				fmt.Println("  (none)")
+			}
+			for _, line := range diff {
+				fmt.Printf("  %s\n", line)
+			}
+		}
+	}
+	// A case that could not run (e.g. a cassette miss in replay mode)
+	// fails the command so CI notices a stale recording.
+	if report.Totals.Errors > 0 {
+		os.Exit(1)
+	}
+}
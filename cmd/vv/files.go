@@ -0,0 +1,138 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/suykerbuyk/vibe-vault/internal/help"
+	"github.com/suykerbuyk/vibe-vault/internal/index"
+	"github.com/suykerbuyk/vibe-vault/internal/sanitize"
+	"github.com/suykerbuyk/vibe-vault/internal/session"
+	"github.com/suykerbuyk/vibe-vault/internal/surface"
+)
+
+// runFiles dispatches `vv files <dossier>`.
+func runFiles(args []string) {
+	if len(args) > 0 && args[0] == "dossier" {
+		runFilesDossier(args[1:])
+		return
+	}
+	if wantsHelp(args) {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdFiles))
+		return
+	}
+	fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdFiles))
+	os.Exit(1)
+}
+
+// runFilesDossier handles `vv files dossier <path> [--write] [--json]`.
+func runFilesDossier(args []string) {
+	if wantsHelp(args) {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdFilesDossier))
+		return
+	}
+	pos := adrPositional(args, "--project")
+	if len(pos) != 1 {
+		fatal("usage: vv files dossier <path> [--project <name>] [--write] [--json]")
+	}
+
+	cfg := mustLoadConfig()
+	surface.EnforceWarnOnly(cfg.VaultPath)
+
+	cwd, _ := os.Getwd()
+	project := flagValue(args, "--project")
+	if project == "" {
+		project = session.DetectProject(cwd)
+	}
+	if project == "" || project == "_unknown" {
+		fatal("cannot determine project; pass --project <name>")
+	}
+
+	idx, err := index.Load(cfg.StateDir())
+	if err != nil {
+		fatal("load index: %v", err)
+	}
+
+	d := idx.FileDossier(project, dossierPath(pos[0], cwd))
+
+	if hasFlag(args, "--write") {
+		rel, err := index.WriteFileDossier(cfg.VaultPath, d, cfg.Flavor(), idx.NoteLinks(project))
+		if err != nil {
+			fatal("%v", err)
+		}
+		fmt.Fprintf(os.Stderr, "wrote %s\n", rel)
+	}
+
+	if hasFlag(args, "--json") {
+		if d.Sessions == nil {
+			d.Sessions = []index.FileSession{}
+		}
+		printJSON(d)
+		return
+	}
+	printDossier(d)
+}
+
+// dossierPath normalizes a command-line path to the spelling recorded in
+// FilesChanged: project-relative when it lies under the working
+// directory, ~-compressed otherwise, with leading "../" segments dropped
+// so suffix matching still applies.
+func dossierPath(p, cwd string) string {
+	if filepath.IsAbs(p) {
+		if rel, err := filepath.Rel(cwd, p); err == nil && cwd != "" && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+		return sanitize.CompressHome(p)
+	}
+	p = path.Clean(filepath.ToSlash(p))
+	for strings.HasPrefix(p, "../") {
+		p = strings.TrimPrefix(p, "../")
+	}
+	return p
+}
+
+func printDossier(d index.FileDossier) {
+	fmt.Printf("%s — %d sessions (%s)\n", d.Path, len(d.Sessions), d.Project)
+	if len(d.Sessions) == 0 {
+		fmt.Println("\nNo recorded session changed this file.")
+		return
+	}
+
+	fmt.Println("\nSessions")
+	for _, s := range d.Sessions {
+		line := fmt.Sprintf("  %s  %s", s.Date, s.Note)
+		if s.Summary != "" {
+			line += "  " + s.Summary
+		} else if s.Title != "" {
+			line += "  " + s.Title
+		}
+		if len(s.Commits) > 0 {
+			line += "  [" + strings.Join(s.Commits, " ") + "]"
+		}
+		fmt.Println(line)
+	}
+
+	if len(d.Decisions) > 0 {
+		fmt.Println("\nDecisions")
+		for _, m := range d.Decisions {
+			fmt.Printf("  - %s (%s)\n", m.Text, m.Note)
+		}
+	}
+	if len(d.OpenThreads) > 0 {
+		fmt.Println("\nOpen threads")
+		for _, m := range d.OpenThreads {
+			fmt.Printf("  - %s (%s)\n", m.Text, m.Note)
+		}
+	}
+
+	fmt.Println("\nChurn")
+	for _, c := range d.Churn {
+		fmt.Printf("  %s  %-12s %d sessions, %d commits\n", c.Month, strings.Repeat("█", min(c.Sessions, 12)), c.Sessions, c.Commits)
+	}
+}
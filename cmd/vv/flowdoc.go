@@ -30,10 +30,10 @@ import (
 const defaultFlowdocOutputTokens = 16384
 
 // defaultAgenticModel is the Anthropic model used by the agentic
-// strategy when --model is not given. Per the Phase-4 plan: haiku is
-// the cheapest tool-use-capable Anthropic model; sonnet/opus are
-// available via --model. (Grok / OpenAI cannot do agentic today —
-// flowdoc-gen-source-ingestion anti-scope.)
+// strategy when --model is not given and the run lands on Anthropic. Per
+// the Phase-4 plan: haiku is the cheapest tool-use-capable Anthropic
+// model; sonnet/opus are available via --model. Other providers default
+// to the configured enrichment.model (see agenticProviderModel).
 const defaultAgenticModel = "claude-haiku-4-5"
 
 // defaultAgenticMaxIterations bounds the agentic tool-use loop. The
@@ -54,16 +54,29 @@ const defaultFlowdocModel = "grok-4-fast"
 // without touching real credentials or the network. The production
 // implementation mirrors the synthesis.go pattern: build via
 // llm.NewProvider, then override Model on the request rather than
-// re-constructing the provider per model.
+// re-constructing the provider per model. Calls are recorded in the usage
+// ledger but deliberately bypass the response cache: a regen after code
+// changes must not replay a stale flows.json.
 var newProviderForFlowdoc = func(cfg config.Config) (llm.Provider, error) {
-	return llm.NewProvider(cfg.Enrichment, cfg.Providers)
+	p, err := llm.NewProvider(cfg.Enrichment, cfg.Providers)
+	if err != nil || p == nil {
+		return p, err
+	}
+	return llm.WithLedger(p, llm.OpenLedger(cfg), cfg.Enrichment.Model), nil
 }
 
-// newAgenticProviderForFlowdoc constructs the agentic (Anthropic
-// multi-turn tool-use) provider. Mirrors newProviderForFlowdoc so tests
-// can swap in a fake AgenticProvider without real credentials.
+// newAgenticProviderForFlowdoc constructs the agentic (multi-turn
+// tool-use) provider for cfg.Enrichment.Provider. Mirrors
+// newProviderForFlowdoc so tests can swap in a fake AgenticProvider
+// without real credentials.
 var newAgenticProviderForFlowdoc = func(model string, cfg config.Config) (llm.AgenticProvider, error) {
-	return llm.NewAgenticProvider(model, cfg.Providers)
+	enrich := cfg.Enrichment
+	enrich.Model = model
+	p, err := llm.NewAgenticProvider(enrich, cfg.Providers)
+	if err != nil {
+		return nil, err
+	}
+	return llm.WithAgenticLedger(p, llm.OpenLedger(cfg), model), nil
 }
 
 // runFlowdoc dispatches `vv flowdoc <verb>`. Returns the process exit
@@ -301,6 +314,7 @@ func runFlowdocGen(args []string) int {
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
+	ctx = llm.WithUsageLabels(ctx, llm.UsageLabels{Purpose: llm.PurposeFlowdoc, Project: project})
 
 	var rawJSON string
 	switch strategy {
@@ -318,15 +332,16 @@ func runFlowdocGen(args []string) int {
 }
 
 // chooseStrategy resolves the effective strategy for a real (non-dry-run)
-// gen, applying the auto policy: agentic if an Anthropic API key is
-// configured (config or env), single-shot otherwise. Explicit
-// --strategy agentic|single-shot is passed through unchanged.
+// gen, applying the auto policy: agentic if the provider the agentic path
+// would use (see agenticProviderName) has an API key configured (config
+// or env), single-shot otherwise. Explicit --strategy
+// agentic|single-shot is passed through unchanged.
 func chooseStrategy(opts flowdocGenOpts, cfg config.Config) (string, error) {
 	switch opts.strategy {
 	case "agentic", "single-shot":
 		return opts.strategy, nil
 	case "", "auto":
-		if _, err := llm.ResolveAPIKey("anthropic", cfg.Providers); err == nil {
+		if _, err := llm.ResolveAPIKey(agenticProviderName(opts.model, cfg), cfg.Providers); err == nil {
 			return "agentic", nil
 		}
 		return "single-shot", nil
@@ -383,12 +398,18 @@ func runFlowdocSingleShotLLM(ctx context.Context, project string, view flowdoc.R
 }
 
 // runFlowdocAgenticLLM is the Phase-4 agentic path: construct an
-// AnthropicAgentic provider + a RepoView tool backend, hand the model
-// the orienting prompt, and let it explore via read_file / grep /
-// list_dir until it emits a terminal flows.json message. Returns the
-// final assistant turn's text content.
+// agentic provider + a RepoView tool backend, hand the model the
+// orienting prompt, and let it explore via read_file / grep / list_dir
+// until it emits a terminal flows.json message. Returns the final
+// assistant turn's text content.
 func runFlowdocAgenticLLM(ctx context.Context, project string, view flowdoc.RepoView, opts flowdocGenOpts, cfg config.Config) (string, error) {
-	model := resolveAgenticModel(opts.model, cfg.Enrichment.Model)
+	configured := cfg.Enrichment.Provider
+	cfg.Enrichment.Provider, cfg.Enrichment.Model = agenticProviderModel(opts.model, cfg)
+	if cfg.Enrichment.Provider != configured {
+		// enrichment.base_url belongs to the configured provider.
+		cfg.Enrichment.BaseURL = ""
+	}
+	model := cfg.Enrichment.Model
 
 	provider, err := newAgenticProviderForFlowdoc(model, cfg)
 	if err != nil {
@@ -432,6 +453,43 @@ func runFlowdocAgenticLLM(ctx context.Context, project string, view flowdoc.Repo
 	return extractAgenticText(resp.Content), nil
 }
 
+// agenticProviderName picks the provider for an agentic run: Anthropic
+// when --model names a claude-* model, otherwise the configured
+// enrichment provider when its key resolves, falling back to Anthropic —
+// the only agentic backend before OpenAI-compatible and Gemini tool use
+// existed, so operators who only configured an Anthropic key keep it.
+func agenticProviderName(flagModel string, cfg config.Config) string {
+	if strings.HasPrefix(strings.ToLower(flagModel), "claude") {
+		return "anthropic"
+	}
+	provider := cfg.Enrichment.Provider
+	if provider == "" {
+		provider = "openai"
+	}
+	if _, err := llm.ResolveAPIKey(provider, cfg.Providers); err == nil {
+		return provider
+	}
+	return "anthropic"
+}
+
+// agenticProviderModel resolves the provider and model for an agentic
+// run. On Anthropic the model follows resolveAgenticModel; elsewhere
+// --model wins, then enrichment.model, then defaultFlowdocModel.
+func agenticProviderModel(flagModel string, cfg config.Config) (provider, model string) {
+	provider = agenticProviderName(flagModel, cfg)
+	if provider == "anthropic" {
+		return provider, resolveAgenticModel(flagModel, cfg.Enrichment.Model)
+	}
+	model = flagModel
+	if model == "" {
+		model = cfg.Enrichment.Model
+	}
+	if model == "" {
+		model = defaultFlowdocModel
+	}
+	return provider, model
+}
+
 // resolveAgenticModel picks the model for an agentic run: explicit
 // --model wins, then the config's enrichment.model if it is an
 // Anthropic ("claude-*") model, finally defaultAgenticModel.
@@ -236,8 +236,10 @@ func TestChooseStrategy(t *testing.T) {
 			t.Errorf("explicit %q: got (%q, %v); want (%q, nil)", s, got, err, s)
 		}
 	}
-	// Auto with no Anthropic key (config or env) → single-shot.
+	// Auto with no agentic-capable key (config or env) → single-shot.
 	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv("XAI_API_KEY", "")
 	got, err := chooseStrategy(flowdocGenOpts{strategy: "auto"}, config.Config{})
 	if err != nil || got != "single-shot" {
 		t.Errorf("auto + no key: got (%q, %v); want (single-shot, nil)", got, err)
@@ -263,6 +265,51 @@ func TestChooseStrategy(t *testing.T) {
 	}
 }
 
+func TestChooseStrategy_ConfiguredProviderKey(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("XAI_API_KEY", "")
+	cfg := config.Config{
+		Enrichment: config.EnrichmentConfig{Provider: "grok"},
+		Providers:  config.ProvidersConfig{Grok: config.ProviderConfig{APIKey: "xai-test"}},
+	}
+	got, err := chooseStrategy(flowdocGenOpts{strategy: "auto"}, cfg)
+	if err != nil || got != "agentic" {
+		t.Errorf("auto + grok key: got (%q, %v); want (agentic, nil)", got, err)
+	}
+	// A claude-* --model asks for Anthropic, which has no key here.
+	got, err = chooseStrategy(flowdocGenOpts{strategy: "auto", model: "claude-sonnet-4-6"}, cfg)
+	if err != nil || got != "single-shot" {
+		t.Errorf("auto + claude model: got (%q, %v); want (single-shot, nil)", got, err)
+	}
+}
+
+func TestAgenticProviderModel(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("GOOGLE_API_KEY", "")
+	t.Setenv("XAI_API_KEY", "")
+	grok := config.Config{
+		Enrichment: config.EnrichmentConfig{Provider: "grok", Model: "grok-4-fast"},
+		Providers:  config.ProvidersConfig{Grok: config.ProviderConfig{APIKey: "xai-test"}},
+	}
+	cases := []struct {
+		name          string
+		flag          string
+		cfg           config.Config
+		provider, mdl string
+	}{
+		{"configured grok", "", grok, "grok", "grok-4-fast"},
+		{"flag overrides grok model", "grok-4", grok, "grok", "grok-4"},
+		{"claude flag forces anthropic", "claude-opus-4-7", grok, "anthropic", "claude-opus-4-7"},
+		{"keyless provider falls back", "", config.Config{Enrichment: config.EnrichmentConfig{Provider: "google", Model: "gemini-2.5-flash"}}, "anthropic", defaultAgenticModel},
+	}
+	for _, c := range cases {
+		p, m := agenticProviderModel(c.flag, c.cfg)
+		if p != c.provider || m != c.mdl {
+			t.Errorf("%s: got (%q, %q); want (%q, %q)", c.name, p, m, c.provider, c.mdl)
+		}
+	}
+}
+
 func TestResolveAgenticModel(t *testing.T) {
 	if got := resolveAgenticModel("claude-opus-4-7", "grok-4-fast"); got != "claude-opus-4-7" {
 		t.Errorf("flag wins: got %q", got)
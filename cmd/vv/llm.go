@@ -0,0 +1,157 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/suykerbuyk/vibe-vault/internal/help"
+	"github.com/suykerbuyk/vibe-vault/internal/llm"
+	"github.com/suykerbuyk/vibe-vault/internal/stats"
+)
+
+// runLlm dispatches `vv llm <cache|usage>`.
+func runLlm(args []string) {
+	if len(args) > 0 && args[0] == "cache" {
+		runLlmCache(args[1:])
+		return
+	}
+	if len(args) > 0 && args[0] == "usage" {
+		runLlmUsage(args[1:])
+		return
+	}
+	if wantsHelp(args) {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdLlm))
+		return
+	}
+	fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdLlm))
+	os.Exit(1)
+}
+
+func runLlmCache(args []string) {
+	if wantsHelp(args) || len(args) == 0 {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdLlmCache))
+		if len(args) == 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	cfg := mustLoadConfig()
+	// Inspect the cache even when it is disabled for new calls.
+	cache := llm.NewCache(llm.CacheDir(cfg), int64(cfg.LLM.CacheMaxMB)<<20)
+
+	switch args[0] {
+	case "stats":
+		st, err := cache.Stats()
+		if err != nil {
+			fatal("%v", err)
+		}
+		if hasFlag(args, "--json") {
+			printJSON(st)
+			return
+		}
+		state := "enabled"
+		if !cfg.LLM.Cache {
+			state = "disabled"
+		}
+		fmt.Printf("LLM cache: %s (%s)\n", st.Dir, state)
+		fmt.Printf("  entries:  %d\n", st.Entries)
+		if st.MaxBytes > 0 {
+			fmt.Printf("  size:     %s of %s\n", humanBytes(st.Bytes), humanBytes(st.MaxBytes))
+		} else {
+			fmt.Printf("  size:     %s (no limit)\n", humanBytes(st.Bytes))
+		}
+		if st.Entries == 0 {
+			return
+		}
+		fmt.Printf("  oldest:   %s\n", st.Oldest.Local().Format("2006-01-02 15:04"))
+		fmt.Printf("  newest:   %s\n", st.Newest.Local().Format("2006-01-02 15:04"))
+		models := make([]string, 0, len(st.ByModel))
+		for m := range st.ByModel {
+			models = append(models, m)
+		}
+		sort.Strings(models)
+		fmt.Println("  by model:")
+		for _, m := range models {
+			fmt.Printf("    %-40s %d\n", m, st.ByModel[m])
+		}
+
+	case "clear":
+		n, err := cache.Clear()
+		if err != nil {
+			fatal("%v", err)
+		}
+		fmt.Printf("Removed %d cached responses from %s\n", n, llm.CacheDir(cfg))
+
+	default:
+		fatal("unknown llm cache command %q (want stats or clear)", args[0])
+	}
+}
+
+// runLlmUsage handles `vv llm usage [--since YYYY-MM-DD] [--json]`.
+func runLlmUsage(args []string) {
+	if wantsHelp(args) {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdLlmUsage))
+		return
+	}
+	cfg := mustLoadConfig()
+
+	var since time.Time
+	if s := flagValue(args, "--since"); s != "" {
+		var err error
+		since, err = time.Parse("2006-01-02", s)
+		if err != nil {
+			fatal("--since must be YYYY-MM-DD format: %v", err)
+		}
+	}
+
+	records, err := llm.ReadUsage(llm.LedgerPath(cfg), since)
+	if err != nil {
+		fatal("%v", err)
+	}
+	sum := llm.SummarizeUsage(records, func(model string, u llm.UsageStats) float64 {
+		return stats.EstimateCost(cfg.Pricing, stats.CostInput{
+			Model:        model,
+			InputTokens:  u.InputTokens,
+			OutputTokens: u.OutputTokens,
+		})
+	})
+	sum.Since = since
+
+	if hasFlag(args, "--json") {
+		printJSON(sum)
+		return
+	}
+
+	header := "LLM usage"
+	if !since.IsZero() {
+		header += " since " + since.Format("2006-01-02")
+	}
+	fmt.Printf("%s: %d calls (%d cached), %d in / %d out tokens", header,
+		sum.Total.Calls, sum.Total.CachedCalls, sum.Total.InputTokens, sum.Total.OutputTokens)
+	if cfg.Pricing.Enabled {
+		fmt.Printf(", $%.4f", sum.Total.CostUSD)
+	}
+	fmt.Println()
+	if sum.Total.Calls == 0 {
+		return
+	}
+	printUsageTotals("By provider", sum.ByProvider, cfg.Pricing.Enabled)
+	printUsageTotals("By purpose", sum.ByPurpose, cfg.Pricing.Enabled)
+}
+
+func printUsageTotals(title string, totals []llm.UsageTotal, priced bool) {
+	fmt.Printf("\n%s\n", title)
+	for _, t := range totals {
+		line := fmt.Sprintf("  %-40s %5d calls %5d cached %10d in %9d out", t.Key, t.Calls, t.CachedCalls, t.InputTokens, t.OutputTokens)
+		if priced {
+			line += fmt.Sprintf("  $%.4f", t.CostUSD)
+		}
+		fmt.Println(line)
+	}
+}
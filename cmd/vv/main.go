@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -23,8 +24,10 @@ import (
 	"github.com/suykerbuyk/vibe-vault/internal/check"
 	"github.com/suykerbuyk/vibe-vault/internal/config"
 	vvcontext "github.com/suykerbuyk/vibe-vault/internal/context"
+	"github.com/suykerbuyk/vibe-vault/internal/daemon"
 	"github.com/suykerbuyk/vibe-vault/internal/discover"
 	"github.com/suykerbuyk/vibe-vault/internal/effectiveness"
+	"github.com/suykerbuyk/vibe-vault/internal/flavor"
 	"github.com/suykerbuyk/vibe-vault/internal/friction"
 	"github.com/suykerbuyk/vibe-vault/internal/help"
 	"github.com/suykerbuyk/vibe-vault/internal/hook"
@@ -36,6 +39,8 @@ import (
 	"github.com/suykerbuyk/vibe-vault/internal/mcp"
 	"github.com/suykerbuyk/vibe-vault/internal/memory"
 	"github.com/suykerbuyk/vibe-vault/internal/meta"
+	"github.com/suykerbuyk/vibe-vault/internal/narrative"
+	"github.com/suykerbuyk/vibe-vault/internal/notediff"
 	"github.com/suykerbuyk/vibe-vault/internal/scaffold"
 	"github.com/suykerbuyk/vibe-vault/internal/session"
 	"github.com/suykerbuyk/vibe-vault/internal/sessionsource"
@@ -43,6 +48,7 @@ import (
 	"github.com/suykerbuyk/vibe-vault/internal/stats"
 	"github.com/suykerbuyk/vibe-vault/internal/surface"
 	"github.com/suykerbuyk/vibe-vault/internal/templates"
+	"github.com/suykerbuyk/vibe-vault/internal/transcript"
 	"github.com/suykerbuyk/vibe-vault/internal/trends"
 	"github.com/suykerbuyk/vibe-vault/internal/vaultsync"
 	"github.com/suykerbuyk/vibe-vault/internal/zed"
@@ -76,6 +82,9 @@ func main() {
 	case "archive":
 		runArchive()
 
+	case "decrypt":
+		runDecrypt()
+
 	case "reprocess":
 		runReprocess()
 
@@ -103,15 +112,36 @@ func main() {
 	case "effectiveness":
 		runEffectiveness()
 
+	case "pr-describe":
+		runPrDescribe(os.Args[2:])
+
+	case "changelog":
+		runChangelog(os.Args[2:])
+
+	case "adr":
+		runAdr(os.Args[2:])
+
+	case "files":
+		runFiles(os.Args[2:])
+
 	case "flowdoc":
 		os.Exit(runFlowdoc(os.Args[2:]))
 
+	case "llm":
+		runLlm(os.Args[2:])
+
+	case "enrich":
+		runEnrich(os.Args[2:])
+
 	case "config":
 		runConfig()
 
 	case "mcp":
 		runMcp()
 
+	case "daemon":
+		runDaemon()
+
 	case "memory":
 		runMemory()
 
@@ -186,10 +216,31 @@ func runInit() {
 	gitInit := hasFlag(args, "--git")
 	args = removeFlag(args, "--git")
 
+	flavorName := flagValue(args, "--flavor")
+	flavorSet := hasFlag(args, "--flavor")
+	if flavorSet {
+		if flavorName == "" {
+			fatal("--flavor requires a value (obsidian, logseq, or markdown)")
+		}
+		var rest []string
+		for i := 0; i < len(args); i++ {
+			if args[i] == "--flavor" {
+				i++
+				continue
+			}
+			rest = append(rest, args[i])
+		}
+		args = rest
+	}
+	vaultFlavor, err := flavor.Parse(flavorName)
+	if err != nil {
+		fatal("%v", err)
+	}
+
 	target := "./vibe-vault"
 	for _, a := range args {
 		if strings.HasPrefix(a, "-") {
-			fatal("unknown flag: %s\nusage: vv init [path] [--git]", a)
+			fatal("unknown flag: %s\nusage: vv init [path] [--git] [--flavor obsidian|logseq|markdown]", a)
 		}
 	}
 	if len(args) > 0 {
@@ -201,7 +252,7 @@ func runInit() {
 		fatal("resolve path: %v", err)
 	}
 
-	initAction, initErr := scaffold.Init(absTarget, scaffold.Options{GitInit: gitInit})
+	initAction, initErr := scaffold.Init(absTarget, scaffold.Options{GitInit: gitInit, Flavor: string(vaultFlavor)})
 	if initErr != nil {
 		fatal("init: %v", initErr)
 	}
@@ -210,7 +261,7 @@ func runInit() {
 	case "adopted":
 		fmt.Printf("Adopted existing vault at %s\n", absTarget)
 	default:
-		fmt.Printf("Created new vault at %s\n", absTarget)
+		fmt.Printf("Created new vault at %s (%s)\n", absTarget, vaultFlavor)
 	}
 
 	cfgPath, cfgAction, err := config.WriteDefault(absTarget)
@@ -227,18 +278,45 @@ func runInit() {
 		fmt.Printf("Config already set to this vault (%s)\n", cfgPath)
 	}
 
-	if initAction == "adopted" {
-		fmt.Println("\nNext steps:")
-		fmt.Println("  1. Run: vv hook install")
-		fmt.Println("  2. Run: vv mcp install")
-	} else {
-		fmt.Println("\nNext steps:")
-		fmt.Printf("  1. Open %s in Obsidian\n", absTarget)
-		fmt.Println("  2. Install community plugins: Dataview, Templater")
-		fmt.Println("  3. Run: vv hook install")
-		fmt.Println("  4. Run: vv mcp install")
+	// A new vault always records its flavor; an adopted vault only changes
+	// flavor when asked to.
+	if initAction != "adopted" || flavorSet {
+		changed, err := config.SetVaultFlavor(cfgPath, string(vaultFlavor))
+		if err != nil {
+			fatal("write config: %v", err)
+		}
+		if changed {
+			fmt.Printf("Config updated: vault_flavor → %s\n", vaultFlavor)
+		}
 	}
 
+	if initAction == "adopted" && flavorSet {
+		actions, err := scaffold.ApplyFlavor(absTarget, vaultFlavor)
+		if err != nil {
+			fatal("apply flavor: %v", err)
+		}
+		for _, a := range actions {
+			fmt.Printf("  %-8s  %s\n", a.Action, a.RelPath)
+		}
+		fmt.Println("Run `vv reprocess` to re-render existing notes, then `vv index`.")
+	}
+
+	fmt.Println("\nNext steps:")
+	step := 1
+	if initAction != "adopted" {
+		switch vaultFlavor {
+		case flavor.Obsidian:
+			fmt.Printf("  %d. Open %s in Obsidian\n", step, absTarget)
+			fmt.Printf("  %d. Install community plugins: Dataview, Templater\n", step+1)
+			step += 2
+		case flavor.Logseq:
+			fmt.Printf("  %d. Add %s as a graph in Logseq\n", step, absTarget)
+			step++
+		}
+	}
+	fmt.Printf("  %d. Run: vv hook install\n", step)
+	fmt.Printf("  %d. Run: vv mcp install\n", step+1)
+
 	fmt.Println("\nTip: enable LLM enrichment for richer session notes — see vv check for status")
 }
 
@@ -440,16 +518,21 @@ func runProcess() {
 	}
 
 	cfg := mustLoadConfig()
-	if len(os.Args) < 3 {
-		fatal("usage: vv process <transcript.jsonl>")
+	args := os.Args[2:]
+	if hasFlag(args, "--no-cache") {
+		cfg.LLM.Cache = false
+		args = removeFlag(args, "--no-cache")
+	}
+	if len(args) < 1 {
+		fatal("usage: vv process <transcript.jsonl> [--no-cache]")
 	}
 
-	provider, err := llm.NewProvider(cfg.Enrichment, cfg.Providers)
+	provider, err := llm.NewConfiguredProvider(cfg)
 	if err != nil {
 		log.Printf("warning: LLM provider init failed: %v", err)
 	}
 
-	path := os.Args[2]
+	path := args[0]
 	// Phase 2 of vault-two-tier-narrative-vs-sessions-split: route the
 	// capture into the host-local staging dir so manual `vv process`
 	// invocations stay consistent with the hook + MCP entry points.
@@ -920,7 +1003,7 @@ func runVault() {
 			if err := idx.Save(); err != nil {
 				fatal("save index: %v", err)
 			}
-			if _, err := index.GenerateContext(idx, cfg.VaultPath, contextOpts(cfg)); err != nil {
+			if _, err := index.GenerateContext(idx, cfg.VaultPath, index.ContextOptionsFor(cfg)); err != nil {
 				log.Printf("warning: generate context: %v", err)
 			}
 			fmt.Printf("vault: regenerated index (%d sessions)\n", count)
@@ -1433,9 +1516,13 @@ func runMcp() {
 			claudeOnly := hasFlag(args[1:], "--claude-only")
 			zedOnly := hasFlag(args[1:], "--zed-only")
 			claudePlugin := hasFlag(args[1:], "--claude-plugin")
+			url := flagValue(args[1:], "--url")
 			if claudePlugin && (claudeOnly || zedOnly) {
 				fatal("--claude-plugin cannot be combined with --claude-only or --zed-only")
 			}
+			if claudePlugin && url != "" {
+				fatal("--claude-plugin cannot be combined with --url")
+			}
 			if claudeOnly && zedOnly {
 				fatal("--claude-only and --zed-only are mutually exclusive")
 			}
@@ -1450,6 +1537,13 @@ func runMcp() {
 				}
 				return
 			}
+			if url != "" {
+				remote := hook.MCPRemote{URL: url, Token: mustLoadConfig().MCP.HTTPToken}
+				if err := hook.InstallMCPRemoteAll(remote, claudeOnly, zedOnly); err != nil {
+					fatal("%v", err)
+				}
+				return
+			}
 			if err := hook.InstallMCPAll(claudeOnly, zedOnly); err != nil {
 				fatal("%v", err)
 			}
@@ -1483,6 +1577,13 @@ func runMcp() {
 				fatal("%v", err)
 			}
 			return
+		case "serve":
+			if wantsHelp(args[1:]) {
+				fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdMcpServe))
+				return
+			}
+			serveMcp(debug, flagValue(args[1:], "--http"))
+			return
 		case "check":
 			if wantsHelp(args[1:]) {
 				fmt.Fprint(os.Stderr, mcpCheckHelp)
@@ -1520,7 +1621,18 @@ func runMcp() {
 		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdMcp))
 		return
 	}
+	serveMcp(debug, "")
+}
+
+// serveMcp runs the MCP server over stdio, or over Streamable HTTP on
+// httpAddr when set.
+func serveMcp(debug bool, httpAddr string) {
 	cfg := mustLoadConfig()
+	if httpAddr != "" {
+		if err := checkMcpHTTPAddr(httpAddr, cfg.MCP.HTTPToken); err != nil {
+			fatal("%v", err)
+		}
+	}
 	logger := log.New(os.Stderr, "", log.LstdFlags)
 	srv := mcp.NewServer(mcp.ServerInfo{Name: "vibe-vault", Version: help.Version}, logger)
 	registerMCPTools(srv, cfg)
@@ -1531,7 +1643,11 @@ func runMcp() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
 	defer cancel()
 
-	if cfg.Zed.AutoCapture {
+	// A running vv daemon already supervises the Zed watcher; a second
+	// one here would capture every thread twice.
+	if cfg.Zed.AutoCapture && daemon.Running(daemonSocket(cfg)) {
+		logger.Printf("zed auto-capture: left to the running vv daemon")
+	} else if cfg.Zed.AutoCapture {
 		dbPath := cfg.Zed.DBPath
 		if dbPath == "" {
 			dbPath = zed.DefaultDBPath()
@@ -1563,12 +1679,52 @@ func runMcp() {
 	}
 	fmt.Fprintf(os.Stderr, "[MCP] surface=%d\n", surface.MCPSurfaceVersion)
 
+	if httpAddr != "" {
+		if err := serveMcpHTTP(ctx, srv, httpAddr, cfg.MCP.HTTPToken); err != nil {
+			logger.Printf("mcp server error: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
 	if err := srv.Serve(ctx, os.Stdin, os.Stdout); err != nil {
 		logger.Printf("mcp server error: %v", err)
 		os.Exit(1)
 	}
 }
 
+// checkMcpHTTPAddr refuses to serve without a token anywhere but a
+// loopback address.
+func checkMcpHTTPAddr(addr, token string) error {
+	if token == "" && !loopbackAddr(addr) {
+		return fmt.Errorf("%s is not a loopback address; set [mcp] http_token in config.toml first", addr)
+	}
+	return nil
+}
+
+// serveMcpHTTP serves srv over Streamable HTTP on addr.
+func serveMcpHTTP(ctx context.Context, srv *mcp.Server, addr, token string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "[MCP] listening on http://%s%s\n", ln.Addr(), mcp.HTTPPath)
+	return srv.ServeStreamableHTTP(ctx, ln, mcp.HTTPOptions{Token: token})
+}
+
+// loopbackAddr reports whether a host:port listen address only accepts
+// local connections.
+func loopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 func runIndex() {
 	if wantsHelp(os.Args[2:]) {
 		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdIndex))
@@ -1592,7 +1748,7 @@ func runIndex() {
 
 	fmt.Printf("indexed %d sessions\n", count)
 
-	if _, err := index.GenerateContext(idx, cfg.VaultPath, contextOpts(cfg)); err != nil {
+	if _, err := index.GenerateContext(idx, cfg.VaultPath, index.ContextOptionsFor(cfg)); err != nil {
 		log.Printf("warning: generate context: %v", err)
 	} else {
 		for _, project := range idx.Projects() {
@@ -1609,162 +1765,6 @@ func defaultTranscriptDir() string {
 	return filepath.Join(home, ".claude", "projects")
 }
 
-func runBackfill() {
-	if wantsHelp(os.Args[2:]) {
-		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdBackfill))
-		return
-	}
-
-	cfg := mustLoadConfig()
-	if err := surface.EnforceFailStop(cfg.VaultPath); err != nil {
-		fmt.Fprintln(os.Stderr, err.Error())
-		os.Exit(1)
-	}
-
-	basePath := defaultTranscriptDir()
-	args := os.Args[2:]
-	if len(args) > 0 && !hasFlag(args, "--") {
-		basePath = args[0]
-	}
-
-	if basePath == "" {
-		fatal("cannot determine transcript directory")
-	}
-
-	fmt.Printf("Discovering transcripts in %s\n", basePath)
-
-	transcripts, err := discover.Discover(basePath)
-	if err != nil {
-		fatal("discover: %v", err)
-	}
-
-	fmt.Printf("Found %d transcripts\n", len(transcripts))
-
-	idx, err := index.Load(cfg.StateDir())
-	if err != nil {
-		fatal("load index: %v", err)
-	}
-
-	var processed, skipped, patched, errors int
-	for _, tf := range transcripts {
-		if idx.Has(tf.SessionID) {
-			// Backfill TranscriptPath on existing entries that lack it
-			entry := idx.Entries[tf.SessionID]
-			if entry.TranscriptPath == "" {
-				entry.TranscriptPath = tf.Path
-				idx.Entries[tf.SessionID] = entry
-				patched++
-			}
-			skipped++
-			continue
-		}
-
-		// Phase 2: backfill writes also honor the staging-root routing
-		// rule so backfilled notes land where future hook fires write,
-		// not in a divergent flat-vault layout.
-		result, err := session.Capture(session.CaptureOpts{
-			TranscriptPath: tf.Path,
-			StagingRoot:    staging.ResolveRoot(cfg.Staging.Root),
-		}, cfg)
-		if err != nil {
-			log.Printf("error processing %s: %v", tf.SessionID, err)
-			errors++
-			continue
-		}
-
-		if result.Skipped {
-			skipped++
-			continue
-		}
-
-		processed++
-		fmt.Printf("  %s → %s\n", result.Project, result.NotePath)
-
-		// Reload index since Capture saved it
-		idx, _ = index.Load(cfg.StateDir())
-	}
-
-	// Save index if we patched TranscriptPaths
-	if patched > 0 {
-		if err := idx.Save(); err != nil {
-			log.Printf("warning: could not save index: %v", err)
-		}
-	}
-
-	fmt.Printf("\nprocessed: %d, skipped: %d (already indexed or trivial), errors: %d\n",
-		processed, skipped, errors)
-	if patched > 0 {
-		fmt.Printf("patched: %d (added transcript paths to existing entries)\n", patched)
-	}
-}
-
-func runArchive() {
-	if wantsHelp(os.Args[2:]) {
-		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdArchive))
-		return
-	}
-
-	cfg := mustLoadConfig()
-	if err := surface.EnforceFailStop(cfg.VaultPath); err != nil {
-		fmt.Fprintln(os.Stderr, err.Error())
-		os.Exit(1)
-	}
-	archiveDir := filepath.Join(cfg.StateDir(), "archive")
-
-	idx, err := index.Load(cfg.StateDir())
-	if err != nil {
-		fatal("load index: %v", err)
-	}
-
-	var archived, skipped int
-	var totalSrc, totalArch int64
-
-	for _, entry := range idx.Entries {
-		transcriptPath := entry.TranscriptPath
-
-		// Fallback: try to discover if no TranscriptPath stored
-		if transcriptPath == "" {
-			defaultDir := defaultTranscriptDir()
-			if defaultDir != "" {
-				found, err := discover.FindBySessionID(defaultDir, entry.SessionID)
-				if err == nil {
-					transcriptPath = found
-				}
-			}
-		}
-
-		if transcriptPath == "" {
-			skipped++
-			continue
-		}
-
-		if archive.IsArchived(entry.SessionID, archiveDir) {
-			skipped++
-			continue
-		}
-
-		srcInfo, err := os.Stat(transcriptPath)
-		if err != nil {
-			skipped++
-			continue
-		}
-
-		archPath, err := archive.Archive(transcriptPath, archiveDir)
-		if err != nil {
-			log.Printf("error archiving %s: %v", entry.SessionID, err)
-			continue
-		}
-
-		archInfo, _ := os.Stat(archPath)
-		totalSrc += srcInfo.Size()
-		totalArch += archInfo.Size()
-		archived++
-	}
-
-	fmt.Printf("archived: %d (%s → %s), skipped: %d\n",
-		archived, humanBytes(totalSrc), humanBytes(totalArch), skipped)
-}
-
 func runReprocess() {
 	if wantsHelp(os.Args[2:]) {
 		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdReprocess))
@@ -1804,11 +1804,29 @@ func runReprocess() {
 	sourceFilter := flagValue(os.Args[2:], "--source")
 	dryRun := hasFlag(os.Args[2:], "--dry-run")
 
-	// Create LLM provider for enrichment.
+	// --diff renders each note in memory and diffs it against disk;
+	// --accept additionally writes the named sections back.
+	var preview *reprocessPreview
+	if hasFlag(os.Args[2:], "--diff") || hasFlag(os.Args[2:], "--accept") {
+		preview = newReprocessPreview(os.Args[2:], cfg)
+		dryRun = false
+	}
+
+	// Create LLM provider for enrichment. A preview re-renders with the
+	// enrichment already in the LLM cache and makes no calls unless
+	// --enrich asks it to.
 	var provider llm.Provider
+	cacheOnly := preview != nil && !hasFlag(os.Args[2:], "--enrich")
 	if !dryRun {
 		var providerErr error
-		provider, providerErr = llm.NewProvider(cfg.Enrichment, cfg.Providers)
+		if hasFlag(os.Args[2:], "--no-cache") {
+			cfg.LLM.Cache = false
+		}
+		if cacheOnly {
+			provider, providerErr = llm.NewCacheOnlyProvider(cfg)
+		} else {
+			provider, providerErr = llm.NewConfiguredProvider(cfg)
+		}
 		if providerErr != nil {
 			log.Printf("warning: LLM provider init failed: %v", providerErr)
 		}
@@ -1817,6 +1835,8 @@ func runReprocess() {
 	// Report mode.
 	if dryRun {
 		fmt.Println("Dry run — no files will be written")
+	} else if preview != nil && preview.accept == nil {
+		fmt.Println("Diff preview — no files will be written")
 	} else if providerName, model, reason := llm.Available(cfg.Enrichment); reason == "" {
 		fmt.Printf("Reprocessing with LLM enrichment (%s/%s)\n", providerName, model)
 	} else {
@@ -1831,6 +1851,36 @@ func runReprocess() {
 	var processed, skipped, errors int
 	affectedProjects := make(map[string]bool)
 
+	// previewEntry feeds a dry-run render to the preview and counts the
+	// outcome.
+	previewEntry := func(entry index.SessionEntry, result *session.CaptureResult, err error) {
+		if err != nil {
+			log.Printf("error rendering %s: %v", entry.SessionID, err)
+			errors++
+			return
+		}
+		if result.Skipped {
+			skipped++
+			return
+		}
+		if cacheOnly && result.EnrichmentAttempted && !result.EnrichmentApplied {
+			// Diffing a heuristic render against an enriched note would
+			// report every enriched section as changed.
+			preview.uncached++
+			return
+		}
+		applied, err := preview.add(entry, result)
+		if err != nil {
+			log.Printf("error diffing %s: %v", entry.SessionID, err)
+			errors++
+			return
+		}
+		processed++
+		if applied {
+			affectedProjects[entry.Project] = true
+		}
+	}
+
 	for _, entry := range idx.Entries {
 		if projectFilter != "" && entry.Project != projectFilter {
 			continue
@@ -1866,7 +1916,13 @@ func runReprocess() {
 				continue
 			}
 
-			result, err := reprocessZedEntry(entry, cfg)
+			if preview != nil {
+				result, err := reprocessZedEntry(entry, cfg, true)
+				previewEntry(entry, result, err)
+				continue
+			}
+
+			result, err := reprocessZedEntry(entry, cfg, false)
 			if err != nil {
 				log.Printf("error reprocessing zed entry %s: %v", entry.SessionID, err)
 				errors++
@@ -1887,40 +1943,7 @@ func runReprocess() {
 			continue
 		}
 
-		// Locate transcript (try in order)
-		transcriptPath := ""
-		var cleanup func()
-
-		// 1. Original location
-		if entry.TranscriptPath != "" {
-			if _, err := os.Stat(entry.TranscriptPath); err == nil {
-				transcriptPath = entry.TranscriptPath
-			}
-		}
-
-		// 2. Archive
-		if transcriptPath == "" {
-			ap := archive.ArchivePath(entry.SessionID, archiveDir)
-			if _, err := os.Stat(ap); err == nil {
-				tmpPath, tmpCleanup, err := archive.Decompress(ap)
-				if err == nil {
-					transcriptPath = tmpPath
-					cleanup = tmpCleanup
-				}
-			}
-		}
-
-		// 3. Fallback scan
-		if transcriptPath == "" {
-			defaultDir := defaultTranscriptDir()
-			if defaultDir != "" {
-				found, err := discover.FindBySessionID(defaultDir, entry.SessionID)
-				if err == nil {
-					transcriptPath = found
-				}
-			}
-		}
-
+		transcriptPath, cleanup := locateTranscript(entry, archiveDir)
 		if transcriptPath == "" {
 			log.Printf("warning: transcript not found for %s", entry.SessionID)
 			skipped++
@@ -1934,12 +1957,18 @@ func runReprocess() {
 			Force:          true,
 			Provider:       provider,
 			StagingRoot:    staging.ResolveRoot(cfg.Staging.Root),
+			DryRun:         preview != nil,
 		}, cfg)
 
 		if cleanup != nil {
 			cleanup()
 		}
 
+		if preview != nil {
+			previewEntry(entry, result, err)
+			continue
+		}
+
 		if err != nil {
 			log.Printf("error reprocessing %s: %v", entry.SessionID, err)
 			errors++
@@ -1959,7 +1988,7 @@ func runReprocess() {
 	// Regenerate context docs for affected projects
 	if !dryRun && len(affectedProjects) > 0 {
 		idx, _ = index.Load(cfg.StateDir())
-		if _, err := index.GenerateContext(idx, cfg.VaultPath, contextOpts(cfg)); err != nil {
+		if _, err := index.GenerateContext(idx, cfg.VaultPath, index.ContextOptionsFor(cfg)); err != nil {
 			log.Printf("warning: generate context: %v", err)
 		} else {
 			for project := range affectedProjects {
@@ -1968,6 +1997,20 @@ func runReprocess() {
 		}
 	}
 
+	if preview != nil {
+		preview.printSummary()
+		fmt.Printf("\nchanged: %d, unchanged: %d, applied: %d, skipped: %d, errors: %d\n",
+			preview.changed, preview.unchanged, preview.applied, skipped, errors)
+		if preview.uncached > 0 {
+			fmt.Printf("not previewed: %d (enrichment not in the LLM cache; --enrich calls the LLM for them)\n",
+				preview.uncached)
+		}
+		if preview.accept[notediff.KeyFrontmatter] && preview.applied > 0 {
+			fmt.Println("Run `vv index` to refresh the session index from the updated frontmatter.")
+		}
+		return
+	}
+
 	label := "reprocessed"
 	if dryRun {
 		label = "would reprocess"
@@ -1975,6 +2018,68 @@ func runReprocess() {
 	fmt.Printf("\n%s: %d, skipped: %d, errors: %d\n", label, processed, skipped, errors)
 }
 
+// locateTranscript finds a Claude Code transcript for an index entry,
+// trying in order: the recorded TranscriptPath, the vault archive
+// (decompressed to a temp file), and a discovery scan of the default
+// transcript dir. Returns "" when nothing is found. cleanup is non-nil
+// only for the archive case and must be called once the caller is done.
+func locateTranscript(entry index.SessionEntry, archiveDir string) (string, func()) {
+	// 1. Original location
+	if entry.TranscriptPath != "" {
+		if _, err := os.Stat(entry.TranscriptPath); err == nil {
+			return entry.TranscriptPath, nil
+		}
+	}
+
+	// 2. Archive
+	if archive.IsArchived(entry.SessionID, archiveDir) {
+		tmpPath, tmpCleanup, err := archive.Decompress(archive.ArchivePath(entry.SessionID, archiveDir))
+		if err == nil {
+			return tmpPath, tmpCleanup
+		}
+	}
+
+	// 3. Fallback scan
+	if defaultDir := defaultTranscriptDir(); defaultDir != "" {
+		if found, err := discover.FindBySessionID(defaultDir, entry.SessionID); err == nil {
+			return found, nil
+		}
+	}
+	return "", nil
+}
+
+// sessionNarrative re-extracts the narrative for an indexed session. Zed
+// entries re-query threads.db; Claude Code entries go through
+// locateTranscript. Returns nil when no transcript can be found — callers
+// treat that as "no evidence", not an error.
+func sessionNarrative(entry index.SessionEntry, archiveDir string) *narrative.Narrative {
+	if entry.Source == "zed" || strings.HasPrefix(entry.TranscriptPath, "zed:") {
+		dbPath, threadID, ok := parseZedTranscriptPath(entry.TranscriptPath)
+		if !ok {
+			return nil
+		}
+		thread, err := zed.QueryThread(dbPath, threadID)
+		if err != nil {
+			return nil
+		}
+		return zed.ExtractNarrative(thread)
+	}
+
+	path, cleanup := locateTranscript(entry, archiveDir)
+	if path == "" {
+		return nil
+	}
+	if cleanup != nil {
+		defer cleanup()
+	}
+	t, err := transcript.ParseFile(path)
+	if err != nil {
+		log.Printf("warning: parse transcript for %s: %v", entry.SessionID, err)
+		return nil
+	}
+	return narrative.Extract(t, t.Stats.CWD)
+}
+
 // dryRunResult holds the detection result for a dry-run reprocess.
 type dryRunResult struct {
 	newProject string
@@ -2006,7 +2111,9 @@ func parseZedTranscriptPath(tp string) (dbPath, threadID string, ok bool) {
 	return tp[:idx], tp[idx+1:], true
 }
 
-func reprocessZedEntry(entry index.SessionEntry, cfg config.Config) (*session.CaptureResult, error) {
+// reprocessZedEntry re-captures a Zed thread; preview renders the note
+// into CaptureResult.Markdown without writing it.
+func reprocessZedEntry(entry index.SessionEntry, cfg config.Config, preview bool) (*session.CaptureResult, error) {
 	dbPath, threadID, ok := parseZedTranscriptPath(entry.TranscriptPath)
 	if !ok {
 		return nil, fmt.Errorf("invalid zed transcript path: %s", entry.TranscriptPath)
@@ -2036,6 +2143,7 @@ func reprocessZedEntry(entry index.SessionEntry, cfg config.Config) (*session.Ca
 		Force:          true,
 		SkipEnrichment: true,
 		StagingRoot:    staging.ResolveRoot(cfg.Staging.Root),
+		DryRun:         preview,
 	}
 
 	return session.CaptureFromParsed(t, info, narr, dialogue, opts, cfg)
@@ -2065,6 +2173,7 @@ func mustLoadConfig() config.Config {
 	if err != nil {
 		fatal("load config: %v", err)
 	}
+	registerEncryptionKey(cfg)
 	return cfg
 }
 
@@ -2120,16 +2229,6 @@ func removeFlag(args []string, flag string) []string {
 	return out
 }
 
-func contextOpts(cfg config.Config) index.ContextOptions {
-	return index.ContextOptions{
-		AlertThreshold:       cfg.Friction.AlertThreshold,
-		TimelineRecentDays:   cfg.History.TimelineRecentDays,
-		TimelineWindowDays:   cfg.History.TimelineWindowDays,
-		DecisionStaleDays:    cfg.History.DecisionStaleDays,
-		KeyFilesRecencyBoost: cfg.History.KeyFilesRecencyBoost,
-	}
-}
-
 func printAction(a vvcontext.FileAction) {
 	if a.Location != "" {
 		fmt.Printf("  %-8s [%s] %s\n", a.Action, a.Location, a.Path)
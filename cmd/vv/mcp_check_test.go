@@ -84,3 +84,24 @@ func TestMcpCheck_ToolsFlag_HelpMentionsFlag(t *testing.T) {
 		t.Errorf("mcpCheckHelp does not mention --tools flag:\n%s", mcpCheckHelp)
 	}
 }
+
+func TestServeMcpHTTP_RequiresTokenOffLoopback(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"127.0.0.1:8765": true,
+		"localhost:0":    true,
+		"[::1]:8765":     true,
+		"0.0.0.0:8765":   false,
+		":8765":          false,
+		"10.0.0.5:8765":  false,
+	} {
+		if got := loopbackAddr(addr); got != want {
+			t.Errorf("loopbackAddr(%q) = %v, want %v", addr, got, want)
+		}
+	}
+	if err := checkMcpHTTPAddr("0.0.0.0:8765", ""); err == nil || !strings.Contains(err.Error(), "http_token") {
+		t.Errorf("tokenless non-loopback serve: err = %v", err)
+	}
+	if err := checkMcpHTTPAddr("0.0.0.0:8765", "s3cret"); err != nil {
+		t.Errorf("non-loopback serve with token: err = %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/help"
+	"github.com/suykerbuyk/vibe-vault/internal/index"
+	"github.com/suykerbuyk/vibe-vault/internal/llm"
+	"github.com/suykerbuyk/vibe-vault/internal/narrative"
+	"github.com/suykerbuyk/vibe-vault/internal/prdescribe"
+	"github.com/suykerbuyk/vibe-vault/internal/session"
+	"github.com/suykerbuyk/vibe-vault/internal/surface"
+)
+
+// runPrDescribe handles `vv pr-describe [--branch X] [--base main]`.
+// Output is markdown on stdout; diagnostics go to stderr so the result can
+// be piped straight into `gh pr create --body-file -`.
+func runPrDescribe(args []string) {
+	if wantsHelp(args) {
+		fmt.Fprint(os.Stderr, help.FormatTerminal(help.CmdPrDescribe))
+		return
+	}
+
+	cfg := mustLoadConfig()
+	surface.EnforceWarnOnly(cfg.VaultPath)
+
+	cwd, _ := os.Getwd()
+
+	branch := flagValue(args, "--branch")
+	if branch == "" {
+		branch = gitCurrentBranch(cwd)
+	}
+	if branch == "" || branch == "HEAD" {
+		fatal("cannot determine branch; pass --branch <name>")
+	}
+	base := flagValue(args, "--base")
+	if base == "" {
+		base = "main"
+	}
+	project := flagValue(args, "--project")
+	if project == "" && cwd != "" {
+		project = session.DetectProject(cwd)
+	}
+
+	idx, err := index.Load(cfg.StateDir())
+	if err != nil {
+		fatal("load index: %v", err)
+	}
+
+	opts := prdescribe.Options{
+		Project: project,
+		Branch:  branch,
+		Base:    base,
+		Commits: gitRangeCommits(cwd, base+".."+branch),
+	}
+	sessions := prdescribe.Collect(idx.Entries, opts)
+	if len(sessions) == 0 {
+		fmt.Fprintf(os.Stderr, "vv: no sessions found for branch %q in project %q\n", branch, project)
+	}
+
+	archiveDir := filepath.Join(cfg.StateDir(), "archive")
+	tests := make(map[string][]narrative.Activity)
+	for _, s := range sessions {
+		narr := sessionNarrative(s, archiveDir)
+		if narr == nil {
+			continue
+		}
+		for _, seg := range narr.Segments {
+			tests[s.SessionID] = append(tests[s.SessionID], seg.Activities...)
+		}
+	}
+
+	out := prdescribe.Render(prdescribe.Build(sessions, tests, opts))
+
+	if hasFlag(args, "--llm") && len(sessions) > 0 {
+		if hasFlag(args, "--no-cache") {
+			cfg.LLM.Cache = false
+		}
+		out = polishPrDescription(cfg, out)
+	}
+	fmt.Print(out)
+}
+
+// polishPrDescription runs the optional LLM pass. Any failure falls back to
+// the heuristic draft with a warning — the command never fails on LLM
+// problems.
+func polishPrDescription(cfg config.Config, draft string) string {
+	provider, err := llm.NewConfiguredProvider(cfg)
+	if err != nil {
+		log.Printf("warning: LLM provider init failed: %v", err)
+		return draft
+	}
+	if provider == nil {
+		log.Printf("warning: --llm requested but enrichment is not configured")
+		return draft
+	}
+
+	timeout := time.Duration(cfg.Synthesis.TimeoutSeconds) * time.Second
+	if timeout == 0 {
+		timeout = 60 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	ctx = llm.WithUsageLabels(ctx, llm.UsageLabels{Purpose: llm.PurposePrDescribe})
+
+	polished, err := prdescribe.Polish(ctx, provider, draft)
+	if err != nil {
+		log.Printf("warning: %v", err)
+		return draft
+	}
+	return polished
+}
+
+// gitCurrentBranch returns the checked-out branch in dir, or "" when dir
+// is not a git work tree.
+func gitCurrentBranch(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// gitRangeCommits lists full SHAs in a revision range (e.g. "main..feat/x").
+// Returns nil on any git failure — unknown refs or a non-repo cwd simply
+// mean no range-based matching.
+func gitRangeCommits(dir, revRange string) []string {
+	if dir == "" {
+		return nil
+	}
+	out, err := exec.Command("git", "-C", dir, "log", "--format=%H", revRange).Output()
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(out))
+}
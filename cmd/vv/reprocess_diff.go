@@ -0,0 +1,180 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/index"
+	"github.com/suykerbuyk/vibe-vault/internal/lockfile"
+	"github.com/suykerbuyk/vibe-vault/internal/notediff"
+	"github.com/suykerbuyk/vibe-vault/internal/session"
+	"github.com/suykerbuyk/vibe-vault/internal/staging"
+	"github.com/suykerbuyk/vibe-vault/internal/templates"
+)
+
+// reprocessPreview collects `vv reprocess --diff` results: each session is
+// rendered in memory (session.CaptureOpts.DryRun), diffed against the note
+// on disk, and — for sections named by --accept — merged back into it.
+type reprocessPreview struct {
+	cfg          config.Config
+	sectionsOnly bool            // --only-changed-sections
+	accept       map[string]bool // --accept keys; nil = preview only
+
+	changed, unchanged, applied int
+	uncached                    int // enrichment missing from the cache
+	bySection                   map[string]int
+}
+
+func newReprocessPreview(args []string, cfg config.Config) *reprocessPreview {
+	p := &reprocessPreview{
+		cfg:          cfg,
+		sectionsOnly: hasFlag(args, "--only-changed-sections"),
+		bySection:    make(map[string]int),
+	}
+	for _, v := range flagValues(args, "--accept") {
+		for _, key := range strings.Split(v, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				if p.accept == nil {
+					p.accept = make(map[string]bool)
+				}
+				p.accept[notediff.Slug(key)] = true
+			}
+		}
+	}
+	return p
+}
+
+// add diffs one dry-run render against entry's existing note, prints the
+// diff, and applies accepted sections. It reports whether the note on
+// disk was rewritten.
+func (p *reprocessPreview) add(entry index.SessionEntry, r *session.CaptureResult) (bool, error) {
+	path := entry.NotePath
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(p.cfg.VaultPath, path)
+	}
+	cfg := p.cfg.WithProjectOverlay(entry.Project)
+	old, err := session.ReadNote(path, cfg)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, fmt.Errorf("read note: %w", err)
+	}
+	exists := err == nil
+
+	changes := notediff.Compare(old, r.Markdown)
+	if len(changes) == 0 {
+		p.unchanged++
+		return false, nil
+	}
+	p.changed++
+	for _, c := range changes {
+		p.bySection[c.Key]++
+	}
+
+	name := entry.NotePath
+	if name == "" {
+		name = r.NotePath
+	}
+	if p.sectionsOnly {
+		fmt.Print(notediff.Diff(name, changes))
+	} else {
+		fmt.Print(templates.UnifiedDiff("a/"+name+" (current)", "b/"+name+" (reprocessed)",
+			old, r.Markdown))
+	}
+
+	if p.accept == nil {
+		return false, nil
+	}
+	if !exists {
+		fmt.Printf("  note missing; run vv reprocess without --diff to write %s\n", name)
+		return false, nil
+	}
+	merged := notediff.Merge(old, r.Markdown, p.accept)
+	if merged == old {
+		return false, nil
+	}
+
+	// Staging-routed notes carry absolute paths and live outside the
+	// vault, so they skip the vault stamp and get a staging commit.
+	stamp := p.cfg.VaultPath
+	if filepath.IsAbs(entry.NotePath) {
+		stamp = ""
+	}
+	written, err := session.WriteNote(stamp, path, merged, entry.Domain, cfg)
+	if err != nil {
+		return false, fmt.Errorf("write note: %w", err)
+	}
+	if stamp == "" {
+		msg := fmt.Sprintf("reprocess: %s/%s", entry.Project, filepath.Base(path))
+		if err := staging.CommitPaths(filepath.Dir(path), written, msg); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: staging commit failed for %s: %v\n", path, err)
+		}
+	}
+	p.applied++
+
+	// Refresh the index entry as a full reprocess would, keeping the
+	// note where it is and the transcript path already on record.
+	if r.Entry != nil {
+		updated := *r.Entry
+		updated.NotePath = entry.NotePath
+		if entry.TranscriptPath != "" {
+			updated.TranscriptPath = entry.TranscriptPath
+		}
+		if err := p.reindex(updated); err != nil {
+			return true, fmt.Errorf("update index: %w", err)
+		}
+	}
+	return true, nil
+}
+
+// reindex records entry in the session index under the index lock.
+func (p *reprocessPreview) reindex(entry index.SessionEntry) error {
+	stateDir := p.cfg.StateDir()
+	fl, err := lockfile.Acquire(filepath.Join(stateDir, "session-index.json") + ".lock")
+	if err != nil {
+		return fmt.Errorf("acquire index lock: %w", err)
+	}
+	defer func() { _ = fl.Release() }()
+
+	idx, err := index.Load(stateDir)
+	if err != nil {
+		return err
+	}
+	idx.Add(entry)
+	return idx.Save()
+}
+
+// printSummary reports how many notes change in each section, most
+// frequently changed first.
+func (p *reprocessPreview) printSummary() {
+	if len(p.bySection) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(p.bySection))
+	width := 0
+	for k := range p.bySection {
+		keys = append(keys, k)
+		width = max(width, len(k))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if p.bySection[keys[i]] != p.bySection[keys[j]] {
+			return p.bySection[keys[i]] > p.bySection[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	fmt.Printf("\nnotes changed per section:\n")
+	for _, k := range keys {
+		mark := ""
+		if p.accept[k] {
+			mark = "  (accepted)"
+		}
+		fmt.Printf("  %-*s  %d%s\n", width, k, p.bySection[k], mark)
+	}
+}
@@ -0,0 +1,581 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Package adr manages Architecture Decision Records promoted from session
+// decisions. Records live at Projects/<project>/decisions/NNNN-slug.md with
+// a status (proposed, accepted, superseded), wikilinks back to the source
+// session notes, and supersedes/superseded_by links forming a chain.
+//
+// Session decisions decay out of history.md after DecisionStaleDays; an ADR
+// does not. Candidates picks the decisions worth promoting and Promote
+// dedupes against existing records with the same significant-word overlap
+// rule synthesis uses for knowledge.md.
+package adr
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/suykerbuyk/vibe-vault/internal/flavor"
+	"github.com/suykerbuyk/vibe-vault/internal/frontmatter"
+	"github.com/suykerbuyk/vibe-vault/internal/lockfile"
+	"github.com/suykerbuyk/vibe-vault/internal/mdutil"
+)
+
+// Status is an ADR lifecycle state.
+type Status string
+
+const (
+	Proposed   Status = "proposed"
+	Accepted   Status = "accepted"
+	Superseded Status = "superseded"
+)
+
+// ParseStatus validates s. The empty string selects Accepted.
+func ParseStatus(s string) (Status, error) {
+	switch Status(strings.ToLower(strings.TrimSpace(s))) {
+	case "", Accepted:
+		return Accepted, nil
+	case Proposed:
+		return Proposed, nil
+	case Superseded:
+		return Superseded, nil
+	}
+	return "", fmt.Errorf("unknown ADR status %q (want proposed, accepted, or superseded)", s)
+}
+
+// minOverlap is the shared significant-word count at which two decisions
+// are considered the same. Matches synthesis's knowledge.md dedup.
+const minOverlap = 2
+
+// Record is one ADR.
+type Record struct {
+	Number       int      `json:"number"`
+	Slug         string   `json:"slug"`
+	Title        string   `json:"title"`
+	Status       Status   `json:"status"`
+	Date         string   `json:"date"`
+	Project      string   `json:"project"`
+	Sessions     []string `json:"sessions,omitempty"` // source session note names
+	Supersedes   int      `json:"supersedes,omitempty"`
+	SupersededBy int      `json:"superseded_by,omitempty"`
+	Decision     string   `json:"decision"`
+	Rationale    string   `json:"rationale,omitempty"`
+	Path         string   `json:"path"` // vault-relative
+}
+
+// ID returns the display identifier, e.g. "ADR-0003".
+func (r Record) ID() string { return fmt.Sprintf("ADR-%04d", r.Number) }
+
+// Name returns the note name (file name without .md), used as wikilink target.
+func (r Record) Name() string { return fmt.Sprintf("%04d-%s", r.Number, r.Slug) }
+
+// Dir returns the absolute decisions directory for a project.
+func Dir(vaultPath, project string) string {
+	return filepath.Join(vaultPath, "Projects", project, "decisions")
+}
+
+var fileRe = regexp.MustCompile(`^(\d{4})-(.+)\.md$`)
+
+// List returns the project's ADRs sorted by number. A missing directory
+// yields an empty slice.
+func List(vaultPath, project string) ([]Record, error) {
+	entries, err := os.ReadDir(Dir(vaultPath, project))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var records []Record
+	for _, e := range entries {
+		m := fileRe.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		r, err := load(vaultPath, project, e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Number < records[j].Number })
+	return records, nil
+}
+
+// Get returns ADR number n.
+func Get(vaultPath, project string, n int) (Record, error) {
+	records, err := List(vaultPath, project)
+	if err != nil {
+		return Record{}, err
+	}
+	for _, r := range records {
+		if r.Number == n {
+			return r, nil
+		}
+	}
+	return Record{}, fmt.Errorf("ADR-%04d not found in project %q", n, project)
+}
+
+func load(vaultPath, project, name string) (Record, error) {
+	m := fileRe.FindStringSubmatch(name)
+	data, err := os.ReadFile(filepath.Join(Dir(vaultPath, project), name))
+	if err != nil {
+		return Record{}, err
+	}
+	res, err := frontmatter.Parse(strings.NewReader(string(data)), frontmatter.Options{})
+	if err != nil {
+		return Record{}, err
+	}
+	fields, body := res.Fields, res.Body
+	if len(fields) == 0 {
+		// Logseq vaults store ADR metadata as page properties.
+		fields, body, _ = flavor.ParseProperties(res.Body)
+	}
+
+	num, _ := strconv.Atoi(m[1])
+	r := Record{
+		Number:   num,
+		Slug:     m[2],
+		Title:    fields["title"],
+		Status:   Status(fields["status"]),
+		Date:     fields["date"],
+		Project:  project,
+		Sessions: parseList(fields["sessions"]),
+		Path:     path.Join("Projects", project, "decisions", name),
+	}
+	r.Supersedes, _ = strconv.Atoi(fields["supersedes"])
+	r.SupersededBy, _ = strconv.Atoi(fields["superseded_by"])
+	r.Decision = section(body, "Decision")
+	r.Rationale = section(body, "Rationale")
+	if r.Title == "" {
+		r.Title = r.Decision
+	}
+	if r.Status == "" {
+		r.Status = Accepted
+	}
+	return r, nil
+}
+
+// parseList reads a frontmatter list in any of the forms vv writes:
+// ["[[a]]", "[[b]]"], ["a", "b"], or the bare "a, b" of Logseq.
+func parseList(v string) []string {
+	v = strings.TrimSpace(v)
+	if strings.HasPrefix(v, "[") && !strings.HasPrefix(v, "[[") {
+		v = strings.TrimSuffix(v[1:], "]")
+	}
+	var out []string
+	for _, p := range strings.Split(v, ",") {
+		p = frontmatter.StripQuotes(strings.TrimSpace(p))
+		p = strings.TrimSuffix(strings.TrimPrefix(p, "[["), "]]")
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// section returns the trimmed body of the "## name" section.
+func section(lines []string, name string) string {
+	var b []string
+	in := false
+	for _, line := range lines {
+		if strings.HasPrefix(line, "## ") {
+			if in {
+				break
+			}
+			in = strings.TrimSpace(line[3:]) == name
+			continue
+		}
+		if in {
+			b = append(b, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(b, "\n"))
+}
+
+// SplitDecision separates enrichment's "Decision — rationale" form. The
+// [permanent]/[core] markers are dropped from the decision text.
+func SplitDecision(text string) (decision, rationale string) {
+	decision = text
+	if d, r, ok := strings.Cut(text, " — "); ok {
+		decision, rationale = d, strings.TrimSpace(r)
+	}
+	for _, marker := range []string{"[permanent]", "[core]", "[Permanent]", "[Core]"} {
+		decision = strings.ReplaceAll(decision, marker, "")
+	}
+	return strings.TrimSpace(decision), rationale
+}
+
+// Slugify turns a decision into a short file-name slug.
+func Slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimRight(b.String(), "-")
+	if len(slug) > 50 {
+		slug = slug[:50]
+		if i := strings.LastIndexByte(slug, '-'); i > 20 {
+			slug = slug[:i]
+		}
+		slug = strings.TrimRight(slug, "-")
+	}
+	if slug == "" {
+		slug = "decision"
+	}
+	return slug
+}
+
+// FindDuplicate returns the first live (non-superseded) record whose title
+// or decision shares at least two significant words with text.
+func FindDuplicate(records []Record, text string) *Record {
+	words := mdutil.SignificantWords(text)
+	if len(words) == 0 {
+		return nil
+	}
+	for i := range records {
+		if records[i].Status == Superseded {
+			continue
+		}
+		if mdutil.Overlap(words, mdutil.SignificantWords(records[i].Title+" "+records[i].Decision)) >= minOverlap {
+			return &records[i]
+		}
+	}
+	return nil
+}
+
+// Options controls how a record is written.
+type Options struct {
+	Status   Status            // default Accepted
+	Sessions []string          // source session note names
+	Date     string            // YYYY-MM-DD; default today
+	Flavor   flavor.Flavor     // vault dialect ("" = obsidian)
+	Links    map[string]string // note name → vault-relative path, for markdown links
+}
+
+// Promote records text as an ADR. When a live ADR already covers the same
+// decision, its source sessions are extended instead and created is false.
+func Promote(vaultPath, project, text string, opts Options) (rec Record, created bool, err error) {
+	fl, err := lock(vaultPath, project)
+	if err != nil {
+		return Record{}, false, err
+	}
+	defer func() { _ = fl.Release() }()
+	records, err := List(vaultPath, project)
+	if err != nil {
+		return Record{}, false, err
+	}
+	if dup := FindDuplicate(records, text); dup != nil {
+		r := *dup
+		merged := mergeSessions(r.Sessions, opts.Sessions)
+		if len(merged) != len(r.Sessions) {
+			r.Sessions = merged
+			if err := write(vaultPath, r, opts); err != nil {
+				return Record{}, false, err
+			}
+		}
+		return r, false, nil
+	}
+	r := newRecord(project, text, nextNumber(records), opts)
+	if err := write(vaultPath, r, opts); err != nil {
+		return Record{}, false, err
+	}
+	return r, true, nil
+}
+
+// Accept moves a proposed ADR to accepted.
+func Accept(vaultPath, project string, n int, opts Options) (Record, error) {
+	fl, err := lock(vaultPath, project)
+	if err != nil {
+		return Record{}, err
+	}
+	defer func() { _ = fl.Release() }()
+	r, err := Get(vaultPath, project, n)
+	if err != nil {
+		return Record{}, err
+	}
+	if r.Status == Superseded {
+		return Record{}, fmt.Errorf("%s is superseded by ADR-%04d", r.ID(), r.SupersededBy)
+	}
+	if r.Status == Accepted {
+		return r, nil
+	}
+	r.Status = Accepted
+	return r, write(vaultPath, r, opts)
+}
+
+// Supersede marks ADR old as superseded. The replacement is ADR by when
+// by > 0, otherwise a new ADR recording text. Both records are linked.
+func Supersede(vaultPath, project string, old int, text string, by int, opts Options) (replacement, previous Record, err error) {
+	if by == old {
+		return Record{}, Record{}, fmt.Errorf("an ADR cannot supersede itself")
+	}
+	fl, err := lock(vaultPath, project)
+	if err != nil {
+		return Record{}, Record{}, err
+	}
+	defer func() { _ = fl.Release() }()
+	records, err := List(vaultPath, project)
+	if err != nil {
+		return Record{}, Record{}, err
+	}
+	var prev, next *Record
+	for i := range records {
+		switch records[i].Number {
+		case old:
+			prev = &records[i]
+		case by:
+			next = &records[i]
+		}
+	}
+	if prev == nil {
+		return Record{}, Record{}, fmt.Errorf("ADR-%04d not found in project %q", old, project)
+	}
+	if prev.Status == Superseded {
+		return Record{}, Record{}, fmt.Errorf("%s is already superseded by ADR-%04d", prev.ID(), prev.SupersededBy)
+	}
+	switch {
+	case by > 0 && next == nil:
+		return Record{}, Record{}, fmt.Errorf("ADR-%04d not found in project %q", by, project)
+	case by > 0:
+		next.Supersedes = old
+		next.Sessions = mergeSessions(next.Sessions, opts.Sessions)
+	case strings.TrimSpace(text) == "":
+		return Record{}, Record{}, fmt.Errorf("supersede needs a replacement decision or ADR number")
+	default:
+		r := newRecord(project, text, nextNumber(records), opts)
+		r.Supersedes = old
+		next = &r
+	}
+
+	prev.Status = Superseded
+	prev.SupersededBy = next.Number
+	if err := write(vaultPath, *next, opts); err != nil {
+		return Record{}, Record{}, err
+	}
+	if err := write(vaultPath, *prev, opts); err != nil {
+		return Record{}, Record{}, err
+	}
+	return *next, *prev, nil
+}
+
+// lock serializes changes to a project's ADRs, so concurrent promotes
+// cannot take the same number. The lock file lives in the vault's
+// git-ignored state directory.
+func lock(vaultPath, project string) (*lockfile.Lockfile, error) {
+	fl, err := lockfile.Acquire(filepath.Join(vaultPath, ".vibe-vault", "adr-"+project+".lock"))
+	if err != nil {
+		return nil, fmt.Errorf("acquire ADR lock: %w", err)
+	}
+	return fl, nil
+}
+
+func newRecord(project, text string, n int, opts Options) Record {
+	decision, rationale := SplitDecision(text)
+	status := opts.Status
+	if status == "" {
+		status = Accepted
+	}
+	date := opts.Date
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	title := decision
+	if r := []rune(title); len(r) > 80 {
+		title = string(r[:79]) + "…"
+	}
+	r := Record{
+		Number:    n,
+		Slug:      Slugify(decision),
+		Title:     title,
+		Status:    status,
+		Date:      date,
+		Project:   project,
+		Sessions:  mergeSessions(nil, opts.Sessions),
+		Decision:  decision,
+		Rationale: rationale,
+	}
+	r.Path = path.Join("Projects", project, "decisions", r.Name()+".md")
+	return r
+}
+
+func nextNumber(records []Record) int {
+	n := 0
+	for _, r := range records {
+		if r.Number > n {
+			n = r.Number
+		}
+	}
+	return n + 1
+}
+
+func mergeSessions(have, add []string) []string {
+	seen := make(map[string]bool, len(have))
+	out := append([]string(nil), have...)
+	for _, s := range have {
+		seen[s] = true
+	}
+	for _, s := range add {
+		if s != "" && !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// write renders r into the vault. Sections other than Status and Sources
+// are preserved from an existing file, so hand-written context survives
+// status changes.
+func write(vaultPath string, r Record, opts Options) error {
+	dest := filepath.Join(vaultPath, filepath.FromSlash(r.Path))
+
+	doc := Render(r)
+	if existing, err := os.ReadFile(dest); err == nil {
+		doc = mergeBody(doc, string(existing))
+	}
+	if opts.Flavor != "" && opts.Flavor != flavor.Obsidian {
+		doc = flavor.Convert(doc, opts.Flavor, flavor.RelativeResolver(r.Path, opts.Links))
+	}
+	return mdutil.AtomicWriteFile(dest, []byte(doc), 0o644)
+}
+
+// Render produces the canonical (Obsidian) markdown for r.
+func Render(r Record) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	b.WriteString("type: adr\n")
+	fmt.Fprintf(&b, "adr: %d\n", r.Number)
+	fmt.Fprintf(&b, "title: %q\n", r.Title)
+	fmt.Fprintf(&b, "status: %s\n", r.Status)
+	fmt.Fprintf(&b, "date: %s\n", r.Date)
+	fmt.Fprintf(&b, "project: %s\n", r.Project)
+	if len(r.Sessions) > 0 {
+		links := make([]string, len(r.Sessions))
+		for i, s := range r.Sessions {
+			links[i] = fmt.Sprintf("\"[[%s]]\"", s)
+		}
+		fmt.Fprintf(&b, "sessions: [%s]\n", strings.Join(links, ", "))
+	}
+	if r.Supersedes > 0 {
+		fmt.Fprintf(&b, "supersedes: %d\n", r.Supersedes)
+	}
+	if r.SupersededBy > 0 {
+		fmt.Fprintf(&b, "superseded_by: %d\n", r.SupersededBy)
+	}
+	b.WriteString("tags: [adr]\n")
+	b.WriteString("---\n\n")
+
+	fmt.Fprintf(&b, "# %s: %s\n\n", r.ID(), r.Title)
+
+	b.WriteString("## Status\n\n")
+	b.WriteString(statusLine(r))
+	b.WriteString("\n\n")
+
+	b.WriteString("## Decision\n\n")
+	b.WriteString(r.Decision)
+	b.WriteString("\n\n")
+
+	if r.Rationale != "" {
+		b.WriteString("## Rationale\n\n")
+		b.WriteString(r.Rationale)
+		b.WriteString("\n\n")
+	}
+
+	if len(r.Sessions) > 0 {
+		b.WriteString("## Sources\n\n")
+		for _, s := range r.Sessions {
+			fmt.Fprintf(&b, "- [[%s]]\n", s)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+func statusLine(r Record) string {
+	if r.Status == Superseded && r.SupersededBy > 0 {
+		return fmt.Sprintf("Superseded by ADR-%04d.", r.SupersededBy)
+	}
+	line := strings.ToUpper(string(r.Status[:1])) + string(r.Status[1:]) + "."
+	if r.Supersedes > 0 {
+		line += fmt.Sprintf(" Supersedes ADR-%04d.", r.Supersedes)
+	}
+	return line
+}
+
+// mergeBody keeps the freshly rendered frontmatter, title, Status, and
+// Sources, and carries every other section over from the existing file.
+func mergeBody(fresh, existing string) string {
+	keep := map[string]bool{"": true, "Status": true, "Sources": true}
+	old := sections(existing)
+	if len(old) == 0 {
+		return fresh
+	}
+	var out []string
+	seen := make(map[string]bool)
+	for _, s := range sections(fresh) {
+		seen[s.name] = true
+		if !keep[s.name] {
+			if o, ok := findSection(old, s.name); ok {
+				s = o
+			}
+		}
+		out = append(out, s.text)
+	}
+	// Sections the user added (Context, Consequences, …) follow, in order.
+	for _, o := range old {
+		if !seen[o.name] && o.name != "" {
+			out = append(out, o.text)
+		}
+	}
+	return strings.TrimRight(strings.Join(out, ""), "\n") + "\n"
+}
+
+type mdSection struct {
+	name string // "" for the preamble before the first "## "
+	text string
+}
+
+// sections splits a canonical document at "## " headings. The preamble
+// (frontmatter or properties plus the title) is the unnamed first section.
+func sections(doc string) []mdSection {
+	var out []mdSection
+	cur := mdSection{}
+	for _, line := range strings.SplitAfter(doc, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			out = append(out, cur)
+			cur = mdSection{name: strings.TrimSpace(line[3:])}
+		}
+		cur.text += line
+	}
+	out = append(out, cur)
+	if !strings.HasSuffix(out[len(out)-1].text, "\n\n") {
+		out[len(out)-1].text = strings.TrimRight(out[len(out)-1].text, "\n") + "\n\n"
+	}
+	return out
+}
+
+func findSection(ss []mdSection, name string) (mdSection, bool) {
+	for _, s := range ss {
+		if s.name == name {
+			return s, true
+		}
+	}
+	return mdSection{}, false
+}
@@ -0,0 +1,196 @@
+package adr
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/suykerbuyk/vibe-vault/internal/flavor"
+)
+
+func TestPromote_CreatesAndDedupes(t *testing.T) {
+	vault := t.TempDir()
+	opts := Options{Sessions: []string{"2026-02-01-01"}, Date: "2026-02-01"}
+
+	r, created, err := Promote(vault, "proj", "Use JWT tokens for API auth — stateless scaling", opts)
+	if err != nil || !created {
+		t.Fatalf("Promote: created=%v err=%v", created, err)
+	}
+	if r.Number != 1 || r.Slug != "use-jwt-tokens-for-api-auth" || r.Rationale != "stateless scaling" {
+		t.Errorf("record = %+v", r)
+	}
+	data, err := os.ReadFile(filepath.Join(vault, "Projects", "proj", "decisions", "0001-use-jwt-tokens-for-api-auth.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"type: adr\n", "status: accepted\n", `sessions: ["[[2026-02-01-01]]"]`,
+		"# ADR-0001: Use JWT tokens for API auth", "## Rationale\n\nstateless scaling", "- [[2026-02-01-01]]",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("missing %q in\n%s", want, data)
+		}
+	}
+
+	dup, created, err := Promote(vault, "proj", "Keep JWT tokens for auth", Options{Sessions: []string{"2026-02-05-01"}})
+	if err != nil || created {
+		t.Fatalf("duplicate: created=%v err=%v", created, err)
+	}
+	if dup.Number != 1 || len(dup.Sessions) != 2 {
+		t.Errorf("duplicate should extend ADR-0001 sources: %+v", dup)
+	}
+
+	r2, created, _ := Promote(vault, "proj", "Store sessions in SQLite", Options{Status: Proposed})
+	if !created || r2.Number != 2 || r2.Status != Proposed {
+		t.Errorf("second ADR = %+v created=%v", r2, created)
+	}
+}
+
+func TestSupersede_ChainAndPreservesEdits(t *testing.T) {
+	vault := t.TempDir()
+	first, _, _ := Promote(vault, "proj", "Use cookies for sessions", Options{})
+	path := filepath.Join(vault, filepath.FromSlash(first.Path))
+	data, _ := os.ReadFile(path)
+	os.WriteFile(path, []byte(string(data)+"\n## Consequences\n\nCSRF tokens required.\n"), 0o644)
+
+	next, prev, err := Supersede(vault, "proj", 1, "Use bearer tokens instead", 0, Options{})
+	if err != nil {
+		t.Fatalf("Supersede: %v", err)
+	}
+	if next.Number != 2 || next.Supersedes != 1 || prev.Status != Superseded || prev.SupersededBy != 2 {
+		t.Errorf("next=%+v prev=%+v", next, prev)
+	}
+	data, _ = os.ReadFile(path)
+	for _, want := range []string{"status: superseded", "superseded_by: 2", "Superseded by ADR-0002.", "## Consequences\n\nCSRF tokens required."} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("superseded ADR missing %q:\n%s", want, data)
+		}
+	}
+
+	if _, _, err := Supersede(vault, "proj", 1, "again", 0, Options{}); err == nil {
+		t.Error("expected error superseding an already-superseded ADR")
+	}
+	// Superseded records no longer absorb duplicates.
+	if _, created, _ := Promote(vault, "proj", "Use cookies for sessions", Options{}); !created {
+		t.Error("superseded ADR should not dedupe new promotions")
+	}
+}
+
+func TestSupersede_RejectsSelf(t *testing.T) {
+	vault := t.TempDir()
+	Promote(vault, "proj", "Use cookies for sessions", Options{})
+	_, _, err := Supersede(vault, "proj", 1, "", 1, Options{})
+	if err == nil || !strings.Contains(err.Error(), "cannot supersede itself") {
+		t.Errorf("self-supersede err = %v", err)
+	}
+}
+
+func TestPromote_ConcurrentGetDistinctNumbers(t *testing.T) {
+	vault := t.TempDir()
+	decisions := make([]string, 16)
+	for i := range decisions {
+		decisions[i] = fmt.Sprintf("Decision %c%c%c", 'a'+i, 'b'+i, 'c'+i)
+	}
+	var wg sync.WaitGroup
+	for _, d := range decisions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := Promote(vault, "proj", d, Options{}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	records, err := List(vault, "proj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[int]bool)
+	for _, r := range records {
+		seen[r.Number] = true
+	}
+	if len(records) != len(decisions) || len(seen) != len(decisions) {
+		t.Errorf("%d records with %d distinct numbers, want %d of each", len(records), len(seen), len(decisions))
+	}
+}
+
+func TestPromote_WaitsForProjectLock(t *testing.T) {
+	vault := t.TempDir()
+	fl, err := lock(vault, "proj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := Promote(vault, "proj", "Use cookies for sessions", Options{})
+		done <- err
+	}()
+	select {
+	case <-done:
+		t.Fatal("Promote ran while another process held the ADR lock")
+	case <-time.After(100 * time.Millisecond):
+	}
+	fl.Release()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAccept(t *testing.T) {
+	vault := t.TempDir()
+	Promote(vault, "proj", "Adopt trunk based development", Options{Status: Proposed})
+	r, err := Accept(vault, "proj", 1, Options{})
+	if err != nil || r.Status != Accepted {
+		t.Fatalf("Accept: %+v %v", r, err)
+	}
+	got, _ := Get(vault, "proj", 1)
+	if got.Status != Accepted {
+		t.Errorf("status on disk = %q", got.Status)
+	}
+}
+
+func TestLogseqRoundTrip(t *testing.T) {
+	vault := t.TempDir()
+	opts := Options{Flavor: flavor.Logseq, Sessions: []string{"2026-03-01-02"}}
+	r, _, err := Promote(vault, "proj", "Vendor the parser library", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filepath.Join(vault, filepath.FromSlash(r.Path)))
+	if !strings.HasPrefix(string(data), "type:: adr\n") {
+		t.Errorf("expected Logseq properties:\n%s", data)
+	}
+	got, err := Get(vault, "proj", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "Vendor the parser library" || got.Status != Accepted || len(got.Sessions) != 1 || got.Sessions[0] != "2026-03-01-02" {
+		t.Errorf("round trip = %+v", got)
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	sources := []Source{
+		{Note: "s1", Date: "2026-01-01", Decisions: []string{"Use Postgres for the event store", "Rename helper"}},
+		{Note: "s2", Date: "2026-01-03", Summary: "Migrated event store tables to Postgres"},
+		{Note: "s3", Date: "2026-01-04", Decisions: []string{"[core] Never vendor generated code"}},
+		{Note: "s4", Date: "2026-01-05", Decisions: []string{"Use zstd compression for archives"}},
+	}
+	existing := []Record{{Number: 1, Title: "Use zstd compression for archives", Status: Accepted}}
+
+	got := Candidates(sources, existing)
+	if len(got) != 2 {
+		t.Fatalf("candidates = %+v", got)
+	}
+	if got[0].Text != "Use Postgres for the event store" || len(got[0].Sessions) != 2 || got[0].Reason != "referenced in 2 sessions" {
+		t.Errorf("first = %+v", got[0])
+	}
+	if got[1].Reason != "marked permanent" {
+		t.Errorf("second = %+v", got[1])
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package adr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/suykerbuyk/vibe-vault/internal/mdutil"
+)
+
+// Source is one session's decisions, as recorded in the index.
+type Source struct {
+	Note      string // session note name (wikilink target)
+	Date      string
+	Decisions []string
+	Summary   string
+}
+
+// Candidate is a session decision significant enough to become an ADR.
+type Candidate struct {
+	Text     string   `json:"text"`
+	Date     string   `json:"date"`     // first session that recorded it
+	Sessions []string `json:"sessions"` // every session referencing it
+	Reason   string   `json:"reason"`
+}
+
+// Candidates returns the decisions in sources worth promoting: those marked
+// [permanent] or [core], and those referenced again by a later session's
+// decisions or summary (the same reference test history.md decay uses).
+// Decisions already covered by a live ADR are skipped, and near-duplicate
+// candidates are merged. Results are in first-seen order.
+func Candidates(sources []Source, existing []Record) []Candidate {
+	sorted := append([]Source(nil), sources...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+
+	var out []Candidate
+	for i, src := range sorted {
+		for _, d := range src.Decisions {
+			if FindDuplicate(existing, d) != nil || mergeInto(out, d, src.Note) {
+				continue
+			}
+			words := mdutil.SignificantWords(d)
+			c := Candidate{Text: d, Date: src.Date, Sessions: []string{src.Note}}
+			for _, later := range sorted[i+1:] {
+				if referenced(words, later) {
+					c.Sessions = mergeSessions(c.Sessions, []string{later.Note})
+				}
+			}
+			lower := strings.ToLower(d)
+			switch {
+			case strings.Contains(lower, "[permanent]") || strings.Contains(lower, "[core]"):
+				c.Reason = "marked permanent"
+			case len(c.Sessions) > 1:
+				c.Reason = fmt.Sprintf("referenced in %d sessions", len(c.Sessions))
+			default:
+				continue
+			}
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// mergeInto adds note to the first candidate that duplicates text.
+func mergeInto(cands []Candidate, text, note string) bool {
+	words := mdutil.SignificantWords(text)
+	for i := range cands {
+		if mdutil.Overlap(words, mdutil.SignificantWords(cands[i].Text)) >= minOverlap {
+			cands[i].Sessions = mergeSessions(cands[i].Sessions, []string{note})
+			return true
+		}
+	}
+	return false
+}
+
+func referenced(words []string, s Source) bool {
+	if len(words) == 0 {
+		return false
+	}
+	for _, d := range s.Decisions {
+		if mdutil.Overlap(words, mdutil.SignificantWords(d)) >= minOverlap {
+			return true
+		}
+	}
+	return s.Summary != "" && mdutil.Overlap(words, mdutil.SignificantWords(s.Summary)) >= minOverlap
+}
@@ -4,6 +4,8 @@
 package archive
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"os"
@@ -11,17 +13,34 @@ import (
 	"strings"
 
 	"github.com/klauspost/compress/zstd"
+
+	"github.com/suykerbuyk/vibe-vault/internal/vaultcrypt"
 )
 
-// Archive compresses srcPath into archiveDir/{session-id}.jsonl.zst.
-// Returns the archive path.
+// Options controls how Archive compresses a transcript.
+type Options struct {
+	Level int             // zstd level (1-22); 0 = the encoder default (3)
+	Dict  *Dict           // trained dictionary; nil compresses without one
+	Key   *vaultcrypt.Key // seal the compressed frame; nil writes it in the clear
+}
+
+// Archive compresses srcPath into archiveDir/{session-id}.jsonl.zst with
+// default settings. Returns the archive path.
 func Archive(srcPath, archiveDir string) (string, error) {
+	return ArchiveWith(srcPath, archiveDir, Options{})
+}
+
+// ArchiveWith compresses srcPath into archiveDir/{session-id}.jsonl.zst.
+// With opts.Dict set, the dictionary's ID is recorded in the zstd frame
+// header, which is how Decompress finds it again. Returns the archive
+// path.
+func ArchiveWith(srcPath, archiveDir string, opts Options) (string, error) {
 	sessionID := extractSessionID(srcPath)
 	if sessionID == "" {
 		return "", fmt.Errorf("cannot extract session ID from %s", srcPath)
 	}
 
-	destPath := ArchivePath(sessionID, archiveDir)
+	destPath := loosePath(sessionID, archiveDir)
 
 	if err := os.MkdirAll(archiveDir, 0o755); err != nil {
 		return "", fmt.Errorf("create archive dir: %w", err)
@@ -33,53 +52,119 @@ func Archive(srcPath, archiveDir string) (string, error) {
 	}
 	defer src.Close()
 
+	if opts.Key != nil {
+		frame, err := encode(src, opts)
+		if err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(destPath, frame, 0o644); err != nil {
+			return "", fmt.Errorf("write archive: %w", err)
+		}
+		return destPath, nil
+	}
+
 	dest, err := os.Create(destPath)
 	if err != nil {
 		return "", fmt.Errorf("create archive: %w", err)
 	}
 	defer dest.Close()
 
-	encoder, err := zstd.NewWriter(dest)
+	if err := compress(dest, src, opts); err != nil {
+		return "", err
+	}
+
+	return destPath, nil
+}
+
+// encode compresses src into memory and, with opts.Key set, seals it.
+func encode(src io.Reader, opts Options) ([]byte, error) {
+	var frame bytes.Buffer
+	if err := compress(&frame, src, opts); err != nil {
+		return nil, err
+	}
+	if opts.Key == nil {
+		return frame.Bytes(), nil
+	}
+	sealed, err := opts.Key.Seal(frame.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("encrypt archive: %w", err)
+	}
+	return sealed, nil
+}
+
+// unseal returns r's zstd stream, decrypting sealed archives with the
+// key installed by vaultcrypt.SetDefault.
+func unseal(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(len(vaultcrypt.Magic)); !vaultcrypt.IsSealed(magic) {
+		return br, nil
+	}
+	k := vaultcrypt.Default()
+	if k == nil {
+		return nil, fmt.Errorf("archive is encrypted: %w (set key_file or passphrase_env under [encryption])", vaultcrypt.ErrNoKey)
+	}
+	sealed, err := io.ReadAll(br)
+	if err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+	plain, err := k.Open(sealed)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(plain), nil
+}
+
+// compress writes src to w as a single zstd stream.
+func compress(w io.Writer, src io.Reader, opts Options) error {
+	eopts := []zstd.EOption{zstd.WithEncoderLevel(encoderLevel(opts.Level))}
+	if opts.Dict != nil {
+		eopts = append(eopts, zstd.WithEncoderDict(opts.Dict.Data))
+	}
+	encoder, err := zstd.NewWriter(w, eopts...)
 	if err != nil {
-		return "", fmt.Errorf("create zstd encoder: %w", err)
+		return fmt.Errorf("create zstd encoder: %w", err)
 	}
 
 	if _, err := io.Copy(encoder, src); err != nil {
 		encoder.Close()
-		return "", fmt.Errorf("compress: %w", err)
+		return fmt.Errorf("compress: %w", err)
 	}
 
 	if err := encoder.Close(); err != nil {
-		return "", fmt.Errorf("finalize compression: %w", err)
+		return fmt.Errorf("finalize compression: %w", err)
 	}
+	return nil
+}
 
-	return destPath, nil
+// encoderLevel maps a zstd CLI level onto the encoder's speed presets.
+func encoderLevel(level int) zstd.EncoderLevel {
+	if level == 0 {
+		return zstd.SpeedDefault
+	}
+	return zstd.EncoderLevelFromZstd(level)
 }
 
-// Decompress decompresses archivePath to a temp file.
+// Decompress decompresses archivePath — a loose .jsonl.zst file or a
+// pack reference from ArchivePath — to a temp file. Archives written with
+// a dictionary are decoded with the version under the archive's dicts/
+// directory whose ID matches the frame header.
 // Returns the temp file path and a cleanup function the caller must defer.
 func Decompress(archivePath string) (string, func(), error) {
-	src, err := os.Open(archivePath)
+	src, archiveDir, closeSrc, err := openArchive(archivePath)
 	if err != nil {
 		return "", nil, fmt.Errorf("open archive: %w", err)
 	}
-	defer src.Close()
-
-	decoder, err := zstd.NewReader(src)
-	if err != nil {
-		return "", nil, fmt.Errorf("create zstd decoder: %w", err)
-	}
-	defer decoder.Close()
+	defer closeSrc()
 
 	tmp, err := os.CreateTemp("", "vv-decompress-*.jsonl")
 	if err != nil {
 		return "", nil, fmt.Errorf("create temp file: %w", err)
 	}
 
-	if _, err := io.Copy(tmp, decoder); err != nil {
+	if _, err := decodeFrame(tmp, src, archiveDir); err != nil {
 		tmp.Close()
 		os.Remove(tmp.Name())
-		return "", nil, fmt.Errorf("decompress: %w", err)
+		return "", nil, err
 	}
 
 	if err := tmp.Close(); err != nil {
@@ -91,14 +176,61 @@ func Decompress(archivePath string) (string, func(), error) {
 	return tmp.Name(), cleanup, nil
 }
 
-// IsArchived returns true if an archive file exists for the given session ID.
+// openArchive opens a loose archive or a packed frame, returning the
+// compressed stream and the archive dir its dictionaries live in.
+func openArchive(archivePath string) (io.Reader, string, func(), error) {
+	if archiveDir, _, sessionID, ok := parsePackRef(archivePath); ok {
+		e, found := lookupPacked(sessionID, archiveDir)
+		if !found {
+			return nil, "", nil, fmt.Errorf("%s: not in pack index", archivePath)
+		}
+		r, closeFn, err := openPacked(archiveDir, e)
+		return r, archiveDir, closeFn, err
+	}
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return f, filepath.Dir(archivePath), func() { f.Close() }, nil
+}
+
+// IsArchived returns true if a loose archive file or a pack entry exists
+// for the given session ID.
 func IsArchived(sessionID, archiveDir string) bool {
-	_, err := os.Stat(ArchivePath(sessionID, archiveDir))
-	return err == nil
+	if _, err := os.Stat(loosePath(sessionID, archiveDir)); err == nil {
+		return true
+	}
+	_, ok := lookupPacked(sessionID, archiveDir)
+	return ok
 }
 
-// ArchivePath returns the deterministic archive path for a session ID.
+// ArchivePath returns where a session's archive lives: the loose
+// {session-id}.jsonl.zst file, or a pack reference when the session has
+// been packed. Unarchived sessions get the loose path.
 func ArchivePath(sessionID, archiveDir string) string {
+	loose := loosePath(sessionID, archiveDir)
+	if _, err := os.Stat(loose); err == nil {
+		return loose
+	}
+	if e, ok := lookupPacked(sessionID, archiveDir); ok {
+		return PackRef(archiveDir, e)
+	}
+	return loose
+}
+
+// StoredSize returns the compressed bytes a session's archive occupies,
+// or 0 when it is not archived.
+func StoredSize(sessionID, archiveDir string) int64 {
+	if fi, err := os.Stat(loosePath(sessionID, archiveDir)); err == nil {
+		return fi.Size()
+	}
+	if e, ok := lookupPacked(sessionID, archiveDir); ok {
+		return e.Length
+	}
+	return 0
+}
+
+func loosePath(sessionID, archiveDir string) string {
 	return filepath.Join(archiveDir, sessionID+".jsonl.zst")
 }
 
@@ -0,0 +1,206 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package archive
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/klauspost/compress/dict"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/suykerbuyk/vibe-vault/internal/atomicfile"
+)
+
+// DictIDBase offsets dictionary IDs past the range zstd reserves for
+// registered dictionaries; version N of the vault dictionary has ID
+// DictIDBase+N.
+const DictIDBase = 32768
+
+// DefaultDictSize is the dictionary size train-dict aims for, matching
+// the zstd CLI's default.
+const DefaultDictSize = 112640
+
+var dictFileRE = regexp.MustCompile(`^v(\d{4,})\.dict$`)
+
+// Dict is one trained zstd dictionary stored under DictDir.
+type Dict struct {
+	Version int
+	ID      uint32
+	Path    string
+	Data    []byte
+}
+
+// DictDir returns the directory holding versioned dictionaries.
+func DictDir(archiveDir string) string {
+	return filepath.Join(archiveDir, "dicts")
+}
+
+func dictPath(archiveDir string, version int) string {
+	return filepath.Join(DictDir(archiveDir), fmt.Sprintf("v%04d.dict", version))
+}
+
+// dictVersions lists the stored dictionary versions in ascending order.
+func dictVersions(archiveDir string) ([]int, error) {
+	entries, err := os.ReadDir(DictDir(archiveDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var versions []int
+	for _, e := range entries {
+		if m := dictFileRE.FindStringSubmatch(e.Name()); m != nil {
+			v, _ := strconv.Atoi(m[1])
+			versions = append(versions, v)
+		}
+	}
+	sort.Ints(versions)
+	return versions, nil
+}
+
+// LoadDict reads dictionary version from archiveDir.
+func LoadDict(archiveDir string, version int) (*Dict, error) {
+	path := dictPath(archiveDir, version)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read dictionary: %w", err)
+	}
+	id, err := dictID(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &Dict{Version: version, ID: id, Path: path, Data: data}, nil
+}
+
+// LatestDict returns the newest stored dictionary, or nil when none has
+// been trained.
+func LatestDict(archiveDir string) (*Dict, error) {
+	versions, err := dictVersions(archiveDir)
+	if err != nil || len(versions) == 0 {
+		return nil, err
+	}
+	return LoadDict(archiveDir, versions[len(versions)-1])
+}
+
+// dictForID finds the stored dictionary an archive frame was written with.
+func dictForID(archiveDir string, id uint32) (*Dict, error) {
+	if id > DictIDBase {
+		if d, err := LoadDict(archiveDir, int(id-DictIDBase)); err == nil && d.ID == id {
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("archive needs zstd dictionary %d, not found in %s", id, DictDir(archiveDir))
+}
+
+func dictID(data []byte) (uint32, error) {
+	d, err := zstd.InspectDictionary(data)
+	if err != nil {
+		return 0, fmt.Errorf("invalid zstd dictionary: %w", err)
+	}
+	return d.ID(), nil
+}
+
+// TrainOptions tunes TrainDict.
+type TrainOptions struct {
+	MaxSize int // dictionary size in bytes; 0 = DefaultDictSize
+	Level   int // zstd level (1-22) the dictionary is tuned for; 0 = default
+}
+
+// TrainDict builds a dictionary from samples with klauspost's dictionary
+// builder and stores it as the next version under DictDir. Earlier
+// versions are kept so archives written with them still decompress.
+func TrainDict(archiveDir string, samples [][]byte, opts TrainOptions) (*Dict, error) {
+	if len(samples) == 0 {
+		return nil, errors.New("no samples to train on")
+	}
+	versions, err := dictVersions(archiveDir)
+	if err != nil {
+		return nil, err
+	}
+	version := 1
+	if len(versions) > 0 {
+		version = versions[len(versions)-1] + 1
+	}
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultDictSize
+	}
+	data, err := dict.BuildZstdDict(samples, dict.Options{
+		MaxDictSize: opts.MaxSize,
+		HashBytes:   6,
+		ZstdDictID:  uint32(DictIDBase + version),
+		ZstdLevel:   encoderLevel(opts.Level),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("build dictionary: %w", err)
+	}
+	if err := os.MkdirAll(DictDir(archiveDir), 0o755); err != nil {
+		return nil, fmt.Errorf("create dict dir: %w", err)
+	}
+	path := dictPath(archiveDir, version)
+	if err := atomicfile.Write("", path, data); err != nil {
+		return nil, fmt.Errorf("write dictionary: %w", err)
+	}
+	return &Dict{Version: version, ID: uint32(DictIDBase + version), Path: path, Data: data}, nil
+}
+
+// Savings compresses samples at level with and without d and returns the
+// total compressed sizes, so train-dict can report what the dictionary
+// buys before it is used.
+func Savings(samples [][]byte, d *Dict, level int) (plain, withDict int64, err error) {
+	for _, s := range samples {
+		n, err := compressedSize(s, Options{Level: level})
+		if err != nil {
+			return 0, 0, err
+		}
+		plain += n
+		n, err = compressedSize(s, Options{Level: level, Dict: d})
+		if err != nil {
+			return 0, 0, err
+		}
+		withDict += n
+	}
+	return plain, withDict, nil
+}
+
+func compressedSize(data []byte, opts Options) (int64, error) {
+	var buf bytes.Buffer
+	if err := compress(&buf, bytes.NewReader(data), opts); err != nil {
+		return 0, err
+	}
+	return int64(buf.Len()), nil
+}
+
+// FrameDictID returns the dictionary ID recorded in an archive's zstd
+// frame header; 0 means the archive was written without a dictionary.
+// archivePath may be a pack reference.
+func FrameDictID(archivePath string) (uint32, error) {
+	src, _, closeSrc, err := openArchive(archivePath)
+	if err != nil {
+		return 0, err
+	}
+	defer closeSrc()
+	stream, err := unseal(src)
+	if err != nil {
+		return 0, err
+	}
+	buf := make([]byte, zstd.HeaderMaxSize)
+	n, err := io.ReadFull(stream, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return 0, fmt.Errorf("read frame header: %w", err)
+	}
+	var h zstd.Header
+	if err := h.Decode(buf[:n]); err != nil {
+		return 0, fmt.Errorf("decode frame header: %w", err)
+	}
+	return h.DictionaryID, nil
+}
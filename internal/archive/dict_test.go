@@ -0,0 +1,114 @@
+package archive
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// transcriptSample builds a small JSONL transcript with the repetitive
+// structure real ones have.
+func transcriptSample(n int) []byte {
+	var b strings.Builder
+	for i := 0; i < 6; i++ {
+		fmt.Fprintf(&b, `{"type":"user","uuid":"u-%d-%d","sessionId":"s-%d","cwd":"/home/dev/project","gitBranch":"main","version":"2.1.0","message":{"role":"user","content":"Please update handler %d in the service"}}`+"\n", n, i, n, n*7+i)
+		fmt.Fprintf(&b, `{"type":"assistant","uuid":"a-%d-%d","sessionId":"s-%d","cwd":"/home/dev/project","gitBranch":"main","version":"2.1.0","message":{"role":"assistant","model":"claude-opus-4-6","content":[{"type":"tool_use","name":"Edit","input":{"file_path":"/home/dev/project/internal/handler_%d.go"}}],"usage":{"input_tokens":%d,"output_tokens":%d}}}`+"\n", n, i, n, i, 1000+n, 200+i)
+	}
+	return []byte(b.String())
+}
+
+func trainSamples(count int) [][]byte {
+	var samples [][]byte
+	for i := 0; i < count; i++ {
+		samples = append(samples, transcriptSample(i))
+	}
+	return samples
+}
+
+func TestTrainDict_VersionsAndSavings(t *testing.T) {
+	archiveDir := t.TempDir()
+	if d, err := LatestDict(archiveDir); err != nil || d != nil {
+		t.Fatalf("LatestDict on empty dir = %v, %v", d, err)
+	}
+
+	d1, err := TrainDict(archiveDir, trainSamples(40), TrainOptions{MaxSize: 8 << 10})
+	if err != nil {
+		t.Fatalf("TrainDict: %v", err)
+	}
+	if d1.Version != 1 || d1.ID != DictIDBase+1 || filepath.Base(d1.Path) != "v0001.dict" {
+		t.Errorf("first dict = v%d id %d at %s", d1.Version, d1.ID, d1.Path)
+	}
+	d2, err := TrainDict(archiveDir, trainSamples(40), TrainOptions{MaxSize: 8 << 10, Level: 19})
+	if err != nil {
+		t.Fatalf("TrainDict: %v", err)
+	}
+	latest, err := LatestDict(archiveDir)
+	if err != nil || latest.Version != 2 || latest.ID != d2.ID {
+		t.Fatalf("LatestDict = %+v, %v", latest, err)
+	}
+
+	plain, withDict, err := Savings([][]byte{transcriptSample(99)}, latest, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if withDict >= plain {
+		t.Errorf("dictionary did not help: %d bytes with, %d without", withDict, plain)
+	}
+}
+
+func TestArchiveWith_DictRoundTripAcrossVersions(t *testing.T) {
+	srcDir := t.TempDir()
+	archiveDir := t.TempDir()
+	original := transcriptSample(7)
+	srcPath := filepath.Join(srcDir, testSessionID+".jsonl")
+	if err := os.WriteFile(srcPath, original, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	d1, err := TrainDict(archiveDir, trainSamples(40), TrainOptions{MaxSize: 8 << 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	archPath, err := ArchiveWith(srcPath, archiveDir, Options{Level: 19, Dict: d1})
+	if err != nil {
+		t.Fatalf("ArchiveWith: %v", err)
+	}
+	if id, err := FrameDictID(archPath); err != nil || id != d1.ID {
+		t.Fatalf("FrameDictID = %d, %v; want %d", id, err, d1.ID)
+	}
+
+	// A newer dictionary must not stop the older archive decoding.
+	if _, err := TrainDict(archiveDir, trainSamples(20), TrainOptions{MaxSize: 8 << 10}); err != nil {
+		t.Fatal(err)
+	}
+	tmpPath, cleanup, err := Decompress(archPath)
+	if err != nil {
+		t.Fatalf("Decompress: %v", err)
+	}
+	defer cleanup()
+	got, _ := os.ReadFile(tmpPath)
+	if string(got) != string(original) {
+		t.Error("decompressed content mismatch")
+	}
+
+	if err := os.Remove(d1.Path); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Decompress(archPath); err == nil || !strings.Contains(err.Error(), fmt.Sprint(d1.ID)) {
+		t.Errorf("Decompress without its dictionary: err = %v", err)
+	}
+}
+
+func TestFrameDictID_NoDict(t *testing.T) {
+	srcPath := filepath.Join(t.TempDir(), testSessionID+".jsonl")
+	os.WriteFile(srcPath, transcriptSample(1), 0o644)
+	archPath, err := Archive(srcPath, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := FrameDictID(archPath); err != nil || id != 0 {
+		t.Errorf("FrameDictID = %d, %v; want 0", id, err)
+	}
+}
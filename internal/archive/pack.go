@@ -0,0 +1,531 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/suykerbuyk/vibe-vault/internal/lockfile"
+)
+
+// Pack files hold many archived transcripts in one append-only file per
+// month (packs/YYYY-MM.pack), each an independently decompressible zstd
+// frame. A JSON Lines offset index beside each pack (packs/YYYY-MM.idx)
+// locates the frames. Removing sessions rewrites a pack under a new
+// generation name (packs/YYYY-MM.N.pack) named by every index entry, so
+// replacing the index is the single step that switches packs. Packed sessions are addressed by a pack reference,
+// "<archiveDir>/packs/YYYY-MM.pack#<session-id>", which ArchivePath
+// returns and Decompress accepts.
+
+const packRefSep = ".pack#"
+
+var monthRE = regexp.MustCompile(`^\d{4}-\d{2}$`)
+
+// PackEntry is one line of a pack's offset index.
+type PackEntry struct {
+	SessionID string `json:"session_id"`
+	Offset    int64  `json:"offset"`
+	Length    int64  `json:"length"` // compressed frame bytes
+	Size      int64  `json:"size"`   // uncompressed transcript bytes
+	SHA256    string `json:"sha256"` // of the uncompressed transcript
+	DictID    uint32 `json:"dict_id,omitempty"`
+	Pack      string `json:"pack,omitempty"` // file in PackDir; empty for YYYY-MM.pack
+
+	Month string `json:"-"` // pack the entry belongs to
+}
+
+// PackDir returns the directory holding pack files and their indexes.
+func PackDir(archiveDir string) string {
+	return filepath.Join(archiveDir, "packs")
+}
+
+func packFile(archiveDir, month string) string {
+	return filepath.Join(PackDir(archiveDir), month+".pack")
+}
+
+func packIndexFile(archiveDir, month string) string {
+	return filepath.Join(PackDir(archiveDir), month+".idx")
+}
+
+// entryPackFile returns the pack file holding e's frame.
+func entryPackFile(archiveDir string, e PackEntry) string {
+	if e.Pack != "" {
+		return filepath.Join(PackDir(archiveDir), e.Pack)
+	}
+	return packFile(archiveDir, e.Month)
+}
+
+// currentPackFile returns the pack file a month's index points at: the
+// one new frames are appended to.
+func currentPackFile(archiveDir, month string, entries []PackEntry) string {
+	if len(entries) == 0 {
+		return packFile(archiveDir, month)
+	}
+	return entryPackFile(archiveDir, entries[len(entries)-1])
+}
+
+// PackRef returns the pack reference for a packed session.
+func PackRef(archiveDir string, e PackEntry) string {
+	return packFile(archiveDir, e.Month) + "#" + e.SessionID
+}
+
+// parsePackRef splits a pack reference into its archive dir, month, and
+// session ID.
+func parsePackRef(ref string) (archiveDir, month, sessionID string, ok bool) {
+	i := strings.LastIndex(ref, packRefSep)
+	if i < 0 {
+		return "", "", "", false
+	}
+	packPath := ref[:i+len(".pack")]
+	sessionID = ref[i+len(packRefSep):]
+	month = strings.TrimSuffix(filepath.Base(packPath), ".pack")
+	if sessionID == "" || !monthRE.MatchString(month) {
+		return "", "", "", false
+	}
+	return filepath.Dir(filepath.Dir(packPath)), month, sessionID, true
+}
+
+// packIndexCache memoizes the merged offset index per archive dir,
+// keyed by the .idx files' sizes and mtimes, so IsArchived in a loop
+// does not re-read every index.
+var packIndexCache = struct {
+	sync.Mutex
+	m map[string]cachedPackIndex
+}{m: make(map[string]cachedPackIndex)}
+
+type cachedPackIndex struct {
+	sig     string
+	entries map[string]PackEntry
+}
+
+// PackMonths lists the months that have a pack index, oldest first.
+func PackMonths(archiveDir string) ([]string, error) {
+	dirEntries, err := os.ReadDir(PackDir(archiveDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var months []string
+	for _, e := range dirEntries {
+		if m, ok := strings.CutSuffix(e.Name(), ".idx"); ok && monthRE.MatchString(m) {
+			months = append(months, m)
+		}
+	}
+	sort.Strings(months)
+	return months, nil
+}
+
+// ReadPackIndex returns one pack's index entries in file order.
+func ReadPackIndex(archiveDir, month string) ([]PackEntry, error) {
+	f, err := os.Open(packIndexFile(archiveDir, month))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entries []PackEntry
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		var e PackEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s.idx line %d: %w", month, line, err)
+		}
+		e.Month = month
+		entries = append(entries, e)
+	}
+	return entries, sc.Err()
+}
+
+// packIndex returns every packed session in archiveDir. A session packed
+// twice resolves to its last entry.
+func packIndex(archiveDir string) (map[string]PackEntry, error) {
+	months, err := PackMonths(archiveDir)
+	if err != nil || len(months) == 0 {
+		return nil, err
+	}
+	var sig strings.Builder
+	for _, m := range months {
+		if fi, err := os.Stat(packIndexFile(archiveDir, m)); err == nil {
+			fmt.Fprintf(&sig, "%s:%d:%d;", m, fi.Size(), fi.ModTime().UnixNano())
+		}
+	}
+
+	packIndexCache.Lock()
+	defer packIndexCache.Unlock()
+	if c, ok := packIndexCache.m[archiveDir]; ok && c.sig == sig.String() {
+		return c.entries, nil
+	}
+	entries := make(map[string]PackEntry)
+	for _, m := range months {
+		list, err := ReadPackIndex(archiveDir, m)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range list {
+			entries[e.SessionID] = e
+		}
+	}
+	packIndexCache.m[archiveDir] = cachedPackIndex{sig: sig.String(), entries: entries}
+	return entries, nil
+}
+
+// lookupPacked finds sessionID in archiveDir's packs.
+func lookupPacked(sessionID, archiveDir string) (PackEntry, bool) {
+	idx, err := packIndex(archiveDir)
+	if err != nil {
+		return PackEntry{}, false
+	}
+	e, ok := idx[sessionID]
+	return e, ok
+}
+
+// PackWith compresses srcPath and appends it to the month's pack.
+func PackWith(srcPath, archiveDir, month string, opts Options) (*PackEntry, error) {
+	sessionID := extractSessionID(srcPath)
+	if sessionID == "" {
+		return nil, fmt.Errorf("cannot extract session ID from %s", srcPath)
+	}
+	data, err := os.ReadFile(srcPath)
+	if err != nil {
+		return nil, fmt.Errorf("open source: %w", err)
+	}
+	frame, err := encode(bytes.NewReader(data), opts)
+	if err != nil {
+		return nil, err
+	}
+	e := PackEntry{
+		SessionID: sessionID,
+		Size:      int64(len(data)),
+		SHA256:    sha256Hex(data),
+	}
+	if opts.Dict != nil {
+		e.DictID = opts.Dict.ID
+	}
+	return appendFrame(archiveDir, month, e, frame)
+}
+
+// MigrateToPack moves a loose {session-id}.jsonl.zst archive into the
+// month's pack. The compressed bytes are appended as-is (keeping their
+// dictionary), read back and checked, and only then is the loose file
+// removed.
+func MigrateToPack(sessionID, archiveDir, month string) (*PackEntry, error) {
+	loose := loosePath(sessionID, archiveDir)
+	frame, err := os.ReadFile(loose)
+	if err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+	e := PackEntry{SessionID: sessionID}
+	var content bytes.Buffer
+	if e.DictID, err = decodeFrame(&content, bytes.NewReader(frame), archiveDir); err != nil {
+		return nil, fmt.Errorf("%s: %w", loose, err)
+	}
+	e.Size = int64(content.Len())
+	e.SHA256 = sha256Hex(content.Bytes())
+
+	packed, err := appendFrame(archiveDir, month, e, frame)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyEntry(archiveDir, *packed); err != nil {
+		return nil, fmt.Errorf("packed copy of %s failed verification, loose file kept: %w", sessionID, err)
+	}
+	if err := os.Remove(loose); err != nil {
+		return nil, fmt.Errorf("remove loose archive: %w", err)
+	}
+	return packed, nil
+}
+
+// appendFrame appends frame to the month's pack and its index entry to
+// the .idx, under a lock shared by all packs in archiveDir. The pack is
+// synced before the index line is written, so a crash leaves at worst
+// unindexed trailing bytes, which Verify reports.
+func appendFrame(archiveDir, month string, e PackEntry, frame []byte) (*PackEntry, error) {
+	if !monthRE.MatchString(month) {
+		return nil, fmt.Errorf("invalid pack month %q (want YYYY-MM)", month)
+	}
+	if err := os.MkdirAll(PackDir(archiveDir), 0o755); err != nil {
+		return nil, fmt.Errorf("create pack dir: %w", err)
+	}
+	fl, err := lockfile.Acquire(filepath.Join(PackDir(archiveDir), ".lock"))
+	if err != nil {
+		return nil, fmt.Errorf("acquire pack lock: %w", err)
+	}
+	defer func() { _ = fl.Release() }()
+
+	existing, err := ReadPackIndex(archiveDir, month)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	e.Month = month
+	if len(existing) > 0 {
+		e.Pack = existing[len(existing)-1].Pack
+	}
+	pack, err := os.OpenFile(currentPackFile(archiveDir, month, existing), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open pack: %w", err)
+	}
+	defer pack.Close()
+	fi, err := pack.Stat()
+	if err != nil {
+		return nil, err
+	}
+	e.Offset = fi.Size()
+	e.Length = int64(len(frame))
+	if _, err := pack.Write(frame); err != nil {
+		return nil, fmt.Errorf("append to pack: %w", err)
+	}
+	if err := pack.Sync(); err != nil {
+		return nil, fmt.Errorf("sync pack: %w", err)
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := os.OpenFile(packIndexFile(archiveDir, month), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open pack index: %w", err)
+	}
+	defer idx.Close()
+	if _, err := idx.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("append to pack index: %w", err)
+	}
+	if err := idx.Sync(); err != nil {
+		return nil, fmt.Errorf("sync pack index: %w", err)
+	}
+	return &e, nil
+}
+
+// VerifyProblem is one archive that failed verification.
+type VerifyProblem struct {
+	SessionID string `json:"session_id,omitempty"`
+	Location  string `json:"location"`
+	Error     string `json:"error"`
+}
+
+// VerifyReport summarizes Verify.
+type VerifyReport struct {
+	Packs       int             `json:"packs"`
+	Packed      int             `json:"packed"`
+	Loose       int             `json:"loose"`
+	PackedBytes int64           `json:"packed_bytes"`
+	LooseBytes  int64           `json:"loose_bytes"`
+	Problems    []VerifyProblem `json:"problems,omitempty"`
+}
+
+// Verify decodes every archived frame in archiveDir, packed and loose,
+// so zstd checks each frame's content checksum. Packed frames are also
+// compared with the SHA-256 and size in their index, and the index must
+// tile the pack: pack bytes no entry covers — left by an interrupted
+// append — and entries whose ranges overlap are reported, as are pack
+// files no index points at, left by an interrupted rewrite.
+func Verify(archiveDir string) (*VerifyReport, error) {
+	report := &VerifyReport{}
+	months, err := PackMonths(archiveDir)
+	if err != nil {
+		return nil, err
+	}
+	referenced := make(map[string]bool)
+	for _, month := range months {
+		entries, err := ReadPackIndex(archiveDir, month)
+		if err != nil {
+			report.Problems = append(report.Problems, VerifyProblem{
+				Location: packIndexFile(archiveDir, month), Error: err.Error()})
+			continue
+		}
+		report.Packs++
+		packPath := currentPackFile(archiveDir, month, entries)
+		referenced[packPath] = true
+		fi, err := os.Stat(packPath)
+		if err != nil {
+			report.Problems = append(report.Problems, VerifyProblem{
+				Location: packPath, Error: err.Error()})
+			continue
+		}
+		report.PackedBytes += fi.Size()
+		for _, e := range entries {
+			report.Packed++
+			if err := verifyEntry(archiveDir, e); err != nil {
+				report.Problems = append(report.Problems, VerifyProblem{
+					SessionID: e.SessionID, Location: PackRef(archiveDir, e), Error: err.Error()})
+			}
+		}
+		problems, end := verifyLayout(archiveDir, entries)
+		report.Problems = append(report.Problems, problems...)
+		if fi.Size() > end {
+			report.Problems = append(report.Problems, VerifyProblem{
+				Location: packPath,
+				Error:    fmt.Sprintf("%d trailing bytes not in the index", fi.Size()-end),
+			})
+		}
+	}
+	packs, err := filepath.Glob(filepath.Join(PackDir(archiveDir), "*.pack"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range packs {
+		if !referenced[path] {
+			report.Problems = append(report.Problems, VerifyProblem{
+				Location: path, Error: "pack file not referenced by any index"})
+		}
+	}
+
+	loose, err := filepath.Glob(filepath.Join(archiveDir, "*.jsonl.zst"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range loose {
+		report.Loose++
+		if fi, err := os.Stat(path); err == nil {
+			report.LooseBytes += fi.Size()
+		}
+		f, err := os.Open(path)
+		if err == nil {
+			_, err = decodeFrame(io.Discard, f, archiveDir)
+			f.Close()
+		}
+		if err != nil {
+			report.Problems = append(report.Problems, VerifyProblem{
+				SessionID: extractSessionID(path), Location: path, Error: err.Error()})
+		}
+	}
+	return report, nil
+}
+
+// verifyLayout checks that a pack's index entries, ordered by offset,
+// cover the pack contiguously from offset 0: each entry starts where the
+// previous one ended. It returns a problem per gap or overlap and the
+// end of the last entry.
+func verifyLayout(archiveDir string, entries []PackEntry) ([]VerifyProblem, int64) {
+	sorted := append([]PackEntry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Offset < sorted[j].Offset })
+	var problems []VerifyProblem
+	var end int64
+	var prev string
+	for _, e := range sorted {
+		switch {
+		case e.Offset > end:
+			problems = append(problems, VerifyProblem{
+				Location: entryPackFile(archiveDir, e),
+				Error:    fmt.Sprintf("%d bytes at offset %d not in the index", e.Offset-end, end),
+			})
+		case e.Offset < end:
+			problems = append(problems, VerifyProblem{
+				SessionID: e.SessionID, Location: PackRef(archiveDir, e),
+				Error: fmt.Sprintf("bytes %d-%d overlap %s", e.Offset, min(end, e.Offset+e.Length), prev),
+			})
+		}
+		if e.Offset+e.Length > end {
+			end = e.Offset + e.Length
+			prev = e.SessionID
+		}
+	}
+	return problems, end
+}
+
+// openPacked returns a reader over a packed session's compressed frame.
+func openPacked(archiveDir string, e PackEntry) (io.Reader, func(), error) {
+	f, err := os.Open(entryPackFile(archiveDir, e))
+	if err != nil {
+		return nil, nil, err
+	}
+	return io.NewSectionReader(f, e.Offset, e.Length), func() { f.Close() }, nil
+}
+
+// verifyEntry decodes a packed frame — zstd checks the frame's content
+// checksum — and compares its size and SHA-256 with the index.
+func verifyEntry(archiveDir string, e PackEntry) error {
+	r, closeFn, err := openPacked(archiveDir, e)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+	h := sha256.New()
+	cw := &countWriter{w: h}
+	if _, err := decodeFrame(cw, r, archiveDir); err != nil {
+		return err
+	}
+	if cw.n != e.Size {
+		return fmt.Errorf("size %d, index says %d", cw.n, e.Size)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != e.SHA256 {
+		return fmt.Errorf("sha256 %s, index says %s", sum[:12], shortSum(e.SHA256))
+	}
+	return nil
+}
+
+// decodeFrame decrypts (when sealed) and decompresses r into w, loading
+// the dictionary named in the frame header from archiveDir. Returns that
+// dictionary ID.
+func decodeFrame(w io.Writer, r io.Reader, archiveDir string) (uint32, error) {
+	r, err := unseal(r)
+	if err != nil {
+		return 0, err
+	}
+	br := bufio.NewReader(r)
+	hdr, _ := br.Peek(zstd.HeaderMaxSize)
+	var h zstd.Header
+	if err := h.Decode(hdr); err != nil {
+		return 0, fmt.Errorf("decode frame header: %w", err)
+	}
+	var dopts []zstd.DOption
+	if h.DictionaryID != 0 {
+		d, err := dictForID(archiveDir, h.DictionaryID)
+		if err != nil {
+			return 0, err
+		}
+		dopts = append(dopts, zstd.WithDecoderDicts(d.Data))
+	}
+	decoder, err := zstd.NewReader(br, dopts...)
+	if err != nil {
+		return 0, fmt.Errorf("create zstd decoder: %w", err)
+	}
+	defer decoder.Close()
+	if _, err := io.Copy(w, decoder); err != nil {
+		return 0, fmt.Errorf("decompress: %w", err)
+	}
+	return h.DictionaryID, nil
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func shortSum(s string) string {
+	if len(s) > 12 {
+		return s[:12]
+	}
+	return s
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
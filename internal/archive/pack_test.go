@@ -0,0 +1,208 @@
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTranscript(t *testing.T, dir, sessionID string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, sessionID+".jsonl")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func decompressed(t *testing.T, archivePath string) string {
+	t.Helper()
+	tmp, cleanup, err := Decompress(archivePath)
+	if err != nil {
+		t.Fatalf("Decompress(%s): %v", archivePath, err)
+	}
+	defer cleanup()
+	got, _ := os.ReadFile(tmp)
+	return string(got)
+}
+
+func TestPackWith_TransparentLookup(t *testing.T) {
+	srcDir := t.TempDir()
+	archiveDir := t.TempDir()
+
+	originals := map[string][]byte{}
+	for i := 0; i < 3; i++ {
+		id := fmt.Sprintf("%08d-bbbb-cccc-dddd-eeeeeeeeeeee", i)
+		originals[id] = transcriptSample(i)
+		e, err := PackWith(writeTranscript(t, srcDir, id, originals[id]), archiveDir, "2026-03", Options{})
+		if err != nil {
+			t.Fatalf("PackWith: %v", err)
+		}
+		if e.Month != "2026-03" || e.Size != int64(len(originals[id])) {
+			t.Errorf("entry = %+v", e)
+		}
+	}
+
+	for id, want := range originals {
+		if !IsArchived(id, archiveDir) {
+			t.Errorf("IsArchived(%s) = false", id)
+		}
+		ap := ArchivePath(id, archiveDir)
+		if !strings.HasSuffix(ap, filepath.Join("packs", "2026-03.pack")+"#"+id) {
+			t.Errorf("ArchivePath = %s", ap)
+		}
+		if got := decompressed(t, ap); got != string(want) {
+			t.Errorf("%s: decompressed content mismatch", id)
+		}
+	}
+	if IsArchived(testSessionID, archiveDir) {
+		t.Error("IsArchived true for a session never packed")
+	}
+	if ap := ArchivePath(testSessionID, archiveDir); ap != filepath.Join(archiveDir, testSessionID+".jsonl.zst") {
+		t.Errorf("ArchivePath for unarchived session = %s", ap)
+	}
+}
+
+func TestMigrateToPack_KeepsDictAndRemovesLoose(t *testing.T) {
+	srcDir := t.TempDir()
+	archiveDir := t.TempDir()
+	d, err := TrainDict(archiveDir, trainSamples(40), TrainOptions{MaxSize: 8 << 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := transcriptSample(5)
+	loose, err := ArchiveWith(writeTranscript(t, srcDir, testSessionID, original), archiveDir, Options{Dict: d})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := MigrateToPack(testSessionID, archiveDir, "2026-02")
+	if err != nil {
+		t.Fatalf("MigrateToPack: %v", err)
+	}
+	if e.DictID != d.ID {
+		t.Errorf("DictID = %d, want %d", e.DictID, d.ID)
+	}
+	if _, err := os.Stat(loose); !os.IsNotExist(err) {
+		t.Errorf("loose archive still present: %v", err)
+	}
+	ap := ArchivePath(testSessionID, archiveDir)
+	if id, err := FrameDictID(ap); err != nil || id != d.ID {
+		t.Errorf("FrameDictID(%s) = %d, %v", ap, id, err)
+	}
+	if got := decompressed(t, ap); got != string(original) {
+		t.Error("decompressed content mismatch after migration")
+	}
+}
+
+func TestVerify_DetectsCorruptionAndTrailingBytes(t *testing.T) {
+	srcDir := t.TempDir()
+	archiveDir := t.TempDir()
+	var entries []*PackEntry
+	for i := 0; i < 2; i++ {
+		id := fmt.Sprintf("%08d-bbbb-cccc-dddd-eeeeeeeeeeee", i)
+		e, err := PackWith(writeTranscript(t, srcDir, id, transcriptSample(i)), archiveDir, "2026-01", Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, e)
+	}
+	if _, err := Archive(writeTranscript(t, srcDir, testSessionID, transcriptSample(9)), archiveDir); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Verify(archiveDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Packs != 1 || report.Packed != 2 || report.Loose != 1 || len(report.Problems) != 0 {
+		t.Fatalf("clean report = %+v", report)
+	}
+
+	// Flip a byte inside the second frame and leave a torn append behind.
+	packPath := filepath.Join(PackDir(archiveDir), "2026-01.pack")
+	data, _ := os.ReadFile(packPath)
+	data[entries[1].Offset+entries[1].Length/2] ^= 0xff
+	data = append(data, 0x28, 0xb5)
+	if err := os.WriteFile(packPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err = Verify(archiveDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Problems) != 2 {
+		t.Fatalf("problems = %+v", report.Problems)
+	}
+	if report.Problems[0].SessionID != entries[1].SessionID {
+		t.Errorf("corrupt frame reported as %+v", report.Problems[0])
+	}
+	if !strings.Contains(report.Problems[1].Error, "2 trailing bytes") {
+		t.Errorf("trailing bytes reported as %+v", report.Problems[1])
+	}
+}
+
+func TestVerify_DetectsGapsAndOverlaps(t *testing.T) {
+	srcDir := t.TempDir()
+	archiveDir := t.TempDir()
+	var entries []PackEntry
+	for i := 0; i < 2; i++ {
+		id := fmt.Sprintf("%08d-bbbb-cccc-dddd-eeeeeeeeeeee", i)
+		e, err := PackWith(writeTranscript(t, srcDir, id, transcriptSample(i)), archiveDir, "2026-01", Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, *e)
+	}
+
+	// Splice 3 stray bytes between the frames, shift the second entry
+	// past them, and index a second session over the first frame.
+	packPath := filepath.Join(PackDir(archiveDir), "2026-01.pack")
+	data, _ := os.ReadFile(packPath)
+	split := entries[1].Offset
+	data = append(data[:split:split], append([]byte{0, 0, 0}, data[split:]...)...)
+	if err := os.WriteFile(packPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entries[1].Offset += 3
+	dup := entries[0]
+	dup.SessionID = "dup"
+	var idx []byte
+	for _, e := range []PackEntry{entries[0], entries[1], dup} {
+		line, _ := json.Marshal(e)
+		idx = append(append(idx, line...), '\n')
+	}
+	if err := os.WriteFile(filepath.Join(PackDir(archiveDir), "2026-01.idx"), idx, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Verify(archiveDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Problems) != 2 {
+		t.Fatalf("problems = %+v", report.Problems)
+	}
+	if p := report.Problems[0]; p.SessionID != "dup" || !strings.Contains(p.Error, "overlap "+entries[0].SessionID) {
+		t.Errorf("overlap reported as %+v", p)
+	}
+	if want := fmt.Sprintf("3 bytes at offset %d not in the index", split); !strings.Contains(report.Problems[1].Error, want) {
+		t.Errorf("gap reported as %+v, want %q", report.Problems[1], want)
+	}
+}
+
+func TestParsePackRef(t *testing.T) {
+	dir, month, id, ok := parsePackRef("/v/.vibe-vault/archive/packs/2026-03.pack#abc")
+	if !ok || dir != "/v/.vibe-vault/archive" || month != "2026-03" || id != "abc" {
+		t.Errorf("parsePackRef = %q %q %q %v", dir, month, id, ok)
+	}
+	for _, ref := range []string{"/a/abc.jsonl.zst", "/a/packs/bad.pack#abc", "/a/packs/2026-03.pack#"} {
+		if _, _, _, ok := parsePackRef(ref); ok {
+			t.Errorf("parsePackRef(%q) ok", ref)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package archive
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/suykerbuyk/vibe-vault/internal/atomicfile"
+	"github.com/suykerbuyk/vibe-vault/internal/lockfile"
+)
+
+// Restore decompresses a session's archive to dest, creating parent
+// directories. The file is written atomically, so a failed restore never
+// leaves a truncated transcript behind. Returns the bytes written.
+func Restore(sessionID, archiveDir, dest string) (int64, error) {
+	if !IsArchived(sessionID, archiveDir) {
+		return 0, fmt.Errorf("session %s is not archived", sessionID)
+	}
+	src, dictDir, closeSrc, err := openArchive(ArchivePath(sessionID, archiveDir))
+	if err != nil {
+		return 0, fmt.Errorf("open archive: %w", err)
+	}
+	defer closeSrc()
+	var content bytes.Buffer
+	if _, err := decodeFrame(&content, src, dictDir); err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return 0, fmt.Errorf("create destination dir: %w", err)
+	}
+	if err := atomicfile.Write("", dest, content.Bytes()); err != nil {
+		return 0, err
+	}
+	return int64(content.Len()), nil
+}
+
+// MatchesOriginal decodes a session's archive and checks it is
+// byte-for-byte the transcript at originalPath. An original that grew
+// after archiving (a session still in progress) does not match.
+func MatchesOriginal(sessionID, archiveDir, originalPath string) error {
+	if !IsArchived(sessionID, archiveDir) {
+		return fmt.Errorf("session %s is not archived", sessionID)
+	}
+	src, dictDir, closeSrc, err := openArchive(ArchivePath(sessionID, archiveDir))
+	if err != nil {
+		return fmt.Errorf("open archive: %w", err)
+	}
+	defer closeSrc()
+	h := sha256.New()
+	cw := &countWriter{w: h}
+	if _, err := decodeFrame(cw, src, dictDir); err != nil {
+		return err
+	}
+
+	f, err := os.Open(originalPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	oh := sha256.New()
+	n, err := io.Copy(oh, f)
+	if err != nil {
+		return fmt.Errorf("read original: %w", err)
+	}
+	if n != cw.n || !bytes.Equal(h.Sum(nil), oh.Sum(nil)) {
+		return fmt.Errorf("archive (%d bytes) differs from original (%d bytes)", cw.n, n)
+	}
+	return nil
+}
+
+// Remove deletes the archives of sessionIDs, loose or packed. Packs that
+// lose frames are rewritten without them; a pack left empty is deleted
+// with its index. Returns the compressed bytes freed.
+func Remove(archiveDir string, sessionIDs []string) (int64, error) {
+	var freed int64
+	byMonth := make(map[string]map[string]bool)
+	for _, id := range sessionIDs {
+		loose := loosePath(id, archiveDir)
+		if fi, err := os.Stat(loose); err == nil {
+			if err := os.Remove(loose); err != nil {
+				return freed, err
+			}
+			freed += fi.Size()
+		}
+		if e, ok := lookupPacked(id, archiveDir); ok {
+			if byMonth[e.Month] == nil {
+				byMonth[e.Month] = make(map[string]bool)
+			}
+			byMonth[e.Month][id] = true
+		}
+	}
+	if len(byMonth) == 0 {
+		return freed, nil
+	}
+
+	fl, err := lockfile.Acquire(filepath.Join(PackDir(archiveDir), ".lock"))
+	if err != nil {
+		return freed, fmt.Errorf("acquire pack lock: %w", err)
+	}
+	defer func() { _ = fl.Release() }()
+
+	months := make([]string, 0, len(byMonth))
+	for m := range byMonth {
+		months = append(months, m)
+	}
+	sort.Strings(months)
+	for _, month := range months {
+		n, err := rewritePack(archiveDir, month, byMonth[month])
+		freed += n
+		if err != nil {
+			return freed, err
+		}
+	}
+	return freed, nil
+}
+
+// beforePackSwitch runs after rewritePack writes the new pack and
+// before the index switches to it. Production code must leave this as a
+// no-op; tests override it to simulate a crash between the two writes.
+var beforePackSwitch = func() error { return nil }
+
+// rewritePack drops the frames of drop from a month's pack. The caller
+// holds the pack lock. The kept frames go to a new generation file and
+// the index is then replaced to point at it, so a crash leaves either
+// the old pack and index or the new ones; the file left over is deleted
+// by the next rewrite and reported by vv archive verify until then.
+func rewritePack(archiveDir, month string, drop map[string]bool) (int64, error) {
+	entries, err := ReadPackIndex(archiveDir, month)
+	if err != nil {
+		return 0, err
+	}
+	var keep []PackEntry
+	var freed int64
+	for _, e := range entries {
+		if drop[e.SessionID] {
+			freed += e.Length
+		} else {
+			keep = append(keep, e)
+		}
+	}
+	idxPath := packIndexFile(archiveDir, month)
+	if len(keep) == 0 {
+		if err := os.Remove(idxPath); err != nil {
+			return 0, err
+		}
+		return freed, removeStalePacks(archiveDir, month, "")
+	}
+
+	old, err := os.Open(currentPackFile(archiveDir, month, entries))
+	if err != nil {
+		return 0, err
+	}
+	defer old.Close()
+	name := nextPackName(month, entries)
+	var pack, idx bytes.Buffer
+	for i := range keep {
+		e := &keep[i]
+		if _, err := io.Copy(&pack, io.NewSectionReader(old, e.Offset, e.Length)); err != nil {
+			return 0, fmt.Errorf("copy frame %s: %w", e.SessionID, err)
+		}
+		e.Offset = int64(pack.Len()) - e.Length
+		e.Pack = name
+		line, err := json.Marshal(e)
+		if err != nil {
+			return 0, err
+		}
+		idx.Write(append(line, '\n'))
+	}
+	packPath := filepath.Join(PackDir(archiveDir), name)
+	if err := writeSynced(packPath, pack.Bytes()); err != nil {
+		return 0, err
+	}
+	if err := beforePackSwitch(); err != nil {
+		return 0, err
+	}
+	if err := atomicfile.Write("", idxPath, idx.Bytes()); err != nil {
+		return 0, err
+	}
+	return freed, removeStalePacks(archiveDir, month, packPath)
+}
+
+// nextPackName returns the generation file name for a rewrite of a
+// month's pack: YYYY-MM.pack becomes YYYY-MM.1.pack, YYYY-MM.1.pack
+// becomes YYYY-MM.2.pack.
+func nextPackName(month string, entries []PackEntry) string {
+	var gen int
+	if len(entries) > 0 {
+		name := entries[len(entries)-1].Pack
+		gen, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, month+"."), ".pack"))
+	}
+	return fmt.Sprintf("%s.%d.pack", month, gen+1)
+}
+
+// removeStalePacks deletes a month's pack files other than current.
+func removeStalePacks(archiveDir, month, current string) error {
+	dir := PackDir(archiveDir)
+	stale, err := filepath.Glob(filepath.Join(dir, month+".*.pack"))
+	if err != nil {
+		return err
+	}
+	for _, path := range append(stale, packFile(archiveDir, month)) {
+		if path == current {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSynced writes data to path and syncs it to disk.
+func writeSynced(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
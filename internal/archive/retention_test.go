@@ -0,0 +1,192 @@
+package archive
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/suykerbuyk/vibe-vault/internal/vaultcrypt"
+)
+
+func TestRestore_FromPackAndLoose(t *testing.T) {
+	srcDir := t.TempDir()
+	archiveDir := t.TempDir()
+	packedID := "11111111-bbbb-cccc-dddd-eeeeeeeeeeee"
+	if _, err := PackWith(writeTranscript(t, srcDir, packedID, transcriptSample(1)), archiveDir, "2026-04", Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Archive(writeTranscript(t, srcDir, testSessionID, transcriptSample(2)), archiveDir); err != nil {
+		t.Fatal(err)
+	}
+
+	for id, want := range map[string][]byte{packedID: transcriptSample(1), testSessionID: transcriptSample(2)} {
+		dest := filepath.Join(t.TempDir(), "projects", "-home-dev", id+".jsonl")
+		n, err := Restore(id, archiveDir, dest)
+		if err != nil {
+			t.Fatalf("Restore(%s): %v", id, err)
+		}
+		got, _ := os.ReadFile(dest)
+		if string(got) != string(want) || n != int64(len(want)) {
+			t.Errorf("%s: restored %d bytes, content match %v", id, n, string(got) == string(want))
+		}
+	}
+	if _, err := Restore("not-archived", archiveDir, filepath.Join(t.TempDir(), "x.jsonl")); err == nil {
+		t.Error("Restore of an unarchived session succeeded")
+	}
+}
+
+func TestMatchesOriginal(t *testing.T) {
+	srcDir := t.TempDir()
+	archiveDir := t.TempDir()
+	src := writeTranscript(t, srcDir, testSessionID, transcriptSample(3))
+	if _, err := Archive(src, archiveDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := MatchesOriginal(testSessionID, archiveDir, src); err != nil {
+		t.Errorf("MatchesOriginal on an unchanged original: %v", err)
+	}
+
+	// The session kept going after it was archived.
+	f, _ := os.OpenFile(src, os.O_APPEND|os.O_WRONLY, 0o644)
+	f.WriteString(`{"type":"user"}` + "\n")
+	f.Close()
+	if err := MatchesOriginal(testSessionID, archiveDir, src); err == nil {
+		t.Error("MatchesOriginal accepted an original that grew")
+	}
+}
+
+func TestRemove_RewritesPacks(t *testing.T) {
+	srcDir := t.TempDir()
+	archiveDir := t.TempDir()
+	ids := make([]string, 3)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("%08d-bbbb-cccc-dddd-eeeeeeeeeeee", i)
+		if _, err := PackWith(writeTranscript(t, srcDir, ids[i], transcriptSample(i)), archiveDir, "2026-05", Options{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lone := "99999999-bbbb-cccc-dddd-eeeeeeeeeeee"
+	if _, err := PackWith(writeTranscript(t, srcDir, lone, transcriptSample(9)), archiveDir, "2026-06", Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	freed, err := Remove(archiveDir, []string{ids[0], lone})
+	if err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if freed == 0 {
+		t.Error("Remove reported nothing freed")
+	}
+	if IsArchived(ids[0], archiveDir) || IsArchived(lone, archiveDir) {
+		t.Error("removed sessions still archived")
+	}
+	if _, err := os.Stat(filepath.Join(PackDir(archiveDir), "2026-06.pack")); !os.IsNotExist(err) {
+		t.Errorf("emptied pack not deleted: %v", err)
+	}
+	for _, id := range ids[1:] {
+		if got := decompressed(t, ArchivePath(id, archiveDir)); got != string(transcriptSample(slices.Index(ids, id))) {
+			t.Errorf("%s: content changed by pack rewrite", id)
+		}
+	}
+	report, err := Verify(archiveDir)
+	if err != nil || len(report.Problems) != 0 || report.Packed != 2 {
+		t.Errorf("Verify after Remove = %+v, %v", report, err)
+	}
+}
+
+func TestRemove_CrashBeforeIndexSwitch(t *testing.T) {
+	srcDir := t.TempDir()
+	archiveDir := t.TempDir()
+	ids := make([]string, 3)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("%08d-bbbb-cccc-dddd-eeeeeeeeeeee", i)
+		if _, err := PackWith(writeTranscript(t, srcDir, ids[i], transcriptSample(i)), archiveDir, "2026-05", Options{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkContent := func(ids ...string) {
+		t.Helper()
+		for _, id := range ids {
+			i, _ := strconv.Atoi(id[:8])
+			if got := decompressed(t, ArchivePath(id, archiveDir)); got != string(transcriptSample(i)) {
+				t.Errorf("%s: content changed", id)
+			}
+		}
+	}
+
+	prev := beforePackSwitch
+	t.Cleanup(func() { beforePackSwitch = prev })
+	beforePackSwitch = func() error { return errors.New("crash") }
+	if _, err := Remove(archiveDir, []string{ids[0]}); err == nil {
+		t.Fatal("Remove succeeded through a crash")
+	}
+	// The old pack and index still pair up; only the new file is extra.
+	checkContent(ids...)
+	report, err := Verify(archiveDir)
+	if err != nil || len(report.Problems) != 1 || report.Packed != 3 {
+		t.Fatalf("Verify after crash = %+v, %v", report, err)
+	}
+	if got := filepath.Base(report.Problems[0].Location); got != "2026-05.1.pack" {
+		t.Errorf("Verify flagged %s, want the unswitched 2026-05.1.pack", got)
+	}
+
+	beforePackSwitch = prev
+	if _, err := Remove(archiveDir, []string{ids[0]}); err != nil {
+		t.Fatalf("Remove retry: %v", err)
+	}
+	if IsArchived(ids[0], archiveDir) {
+		t.Error("removed session still archived")
+	}
+	if _, err := os.Stat(filepath.Join(PackDir(archiveDir), "2026-05.pack")); !os.IsNotExist(err) {
+		t.Errorf("superseded pack not deleted: %v", err)
+	}
+	// Appends go to the generation the index now points at.
+	added := "00000003-bbbb-cccc-dddd-eeeeeeeeeeee"
+	if _, err := PackWith(writeTranscript(t, srcDir, added, transcriptSample(3)), archiveDir, "2026-05", Options{}); err != nil {
+		t.Fatal(err)
+	}
+	checkContent(ids[1], ids[2], added)
+	if report, err := Verify(archiveDir); err != nil || len(report.Problems) != 0 || report.Packed != 3 {
+		t.Errorf("Verify after retry = %+v, %v", report, err)
+	}
+}
+
+func TestEncryptedArchive_TransparentWithKey(t *testing.T) {
+	srcDir := t.TempDir()
+	archiveDir := t.TempDir()
+	key, _ := vaultcrypt.NewKey(bytes.Repeat([]byte{3}, 32))
+	original := transcriptSample(4)
+
+	loose, err := ArchiveWith(writeTranscript(t, srcDir, testSessionID, original), archiveDir, Options{Key: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	packedID := "22222222-bbbb-cccc-dddd-eeeeeeeeeeee"
+	if _, err := PackWith(writeTranscript(t, srcDir, packedID, original), archiveDir, "2026-07", Options{Key: key}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(loose); !vaultcrypt.IsSealed(data) {
+		t.Fatal("archive written without encryption")
+	}
+
+	vaultcrypt.SetDefault(nil)
+	if _, _, err := Decompress(loose); !errors.Is(err, vaultcrypt.ErrNoKey) {
+		t.Errorf("Decompress without key: err = %v, want ErrNoKey", err)
+	}
+
+	vaultcrypt.SetDefault(key)
+	t.Cleanup(func() { vaultcrypt.SetDefault(nil) })
+	for _, id := range []string{testSessionID, packedID} {
+		if got := decompressed(t, ArchivePath(id, archiveDir)); got != string(original) {
+			t.Errorf("%s: decrypted content mismatch", id)
+		}
+	}
+	if report, err := Verify(archiveDir); err != nil || len(report.Problems) != 0 {
+		t.Errorf("Verify = %+v, %v", report, err)
+	}
+}
@@ -0,0 +1,413 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Package backfill captures historical transcripts in bulk. A worker
+// pool parses, extracts, and enriches transcripts concurrently; a single
+// writer commits the prepared sessions in discovery order, in short
+// batches under the index lock, so hook captures can interleave and note
+// linking matches a serial run. The index is the resume record: every
+// committed batch is saved before the next begins, and a later run skips
+// indexed sessions before parsing anything, so an interrupted backfill
+// resumes where it stopped.
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/discover"
+	"github.com/suykerbuyk/vibe-vault/internal/enrichment"
+	"github.com/suykerbuyk/vibe-vault/internal/index"
+	"github.com/suykerbuyk/vibe-vault/internal/llm"
+	"github.com/suykerbuyk/vibe-vault/internal/lockfile"
+	"github.com/suykerbuyk/vibe-vault/internal/narrative"
+	"github.com/suykerbuyk/vibe-vault/internal/prose"
+	"github.com/suykerbuyk/vibe-vault/internal/session"
+	"github.com/suykerbuyk/vibe-vault/internal/transcript"
+)
+
+const (
+	maxDefaultWorkers     = 8
+	defaultLLMConcurrency = 2
+	// windowPerWorker bounds how far workers may run ahead of the
+	// in-order writer, capping the parsed transcripts held in memory.
+	windowPerWorker = 4
+)
+
+// Options configures Run.
+type Options struct {
+	Workers        int          // parse/extract workers; 0 = NumCPU, at most 8
+	LLMConcurrency int          // enrichment calls in flight; 0 = 2
+	Provider       llm.Provider // nil = heuristic notes only
+	StagingRoot    string       // see session.CaptureOpts.StagingRoot
+	OnProgress     func(Progress)
+}
+
+func (o Options) workers() int {
+	if o.Workers > 0 {
+		return o.Workers
+	}
+	return min(runtime.NumCPU(), maxDefaultWorkers)
+}
+
+func (o Options) llmConcurrency() int {
+	if o.LLMConcurrency > 0 {
+		return o.LLMConcurrency
+	}
+	return defaultLLMConcurrency
+}
+
+// Failure is one transcript that could not be captured.
+type Failure struct {
+	SessionID string `json:"session_id"`
+	Path      string `json:"path"`
+	Stage     string `json:"stage"` // "parse" or "capture"
+	Error     string `json:"error"`
+}
+
+// Report summarizes a backfill run.
+type Report struct {
+	Found       int           `json:"found"`
+	Processed   int           `json:"processed"`
+	Enriched    int           `json:"enriched"`
+	Skipped     int           `json:"skipped"` // already indexed or trivial
+	Patched     int           `json:"patched"` // transcript paths added to existing entries
+	Failures    []Failure     `json:"failures"`
+	Interrupted bool          `json:"interrupted"`
+	Elapsed     time.Duration `json:"-"`
+}
+
+// Progress is reported after each transcript the writer resolves.
+type Progress struct {
+	Done, Total                int
+	Processed, Skipped, Failed int
+	Elapsed                    time.Duration
+}
+
+// ETA extrapolates the time remaining from the rate so far; zero until
+// the first transcript is done.
+func (p Progress) ETA() time.Duration {
+	if p.Done == 0 {
+		return 0
+	}
+	return p.Elapsed * time.Duration(p.Total-p.Done) / time.Duration(p.Done)
+}
+
+// String renders a one-line progress report.
+func (p Progress) String() string {
+	pct := 100.0
+	if p.Total > 0 {
+		pct = float64(p.Done) * 100 / float64(p.Total)
+	}
+	rate := 0.0
+	if p.Elapsed > 0 {
+		rate = float64(p.Done) / p.Elapsed.Seconds()
+	}
+	eta := "--"
+	if p.Done > 0 {
+		eta = p.ETA().Round(time.Second).String()
+	}
+	return fmt.Sprintf("%d/%d (%.0f%%) %.1f/s ETA %s | processed %d, skipped %d, failed %d",
+		p.Done, p.Total, pct, rate, eta, p.Processed, p.Skipped, p.Failed)
+}
+
+// job is one transcript queued for a worker; seq is its discovery order.
+type job struct {
+	seq int
+	tf  discover.TranscriptFile
+}
+
+// prepared is a worker's output: a session ready for the writer, a
+// failure, or a job dropped because the run was cancelled.
+type prepared struct {
+	seq     int
+	tf      discover.TranscriptFile
+	opts    session.CaptureOpts
+	failure *Failure
+	dropped bool
+}
+
+// Run captures every transcript in transcripts that is not yet indexed.
+// Cancelling ctx stops new work; sessions already prepared are still
+// committed, and in-flight enrichment is discarded rather than written
+// unenriched, so the next run picks those sessions up again along with
+// failed and trivial ones. Run returns an error only when the index
+// cannot be loaded or saved; per-transcript failures are listed in the
+// Report.
+func Run(ctx context.Context, transcripts []discover.TranscriptFile, opts Options, cfg config.Config) (*Report, error) {
+	start := time.Now()
+	report := &Report{Found: len(transcripts), Failures: []Failure{}}
+
+	// Skip what is already indexed, patching missing transcript paths
+	// on the way.
+	var queue []discover.TranscriptFile
+	err := withIndex(cfg, func(idx *index.Index) bool {
+		for _, tf := range transcripts {
+			if entry, ok := idx.Entries[tf.SessionID]; ok {
+				if entry.TranscriptPath == "" {
+					entry.TranscriptPath = tf.Path
+					idx.Entries[tf.SessionID] = entry
+					report.Patched++
+				}
+				report.Skipped++
+				continue
+			}
+			queue = append(queue, tf)
+		}
+		return report.Patched > 0
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	w := &writer{
+		cfg:      cfg,
+		opts:     opts,
+		report:   report,
+		progress: Progress{Total: len(queue)},
+		start:    start,
+	}
+	if len(queue) > 0 {
+		err = w.run(ctx, queue)
+	}
+	report.Interrupted = ctx.Err() != nil
+	report.Elapsed = time.Since(start)
+	return report, err
+}
+
+type writer struct {
+	cfg      config.Config
+	opts     Options
+	report   *Report
+	progress Progress
+	start    time.Time
+}
+
+func (w *writer) run(ctx context.Context, queue []discover.TranscriptFile) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	workers := w.opts.workers()
+	window := make(chan struct{}, workers*windowPerWorker)
+	jobs := make(chan job)
+	results := make(chan prepared, cap(window))
+	llmSlots := make(chan struct{}, w.opts.llmConcurrency())
+
+	// Dispatch in discovery order, never more than the window ahead of
+	// the writer. Every dispatched job yields exactly one result, so the
+	// writer's sequence has no gaps.
+	go func() {
+		defer close(jobs)
+		for seq, tf := range queue {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			jobs <- job{seq: seq, tf: tf}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				results <- prepare(ctx, j, w.opts, llmSlots, w.cfg)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	pending := make(map[int]prepared)
+	next := 0
+	for r := range results {
+		pending[r.seq] = r
+		// Take whatever else is ready so one lock covers a batch.
+	drain:
+		for {
+			select {
+			case r, ok := <-results:
+				if !ok {
+					break drain
+				}
+				pending[r.seq] = r
+			default:
+				break drain
+			}
+		}
+		var batch []prepared
+		for {
+			p, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			batch = append(batch, p)
+			next++
+		}
+		if len(batch) == 0 {
+			continue
+		}
+		err := w.commit(batch)
+		for range batch {
+			<-window
+		}
+		if err != nil {
+			// Stop the workers and unblock the pipeline before giving up.
+			cancel()
+			go func() {
+				for range results {
+					<-window
+				}
+			}()
+			return err
+		}
+	}
+	return nil
+}
+
+// prepare does a job's parallel work: parse, detect, extract, and (when
+// a provider is set) enrich, holding one of llmSlots for the call.
+func prepare(ctx context.Context, j job, opts Options, llmSlots chan struct{}, cfg config.Config) prepared {
+	p := prepared{seq: j.seq, tf: j.tf}
+	if ctx.Err() != nil {
+		p.dropped = true
+		return p
+	}
+	t, err := transcript.ParseFile(j.tf.Path)
+	if err != nil {
+		p.failure = &Failure{SessionID: j.tf.SessionID, Path: j.tf.Path, Stage: "parse", Error: err.Error()}
+		return p
+	}
+	cwd := t.Stats.CWD
+	sessionID := t.Stats.SessionID
+	if sessionID == "" {
+		sessionID = j.tf.SessionID
+	}
+	info := session.Detect(cwd, t.Stats.GitBranch, t.Stats.Model, sessionID, cfg)
+	narr := narrative.Extract(t, cwd)
+	dialogue := prose.Extract(t, cwd)
+
+	var enriched *enrichment.Result
+	if opts.Provider != nil {
+		select {
+		case llmSlots <- struct{}{}:
+		case <-ctx.Done():
+			p.dropped = true
+			return p
+		}
+		enriched = session.Enrich(ctx, opts.Provider, t, info, narr, dialogue, cfg)
+		<-llmSlots
+		if ctx.Err() != nil {
+			// The call may have been cut short; leave the session for
+			// the next run rather than write it unenriched.
+			p.dropped = true
+			return p
+		}
+	}
+
+	p.opts = session.CaptureOpts{
+		TranscriptPath: j.tf.Path,
+		CWD:            cwd,
+		SessionID:      sessionID,
+		StagingRoot:    opts.StagingRoot,
+		Enrichment:     enriched,
+		Transcript:     t,
+		Info:           &info,
+		Narrative:      narr,
+		Dialogue:       dialogue,
+	}
+	return p
+}
+
+// commit writes a batch of prepared sessions under one index lock and
+// saves the index before returning, so the batch survives an interrupt.
+func (w *writer) commit(batch []prepared) error {
+	return withIndex(w.cfg, func(idx *index.Index) bool {
+		changed := false
+		for _, p := range batch {
+			if p.dropped {
+				continue
+			}
+			w.progress.Done++
+			if p.failure != nil {
+				w.fail(*p.failure)
+				continue
+			}
+			p.opts.Index = idx
+			res, err := session.Capture(p.opts, w.cfg)
+			switch {
+			case err != nil:
+				w.fail(Failure{SessionID: p.tf.SessionID, Path: p.tf.Path, Stage: "capture", Error: err.Error()})
+				continue
+			case res.Skipped:
+				w.report.Skipped++
+				w.progress.Skipped++
+			default:
+				changed = true
+				w.report.Processed++
+				w.progress.Processed++
+				if res.EnrichmentApplied {
+					w.report.Enriched++
+				}
+			}
+			w.notify()
+		}
+		return changed
+	})
+}
+
+func (w *writer) fail(f Failure) {
+	w.report.Failures = append(w.report.Failures, f)
+	w.progress.Failed++
+	w.notify()
+}
+
+func (w *writer) notify() {
+	if w.opts.OnProgress == nil {
+		return
+	}
+	w.progress.Elapsed = time.Since(w.start)
+	w.opts.OnProgress(w.progress)
+}
+
+// withIndex runs fn on the freshly loaded index under the index lock,
+// saving it when fn reports a change. Holding the lock per batch rather
+// than per run lets hook captures interleave with a long backfill.
+func withIndex(cfg config.Config, fn func(idx *index.Index) (changed bool)) error {
+	stateDir := cfg.StateDir()
+	if err := os.MkdirAll(stateDir, 0o755); err != nil {
+		log.Printf("warning: could not create state dir: %v", err)
+	}
+	fl, err := lockfile.Acquire(filepath.Join(stateDir, "session-index.json") + ".lock")
+	if err != nil {
+		log.Printf("warning: could not acquire index lock: %v", err)
+	}
+	defer func() {
+		if fl != nil {
+			_ = fl.Release()
+		}
+	}()
+
+	idx, err := index.Load(stateDir)
+	if err != nil {
+		return fmt.Errorf("load index: %w", err)
+	}
+	if fn(idx) {
+		if err := idx.Save(); err != nil {
+			return fmt.Errorf("save index: %w", err)
+		}
+	}
+	return nil
+}
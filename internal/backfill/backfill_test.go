@@ -0,0 +1,235 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/discover"
+	"github.com/suykerbuyk/vibe-vault/internal/index"
+)
+
+// writeTranscript writes a minimal two-exchange session (or a trivial
+// one-message session) and returns it as a discovered file.
+func writeTranscript(t *testing.T, dir string, n int, trivial bool) discover.TranscriptFile {
+	t.Helper()
+	id := fmt.Sprintf("0000%04d-0000-4000-8000-000000000000", n)
+	line := func(typ, uuid, minute, content string) string {
+		msg := fmt.Sprintf(`{"role":"user","content":%q}`, content)
+		if typ == "assistant" {
+			msg = fmt.Sprintf(`{"role":"assistant","model":"claude-opus-4-6","content":[{"type":"text","text":%q}]}`, content)
+		}
+		return fmt.Sprintf(`{"type":%q,"uuid":%q,"sessionId":%q,"timestamp":"2027-06-15T10:%s:00Z","cwd":"/home/dev/backfill-proj","message":%s}`+"\n",
+			typ, uuid, id, minute, msg)
+	}
+	data := line("user", "u1", "00", fmt.Sprintf("Implement feature number %d", n))
+	if !trivial {
+		data += line("assistant", "a1", "01", "Implemented the feature.") +
+			line("user", "u2", "02", "Now add tests for it") +
+			line("assistant", "a2", "03", "Added the tests.")
+	}
+	path := filepath.Join(dir, id+".jsonl")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return discover.TranscriptFile{Path: path, SessionID: id}
+}
+
+func TestRun_CommitsInOrder(t *testing.T) {
+	cfg := config.Config{VaultPath: t.TempDir()}
+	src := t.TempDir()
+	var tfs []discover.TranscriptFile
+	for i := 0; i < 12; i++ {
+		tfs = append(tfs, writeTranscript(t, src, i, false))
+	}
+	tfs = append(tfs, writeTranscript(t, src, 99, true))
+
+	var last Progress
+	report, err := Run(context.Background(), tfs, Options{
+		Workers:    4,
+		OnProgress: func(p Progress) { last = p },
+	}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Processed != 12 || report.Skipped != 1 || len(report.Failures) != 0 || report.Interrupted {
+		t.Fatalf("report = %+v", report)
+	}
+	if last.Done != 13 || last.Total != 13 || last.Processed != 12 {
+		t.Errorf("last progress = %+v", last)
+	}
+
+	// The single writer commits in discovery order, so iteration
+	// ordinals follow the input regardless of which worker finished first.
+	idx, err := index.Load(cfg.StateDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, tf := range tfs[:12] {
+		e, ok := idx.Entries[tf.SessionID]
+		if !ok {
+			t.Fatalf("%s not indexed", tf.SessionID)
+		}
+		if e.Iteration != i+1 {
+			t.Errorf("%s iteration = %d, want %d", tf.SessionID, e.Iteration, i+1)
+		}
+	}
+
+	// A second run skips what is indexed and re-reads only the trivial
+	// session, which may have grown.
+	last = Progress{}
+	again, err := Run(context.Background(), tfs, Options{OnProgress: func(p Progress) { last = p }}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Processed != 0 || again.Skipped != 13 || last.Total != 1 {
+		t.Errorf("second run = %+v, progress %+v", again, last)
+	}
+}
+
+func TestRun_ResumesFromIndex(t *testing.T) {
+	cfg := config.Config{VaultPath: t.TempDir()}
+	src := t.TempDir()
+	var tfs []discover.TranscriptFile
+	for i := 0; i < 12; i++ {
+		tfs = append(tfs, writeTranscript(t, src, i, false))
+	}
+
+	// Interrupt after the third commit; whatever was already prepared
+	// is still committed.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	first, err := Run(ctx, tfs, Options{
+		Workers: 1,
+		OnProgress: func(p Progress) {
+			if p.Processed == 3 {
+				cancel()
+			}
+		},
+	}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.Interrupted || first.Processed < 3 || first.Processed >= len(tfs) {
+		t.Fatalf("first run = %+v", first)
+	}
+
+	// Corrupt the transcripts the first run committed: the resumed run
+	// must not parse them again, only queue the remainder.
+	idx, err := index.Load(cfg.StateDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tf := range tfs {
+		if _, ok := idx.Entries[tf.SessionID]; ok {
+			if err := os.WriteFile(tf.Path, []byte("not json\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	var last Progress
+	second, err := Run(context.Background(), tfs, Options{
+		Workers:    1,
+		OnProgress: func(p Progress) { last = p },
+	}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remaining := len(tfs) - first.Processed
+	if second.Skipped != first.Processed || second.Processed != remaining || len(second.Failures) != 0 {
+		t.Errorf("second run = %+v, want %d skipped and %d processed", second, first.Processed, remaining)
+	}
+	if last.Total != remaining || last.Done != remaining {
+		t.Errorf("second run progress = %+v, want %d queued", last, remaining)
+	}
+	idx, err = index.Load(cfg.StateDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, tf := range tfs {
+		if e := idx.Entries[tf.SessionID]; e.Iteration != i+1 {
+			t.Errorf("%s iteration = %d, want %d", tf.SessionID, e.Iteration, i+1)
+		}
+	}
+}
+
+func TestRun_CollectsFailures(t *testing.T) {
+	cfg := config.Config{VaultPath: t.TempDir()}
+	src := t.TempDir()
+	good := writeTranscript(t, src, 1, false)
+	missing := discover.TranscriptFile{Path: filepath.Join(src, "gone.jsonl"), SessionID: "gone"}
+	tfs := []discover.TranscriptFile{missing, good}
+
+	report, err := Run(context.Background(), tfs, Options{}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Processed != 1 || len(report.Failures) != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	if f := report.Failures[0]; f.SessionID != "gone" || f.Stage != "parse" || f.Error == "" {
+		t.Errorf("failure = %+v", f)
+	}
+
+	// The retry attempts the failed session again and skips the good one.
+	var last Progress
+	retry, err := Run(context.Background(), tfs, Options{OnProgress: func(p Progress) { last = p }}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retry.Skipped != 1 || len(retry.Failures) != 1 || last.Total != 1 {
+		t.Errorf("retry = %+v, progress %+v", retry, last)
+	}
+}
+
+func TestRun_TrivialLeftForNextRun(t *testing.T) {
+	cfg := config.Config{VaultPath: t.TempDir()}
+	trivial := writeTranscript(t, t.TempDir(), 1, true)
+
+	report, err := Run(context.Background(), []discover.TranscriptFile{trivial}, Options{}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Skipped != 1 || report.Processed != 0 {
+		t.Fatalf("report = %+v", report)
+	}
+	idx, err := index.Load(cfg.StateDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := idx.Entries[trivial.SessionID]; ok {
+		t.Error("trivial session was indexed; a live session would never be captured")
+	}
+}
+
+func TestRun_CancelledBeforeStart(t *testing.T) {
+	cfg := config.Config{VaultPath: t.TempDir()}
+	tf := writeTranscript(t, t.TempDir(), 1, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	report, err := Run(ctx, []discover.TranscriptFile{tf}, Options{}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Interrupted || report.Processed != 0 {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestProgress_ETA(t *testing.T) {
+	p := Progress{Done: 25, Total: 100, Elapsed: 10e9}
+	if got := p.ETA(); got != 30e9 {
+		t.Errorf("ETA = %v, want 30s", got)
+	}
+	if got := (Progress{Total: 5}).String(); got != "0/5 (0%) 0.0/s ETA -- | processed 0, skipped 0, failed 0" {
+		t.Errorf("String = %q", got)
+	}
+}
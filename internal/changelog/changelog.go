@@ -0,0 +1,257 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Package changelog renders a Keep-a-Changelog section for a git revision
+// range, joining each commit to the sessions that produced it through the
+// index's Commits field.
+package changelog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/suykerbuyk/vibe-vault/internal/index"
+	"github.com/suykerbuyk/vibe-vault/internal/narrative"
+)
+
+// maxDecisions caps the "motivated by" decisions shown per entry.
+const maxDecisions = 3
+
+// Commit is one commit in the requested range.
+type Commit struct {
+	SHA     string
+	Subject string
+}
+
+// SessionRef links a changelog entry back to a session note.
+type SessionRef struct {
+	SessionID string
+	Title     string
+	NotePath  string
+}
+
+// Entry is one changelog bullet.
+type Entry struct {
+	Subject   string // commit subject with the conventional prefix stripped
+	Intent    string // "feat", "fix", ... ("" when unknown)
+	SHA       string
+	Sessions  []SessionRef
+	Decisions []string
+}
+
+// Section groups entries under a Keep-a-Changelog heading.
+type Section struct {
+	Heading string
+	Entries []Entry
+}
+
+// Release is the rendered unit: one version header with its sections.
+type Release struct {
+	Version  string
+	Date     string
+	Sections []Section
+	Commits  int // commits in range
+	Linked   int // commits joined to at least one session
+}
+
+// Options configures Build.
+type Options struct {
+	Version string // header label; "" renders "Unreleased"
+	Date    string // YYYY-MM-DD; omitted when empty
+	Project string // restrict session joins to one project ("" = any)
+
+	// Intent infers the intent for a session when the commit subject has
+	// no conventional prefix. Typically re-extracts the session narrative
+	// and calls narrative.IntentPrefix. nil disables the fallback.
+	Intent func(index.SessionEntry) string
+}
+
+// sectionOrder maps Keep-a-Changelog headings to the conventional-commit
+// intents they collect, in render order. Intents not listed (and commits
+// with no inferable intent) land in "Changed".
+var sectionOrder = []struct {
+	heading string
+	intents []string
+}{
+	{"Added", []string{"feat"}},
+	{"Changed", []string{"refactor", "perf", "style", "docs", "build", "ci", "chore", "test"}},
+	{"Fixed", []string{"fix"}},
+}
+
+// ParseRange splits "<from>..<to>" into its refs. A missing <to> defaults
+// to HEAD; a missing <from> is an error.
+func ParseRange(s string) (from, to string, err error) {
+	from, to, ok := strings.Cut(s, "..")
+	if !ok || strings.HasPrefix(to, ".") {
+		return "", "", fmt.Errorf("invalid range %q (expected <from-ref>..<to-ref>)", s)
+	}
+	if from == "" {
+		return "", "", fmt.Errorf("invalid range %q: missing <from-ref>", s)
+	}
+	if to == "" {
+		to = "HEAD"
+	}
+	return from, to, nil
+}
+
+// Build joins commits to sessions and groups them into sections. Commits
+// are expected newest first (git log order); entries keep that order
+// within each section.
+func Build(commits []Commit, entries map[string]index.SessionEntry, opts Options) Release {
+	r := Release{Version: opts.Version, Date: opts.Date, Commits: len(commits)}
+
+	bySHA := sessionsByCommit(entries, opts.Project)
+	grouped := make(map[string][]Entry)
+	seenSubject := make(map[string]bool)
+
+	for _, c := range commits {
+		sessions := matchSessions(c.SHA, bySHA)
+		if len(sessions) > 0 {
+			r.Linked++
+		}
+
+		intent := narrative.ConventionalPrefix(c.Subject)
+		if intent == "" && opts.Intent != nil {
+			for _, s := range sessions {
+				if intent = opts.Intent(s); intent != "" {
+					break
+				}
+			}
+		}
+
+		subject := strings.TrimSpace(narrative.StripConventionalPrefix(c.Subject))
+		key := strings.ToLower(subject)
+		if subject == "" || seenSubject[key] {
+			continue
+		}
+		seenSubject[key] = true
+
+		e := Entry{Subject: subject, Intent: intent, SHA: c.SHA}
+		seenDecision := make(map[string]bool)
+		for _, s := range sessions {
+			e.Sessions = append(e.Sessions, SessionRef{SessionID: s.SessionID, Title: s.Title, NotePath: s.NotePath})
+			for _, d := range s.Decisions {
+				if len(e.Decisions) >= maxDecisions || seenDecision[d] {
+					continue
+				}
+				seenDecision[d] = true
+				e.Decisions = append(e.Decisions, d)
+			}
+		}
+		heading := headingFor(intent)
+		grouped[heading] = append(grouped[heading], e)
+	}
+
+	for _, so := range sectionOrder {
+		if es := grouped[so.heading]; len(es) > 0 {
+			r.Sections = append(r.Sections, Section{Heading: so.heading, Entries: es})
+		}
+	}
+	return r
+}
+
+// Render formats a Release as a Keep-a-Changelog markdown section.
+func Render(r Release) string {
+	var b strings.Builder
+
+	version := r.Version
+	if version == "" {
+		version = "Unreleased"
+	}
+	if r.Date != "" && version != "Unreleased" {
+		fmt.Fprintf(&b, "## [%s] - %s\n\n", version, r.Date)
+	} else {
+		fmt.Fprintf(&b, "## [%s]\n\n", version)
+	}
+
+	if len(r.Sections) == 0 {
+		b.WriteString("_No changes in range._\n")
+		return b.String()
+	}
+
+	for _, sec := range r.Sections {
+		fmt.Fprintf(&b, "### %s\n\n", sec.Heading)
+		for _, e := range sec.Entries {
+			fmt.Fprintf(&b, "- %s", e.Subject)
+			if e.SHA != "" {
+				fmt.Fprintf(&b, " (`%s`)", shortSHA(e.SHA))
+			}
+			for _, s := range e.Sessions {
+				fmt.Fprintf(&b, " — [session](%s)", linkTarget(s.NotePath))
+			}
+			b.WriteString("\n")
+			for _, d := range e.Decisions {
+				fmt.Fprintf(&b, "  - Decision: %s\n", d)
+			}
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+func headingFor(intent string) string {
+	for _, so := range sectionOrder {
+		for _, i := range so.intents {
+			if i == intent {
+				return so.heading
+			}
+		}
+	}
+	return "Changed"
+}
+
+// sessionsByCommit indexes entries by every SHA they recorded.
+func sessionsByCommit(entries map[string]index.SessionEntry, project string) map[string][]index.SessionEntry {
+	out := make(map[string][]index.SessionEntry)
+	for _, e := range entries {
+		if project != "" && e.Project != project {
+			continue
+		}
+		for _, sha := range e.Commits {
+			if sha != "" {
+				out[sha] = append(out[sha], e)
+			}
+		}
+	}
+	for sha := range out {
+		sort.Slice(out[sha], func(i, j int) bool {
+			return out[sha][i].CreatedAt.Before(out[sha][j].CreatedAt)
+		})
+	}
+	return out
+}
+
+// matchSessions finds sessions whose recorded SHA is a prefix of (or equal
+// to) the full commit SHA. Sessions record short SHAs from git's commit
+// output, whose length varies with repository size.
+func matchSessions(sha string, bySHA map[string][]index.SessionEntry) []index.SessionEntry {
+	var out []index.SessionEntry
+	seen := make(map[string]bool)
+	for recorded, es := range bySHA {
+		if !strings.HasPrefix(sha, recorded) {
+			continue
+		}
+		for _, e := range es {
+			if !seen[e.SessionID] {
+				seen[e.SessionID] = true
+				out = append(out, e)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// linkTarget URL-escapes spaces so note paths render as valid markdown
+// links.
+func linkTarget(notePath string) string {
+	return strings.ReplaceAll(notePath, " ", "%20")
+}
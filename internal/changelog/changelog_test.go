@@ -0,0 +1,149 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package changelog
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/suykerbuyk/vibe-vault/internal/index"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		in       string
+		from, to string
+		wantErr  bool
+	}{
+		{"v1.0.0..v1.1.0", "v1.0.0", "v1.1.0", false},
+		{"v1.0.0..", "v1.0.0", "HEAD", false},
+		{"..HEAD", "", "", true},
+		{"v1.0.0", "", "", true},
+		{"a...b", "", "", true},
+	}
+	for _, tt := range tests {
+		from, to, err := ParseRange(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRange(%q) err = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if from != tt.from || to != tt.to {
+			t.Errorf("ParseRange(%q) = (%q, %q), want (%q, %q)", tt.in, from, to, tt.from, tt.to)
+		}
+	}
+}
+
+func fixture() ([]Commit, map[string]index.SessionEntry) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	commits := []Commit{
+		{SHA: "ddd4444000000000000000000000000000000000", Subject: "tidy imports"},
+		{SHA: "ccc3333000000000000000000000000000000000", Subject: "fix(auth): reject expired tokens"},
+		{SHA: "bbb2222000000000000000000000000000000000", Subject: "feat: add token refresh"},
+		{SHA: "aaa1111000000000000000000000000000000000", Subject: "refactor: split handler"},
+	}
+	entries := map[string]index.SessionEntry{
+		"s1": {
+			SessionID: "s1", Project: "p", CreatedAt: t0, Title: "Refresh tokens",
+			NotePath:  "Projects/p/sessions/2026-03-01-090000000.md",
+			Commits:   []string{"bbb2222"},
+			Decisions: []string{"Refresh in middleware — keeps handlers simple"},
+		},
+		"s2": {
+			SessionID: "s2", Project: "p", CreatedAt: t0.Add(time.Hour), Title: "Expiry bug",
+			NotePath: "Projects/p/sessions/2026-03-01-100000000.md",
+			Commits:  []string{"ccc3333", "ddd4444"},
+		},
+		"other": {
+			SessionID: "other", Project: "q", CreatedAt: t0,
+			Commits: []string{"aaa1111"},
+		},
+	}
+	return commits, entries
+}
+
+func TestBuild_GroupsByIntent(t *testing.T) {
+	commits, entries := fixture()
+	r := Build(commits, entries, Options{Project: "p", Version: "1.1.0", Date: "2026-03-02"})
+
+	if r.Commits != 4 || r.Linked != 3 {
+		t.Errorf("commits=%d linked=%d, want 4/3", r.Commits, r.Linked)
+	}
+	var headings []string
+	for _, s := range r.Sections {
+		headings = append(headings, s.Heading)
+	}
+	if strings.Join(headings, ",") != "Added,Changed,Fixed" {
+		t.Errorf("headings = %v, want Added,Changed,Fixed", headings)
+	}
+	added := r.Sections[0].Entries
+	if len(added) != 1 || added[0].Subject != "add token refresh" {
+		t.Fatalf("added = %+v", added)
+	}
+	if len(added[0].Sessions) != 1 || added[0].Sessions[0].SessionID != "s1" {
+		t.Errorf("added entry sessions = %+v, want s1", added[0].Sessions)
+	}
+	if len(added[0].Decisions) != 1 {
+		t.Errorf("added entry decisions = %v", added[0].Decisions)
+	}
+}
+
+func TestBuild_IntentFallback(t *testing.T) {
+	commits, entries := fixture()
+	r := Build(commits, entries, Options{
+		Project: "p",
+		Intent: func(e index.SessionEntry) string {
+			if e.SessionID == "s2" {
+				return "fix"
+			}
+			return ""
+		},
+	})
+	for _, s := range r.Sections {
+		if s.Heading != "Fixed" {
+			continue
+		}
+		if len(s.Entries) != 2 {
+			t.Errorf("Fixed entries = %+v, want unprefixed commit inferred as fix", s.Entries)
+		}
+		return
+	}
+	t.Fatal("no Fixed section")
+}
+
+func TestBuild_ProjectFilterSkipsForeignSessions(t *testing.T) {
+	commits, entries := fixture()
+	r := Build(commits, entries, Options{Project: "p"})
+	for _, s := range r.Sections {
+		for _, e := range s.Entries {
+			if e.Subject == "split handler" && len(e.Sessions) != 0 {
+				t.Errorf("commit linked to session from another project: %+v", e.Sessions)
+			}
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	commits, entries := fixture()
+	out := Render(Build(commits, entries, Options{Project: "p", Version: "1.1.0", Date: "2026-03-02"}))
+	for _, want := range []string{
+		"## [1.1.0] - 2026-03-02",
+		"### Added",
+		"- add token refresh (`bbb2222`) — [session](Projects/p/sessions/2026-03-01-090000000.md)",
+		"  - Decision: Refresh in middleware — keeps handlers simple",
+		"### Fixed",
+		"- reject expired tokens (`ccc3333`)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("render missing %q\n%s", want, out)
+		}
+	}
+}
+
+func TestRender_EmptyUnreleased(t *testing.T) {
+	out := Render(Build(nil, nil, Options{}))
+	if out != "## [Unreleased]\n\n_No changes in range._\n" {
+		t.Errorf("got %q", out)
+	}
+}
@@ -5,6 +5,7 @@ package check
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,6 +16,7 @@ import (
 	"github.com/suykerbuyk/vibe-vault/internal/hook"
 	"github.com/suykerbuyk/vibe-vault/internal/meta"
 	"github.com/suykerbuyk/vibe-vault/internal/plugin"
+	"github.com/suykerbuyk/vibe-vault/internal/vaultcrypt"
 )
 
 // Status represents the outcome of a single check.
@@ -233,6 +235,44 @@ func CheckEnrichment(ecfg config.EnrichmentConfig) Result {
 	}
 }
 
+// CheckEncryption warns when [encryption] is in use but its key is not
+// available: sealed archives and notes cannot be read, and new ones that
+// are opted in cannot be written.
+func CheckEncryption(ecfg config.EncryptionConfig) Result {
+	optedIn := ecfg.Archives || ecfg.Notes || len(ecfg.NoteDomains) > 0
+	if !ecfg.Configured() {
+		if optedIn {
+			return Result{Name: "encryption", Status: Warn, Detail: "enabled but no key_file or passphrase_env set"}
+		}
+		return Result{Name: "encryption", Status: Pass, Detail: "off"}
+	}
+	source := "passphrase from $" + ecfg.PassphraseEnv
+	missing := "$" + ecfg.PassphraseEnv + " not set"
+	if ecfg.KeyFile != "" {
+		source = "key file " + config.CompressHome(ecfg.KeyFile)
+		missing = source + " not found"
+	}
+	if _, err := vaultcrypt.Load(ecfg.KeyFile, ecfg.PassphraseEnv); errors.Is(err, vaultcrypt.ErrNoKey) {
+		return Result{Name: "encryption", Status: Warn, Detail: missing + " (encrypted archives and notes unreadable)"}
+	} else if err != nil {
+		return Result{Name: "encryption", Status: Fail, Detail: err.Error()}
+	}
+	var scope []string
+	if ecfg.Archives {
+		scope = append(scope, "archives")
+	}
+	if ecfg.Notes {
+		scope = append(scope, "notes")
+	}
+	for _, d := range ecfg.NoteDomains {
+		scope = append(scope, d+" notes")
+	}
+	if len(scope) == 0 {
+		scope = append(scope, "read only")
+	}
+	return Result{Name: "encryption", Status: Pass, Detail: source + " (" + strings.Join(scope, ", ") + ")"}
+}
+
 // CheckSynthesis checks whether the synthesis agent is configured and has
 // access to an LLM provider (which comes from the enrichment config).
 func CheckSynthesis(scfg config.SynthesisConfig, ecfg config.EnrichmentConfig) Result {
@@ -605,6 +645,7 @@ func Run(cfg config.Config) Report {
 	results = append(results, CheckDomains(cfg.Domains)...)
 	results = append(results, CheckEnrichment(cfg.Enrichment))
 	results = append(results, CheckSynthesis(cfg.Synthesis, cfg.Enrichment))
+	results = append(results, CheckEncryption(cfg.Encryption))
 	results = append(results, CheckHook())
 	results = append(results, CheckMCP())
 
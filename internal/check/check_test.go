@@ -192,6 +192,31 @@ func TestCheckEnrichment_EnabledNoKey(t *testing.T) {
 	}
 }
 
+func TestCheckEncryption(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "vault.key")
+	os.WriteFile(keyFile, []byte(strings.Repeat("ab", 32)), 0o600)
+	t.Setenv("TEST_VV_PASSPHRASE", "")
+
+	cases := []struct {
+		name   string
+		ecfg   config.EncryptionConfig
+		status Status
+		detail string
+	}{
+		{"off", config.EncryptionConfig{}, Pass, "off"},
+		{"opted in without key", config.EncryptionConfig{Archives: true}, Warn, "no key_file"},
+		{"key file missing", config.EncryptionConfig{KeyFile: keyFile + ".gone", Archives: true}, Warn, "not found"},
+		{"passphrase unset", config.EncryptionConfig{PassphraseEnv: "TEST_VV_PASSPHRASE"}, Warn, "$TEST_VV_PASSPHRASE not set"},
+		{"key present", config.EncryptionConfig{KeyFile: keyFile, Archives: true, NoteDomains: []string{"work"}}, Pass, "archives, work notes"},
+	}
+	for _, c := range cases {
+		r := CheckEncryption(c.ecfg)
+		if r.Status != c.status || !strings.Contains(r.Detail, c.detail) {
+			t.Errorf("%s: got %s %q, want %s containing %q", c.name, r.Status, r.Detail, c.status, c.detail)
+		}
+	}
+}
+
 func TestCheckEnrichment_InferredKeyEnv(t *testing.T) {
 	t.Setenv("ANTHROPIC_API_KEY", "sk-ant-123")
 	ecfg := config.EnrichmentConfig{Enabled: true, Provider: "anthropic"}
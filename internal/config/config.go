@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/BurntSushi/toml"
 
+	"github.com/suykerbuyk/vibe-vault/internal/flavor"
 	"github.com/suykerbuyk/vibe-vault/internal/meta"
 )
 
@@ -21,18 +23,44 @@ const DefaultSessionTag = "vv-session"
 type Config struct {
 	VaultPath string `toml:"vault_path"`
 
+	// VaultFlavor selects the note dialect: "obsidian" (default),
+	// "logseq", or "markdown". See internal/flavor.
+	VaultFlavor string `toml:"vault_flavor"`
+
 	Domains    DomainsConfig    `toml:"domains"`
 	Tags       TagsConfig       `toml:"tags"`
 	Enrichment EnrichmentConfig `toml:"enrichment"`
 	Archive    ArchiveConfig    `toml:"archive"`
+	Encryption EncryptionConfig `toml:"encryption"`
 	Friction   FrictionConfig   `toml:"friction"`
 	Pricing    PricingConfig    `toml:"pricing"`
 	History    HistoryConfig    `toml:"history"`
 	MCP        MCPConfig        `toml:"mcp"`
 	Zed        ZedConfig        `toml:"zed"`
+	Daemon     DaemonConfig     `toml:"daemon"`
 	Synthesis  SynthesisConfig  `toml:"synthesis"`
 	Providers  ProvidersConfig  `toml:"providers"`
 	Staging    StagingConfig    `toml:"staging"`
+	Notes      NotesConfig      `toml:"notes"`
+	LLM        LLMConfig        `toml:"llm"`
+}
+
+// LLMConfig controls behavior shared by every LLM call regardless of
+// provider. See internal/llm.WithCache.
+type LLMConfig struct {
+	// Cache enables the content-addressed response cache under
+	// <state>/llm-cache, so re-running enrichment or synthesis over an
+	// unchanged transcript costs nothing.
+	Cache      bool `toml:"cache"`
+	CacheMaxMB int  `toml:"cache_max_mb"` // size limit; oldest entries are evicted first
+}
+
+// NotesConfig controls optional session-note content.
+type NotesConfig struct {
+	// Diagram selects an embedded Mermaid activity diagram: "gantt",
+	// "timeline", or "" (off). Non-empty also adds the activity-mix pie
+	// chart to generated history.md context docs.
+	Diagram string `toml:"diagram"`
 }
 
 // StagingConfig controls the host-local staging dir used by the
@@ -94,9 +122,25 @@ type ZedConfig struct {
 	AutoCapture     bool   `toml:"auto_capture"`     // auto-capture threads via MCP watcher
 }
 
+// DaemonConfig controls `vv daemon`: how often each maintenance job
+// runs, in minutes (0 disables the job), and where status is served.
+type DaemonConfig struct {
+	Socket            string   `toml:"socket"`              // unix socket; default under $XDG_RUNTIME_DIR
+	ArchiveMinutes    int      `toml:"archive_minutes"`     // vv archive
+	IndexMinutes      int      `toml:"index_minutes"`       // vv index
+	SyncMinutes       int      `toml:"sync_minutes"`        // vv vault sync-sessions
+	WorktreeGCMinutes int      `toml:"worktree_gc_minutes"` // vv worktree gc, over WorktreeRepos
+	WorktreeRepos     []string `toml:"worktree_repos"`      // repositories to reap worktrees in
+}
+
 // MCPConfig controls MCP server behavior.
 type MCPConfig struct {
 	DefaultMaxTokens int `toml:"default_max_tokens"`
+
+	// HTTPToken is the bearer token `vv mcp serve --http` requires of
+	// clients and `vv mcp install --url` writes into editor settings.
+	// Empty disables auth, which is only allowed on loopback addresses.
+	HTTPToken string `toml:"http_token"`
 }
 
 // HistoryConfig controls history.md pruning and decay behavior.
@@ -126,10 +170,59 @@ type EnrichmentConfig struct {
 	Model          string `toml:"model"`
 	APIKeyEnv      string `toml:"api_key_env"`
 	BaseURL        string `toml:"base_url"`
+
+	// Fallback lists providers tried in order when the primary fails with
+	// a transient or auth error. Keys and base URLs come from the
+	// [providers.<name>] blocks; enrichment.base_url applies only to the
+	// primary.
+	Fallback []ProviderModel `toml:"fallback"`
+
+	// Chunked selects map-reduce enrichment for long transcripts: "auto"
+	// summarizes each compaction segment separately when the transcript
+	// would otherwise be truncated, "always" does so whenever there is
+	// more than one chunk, and "off" keeps the single truncated call.
+	// ChunkChars caps the transcript text per call — lower it for small
+	// local models — and MaxChunks caps the calls per session.
+	Chunked    string `toml:"chunked"`
+	ChunkChars int    `toml:"chunk_chars"`
+	MaxChunks  int    `toml:"max_chunks"`
+}
+
+// ProviderModel names one link of the enrichment fallback chain.
+type ProviderModel struct {
+	Provider string `toml:"provider"` // openai | anthropic | google | grok
+	Model    string `toml:"model"`
 }
 
 type ArchiveConfig struct {
 	Compress bool `toml:"compress"`
+	Pack     bool `toml:"pack"` // append to monthly pack files instead of one file per session
+
+	// Retention for vv archive prune, in months of session age; 0 keeps
+	// forever. Originals are only deleted once a verified archive exists.
+	KeepMonths          int `toml:"keep_months"`           // archived copies
+	KeepOriginalsMonths int `toml:"keep_originals_months"` // transcripts under ~/.claude/projects
+}
+
+// EncryptionConfig opts archives and session notes into AES-256-GCM
+// encryption at rest. The key comes from KeyFile, else from the
+// passphrase in the PassphraseEnv variable.
+type EncryptionConfig struct {
+	KeyFile       string   `toml:"key_file"`       // 32 bytes: raw, hex, or base64
+	PassphraseEnv string   `toml:"passphrase_env"` // env var holding a passphrase
+	Archives      bool     `toml:"archives"`       // encrypt new transcript archives
+	Notes         bool     `toml:"notes"`          // encrypt session notes; usually set per project
+	NoteDomains   []string `toml:"note_domains"`   // encrypt session notes in these domains
+}
+
+// Configured reports whether a key source is set.
+func (e EncryptionConfig) Configured() bool {
+	return e.KeyFile != "" || e.PassphraseEnv != ""
+}
+
+// EncryptNotes reports whether session notes in domain are encrypted.
+func (e EncryptionConfig) EncryptNotes(domain string) bool {
+	return e.Notes || (domain != "" && slices.Contains(e.NoteDomains, domain))
 }
 
 type FrictionConfig struct {
@@ -171,7 +264,10 @@ func DefaultConfig() Config {
 			// Anthropic → api.anthropic.com, Google → its default). A hardcoded
 			// xAI URL here was leaking into Anthropic/Google constructions when
 			// users switched Provider without also setting base_url.
-			BaseURL: "",
+			BaseURL:    "",
+			Chunked:    "auto",
+			ChunkChars: 24000,
+			MaxChunks:  8,
 		},
 		Archive: ArchiveConfig{
 			Compress: true,
@@ -195,10 +291,20 @@ func DefaultConfig() Config {
 			DebounceMinutes: 5,
 			AutoCapture:     true,
 		},
+		Daemon: DaemonConfig{
+			ArchiveMinutes:    60,
+			IndexMinutes:      360,
+			SyncMinutes:       30,
+			WorktreeGCMinutes: 60,
+		},
 		Synthesis: SynthesisConfig{
 			Enabled:        true,
 			TimeoutSeconds: 60,
 		},
+		LLM: LLMConfig{
+			Cache:      true,
+			CacheMaxMB: 256,
+		},
 	}
 }
 
@@ -222,12 +328,21 @@ func Load() (Config, error) {
 		}
 	}
 
+	if _, err := flavor.Parse(cfg.VaultFlavor); err != nil {
+		return cfg, fmt.Errorf("vault_flavor: %w", err)
+	}
+
 	// Expand ~ in paths
 	cfg.VaultPath = expandHome(cfg.VaultPath)
 	cfg.Domains.Work = expandHome(cfg.Domains.Work)
 	cfg.Domains.Personal = expandHome(cfg.Domains.Personal)
 	cfg.Domains.Opensource = expandHome(cfg.Domains.Opensource)
 	cfg.Zed.DBPath = expandHome(cfg.Zed.DBPath)
+	cfg.Encryption.KeyFile = expandHome(cfg.Encryption.KeyFile)
+	cfg.Daemon.Socket = expandHome(cfg.Daemon.Socket)
+	for i, repo := range cfg.Daemon.WorktreeRepos {
+		cfg.Daemon.WorktreeRepos[i] = expandHome(repo)
+	}
 
 	return cfg, nil
 }
@@ -258,6 +373,17 @@ func expandHome(path string) string {
 	return filepath.Join(home, path[2:])
 }
 
+// Flavor returns the configured vault flavor, defaulting to Obsidian.
+// Load rejects unknown values, so a parse failure here only happens for
+// hand-built configs and also falls back to Obsidian.
+func (c Config) Flavor() flavor.Flavor {
+	f, err := flavor.Parse(c.VaultFlavor)
+	if err != nil {
+		return flavor.Obsidian
+	}
+	return f
+}
+
 // SessionTag returns the configured session tag, defaulting to DefaultSessionTag.
 func (c Config) SessionTag() string {
 	if c.Tags.Session != "" {
@@ -316,9 +442,30 @@ func (c Config) Overlay(projectConfigPath string) Config {
 	if md.IsDefined("enrichment", "base_url") {
 		c.Enrichment.BaseURL = overlay.Enrichment.BaseURL
 	}
+	if md.IsDefined("enrichment", "fallback") {
+		c.Enrichment.Fallback = overlay.Enrichment.Fallback
+	}
+	if md.IsDefined("enrichment", "chunked") {
+		c.Enrichment.Chunked = overlay.Enrichment.Chunked
+	}
+	if md.IsDefined("enrichment", "chunk_chars") {
+		c.Enrichment.ChunkChars = overlay.Enrichment.ChunkChars
+	}
+	if md.IsDefined("enrichment", "max_chunks") {
+		c.Enrichment.MaxChunks = overlay.Enrichment.MaxChunks
+	}
 	if md.IsDefined("archive", "compress") {
 		c.Archive.Compress = overlay.Archive.Compress
 	}
+	if md.IsDefined("archive", "keep_months") {
+		c.Archive.KeepMonths = overlay.Archive.KeepMonths
+	}
+	if md.IsDefined("archive", "keep_originals_months") {
+		c.Archive.KeepOriginalsMonths = overlay.Archive.KeepOriginalsMonths
+	}
+	if md.IsDefined("encryption", "notes") {
+		c.Encryption.Notes = overlay.Encryption.Notes
+	}
 	if md.IsDefined("friction", "alert_threshold") {
 		c.Friction.AlertThreshold = overlay.Friction.AlertThreshold
 	}
@@ -352,6 +499,9 @@ func (c Config) Overlay(projectConfigPath string) Config {
 	if md.IsDefined("zed", "auto_capture") {
 		c.Zed.AutoCapture = overlay.Zed.AutoCapture
 	}
+	if md.IsDefined("notes", "diagram") {
+		c.Notes.Diagram = overlay.Notes.Diagram
+	}
 	if md.IsDefined("staging", "root") {
 		c.Staging.Root = overlay.Staging.Root
 	}
@@ -361,6 +511,12 @@ func (c Config) Overlay(projectConfigPath string) Config {
 	if md.IsDefined("synthesis", "timeout_seconds") {
 		c.Synthesis.TimeoutSeconds = overlay.Synthesis.TimeoutSeconds
 	}
+	if md.IsDefined("llm", "cache") {
+		c.LLM.Cache = overlay.LLM.Cache
+	}
+	if md.IsDefined("llm", "cache_max_mb") {
+		c.LLM.CacheMaxMB = overlay.LLM.CacheMaxMB
+	}
 	// Providers: struct-of-structs, merged field-by-field via md.IsDefined().
 	// Mirrors the Wrap.Tiers approach for the same reason — Overlay() decodes
 	// into a fresh struct, so any provider sub-section the operator omits
@@ -152,6 +152,61 @@ alert_threshold = 60
 	}
 }
 
+func TestLoad_DaemonConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+
+	configDir := filepath.Join(xdg, "vibe-vault")
+	os.MkdirAll(configDir, 0o755)
+	tomlContent := `[daemon]
+sync_minutes = 0
+worktree_repos = ["~/work/app"]
+`
+	os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(tomlContent), 0o644)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	d := cfg.Daemon
+	if d.SyncMinutes != 0 || d.ArchiveMinutes != 60 || d.IndexMinutes != 360 {
+		t.Errorf("intervals = %+v, want sync disabled and other defaults kept", d)
+	}
+	if want := filepath.Join(home, "work", "app"); len(d.WorktreeRepos) != 1 || d.WorktreeRepos[0] != want {
+		t.Errorf("WorktreeRepos = %v, want [%s]", d.WorktreeRepos, want)
+	}
+}
+
+func TestLoad_VaultFlavor(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	t.Setenv("HOME", t.TempDir())
+
+	configDir := filepath.Join(xdg, "vibe-vault")
+	os.MkdirAll(configDir, 0o755)
+	path := filepath.Join(configDir, "config.toml")
+
+	os.WriteFile(path, []byte(`vault_flavor = "logseq"`+"\n"), 0o644)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Flavor() != "logseq" {
+		t.Errorf("Flavor() = %q, want logseq", cfg.Flavor())
+	}
+
+	os.WriteFile(path, []byte(`vault_flavor = "roam"`+"\n"), 0o644)
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "vault_flavor") {
+		t.Errorf("expected vault_flavor error, got %v", err)
+	}
+
+	if got := DefaultConfig().Flavor(); got != "obsidian" {
+		t.Errorf("default Flavor() = %q, want obsidian", got)
+	}
+}
+
 func TestLoad_FrictionConfigAbsent(t *testing.T) {
 	xdg := t.TempDir()
 	t.Setenv("XDG_CONFIG_HOME", xdg)
@@ -298,6 +353,63 @@ default_max_tokens = 8000
 	}
 }
 
+func TestOverlay_NotesDiagram(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.toml")
+	os.WriteFile(cfgPath, []byte(`[notes]
+diagram = "gantt"
+`), 0o644)
+
+	result := DefaultConfig().Overlay(cfgPath)
+	if result.Notes.Diagram != "gantt" {
+		t.Errorf("Notes.Diagram = %q, want gantt", result.Notes.Diagram)
+	}
+}
+
+func TestOverlay_EnrichmentFallback(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.toml")
+	os.WriteFile(cfgPath, []byte(`[enrichment]
+provider = "grok"
+
+[[enrichment.fallback]]
+provider = "anthropic"
+model = "claude-haiku-4-5"
+
+[[enrichment.fallback]]
+provider = "google"
+model = "gemini-2.5-flash"
+`), 0o644)
+
+	result := DefaultConfig().Overlay(cfgPath)
+	want := []ProviderModel{
+		{Provider: "anthropic", Model: "claude-haiku-4-5"},
+		{Provider: "google", Model: "gemini-2.5-flash"},
+	}
+	if len(result.Enrichment.Fallback) != 2 || result.Enrichment.Fallback[0] != want[0] || result.Enrichment.Fallback[1] != want[1] {
+		t.Errorf("Enrichment.Fallback = %+v, want %+v", result.Enrichment.Fallback, want)
+	}
+	if result.Enrichment.Model != DefaultConfig().Enrichment.Model {
+		t.Errorf("Enrichment.Model = %q, should keep the default", result.Enrichment.Model)
+	}
+}
+
+func TestOverlay_EnrichmentChunking(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.toml")
+	os.WriteFile(cfgPath, []byte(`[enrichment]
+chunk_chars = 6000
+`), 0o644)
+
+	result := DefaultConfig().Overlay(cfgPath)
+	if result.Enrichment.ChunkChars != 6000 {
+		t.Errorf("Enrichment.ChunkChars = %d, want 6000", result.Enrichment.ChunkChars)
+	}
+	if result.Enrichment.Chunked != "auto" || result.Enrichment.MaxChunks != 8 {
+		t.Errorf("Chunked/MaxChunks = %q/%d, should keep the defaults", result.Enrichment.Chunked, result.Enrichment.MaxChunks)
+	}
+}
+
 func TestOverlay_MissingFile(t *testing.T) {
 	base := DefaultConfig()
 	result := base.Overlay("/nonexistent/config.toml")
@@ -341,6 +453,25 @@ session = "proj-tag"
 	}
 }
 
+func TestWithProjectOverlay_ArchiveRetention(t *testing.T) {
+	dir := t.TempDir()
+	base := Config{VaultPath: dir, Archive: ArchiveConfig{KeepMonths: 24, KeepOriginalsMonths: 3}}
+
+	projDir := filepath.Join(dir, "Projects", "client", "agentctx")
+	os.MkdirAll(projDir, 0o755)
+	os.WriteFile(filepath.Join(projDir, "config.toml"), []byte(`[archive]
+keep_months = 6
+`), 0o644)
+
+	result := base.WithProjectOverlay("client")
+	if result.Archive.KeepMonths != 6 {
+		t.Errorf("Archive.KeepMonths = %d, want 6", result.Archive.KeepMonths)
+	}
+	if result.Archive.KeepOriginalsMonths != 3 {
+		t.Errorf("Archive.KeepOriginalsMonths = %d, want inherited 3", result.Archive.KeepOriginalsMonths)
+	}
+}
+
 func TestSessionTag(t *testing.T) {
 	// Default
 	cfg := DefaultConfig()
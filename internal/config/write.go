@@ -63,6 +63,10 @@ func WriteDefault(vaultPath string) (string, string, error) {
 
 	content := fmt.Sprintf(`vault_path = %q
 
+# Note dialect: "obsidian", "logseq", or "markdown".
+# Change with: vv init <vault> --flavor <name>
+vault_flavor = "obsidian"
+
 [domains]
 work = "~/work"
 personal = "~/personal"
@@ -79,9 +83,56 @@ provider = "openai"
 model = "grok-3-mini-fast"
 api_key_env = "XAI_API_KEY"
 base_url = "https://api.x.ai/v1"
+# Long sessions: summarize each compaction segment, then combine
+# ("auto" | "always" | "off"). Lower chunk_chars for small local models.
+chunked = "auto"
+chunk_chars = 24000
+max_chunks = 8
+# Fail over, in order, when the primary provider is down or rejects its
+# key. Each needs a key under [providers.<name>].
+# [[enrichment.fallback]]
+# provider = "anthropic"
+# model = "claude-haiku-4-5"
 
 [archive]
 compress = true
+# Append archives to monthly pack files (archive/packs/YYYY-MM.pack)
+# instead of one file per session. Migrate existing ones with vv archive pack.
+pack = false
+# Retention for vv archive prune, in months of session age (0 = forever).
+# Originals in ~/.claude are deleted only once a verified archive exists.
+# Both can be overridden per project.
+keep_months = 0
+keep_originals_months = 0
+
+# Encrypt archives and session notes at rest (AES-256-GCM). Create a key
+# with vv config gen-encryption-key, or name a passphrase variable.
+# [encryption]
+# key_file = "~/.config/vibe-vault/vault.key"
+# passphrase_env = "VV_PASSPHRASE"
+# archives = true
+# note_domains = ["work"]
+
+[daemon]
+# vv daemon runs these jobs every N minutes (0 disables a job) and serves
+# status on a unix socket (vv daemon status). Worktree GC only runs over
+# the listed repositories.
+archive_minutes = 60
+index_minutes = 360
+sync_minutes = 30
+worktree_gc_minutes = 60
+# worktree_repos = ["~/work/myproject"]
+
+# Bearer token for the shared HTTP MCP server (vv mcp serve --http). Clients
+# registered with vv mcp install --url send it. Required unless the server
+# listens on a loopback address.
+# [mcp]
+# http_token = ""
+
+[notes]
+# Embed a Mermaid activity diagram in session notes: "gantt", "timeline",
+# or "" (off). Also adds an activity-mix pie chart to history.md.
+diagram = ""
 
 [synthesis]
 # Runs after each session when [enrichment] has an LLM provider configured.
@@ -89,6 +140,12 @@ compress = true
 enabled = true
 timeout_seconds = 15
 
+[llm]
+# Cache LLM responses by provider, model, and prompt so reprocessing an
+# unchanged session costs nothing. Inspect with: vv llm cache stats
+cache = true
+cache_max_mb = 256
+
 # [providers.anthropic]
 # api_key = "sk-ant-..."
 # # Required by hook enrichment / session synthesis when
@@ -173,6 +230,39 @@ func updateVaultPath(configPath, newVaultPath string) (bool, error) {
 	return true, nil
 }
 
+// SetVaultFlavor writes vault_flavor into an existing config file,
+// replacing the key in place or inserting it after vault_path. Returns
+// true if the file was modified.
+func SetVaultFlavor(configPath, f string) (bool, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return false, err
+	}
+	content := string(data)
+	line := fmt.Sprintf("vault_flavor = %q", f)
+
+	re := regexp.MustCompile(`(?m)^vault_flavor\s*=\s*.*$`)
+	switch {
+	case re.MatchString(content):
+		if re.FindString(content) == line {
+			return false, nil
+		}
+		content = re.ReplaceAllString(content, line)
+	default:
+		vp := regexp.MustCompile(`(?m)^vault_path\s*=\s*.*$`)
+		if loc := vp.FindStringIndex(content); loc != nil {
+			content = content[:loc[1]] + "\n" + line + content[loc[1]:]
+		} else {
+			content = line + "\n" + content
+		}
+	}
+
+	if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // ProjectConfigTemplate returns a fully-commented config.toml for per-project
 // overlay. All settings are commented out; uncommenting any setting overrides
 // the global config for that project only.
@@ -195,9 +285,17 @@ func ProjectConfigTemplate() string {
 # model = "grok-3-mini-fast"
 # api_key_env = "XAI_API_KEY"
 # base_url = "https://api.x.ai/v1"
+# [[enrichment.fallback]]
+# provider = "anthropic"
+# model = "claude-haiku-4-5"
 
 # [archive]
 # compress = true
+# keep_months = 0
+# keep_originals_months = 0
+
+# [encryption]
+# notes = true
 
 # [friction]
 # alert_threshold = 40
@@ -209,6 +307,10 @@ func ProjectConfigTemplate() string {
 # enabled = true
 # timeout_seconds = 15
 
+# [llm]
+# cache = true
+# cache_max_mb = 256
+
 # [providers.anthropic]
 # api_key = "sk-ant-..."
 # # Required by hook enrichment / session synthesis when
@@ -300,3 +300,30 @@ func TestCompressHome(t *testing.T) {
 		}
 	}
 }
+
+func TestSetVaultFlavor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	os.WriteFile(path, []byte("vault_path = \"~/v\"\n\n[domains]\nwork = \"~/w\"\n"), 0o644)
+
+	changed, err := SetVaultFlavor(path, "logseq")
+	if err != nil || !changed {
+		t.Fatalf("insert: changed=%v err=%v", changed, err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "vault_path = \"~/v\"\nvault_flavor = \"logseq\"\n") {
+		t.Errorf("flavor not inserted after vault_path:\n%s", data)
+	}
+
+	changed, err = SetVaultFlavor(path, "logseq")
+	if err != nil || changed {
+		t.Errorf("same value: changed=%v err=%v, want unchanged", changed, err)
+	}
+
+	if _, err := SetVaultFlavor(path, "markdown"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Count(string(data), "vault_flavor") != 1 || !strings.Contains(string(data), `vault_flavor = "markdown"`) {
+		t.Errorf("flavor not replaced in place:\n%s", data)
+	}
+}
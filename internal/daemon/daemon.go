@@ -0,0 +1,404 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Package daemon implements `vv daemon`: one long-running process that
+// starts every enabled SessionSource through a sessionsource.Registry,
+// runs maintenance jobs (archive, index rebuild, staging sync, worktree
+// GC) on their configured intervals, serves status on a local unix
+// socket, and rebuilds both sets from a fresh configuration on SIGHUP.
+//
+// What a job does lives in cmd/vv; this package only owns lifecycle and
+// scheduling. Jobs run one at a time, so two of them never contend for
+// the session index.
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/sessionsource"
+)
+
+// DefaultStartDelay postpones every job's first run after the daemon
+// starts, so logging in is not followed by a burst of maintenance.
+const DefaultStartDelay = time.Minute
+
+// Job is a periodic maintenance task. Run returns a one-line summary of
+// what it did, shown by `vv daemon status`.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context, cfg config.Config) (string, error)
+}
+
+// Options configures Run.
+type Options struct {
+	// Load reads the configuration, at start and on every reload.
+	Load func() (config.Config, error)
+
+	// Sources and Jobs build the supervised set for a configuration.
+	// Sources that report Enabled() false are listed but not started.
+	Sources func(cfg config.Config) []sessionsource.SessionSource
+	Jobs    func(cfg config.Config) []Job
+
+	// Sink receives the sessions sources capture.
+	Sink sessionsource.Sink
+
+	// SocketPath is where status is served; DefaultSocketPath() when
+	// empty. It is fixed for the life of the process.
+	SocketPath string
+
+	// Reload delivers reload requests (SIGHUP in production).
+	Reload <-chan os.Signal
+
+	// StartDelay overrides DefaultStartDelay; tests use a few
+	// milliseconds.
+	StartDelay time.Duration
+
+	Logger *log.Logger
+}
+
+// Status is the daemon's state as served on its socket.
+type Status struct {
+	PID        int            `json:"pid"`
+	Started    time.Time      `json:"started"`
+	Reloads    int            `json:"reloads"`
+	LastReload time.Time      `json:"last_reload"`
+	Sources    []SourceStatus `json:"sources"`
+	Jobs       []JobStatus    `json:"jobs"`
+}
+
+// SourceStatus reports one session source.
+type SourceStatus struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Running bool   `json:"running"`
+	Error   string `json:"error,omitempty"`
+}
+
+// JobStatus reports one maintenance job.
+type JobStatus struct {
+	Name         string    `json:"name"`
+	Every        string    `json:"every"`
+	Running      bool      `json:"running"`
+	Runs         int       `json:"runs"`
+	LastRun      time.Time `json:"last_run"`
+	LastDuration string    `json:"last_duration,omitempty"`
+	LastResult   string    `json:"last_result,omitempty"`
+	LastError    string    `json:"last_error,omitempty"`
+	NextRun      time.Time `json:"next_run"`
+}
+
+type daemon struct {
+	opts    Options
+	logger  *log.Logger
+	started time.Time
+	trigger chan string
+
+	mu         sync.Mutex
+	cfg        config.Config
+	reloads    int
+	lastReload time.Time
+	sources    []SourceStatus
+	jobs       []*jobState
+}
+
+type jobState struct {
+	job Job
+	JobStatus
+}
+
+// Run supervises sources and jobs until ctx is cancelled. It fails if
+// the configuration cannot be loaded or another daemon already serves
+// the socket; a configuration that fails to load on reload is logged
+// and the current one kept.
+func Run(ctx context.Context, opts Options) error {
+	if opts.SocketPath == "" {
+		opts.SocketPath = DefaultSocketPath()
+	}
+	if opts.StartDelay == 0 {
+		opts.StartDelay = DefaultStartDelay
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	cfg, err := opts.Load()
+	if err != nil {
+		return err
+	}
+	ln, err := listen(opts.SocketPath)
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+
+	d := &daemon{
+		opts:    opts,
+		logger:  logger,
+		started: time.Now(),
+		trigger: make(chan string, 8),
+	}
+	go d.serve(ln)
+	logger.Printf("daemon: serving status on %s", opts.SocketPath)
+
+	for {
+		gen := d.start(ctx, cfg)
+		next, ok := d.awaitReload(ctx)
+		gen.stop()
+		if !ok {
+			return nil
+		}
+		cfg = next
+		d.mu.Lock()
+		d.reloads++
+		d.lastReload = time.Now()
+		d.mu.Unlock()
+		logger.Printf("daemon: configuration reloaded")
+	}
+}
+
+// awaitReload blocks until a reload request yields a configuration
+// (true) or ctx is cancelled (false).
+func (d *daemon) awaitReload(ctx context.Context) (config.Config, bool) {
+	for {
+		select {
+		case <-ctx.Done():
+			return config.Config{}, false
+		case <-d.opts.Reload:
+			cfg, err := d.opts.Load()
+			if err != nil {
+				d.logger.Printf("daemon: reload: %v (keeping the current configuration)", err)
+				continue
+			}
+			return cfg, true
+		}
+	}
+}
+
+// generation is one configuration's sources and scheduler.
+type generation struct {
+	cancel   context.CancelFunc
+	registry *sessionsource.Registry
+	wg       sync.WaitGroup
+}
+
+func (g *generation) stop() {
+	g.cancel()
+	g.registry.StopAll()
+	g.wg.Wait()
+}
+
+func (d *daemon) start(ctx context.Context, cfg config.Config) *generation {
+	ctx, cancel := context.WithCancel(ctx)
+	g := &generation{cancel: cancel, registry: sessionsource.NewRegistry()}
+
+	var sources []SourceStatus
+	var enabled []string
+	for _, src := range d.opts.Sources(cfg) {
+		st := SourceStatus{Name: src.Name(), Enabled: src.Enabled()}
+		if err := g.registry.Register(src); err != nil {
+			st.Error = err.Error()
+		} else if st.Enabled {
+			st.Running = true
+			enabled = append(enabled, st.Name)
+		}
+		sources = append(sources, st)
+	}
+
+	d.mu.Lock()
+	d.cfg = cfg
+	d.sources = sources
+	d.jobs = mergeJobs(d.jobs, d.opts.Jobs(cfg), time.Now().Add(d.opts.StartDelay))
+	d.mu.Unlock()
+
+	for _, name := range enabled {
+		g.wg.Add(1)
+		go func() {
+			defer g.wg.Done()
+			// Start blocks for the life of a watch-driven source and
+			// returns at once for a hook-driven one.
+			err := g.registry.Start(ctx, name, d.opts.Sink)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				d.logger.Printf("daemon: source %s: %v", name, err)
+				d.sourceFailed(name, err)
+			}
+		}()
+	}
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		d.schedule(ctx)
+	}()
+	return g
+}
+
+// mergeJobs carries run history across a reload so reloading does not
+// re-run every job; a job new to this configuration is first due at
+// firstRun.
+func mergeJobs(prev []*jobState, jobs []Job, firstRun time.Time) []*jobState {
+	out := make([]*jobState, 0, len(jobs))
+	for _, j := range jobs {
+		st := &jobState{job: j, JobStatus: JobStatus{Name: j.Name, NextRun: firstRun}}
+		for _, p := range prev {
+			if p.Name == j.Name {
+				st.JobStatus = p.JobStatus
+				if !p.LastRun.IsZero() {
+					st.NextRun = p.LastRun.Add(j.Interval)
+				}
+			}
+		}
+		st.Every = j.Interval.String()
+		st.Running = false
+		out = append(out, st)
+	}
+	return out
+}
+
+func (d *daemon) sourceFailed(name string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i := range d.sources {
+		if d.sources[i].Name == name {
+			d.sources[i].Running = false
+			d.sources[i].Error = err.Error()
+		}
+	}
+}
+
+// schedule runs due and triggered jobs, one at a time, until ctx is
+// cancelled.
+func (d *daemon) schedule(ctx context.Context) {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		due, wait := d.nextDue(time.Now())
+		timer.Reset(wait)
+		select {
+		case <-ctx.Done():
+			return
+		case name := <-d.trigger:
+			d.runJob(ctx, name)
+		case <-timer.C:
+			if due != "" {
+				d.runJob(ctx, due)
+			}
+		}
+	}
+}
+
+// nextDue returns the job due soonest and how long until it is due;
+// with no jobs it returns "" and an hour.
+func (d *daemon) nextDue(now time.Time) (string, time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	name, wait := "", time.Hour
+	for _, j := range d.jobs {
+		if w := max(j.NextRun.Sub(now), 0); name == "" || w < wait {
+			name, wait = j.Name, w
+		}
+	}
+	return name, wait
+}
+
+func (d *daemon) runJob(ctx context.Context, name string) {
+	d.mu.Lock()
+	var st *jobState
+	for _, j := range d.jobs {
+		if j.Name == name {
+			st = j
+		}
+	}
+	if st == nil {
+		d.mu.Unlock()
+		return
+	}
+	st.Running = true
+	cfg := d.cfg
+	d.mu.Unlock()
+
+	start := time.Now()
+	result, err := runGuarded(ctx, st.job, cfg)
+	elapsed := time.Since(start)
+	if err != nil {
+		d.logger.Printf("daemon: %s failed after %s: %v", name, elapsed.Round(time.Millisecond), err)
+	} else {
+		d.logger.Printf("daemon: %s: %s", name, result)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	st.Running = false
+	st.Runs++
+	st.LastRun = start
+	st.LastDuration = elapsed.Round(time.Millisecond).String()
+	st.LastResult, st.LastError = result, ""
+	if err != nil {
+		st.LastResult, st.LastError = "", err.Error()
+	}
+	st.NextRun = start.Add(st.job.Interval)
+}
+
+// runGuarded runs a job, turning a panic into an error so one bad run
+// does not take the daemon and its sources down with it.
+func runGuarded(ctx context.Context, job Job, cfg config.Config) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx, cfg)
+}
+
+// enqueue asks the scheduler to run a job now.
+func (d *daemon) enqueue(name string) error {
+	d.mu.Lock()
+	known := false
+	for _, j := range d.jobs {
+		known = known || j.Name == name
+	}
+	d.mu.Unlock()
+	if !known {
+		return fmt.Errorf("unknown or disabled job %q", name)
+	}
+	select {
+	case d.trigger <- name:
+		return nil
+	default:
+		return fmt.Errorf("job queue full; try again shortly")
+	}
+}
+
+func (d *daemon) status() Status {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	st := Status{
+		PID:        os.Getpid(),
+		Started:    d.started,
+		Reloads:    d.reloads,
+		LastReload: d.lastReload,
+		Sources:    append([]SourceStatus(nil), d.sources...),
+	}
+	for _, j := range d.jobs {
+		st.Jobs = append(st.Jobs, j.JobStatus)
+	}
+	return st
+}
+
+func (d *daemon) serve(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go d.handle(conn)
+	}
+}
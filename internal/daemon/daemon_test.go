@@ -0,0 +1,233 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package daemon
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/sessionsource"
+)
+
+// watchSource blocks in Start until its context ends, like zed-acp.
+type watchSource struct {
+	name    string
+	enabled bool
+	starts  *atomic.Int32
+}
+
+func (s watchSource) Name() string  { return s.name }
+func (s watchSource) Enabled() bool { return s.enabled }
+func (s watchSource) Start(ctx context.Context, _ sessionsource.Sink) error {
+	s.starts.Add(1)
+	<-ctx.Done()
+	return ctx.Err()
+}
+func (watchSource) Stop() error { return nil }
+
+type harness struct {
+	sock    string
+	reload  chan os.Signal
+	loads   atomic.Int32
+	starts  atomic.Int32
+	done    chan error
+	jobFail atomic.Bool
+}
+
+func startDaemon(t *testing.T) *harness {
+	t.Helper()
+	h := &harness{
+		sock:   filepath.Join(t.TempDir(), "d.sock"),
+		reload: make(chan os.Signal, 1),
+		done:   make(chan error, 1),
+	}
+	opts := Options{
+		Load: func() (config.Config, error) {
+			h.loads.Add(1)
+			return config.Config{VaultPath: "/vault"}, nil
+		},
+		Sources: func(config.Config) []sessionsource.SessionSource {
+			return []sessionsource.SessionSource{
+				watchSource{name: "watch", enabled: true, starts: &h.starts},
+				watchSource{name: "absent", enabled: false, starts: &h.starts},
+			}
+		},
+		Jobs: func(config.Config) []Job {
+			return []Job{{
+				Name:     "sweep",
+				Interval: time.Hour,
+				Run: func(_ context.Context, cfg config.Config) (string, error) {
+					if h.jobFail.Load() {
+						panic("boom")
+					}
+					return "swept " + cfg.VaultPath, nil
+				},
+			}}
+		},
+		Sink:       sessionsource.CaptureSink{},
+		SocketPath: h.sock,
+		Reload:     h.reload,
+		StartDelay: 20 * time.Millisecond,
+		Logger:     log.New(io.Discard, "", 0),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { h.done <- Run(ctx, opts) }()
+	waitFor(t, "socket", func() bool { return Running(h.sock) })
+	t.Cleanup(func() {
+		cancel()
+		<-h.done
+	})
+	return h
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func jobStatus(t *testing.T, sock string) JobStatus {
+	t.Helper()
+	st, err := GetStatus(sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(st.Jobs) != 1 {
+		t.Fatalf("jobs = %+v", st.Jobs)
+	}
+	return st.Jobs[0]
+}
+
+func TestRun_StartsEnabledSourcesAndRunsJobs(t *testing.T) {
+	h := startDaemon(t)
+
+	waitFor(t, "first job run", func() bool { return jobStatus(t, h.sock).Runs == 1 })
+	st, _ := GetStatus(h.sock)
+	if st.PID != os.Getpid() {
+		t.Errorf("pid = %d", st.PID)
+	}
+	want := []SourceStatus{{Name: "watch", Enabled: true, Running: true}, {Name: "absent"}}
+	if len(st.Sources) != 2 || st.Sources[0] != want[0] || st.Sources[1] != want[1] {
+		t.Errorf("sources = %+v, want %+v", st.Sources, want)
+	}
+	if h.starts.Load() != 1 {
+		t.Errorf("source starts = %d, want 1 (disabled source must not start)", h.starts.Load())
+	}
+	j := st.Jobs[0]
+	if j.LastResult != "swept /vault" || j.Every != "1h0m0s" || !j.NextRun.After(time.Now().Add(50*time.Minute)) {
+		t.Errorf("job status = %+v", j)
+	}
+
+	// A manual trigger runs the job again, ahead of its interval.
+	if err := Trigger(h.sock, "sweep"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "triggered run", func() bool { return jobStatus(t, h.sock).Runs == 2 })
+	if err := Trigger(h.sock, "nope"); err == nil || !strings.Contains(err.Error(), "unknown") {
+		t.Errorf("unknown job: err = %v", err)
+	}
+}
+
+func TestRun_JobPanicIsRecorded(t *testing.T) {
+	h := startDaemon(t)
+	h.jobFail.Store(true)
+	waitFor(t, "failed run", func() bool { return jobStatus(t, h.sock).Runs == 1 })
+	if j := jobStatus(t, h.sock); j.LastError != "panic: boom" {
+		t.Errorf("last error = %q", j.LastError)
+	}
+	if !Running(h.sock) {
+		t.Error("daemon died with its job")
+	}
+}
+
+func TestRun_ReloadRestartsSourcesAndKeepsHistory(t *testing.T) {
+	h := startDaemon(t)
+	waitFor(t, "first job run", func() bool { return jobStatus(t, h.sock).Runs == 1 })
+
+	h.reload <- syscall.SIGHUP
+	waitFor(t, "reload", func() bool {
+		st, err := GetStatus(h.sock)
+		return err == nil && st.Reloads == 1
+	})
+	if h.loads.Load() != 2 {
+		t.Errorf("config loads = %d, want 2", h.loads.Load())
+	}
+	waitFor(t, "source restart", func() bool { return h.starts.Load() == 2 })
+	// History survives, so the reload does not re-run the job.
+	time.Sleep(50 * time.Millisecond)
+	if j := jobStatus(t, h.sock); j.Runs != 1 {
+		t.Errorf("runs after reload = %d, want 1", j.Runs)
+	}
+}
+
+func TestRun_RefusesSecondDaemonAndReplacesStaleSocket(t *testing.T) {
+	h := startDaemon(t)
+	opts := Options{
+		Load:       func() (config.Config, error) { return config.Config{}, nil },
+		SocketPath: h.sock,
+	}
+	if err := Run(context.Background(), opts); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Errorf("second daemon: err = %v", err)
+	}
+
+	stale := filepath.Join(t.TempDir(), "stale.sock")
+	os.WriteFile(stale, nil, 0o600)
+	ln, err := listen(stale)
+	if err != nil {
+		t.Fatalf("listen over stale socket: %v", err)
+	}
+	ln.Close()
+}
+
+func TestGetStatus_NotRunning(t *testing.T) {
+	_, err := GetStatus(filepath.Join(t.TempDir(), "none.sock"))
+	if err == nil || !strings.Contains(err.Error(), "not running") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestUnit(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/home/u/.config")
+	u := Unit("/opt/my tools/vv")
+	for _, want := range []string{
+		`ExecStart="/opt/my tools/vv" daemon`,
+		"ExecReload=/bin/kill -HUP $MAINPID",
+		"EnvironmentFile=-/home/u/.config/vibe-vault/daemon.env",
+		"WantedBy=default.target",
+	} {
+		if !strings.Contains(u, want) {
+			t.Errorf("unit missing %q:\n%s", want, u)
+		}
+	}
+	if got := UnitPath(); got != "/home/u/.config/systemd/user/vibe-vault.service" {
+		t.Errorf("UnitPath = %q", got)
+	}
+}
+
+func TestUnit_EscapesSpecifiers(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/home/u/100%/.config")
+	u := Unit("/opt/50%$off/vv")
+	for _, want := range []string{
+		"ExecStart=/opt/50%%$$off/vv daemon",
+		"EnvironmentFile=-/home/u/100%%/.config/vibe-vault/daemon.env",
+	} {
+		if !strings.Contains(u, want) {
+			t.Errorf("unit missing %q:\n%s", want, u)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The socket protocol is one request line per connection ("status" or
+// "run <job>") answered with one JSON object: a Status, or a reply.
+type reply struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+const socketTimeout = 5 * time.Second
+
+// DefaultSocketPath returns $XDG_RUNTIME_DIR/vibe-vault/daemon.sock, or
+// a per-user directory under the system temp dir when XDG_RUNTIME_DIR is
+// unset.
+func DefaultSocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "vibe-vault", "daemon.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("vibe-vault-%d", os.Getuid()), "daemon.sock")
+}
+
+// listen binds the socket, replacing one left behind by a daemon that
+// did not shut down cleanly but refusing to displace a live one.
+func listen(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if Running(path) {
+		return nil, fmt.Errorf("a vv daemon is already running (%s)", path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("remove stale socket: %w", err)
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+func (d *daemon) handle(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(socketTimeout))
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return
+	}
+	var resp any
+	switch cmd, arg, _ := strings.Cut(strings.TrimSpace(line), " "); cmd {
+	case "status":
+		resp = d.status()
+	case "run":
+		if err := d.enqueue(arg); err != nil {
+			resp = reply{Error: err.Error()}
+		} else {
+			resp = reply{OK: true}
+		}
+	default:
+		resp = reply{Error: fmt.Sprintf("unknown request %q", cmd)}
+	}
+	json.NewEncoder(conn).Encode(resp)
+}
+
+// Running reports whether a daemon is listening on the socket at path.
+func Running(path string) bool {
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// GetStatus asks the daemon at path for its status.
+func GetStatus(path string) (*Status, error) {
+	var st Status
+	if err := query(path, "status", &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// Trigger asks the daemon at path to run a job now. It returns once the
+// job is queued, not when it finishes.
+func Trigger(path, job string) error {
+	var r reply
+	if err := query(path, "run "+job, &r); err != nil {
+		return err
+	}
+	if r.Error != "" {
+		return errors.New(r.Error)
+	}
+	return nil
+}
+
+func query(path, req string, out any) error {
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return fmt.Errorf("vv daemon is not running (%s)", path)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(socketTimeout))
+	if _, err := fmt.Fprintln(conn, req); err != nil {
+		return err
+	}
+	if err := json.NewDecoder(conn).Decode(out); err != nil {
+		return fmt.Errorf("read daemon reply: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suykerbuyk/vibe-vault/internal/atomicfile"
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/meta"
+)
+
+// UnitName is the systemd user unit `vv daemon install` writes.
+const UnitName = "vibe-vault.service"
+
+// UnitPath returns where the user unit lives:
+// $XDG_CONFIG_HOME/systemd/user, else ~/.config/systemd/user.
+func UnitPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, _ := meta.HomeDir()
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "systemd", "user", UnitName)
+}
+
+// Unit renders the systemd user unit that runs `<exe> daemon`. systemd
+// does not pass on the login shell's environment, so provider API keys
+// for enrichment go in the optional daemon.env next to config.toml.
+func Unit(exe string) string {
+	// systemd expands %-specifiers in both settings and $VARS in
+	// ExecStart; double them so a path containing either is taken
+	// literally.
+	exe = strings.NewReplacer("%", "%%", "$", "$$").Replace(exe)
+	if strings.ContainsAny(exe, " \t") {
+		exe = `"` + exe + `"`
+	}
+	envFile := strings.ReplaceAll(filepath.Join(config.ConfigDir(), "daemon.env"), "%", "%%")
+	return fmt.Sprintf(`# Generated by vv daemon install.
+[Unit]
+Description=vibe-vault session capture and maintenance daemon
+Documentation=man:vv-daemon(1)
+
+[Service]
+Type=simple
+# KEY=value lines, e.g. provider API keys for enrichment.
+EnvironmentFile=-%s
+ExecStart=%s daemon
+ExecReload=/bin/kill -HUP $MAINPID
+Restart=on-failure
+RestartSec=30
+
+[Install]
+WantedBy=default.target
+`, envFile, exe)
+}
+
+// Install writes the unit for exe to UnitPath and returns the path.
+func Install(exe string) (string, error) {
+	path := UnitPath()
+	if err := atomicfile.Write("", path, []byte(Unit(exe))); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Uninstall removes the unit, reporting whether one was installed.
+func Uninstall() (string, bool, error) {
+	path := UnitPath()
+	err := os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return path, false, nil
+	}
+	return path, err == nil, err
+}
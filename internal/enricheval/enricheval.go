@@ -0,0 +1,386 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Package enricheval scores the enrichment and synthesis prompts against
+// golden session transcripts. Each fixture case pairs a transcript with
+// the outcome a good summary should capture; LLM calls go through a
+// cassette so a recorded run replays offline and deterministically, and
+// reports from two prompt revisions can be diffed.
+package enricheval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/enrichment"
+	"github.com/suykerbuyk/vibe-vault/internal/llm"
+	"github.com/suykerbuyk/vibe-vault/internal/narrative"
+	"github.com/suykerbuyk/vibe-vault/internal/noteparse"
+	"github.com/suykerbuyk/vibe-vault/internal/session"
+	"github.com/suykerbuyk/vibe-vault/internal/synthesis"
+	"github.com/suykerbuyk/vibe-vault/internal/transcript"
+)
+
+// Files that make up a fixture case directory.
+const (
+	TranscriptFile = "transcript.jsonl" // required
+	ExpectedFile   = "expected.json"    // required
+	CassetteFile   = "cassette.json"    // written by record mode
+	KnowledgeFile  = "knowledge.md"     // optional synthesis context
+	ResumeFile     = "resume.md"        // optional synthesis context
+)
+
+const defaultMaxSentences = 3
+
+// Expected is a case's expected.json. Every field is optional; a case is
+// scored only on what it declares.
+type Expected struct {
+	Tag       string       `json:"tag"`
+	Decisions []string     `json:"decisions"` // keyword phrases; see matchPhrase
+	Summary   SummaryRules `json:"summary"`
+	Learnings []string     `json:"learnings"` // keyword phrases; enables synthesis
+}
+
+// SummaryRules bound the enrichment summary. The summary must always be
+// non-empty and at most MaxSentences long.
+type SummaryRules struct {
+	MaxSentences int      `json:"max_sentences"` // default 3, per the prompt
+	MaxChars     int      `json:"max_chars"`     // 0 = unbounded
+	MustMention  []string `json:"must_mention"`  // keyword phrases
+}
+
+// Options configures Run.
+type Options struct {
+	Mode     string       // llm.CassetteReplay or llm.CassetteRecord
+	Provider llm.Provider // live provider; required in record mode
+	Config   config.Config
+}
+
+// CaseResult is one fixture case's outcome. Recall fields are nil when
+// the case declares nothing to recall.
+type CaseResult struct {
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+
+	Tag         string `json:"tag"`
+	ExpectedTag string `json:"expected_tag,omitempty"`
+
+	Summary       string   `json:"summary"`
+	SummaryIssues []string `json:"summary_issues,omitempty"`
+
+	Decisions       []string `json:"decisions,omitempty"`
+	DecisionRecall  *float64 `json:"decision_recall,omitempty"`
+	MissedDecisions []string `json:"missed_decisions,omitempty"`
+
+	Learnings       []string `json:"learnings,omitempty"`
+	LearningRecall  *float64 `json:"learning_recall,omitempty"`
+	MissedLearnings []string `json:"missed_learnings,omitempty"`
+
+	Calls    int `json:"calls"`
+	Replayed int `json:"replayed"`
+}
+
+// Scored reports whether the case ran far enough to be scored.
+func (c CaseResult) Scored() bool { return c.Error == "" }
+
+// TagMatch reports whether the case declares a tag and got it.
+func (c CaseResult) TagMatch() bool { return c.ExpectedTag != "" && c.Tag == c.ExpectedTag }
+
+// Totals aggregates scored cases. Each rate is over the cases that
+// declare the corresponding expectation.
+type Totals struct {
+	Cases          int     `json:"cases"`
+	Errors         int     `json:"errors"`
+	TagAccuracy    float64 `json:"tag_accuracy"`
+	DecisionRecall float64 `json:"decision_recall"`
+	SummaryPass    float64 `json:"summary_pass"`
+	LearningRecall float64 `json:"learning_recall"`
+}
+
+// Report is the result of one evaluation run.
+type Report struct {
+	EnrichmentPrompt string       `json:"enrichment_prompt"`
+	SynthesisPrompt  string       `json:"synthesis_prompt"`
+	Cases            []CaseResult `json:"cases"`
+	Totals           Totals       `json:"totals"`
+}
+
+// Run evaluates every case directory under dir — each subdirectory
+// holding a transcript.jsonl — in name order. A failing case is recorded
+// in its CaseResult and does not stop the run. Cassettes are saved after
+// each case, so an interrupted recording keeps what it captured.
+func Run(ctx context.Context, dir string, opts Options) (*Report, error) {
+	cases, err := findCases(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("no fixture cases in %s (want <case>/%s)", dir, TranscriptFile)
+	}
+
+	report := &Report{
+		EnrichmentPrompt: enrichment.PromptVersion(),
+		SynthesisPrompt:  synthesis.PromptVersion(),
+	}
+	for _, name := range cases {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		report.Cases = append(report.Cases, runCase(ctx, filepath.Join(dir, name), name, opts))
+	}
+	report.Totals = total(report.Cases)
+	return report, nil
+}
+
+func findCases(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var cases []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, e.Name(), TranscriptFile)); err == nil {
+			cases = append(cases, e.Name())
+		}
+	}
+	sort.Strings(cases)
+	return cases, nil
+}
+
+func runCase(ctx context.Context, caseDir, name string, opts Options) CaseResult {
+	res := CaseResult{Name: name}
+	cas, err := llm.OpenCassette(filepath.Join(caseDir, CassetteFile), opts.Mode, opts.Provider)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	err = evaluate(ctx, caseDir, cas, opts.Config, &res)
+	hits, misses := cas.Stats()
+	res.Calls, res.Replayed = hits+misses, hits
+	if saveErr := cas.Save(); saveErr != nil && err == nil {
+		err = fmt.Errorf("save cassette: %w", saveErr)
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res
+}
+
+func evaluate(ctx context.Context, caseDir string, provider llm.Provider, cfg config.Config, res *CaseResult) error {
+	var exp Expected
+	data, err := os.ReadFile(filepath.Join(caseDir, ExpectedFile))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &exp); err != nil {
+		return fmt.Errorf("parse %s: %w", ExpectedFile, err)
+	}
+
+	t, err := transcript.ParseFile(filepath.Join(caseDir, TranscriptFile))
+	if err != nil {
+		return fmt.Errorf("parse transcript: %w", err)
+	}
+	narr := narrative.Extract(t, t.Stats.CWD)
+	input := session.EnrichmentInput(t, narr, cfg)
+
+	enriched, err := enrichment.Generate(ctx, provider, input)
+	if err != nil {
+		return err
+	}
+	res.Tag = enriched.Tag
+	res.ExpectedTag = exp.Tag
+	res.Summary = enriched.Summary
+	res.SummaryIssues = checkSummary(enriched.Summary, exp.Summary)
+	res.Decisions = enriched.Decisions
+	if len(exp.Decisions) > 0 {
+		recall, missed := recallPhrases(exp.Decisions, enriched.Decisions)
+		res.DecisionRecall, res.MissedDecisions = &recall, missed
+	}
+
+	if len(exp.Learnings) == 0 {
+		return nil
+	}
+	knowledge, err := readOptional(filepath.Join(caseDir, KnowledgeFile))
+	if err != nil {
+		return err
+	}
+	resume, err := readOptional(filepath.Join(caseDir, ResumeFile))
+	if err != nil {
+		return err
+	}
+	note := &noteparse.Note{
+		Summary:      enriched.Summary,
+		Tag:          enriched.Tag,
+		Decisions:    enriched.Decisions,
+		OpenThreads:  enriched.OpenThreads,
+		FilesChanged: input.FilesChanged,
+	}
+	if !t.Stats.StartTime.IsZero() {
+		note.Date = t.Stats.StartTime.Format("2006-01-02")
+	}
+	synth, err := synthesis.Synthesize(ctx, provider, &synthesis.Input{
+		SessionNote: note,
+		KnowledgeMD: knowledge,
+		ResumeMD:    resume,
+	})
+	if err != nil {
+		return fmt.Errorf("synthesis: %w", err)
+	}
+	for _, l := range synth.Learnings {
+		res.Learnings = append(res.Learnings, l.Entry)
+	}
+	recall, missed := recallPhrases(exp.Learnings, res.Learnings)
+	res.LearningRecall, res.MissedLearnings = &recall, missed
+	return nil
+}
+
+func readOptional(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	return string(data), err
+}
+
+// checkSummary applies the summary length rules and returns one message
+// per broken rule.
+func checkSummary(summary string, rules SummaryRules) []string {
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		return []string{"empty summary"}
+	}
+	var issues []string
+	limit := rules.MaxSentences
+	if limit <= 0 {
+		limit = defaultMaxSentences
+	}
+	if n := countSentences(summary); n > limit {
+		issues = append(issues, fmt.Sprintf("%d sentences, want at most %d", n, limit))
+	}
+	if rules.MaxChars > 0 && len(summary) > rules.MaxChars {
+		issues = append(issues, fmt.Sprintf("%d chars, want at most %d", len(summary), rules.MaxChars))
+	}
+	for _, p := range rules.MustMention {
+		if !matchPhrase(p, summary) {
+			issues = append(issues, fmt.Sprintf("does not mention %q", p))
+		}
+	}
+	return issues
+}
+
+// countSentences counts sentence terminators followed by whitespace or
+// the end of text, so file names like "main.go" do not count.
+func countSentences(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] != '.' && s[i] != '!' && s[i] != '?' {
+			continue
+		}
+		if i == len(s)-1 || s[i+1] == ' ' || s[i+1] == '\n' {
+			n++
+		}
+	}
+	if n == 0 {
+		return 1
+	}
+	return n
+}
+
+// recallPhrases returns the fraction of expected phrases matched by some
+// item, and the phrases that were not.
+func recallPhrases(expected, items []string) (float64, []string) {
+	var missed []string
+	for _, p := range expected {
+		found := false
+		for _, it := range items {
+			if matchPhrase(p, it) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missed = append(missed, p)
+		}
+	}
+	return float64(len(expected)-len(missed)) / float64(len(expected)), missed
+}
+
+// matchPhrase reports whether every word of phrase occurs in text,
+// case-insensitively and as a substring, so "backoff retr" matches
+// "Retried with exponential backoff".
+func matchPhrase(phrase, text string) bool {
+	text = strings.ToLower(text)
+	words := strings.Fields(strings.ToLower(phrase))
+	for _, w := range words {
+		if !strings.Contains(text, w) {
+			return false
+		}
+	}
+	return len(words) > 0
+}
+
+func total(cases []CaseResult) Totals {
+	t := Totals{Cases: len(cases)}
+	var tagN, tagOK, decN, sumN, sumOK, learnN int
+	var decSum, learnSum float64
+	for _, c := range cases {
+		if !c.Scored() {
+			t.Errors++
+			continue
+		}
+		if c.ExpectedTag != "" {
+			tagN++
+			if c.TagMatch() {
+				tagOK++
+			}
+		}
+		if c.DecisionRecall != nil {
+			decN++
+			decSum += *c.DecisionRecall
+		}
+		sumN++
+		if len(c.SummaryIssues) == 0 {
+			sumOK++
+		}
+		if c.LearningRecall != nil {
+			learnN++
+			learnSum += *c.LearningRecall
+		}
+	}
+	t.TagAccuracy = ratio(float64(tagOK), tagN)
+	t.DecisionRecall = ratio(decSum, decN)
+	t.SummaryPass = ratio(float64(sumOK), sumN)
+	t.LearningRecall = ratio(learnSum, learnN)
+	return t
+}
+
+func ratio(sum float64, n int) float64 {
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+// LoadReport reads a report written with --json or --out.
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parse report %s: %w", path, err)
+	}
+	return &r, nil
+}
@@ -0,0 +1,207 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package enricheval
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/suykerbuyk/vibe-vault/internal/llm"
+)
+
+// schemaProvider answers by reply schema, standing in for a live model.
+type schemaProvider struct {
+	replies map[string]string
+	calls   int
+}
+
+func (p *schemaProvider) Name() string { return "fake" }
+
+func (p *schemaProvider) ChatCompletion(_ context.Context, req llm.Request) (*llm.Response, error) {
+	p.calls++
+	reply, ok := p.replies[req.Schema.Title]
+	if !ok {
+		return nil, errors.New("unexpected request")
+	}
+	return &llm.Response{Content: reply, Usage: llm.UsageStats{InputTokens: 100, OutputTokens: 20}}, nil
+}
+
+var liveReplies = map[string]string{
+	"session_enrichment": `{"summary": "Added JWT authentication to the API. The tests passed and the change was committed.",
+		"decisions": ["Used JWT bearer tokens — stateless auth fits the API"],
+		"open_threads": ["Add token refresh"],
+		"tag": "implementation"}`,
+	"session_synthesis": `{"learnings": [{"section": "Decisions", "entry": "Chose stateless JWT auth for the API"}],
+		"stale_entries": [], "resume_update": null, "task_updates": [], "reasoning": "new auth"}`,
+}
+
+// writeCase builds a fixture case from the shared narrative test session.
+func writeCase(t *testing.T, dir, name, expected string) {
+	t.Helper()
+	transcript, err := os.ReadFile(filepath.Join("..", "..", "test", "testdata", "narrative-session.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	caseDir := filepath.Join(dir, name)
+	if err := os.MkdirAll(caseDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for file, data := range map[string][]byte{
+		TranscriptFile: transcript,
+		ExpectedFile:   []byte(expected),
+	} {
+		if err := os.WriteFile(filepath.Join(caseDir, file), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRun_RecordThenReplay(t *testing.T) {
+	dir := t.TempDir()
+	writeCase(t, dir, "jwt-auth", `{"tag": "implementation", "decisions": ["jwt stateless", "refresh tokens"],
+		"summary": {"must_mention": ["JWT"]}, "learnings": ["stateless jwt"]}`)
+	writeCase(t, dir, "jwt-wrong-tag", `{"tag": "debugging"}`)
+
+	live := &schemaProvider{replies: liveReplies}
+	recorded, err := Run(context.Background(), dir, Options{Mode: llm.CassetteRecord, Provider: live})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Both cases send the same enrichment prompt, but each has its own
+	// cassette; only jwt-auth expects learnings and runs synthesis.
+	if live.calls != 3 {
+		t.Errorf("live calls = %d, want 3", live.calls)
+	}
+
+	replayed, err := Run(context.Background(), dir, Options{Mode: llm.CassetteReplay})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range replayed.Cases {
+		if c := replayed.Cases[i]; c.Replayed != c.Calls {
+			t.Errorf("%s: replayed %d of %d calls", c.Name, c.Replayed, c.Calls)
+		}
+		replayed.Cases[i].Replayed = recorded.Cases[i].Replayed
+	}
+	if !reflect.DeepEqual(recorded, replayed) {
+		t.Errorf("replay differs from recording:\n%+v\n%+v", recorded, replayed)
+	}
+
+	auth := replayed.Cases[0]
+	if auth.Name != "jwt-auth" || !auth.Scored() {
+		t.Fatalf("first case = %+v", auth)
+	}
+	if !auth.TagMatch() || len(auth.SummaryIssues) != 0 {
+		t.Errorf("tag/summary: %+v", auth)
+	}
+	if *auth.DecisionRecall != 0.5 || !reflect.DeepEqual(auth.MissedDecisions, []string{"refresh tokens"}) {
+		t.Errorf("decision recall = %v, missed %v", *auth.DecisionRecall, auth.MissedDecisions)
+	}
+	if *auth.LearningRecall != 1 {
+		t.Errorf("learning recall = %v", *auth.LearningRecall)
+	}
+
+	tot := replayed.Totals
+	if tot.Cases != 2 || tot.Errors != 0 || tot.TagAccuracy != 0.5 || tot.DecisionRecall != 0.5 || tot.SummaryPass != 1 {
+		t.Errorf("totals = %+v", tot)
+	}
+}
+
+func TestRun_ReplayMissFailsCase(t *testing.T) {
+	dir := t.TempDir()
+	writeCase(t, dir, "unrecorded", `{"tag": "implementation"}`)
+
+	report, err := Run(context.Background(), dir, Options{Mode: llm.CassetteReplay})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := report.Cases[0]
+	if c.Scored() || !strings.Contains(c.Error, llm.ErrCassetteMiss.Error()) {
+		t.Errorf("case = %+v, want a cassette miss", c)
+	}
+	if report.Totals.Errors != 1 {
+		t.Errorf("errors = %d, want 1", report.Totals.Errors)
+	}
+}
+
+func TestRun_NoCases(t *testing.T) {
+	if _, err := Run(context.Background(), t.TempDir(), Options{Mode: llm.CassetteReplay}); err == nil {
+		t.Fatal("want error for a directory without cases")
+	}
+}
+
+func TestCheckSummary(t *testing.T) {
+	tests := []struct {
+		summary string
+		rules   SummaryRules
+		want    int
+	}{
+		{"Fixed the flaky test in main.go. Added a retry.", SummaryRules{}, 0},
+		{"", SummaryRules{}, 1},
+		{"One. Two. Three. Four.", SummaryRules{}, 1},
+		{"One. Two. Three. Four.", SummaryRules{MaxSentences: 4}, 0},
+		{"A long summary sentence.", SummaryRules{MaxChars: 10}, 1},
+		{"Fixed the timeout.", SummaryRules{MustMention: []string{"timeout", "retry"}}, 1},
+	}
+	for _, tt := range tests {
+		if got := checkSummary(tt.summary, tt.rules); len(got) != tt.want {
+			t.Errorf("checkSummary(%q, %+v) = %v, want %d issues", tt.summary, tt.rules, got, tt.want)
+		}
+	}
+}
+
+func TestMatchPhrase(t *testing.T) {
+	if !matchPhrase("backoff retr", "Retried with exponential BACKOFF") {
+		t.Error("want match on every word, case-insensitively")
+	}
+	if matchPhrase("retry breaker", "Retried with exponential backoff") {
+		t.Error("want no match when a word is absent")
+	}
+	if matchPhrase("  ", "anything") {
+		t.Error("an empty phrase must not match")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	one, half := 1.0, 0.5
+	base := &Report{
+		EnrichmentPrompt: "aaa", SynthesisPrompt: "sss",
+		Cases: []CaseResult{
+			{Name: "a", Tag: "debugging", ExpectedTag: "implementation", DecisionRecall: &half},
+			{Name: "gone"},
+		},
+		Totals: Totals{Cases: 2, TagAccuracy: 0, DecisionRecall: 0.5, SummaryPass: 1},
+	}
+	cur := &Report{
+		EnrichmentPrompt: "bbb", SynthesisPrompt: "sss",
+		Cases: []CaseResult{
+			{Name: "a", Tag: "implementation", ExpectedTag: "implementation", DecisionRecall: &one,
+				SummaryIssues: []string{"4 sentences, want at most 3"}},
+			{Name: "new"},
+		},
+		Totals: Totals{Cases: 2, TagAccuracy: 1, DecisionRecall: 1, SummaryPass: 0.5},
+	}
+	want := []string{
+		"enrichment prompt aaa -> bbb",
+		"tag accuracy 0.00 -> 1.00 (+1.00)",
+		"decision recall 0.50 -> 1.00 (+0.50)",
+		"summary pass 1.00 -> 0.50 (-0.50)",
+		"a: tag debugging -> implementation (want implementation)",
+		"a: summary now fails: 4 sentences, want at most 3",
+		"a: decision recall 0.50 -> 1.00",
+		"new: added",
+		"gone: removed",
+	}
+	if got := Diff(base, cur); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if got := Diff(cur, cur); got != nil {
+		t.Errorf("Diff of identical reports = %v, want nil", got)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package enricheval
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Render formats r for the terminal: one block per case, then totals.
+func Render(r *Report) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Prompts: enrichment %s, synthesis %s\n", r.EnrichmentPrompt, r.SynthesisPrompt)
+	for _, c := range r.Cases {
+		fmt.Fprintf(&b, "\n%s\n", c.Name)
+		if !c.Scored() {
+			fmt.Fprintf(&b, "  error      %s\n", c.Error)
+			continue
+		}
+		tag := c.Tag
+		if c.ExpectedTag != "" {
+			tag += fmt.Sprintf(" (want %s) %s", c.ExpectedTag, passFail(c.TagMatch()))
+		}
+		fmt.Fprintf(&b, "  tag        %s\n", tag)
+		if len(c.SummaryIssues) == 0 {
+			b.WriteString("  summary    ok\n")
+		} else {
+			fmt.Fprintf(&b, "  summary    %s\n", strings.Join(c.SummaryIssues, "; "))
+		}
+		if c.DecisionRecall != nil {
+			fmt.Fprintf(&b, "  decisions  recall %.2f%s\n", *c.DecisionRecall, missedSuffix(c.MissedDecisions))
+		}
+		if c.LearningRecall != nil {
+			fmt.Fprintf(&b, "  learnings  recall %.2f%s\n", *c.LearningRecall, missedSuffix(c.MissedLearnings))
+		}
+		fmt.Fprintf(&b, "  calls      %d (%d replayed)\n", c.Calls, c.Replayed)
+	}
+
+	t := r.Totals
+	fmt.Fprintf(&b, "\n%d cases, %d errors\n", t.Cases, t.Errors)
+	fmt.Fprintf(&b, "  tag accuracy     %.2f\n", t.TagAccuracy)
+	fmt.Fprintf(&b, "  decision recall  %.2f\n", t.DecisionRecall)
+	fmt.Fprintf(&b, "  summary pass     %.2f\n", t.SummaryPass)
+	fmt.Fprintf(&b, "  learning recall  %.2f\n", t.LearningRecall)
+	return b.String()
+}
+
+func passFail(ok bool) string {
+	if ok {
+		return "ok"
+	}
+	return "MISMATCH"
+}
+
+func missedSuffix(missed []string) string {
+	if len(missed) == 0 {
+		return ""
+	}
+	return ", missed " + quoteAll(missed)
+}
+
+func quoteAll(items []string) string {
+	q := make([]string, len(items))
+	for i, s := range items {
+		q[i] = fmt.Sprintf("%q", s)
+	}
+	return strings.Join(q, ", ")
+}
+
+// Diff compares cur against a baseline report from an earlier prompt
+// revision: prompt versions, totals that moved, and per-case changes in
+// tag, summary rules, and recall. Cases present in only one report are
+// listed as added or removed. It returns nil when nothing changed.
+func Diff(base, cur *Report) []string {
+	var lines []string
+	addf := func(format string, args ...any) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}
+
+	if base.EnrichmentPrompt != cur.EnrichmentPrompt {
+		addf("enrichment prompt %s -> %s", base.EnrichmentPrompt, cur.EnrichmentPrompt)
+	}
+	if base.SynthesisPrompt != cur.SynthesisPrompt {
+		addf("synthesis prompt %s -> %s", base.SynthesisPrompt, cur.SynthesisPrompt)
+	}
+	for _, m := range []struct {
+		name     string
+		old, new float64
+	}{
+		{"tag accuracy", base.Totals.TagAccuracy, cur.Totals.TagAccuracy},
+		{"decision recall", base.Totals.DecisionRecall, cur.Totals.DecisionRecall},
+		{"summary pass", base.Totals.SummaryPass, cur.Totals.SummaryPass},
+		{"learning recall", base.Totals.LearningRecall, cur.Totals.LearningRecall},
+	} {
+		if fmt.Sprintf("%.2f", m.old) != fmt.Sprintf("%.2f", m.new) {
+			addf("%s %.2f -> %.2f (%+.2f)", m.name, m.old, m.new, m.new-m.old)
+		}
+	}
+	if base.Totals.Errors != cur.Totals.Errors {
+		addf("errors %d -> %d", base.Totals.Errors, cur.Totals.Errors)
+	}
+
+	prev := make(map[string]CaseResult, len(base.Cases))
+	for _, c := range base.Cases {
+		prev[c.Name] = c
+	}
+	seen := make(map[string]bool, len(cur.Cases))
+	for _, c := range cur.Cases {
+		seen[c.Name] = true
+		old, ok := prev[c.Name]
+		if !ok {
+			addf("%s: added", c.Name)
+			continue
+		}
+		for _, change := range caseChanges(old, c) {
+			addf("%s: %s", c.Name, change)
+		}
+	}
+	for _, c := range base.Cases {
+		if !seen[c.Name] {
+			addf("%s: removed", c.Name)
+		}
+	}
+	return lines
+}
+
+func caseChanges(old, cur CaseResult) []string {
+	var out []string
+	if old.Error != cur.Error {
+		switch {
+		case cur.Error == "":
+			out = append(out, "now succeeds")
+		case old.Error == "":
+			return append(out, "now fails: "+cur.Error)
+		default:
+			return append(out, "error changed: "+cur.Error)
+		}
+	}
+	if !cur.Scored() {
+		return out
+	}
+	if old.Tag != cur.Tag {
+		change := fmt.Sprintf("tag %s -> %s", old.Tag, cur.Tag)
+		if cur.ExpectedTag != "" {
+			change += fmt.Sprintf(" (want %s)", cur.ExpectedTag)
+		}
+		out = append(out, change)
+	}
+	if oldOK, curOK := len(old.SummaryIssues) == 0, len(cur.SummaryIssues) == 0; oldOK != curOK {
+		if curOK {
+			out = append(out, "summary now passes")
+		} else {
+			out = append(out, "summary now fails: "+strings.Join(cur.SummaryIssues, "; "))
+		}
+	}
+	if change := recallChange("decision", old.DecisionRecall, cur.DecisionRecall); change != "" {
+		out = append(out, change)
+	}
+	if change := recallChange("learning", old.LearningRecall, cur.LearningRecall); change != "" {
+		out = append(out, change)
+	}
+	return out
+}
+
+func recallChange(name string, old, cur *float64) string {
+	if cur == nil {
+		return ""
+	}
+	if old == nil {
+		return fmt.Sprintf("%s recall %.2f", name, *cur)
+	}
+	if fmt.Sprintf("%.2f", *old) == fmt.Sprintf("%.2f", *cur) {
+		return ""
+	}
+	return fmt.Sprintf("%s recall %.2f -> %.2f", name, *old, *cur)
+}
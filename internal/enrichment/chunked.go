@@ -0,0 +1,276 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package enrichment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/suykerbuyk/vibe-vault/internal/llm"
+)
+
+// Chunking modes for ChunkOptions.Mode.
+const (
+	ChunkAuto   = "auto"   // map-reduce only when the transcript would be truncated
+	ChunkAlways = "always" // map-reduce whenever the transcript spans more than one chunk
+	ChunkOff    = "off"    // always a single, truncated call
+)
+
+const (
+	defaultChunkChars  = maxUserChars + maxAssistantChars
+	defaultMaxChunks   = 8
+	maxChunkActivities = 10
+)
+
+// Chunk is one compaction-bounded stretch of a session transcript.
+type Chunk struct {
+	UserRequest   string // first meaningful user message in the stretch
+	UserText      string
+	AssistantText string
+	Activities    []string
+}
+
+// ChunkOptions configures map-reduce enrichment. Zero values take the
+// defaults: auto mode, a chunk budget equal to the single-call budget,
+// and at most 8 map calls.
+type ChunkOptions struct {
+	Mode       string
+	ChunkChars int // transcript characters per map call
+	MaxChunks  int // cap on map calls; chunks are merged to fit
+}
+
+func (o ChunkOptions) chunkChars() int {
+	if o.ChunkChars > 0 {
+		return o.ChunkChars
+	}
+	return defaultChunkChars
+}
+
+func (o ChunkOptions) maxChunks() int {
+	if o.MaxChunks > 0 {
+		return o.MaxChunks
+	}
+	return defaultMaxChunks
+}
+
+// Calls reports how many LLM calls Generate will make for input: one for
+// a single call, or one per planned chunk plus the reduce call.
+func Calls(input PromptInput) int {
+	if plan := planChunks(input); len(plan) > 1 {
+		return len(plan) + 1
+	}
+	return 1
+}
+
+// planChunks returns the chunks to map over, or nil when input should go
+// through the single-call path. Oversized chunks are split at line
+// boundaries, small neighbours are packed together up to the budget, and
+// past MaxChunks adjacent chunks are merged with each member trimmed to
+// its share, so every stretch of the session stays represented.
+func planChunks(input PromptInput) []Chunk {
+	opts := input.Chunking
+	switch opts.Mode {
+	case ChunkOff:
+		return nil
+	case ChunkAlways:
+	default:
+		if len(input.UserText) <= maxUserChars && len(input.AssistantText) <= maxAssistantChars {
+			return nil
+		}
+	}
+	budget := opts.chunkChars()
+
+	var split []Chunk
+	for _, c := range input.Chunks {
+		split = append(split, splitChunk(c, budget)...)
+	}
+
+	var packed []Chunk
+	for _, c := range split {
+		if n := len(packed); n > 0 && chunkSize(packed[n-1])+chunkSize(c) <= budget {
+			packed[n-1] = mergeChunks([]Chunk{packed[n-1], c}, budget)
+			continue
+		}
+		packed = append(packed, c)
+	}
+
+	if limit := opts.maxChunks(); len(packed) > limit {
+		merged := make([]Chunk, 0, limit)
+		for i := 0; i < limit; i++ {
+			lo, hi := i*len(packed)/limit, (i+1)*len(packed)/limit
+			merged = append(merged, mergeChunks(packed[lo:hi], budget))
+		}
+		packed = merged
+	}
+	if len(packed) < 2 {
+		return nil
+	}
+	return packed
+}
+
+func chunkSize(c Chunk) int { return len(c.UserText) + len(c.AssistantText) }
+
+// splitChunk cuts c into pieces of at most budget characters, splitting
+// user and assistant text in step so each piece covers the same stretch.
+func splitChunk(c Chunk, budget int) []Chunk {
+	n := (chunkSize(c) + budget - 1) / budget
+	if n <= 1 {
+		return []Chunk{c}
+	}
+	users := splitText(c.UserText, n)
+	assts := splitText(c.AssistantText, n)
+	out := make([]Chunk, n)
+	for i := range out {
+		out[i] = Chunk{UserText: users[i], AssistantText: assts[i]}
+	}
+	out[0].UserRequest = c.UserRequest
+	out[0].Activities = c.Activities
+	return out
+}
+
+// splitText cuts text into n roughly equal parts, preferring line breaks.
+func splitText(text string, n int) []string {
+	parts := make([]string, 0, n)
+	for i := n; i > 0; i-- {
+		if i == 1 {
+			parts = append(parts, text)
+			break
+		}
+		cut := len(text) / i
+		if idx := strings.LastIndex(text[:cut], "\n"); idx > cut/2 {
+			cut = idx + 1
+		}
+		parts = append(parts, text[:cut])
+		text = text[cut:]
+	}
+	return parts
+}
+
+// mergeChunks joins adjacent chunks. When they overflow budget together,
+// each member's text is trimmed to an equal share of it.
+func mergeChunks(cs []Chunk, budget int) Chunk {
+	total := 0
+	for _, c := range cs {
+		total += chunkSize(c)
+	}
+	share := 0
+	if total > budget {
+		share = budget / len(cs) / 2
+	}
+
+	var out Chunk
+	var users, assts []string
+	for _, c := range cs {
+		if out.UserRequest == "" {
+			out.UserRequest = c.UserRequest
+		}
+		out.Activities = append(out.Activities, c.Activities...)
+		u, a := c.UserText, c.AssistantText
+		if share > 0 {
+			u, a = truncate(u, share), truncate(a, share)
+		}
+		if u != "" {
+			users = append(users, u)
+		}
+		if a != "" {
+			assts = append(assts, a)
+		}
+	}
+	out.UserText = strings.Join(users, "\n")
+	out.AssistantText = strings.Join(assts, "\n")
+	return out
+}
+
+// chunkSchema asks for native structured output on map calls. Their
+// replies are not repaired: formatPartial passes anything through to the
+// reduce call, which is validated.
+var chunkSchema = &llm.Schema{
+	Title:    "session_part",
+	Type:     "object",
+	Required: []string{"summary"},
+	Properties: map[string]*llm.Schema{
+		"summary":      {Type: "string"},
+		"decisions":    {Type: "array", Items: &llm.Schema{Type: "string"}, MaxItems: 3},
+		"open_threads": {Type: "array", Items: &llm.Schema{Type: "string"}, MaxItems: 3},
+	},
+}
+
+// generateChunked is the map-reduce path: one call per chunk extracts
+// that stretch's outcome, decisions, and open threads; a final call folds
+// the partial notes into the session-level Result. A failed map call is
+// logged and its chunk skipped; the run fails only when every map call
+// does. Usage sums every call, and Cached holds only when all were cached.
+func generateChunked(ctx context.Context, provider llm.Provider, input PromptInput, chunks []Chunk) (*Result, error) {
+	var usage llm.UsageStats
+	cached := true
+	add := func(resp *llm.Response) {
+		usage.InputTokens += resp.Usage.InputTokens
+		usage.OutputTokens += resp.Usage.OutputTokens
+		cached = cached && resp.Cached
+	}
+
+	partials := make([]string, len(chunks))
+	var errs []error
+	for i, c := range chunks {
+		resp, err := provider.ChatCompletion(ctx, llm.Request{
+			System:      chunkSystemPrompt,
+			UserPrompt:  buildChunkPrompt(c, i, len(chunks)),
+			Temperature: 0.3,
+			JSONMode:    true,
+			Schema:      chunkSchema,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("enrichment: %w", err)
+			}
+			log.Printf("warning: enrichment chunk %d/%d failed: %v", i+1, len(chunks), err)
+			errs = append(errs, err)
+			continue
+		}
+		add(resp)
+		partials[i] = formatPartial(resp.Content)
+	}
+	if len(errs) == len(chunks) {
+		return nil, fmt.Errorf("enrichment: every chunk failed: %w", errors.Join(errs...))
+	}
+
+	resp, err := completeResult(ctx, provider, reduceSystemPrompt, buildReducePrompt(input, partials))
+	if err != nil {
+		return nil, err
+	}
+	add(resp)
+
+	result, err := parseResponse(resp.Content)
+	if err != nil {
+		return nil, err
+	}
+	result.Usage = usage
+	result.Cached = cached
+	result.Provider, result.Model = resp.Provider, resp.Model
+	result.Chunks = len(chunks)
+	return result, nil
+}
+
+// formatPartial renders a map call's JSON as plain text for the reduce
+// prompt. Unparseable output is passed through so the reducer still sees
+// whatever the model said.
+func formatPartial(content string) string {
+	var ej enrichmentJSON
+	if err := json.Unmarshal([]byte(content), &ej); err != nil {
+		return strings.TrimSpace(content)
+	}
+	var b strings.Builder
+	b.WriteString(ej.Summary)
+	for _, d := range ej.Decisions {
+		fmt.Fprintf(&b, "\n- Decision: %s", d)
+	}
+	for _, t := range ej.OpenThreads {
+		fmt.Fprintf(&b, "\n- Open: %s", t)
+	}
+	return b.String()
+}
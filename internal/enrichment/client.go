@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"strings"
 
 	"github.com/suykerbuyk/vibe-vault/internal/llm"
@@ -21,28 +22,78 @@ var allowedTags = map[string]bool{
 	"research":       true,
 }
 
-// Generate calls the LLM to enrich a session note.
+const (
+	maxDecisions   = 5
+	maxOpenThreads = 3
+)
+
+// resultSchema is the enrichment reply contract, enforced by
+// llm.CompleteStructured and sent as native structured output where the
+// provider supports it.
+var resultSchema = &llm.Schema{
+	Title:    "session_enrichment",
+	Type:     "object",
+	Required: []string{"summary", "tag"},
+	Properties: map[string]*llm.Schema{
+		"summary":      {Type: "string"},
+		"decisions":    {Type: "array", Items: &llm.Schema{Type: "string"}, MaxItems: maxDecisions},
+		"open_threads": {Type: "array", Items: &llm.Schema{Type: "string"}, MaxItems: maxOpenThreads},
+		"tag": {Type: "string", Enum: []string{
+			"implementation", "debugging", "review", "planning", "exploration", "research",
+		}},
+	},
+}
+
+// Generate calls the LLM to enrich a session note. Long transcripts with
+// Chunks go through the map-reduce path (see PromptInput.Chunking);
+// everything else is one call over the truncated transcript.
 // Returns (nil, nil) if provider is nil (enrichment disabled/unavailable).
 func Generate(ctx context.Context, provider llm.Provider, input PromptInput) (*Result, error) {
 	if provider == nil {
 		return nil, nil
 	}
+	if chunks := planChunks(input); chunks != nil {
+		return generateChunked(ctx, provider, input, chunks)
+	}
 
 	messages := buildMessages(input)
 
-	resp, err := provider.ChatCompletion(ctx, llm.Request{
-		System:      messages[0].Content, // system prompt
-		UserPrompt:  messages[1].Content, // user prompt
+	resp, err := completeResult(ctx, provider, messages[0].Content, messages[1].Content)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := parseResponse(resp.Content)
+	if err != nil {
+		return nil, err
+	}
+	result.Usage = resp.Usage
+	result.Cached = resp.Cached
+	result.Provider, result.Model = resp.Provider, resp.Model
+	return result, nil
+}
+
+// completeResult runs a final-result call through schema validation and
+// one repair round. Output still off-schema afterwards is logged and
+// salvaged by parseResponse.
+func completeResult(ctx context.Context, provider llm.Provider, system, user string) (*llm.Response, error) {
+	resp, violations, err := llm.CompleteStructured(ctx, provider, llm.Request{
+		System:      system,
+		UserPrompt:  user,
 		Temperature: 0.3,
-		JSONMode:    true,
-	})
+		Schema:      resultSchema,
+	}, nil)
 	if err != nil {
 		return nil, fmt.Errorf("enrichment: %w", err)
 	}
-
-	return parseResponse(resp.Content)
+	if len(violations) > 0 {
+		log.Printf("warning: enrichment reply off-schema after repair: %s", strings.Join(violations, "; "))
+	}
+	return resp, nil
 }
 
+// parseResponse decodes the enrichment JSON, dropping an unknown tag and
+// capping the lists at their schema limits.
 func parseResponse(content string) (*Result, error) {
 	var ej enrichmentJSON
 	if err := json.Unmarshal([]byte(content), &ej); err != nil {
@@ -51,8 +102,8 @@ func parseResponse(content string) (*Result, error) {
 
 	return &Result{
 		Summary:     ej.Summary,
-		Decisions:   ej.Decisions,
-		OpenThreads: ej.OpenThreads,
+		Decisions:   capList(ej.Decisions, maxDecisions),
+		OpenThreads: capList(ej.OpenThreads, maxOpenThreads),
 		Tag:         validateTag(ej.Tag),
 	}, nil
 }
@@ -64,3 +115,10 @@ func validateTag(tag string) string {
 	}
 	return ""
 }
+
+func capList(items []string, limit int) []string {
+	if len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
@@ -172,6 +172,7 @@ func TestGenerate_MockProvider(t *testing.T) {
 	mock := &mockProvider{
 		response: &llm.Response{
 			Content: `{"summary":"Built enrichment pipeline.","decisions":["Raw HTTP over SDK — fewer deps"],"open_threads":["Add retry logic"],"tag":"implementation"}`,
+			Usage:   llm.UsageStats{InputTokens: 800, OutputTokens: 60},
 		},
 	}
 
@@ -196,6 +197,9 @@ func TestGenerate_MockProvider(t *testing.T) {
 	if result.Tag != "implementation" {
 		t.Errorf("tag: got %q", result.Tag)
 	}
+	if result.Usage.InputTokens != 800 || result.Usage.OutputTokens != 60 {
+		t.Errorf("usage: got %+v", result.Usage)
+	}
 	if mock.calls != 1 {
 		t.Errorf("expected 1 call, got %d", mock.calls)
 	}
@@ -211,3 +215,181 @@ func TestGenerate_ProviderError(t *testing.T) {
 		t.Fatal("expected error from provider")
 	}
 }
+
+// scriptedProvider answers map calls with a per-part summary and the
+// reduce call with the final JSON, recording every request.
+type scriptedProvider struct {
+	reqs    []llm.Request
+	failMap int // 1-based map call to fail; 0 = none
+}
+
+func (s *scriptedProvider) Name() string { return "scripted" }
+func (s *scriptedProvider) ChatCompletion(_ context.Context, req llm.Request) (*llm.Response, error) {
+	s.reqs = append(s.reqs, req)
+	if req.System == reduceSystemPrompt {
+		return &llm.Response{
+			Content: `{"summary":"Whole session.","decisions":["D — r"],"open_threads":[],"tag":"debugging"}`,
+			Usage:   llm.UsageStats{InputTokens: 100, OutputTokens: 20},
+		}, nil
+	}
+	if len(s.reqs) == s.failMap {
+		return nil, fmt.Errorf("boom")
+	}
+	return &llm.Response{
+		Content: fmt.Sprintf(`{"summary":"part %d done","decisions":[],"open_threads":["t%d"]}`, len(s.reqs), len(s.reqs)),
+		Usage:   llm.UsageStats{InputTokens: 10, OutputTokens: 5},
+		Cached:  true,
+	}, nil
+}
+
+func longInput(segments int, perSegment int) PromptInput {
+	in := PromptInput{Duration: 360}
+	var users []string
+	for i := 0; i < segments; i++ {
+		text := strings.Repeat(fmt.Sprintf("segment %d line\n", i), perSegment/16)
+		in.Chunks = append(in.Chunks, Chunk{
+			UserRequest:   fmt.Sprintf("request %d", i),
+			UserText:      text,
+			AssistantText: text,
+		})
+		users = append(users, text)
+	}
+	in.UserText = strings.Join(users, "\n")
+	in.AssistantText = in.UserText
+	return in
+}
+
+func TestPlanChunks_Modes(t *testing.T) {
+	short := PromptInput{UserText: "hi", AssistantText: "ok", Chunks: []Chunk{{UserText: "hi"}, {AssistantText: "ok"}}}
+	if got := planChunks(short); got != nil {
+		t.Errorf("auto + short transcript: got %d chunks, want single call", len(got))
+	}
+
+	long := longInput(3, 10000)
+	if got := planChunks(long); len(got) != 3 {
+		t.Errorf("auto + long transcript: got %d chunks, want 3", len(got))
+	}
+	long.Chunking.Mode = ChunkOff
+	if got := planChunks(long); got != nil {
+		t.Error("off: want single call")
+	}
+
+	short.Chunking = ChunkOptions{Mode: ChunkAlways, ChunkChars: 2}
+	if got := planChunks(short); len(got) != 2 {
+		t.Errorf("always: got %d chunks, want 2", len(got))
+	}
+	if Calls(short) != 3 {
+		t.Errorf("Calls = %d, want 3 (two maps + reduce)", Calls(short))
+	}
+}
+
+func TestPlanChunks_Budget(t *testing.T) {
+	// One oversized segment is split to fit the budget.
+	in := longInput(1, 20000)
+	in.Chunking = ChunkOptions{ChunkChars: 8000}
+	chunks := planChunks(in)
+	if len(chunks) < 5 {
+		t.Fatalf("got %d chunks, want the 40K segment split at 8K", len(chunks))
+	}
+	for i, c := range chunks {
+		if chunkSize(c) > 8000 {
+			t.Errorf("chunk %d size %d exceeds budget", i, chunkSize(c))
+		}
+	}
+	if chunks[0].UserRequest != "request 0" || chunks[1].UserRequest != "" {
+		t.Error("the opening request belongs to the first piece only")
+	}
+
+	// Small neighbours are packed together.
+	in = longInput(10, 2000)
+	in.Chunking = ChunkOptions{Mode: ChunkAlways, ChunkChars: 8000}
+	if got := planChunks(in); len(got) != 5 {
+		t.Errorf("packing: got %d chunks, want 5", len(got))
+	}
+
+	// Past MaxChunks every segment is still represented.
+	in = longInput(12, 20000)
+	in.Chunking = ChunkOptions{MaxChunks: 4}
+	chunks = planChunks(in)
+	if len(chunks) != 4 {
+		t.Fatalf("cap: got %d chunks, want 4", len(chunks))
+	}
+	all := ""
+	for _, c := range chunks {
+		if chunkSize(c) > defaultChunkChars+200 {
+			t.Errorf("merged chunk size %d well over budget", chunkSize(c))
+		}
+		all += c.UserText
+	}
+	for i := 0; i < 12; i++ {
+		if !strings.Contains(all, fmt.Sprintf("segment %d line", i)) {
+			t.Errorf("segment %d dropped by the cap", i)
+		}
+	}
+}
+
+func TestGenerate_MapReduce(t *testing.T) {
+	p := &scriptedProvider{}
+	result, err := Generate(context.Background(), p, longInput(3, 10000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.reqs) != 4 {
+		t.Fatalf("calls = %d, want 3 maps + 1 reduce", len(p.reqs))
+	}
+	if !strings.Contains(p.reqs[1].UserPrompt, "Part 2 of 3") || !strings.Contains(p.reqs[1].UserPrompt, "request 1") {
+		t.Errorf("map prompt missing part header or request:\n%.200s", p.reqs[1].UserPrompt)
+	}
+	reduce := p.reqs[3].UserPrompt
+	for _, want := range []string{"Duration: 360 minutes", "part 1 done", "part 3 done", "- Open: t2"} {
+		if !strings.Contains(reduce, want) {
+			t.Errorf("reduce prompt missing %q", want)
+		}
+	}
+	if strings.Contains(reduce, "segment 0 line") {
+		t.Error("reduce prompt should carry part notes, not transcript text")
+	}
+	if result.Summary != "Whole session." || result.Tag != "debugging" || result.Chunks != 3 {
+		t.Errorf("result = %+v", result)
+	}
+	if result.Usage != (llm.UsageStats{InputTokens: 130, OutputTokens: 35}) {
+		t.Errorf("usage = %+v, want every call summed", result.Usage)
+	}
+	if result.Cached {
+		t.Error("Cached should be false when the reduce call was billed")
+	}
+}
+
+func TestGenerate_MapReduceSkipsFailedChunk(t *testing.T) {
+	p := &scriptedProvider{failMap: 2}
+	result, err := Generate(context.Background(), p, longInput(3, 10000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(p.reqs[3].UserPrompt, "### Part 2 of 3\n(unavailable)") {
+		t.Error("failed part should be marked unavailable in the reduce prompt")
+	}
+	if result.Summary != "Whole session." {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestGenerate_RepairsOffSchemaReply(t *testing.T) {
+	// The mock answers the repair request with the same bad reply, so the
+	// result is salvaged: unknown tag dropped, lists capped.
+	mock := &mockProvider{
+		response: &llm.Response{
+			Content: `{"summary":"Did things.","decisions":["a","b","c","d","e","f"],"open_threads":[],"tag":"refactoring"}`,
+		},
+	}
+	result, err := Generate(context.Background(), mock, PromptInput{UserText: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mock.calls != 2 {
+		t.Errorf("calls = %d, want the original plus one repair", mock.calls)
+	}
+	if result.Tag != "" || len(result.Decisions) != maxDecisions {
+		t.Errorf("salvaged result = %+v", result)
+	}
+}
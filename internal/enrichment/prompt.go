@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+
+	"github.com/suykerbuyk/vibe-vault/internal/llm"
 )
 
 const (
@@ -28,6 +30,12 @@ type PromptInput struct {
 	NarrativeSummary string   // Heuristic summary for LLM to refine
 	NarrativeTag     string   // Heuristic tag
 	Activities       []string // Activity descriptions for context
+
+	// Chunks splits the transcript at compaction boundaries for
+	// map-reduce enrichment; Chunking decides when that path is taken.
+	// Without chunks, long transcripts are truncated.
+	Chunks   []Chunk
+	Chunking ChunkOptions
 }
 
 // chatMessage is used internally by buildMessages for prompt construction.
@@ -61,12 +69,28 @@ func buildMessages(input PromptInput) []chatMessage {
 
 func buildUserPrompt(input PromptInput) string {
 	var b strings.Builder
+	writeSessionContext(&b, input)
+
+	// Transcript text
+	userText := truncate(input.UserText, maxUserChars)
+	asstText := truncate(input.AssistantText, maxAssistantChars)
+
+	b.WriteString("\n## User Messages\n")
+	b.WriteString(userText)
+	b.WriteString("\n\n## Assistant Messages\n")
+	b.WriteString(asstText)
+
+	return b.String()
+}
 
+// writeSessionContext writes the metadata, tool, file, and heuristic
+// sections shared by the single-call and reduce prompts.
+func writeSessionContext(b *strings.Builder, input PromptInput) {
 	// Metadata section
 	b.WriteString("## Session Metadata\n")
-	fmt.Fprintf(&b, "- Duration: %d minutes\n", input.Duration)
-	fmt.Fprintf(&b, "- User messages: %d\n", input.UserMessages)
-	fmt.Fprintf(&b, "- Assistant messages: %d\n", input.AsstMessages)
+	fmt.Fprintf(b, "- Duration: %d minutes\n", input.Duration)
+	fmt.Fprintf(b, "- User messages: %d\n", input.UserMessages)
+	fmt.Fprintf(b, "- Assistant messages: %d\n", input.AsstMessages)
 
 	// Tool usage
 	if len(input.ToolCounts) > 0 {
@@ -78,7 +102,7 @@ func buildUserPrompt(input PromptInput) string {
 		}
 		sort.Strings(keys)
 		for _, k := range keys {
-			fmt.Fprintf(&b, "- %s: %d\n", k, input.ToolCounts[k])
+			fmt.Fprintf(b, "- %s: %d\n", k, input.ToolCounts[k])
 		}
 	}
 
@@ -86,7 +110,7 @@ func buildUserPrompt(input PromptInput) string {
 	if len(input.FilesChanged) > 0 {
 		b.WriteString("\n## Files Changed\n")
 		for _, f := range input.FilesChanged {
-			fmt.Fprintf(&b, "- %s\n", f)
+			fmt.Fprintf(b, "- %s\n", f)
 		}
 	}
 
@@ -95,10 +119,10 @@ func buildUserPrompt(input PromptInput) string {
 		b.WriteString("\n## Heuristic Analysis\n")
 		b.WriteString("The following was extracted heuristically. Refine rather than replace.\n")
 		if input.NarrativeSummary != "" {
-			fmt.Fprintf(&b, "- Summary: %s\n", input.NarrativeSummary)
+			fmt.Fprintf(b, "- Summary: %s\n", input.NarrativeSummary)
 		}
 		if input.NarrativeTag != "" {
-			fmt.Fprintf(&b, "- Tag: %s\n", input.NarrativeTag)
+			fmt.Fprintf(b, "- Tag: %s\n", input.NarrativeTag)
 		}
 		if len(input.Activities) > 0 {
 			b.WriteString("- Activities:\n")
@@ -107,26 +131,91 @@ func buildUserPrompt(input PromptInput) string {
 				max = 20
 			}
 			for _, a := range input.Activities[:max] {
-				fmt.Fprintf(&b, "  - %s\n", a)
+				fmt.Fprintf(b, "  - %s\n", a)
 			}
 			if len(input.Activities) > 20 {
-				fmt.Fprintf(&b, "  - ... and %d more\n", len(input.Activities)-20)
+				fmt.Fprintf(b, "  - ... and %d more\n", len(input.Activities)-20)
 			}
 		}
 	}
+}
 
-	// Transcript text
-	userText := truncate(input.UserText, maxUserChars)
-	asstText := truncate(input.AssistantText, maxAssistantChars)
+const chunkSystemPrompt = `You analyze one part of a longer Claude Code session transcript. Other parts are summarized separately and combined afterwards.
+
+Respond with valid JSON only. No markdown, no explanation. Schema:
+{
+  "summary": "1-2 sentences. Past tense. What this part accomplished.",
+  "decisions": ["Decision — rationale", ...],
+  "open_threads": ["Unfinished item at the end of this part", ...]
+}
+
+Rules:
+- Describe only this part; do not guess at the rest of the session.
+- decisions: 0-3 technical decisions made in this part. Omit if none.
+- open_threads: 0-3 items still unfinished at the end of this part. Omit if none.`
+
+const reduceSystemPrompt = `You combine per-part notes from one long Claude Code session into a single structured JSON summary. The parts are in chronological order.
+
+Respond with valid JSON only. No markdown, no explanation. Schema:
+{
+  "summary": "1-3 sentences. Past tense. Outcome-focused. What the whole session accomplished.",
+  "decisions": ["Decision — rationale", ...],
+  "open_threads": ["Actionable next step", ...],
+  "tag": "one of: implementation, debugging, review, planning, exploration, research"
+}
+
+Rules:
+- summary: Cover the session as a whole, not just its first or last part.
+- decisions: 0-5 of the most significant decisions across all parts. Format: "Decision — rationale". Merge duplicates.
+- open_threads: 0-3 items. Drop threads a later part resolved.
+- tag: Classify the session's primary activity. Exactly one tag.`
+
+// buildChunkPrompt renders the map prompt for chunk i of n.
+func buildChunkPrompt(c Chunk, i, n int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Part %d of %d\n", i+1, n)
+	if c.UserRequest != "" {
+		fmt.Fprintf(&b, "- Opening request: %s\n", c.UserRequest)
+	}
+	if len(c.Activities) > 0 {
+		b.WriteString("- Activities:\n")
+		limit := min(len(c.Activities), maxChunkActivities)
+		for _, a := range c.Activities[:limit] {
+			fmt.Fprintf(&b, "  - %s\n", a)
+		}
+		if len(c.Activities) > limit {
+			fmt.Fprintf(&b, "  - ... and %d more\n", len(c.Activities)-limit)
+		}
+	}
 
 	b.WriteString("\n## User Messages\n")
-	b.WriteString(userText)
+	b.WriteString(c.UserText)
 	b.WriteString("\n\n## Assistant Messages\n")
-	b.WriteString(asstText)
+	b.WriteString(c.AssistantText)
+	return b.String()
+}
 
+// buildReducePrompt renders the session context followed by the per-part
+// notes. Parts whose map call failed are marked rather than dropped so
+// the model knows there is a gap.
+func buildReducePrompt(input PromptInput, partials []string) string {
+	var b strings.Builder
+	writeSessionContext(&b, input)
+	b.WriteString("\n## Part Notes\n")
+	for i, p := range partials {
+		if p == "" {
+			p = "(unavailable)"
+		}
+		fmt.Fprintf(&b, "\n### Part %d of %d\n%s\n", i+1, len(partials), p)
+	}
 	return b.String()
 }
 
+// PromptVersion fingerprints the enrichment prompts and reply schemas.
+func PromptVersion() string {
+	return llm.PromptVersion(systemPrompt, chunkSystemPrompt, reduceSystemPrompt, resultSchema, chunkSchema)
+}
+
 func truncate(text string, maxChars int) string {
 	if len(text) <= maxChars {
 		return text
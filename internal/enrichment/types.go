@@ -3,12 +3,28 @@
 
 package enrichment
 
+import "github.com/suykerbuyk/vibe-vault/internal/llm"
+
 // Result holds the LLM-generated enrichment for a session note.
 type Result struct {
 	Summary     string
 	Decisions   []string
 	OpenThreads []string
 	Tag         string
+
+	// Usage is what the enrichment call consumed; Cached reports it was
+	// served from the LLM cache rather than billed again.
+	Usage  llm.UsageStats
+	Cached bool
+
+	// Provider and Model name the fallback-chain link that served the
+	// call; empty when no chain is configured.
+	Provider string
+	Model    string
+
+	// Chunks is the number of transcript chunks map-reduced into this
+	// result; 0 for a single call.
+	Chunks int
 }
 
 // enrichmentJSON is the expected JSON structure from the LLM response.
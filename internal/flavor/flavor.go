@@ -0,0 +1,274 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Package flavor adapts vv's rendered markdown to the target note app.
+//
+// Renderers produce one canonical form — Obsidian: YAML frontmatter,
+// [[wikilinks]], "- [ ]" tasks. Convert rewrites that form for Logseq
+// (key:: value page properties, TODO/DONE tasks) or plain markdown
+// (relative [name](path.md) links). ParseProperties reads Logseq
+// properties back so note parsing works for every flavor.
+package flavor
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Flavor names a vault dialect.
+type Flavor string
+
+const (
+	Obsidian Flavor = "obsidian"
+	Logseq   Flavor = "logseq"
+	Markdown Flavor = "markdown"
+)
+
+// All lists the supported flavors in display order.
+var All = []Flavor{Obsidian, Logseq, Markdown}
+
+// Parse validates s. The empty string selects Obsidian, the default.
+func Parse(s string) (Flavor, error) {
+	switch Flavor(strings.ToLower(strings.TrimSpace(s))) {
+	case "", Obsidian:
+		return Obsidian, nil
+	case Logseq:
+		return Logseq, nil
+	case Markdown:
+		return Markdown, nil
+	}
+	return "", fmt.Errorf("unknown vault flavor %q (want obsidian, logseq, or markdown)", s)
+}
+
+// Resolver maps a wikilink target (a note name without extension) to the
+// link path used by the markdown flavor. A nil Resolver yields name+".md".
+type Resolver func(name string) string
+
+// RelativeResolver resolves names through targets (name → vault-relative
+// note path), producing links relative to the document at fromPath (also
+// vault-relative). Unknown names fall back to name+".md".
+func RelativeResolver(fromPath string, targets map[string]string) Resolver {
+	fromDir := path.Dir(toSlash(fromPath))
+	return func(name string) string {
+		target, ok := targets[name]
+		if !ok {
+			return name + ".md"
+		}
+		return relPath(fromDir, toSlash(target))
+	}
+}
+
+var (
+	wikilinkRe   = regexp.MustCompile(`\[\[([^\[\]|]+)(?:\|([^\[\]]+))?\]\]`)
+	quotedLinkRe = regexp.MustCompile(`"\[\[([^\[\]|]+)(?:\|[^\[\]]+)?\]\]"`)
+	logseqKeyRe  = regexp.MustCompile(`^([A-Za-z0-9_-]+):: ?(.*)$`)
+)
+
+// Convert rewrites a canonical (Obsidian) document for flavor f.
+func Convert(doc string, f Flavor, resolve Resolver) string {
+	switch f {
+	case Logseq:
+		return toLogseq(doc)
+	case Markdown:
+		return toMarkdown(doc, resolve)
+	}
+	return doc
+}
+
+// splitFrontmatter returns the YAML lines between the leading "---"
+// delimiters and the remainder. ok is false when doc has no frontmatter.
+func splitFrontmatter(doc string) (fm []string, body string, ok bool) {
+	if !strings.HasPrefix(doc, "---\n") {
+		return nil, doc, false
+	}
+	rest := doc[len("---\n"):]
+	end := strings.Index(rest, "\n---\n")
+	if end < 0 {
+		return nil, doc, false
+	}
+	return strings.Split(rest[:end], "\n"), rest[end+len("\n---\n"):], true
+}
+
+func toMarkdown(doc string, resolve Resolver) string {
+	if resolve == nil {
+		resolve = func(name string) string { return name + ".md" }
+	}
+	var b strings.Builder
+	fm, body, ok := splitFrontmatter(doc)
+	if ok {
+		b.WriteString("---\n")
+		for _, line := range fm {
+			// Frontmatter links become plain note names: YAML has no
+			// portable link syntax outside Obsidian.
+			b.WriteString(quotedLinkRe.ReplaceAllString(line, `"$1"`))
+			b.WriteString("\n")
+		}
+		b.WriteString("---\n")
+	}
+	b.WriteString(mapOutsideFences(body, func(line string) string {
+		return wikilinkRe.ReplaceAllStringFunc(line, func(m string) string {
+			sub := wikilinkRe.FindStringSubmatch(m)
+			text := sub[1]
+			if sub[2] != "" {
+				text = sub[2]
+			}
+			return fmt.Sprintf("[%s](%s)", text, escapeLinkPath(resolve(sub[1])))
+		})
+	}))
+	return b.String()
+}
+
+func toLogseq(doc string) string {
+	var b strings.Builder
+	fm, body, ok := splitFrontmatter(doc)
+	if ok {
+		for i := 0; i < len(fm); i++ {
+			if isContinuation(fm[i]) {
+				continue
+			}
+			key, val, found := strings.Cut(fm[i], ":")
+			if !found || strings.TrimSpace(key) == "" {
+				continue
+			}
+			val = logseqValue(strings.TrimSpace(val))
+			if val == "" {
+				// A block list ("tags:" then "  - a" lines) folds into
+				// Logseq's comma-separated multi-value form.
+				var items []string
+				for ; i+1 < len(fm) && isContinuation(fm[i+1]); i++ {
+					if item, ok := strings.CutPrefix(strings.TrimSpace(fm[i+1]), "- "); ok {
+						items = append(items, unquote(strings.TrimSpace(item)))
+					}
+				}
+				val = strings.Join(items, ", ")
+			}
+			fmt.Fprintf(&b, "%s:: %s\n", logseqKey(strings.TrimSpace(key)), val)
+		}
+		// Logseq ends the page-properties block at the first blank line.
+		body = "\n" + strings.TrimLeft(body, "\n")
+	}
+	b.WriteString(mapOutsideFences(body, func(line string) string {
+		trimmed := strings.TrimLeft(line, " \t")
+		indent := line[:len(line)-len(trimmed)]
+		switch {
+		case strings.HasPrefix(trimmed, "- [ ] "):
+			return indent + "- TODO " + trimmed[len("- [ ] "):]
+		case strings.HasPrefix(trimmed, "- [x] "), strings.HasPrefix(trimmed, "- [X] "):
+			return indent + "- DONE " + trimmed[len("- [x] "):]
+		}
+		return line
+	}))
+	return b.String()
+}
+
+// isContinuation reports whether a frontmatter line continues the key
+// above it: an indented line or a block-list item.
+func isContinuation(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "- ")
+}
+
+// logseqKey maps YAML keys to Logseq's preferred hyphenated form.
+func logseqKey(k string) string { return strings.ReplaceAll(k, "_", "-") }
+
+// logseqValue unquotes YAML scalars and flattens [a, b] lists to the
+// comma-separated form Logseq uses for multi-value properties.
+func logseqValue(v string) string {
+	if strings.HasPrefix(v, "[") && strings.HasSuffix(v, "]") {
+		inner := strings.TrimSpace(v[1 : len(v)-1])
+		if inner == "" {
+			return ""
+		}
+		parts := strings.Split(inner, ",")
+		for i, p := range parts {
+			parts[i] = unquote(strings.TrimSpace(p))
+		}
+		return strings.Join(parts, ", ")
+	}
+	return unquote(v)
+}
+
+func unquote(v string) string {
+	if len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"' {
+		v = v[1 : len(v)-1]
+		v = strings.ReplaceAll(v, `\"`, `"`)
+		v = strings.ReplaceAll(v, `\\`, `\`)
+	}
+	return v
+}
+
+// ParseProperties reads a leading Logseq page-properties block from body
+// lines. Keys are returned in YAML form (hyphens → underscores) and
+// multi-value properties as "[a, b]" so callers can treat them exactly
+// like frontmatter fields. rest holds the lines after the block. found is
+// false when the first non-blank line is not a property.
+func ParseProperties(lines []string) (fields map[string]string, rest []string, found bool) {
+	fields = make(map[string]string)
+	i := 0
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+	for ; i < len(lines); i++ {
+		m := logseqKeyRe.FindStringSubmatch(strings.TrimSpace(lines[i]))
+		if m == nil {
+			break
+		}
+		key := strings.ReplaceAll(m[1], "-", "_")
+		val := strings.TrimSpace(m[2])
+		if key == "tags" || key == "commits" || key == "tools" || key == "branches" {
+			val = "[" + val + "]"
+		}
+		fields[key] = val
+	}
+	if len(fields) == 0 {
+		return fields, lines, false
+	}
+	return fields, lines[i:], true
+}
+
+// mapOutsideFences applies fn to every line not inside a ``` fence.
+func mapOutsideFences(text string, fn func(string) string) string {
+	lines := strings.Split(text, "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if !inFence {
+			lines[i] = fn(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func escapeLinkPath(p string) string { return strings.ReplaceAll(p, " ", "%20") }
+
+func toSlash(p string) string { return strings.ReplaceAll(p, `\`, "/") }
+
+// relPath returns target relative to fromDir (both slash-separated).
+func relPath(fromDir, target string) string {
+	from := splitPath(fromDir)
+	to := splitPath(target)
+	n := 0
+	for n < len(from) && n < len(to)-1 && from[n] == to[n] {
+		n++
+	}
+	var parts []string
+	for range from[n:] {
+		parts = append(parts, "..")
+	}
+	parts = append(parts, to[n:]...)
+	return strings.Join(parts, "/")
+}
+
+func splitPath(p string) []string {
+	var out []string
+	for _, s := range strings.Split(p, "/") {
+		if s != "" && s != "." {
+			out = append(out, s)
+		}
+	}
+	return out
+}
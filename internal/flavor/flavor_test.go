@@ -0,0 +1,130 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package flavor
+
+import (
+	"strings"
+	"testing"
+)
+
+const canonical = `---
+date: 2026-02-22
+session_id: "abc"
+tags: [vv-session, implementation]
+summary: "Added \"JWT\" auth"
+previous: "[[2026-02-21-03]]"
+related: ["[[2026-02-20-01]]", "[[2026-02-19-02]]"]
+---
+
+# Auth
+
+## Open Threads
+
+- [ ] Add refresh tokens
+- [x] Wire middleware
+
+## Related Sessions
+
+- [[2026-02-20-01]] — 2 shared files
+
+` + "```mermaid\ngantt\n    [[not-a-link]]\n```\n"
+
+func TestParse(t *testing.T) {
+	for in, want := range map[string]Flavor{"": Obsidian, "obsidian": Obsidian, "Logseq": Logseq, " markdown ": Markdown} {
+		got, err := Parse(in)
+		if err != nil || got != want {
+			t.Errorf("Parse(%q) = (%q, %v), want %q", in, got, err, want)
+		}
+	}
+	if _, err := Parse("roam"); err == nil {
+		t.Error("expected error for unknown flavor")
+	}
+}
+
+func TestConvert_ObsidianIsIdentity(t *testing.T) {
+	if got := Convert(canonical, Obsidian, nil); got != canonical {
+		t.Errorf("obsidian conversion changed the document:\n%s", got)
+	}
+}
+
+func TestConvert_Markdown(t *testing.T) {
+	resolve := RelativeResolver("Projects/p/sessions/host/2026-02-22/a.md", map[string]string{
+		"2026-02-20-01": "Projects/p/sessions/host/2026-02-20/2026-02-20-01.md",
+	})
+	got := Convert(canonical, Markdown, resolve)
+	for _, want := range []string{
+		`previous: "2026-02-21-03"`,
+		`related: ["2026-02-20-01", "2026-02-19-02"]`,
+		"- [2026-02-20-01](../2026-02-20/2026-02-20-01.md) — 2 shared files",
+		"- [ ] Add refresh tokens",
+		"    [[not-a-link]]",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("markdown output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestConvert_Logseq(t *testing.T) {
+	got := Convert(canonical, Logseq, nil)
+	if !strings.HasPrefix(got, "date:: 2026-02-22\nsession-id:: abc\ntags:: vv-session, implementation\n") {
+		t.Errorf("properties block wrong:\n%s", got)
+	}
+	for _, want := range []string{
+		`summary:: Added "JWT" auth`,
+		"previous:: [[2026-02-21-03]]",
+		"related:: [[2026-02-20-01]], [[2026-02-19-02]]\n\n# Auth",
+		"- TODO Add refresh tokens",
+		"- DONE Wire middleware",
+		"- [[2026-02-20-01]] — 2 shared files",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("logseq output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "---\n") {
+		t.Errorf("logseq output kept YAML delimiters:\n%s", got)
+	}
+}
+
+func TestConvert_LogseqBlockList(t *testing.T) {
+	doc := "---\ndate: 2026-02-22\ntags:\n  - vv-session\n  - \"keeper\"\n- flat\naliases:\nsummary: x\n---\n\n# Auth\n"
+	got := Convert(doc, Logseq, nil)
+	want := "date:: 2026-02-22\ntags:: vv-session, keeper, flat\naliases:: \nsummary:: x\n\n# Auth\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	fields, _, _ := ParseProperties(strings.Split(got, "\n"))
+	if fields["tags"] != "[vv-session, keeper, flat]" {
+		t.Errorf("tags read back as %q", fields["tags"])
+	}
+}
+
+func TestParseProperties_RoundTrip(t *testing.T) {
+	lines := strings.Split(Convert(canonical, Logseq, nil), "\n")
+	fields, rest, found := ParseProperties(lines)
+	if !found {
+		t.Fatal("expected properties")
+	}
+	if fields["session_id"] != "abc" || fields["tags"] != "[vv-session, implementation]" || fields["previous"] != "[[2026-02-21-03]]" {
+		t.Errorf("fields = %v", fields)
+	}
+	if len(rest) == 0 || rest[0] != "" || rest[1] != "# Auth" {
+		t.Errorf("rest starts %q", rest[:2])
+	}
+
+	if _, _, found := ParseProperties([]string{"# Heading", "a:: b"}); found {
+		t.Error("properties must lead the page")
+	}
+}
+
+func TestRelativeResolver(t *testing.T) {
+	r := RelativeResolver("Projects/p/history.md", map[string]string{"n": "Projects/p/sessions/n.md"})
+	if got := r("n"); got != "sessions/n.md" {
+		t.Errorf("got %q", got)
+	}
+	if got := r("unknown"); got != "unknown.md" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -60,24 +60,33 @@ var TopLevel = Command{
 }
 
 var CmdInit = Command{
-	Name:     "init",
-	Synopsis: "create a new Obsidian vault for session notes",
-	Brief:    "Create a new vault (default: ./vibe-vault)",
-	Usage:    "vv init [path] [--git]",
+	Name:       "init",
+	Synopsis:   "create a new Obsidian vault for session notes",
+	Brief:      "Create a new vault (default: ./vibe-vault)",
+	Usage:      "vv init [path] [--git] [--flavor <name>]",
+	TableUsage: "vv init [path] [--git]",
 	Args: []Arg{
 		{Name: "path", Desc: "Target directory (default: ./vibe-vault)", Optional: true},
 	},
 	Flags: []Flag{
 		{Name: "--git", Desc: "Initialize a git repository in the new vault"},
+		{Name: "--flavor <name>", Desc: "Vault flavor: obsidian (default), logseq, or markdown"},
 	},
 	Description: `Creates a fully configured Obsidian vault with Dataview dashboards,
 Templater templates, and session capture infrastructure. Also writes
 a default config to ~/.config/vibe-vault/config.toml pointing at the
-new vault.`,
+new vault.
+
+--flavor logseq writes Logseq query dashboards and notes with key:: value
+page properties; --flavor markdown writes plain notes with relative links.
+Run against an existing vault, --flavor switches its dashboards (old
+ones move to _archive/flavor-backup/) and records vault_flavor in the
+config; follow with vv reprocess to re-render existing notes.`,
 	Examples: []string{
 		"vv init                       Create ./vibe-vault",
 		"vv init ~/obsidian/my-vault   Create at a specific path",
 		"vv init --git                 Create with git repo initialized",
+		"vv init --flavor logseq       Create a Logseq graph",
 	},
 	SeeAlso: []string{"vv(1)", "vv-hook(1)", "vv-check(1)"},
 }
@@ -113,11 +122,14 @@ var CmdProcess = Command{
 	Name:       "process",
 	Synopsis:   "process a single transcript file",
 	Brief:      "Process a single transcript file",
-	Usage:      "vv process <transcript.jsonl>",
+	Usage:      "vv process <transcript.jsonl> [--no-cache]",
 	TableUsage: "vv process <file.jsonl>",
 	Args: []Arg{
 		{Name: "transcript.jsonl", Desc: "Path to a Claude Code JSONL transcript"},
 	},
+	Flags: []Flag{
+		{Name: "--no-cache", Desc: "Bypass the LLM response cache"},
+	},
 	Description: `Parses the transcript, detects the project from the session's working
 directory, and writes a session note to the vault. Skips trivial
 sessions (< 2 messages) and already-indexed sessions.`,
@@ -144,39 +156,214 @@ Use this after manually editing or deleting session notes.`,
 }
 
 var CmdBackfill = Command{
-	Name:     "backfill",
-	Synopsis: "discover and process historical transcripts",
-	Brief:    "Discover and process historical transcripts",
-	Usage:    "vv backfill [path]",
+	Name:       "backfill",
+	Synopsis:   "discover and process historical transcripts",
+	Brief:      "Discover and process historical transcripts",
+	Usage:      "vv backfill [path] [--workers N] [--llm-concurrency N] [--no-enrich] [--json]",
+	TableUsage: "vv backfill [path] [...]",
 	Args: []Arg{
 		{Name: "path", Desc: "Directory to scan for transcripts (default: ~/.claude/projects/)", Optional: true},
 	},
+	Flags: []Flag{
+		{Name: "--workers <N>", Desc: "Transcripts parsed and extracted in parallel (default: CPUs, at most 8)"},
+		{Name: "--llm-concurrency <N>", Desc: "Enrichment calls in flight at once (default: 2)"},
+		{Name: "--no-enrich", Desc: "Write heuristic notes without LLM enrichment"},
+		{Name: "--json", Desc: "Emit the final report as JSON"},
+	},
 	Description: `Recursively discovers Claude Code JSONL transcripts by UUID filename
 pattern, skips already-indexed sessions, and processes the rest through
 the full capture pipeline. Also patches TranscriptPath on existing index
 entries that lack it.
 
+A pool of workers parses transcripts and runs LLM enrichment (when
+[enrichment] is enabled) in parallel; a single writer commits notes in
+discovery order, taking the index lock per small batch so hook captures
+are not blocked for the whole run. A progress line with an ETA is drawn
+on stderr.
+
+The index is saved after every batch, and already-indexed sessions are
+skipped before they are parsed, so Ctrl-C (which commits what is already
+prepared and stops) loses little: running the same command again resumes
+from the index. Trivial sessions are not indexed, so one still in progress
+is captured later. Transcripts that fail to parse or capture are listed in
+the final report and retried on the next run.
+Exits 1 when interrupted or when any transcript failed.
+
 Subagent transcripts (in /subagents/ subdirectories) are automatically
 filtered out.`,
 	Examples: []string{
 		"vv backfill                              Scan default Claude projects dir",
 		"vv backfill ~/.claude/projects/myproj    Scan a specific directory",
+		"vv backfill --workers 4 --llm-concurrency 1",
 	},
 	SeeAlso: []string{"vv(1)", "vv-process(1)", "vv-archive(1)"},
 }
 
 var CmdArchive = Command{
-	Name:     "archive",
-	Synopsis: "compress transcripts into vault archive",
-	Brief:    "Compress transcripts into vault archive",
-	Usage:    "vv archive",
+	Name:       "archive",
+	Synopsis:   "compress transcripts into vault archive",
+	Brief:      "Compress transcripts into vault archive",
+	Usage:      "vv archive [train-dict|pack|verify|restore|prune] [--level <n>] [--no-dict]",
+	TableUsage: "vv archive [...]",
+	Flags: []Flag{
+		{Name: "--level <n>", Desc: "zstd compression level, 1-22 (default 3)"},
+		{Name: "--no-dict", Desc: "Compress without the trained dictionary"},
+	},
 	Description: `Iterates all sessions in the index and compresses each transcript to
 .vibe-vault/archive/{session-id}.jsonl.zst using zstd compression
 (typically ~10:1 on JSONL). Skips already-archived and missing
 transcripts. Originals are not deleted.
 
+When vv archive train-dict has built a dictionary, new archives are
+compressed with the newest one. Its ID is recorded in each archive's
+zstd frame header, and decompression (vv reprocess, train-dict) picks
+the matching version from .vibe-vault/archive/dicts/.
+
+With pack = true under [archive] in config.toml, archives are appended
+to a monthly pack file, .vibe-vault/archive/packs/YYYY-MM.pack, as
+independently decompressible zstd frames, and an offset index
+(YYYY-MM.idx) records where each session's frame lives. Reading is
+transparent: reprocess and backfill find packed and loose archives
+alike.
+
+Subcommands:
+  vv archive train-dict   Train a zstd dictionary from recent transcripts
+  vv archive pack         Move loose archives into monthly pack files
+  vv archive verify       Decode every archive and check frame checksums
+  vv archive restore      Restore an archived transcript for claude --resume
+  vv archive prune        Apply the [archive] retention policy
+
 Reports total bytes before and after compression.`,
-	SeeAlso: []string{"vv(1)", "vv-backfill(1)", "vv-reprocess(1)"},
+	Examples: []string{
+		"vv archive",
+		"vv archive --level 19",
+	},
+	SeeAlso: []string{"vv(1)", "vv-archive-train-dict(1)", "vv-archive-pack(1)", "vv-archive-verify(1)", "vv-archive-restore(1)", "vv-archive-prune(1)", "vv-backfill(1)", "vv-reprocess(1)"},
+}
+
+var CmdArchiveTrainDict = Command{
+	Name:     "archive train-dict",
+	Synopsis: "train a zstd dictionary for transcript archives",
+	Brief:    "Train a zstd dictionary for transcript archives",
+	Usage:    "vv archive train-dict [--samples <n>] [--max-size <bytes>] [--level <n>]",
+	Flags: []Flag{
+		{Name: "--samples <n>", Desc: "Number of recent transcripts to sample (default 500)"},
+		{Name: "--max-size <bytes>", Desc: "Dictionary size (default 112640)"},
+		{Name: "--level <n>", Desc: "zstd level to tune the dictionary for (default 3)"},
+	},
+	Description: `Transcripts share most of their structure: JSON keys, tool names,
+system prompts. A dictionary trained on them lets zstd exploit that even
+for small sessions that compress poorly on their own.
+
+Samples the most recently captured transcripts (the first 256 KiB of
+each, read from the original or the archive), builds a dictionary, and
+stores it as the next version, .vibe-vault/archive/dicts/vNNNN.dict.
+Version N has dictionary ID 32768+N. Older versions are kept so the
+archives written with them still decompress.
+
+Every fifth sample is held out of training and compressed with and
+without the new dictionary to report the savings. Run vv archive
+afterwards to compress new transcripts with it.`,
+	Examples: []string{
+		"vv archive train-dict",
+		"vv archive train-dict --samples 200 --level 19",
+	},
+	SeeAlso: []string{"vv(1)", "vv-archive(1)"},
+}
+
+var CmdArchivePack = Command{
+	Name:     "archive pack",
+	Synopsis: "move loose archives into monthly pack files",
+	Brief:    "Move loose archives into monthly pack files",
+	Usage:    "vv archive pack",
+	Description: `Appends every loose .vibe-vault/archive/{session-id}.jsonl.zst to the
+pack for its session's month (the note date, or the file's mtime for
+sessions no longer indexed) and records it in that month's offset index.
+A vault with thousands of sessions ends up with two files per month
+instead of one per session, which keeps inode counts and git status
+cheap.
+
+Compressed frames are copied as-is, so each keeps its level and
+dictionary. Every frame is read back and checked against the index
+before its loose file is removed. Safe to re-run; set pack = true under
+[archive] so vv archive keeps writing to packs afterwards.`,
+	Examples: []string{
+		"vv archive pack",
+	},
+	SeeAlso: []string{"vv(1)", "vv-archive(1)", "vv-archive-verify(1)"},
+}
+
+var CmdArchiveVerify = Command{
+	Name:     "archive verify",
+	Synopsis: "check every archive decompresses intact",
+	Brief:    "Check every archive decompresses intact",
+	Usage:    "vv archive verify [--json]",
+	Flags: []Flag{
+		{Name: "--json", Desc: "Print the report as JSON"},
+	},
+	Description: `Decodes every packed and loose archive, which makes zstd check each
+frame's content checksum. Packed frames are also compared with the size
+and SHA-256 in their pack's offset index, which must cover the pack
+end to end: bytes no index entry covers (left by an interrupted append)
+and entries whose ranges overlap are reported.
+
+Exits 1 if any archive fails.`,
+	Examples: []string{
+		"vv archive verify",
+		"vv archive verify --json",
+	},
+	SeeAlso: []string{"vv(1)", "vv-archive(1)", "vv-archive-pack(1)"},
+}
+
+var CmdArchiveRestore = Command{
+	Name:     "archive restore",
+	Synopsis: "restore an archived transcript",
+	Brief:    "Restore an archived transcript",
+	Usage:    "vv archive restore <session-id> [--to <path>] [--force]",
+	Flags: []Flag{
+		{Name: "--to <path>", Desc: "Write here instead of the original path (a directory gets <session-id>.jsonl)"},
+		{Name: "--force", Desc: "Overwrite an existing file"},
+	},
+	Description: `Decompresses a session's archive, loose or packed, back to the path it
+was captured from under ~/.claude/projects/, so claude --resume can pick
+the session up again after its original was pruned. Sessions without a
+recorded transcript path need --to.
+
+Refuses to overwrite an existing file unless --force is given.`,
+	Examples: []string{
+		"vv archive restore 3f2a9c1e-5b7d-4e8f-a012-6c4d8e9f0b1a",
+		"vv archive restore 3f2a9c1e-5b7d-4e8f-a012-6c4d8e9f0b1a --to /tmp/",
+	},
+	SeeAlso: []string{"vv(1)", "vv-archive(1)", "vv-archive-prune(1)"},
+}
+
+var CmdArchivePrune = Command{
+	Name:     "archive prune",
+	Synopsis: "apply the archive retention policy",
+	Brief:    "Apply the archive retention policy",
+	Usage:    "vv archive prune [--dry-run]",
+	Flags: []Flag{
+		{Name: "--dry-run", Desc: "List what would be deleted without deleting it"},
+	},
+	Description: `Ages out transcript data by session date, using two settings under
+[archive] in config.toml. Either can be overridden per project in
+Projects/<project>/agentctx/config.toml; 0 (the default) keeps forever.
+
+  keep_originals_months   Delete original transcripts in ~/.claude/projects
+                          older than this. An original is deleted only when
+                          its archive decodes to exactly the same bytes;
+                          unarchived or changed originals are kept and
+                          counted.
+  keep_months             Delete archived copies older than this. Packs
+                          that lose sessions are rewritten.
+
+Session notes are never touched. Sessions missing from the index, and
+Zed threads, are left alone.`,
+	Examples: []string{
+		"vv archive prune --dry-run",
+		"vv archive prune",
+	},
+	SeeAlso: []string{"vv(1)", "vv-archive(1)", "vv-archive-restore(1)", "vv-config(1)"},
 }
 
 var CmdReprocess = Command{
@@ -190,6 +377,11 @@ var CmdReprocess = Command{
 		{Name: "--source <name>", Desc: "Filter by source (zed, claude-code)"},
 		{Name: "--dry-run", Desc: "Show what would be reprocessed without writing"},
 		{Name: "--backfill-context", Desc: "Populate ContextAvailable on entries (no reprocessing)"},
+		{Name: "--no-cache", Desc: "Bypass the LLM response cache"},
+		{Name: "--diff", Desc: "Render in memory and print unified diffs against existing notes"},
+		{Name: "--only-changed-sections", Desc: "With --diff, print one diff per changed section"},
+		{Name: "--accept <sections>", Desc: "Write only these comma-separated sections (implies --diff)"},
+		{Name: "--enrich", Desc: "With --diff, call the LLM for sessions whose enrichment is not cached"},
 	},
 	Description: `Re-runs the capture pipeline with Force mode for all (or filtered)
 sessions in the index. Locates transcripts via three-tier lookup:
@@ -198,11 +390,31 @@ sessions in the index. Locates transcripts via three-tier lookup:
   2. Archived copy (.vibe-vault/archive/)
   3. Fallback discovery scan (~/.claude/projects/)
 
-Overwrites existing notes in place (preserves iteration numbers).
-Regenerates history.md for each affected project.`,
+Overwrites existing notes in place, preserving iteration numbers, the
+<!-- vv:user:start/end --> region, the rating and notes frontmatter
+keys, and tags added by hand.
+Notes are rendered in the configured vault_flavor, so reprocessing
+converts a vault after vv init --flavor. Regenerates history.md for
+each affected project.
+
+Enrichment and synthesis responses are served from the LLM cache when
+the transcript and prompt are unchanged, so reprocessing twice costs
+nothing the second time (see vv llm cache).
+
+--diff previews an upgrade: each note is rendered in memory and
+compared with the note on disk. The preview makes no LLM calls: it
+reuses enrichment from the LLM cache, and sessions with none cached are
+counted but not diffed. --enrich enriches those too, at the cost of a
+real run. Nothing is written unless --accept names sections to take
+from the new render; every other section, including hand-added ones, is
+kept verbatim. Section names are frontmatter, title, and the slugged ## heading
+(what-happened, key-decisions, ...). A summary counts how many notes
+change in each section. After accepting frontmatter, run vv index.`,
 	Examples: []string{
 		"vv reprocess                       Reprocess all sessions",
 		"vv reprocess --project myproject   Reprocess one project only",
+		"vv reprocess --diff                Preview what would change",
+		"vv reprocess --accept frontmatter,what-happened",
 	},
 	SeeAlso: []string{"vv(1)", "vv-archive(1)", "vv-index(1)"},
 }
@@ -405,6 +617,216 @@ populate ContextAvailable data on historical sessions.`,
 	SeeAlso: []string{"vv(1)", "vv-friction(1)", "vv-trends(1)", "vv-reprocess(1)"},
 }
 
+var CmdPrDescribe = Command{
+	Name:       "pr-describe",
+	Synopsis:   "generate a pull-request description from branch sessions",
+	Brief:      "Generate a PR description from a branch's sessions",
+	Usage:      "vv pr-describe [--branch <name>] [--base <ref>] [--project <name>] [--llm]",
+	TableUsage: "vv pr-describe [--branch X]",
+	Flags: []Flag{
+		{Name: "--branch <name>", Desc: "Feature branch (default: current git branch)"},
+		{Name: "--base <ref>", Desc: "Merge target (default: main)"},
+		{Name: "--project <name>", Desc: "Project to search (default: auto-detected from cwd)"},
+		{Name: "--llm", Desc: "Polish the draft with the configured enrichment provider"},
+		{Name: "--no-cache", Desc: "With --llm, bypass the LLM response cache"},
+	},
+	Description: `Collects every indexed session whose branch matches, plus sessions
+whose recorded commits fall in <base>..<branch>, orders them oldest
+first, and renders a markdown PR description to stdout:
+
+  Summary          One line per session
+  Key Decisions    "Decision — rationale" from enrichment
+  Files Changed    Union of FilesChanged, with per-file session counts
+  Commits          SHAs recorded in the sessions
+  Test Evidence    Test runs re-extracted from session transcripts
+  Open Threads     Latest session's threads first, then earlier ones
+
+Transcripts are located via the same lookup as vv reprocess (original
+path, archive, discovery scan); sessions without one contribute no
+test evidence. --llm is optional and falls back to the heuristic draft
+on any provider error.`,
+	Examples: []string{
+		"vv pr-describe                              Describe the current branch",
+		"vv pr-describe --branch feat/auth --base dev",
+		"vv pr-describe --llm | gh pr create --body-file -",
+	},
+	SeeAlso: []string{"vv(1)", "vv-reprocess(1)", "vv-archive(1)"},
+}
+
+var CmdChangelog = Command{
+	Name:       "changelog",
+	Synopsis:   "generate a changelog section from sessions between two git refs",
+	Brief:      "Generate a Keep-a-Changelog section for a git range",
+	Usage:      "vv changelog <from-ref>..<to-ref> [--project <name>] [--version <label>]",
+	TableUsage: "vv changelog <from>..<to>",
+	Args: []Arg{
+		{Name: "from-ref..to-ref", Desc: "Git revision range; <to-ref> defaults to HEAD"},
+	},
+	Flags: []Flag{
+		{Name: "--project <name>", Desc: "Only join sessions from this project"},
+		{Name: "--version <label>", Desc: "Section label (default: <to-ref>, or Unreleased for HEAD)"},
+	},
+	Description: `Lists the non-merge commits in the range (from the current directory's
+repository), joins each one to the sessions whose recorded commits
+match it, and renders a Keep-a-Changelog section to stdout.
+
+Entries are grouped by conventional-commit intent: the commit subject's
+prefix when present, otherwise the intent inferred from the linked
+session's activity (the same inference used for session summaries).
+
+  feat                          Added
+  fix                           Fixed
+  refactor, perf, docs, ...     Changed
+
+Each entry links to its session note and lists up to three decisions
+from the linked sessions.`,
+	Examples: []string{
+		"vv changelog v1.2.0..v1.3.0",
+		"vv changelog v1.2.0..            Unreleased changes since v1.2.0",
+		"vv changelog v1.2.0..HEAD --version 1.3.0 >> CHANGELOG.md",
+	},
+	SeeAlso: []string{"vv(1)", "vv-pr-describe(1)"},
+}
+
+var CmdAdr = Command{
+	Name:       "adr",
+	Synopsis:   "architecture decision records from session decisions",
+	Brief:      "Promote session decisions to Architecture Decision Records",
+	Usage:      "vv adr <list | promote | supersede> [--project <name>]",
+	TableUsage: "vv adr [list | ...]",
+	Description: `Session decisions decay out of history.md after a few weeks. An ADR
+keeps a significant one permanently at
+Projects/<project>/decisions/NNNN-slug.md, with a status (proposed,
+accepted, superseded), links to the sessions that made it, and a
+supersede chain. Live ADRs are listed under "Architecture Decisions"
+in history.md.
+
+Subcommands:
+  vv adr list [--candidates]          List ADRs, or decisions worth promoting
+  vv adr promote "<decision>"         Record a decision as an ADR
+  vv adr supersede <n> "<decision>"   Replace ADR n with a new decision
+
+The project defaults to the one detected from the current directory.`,
+	SeeAlso: []string{"vv(1)", "vv-adr-list(1)", "vv-adr-promote(1)", "vv-adr-supersede(1)"},
+}
+
+var CmdAdrList = Command{
+	Name:     "adr list",
+	Synopsis: "list a project's ADRs or promotion candidates",
+	Brief:    "List ADRs or promotion candidates",
+	Usage:    "vv adr list [--project <name>] [--candidates] [--json]",
+	Flags: []Flag{
+		{Name: "--project <name>", Desc: "Project (default: detected from the current directory)"},
+		{Name: "--candidates", Desc: "List session decisions worth promoting instead"},
+		{Name: "--json", Desc: "Emit JSON"},
+	},
+	Description: `Lists ADRs with their status. With --candidates, lists session
+decisions that are marked [permanent] or [core], or that a later
+session's decisions or summary refer back to. Decisions already covered
+by a live ADR (two or more shared significant words) are left out.`,
+	SeeAlso: []string{"vv(1)", "vv-adr(1)"},
+}
+
+var CmdAdrPromote = Command{
+	Name:     "adr promote",
+	Synopsis: "record a session decision as an ADR",
+	Brief:    "Record a decision as an ADR",
+	Usage:    "vv adr promote (\"<decision>\" | <number> | --auto) [--project <name>]",
+	Flags: []Flag{
+		{Name: "--project <name>", Desc: "Project (default: detected from the current directory)"},
+		{Name: "--status <s>", Desc: "Initial status: accepted (default) or proposed"},
+		{Name: "--session <note>", Desc: "Source session note name (repeatable)"},
+		{Name: "--auto", Desc: "Propose an ADR for every candidate (see vv adr list --candidates)"},
+		{Name: "--dry-run", Desc: "With --auto, show what would be proposed"},
+	},
+	Description: `Writes the next-numbered ADR. Enrichment's "Decision — rationale"
+form is split into Decision and Rationale sections. When a live ADR
+already shares two or more significant words with the decision, the
+source sessions are added to it instead of creating a duplicate.
+
+A bare number accepts an existing proposed ADR. --auto creates a
+proposed ADR for each candidate, linked to every session that made or
+referenced it.`,
+	Examples: []string{
+		"vv adr promote \"Use JWT over sessions — stateless scaling\"",
+		"vv adr promote --auto --dry-run",
+		"vv adr promote 4                  Accept proposed ADR-0004",
+	},
+	SeeAlso: []string{"vv(1)", "vv-adr(1)", "vv-adr-supersede(1)"},
+}
+
+var CmdAdrSupersede = Command{
+	Name:     "adr supersede",
+	Synopsis: "replace an ADR with a newer decision",
+	Brief:    "Replace an ADR with a newer decision",
+	Usage:    "vv adr supersede <number> (\"<decision>\" | --by <number>) [--project <name>]",
+	Args: []Arg{
+		{Name: "number", Desc: "The ADR being replaced"},
+	},
+	Flags: []Flag{
+		{Name: "--project <name>", Desc: "Project (default: detected from the current directory)"},
+		{Name: "--by <number>", Desc: "Link an existing ADR as the replacement"},
+		{Name: "--session <note>", Desc: "Source session note name (repeatable)"},
+	},
+	Description: `Marks the ADR superseded and links it to its replacement: either a
+new ADR recording the given decision, or an existing one (--by). Both
+records carry the link (supersedes / superseded_by). Hand-written
+sections of the old ADR are kept.`,
+	Examples: []string{
+		"vv adr supersede 2 \"Use Postgres instead of SQLite\"",
+		"vv adr supersede 2 --by 5",
+	},
+	SeeAlso: []string{"vv(1)", "vv-adr(1)"},
+}
+
+var CmdFiles = Command{
+	Name:       "files",
+	Synopsis:   "per-file session history",
+	Brief:      "Show the session history of a file",
+	Usage:      "vv files dossier <path> [--project <name>] [--write] [--json]",
+	TableUsage: "vv files dossier <path>",
+	Description: `Answers "who touched this file, why, and what was decided" from the
+session index.
+
+Subcommands:
+  vv files dossier <path>     Sessions, commits, decisions, and churn for a file
+
+The project defaults to the one detected from the current directory.`,
+	SeeAlso: []string{"vv(1)", "vv-files-dossier(1)"},
+}
+
+var CmdFilesDossier = Command{
+	Name:     "files dossier",
+	Synopsis: "show every session that changed a file",
+	Brief:    "Show sessions, decisions, and churn for a file",
+	Usage:    "vv files dossier <path> [--project <name>] [--write] [--json]",
+	Args: []Arg{
+		{Name: "path", Desc: "File path, project-relative or absolute; a trailing part such as index/context.go is enough"},
+	},
+	Flags: []Flag{
+		{Name: "--project <name>", Desc: "Project (default: detected from the current directory)"},
+		{Name: "--write", Desc: "Also write the dossier note to Projects/<project>/files/<path>.md"},
+		{Name: "--json", Desc: "Emit JSON"},
+	},
+	Description: `Collects every indexed session whose files changed include the path,
+oldest first, and shows:
+
+  Sessions       Note, summary, and recorded commit SHAs
+  Decisions      Made in those sessions, or naming the file elsewhere
+  Open threads   Likewise, minus threads a later decision resolved
+  Churn          Sessions and commits per month
+
+--write renders the same dossier as a vault note, in the configured
+vault flavor; re-run it to refresh. The vv_get_file_history MCP tool
+returns the dossier as JSON.`,
+	Examples: []string{
+		"vv files dossier internal/index/context.go",
+		"vv files dossier context.go --json",
+		"vv files dossier cmd/vv/main.go --write",
+	},
+	SeeAlso: []string{"vv(1)", "vv-files(1)", "vv-stats(1)"},
+}
+
 var CmdFlowdoc = Command{
 	Name:       "flowdoc",
 	Synopsis:   "generate and verify the flowdoc graph for the current project",
@@ -451,12 +873,13 @@ var CmdMcp = Command{
 	Synopsis:   "start MCP server for AI agent integration",
 	Brief:      "Start MCP server (JSON-RPC over stdio)",
 	TableUsage: "vv mcp [install | ...]",
-	Usage:      "vv mcp\n    vv mcp install [--claude-only | --zed-only]\n    vv mcp uninstall [--claude-only | --zed-only]",
+	Usage:      "vv mcp\n    vv mcp serve [--http ADDR]\n    vv mcp install [--claude-only | --zed-only] [--url URL]\n    vv mcp uninstall [--claude-only | --zed-only]",
 	Description: `Starts a Model Context Protocol (MCP) server that exposes vibe-vault
 tools over JSON-RPC 2.0 on stdin/stdout. This allows AI agents like
 Claude Code and Zed to query project context programmatically.
 
 Subcommands:
+  serve       Serve over stdio, or one shared server over HTTP
   install     Register the MCP server in editor settings
   uninstall   Remove the MCP server from editor settings
 
@@ -493,18 +916,47 @@ vibe-vault projects" or "capture this session". The agent will
 call the MCP tools automatically.
 
 The server logs tool calls to stderr for observability.`,
-	SeeAlso: []string{"vv(1)", "vv-inject(1)", "vv-mcp-install(1)", "vv-mcp-uninstall(1)"},
+	SeeAlso: []string{"vv(1)", "vv-inject(1)", "vv-mcp-serve(1)", "vv-mcp-install(1)", "vv-mcp-uninstall(1)"},
+}
+
+var CmdMcpServe = Command{
+	Name:     "mcp serve",
+	Synopsis: "serve MCP over stdio or Streamable HTTP",
+	Brief:    "Run one MCP server shared by every editor over HTTP",
+	Usage:    "vv mcp serve [--http ADDR] [--debug]",
+	Flags: []Flag{
+		{Name: "--http ADDR", Desc: "Listen on ADDR (e.g. 127.0.0.1:8765) instead of stdio"},
+		{Name: "--debug", Desc: "Log every JSON-RPC message to stderr"},
+	},
+	Description: `Without --http this is "vv mcp": one server per editor, over stdio.
+
+With --http, one long-running server speaks the MCP Streamable HTTP
+transport at http://ADDR/mcp, so every editor shares one process and
+one loaded index. Clients POST JSON-RPC requests and hold a GET open as
+a server-sent event stream for notifications such as resource updates.
+Each client's initialize starts a session named by the Mcp-Session-Id
+header; DELETE ends it.
+
+Clients must send "Authorization: Bearer <token>" when [mcp] http_token
+is set in config.toml. Without a token the server refuses to listen on
+anything but a loopback address. Browser requests from non-local origins
+are rejected.
+
+Register the URL form with:
+  vv mcp install --url http://127.0.0.1:8765/mcp`,
+	SeeAlso: []string{"vv-mcp(1)", "vv-mcp-install(1)"},
 }
 
 var CmdMcpInstall = Command{
 	Name:     "mcp install",
 	Synopsis: "register MCP server in editor settings",
 	Brief:    "Add vibe-vault MCP server to settings.json",
-	Usage:    "vv mcp install [--claude-only | --zed-only | --claude-plugin]",
+	Usage:    "vv mcp install [--claude-only | --zed-only | --claude-plugin] [--url URL]",
 	Flags: []Flag{
 		{Name: "--claude-only", Desc: "Install only into Claude Code (~/.claude/settings.json)"},
 		{Name: "--zed-only", Desc: "Install only into Zed (~/.config/zed/settings.json)"},
 		{Name: "--claude-plugin", Desc: "Deploy as Claude Code plugin (fixes tool registration bug #2682)"},
+		{Name: "--url URL", Desc: "Register a running vv mcp serve --http server instead of stdio"},
 	},
 	Description: `Adds a "vibe-vault" entry to each detected editor's MCP/context server
 settings. By default, installs into all editors whose config directories
@@ -519,13 +971,18 @@ settings.json.vv.bak before any modification.
 This command is idempotent: running it when the MCP server is already
 configured prints an informational message and exits successfully.
 
+Use --url to point editors at a shared "vv mcp serve --http" server. The
+entry carries the URL and, when [mcp] http_token is set, an Authorization
+header with the token. Installing again with or without --url switches
+the entry between the two forms.
+
 Use --claude-plugin if Claude Code does not expose vibe-vault tools after
 a standard install. This deploys vibe-vault as a local plugin, which uses
 a different (working) code path for tool registration. Both plugin and
 mcpServers entries can safely coexist.
 
 Restart the editor after running this command.`,
-	SeeAlso: []string{"vv-mcp(1)", "vv-mcp-uninstall(1)"},
+	SeeAlso: []string{"vv-mcp(1)", "vv-mcp-serve(1)", "vv-mcp-uninstall(1)"},
 }
 
 var CmdMcpUninstall = Command{
@@ -554,22 +1011,159 @@ configured prints an informational message and exits successfully.`,
 	SeeAlso: []string{"vv-mcp(1)", "vv-mcp-install(1)"},
 }
 
+var CmdLlm = Command{
+	Name:       "llm",
+	Synopsis:   "inspect and manage LLM provider state",
+	Brief:      "Inspect the LLM response cache and usage ledger",
+	Usage:      "vv llm <cache | usage> [...]",
+	TableUsage: "vv llm cache|usage [...]",
+	Description: `Enrichment, synthesis, and pr-describe --llm responses are cached on
+disk, keyed by a SHA-256 of provider, model, system prompt, user
+prompt, temperature, JSON mode, and token cap. An unchanged transcript
+re-sent by vv reprocess is answered from the cache without an API call.
+
+Every LLM call vv makes, cached or not, is also appended to a usage
+ledger at <vault>/.vibe-vault/llm-usage.jsonl with its provider, model,
+token counts, purpose, project, and session.
+
+Subcommands:
+  vv llm cache stats    Entry count, size, and per-model breakdown
+  vv llm cache clear    Delete every cached response
+  vv llm usage          Token and cost totals per provider and purpose
+
+Configured under [llm] in config.toml:
+
+  cache = true          Enable the cache (default)
+  cache_max_mb = 256    Size limit; least recently used entries go first
+
+Pass --no-cache to vv process, vv reprocess, or vv pr-describe --llm to
+bypass it for one run.`,
+	SeeAlso: []string{"vv(1)", "vv-llm-cache(1)", "vv-llm-usage(1)", "vv-reprocess(1)"},
+}
+
+var CmdLlmCache = Command{
+	Name:     "llm cache",
+	Synopsis: "show or clear the LLM response cache",
+	Brief:    "Show or clear the LLM response cache",
+	Usage:    "vv llm cache <stats | clear> [--json]",
+	Flags: []Flag{
+		{Name: "--json", Desc: "Emit stats as JSON"},
+	},
+	Description: `stats walks <vault>/.vibe-vault/llm-cache and reports the number of
+cached responses, their total size against cache_max_mb, the oldest and
+newest entries, and entries per provider/model. clear deletes them all.`,
+	Examples: []string{
+		"vv llm cache stats",
+		"vv llm cache clear",
+	},
+	SeeAlso: []string{"vv(1)", "vv-llm(1)"},
+}
+
+var CmdLlmUsage = Command{
+	Name:     "llm usage",
+	Synopsis: "report vv's own LLM token usage and cost",
+	Brief:    "Report vv's own LLM token usage and cost",
+	Usage:    "vv llm usage [--since YYYY-MM-DD] [--json]",
+	Flags: []Flag{
+		{Name: "--since <YYYY-MM-DD>", Desc: "Only count calls on or after this date"},
+		{Name: "--json", Desc: "Emit the summary as JSON"},
+	},
+	Description: `Reads the usage ledger and totals calls, cache hits, and billed input
+and output tokens per provider/model and per purpose (enrichment,
+synthesis, pr-describe, flowdoc, eval). Cache hits count as calls but
+add no tokens or cost. When [pricing] is enabled, costs are estimated
+with the same model patterns as session costs.`,
+	Examples: []string{
+		"vv llm usage",
+		"vv llm usage --since 2026-10-01",
+	},
+	SeeAlso: []string{"vv(1)", "vv-llm(1)", "vv-stats(1)"},
+}
+
+var CmdEnrich = Command{
+	Name:       "enrich",
+	Synopsis:   "evaluate the enrichment and synthesis prompts",
+	Brief:      "Evaluate enrichment prompts against golden transcripts",
+	Usage:      "vv enrich <eval> [...]",
+	TableUsage: "vv enrich eval <dir> [...]",
+	Description: `Tools for working on the prompts behind session enrichment and
+synthesis.
+
+Subcommands:
+  vv enrich eval <dir>   Score the prompts over golden transcripts`,
+	Examples: []string{
+		"vv enrich eval testdata/eval",
+	},
+	SeeAlso: []string{"vv(1)", "vv-enrich-eval(1)", "vv-llm(1)"},
+}
+
+var CmdEnrichEval = Command{
+	Name:     "enrich eval",
+	Synopsis: "score enrichment and synthesis prompts over golden transcripts",
+	Brief:    "Score enrichment and synthesis prompts over golden transcripts",
+	Usage:    "vv enrich eval <fixtures-dir> [--record] [--baseline <file>] [--out <file>] [--json]",
+	Flags: []Flag{
+		{Name: "--record", Desc: "Send unrecorded requests to the configured provider and save them"},
+		{Name: "--baseline <file>", Desc: "Diff against a report saved from an earlier prompt revision"},
+		{Name: "--out <file>", Desc: "Write the report as JSON for use as a later baseline"},
+		{Name: "--json", Desc: "Emit the report (and diff) as JSON"},
+	},
+	Description: `Runs the production enrichment prompt — and, for cases that expect
+learnings, the synthesis prompt — over each case directory under
+<fixtures-dir>:
+
+  <case>/transcript.jsonl   Session transcript (required)
+  <case>/expected.json      Expectations (required)
+  <case>/knowledge.md       Synthesis context (optional)
+  <case>/resume.md          Synthesis context (optional)
+  <case>/cassette.json      Recorded LLM interactions
+
+expected.json declares what a good summary captures; every field is
+optional:
+
+  {"tag": "debugging",
+   "decisions": ["retry backoff", "circuit breaker"],
+   "summary": {"max_sentences": 3, "max_chars": 400, "must_mention": ["timeout"]},
+   "learnings": ["breaker state"]}
+
+Decisions, learnings, and must_mention entries are keyword phrases: a
+phrase matches when each of its words appears in one produced item,
+case-insensitively. Cases are scored on tag accuracy, decision and
+learning recall, and the summary rules (non-empty, at most 3 sentences
+unless overridden).
+
+LLM calls are served from each case's cassette. Replay is the default:
+it is offline and deterministic, and a request missing from the
+cassette fails the case. After changing a prompt, re-record with
+--record (misses go to the configured enrichment provider, bypassing
+the response cache), then compare with --baseline against the report
+saved by --out before the change. The report carries a fingerprint of
+each prompt so the diff shows which revision produced it. Exits 1 when
+any case fails to run.`,
+	Examples: []string{
+		"vv enrich eval testdata/eval --out /tmp/before.json",
+		"vv enrich eval testdata/eval --record --baseline /tmp/before.json",
+	},
+	SeeAlso: []string{"vv(1)", "vv-enrich(1)", "vv-llm-usage(1)"},
+}
+
 var CmdConfig = Command{
 	Name:       "config",
 	Synopsis:   "manage vibe-vault configuration",
 	Brief:      "Manage configuration (provider keys, etc.)",
-	Usage:      "vv config [set-key]",
+	Usage:      "vv config [set-key|gen-encryption-key]",
 	TableUsage: "vv config [set-key | ...]",
 	Description: `Manages settings stored in ~/.config/vibe-vault/config.toml.
 
 Subcommands:
-  set-key   Store a per-provider API key (anthropic, openai, google)
+  set-key              Store a per-provider API key (anthropic, openai, google)
+  gen-encryption-key   Create a key file for [encryption]
 
 Hook enrichment and session synthesis resolve provider keys via a
 layered lookup: the value in config.toml wins, falling back to the
 provider's environment variable (ANTHROPIC_API_KEY / OPENAI_API_KEY /
 GOOGLE_API_KEY) for operators who already have shell-env-based setup.`,
-	SeeAlso: []string{"vv(1)", "vv-config-set-key(1)"},
+	SeeAlso: []string{"vv(1)", "vv-config-set-key(1)", "vv-config-gen-encryption-key(1)"},
 }
 
 var CmdConfigSetKey = Command{
@@ -609,6 +1203,63 @@ config slot is empty.`,
 	SeeAlso: []string{"vv(1)", "vv-config(1)"},
 }
 
+var CmdConfigGenEncryptionKey = Command{
+	Name:     "config gen-encryption-key",
+	Synopsis: "create an encryption key file",
+	Brief:    "Create a key file for archive and note encryption",
+	Usage:    "vv config gen-encryption-key [<path>]",
+	Args: []Arg{
+		{Name: "path", Desc: "Where to write the key (default ~/.config/vibe-vault/vault.key)"},
+	},
+	Description: `Writes a random 32-byte AES-256 key, hex-encoded, with mode 0600, and
+prints the [encryption] lines to add to config.toml. Refuses to replace
+an existing file: archives and notes sealed with the old key would
+become unreadable.
+
+Keep a copy of the key outside the vault. Anyone with the vault's git
+remote but not the key sees only ciphertext; losing the key loses the
+encrypted data.`,
+	Examples: []string{
+		"vv config gen-encryption-key",
+	},
+	SeeAlso: []string{"vv(1)", "vv-config(1)", "vv-decrypt(1)"},
+}
+
+var CmdDecrypt = Command{
+	Name:     "decrypt",
+	Synopsis: "print the plaintext of an encrypted note or archive",
+	Brief:    "Print an encrypted note or archive",
+	Usage:    "vv decrypt <file>",
+	Args: []Arg{
+		{Name: "file", Desc: "Encrypted session note (stub or .md.enc payload), .jsonl.zst archive, or other sealed file"},
+	},
+	Description: `Encryption at rest is configured under [encryption] in config.toml:
+
+  [encryption]
+  key_file = "~/.config/vibe-vault/vault.key"   # or passphrase_env = "VV_PASSPHRASE"
+  archives = true                                # seal new transcript archives
+  note_domains = ["work"]                        # seal session notes in these domains
+
+A project opts its notes in with notes = true under [encryption] in
+Projects/<project>/agentctx/config.toml. Files are sealed with AES-256-GCM;
+a passphrase is stretched with PBKDF2-SHA256 (600,000 iterations).
+
+An encrypted session note is written as a payload, <note>.md.enc, plus
+a stub at the note's path that Obsidian shows: it keeps the identifying
+frontmatter (date, project, domain, tags, ...) and points at the
+payload. Summaries, decisions and prose stay in the payload. The local
+session index and generated context documents are not encrypted.
+
+vv archive, reprocess and backfill decrypt transparently when the key is
+available; vv check warns when it is not. vv decrypt prints a file's
+plaintext to stdout — archives decompressed, notes as markdown.`,
+	Examples: []string{
+		"vv decrypt Projects/acme/sessions/2026-03-08-01.md",
+		"vv decrypt ~/vault/.vibe-vault/archive/<session-id>.jsonl.zst | jq .",
+	},
+	SeeAlso: []string{"vv(1)", "vv-config-gen-encryption-key(1)", "vv-archive(1)", "vv-check(1)"},
+}
+
 var CmdVersion = Command{
 	Name:     "version",
 	Synopsis: "print version",
@@ -842,7 +1493,10 @@ Subcommands:
   vv templates list              Show all templates with status
   vv templates diff [--file X]   Unified diff of vault vs defaults
   vv templates show <name>       Print built-in default to stdout
-  vv templates reset [--file X]  Reset templates to defaults`,
+  vv templates reset [--file X]  Reset templates to defaults
+
+Customizing session-note.tmpl changes the layout of auto-generated
+session notes; see Templates/README.md in the vault for its fields.`,
 	SeeAlso: []string{"vv(1)", "vv-context-init(1)"},
 }
 
@@ -1299,6 +1953,101 @@ var StagingSubcommands = []Command{
 	CmdStagingMigrate,
 }
 
+var CmdDaemon = Command{
+	Name:       "daemon",
+	Synopsis:   "supervise session sources and maintenance jobs",
+	Brief:      "Run capture sources and maintenance jobs in one long-lived process",
+	Usage:      "vv daemon [status|run|install|uninstall]",
+	TableUsage: "vv daemon [...]",
+	Description: `Runs in the foreground until SIGTERM or SIGINT. Starts every enabled
+session source (the Zed agent-panel watcher when threads.db exists; the
+Claude Code hooks need no process) and runs maintenance jobs on the
+intervals set under [daemon] in config.toml, one job at a time:
+
+  archive       vv archive               archive_minutes (default 60)
+  index         vv index                 index_minutes (default 360)
+  sync-sessions vv vault sync-sessions   sync_minutes (default 30)
+  worktree-gc   vv worktree gc           worktree_gc_minutes (default 60),
+                                         over worktree_repos only
+
+0 disables a job. Each job first runs a minute after start. Status is
+served on a unix socket ($XDG_RUNTIME_DIR/vibe-vault/daemon.sock unless
+[daemon] socket is set). SIGHUP re-reads config.toml and restarts the
+sources and schedule; job history is kept. While the daemon runs,
+vv mcp leaves Zed auto-capture to it.
+
+Subcommands:
+  vv daemon status      Show sources, jobs, and last results
+  vv daemon run <job>   Run a job now
+  vv daemon install     Write a systemd user unit
+  vv daemon uninstall   Remove the systemd user unit`,
+	Examples: []string{
+		"vv daemon",
+		"vv daemon install && systemctl --user enable --now vibe-vault",
+		"systemctl --user reload vibe-vault   # after editing config.toml",
+	},
+	SeeAlso: []string{"vv(1)", "vv-daemon-status(1)", "vv-zed(1)", "vv-archive(1)", "vv-index(1)", "vv-worktree-gc(1)"},
+}
+
+var CmdDaemonStatus = Command{
+	Name:     "daemon status",
+	Synopsis: "show daemon sources and jobs",
+	Brief:    "Show the running daemon's sources, jobs, and last results",
+	Usage:    "vv daemon status [--json]",
+	Flags: []Flag{
+		{Name: "--json", Desc: "Print the status as JSON"},
+	},
+	Description: `Asks the running daemon over its unix socket for each session source's
+state and each job's interval, last run, result or error, and next run.
+Exits 1 when no daemon is running.`,
+	SeeAlso: []string{"vv(1)", "vv-daemon(1)"},
+}
+
+var CmdDaemonRun = Command{
+	Name:     "daemon run",
+	Synopsis: "run a daemon job now",
+	Brief:    "Queue a maintenance job to run now in the daemon",
+	Usage:    "vv daemon run <job>",
+	Args: []Arg{
+		{Name: "<job>", Desc: "archive, index, sync-sessions, or worktree-gc"},
+	},
+	Description: `Queues the job in the running daemon and returns; vv daemon status
+shows the result. The job's next scheduled run moves to one interval
+after this one. Disabled jobs cannot be run.`,
+	Examples: []string{"vv daemon run archive"},
+	SeeAlso:  []string{"vv(1)", "vv-daemon(1)", "vv-daemon-status(1)"},
+}
+
+var CmdDaemonInstall = Command{
+	Name:     "daemon install",
+	Synopsis: "write a systemd user unit for the daemon",
+	Brief:    "Write a systemd user unit that runs vv daemon",
+	Usage:    "vv daemon install [--print]",
+	Flags: []Flag{
+		{Name: "--print", Desc: "Print the unit instead of writing it"},
+	},
+	Description: `Writes ~/.config/systemd/user/vibe-vault.service running this vv
+binary as "vv daemon", with reload mapped to SIGHUP. systemd does not
+pass on your shell's environment: put provider API keys for enrichment
+in ~/.config/vibe-vault/daemon.env as KEY=value lines.
+
+Enable it with:
+  systemctl --user daemon-reload
+  systemctl --user enable --now vibe-vault`,
+	SeeAlso: []string{"vv(1)", "vv-daemon(1)", "vv-daemon-uninstall(1)"},
+}
+
+var CmdDaemonUninstall = Command{
+	Name:     "daemon uninstall",
+	Synopsis: "remove the daemon's systemd user unit",
+	Brief:    "Remove the systemd user unit written by vv daemon install",
+	Usage:    "vv daemon uninstall",
+	Description: `Removes ~/.config/systemd/user/vibe-vault.service. Stop and disable
+the service first:
+  systemctl --user disable --now vibe-vault`,
+	SeeAlso: []string{"vv(1)", "vv-daemon(1)", "vv-daemon-install(1)"},
+}
+
 var CmdWorktree = Command{
 	Name:       "worktree",
 	Synopsis:   "subagent worktree management",
@@ -1352,6 +2101,18 @@ var WorktreeSubcommands = []Command{
 	CmdWorktreeGc,
 }
 
+// AdrSubcommands is the ordered list of adr sub-subcommands.
+var AdrSubcommands = []Command{
+	CmdAdrList,
+	CmdAdrPromote,
+	CmdAdrSupersede,
+}
+
+// FilesSubcommands is the ordered list of files sub-subcommands.
+var FilesSubcommands = []Command{
+	CmdFilesDossier,
+}
+
 // ZedSubcommands is the ordered list of zed sub-subcommands.
 var ZedSubcommands = []Command{
 	CmdZedBackfill,
@@ -1387,13 +2148,43 @@ var ContextSubcommands = []Command{
 
 // McpSubcommands is the ordered list of mcp sub-subcommands.
 var McpSubcommands = []Command{
+	CmdMcpServe,
 	CmdMcpInstall,
 	CmdMcpUninstall,
 }
 
+// LlmSubcommands is the ordered list of llm sub-subcommands.
+var LlmSubcommands = []Command{
+	CmdLlmCache,
+	CmdLlmUsage,
+}
+
+// DaemonSubcommands is the ordered list of daemon sub-subcommands.
+var DaemonSubcommands = []Command{
+	CmdDaemonStatus,
+	CmdDaemonRun,
+	CmdDaemonInstall,
+	CmdDaemonUninstall,
+}
+
+// ArchiveSubcommands is the ordered list of archive sub-subcommands.
+var ArchiveSubcommands = []Command{
+	CmdArchiveTrainDict,
+	CmdArchivePack,
+	CmdArchiveVerify,
+	CmdArchiveRestore,
+	CmdArchivePrune,
+}
+
+// EnrichSubcommands is the ordered list of enrich sub-subcommands.
+var EnrichSubcommands = []Command{
+	CmdEnrichEval,
+}
+
 // ConfigSubcommands is the ordered list of config sub-subcommands.
 var ConfigSubcommands = []Command{
 	CmdConfigSetKey,
+	CmdConfigGenEncryptionKey,
 }
 
 // Subcommands is the ordered list of all subcommands.
@@ -1405,6 +2196,7 @@ var Subcommands = []Command{
 	CmdIndex,
 	CmdBackfill,
 	CmdArchive,
+	CmdDecrypt,
 	CmdReprocess,
 	CmdCheck,
 	CmdStats,
@@ -1413,14 +2205,21 @@ var Subcommands = []Command{
 	CmdInject,
 	CmdExport,
 	CmdEffectiveness,
+	CmdPrDescribe,
+	CmdChangelog,
+	CmdAdr,
+	CmdFiles,
 	CmdFlowdoc,
 	CmdMemory,
 	CmdVault,
 	CmdStaging,
 	CmdZed,
 	CmdMcp,
+	CmdDaemon,
 	CmdWorktree,
 	CmdConfig,
+	CmdLlm,
+	CmdEnrich,
 	CmdCommand,
 	CmdTemplates,
 	CmdVersion,
@@ -11,23 +11,31 @@ import (
 var expectedTerminal = map[string]string{
 	"init": "vv init \u2014 create a new Obsidian vault for session notes\n" +
 		"\n" +
-		"Usage: vv init [path] [--git]\n" +
+		"Usage: vv init [path] [--git] [--flavor <name>]\n" +
 		"\n" +
 		"Arguments:\n" +
-		"  path       Target directory (default: ./vibe-vault)\n" +
+		"  path              Target directory (default: ./vibe-vault)\n" +
 		"\n" +
 		"Flags:\n" +
-		"  --git      Initialize a git repository in the new vault\n" +
+		"  --git             Initialize a git repository in the new vault\n" +
+		"  --flavor <name>   Vault flavor: obsidian (default), logseq, or markdown\n" +
 		"\n" +
 		"Creates a fully configured Obsidian vault with Dataview dashboards,\n" +
 		"Templater templates, and session capture infrastructure. Also writes\n" +
 		"a default config to ~/.config/vibe-vault/config.toml pointing at the\n" +
 		"new vault.\n" +
 		"\n" +
+		"--flavor logseq writes Logseq query dashboards and notes with key:: value\n" +
+		"page properties; --flavor markdown writes plain notes with relative links.\n" +
+		"Run against an existing vault, --flavor switches its dashboards (old\n" +
+		"ones move to _archive/flavor-backup/) and records vault_flavor in the\n" +
+		"config; follow with vv reprocess to re-render existing notes.\n" +
+		"\n" +
 		"Examples:\n" +
 		"  vv init                       Create ./vibe-vault\n" +
 		"  vv init ~/obsidian/my-vault   Create at a specific path\n" +
-		"  vv init --git                 Create with git repo initialized\n",
+		"  vv init --git                 Create with git repo initialized\n" +
+		"  vv init --flavor logseq       Create a Logseq graph\n",
 
 	"hook": "vv hook \u2014 Claude Code hook handler\n" +
 		"\n" +
@@ -54,11 +62,14 @@ var expectedTerminal = map[string]string{
 
 	"process": "vv process \u2014 process a single transcript file\n" +
 		"\n" +
-		"Usage: vv process <transcript.jsonl>\n" +
+		"Usage: vv process <transcript.jsonl> [--no-cache]\n" +
 		"\n" +
 		"Arguments:\n" +
 		"  transcript.jsonl   Path to a Claude Code JSONL transcript\n" +
 		"\n" +
+		"Flags:\n" +
+		"  --no-cache         Bypass the LLM response cache\n" +
+		"\n" +
 		"Parses the transcript, detects the project from the session's working\n" +
 		"directory, and writes a session note to the vault. Skips trivial\n" +
 		"sessions (< 2 messages) and already-indexed sessions.\n" +
@@ -98,43 +109,127 @@ var expectedTerminal = map[string]string{
 
 	"backfill": "vv backfill \u2014 discover and process historical transcripts\n" +
 		"\n" +
-		"Usage: vv backfill [path]\n" +
+		"Usage: vv backfill [path] [--workers N] [--llm-concurrency N] [--no-enrich] [--json]\n" +
 		"\n" +
 		"Arguments:\n" +
-		"  path   Directory to scan for transcripts (default: ~/.claude/projects/)\n" +
+		"  path                    Directory to scan for transcripts (default: ~/.claude/projects/)\n" +
+		"\n" +
+		"Flags:\n" +
+		"  --workers <N>           Transcripts parsed and extracted in parallel (default: CPUs, at most 8)\n" +
+		"  --llm-concurrency <N>   Enrichment calls in flight at once (default: 2)\n" +
+		"  --no-enrich             Write heuristic notes without LLM enrichment\n" +
+		"  --json                  Emit the final report as JSON\n" +
 		"\n" +
 		"Recursively discovers Claude Code JSONL transcripts by UUID filename\n" +
 		"pattern, skips already-indexed sessions, and processes the rest through\n" +
 		"the full capture pipeline. Also patches TranscriptPath on existing index\n" +
 		"entries that lack it.\n" +
 		"\n" +
+		"A pool of workers parses transcripts and runs LLM enrichment (when\n" +
+		"[enrichment] is enabled) in parallel; a single writer commits notes in\n" +
+		"discovery order, taking the index lock per small batch so hook captures\n" +
+		"are not blocked for the whole run. A progress line with an ETA is drawn\n" +
+		"on stderr.\n" +
+		"\n" +
+		"The index is saved after every batch, and already-indexed sessions are\n" +
+		"skipped before they are parsed, so Ctrl-C (which commits what is already\n" +
+		"prepared and stops) loses little: running the same command again resumes\n" +
+		"from the index. Trivial sessions are not indexed, so one still in progress\n" +
+		"is captured later. Transcripts that fail to parse or capture are listed in\n" +
+		"the final report and retried on the next run.\n" +
+		"Exits 1 when interrupted or when any transcript failed.\n" +
+		"\n" +
 		"Subagent transcripts (in /subagents/ subdirectories) are automatically\n" +
 		"filtered out.\n" +
 		"\n" +
 		"Examples:\n" +
 		"  vv backfill                              Scan default Claude projects dir\n" +
-		"  vv backfill ~/.claude/projects/myproj    Scan a specific directory\n",
+		"  vv backfill ~/.claude/projects/myproj    Scan a specific directory\n" +
+		"  vv backfill --workers 4 --llm-concurrency 1\n",
 
 	"archive": "vv archive \u2014 compress transcripts into vault archive\n" +
 		"\n" +
-		"Usage: vv archive\n" +
+		"Usage: vv archive [train-dict|pack|verify|restore|prune] [--level <n>] [--no-dict]\n" +
+		"\n" +
+		"Flags:\n" +
+		"  --level <n>   zstd compression level, 1-22 (default 3)\n" +
+		"  --no-dict     Compress without the trained dictionary\n" +
 		"\n" +
 		"Iterates all sessions in the index and compresses each transcript to\n" +
 		".vibe-vault/archive/{session-id}.jsonl.zst using zstd compression\n" +
 		"(typically ~10:1 on JSONL). Skips already-archived and missing\n" +
 		"transcripts. Originals are not deleted.\n" +
 		"\n" +
-		"Reports total bytes before and after compression.\n",
+		"When vv archive train-dict has built a dictionary, new archives are\n" +
+		"compressed with the newest one. Its ID is recorded in each archive's\n" +
+		"zstd frame header, and decompression (vv reprocess, train-dict) picks\n" +
+		"the matching version from .vibe-vault/archive/dicts/.\n" +
+		"\n" +
+		"With pack = true under [archive] in config.toml, archives are appended\n" +
+		"to a monthly pack file, .vibe-vault/archive/packs/YYYY-MM.pack, as\n" +
+		"independently decompressible zstd frames, and an offset index\n" +
+		"(YYYY-MM.idx) records where each session's frame lives. Reading is\n" +
+		"transparent: reprocess and backfill find packed and loose archives\n" +
+		"alike.\n" +
+		"\n" +
+		"Subcommands:\n" +
+		"  vv archive train-dict   Train a zstd dictionary from recent transcripts\n" +
+		"  vv archive pack         Move loose archives into monthly pack files\n" +
+		"  vv archive verify       Decode every archive and check frame checksums\n" +
+		"  vv archive restore      Restore an archived transcript for claude --resume\n" +
+		"  vv archive prune        Apply the [archive] retention policy\n" +
+		"\n" +
+		"Reports total bytes before and after compression.\n" +
+		"\n" +
+		"Examples:\n" +
+		"  vv archive\n" +
+		"  vv archive --level 19\n",
+	"decrypt": "vv decrypt \u2014 print the plaintext of an encrypted note or archive\n" +
+		"\n" +
+		"Usage: vv decrypt <file>\n" +
+		"\n" +
+		"Arguments:\n" +
+		"  file   Encrypted session note (stub or .md.enc payload), .jsonl.zst archive, or other sealed file\n" +
+		"\n" +
+		"Encryption at rest is configured under [encryption] in config.toml:\n" +
+		"\n" +
+		"  [encryption]\n" +
+		"  key_file = \"~/.config/vibe-vault/vault.key\"   # or passphrase_env = \"VV_PASSPHRASE\"\n" +
+		"  archives = true                                # seal new transcript archives\n" +
+		"  note_domains = [\"work\"]                        # seal session notes in these domains\n" +
+		"\n" +
+		"A project opts its notes in with notes = true under [encryption] in\n" +
+		"Projects/<project>/agentctx/config.toml. Files are sealed with AES-256-GCM;\n" +
+		"a passphrase is stretched with PBKDF2-SHA256 (600,000 iterations).\n" +
+		"\n" +
+		"An encrypted session note is written as a payload, <note>.md.enc, plus\n" +
+		"a stub at the note's path that Obsidian shows: it keeps the identifying\n" +
+		"frontmatter (date, project, domain, tags, ...) and points at the\n" +
+		"payload. Summaries, decisions and prose stay in the payload. The local\n" +
+		"session index and generated context documents are not encrypted.\n" +
+		"\n" +
+		"vv archive, reprocess and backfill decrypt transparently when the key is\n" +
+		"available; vv check warns when it is not. vv decrypt prints a file's\n" +
+		"plaintext to stdout \u2014 archives decompressed, notes as markdown.\n" +
+		"\n" +
+		"Examples:\n" +
+		"  vv decrypt Projects/acme/sessions/2026-03-08-01.md\n" +
+		"  vv decrypt ~/vault/.vibe-vault/archive/<session-id>.jsonl.zst | jq .\n",
 
 	"reprocess": "vv reprocess \u2014 re-generate notes from transcripts\n" +
 		"\n" +
 		"Usage: vv reprocess [--project <name>]\n" +
 		"\n" +
 		"Flags:\n" +
-		"  --project <name>     Only reprocess sessions for this project\n" +
-		"  --source <name>      Filter by source (zed, claude-code)\n" +
-		"  --dry-run            Show what would be reprocessed without writing\n" +
-		"  --backfill-context   Populate ContextAvailable on entries (no reprocessing)\n" +
+		"  --project <name>          Only reprocess sessions for this project\n" +
+		"  --source <name>           Filter by source (zed, claude-code)\n" +
+		"  --dry-run                 Show what would be reprocessed without writing\n" +
+		"  --backfill-context        Populate ContextAvailable on entries (no reprocessing)\n" +
+		"  --no-cache                Bypass the LLM response cache\n" +
+		"  --diff                    Render in memory and print unified diffs against existing notes\n" +
+		"  --only-changed-sections   With --diff, print one diff per changed section\n" +
+		"  --accept <sections>       Write only these comma-separated sections (implies --diff)\n" +
+		"  --enrich                  With --diff, call the LLM for sessions whose enrichment is not cached\n" +
 		"\n" +
 		"Re-runs the capture pipeline with Force mode for all (or filtered)\n" +
 		"sessions in the index. Locates transcripts via three-tier lookup:\n" +
@@ -143,12 +238,32 @@ var expectedTerminal = map[string]string{
 		"  2. Archived copy (.vibe-vault/archive/)\n" +
 		"  3. Fallback discovery scan (~/.claude/projects/)\n" +
 		"\n" +
-		"Overwrites existing notes in place (preserves iteration numbers).\n" +
-		"Regenerates history.md for each affected project.\n" +
+		"Overwrites existing notes in place, preserving iteration numbers, the\n" +
+		"<!-- vv:user:start/end --> region, the rating and notes frontmatter\n" +
+		"keys, and tags added by hand.\n" +
+		"Notes are rendered in the configured vault_flavor, so reprocessing\n" +
+		"converts a vault after vv init --flavor. Regenerates history.md for\n" +
+		"each affected project.\n" +
+		"\n" +
+		"Enrichment and synthesis responses are served from the LLM cache when\n" +
+		"the transcript and prompt are unchanged, so reprocessing twice costs\n" +
+		"nothing the second time (see vv llm cache).\n" +
+		"\n" +
+		"--diff previews an upgrade: each note is rendered in memory and\n" +
+		"compared with the note on disk. The preview makes no LLM calls: it\n" +
+		"reuses enrichment from the LLM cache, and sessions with none cached are\n" +
+		"counted but not diffed. --enrich enriches those too, at the cost of a\n" +
+		"real run. Nothing is written unless --accept names sections to take\n" +
+		"from the new render; every other section, including hand-added ones, is\n" +
+		"kept verbatim. Section names are frontmatter, title, and the slugged ## heading\n" +
+		"(what-happened, key-decisions, ...). A summary counts how many notes\n" +
+		"change in each section. After accepting frontmatter, run vv index.\n" +
 		"\n" +
 		"Examples:\n" +
 		"  vv reprocess                       Reprocess all sessions\n" +
-		"  vv reprocess --project myproject   Reprocess one project only\n",
+		"  vv reprocess --project myproject   Reprocess one project only\n" +
+		"  vv reprocess --diff                Preview what would change\n" +
+		"  vv reprocess --accept frontmatter,what-happened\n",
 
 	"stats": "vv stats \u2014 show session analytics and metrics\n" +
 		"\n" +
@@ -328,6 +443,97 @@ var expectedTerminal = map[string]string{
 		"  vv effectiveness                       Show all projects\n" +
 		"  vv effectiveness --project myproject   Show one project\n" +
 		"  vv effectiveness --format json         Output as JSON\n",
+	"pr-describe": "vv pr-describe \u2014 generate a pull-request description from branch sessions\n" +
+		"\n" +
+		"Usage: vv pr-describe [--branch <name>] [--base <ref>] [--project <name>] [--llm]\n" +
+		"\n" +
+		"Flags:\n" +
+		"  --branch <name>    Feature branch (default: current git branch)\n" +
+		"  --base <ref>       Merge target (default: main)\n" +
+		"  --project <name>   Project to search (default: auto-detected from cwd)\n" +
+		"  --llm              Polish the draft with the configured enrichment provider\n" +
+		"  --no-cache         With --llm, bypass the LLM response cache\n" +
+		"\n" +
+		"Collects every indexed session whose branch matches, plus sessions\n" +
+		"whose recorded commits fall in <base>..<branch>, orders them oldest\n" +
+		"first, and renders a markdown PR description to stdout:\n" +
+		"\n" +
+		"  Summary          One line per session\n" +
+		"  Key Decisions    \"Decision \u2014 rationale\" from enrichment\n" +
+		"  Files Changed    Union of FilesChanged, with per-file session counts\n" +
+		"  Commits          SHAs recorded in the sessions\n" +
+		"  Test Evidence    Test runs re-extracted from session transcripts\n" +
+		"  Open Threads     Latest session's threads first, then earlier ones\n" +
+		"\n" +
+		"Transcripts are located via the same lookup as vv reprocess (original\n" +
+		"path, archive, discovery scan); sessions without one contribute no\n" +
+		"test evidence. --llm is optional and falls back to the heuristic draft\n" +
+		"on any provider error.\n" +
+		"\n" +
+		"Examples:\n" +
+		"  vv pr-describe                              Describe the current branch\n" +
+		"  vv pr-describe --branch feat/auth --base dev\n" +
+		"  vv pr-describe --llm | gh pr create --body-file -\n",
+	"changelog": "vv changelog \u2014 generate a changelog section from sessions between two git refs\n" +
+		"\n" +
+		"Usage: vv changelog <from-ref>..<to-ref> [--project <name>] [--version <label>]\n" +
+		"\n" +
+		"Arguments:\n" +
+		"  from-ref..to-ref    Git revision range; <to-ref> defaults to HEAD\n" +
+		"\n" +
+		"Flags:\n" +
+		"  --project <name>    Only join sessions from this project\n" +
+		"  --version <label>   Section label (default: <to-ref>, or Unreleased for HEAD)\n" +
+		"\n" +
+		"Lists the non-merge commits in the range (from the current directory's\n" +
+		"repository), joins each one to the sessions whose recorded commits\n" +
+		"match it, and renders a Keep-a-Changelog section to stdout.\n" +
+		"\n" +
+		"Entries are grouped by conventional-commit intent: the commit subject's\n" +
+		"prefix when present, otherwise the intent inferred from the linked\n" +
+		"session's activity (the same inference used for session summaries).\n" +
+		"\n" +
+		"  feat                          Added\n" +
+		"  fix                           Fixed\n" +
+		"  refactor, perf, docs, ...     Changed\n" +
+		"\n" +
+		"Each entry links to its session note and lists up to three decisions\n" +
+		"from the linked sessions.\n" +
+		"\n" +
+		"Examples:\n" +
+		"  vv changelog v1.2.0..v1.3.0\n" +
+		"  vv changelog v1.2.0..            Unreleased changes since v1.2.0\n" +
+		"  vv changelog v1.2.0..HEAD --version 1.3.0 >> CHANGELOG.md\n",
+
+	"adr": "vv adr \u2014 architecture decision records from session decisions\n" +
+		"\n" +
+		"Usage: vv adr <list | promote | supersede> [--project <name>]\n" +
+		"\n" +
+		"Session decisions decay out of history.md after a few weeks. An ADR\n" +
+		"keeps a significant one permanently at\n" +
+		"Projects/<project>/decisions/NNNN-slug.md, with a status (proposed,\n" +
+		"accepted, superseded), links to the sessions that made it, and a\n" +
+		"supersede chain. Live ADRs are listed under \"Architecture Decisions\"\n" +
+		"in history.md.\n" +
+		"\n" +
+		"Subcommands:\n" +
+		"  vv adr list [--candidates]          List ADRs, or decisions worth promoting\n" +
+		"  vv adr promote \"<decision>\"         Record a decision as an ADR\n" +
+		"  vv adr supersede <n> \"<decision>\"   Replace ADR n with a new decision\n" +
+		"\n" +
+		"The project defaults to the one detected from the current directory.\n",
+
+	"files": "vv files \u2014 per-file session history\n" +
+		"\n" +
+		"Usage: vv files dossier <path> [--project <name>] [--write] [--json]\n" +
+		"\n" +
+		"Answers \"who touched this file, why, and what was decided\" from the\n" +
+		"session index.\n" +
+		"\n" +
+		"Subcommands:\n" +
+		"  vv files dossier <path>     Sessions, commits, decisions, and churn for a file\n" +
+		"\n" +
+		"The project defaults to the one detected from the current directory.\n",
 
 	"flowdoc": "vv flowdoc \u2014 generate and verify the flowdoc graph for the current project\n" +
 		"\n" +
@@ -415,7 +621,8 @@ var expectedTerminal = map[string]string{
 	"mcp": "vv mcp \u2014 start MCP server for AI agent integration\n" +
 		"\n" +
 		"Usage: vv mcp\n" +
-		"    vv mcp install [--claude-only | --zed-only]\n" +
+		"    vv mcp serve [--http ADDR]\n" +
+		"    vv mcp install [--claude-only | --zed-only] [--url URL]\n" +
 		"    vv mcp uninstall [--claude-only | --zed-only]\n" +
 		"\n" +
 		"Starts a Model Context Protocol (MCP) server that exposes vibe-vault\n" +
@@ -423,6 +630,7 @@ var expectedTerminal = map[string]string{
 		"Claude Code and Zed to query project context programmatically.\n" +
 		"\n" +
 		"Subcommands:\n" +
+		"  serve       Serve over stdio, or one shared server over HTTP\n" +
 		"  install     Register the MCP server in editor settings\n" +
 		"  uninstall   Remove the MCP server from editor settings\n" +
 		"\n" +
@@ -459,21 +667,92 @@ var expectedTerminal = map[string]string{
 		"call the MCP tools automatically.\n" +
 		"\n" +
 		"The server logs tool calls to stderr for observability.\n",
+	"daemon": "vv daemon \u2014 supervise session sources and maintenance jobs\n" +
+		"\n" +
+		"Usage: vv daemon [status|run|install|uninstall]\n" +
+		"\n" +
+		"Runs in the foreground until SIGTERM or SIGINT. Starts every enabled\n" +
+		"session source (the Zed agent-panel watcher when threads.db exists; the\n" +
+		"Claude Code hooks need no process) and runs maintenance jobs on the\n" +
+		"intervals set under [daemon] in config.toml, one job at a time:\n" +
+		"\n" +
+		"  archive       vv archive               archive_minutes (default 60)\n" +
+		"  index         vv index                 index_minutes (default 360)\n" +
+		"  sync-sessions vv vault sync-sessions   sync_minutes (default 30)\n" +
+		"  worktree-gc   vv worktree gc           worktree_gc_minutes (default 60),\n" +
+		"                                         over worktree_repos only\n" +
+		"\n" +
+		"0 disables a job. Each job first runs a minute after start. Status is\n" +
+		"served on a unix socket ($XDG_RUNTIME_DIR/vibe-vault/daemon.sock unless\n" +
+		"[daemon] socket is set). SIGHUP re-reads config.toml and restarts the\n" +
+		"sources and schedule; job history is kept. While the daemon runs,\n" +
+		"vv mcp leaves Zed auto-capture to it.\n" +
+		"\n" +
+		"Subcommands:\n" +
+		"  vv daemon status      Show sources, jobs, and last results\n" +
+		"  vv daemon run <job>   Run a job now\n" +
+		"  vv daemon install     Write a systemd user unit\n" +
+		"  vv daemon uninstall   Remove the systemd user unit\n" +
+		"\n" +
+		"Examples:\n" +
+		"  vv daemon\n" +
+		"  vv daemon install && systemctl --user enable --now vibe-vault\n" +
+		"  systemctl --user reload vibe-vault   # after editing config.toml\n",
 
 	"config": "vv config \u2014 manage vibe-vault configuration\n" +
 		"\n" +
-		"Usage: vv config [set-key]\n" +
+		"Usage: vv config [set-key|gen-encryption-key]\n" +
 		"\n" +
 		"Manages settings stored in ~/.config/vibe-vault/config.toml.\n" +
 		"\n" +
 		"Subcommands:\n" +
-		"  set-key   Store a per-provider API key (anthropic, openai, google)\n" +
+		"  set-key              Store a per-provider API key (anthropic, openai, google)\n" +
+		"  gen-encryption-key   Create a key file for [encryption]\n" +
 		"\n" +
 		"Hook enrichment and session synthesis resolve provider keys via a\n" +
 		"layered lookup: the value in config.toml wins, falling back to the\n" +
 		"provider's environment variable (ANTHROPIC_API_KEY / OPENAI_API_KEY /\n" +
 		"GOOGLE_API_KEY) for operators who already have shell-env-based setup.\n",
 
+	"llm": "vv llm \u2014 inspect and manage LLM provider state\n" +
+		"\n" +
+		"Usage: vv llm <cache | usage> [...]\n" +
+		"\n" +
+		"Enrichment, synthesis, and pr-describe --llm responses are cached on\n" +
+		"disk, keyed by a SHA-256 of provider, model, system prompt, user\n" +
+		"prompt, temperature, JSON mode, and token cap. An unchanged transcript\n" +
+		"re-sent by vv reprocess is answered from the cache without an API call.\n" +
+		"\n" +
+		"Every LLM call vv makes, cached or not, is also appended to a usage\n" +
+		"ledger at <vault>/.vibe-vault/llm-usage.jsonl with its provider, model,\n" +
+		"token counts, purpose, project, and session.\n" +
+		"\n" +
+		"Subcommands:\n" +
+		"  vv llm cache stats    Entry count, size, and per-model breakdown\n" +
+		"  vv llm cache clear    Delete every cached response\n" +
+		"  vv llm usage          Token and cost totals per provider and purpose\n" +
+		"\n" +
+		"Configured under [llm] in config.toml:\n" +
+		"\n" +
+		"  cache = true          Enable the cache (default)\n" +
+		"  cache_max_mb = 256    Size limit; least recently used entries go first\n" +
+		"\n" +
+		"Pass --no-cache to vv process, vv reprocess, or vv pr-describe --llm to\n" +
+		"bypass it for one run.\n",
+
+	"enrich": "vv enrich \u2014 evaluate the enrichment and synthesis prompts\n" +
+		"\n" +
+		"Usage: vv enrich <eval> [...]\n" +
+		"\n" +
+		"Tools for working on the prompts behind session enrichment and\n" +
+		"synthesis.\n" +
+		"\n" +
+		"Subcommands:\n" +
+		"  vv enrich eval <dir>   Score the prompts over golden transcripts\n" +
+		"\n" +
+		"Examples:\n" +
+		"  vv enrich eval testdata/eval\n",
+
 	"command": "vv command \u2014 print canonical bodies of vibe-vault slash commands\n" +
 		"\n" +
 		"Usage: vv command [get <name>]\n" +
@@ -501,7 +780,10 @@ var expectedTerminal = map[string]string{
 		"  vv templates list              Show all templates with status\n" +
 		"  vv templates diff [--file X]   Unified diff of vault vs defaults\n" +
 		"  vv templates show <name>       Print built-in default to stdout\n" +
-		"  vv templates reset [--file X]  Reset templates to defaults\n",
+		"  vv templates reset [--file X]  Reset templates to defaults\n" +
+		"\n" +
+		"Customizing session-note.tmpl changes the layout of auto-generated\n" +
+		"session notes; see Templates/README.md in the vault for its fields.\n",
 
 	"version": "vv version \u2014 print version\n" +
 		"\n" +
@@ -565,8 +847,9 @@ func TestFormatUsage(t *testing.T) {
 		"  vv context [init | ...]          Manage vault-resident AI context\n" +
 		"  vv process <file.jsonl>          Process a single transcript file\n" +
 		"  vv index                         Rebuild session index from notes\n" +
-		"  vv backfill [path]               Discover and process historical transcripts\n" +
-		"  vv archive                       Compress transcripts into vault archive\n" +
+		"  vv backfill [path] [...]         Discover and process historical transcripts\n" +
+		"  vv archive [...]                 Compress transcripts into vault archive\n" +
+		"  vv decrypt <file>                Print an encrypted note or archive\n" +
 		"  vv reprocess [--project X]       Re-generate notes from transcripts\n" +
 		"  vv check                         Validate config, vault, and hook setup\n" +
 		"  vv stats [--project X]           Show session analytics and metrics\n" +
@@ -575,14 +858,21 @@ func TestFormatUsage(t *testing.T) {
 		"  vv inject [--project X]          Output session-start context payload\n" +
 		"  vv export [--format X]           Export session data (JSON or CSV)\n" +
 		"  vv effectiveness [--project X]   Analyze context effectiveness on outcomes\n" +
+		"  vv pr-describe [--branch X]      Generate a PR description from a branch's sessions\n" +
+		"  vv changelog <from>..<to>        Generate a Keep-a-Changelog section for a git range\n" +
+		"  vv adr [list | ...]              Promote session decisions to Architecture Decision Records\n" +
+		"  vv files dossier <path>          Show the session history of a file\n" +
 		"  vv flowdoc gen|verify [...]      Generate flows.json + FLOWS.html via LLM; verify refs against the tree\n" +
 		"  vv memory [link | ...]           Link Claude Code auto-memory into vault\n" +
 		"  vv vault <command>               Vault git sync (pull, push, status, recover)\n" +
 		"  vv staging [init | ...]          Manage host-local staging dir (init, status, gc, migrate)\n" +
 		"  vv zed <subcommand>              Import Zed agent panel threads into vault\n" +
 		"  vv mcp [install | ...]           Start MCP server (JSON-RPC over stdio)\n" +
+		"  vv daemon [...]                  Run capture sources and maintenance jobs in one long-lived process\n" +
 		"  vv worktree [gc | ...]           Manage subagent worktrees (gc)\n" +
 		"  vv config [set-key | ...]        Manage configuration (provider keys, etc.)\n" +
+		"  vv llm cache|usage [...]         Inspect the LLM response cache and usage ledger\n" +
+		"  vv enrich eval <dir> [...]       Evaluate enrichment prompts against golden transcripts\n" +
 		"  vv command [get]                 Print embedded slash-command bodies for shellout consumers\n" +
 		"  vv templates [list | ...]        Inspect, compare, and reset vault templates\n" +
 		"  vv version                       Print version\n" +
@@ -622,7 +912,7 @@ func TestCmdIndex_GlobIsRecursive(t *testing.T) {
 func TestRegistryCompleteness(t *testing.T) {
 	expectedNames := []string{
 		"init", "hook", "context", "process", "index",
-		"backfill", "archive", "reprocess", "check", "stats", "friction", "trends", "inject", "export", "effectiveness", "flowdoc", "memory", "vault", "staging", "zed", "mcp", "worktree", "config", "command", "templates", "version",
+		"backfill", "archive", "decrypt", "reprocess", "check", "stats", "friction", "trends", "inject", "export", "effectiveness", "pr-describe", "changelog", "adr", "files", "flowdoc", "memory", "vault", "staging", "zed", "mcp", "daemon", "worktree", "config", "llm", "enrich", "command", "templates", "version",
 	}
 	if len(Subcommands) != len(expectedNames) {
 		t.Fatalf("expected %d subcommands, got %d", len(expectedNames), len(Subcommands))
@@ -690,6 +980,12 @@ func TestFormatRoffStructure(t *testing.T) {
 	allCmds = append(allCmds, ContextSubcommands...)
 	allCmds = append(allCmds, ZedSubcommands...)
 	allCmds = append(allCmds, TemplatesSubcommands...)
+	allCmds = append(allCmds, AdrSubcommands...)
+	allCmds = append(allCmds, FilesSubcommands...)
+	allCmds = append(allCmds, LlmSubcommands...)
+	allCmds = append(allCmds, ArchiveSubcommands...)
+	allCmds = append(allCmds, DaemonSubcommands...)
+	allCmds = append(allCmds, EnrichSubcommands...)
 	allCmds = append(allCmds, CommandSubcommands...)
 	allCmds = append(allCmds, MemorySubcommands...)
 	// Test each subcommand has required sections
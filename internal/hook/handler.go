@@ -180,8 +180,9 @@ func handleSessionEnd(input *Input, cfg config.Config) error {
 
 	// Create LLM provider (nil if disabled). When enabled, the layered key
 	// resolver (providers.<P>.api_key → env-var → actionable error) runs
-	// inside NewProvider.
-	provider, providerErr := llm.NewProvider(cfg.Enrichment, cfg.Providers)
+	// inside NewProvider. Responses land in the LLM cache, so a later
+	// vv reprocess of this unchanged transcript is free.
+	provider, providerErr := llm.NewConfiguredProvider(cfg)
 	if providerErr != nil {
 		log.Printf("warning: LLM provider init failed: %v", providerErr)
 	}
@@ -223,25 +224,7 @@ func handleSessionEnd(input *Input, cfg config.Config) error {
 		return nil
 	}
 
-	// Report enrichment status in output. The "enriched by X" message is only
-	// printed when enrichment actually produced usable content — checking config
-	// validity alone is not enough, since a valid config can still 401/500/timeout
-	// at the HTTP layer and leave the note heuristic-only, and prose extraction
-	// deliberately short-circuits the LLM enrichment path when it has output.
-	providerName, model, reason := llm.Available(cfg.Enrichment)
-	var enrichTag string
-	switch {
-	case !cfg.Enrichment.Enabled:
-		enrichTag = "heuristic — no LLM configured"
-	case reason != "":
-		enrichTag = fmt.Sprintf("heuristic — LLM unavailable: %s", reason)
-	case !result.EnrichmentAttempted:
-		enrichTag = "prose-extracted (LLM enrichment skipped by design)"
-	case result.EnrichmentApplied:
-		enrichTag = fmt.Sprintf("enriched by %s/%s", providerName, model)
-	default:
-		enrichTag = fmt.Sprintf("heuristic — LLM call failed (target: %s/%s; see warning above)", providerName, model)
-	}
+	enrichTag := enrichmentTag(cfg, result)
 	fmt.Fprintf(os.Stderr, "vv: session captured → %s (%s)\n", result.NotePath, enrichTag)
 
 	if result.FrictionAlert != "" {
@@ -263,6 +246,11 @@ func handleSessionEnd(input *Input, cfg config.Config) error {
 				}
 				synthCtx, synthCancel := context.WithTimeout(context.Background(), synthTimeout)
 				defer synthCancel()
+				synthCtx = llm.WithUsageLabels(synthCtx, llm.UsageLabels{
+					Purpose: llm.PurposeSynthesis,
+					Project: result.Project,
+					Session: input.SessionID,
+				})
 
 				notePath := filepath.Join(cfg.VaultPath, result.NotePath)
 				report, synthErr := synthesis.Run(synthCtx, synthesis.RunOpts{
@@ -289,13 +277,7 @@ func handleSessionEnd(input *Input, cfg config.Config) error {
 		return nil
 	}
 
-	genResult, err := index.GenerateContext(idx, cfg.VaultPath, index.ContextOptions{
-		AlertThreshold:       cfg.Friction.AlertThreshold,
-		TimelineRecentDays:   cfg.History.TimelineRecentDays,
-		TimelineWindowDays:   cfg.History.TimelineWindowDays,
-		DecisionStaleDays:    cfg.History.DecisionStaleDays,
-		KeyFilesRecencyBoost: cfg.History.KeyFilesRecencyBoost,
-	})
+	genResult, err := index.GenerateContext(idx, cfg.VaultPath, index.ContextOptionsFor(cfg))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "vv: warning: context refresh failed: %v\n", err)
 		return nil
@@ -304,3 +286,30 @@ func handleSessionEnd(input *Input, cfg config.Config) error {
 
 	return nil
 }
+
+// enrichmentTag describes how a captured note was enriched. The
+// "enriched by X" message is only printed when enrichment actually
+// produced usable content — checking config validity alone is not
+// enough, since a valid config can still 401/500/timeout at the HTTP
+// layer and leave the note heuristic-only, and prose extraction
+// deliberately short-circuits the LLM enrichment path when it has
+// output. Applied enrichment is checked before llm.Available, which
+// only looks at the primary provider: a fallback link can answer when
+// the primary's key is missing.
+func enrichmentTag(cfg config.Config, result *session.CaptureResult) string {
+	providerName, model, reason := llm.Available(cfg.Enrichment)
+	switch {
+	case !cfg.Enrichment.Enabled:
+		return "heuristic — no LLM configured"
+	case result.EnrichmentApplied && len(cfg.Enrichment.Fallback) > 0:
+		return "enriched by " + result.EnrichedBy
+	case result.EnrichmentApplied:
+		return fmt.Sprintf("enriched by %s/%s", providerName, model)
+	case reason != "":
+		return fmt.Sprintf("heuristic — LLM unavailable: %s", reason)
+	case !result.EnrichmentAttempted:
+		return "prose-extracted (LLM enrichment skipped by design)"
+	default:
+		return fmt.Sprintf("heuristic — LLM call failed (target: %s/%s; see warning above)", providerName, model)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/session"
 )
 
 // minimalTranscript is a JSONL transcript with enough messages to not be skipped as trivial.
@@ -442,3 +443,34 @@ func TestReadStdinFrom_LargeInput(t *testing.T) {
 		t.Fatal("expected error for oversized input")
 	}
 }
+
+func TestEnrichmentTag(t *testing.T) {
+	t.Setenv("VV_TEST_MISSING_KEY", "")
+	cfg := config.Config{Enrichment: config.EnrichmentConfig{
+		Enabled:   true,
+		Provider:  "openai",
+		Model:     "gpt-4o-mini",
+		APIKeyEnv: "VV_TEST_MISSING_KEY",
+		Fallback:  []config.ProviderModel{{Provider: "anthropic", Model: "claude-haiku"}},
+	}}
+
+	// The primary's key is missing, but the fallback link answered.
+	got := enrichmentTag(cfg, &session.CaptureResult{
+		EnrichmentAttempted: true,
+		EnrichmentApplied:   true,
+		EnrichedBy:          "anthropic/claude-haiku",
+	})
+	if got != "enriched by anthropic/claude-haiku" {
+		t.Errorf("fallback enrichment: got %q", got)
+	}
+
+	got = enrichmentTag(cfg, &session.CaptureResult{EnrichmentAttempted: true})
+	if !strings.HasPrefix(got, "heuristic — LLM unavailable: VV_TEST_MISSING_KEY not set") {
+		t.Errorf("failed enrichment: got %q", got)
+	}
+
+	cfg.Enrichment.Enabled = false
+	if got := enrichmentTag(cfg, &session.CaptureResult{}); got != "heuristic — no LLM configured" {
+		t.Errorf("disabled: got %q", got)
+	}
+}
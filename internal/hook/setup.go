@@ -10,6 +10,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 
 	"github.com/suykerbuyk/vibe-vault/internal/config"
@@ -110,9 +111,29 @@ func Uninstall() error {
 const mcpServerName = "vibe-vault"
 const mcpCommand = "vv"
 
+// MCPRemote points editors at a shared `vv mcp serve --http` server
+// instead of each spawning `vv mcp` over stdio.
+type MCPRemote struct {
+	URL   string
+	Token string // sent as "Authorization: Bearer <token>" when set
+}
+
+// headers returns the HTTP headers editors send to the server.
+func (r *MCPRemote) headers() map[string]any {
+	if r.Token == "" {
+		return nil
+	}
+	return map[string]any{"Authorization": "Bearer " + r.Token}
+}
+
 // InstallMCP adds the vibe-vault MCP server entry to ~/.claude/settings.json.
 // Idempotent: returns nil when already installed.
 func InstallMCP() error {
+	return installMCP(nil)
+}
+
+// installMCP writes the stdio entry, or the HTTP entry for remote.
+func installMCP(remote *MCPRemote) error {
 	path, err := SettingsPath()
 	if err != nil {
 		return err
@@ -127,7 +148,8 @@ func InstallMCP() error {
 		return err
 	}
 
-	if isMCPInstalled(settings) {
+	entry := mcpEntry(remote)
+	if mcpEntryCurrent(settings["mcpServers"], entry) {
 		fmt.Fprintf(os.Stderr, "vibe-vault MCP server already configured in %s\n", config.CompressHome(path))
 		return nil
 	}
@@ -136,7 +158,7 @@ func InstallMCP() error {
 		return err
 	}
 
-	addMCP(settings)
+	addMCP(settings, entry)
 
 	if err := writeSettings(path, settings); err != nil {
 		return err
@@ -191,6 +213,11 @@ func ZedSettingsPath() (string, error) {
 // InstallMCPZed adds the vibe-vault MCP server entry to Zed's settings.json.
 // Idempotent: returns nil when already installed.
 func InstallMCPZed() error {
+	return installMCPZed(nil)
+}
+
+// installMCPZed writes the stdio entry, or the HTTP entry for remote.
+func installMCPZed(remote *MCPRemote) error {
 	path, err := ZedSettingsPath()
 	if err != nil {
 		return err
@@ -205,7 +232,8 @@ func InstallMCPZed() error {
 	// is intentionally skipped here — Zed's settings.json has its
 	// own schema. Re-evaluate if Zed grows a comparable contract.
 
-	if isMCPZedInstalled(settings) {
+	entry := zedMCPEntry(remote)
+	if mcpEntryCurrent(settings["context_servers"], entry) {
 		fmt.Fprintf(os.Stderr, "vibe-vault MCP server already configured in %s\n", config.CompressHome(path))
 		return nil
 	}
@@ -214,7 +242,7 @@ func InstallMCPZed() error {
 		return err
 	}
 
-	addMCPZed(settings)
+	addMCPZed(settings, entry)
 
 	if err := writeSettings(path, settings); err != nil {
 		return err
@@ -267,17 +295,47 @@ func isMCPZedInstalled(settings map[string]any) bool {
 	return ok
 }
 
-// addMCPZed adds the vibe-vault entry to Zed's context_servers.
-func addMCPZed(settings map[string]any) {
+// addMCPZed sets the vibe-vault entry in Zed's context_servers.
+func addMCPZed(settings map[string]any, entry map[string]any) {
 	servers, ok := settings["context_servers"].(map[string]any)
 	if !ok {
 		servers = make(map[string]any)
 		settings["context_servers"] = servers
 	}
-	servers[mcpServerName] = map[string]any{
-		"command": mcpCommand,
-		"args":    []any{"mcp"},
+	servers[mcpServerName] = entry
+}
+
+// zedMCPEntry is Zed's context_servers entry: the stdio command, or the
+// URL of a remote server.
+func zedMCPEntry(remote *MCPRemote) map[string]any {
+	if remote == nil {
+		return map[string]any{"command": mcpCommand, "args": []any{"mcp"}}
 	}
+	entry := map[string]any{"url": remote.URL}
+	if h := remote.headers(); h != nil {
+		entry["headers"] = h
+	}
+	return entry
+}
+
+// mcpEntryCurrent reports whether the vibe-vault entry in an editor's
+// server map already has the wanted form. Any stdio entry counts, since
+// users may point it at their own vv binary; a URL entry must match, so
+// switching transports or tokens rewrites it.
+func mcpEntryCurrent(servers any, want map[string]any) bool {
+	m, ok := servers.(map[string]any)
+	if !ok {
+		return false
+	}
+	have, ok := m[mcpServerName].(map[string]any)
+	if !ok {
+		return false
+	}
+	if _, stdio := want["command"]; stdio {
+		_, ok := have["command"]
+		return ok
+	}
+	return reflect.DeepEqual(have, want)
 }
 
 // removeMCPZed removes the vibe-vault entry from Zed's context_servers.
@@ -303,17 +361,27 @@ func isMCPInstalled(settings map[string]any) bool {
 	return ok
 }
 
-// addMCP adds the vibe-vault MCP server entry.
-func addMCP(settings map[string]any) {
+// addMCP sets the vibe-vault MCP server entry.
+func addMCP(settings map[string]any, entry map[string]any) {
 	servers, ok := settings["mcpServers"].(map[string]any)
 	if !ok {
 		servers = make(map[string]any)
 		settings["mcpServers"] = servers
 	}
-	servers[mcpServerName] = map[string]any{
-		"command": mcpCommand,
-		"args":    []any{"mcp"},
+	servers[mcpServerName] = entry
+}
+
+// mcpEntry is Claude Code's mcpServers entry: the stdio command, or an
+// "http" entry for a remote server.
+func mcpEntry(remote *MCPRemote) map[string]any {
+	if remote == nil {
+		return map[string]any{"command": mcpCommand, "args": []any{"mcp"}}
 	}
+	entry := map[string]any{"type": "http", "url": remote.URL}
+	if h := remote.headers(); h != nil {
+		entry["headers"] = h
+	}
+	return entry
 }
 
 // removeMCP removes the vibe-vault MCP server entry.
@@ -630,10 +698,20 @@ func zedDetected() bool {
 // InstallMCPAll installs the MCP server into all detected editors.
 // Pass claudeOnly or zedOnly to restrict to a single editor.
 func InstallMCPAll(claudeOnly, zedOnly bool) error {
+	return installMCPAll(nil, claudeOnly, zedOnly)
+}
+
+// InstallMCPRemoteAll is InstallMCPAll for a shared HTTP server: editors
+// connect to remote's URL instead of spawning vv.
+func InstallMCPRemoteAll(remote MCPRemote, claudeOnly, zedOnly bool) error {
+	return installMCPAll(&remote, claudeOnly, zedOnly)
+}
+
+func installMCPAll(remote *MCPRemote, claudeOnly, zedOnly bool) error {
 	var errs []error
 	if !zedOnly {
 		if claudeDetected() {
-			if err := InstallMCP(); err != nil {
+			if err := installMCP(remote); err != nil {
 				errs = append(errs, err)
 			} else {
 				fmt.Fprintf(os.Stderr, "note: if Claude Code tools don't appear, try: vv mcp install --claude-plugin\n")
@@ -645,7 +723,7 @@ func InstallMCPAll(claudeOnly, zedOnly bool) error {
 	}
 	if !claudeOnly {
 		if zedDetected() {
-			if err := InstallMCPZed(); err != nil {
+			if err := installMCPZed(remote); err != nil {
 				errs = append(errs, err)
 			}
 		} else {
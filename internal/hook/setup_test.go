@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -794,6 +795,48 @@ func TestInstallMCPAll_OnlyZed(t *testing.T) {
 	}
 }
 
+func TestInstallMCPRemoteAll_SwitchesTransport(t *testing.T) {
+	home := setupHome(t)
+	os.MkdirAll(filepath.Join(home, ".claude"), 0o755)
+	os.MkdirAll(filepath.Join(home, ".config", "zed"), 0o755)
+
+	if err := InstallMCPAll(false, false); err != nil {
+		t.Fatal(err)
+	}
+	remote := MCPRemote{URL: "http://127.0.0.1:8765/mcp", Token: "s3cret"}
+	if err := InstallMCPRemoteAll(remote, false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	headers := map[string]any{"Authorization": "Bearer s3cret"}
+	cs := readJSON(t, settingsPath(home))
+	want := map[string]any{"type": "http", "url": remote.URL, "headers": headers}
+	if got := cs["mcpServers"].(map[string]any)["vibe-vault"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Claude entry = %v, want %v", got, want)
+	}
+	zs := readJSON(t, zedSettingsPath(home))
+	want = map[string]any{"url": remote.URL, "headers": headers}
+	if got := zs["context_servers"].(map[string]any)["vibe-vault"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Zed entry = %v, want %v", got, want)
+	}
+
+	// Same URL again is a no-op; plain install goes back to stdio.
+	first, _ := os.ReadFile(settingsPath(home))
+	if err := InstallMCPRemoteAll(remote, true, false); err != nil {
+		t.Fatal(err)
+	}
+	if second, _ := os.ReadFile(settingsPath(home)); string(first) != string(second) {
+		t.Error("repeated remote install modified the file")
+	}
+	if err := InstallMCPAll(true, false); err != nil {
+		t.Fatal(err)
+	}
+	cs = readJSON(t, settingsPath(home))
+	if got := cs["mcpServers"].(map[string]any)["vibe-vault"].(map[string]any); got["command"] != "vv" {
+		t.Errorf("Claude entry after stdio install = %v", got)
+	}
+}
+
 func TestInstallMCPAll_NeitherDetected(t *testing.T) {
 	setupHome(t)
 	// No editor directories
@@ -0,0 +1,46 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package index
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/suykerbuyk/vibe-vault/internal/adr"
+)
+
+// ADRSources returns the project's session decisions in the form
+// adr.Candidates consumes, in chronological order.
+func (idx *Index) ADRSources(project string) []adr.Source {
+	entries := idx.projectEntries(project)
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Date < entries[j].Date ||
+			(entries[i].Date == entries[j].Date && entries[i].Iteration < entries[j].Iteration)
+	})
+	var out []adr.Source
+	for _, e := range entries {
+		if len(e.Decisions) == 0 && e.Summary == "" {
+			continue
+		}
+		out = append(out, adr.Source{
+			Note:      filenameNoExt(e.NotePath),
+			Date:      e.Date,
+			Decisions: e.Decisions,
+			Summary:   e.Summary,
+		})
+	}
+	return out
+}
+
+// NoteLinks maps the project's session note names to their vault-relative
+// paths, for resolving links in the markdown vault flavor.
+func (idx *Index) NoteLinks(project string) map[string]string {
+	links := make(map[string]string)
+	for _, e := range idx.projectEntries(project) {
+		if e.NotePath != "" && !filepath.IsAbs(e.NotePath) {
+			links[filenameNoExt(e.NotePath)] = e.NotePath
+		}
+	}
+	return links
+}
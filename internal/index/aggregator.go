@@ -427,6 +427,7 @@ func buildEntryFromFile(vaultPath, project, host, absPath string) (*SessionEntry
 		Branch:       note.Branch,
 		Commits:      note.Commits,
 		Host:         host,
+		sealed:       note.Sealed,
 	}
 
 	if t, ok := note.Frontmatter["title"]; ok && t != "" {
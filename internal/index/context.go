@@ -5,22 +5,43 @@ package index
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/suykerbuyk/vibe-vault/internal/adr"
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/flavor"
 	"github.com/suykerbuyk/vibe-vault/internal/mdutil"
 )
 
 // ContextOptions controls history.md rendering and pruning behavior.
 type ContextOptions struct {
-	AlertThreshold       int       // friction score threshold for alerts (0 = disabled)
-	Now                  time.Time // reference time for recency (zero = time.Now())
-	TimelineRecentDays   int       // full detail window (default 7)
-	TimelineWindowDays   int       // condensed window (default 30)
-	DecisionStaleDays    int       // decay threshold (default 90)
-	KeyFilesRecencyBoost int       // multiplier for recent file sessions (default 3)
+	AlertThreshold       int           // friction score threshold for alerts (0 = disabled)
+	Now                  time.Time     // reference time for recency (zero = time.Now())
+	TimelineRecentDays   int           // full detail window (default 7)
+	TimelineWindowDays   int           // condensed window (default 30)
+	DecisionStaleDays    int           // decay threshold (default 90)
+	KeyFilesRecencyBoost int           // multiplier for recent file sessions (default 3)
+	ActivityChart        bool          // render the Mermaid activity-mix pie chart
+	Flavor               flavor.Flavor // vault dialect for links/properties ("" = obsidian)
+	ADRs                 []adr.Record  // the project's decision records (set per project by GenerateContext)
+}
+
+// ContextOptionsFor maps cfg's [friction], [history], and [notes]
+// settings and vault flavor onto the history.md generation options.
+func ContextOptionsFor(cfg config.Config) ContextOptions {
+	return ContextOptions{
+		AlertThreshold:       cfg.Friction.AlertThreshold,
+		TimelineRecentDays:   cfg.History.TimelineRecentDays,
+		TimelineWindowDays:   cfg.History.TimelineWindowDays,
+		DecisionStaleDays:    cfg.History.DecisionStaleDays,
+		KeyFilesRecencyBoost: cfg.History.KeyFilesRecencyBoost,
+		ActivityChart:        cfg.Notes.Diagram != "",
+		Flavor:               cfg.Flavor(),
+	}
 }
 
 // normalize fills zero-value fields with sensible defaults.
@@ -127,8 +148,33 @@ func (idx *Index) ProjectContext(project string, opts ContextOptions) string {
 	}
 	b.WriteString("\n")
 
-	// Key Decisions — with staleness decay and permanence markers
-	decisions := collectDecisionsWithDecay(entries, opts)
+	// Architecture Decisions — promoted ADRs never decay
+	var liveADRs []adr.Record
+	for _, r := range opts.ADRs {
+		if r.Status != adr.Superseded {
+			liveADRs = append(liveADRs, r)
+		}
+	}
+	if len(liveADRs) > 0 {
+		b.WriteString("## Architecture Decisions\n\n")
+		for _, r := range liveADRs {
+			line := fmt.Sprintf("- [[%s]] %s", r.Name(), r.Title)
+			if r.Status == adr.Proposed {
+				line += " *(proposed)*"
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	// Key Decisions — with staleness decay and permanence markers;
+	// decisions already recorded as ADRs are listed above instead.
+	var decisions []datedDecision
+	for _, d := range collectDecisionsWithDecay(entries, opts) {
+		if adr.FindDuplicate(liveADRs, d.text) == nil {
+			decisions = append(decisions, d)
+		}
+	}
 	if len(decisions) > 0 {
 		b.WriteString("## Key Decisions\n\n")
 		var lastDate string
@@ -180,9 +226,68 @@ func (idx *Index) ProjectContext(project string, opts ContextOptions) string {
 		b.WriteString("\n")
 	}
 
+	// Activity Mix — Mermaid pie of narrative activity kinds
+	if opts.ActivityChart {
+		b.WriteString(renderActivityMix(entries))
+	}
+
 	b.WriteString("---\n")
 	b.WriteString("*Auto-generated by vv index*\n")
 
+	doc := b.String()
+	if opts.Flavor != "" && opts.Flavor != flavor.Obsidian {
+		targets := make(map[string]string, len(entries))
+		for _, e := range entries {
+			if !filepath.IsAbs(e.NotePath) {
+				targets[filenameNoExt(e.NotePath)] = e.NotePath
+			}
+		}
+		for _, r := range opts.ADRs {
+			targets[r.Name()] = r.Path
+		}
+		from := path.Join("Projects", project, "history.md")
+		doc = flavor.Convert(doc, opts.Flavor, flavor.RelativeResolver(from, targets))
+	}
+	return doc
+}
+
+// renderActivityMix sums ActivityCounts across entries into a Mermaid pie
+// chart. Returns empty string when no entry recorded activity counts.
+func renderActivityMix(entries []SessionEntry) string {
+	mix := make(map[string]int)
+	sessions := 0
+	for _, e := range entries {
+		if len(e.ActivityCounts) == 0 {
+			continue
+		}
+		sessions++
+		for kind, n := range e.ActivityCounts {
+			mix[kind] += n
+		}
+	}
+	if sessions == 0 {
+		return ""
+	}
+
+	kinds := make([]string, 0, len(mix))
+	for k := range mix {
+		kinds = append(kinds, k)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		if mix[kinds[i]] != mix[kinds[j]] {
+			return mix[kinds[i]] > mix[kinds[j]]
+		}
+		return kinds[i] < kinds[j]
+	})
+
+	var b strings.Builder
+	b.WriteString("## Activity Mix\n\n")
+	b.WriteString("```mermaid\npie showData\n")
+	fmt.Fprintf(&b, "    title Activities across %d sessions\n", sessions)
+	for _, k := range kinds {
+		fmt.Fprintf(&b, "    \"%s\" : %d\n", k, mix[k])
+	}
+	b.WriteString("```\n\n")
 	return b.String()
 }
 
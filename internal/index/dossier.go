@@ -0,0 +1,272 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package index
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/suykerbuyk/vibe-vault/internal/flavor"
+)
+
+// FileSession is one session that changed a file.
+type FileSession struct {
+	Note    string   `json:"note"` // note name, e.g. "2026-03-01-143025123"
+	Date    string   `json:"date"`
+	Title   string   `json:"title"`
+	Summary string   `json:"summary,omitempty"`
+	Branch  string   `json:"branch,omitempty"`
+	Commits []string `json:"commits,omitempty"`
+}
+
+// FileMention is a decision or open thread attached to a file, either
+// because its session changed the file or because its text names it.
+type FileMention struct {
+	Text string `json:"text"`
+	Note string `json:"note"`
+	Date string `json:"date"`
+}
+
+// FileChurn counts the sessions and commits touching a file in one month.
+type FileChurn struct {
+	Month    string `json:"month"` // YYYY-MM
+	Sessions int    `json:"sessions"`
+	Commits  int    `json:"commits"`
+}
+
+// FileDossier is the session history of one file within a project.
+type FileDossier struct {
+	Project     string        `json:"project"`
+	Path        string        `json:"path"`
+	Sessions    []FileSession `json:"sessions"`
+	Decisions   []FileMention `json:"decisions,omitempty"`
+	OpenThreads []FileMention `json:"open_threads,omitempty"`
+	Churn       []FileChurn   `json:"churn,omitempty"`
+}
+
+// MatchFile reports whether a FilesChanged entry refers to file. Entries
+// are project-relative (or ~-compressed when outside the project), so a
+// file matches on equality or when one is a path-segment suffix of the
+// other — "index/context.go" finds "internal/index/context.go".
+func MatchFile(changed, file string) bool {
+	changed = strings.TrimPrefix(filepath.ToSlash(changed), "./")
+	file = strings.TrimPrefix(filepath.ToSlash(file), "./")
+	if changed == "" || file == "" {
+		return false
+	}
+	return changed == file ||
+		strings.HasSuffix(changed, "/"+file) ||
+		strings.HasSuffix(file, "/"+changed)
+}
+
+// mentionsFile reports whether free text names the file, by its path or
+// by its base name when that carries an extension (so "main" alone does
+// not match every sentence containing the word).
+func mentionsFile(text, file string) bool {
+	if strings.Contains(text, file) {
+		return true
+	}
+	base := path.Base(file)
+	return strings.Contains(base, ".") && strings.Contains(text, base)
+}
+
+// FileDossier collects every session in the project whose FilesChanged
+// matches file, oldest first. Decisions and open threads are included
+// when their session changed the file or when their text mentions it
+// from any session; open threads later resolved by a decision are
+// dropped, as in history.md. Path is reported as the recorded spelling
+// when exactly one file matched.
+func (idx *Index) FileDossier(project, file string) FileDossier {
+	entries := idx.projectEntries(project)
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Date < entries[j].Date ||
+			(entries[i].Date == entries[j].Date && entries[i].Iteration < entries[j].Iteration)
+	})
+
+	d := FileDossier{Project: project, Path: file}
+	matched := make(map[string]bool)
+	var allDecisions []string
+	var threads []FileMention
+	seenDecision := make(map[string]bool)
+	seenThread := make(map[string]bool)
+	churn := make(map[string]*FileChurn)
+	var months []string
+
+	for _, e := range entries {
+		allDecisions = append(allDecisions, e.Decisions...)
+
+		touched := false
+		for _, f := range e.FilesChanged {
+			if MatchFile(f, file) {
+				touched = true
+				matched[f] = true
+			}
+		}
+		note := filenameNoExt(e.NotePath)
+
+		if touched {
+			d.Sessions = append(d.Sessions, FileSession{
+				Note:    note,
+				Date:    e.Date,
+				Title:   e.Title,
+				Summary: e.Summary,
+				Branch:  e.Branch,
+				Commits: e.Commits,
+			})
+			if month := monthOf(e.Date); month != "" {
+				c, ok := churn[month]
+				if !ok {
+					c = &FileChurn{Month: month}
+					churn[month] = c
+					months = append(months, month)
+				}
+				c.Sessions++
+				c.Commits += len(e.Commits)
+			}
+		}
+
+		for _, dec := range e.Decisions {
+			if !touched && !mentionsFile(dec, file) {
+				continue
+			}
+			if key := strings.ToLower(strings.TrimSpace(dec)); !seenDecision[key] {
+				seenDecision[key] = true
+				d.Decisions = append(d.Decisions, FileMention{Text: dec, Note: note, Date: e.Date})
+			}
+		}
+		for _, t := range e.OpenThreads {
+			if !touched && !mentionsFile(t, file) {
+				continue
+			}
+			if !seenThread[t] {
+				seenThread[t] = true
+				threads = append(threads, FileMention{Text: t, Note: note, Date: e.Date})
+			}
+		}
+	}
+
+	for _, t := range threads {
+		if !isResolvedByDecisions(t.Text, allDecisions) {
+			d.OpenThreads = append(d.OpenThreads, t)
+		}
+	}
+	sort.Strings(months)
+	for _, m := range months {
+		d.Churn = append(d.Churn, *churn[m])
+	}
+	if len(matched) == 1 {
+		for f := range matched {
+			d.Path = f
+		}
+	}
+	return d
+}
+
+// monthOf returns the YYYY-MM prefix of a YYYY-MM-DD date.
+func monthOf(date string) string {
+	if len(date) < 7 {
+		return ""
+	}
+	return date[:7]
+}
+
+// FileDossierPath returns the vault-relative path of a file's dossier
+// note: Projects/<project>/files/<path>.md. Leading "~/" and "../"
+// segments are dropped so the note always lands inside files/.
+func FileDossierPath(project, file string) string {
+	rel := path.Clean("/" + strings.TrimPrefix(filepath.ToSlash(file), "~/"))
+	return path.Join("Projects", project, "files", rel+".md")
+}
+
+// RenderFileDossier renders a dossier as a vault note. Session links are
+// resolved for non-Obsidian flavors against the note's own location.
+func RenderFileDossier(d FileDossier, f flavor.Flavor, links map[string]string) string {
+	var b strings.Builder
+
+	b.WriteString("---\n")
+	b.WriteString("type: file-dossier\n")
+	fmt.Fprintf(&b, "project: %s\n", d.Project)
+	fmt.Fprintf(&b, "path: %q\n", d.Path)
+	fmt.Fprintf(&b, "sessions: %d\n", len(d.Sessions))
+	if n := len(d.Sessions); n > 0 {
+		fmt.Fprintf(&b, "last_touched: %s\n", d.Sessions[n-1].Date)
+	}
+	b.WriteString("tags: [vv-file]\n")
+	b.WriteString("---\n\n")
+
+	fmt.Fprintf(&b, "# %s\n\n", d.Path)
+
+	b.WriteString("## Sessions\n\n")
+	if len(d.Sessions) == 0 {
+		b.WriteString("No recorded session changed this file.\n\n")
+	}
+	for _, s := range d.Sessions {
+		line := fmt.Sprintf("- [[%s]]", s.Note)
+		if s.Summary != "" {
+			line += " — " + s.Summary
+		} else if s.Title != "" {
+			line += " — " + s.Title
+		}
+		for _, c := range s.Commits {
+			line += fmt.Sprintf(" `%s`", c)
+		}
+		b.WriteString(line + "\n")
+	}
+	if len(d.Sessions) > 0 {
+		b.WriteString("\n")
+	}
+
+	if len(d.Decisions) > 0 {
+		b.WriteString("## Decisions\n\n")
+		for _, m := range d.Decisions {
+			fmt.Fprintf(&b, "- %s ([[%s]])\n", m.Text, m.Note)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(d.OpenThreads) > 0 {
+		b.WriteString("## Open Threads\n\n")
+		for _, m := range d.OpenThreads {
+			fmt.Fprintf(&b, "- [ ] %s ([[%s]])\n", m.Text, m.Note)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(d.Churn) > 0 {
+		b.WriteString("## Churn\n\n")
+		b.WriteString("| Month | Sessions | Commits | |\n")
+		b.WriteString("|-------|---------:|--------:|-|\n")
+		for _, c := range d.Churn {
+			fmt.Fprintf(&b, "| %s | %d | %d | %s |\n", c.Month, c.Sessions, c.Commits, strings.Repeat("█", c.Sessions))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("---\n")
+	b.WriteString("*Auto-generated by vv files dossier*\n")
+
+	doc := b.String()
+	if f != "" && f != flavor.Obsidian {
+		doc = flavor.Convert(doc, f, flavor.RelativeResolver(FileDossierPath(d.Project, d.Path), links))
+	}
+	return doc
+}
+
+// WriteFileDossier renders the dossier and writes it to its
+// FileDossierPath, returning that vault-relative path.
+func WriteFileDossier(vaultPath string, d FileDossier, f flavor.Flavor, links map[string]string) (string, error) {
+	rel := FileDossierPath(d.Project, d.Path)
+	abs := filepath.Join(vaultPath, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil {
+		return "", fmt.Errorf("create dossier dir: %w", err)
+	}
+	if err := os.WriteFile(abs, []byte(RenderFileDossier(d, f, links)), 0o644); err != nil {
+		return "", fmt.Errorf("write dossier: %w", err)
+	}
+	return rel, nil
+}
@@ -0,0 +1,116 @@
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/suykerbuyk/vibe-vault/internal/flavor"
+)
+
+func dossierIndex() *Index {
+	return &Index{Entries: map[string]SessionEntry{
+		"a": {SessionID: "a", Project: "proj", Date: "2026-02-10", Iteration: 1,
+			NotePath: "Projects/proj/sessions/2026-02-10-01.md", Summary: "Added decay to key decisions",
+			FilesChanged: []string{"internal/index/context.go", "README.md"},
+			Commits:      []string{"abc1234"},
+			Decisions:    []string{"Decay decisions after 90 days"},
+			OpenThreads:  []string{"Tune decay window per project"}},
+		"b": {SessionID: "b", Project: "proj", Date: "2026-03-02", Iteration: 1,
+			NotePath: "Projects/proj/sessions/2026-03-02-01.md", Summary: "Split key files weighting",
+			FilesChanged: []string{"internal/index/context.go"},
+			Commits:      []string{"def5678", "0a1b2c3"}},
+		"c": {SessionID: "c", Project: "proj", Date: "2026-03-05", Iteration: 1,
+			NotePath:     "Projects/proj/sessions/2026-03-05-01.md",
+			FilesChanged: []string{"cmd/vv/main.go"},
+			Decisions:    []string{"Keep context.go free of I/O", "Tune decay window per project via config"},
+			OpenThreads:  []string{"Unrelated thread"}},
+		"d": {SessionID: "d", Project: "other", Date: "2026-03-06",
+			NotePath:     "Projects/other/sessions/2026-03-06-01.md",
+			FilesChanged: []string{"internal/index/context.go"}},
+	}}
+}
+
+func TestMatchFile(t *testing.T) {
+	for _, tc := range []struct {
+		changed, file string
+		want          bool
+	}{
+		{"internal/index/context.go", "internal/index/context.go", true},
+		{"internal/index/context.go", "index/context.go", true},
+		{"internal/index/context.go", "./internal/index/context.go", true},
+		{"index/context.go", "/home/u/src/internal/index/context.go", true},
+		{"internal/index/context.go", "ontext.go", false},
+		{"internal/index/context.go", "internal/context/context.go", false},
+		{"", "context.go", false},
+	} {
+		if got := MatchFile(tc.changed, tc.file); got != tc.want {
+			t.Errorf("MatchFile(%q, %q) = %v, want %v", tc.changed, tc.file, got, tc.want)
+		}
+	}
+}
+
+func TestFileDossier(t *testing.T) {
+	d := dossierIndex().FileDossier("proj", "index/context.go")
+
+	if d.Path != "internal/index/context.go" {
+		t.Errorf("Path = %q, want recorded spelling", d.Path)
+	}
+	if len(d.Sessions) != 2 || d.Sessions[0].Note != "2026-02-10-01" || d.Sessions[1].Commits[1] != "0a1b2c3" {
+		t.Fatalf("Sessions = %+v", d.Sessions)
+	}
+	// Session a's decision (touched the file) and session c's (names it).
+	if len(d.Decisions) != 2 || d.Decisions[1].Text != "Keep context.go free of I/O" {
+		t.Errorf("Decisions = %+v", d.Decisions)
+	}
+	// a's thread is resolved by c's "Tune decay window per project via config".
+	if len(d.OpenThreads) != 0 {
+		t.Errorf("OpenThreads = %+v", d.OpenThreads)
+	}
+	want := []FileChurn{{Month: "2026-02", Sessions: 1, Commits: 1}, {Month: "2026-03", Sessions: 1, Commits: 2}}
+	if len(d.Churn) != 2 || d.Churn[0] != want[0] || d.Churn[1] != want[1] {
+		t.Errorf("Churn = %+v", d.Churn)
+	}
+
+	if empty := dossierIndex().FileDossier("proj", "nope.go"); len(empty.Sessions) != 0 || empty.Path != "nope.go" {
+		t.Errorf("unmatched dossier = %+v", empty)
+	}
+}
+
+func TestWriteFileDossier(t *testing.T) {
+	vault := t.TempDir()
+	idx := dossierIndex()
+	d := idx.FileDossier("proj", "internal/index/context.go")
+
+	rel, err := WriteFileDossier(vault, d, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel != "Projects/proj/files/internal/index/context.go.md" {
+		t.Errorf("rel = %q", rel)
+	}
+	data, err := os.ReadFile(filepath.Join(vault, filepath.FromSlash(rel)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"type: file-dossier\n", "sessions: 2\n", "last_touched: 2026-03-02\n",
+		"- [[2026-02-10-01]] — Added decay to key decisions `abc1234`",
+		"## Decisions\n\n- Decay decisions after 90 days ([[2026-02-10-01]])",
+		"| 2026-03 | 1 | 2 | █ |",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("missing %q in\n%s", want, data)
+		}
+	}
+
+	md := RenderFileDossier(d, flavor.Markdown, idx.NoteLinks("proj"))
+	if !strings.Contains(md, "[2026-02-10-01](../../../sessions/2026-02-10-01.md)") {
+		t.Errorf("markdown flavor links not relative:\n%s", md)
+	}
+
+	if got := FileDossierPath("proj", "~/../etc/passwd"); got != "Projects/proj/files/etc/passwd.md" {
+		t.Errorf("FileDossierPath escaped files/: %q", got)
+	}
+}
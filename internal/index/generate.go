@@ -7,6 +7,8 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+
+	"github.com/suykerbuyk/vibe-vault/internal/adr"
 )
 
 // GenerateResult holds metrics from a GenerateContext call.
@@ -22,7 +24,13 @@ func GenerateContext(idx *Index, vaultPath string, opts ContextOptions) (*Genera
 	// Generate per-project context documents
 	projectsDir := filepath.Join(vaultPath, "Projects")
 	for _, project := range idx.Projects() {
-		doc := idx.ProjectContext(project, opts)
+		popts := opts
+		records, err := adr.List(vaultPath, project)
+		if err != nil {
+			log.Printf("warning: read decisions for %s: %v", project, err)
+		}
+		popts.ADRs = records
+		doc := idx.ProjectContext(project, popts)
 		dir := filepath.Join(projectsDir, project)
 		if err := os.MkdirAll(dir, 0o755); err != nil {
 			log.Printf("warning: create dir for %s: %v", project, err)
@@ -6,8 +6,12 @@ package index
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/flavor"
 )
 
 func TestGenerateContext_WritesHistoryMd(t *testing.T) {
@@ -141,3 +145,23 @@ func TestGenerateContext_MultipleProjects(t *testing.T) {
 		}
 	}
 }
+
+func TestContextOptionsFor(t *testing.T) {
+	cfg := config.Config{VaultFlavor: "logseq"}
+	cfg.Friction.AlertThreshold = 40
+	cfg.History.TimelineRecentDays = 3
+	cfg.History.DecisionStaleDays = 60
+	cfg.Notes.Diagram = "gantt"
+
+	got := ContextOptionsFor(cfg)
+	want := ContextOptions{
+		AlertThreshold:     40,
+		TimelineRecentDays: 3,
+		DecisionStaleDays:  60,
+		ActivityChart:      true,
+		Flavor:             flavor.Logseq,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ContextOptionsFor = %+v, want %+v", got, want)
+	}
+}
@@ -1,6 +1,7 @@
 package index
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -9,7 +10,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/suykerbuyk/vibe-vault/internal/adr"
+	"github.com/suykerbuyk/vibe-vault/internal/flavor"
 	"github.com/suykerbuyk/vibe-vault/internal/mdutil"
+	"github.com/suykerbuyk/vibe-vault/internal/render"
+	"github.com/suykerbuyk/vibe-vault/internal/vaultcrypt"
 )
 
 // --- Backwards compat & enriched roundtrip ---
@@ -477,6 +482,47 @@ func TestRebuild(t *testing.T) {
 	}
 }
 
+func TestRebuildEncryptedNote(t *testing.T) {
+	projectsDir := filepath.Join(t.TempDir(), "Projects")
+	stateDir := t.TempDir()
+
+	key, err := vaultcrypt.NewKey(bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := key.Seal([]byte(goodNote))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const name = "2026-02-25-01.md"
+	writeNote(t, projectsDir, "myproject", name, render.EncryptedStub(goodNote, name+render.EncryptedPayloadExt))
+	writeNote(t, projectsDir, "myproject", name+render.EncryptedPayloadExt, string(sealed))
+
+	vaultcrypt.SetDefault(key)
+	t.Cleanup(func() { vaultcrypt.SetDefault(nil) })
+	idx, _, err := Rebuild(projectsDir, stateDir)
+	if err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+	e := idx.Entries["rebuild-001"]
+	if e.Summary != "Built rebuild command" || e.Branch != "feature/rebuild" || len(e.Decisions) != 1 {
+		t.Fatalf("entry not built from the decrypted note: %+v", e)
+	}
+	if err := idx.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without the key, the stub alone must not wipe the indexed content.
+	vaultcrypt.SetDefault(nil)
+	idx, _, err = Rebuild(projectsDir, stateDir)
+	if err != nil {
+		t.Fatalf("Rebuild without key: %v", err)
+	}
+	if got := idx.Entries["rebuild-001"]; got.Summary != e.Summary || len(got.Decisions) != 1 {
+		t.Errorf("keyless rebuild replaced content with the stub's: %+v", got)
+	}
+}
+
 func TestRebuildSkipsMalformed(t *testing.T) {
 	projectsDir := filepath.Join(t.TempDir(), "Projects")
 	stateDir := t.TempDir()
@@ -1065,6 +1111,75 @@ func TestProjectContextKeyFiles(t *testing.T) {
 	}
 }
 
+func TestProjectContextActivityMix(t *testing.T) {
+	idx := &Index{Entries: map[string]SessionEntry{
+		"a": {SessionID: "a", Project: "proj", Date: "2026-02-20", Iteration: 1, NotePath: "a.md",
+			ActivityCounts: map[string]int{"modify": 5, "test": 2}},
+		"b": {SessionID: "b", Project: "proj", Date: "2026-02-21", Iteration: 1, NotePath: "b.md",
+			ActivityCounts: map[string]int{"modify": 1, "commit": 2}},
+		"c": {SessionID: "c", Project: "proj", Date: "2026-02-22", Iteration: 1, NotePath: "c.md"},
+	}}
+	now := time.Date(2026, 2, 25, 0, 0, 0, 0, time.UTC)
+
+	if doc := idx.ProjectContext("proj", ContextOptions{Now: now}); contains(doc, "Activity Mix") {
+		t.Error("activity mix rendered without ActivityChart")
+	}
+
+	doc := idx.ProjectContext("proj", ContextOptions{Now: now, ActivityChart: true})
+	want := "## Activity Mix\n\n```mermaid\npie showData\n" +
+		"    title Activities across 2 sessions\n" +
+		"    \"modify\" : 6\n    \"commit\" : 2\n    \"test\" : 2\n```\n"
+	if !contains(doc, want) {
+		t.Errorf("activity mix missing or wrong:\n%s", doc)
+	}
+}
+
+func TestProjectContextFlavor(t *testing.T) {
+	idx := &Index{Entries: map[string]SessionEntry{
+		"a": {SessionID: "a", Project: "proj", Date: "2026-02-24", Iteration: 1,
+			NotePath: "Projects/proj/sessions/2026-02-24-01.md", Summary: "did a"},
+	}}
+	now := time.Date(2026, 2, 25, 0, 0, 0, 0, time.UTC)
+
+	md := idx.ProjectContext("proj", ContextOptions{Now: now, Flavor: flavor.Markdown})
+	if !contains(md, "- [2026-02-24-01](sessions/2026-02-24-01.md) — did a") {
+		t.Errorf("markdown flavor link wrong:\n%s", md)
+	}
+
+	ls := idx.ProjectContext("proj", ContextOptions{Now: now, Flavor: flavor.Logseq})
+	if !strings.HasPrefix(ls, "type:: project-context\nproject:: proj\n") {
+		t.Errorf("logseq flavor properties wrong:\n%s", ls)
+	}
+}
+
+func TestProjectContextADRs(t *testing.T) {
+	idx := &Index{Entries: map[string]SessionEntry{
+		"a": {SessionID: "a", Project: "proj", Date: "2026-02-24", Iteration: 1,
+			NotePath: "Projects/proj/sessions/2026-02-24-01.md",
+			Decisions: []string{"Use JWT tokens for auth", "Split parser package"}},
+	}}
+	opts := ContextOptions{
+		Now: time.Date(2026, 2, 25, 0, 0, 0, 0, time.UTC),
+		ADRs: []adr.Record{
+			{Number: 1, Slug: "use-cookies", Title: "Use cookies", Status: adr.Superseded},
+			{Number: 2, Slug: "use-jwt-tokens-for-auth", Title: "Use JWT tokens for auth", Status: adr.Accepted},
+			{Number: 3, Slug: "adopt-sqlite", Title: "Adopt SQLite", Status: adr.Proposed},
+		},
+	}
+	doc := idx.ProjectContext("proj", opts)
+	for _, want := range []string{
+		"## Architecture Decisions\n\n- [[0002-use-jwt-tokens-for-auth]] Use JWT tokens for auth\n- [[0003-adopt-sqlite]] Adopt SQLite *(proposed)*\n",
+		"## Key Decisions\n\n**2026-02-24**\n- Split parser package\n\n",
+	} {
+		if !contains(doc, want) {
+			t.Errorf("missing %q in:\n%s", want, doc)
+		}
+	}
+	if contains(doc, "0001-use-cookies") {
+		t.Errorf("superseded ADR listed:\n%s", doc)
+	}
+}
+
 func TestProjects(t *testing.T) {
 	idx := &Index{Entries: make(map[string]SessionEntry)}
 
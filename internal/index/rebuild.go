@@ -23,15 +23,17 @@ import (
 // AggregateProject so per-host bucketing, fast-path index reads, and
 // per-host attribution all live in one place. Rebuild is now a thin
 // orchestrator: enumerate projects, aggregate each, reconcile fields
-// the notes do not carry (TranscriptPath, ToolCounts) against the prior
+// the notes do not carry (TranscriptPath, ToolCounts, ActivityCounts) against the prior
 // on-disk index, and merge with cross-project collision detection.
 //
-// Malformed notes are logged and skipped by the aggregator. A
+// Encrypted notes are indexed from their decrypted payload (see
+// noteparse.ParseFile). Malformed notes are logged and skipped by the
+// aggregator. A
 // SessionID that appears in two projects (a fixture / corruption case)
 // surfaces as an error — defense-in-depth, since SessionIDs are UUIDs.
 func Rebuild(projectsDir, stateDir string) (*Index, int, error) {
 	// Load existing index to preserve fields the notes do not carry
-	// (TranscriptPath, ToolCounts). Errors collapse to an empty oldIdx;
+	// (TranscriptPath, ToolCounts, ActivityCounts). Errors collapse to an empty oldIdx;
 	// a missing prior index is the common first-run case.
 	oldIdx, _ := Load(stateDir)
 
@@ -80,13 +82,23 @@ func Rebuild(projectsDir, stateDir string) (*Index, int, error) {
 				log.Printf("rebuild: session_id %s collides between projects %s and %s; keeping last",
 					sid, existing.Project, entry.Project)
 			}
-			if old, ok := oldIdx.Entries[sid]; ok {
+			if old, ok := oldIdx.Entries[sid]; ok && entry.sealed {
+				// Without the key an encrypted note yields only its
+				// stub's identifying fields; the prior entry has the
+				// content.
+				log.Printf("rebuild: %s is encrypted and no key is configured; keeping its indexed content", entry.NotePath)
+				old.NotePath, old.Host = entry.NotePath, entry.Host
+				entry = old
+			} else if ok {
 				if old.TranscriptPath != "" {
 					entry.TranscriptPath = old.TranscriptPath
 				}
 				if len(old.ToolCounts) > 0 {
 					entry.ToolCounts = old.ToolCounts
 				}
+				if len(old.ActivityCounts) > 0 {
+					entry.ActivityCounts = old.ActivityCounts
+				}
 			}
 			idx.Entries[sid] = entry
 			count++
@@ -69,6 +69,9 @@ func (a *Anthropic) ChatCompletion(ctx context.Context, req Request) (*Response,
 	if isTransientStatus(resp.StatusCode) {
 		return nil, &TransientError{Err: fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))}
 	}
+	if isAuthStatus(resp.StatusCode) {
+		return nil, &AuthError{Err: fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))}
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
 	}
@@ -101,7 +104,13 @@ func (a *Anthropic) ChatCompletion(ctx context.Context, req Request) (*Response,
 		text = stripJSONFence(text)
 	}
 
-	return &Response{Content: text}, nil
+	return &Response{
+		Content: text,
+		Usage: UsageStats{
+			InputTokens:  antResp.Usage.InputTokens,
+			OutputTokens: antResp.Usage.OutputTokens,
+		},
+	}, nil
 }
 
 // stripJSONFence removes a surrounding ```json ... ``` or ``` ... ``` fence
@@ -142,9 +151,15 @@ type anthropicMessage struct {
 
 type anthropicResponse struct {
 	Content []anthropicContentBlock `json:"content"`
+	Usage   anthropicUsage          `json:"usage"`
 	Error   *anthropicError         `json:"error,omitempty"`
 }
 
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
 type anthropicContentBlock struct {
 	Type string `json:"type"`
 	Text string `json:"text,omitempty"`
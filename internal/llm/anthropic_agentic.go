@@ -98,7 +98,7 @@ func (a *AnthropicAgentic) RunTools(ctx context.Context, req ToolsRequest) (*Too
 	}
 
 	var lastAssistant []anthropicAgenticContentBlock
-	var lastUsage UsageStats
+	var lastUsage, totalUsage UsageStats
 
 	maxTokens := req.MaxTokens
 	if maxTokens <= 0 {
@@ -147,12 +147,15 @@ func (a *AnthropicAgentic) RunTools(ctx context.Context, req ToolsRequest) (*Too
 			InputTokens:  decoded.Usage.InputTokens,
 			OutputTokens: decoded.Usage.OutputTokens,
 		}
+		totalUsage.InputTokens += lastUsage.InputTokens
+		totalUsage.OutputTokens += lastUsage.OutputTokens
 
 		if decoded.StopReason != "tool_use" {
 			return &ToolsResponse{
 				StopReason: normalizeStopReason(decoded.StopReason),
 				Content:    fromWireBlocks(decoded.Content),
 				Usage:      lastUsage,
+				TotalUsage: totalUsage,
 			}, nil
 		}
 
@@ -196,6 +199,7 @@ func (a *AnthropicAgentic) RunTools(ctx context.Context, req ToolsRequest) (*Too
 		StopReason: "max_tokens",
 		Content:    fromWireBlocks(lastAssistant),
 		Usage:      lastUsage,
+		TotalUsage: totalUsage,
 	}, nil
 }
 
@@ -120,6 +120,9 @@ func TestAnthropicAgentic_SingleToolCall(t *testing.T) {
 	if resp.Usage.OutputTokens != 8 {
 		t.Errorf("Usage.OutputTokens = %d, want 8", resp.Usage.OutputTokens)
 	}
+	if resp.TotalUsage != (UsageStats{InputTokens: 30, OutputTokens: 13}) {
+		t.Errorf("TotalUsage = %+v, want 30 in / 13 out across both turns", resp.TotalUsage)
+	}
 
 	// Second request must echo the tool_result back to the model.
 	if len(*requests) != 2 {
@@ -34,6 +34,7 @@ func TestAnthropicChatCompletion(t *testing.T) {
 			Content: []anthropicContentBlock{
 				{Type: "text", Text: `{"summary":"from claude"}`},
 			},
+			Usage: anthropicUsage{InputTokens: 200, OutputTokens: 40},
 		}
 		json.NewEncoder(w).Encode(resp)
 	}))
@@ -56,6 +57,9 @@ func TestAnthropicChatCompletion(t *testing.T) {
 	if resp.Content != `{"summary":"from claude"}` {
 		t.Fatalf("got %q", resp.Content)
 	}
+	if resp.Usage != (UsageStats{InputTokens: 200, OutputTokens: 40}) {
+		t.Fatalf("usage = %+v, want 200 in / 40 out", resp.Usage)
+	}
 }
 
 func TestAnthropicName(t *testing.T) {
@@ -0,0 +1,371 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+)
+
+// CacheDirName is the cache's directory under the state dir.
+const CacheDirName = "llm-cache"
+
+// evictTarget is the fraction of the size limit eviction shrinks the cache
+// to, so a full cache is not rescanned on every subsequent write.
+const evictTarget = 0.9
+
+// Cache is a content-addressed on-disk store of LLM responses. Entries
+// live at <dir>/<key[:2]>/<key>.json; a hit refreshes the entry's mtime,
+// and eviction removes the least recently used entries first. Cache I/O
+// failures never fail a call — they degrade to a miss.
+type Cache struct {
+	dir      string
+	maxBytes int64
+
+	mu   sync.Mutex
+	size int64 // bytes on disk; -1 until the first write scans the dir
+	now  func() time.Time
+}
+
+// cacheEntry is the on-disk form of one cached response.
+type cacheEntry struct {
+	Provider string     `json:"provider"`
+	Model    string     `json:"model"`
+	Created  time.Time  `json:"created"`
+	Content  string     `json:"content"`
+	Usage    UsageStats `json:"usage"`
+}
+
+// cacheKey is every request field that can change the response.
+type cacheKey struct {
+	Provider    string  `json:"provider"`
+	Model       string  `json:"model"`
+	System      string  `json:"system"`
+	UserPrompt  string  `json:"user_prompt"`
+	Temperature float64 `json:"temperature"`
+	JSONMode    bool    `json:"json_mode"`
+	MaxTokens   int     `json:"max_tokens"`
+	Schema      string  `json:"schema,omitempty"`
+}
+
+// NewCache returns a cache rooted at dir holding at most maxBytes
+// (0 = unlimited). The directory is created on first write.
+func NewCache(dir string, maxBytes int64) *Cache {
+	return &Cache{dir: dir, maxBytes: maxBytes, size: -1, now: time.Now}
+}
+
+// CacheDir returns the cache directory for cfg.
+func CacheDir(cfg config.Config) string {
+	return filepath.Join(cfg.StateDir(), CacheDirName)
+}
+
+// OpenCache returns the cache configured by cfg.LLM, or nil when caching
+// is disabled.
+func OpenCache(cfg config.Config) *Cache {
+	if !cfg.LLM.Cache {
+		return nil
+	}
+	return NewCache(CacheDir(cfg), int64(cfg.LLM.CacheMaxMB)<<20)
+}
+
+// ErrCacheMiss is returned by a WithCacheOnly provider for a request
+// the cache cannot answer.
+var ErrCacheMiss = errors.New("llm: response not cached")
+
+// cacheProvider serves ChatCompletion from a Cache, falling through to
+// the wrapped provider on a miss unless cacheOnly is set.
+type cacheProvider struct {
+	inner     Provider
+	cache     *Cache
+	model     string // key model when a request leaves Model empty
+	cacheOnly bool
+}
+
+// WithCache wraps a Provider so identical requests are answered from c.
+// model is the provider's configured default model, used in the key for
+// requests that leave Model empty. A nil cache returns p unchanged.
+func WithCache(p Provider, c *Cache, model string) Provider {
+	if p == nil || c == nil {
+		return p
+	}
+	return &cacheProvider{inner: p, cache: c, model: model}
+}
+
+// WithCacheOnly wraps a Provider so requests are answered from c and
+// never reach p; a miss, or a nil cache, returns ErrCacheMiss. It lets a
+// preview re-render with the enrichment it already paid for.
+func WithCacheOnly(p Provider, c *Cache, model string) Provider {
+	if p == nil {
+		return nil
+	}
+	return &cacheProvider{inner: p, cache: c, model: model, cacheOnly: true}
+}
+
+func (c *cacheProvider) Name() string { return c.inner.Name() }
+
+func (c *cacheProvider) ChatCompletion(ctx context.Context, req Request) (*Response, error) {
+	model := req.Model
+	if model == "" {
+		model = c.model
+	}
+	if c.cache == nil {
+		return nil, ErrCacheMiss
+	}
+	key := c.cache.Key(c.inner.Name(), model, req)
+	_, chain := c.inner.(*FallbackProvider)
+	if e, ok := c.cache.Get(key); ok {
+		resp := &Response{Content: e.Content, Usage: e.Usage, Cached: true}
+		if chain {
+			resp.Provider, resp.Model = e.Provider, e.Model
+		}
+		return resp, nil
+	}
+	if c.cacheOnly {
+		return nil, ErrCacheMiss
+	}
+
+	resp, err := c.inner.ChatCompletion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil && resp.Content != "" {
+		// Entries record who actually served the call, which for a
+		// fallback chain may not be the primary the key names.
+		e := cacheEntry{Provider: c.inner.Name(), Model: model, Content: resp.Content, Usage: resp.Usage}
+		if resp.Provider != "" {
+			e.Provider, e.Model = resp.Provider, resp.Model
+		}
+		if err := c.cache.Put(key, e); err != nil {
+			log.Printf("warning: llm cache write: %v", err)
+		}
+	}
+	return resp, nil
+}
+
+// Key returns the content address of a request: a SHA-256 over the
+// provider, model, system and user prompts, temperature, JSON mode,
+// token cap, and response schema.
+func (c *Cache) Key(provider, model string, req Request) string {
+	data, _ := json.Marshal(cacheKey{
+		Provider:    provider,
+		Model:       model,
+		System:      req.System,
+		UserPrompt:  req.UserPrompt,
+		Temperature: req.Temperature,
+		JSONMode:    req.JSONMode,
+		MaxTokens:   req.MaxTokens,
+		Schema:      req.Schema.fingerprint(),
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// Get returns the cached entry for key and marks it used.
+func (c *Cache) Get(key string) (cacheEntry, bool) {
+	p := c.path(key)
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return cacheEntry{}, false
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(data, &e); err != nil || e.Content == "" {
+		return cacheEntry{}, false
+	}
+	now := c.now()
+	_ = os.Chtimes(p, now, now)
+	return e, true
+}
+
+// Put stores an entry under key, evicting least recently used entries
+// when the cache exceeds its size limit.
+func (c *Cache) Put(key string, e cacheEntry) error {
+	if e.Created.IsZero() {
+		e.Created = c.now().UTC()
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	p := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	prev := int64(0)
+	if info, err := os.Stat(p); err == nil {
+		prev = info.Size()
+	}
+	// Write-then-rename so a concurrent reader (hook vs. reprocess) never
+	// sees a partial entry.
+	tmp, err := os.CreateTemp(filepath.Dir(p), key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if werr == nil {
+		werr = cerr
+	}
+	if werr == nil {
+		werr = os.Rename(tmp.Name(), p)
+	}
+	if werr != nil {
+		os.Remove(tmp.Name())
+		return werr
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.size < 0 {
+		c.size = c.scanSize()
+	} else {
+		c.size += int64(len(data)) - prev
+	}
+	if c.maxBytes > 0 && c.size > c.maxBytes {
+		c.size = c.evictLocked()
+	}
+	return nil
+}
+
+// cacheFile is one entry file found by a directory walk.
+type cacheFile struct {
+	path  string
+	size  int64
+	mtime time.Time
+}
+
+func (c *Cache) files() ([]cacheFile, error) {
+	var out []cacheFile
+	err := filepath.WalkDir(c.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(p, ".json") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		out = append(out, cacheFile{path: p, size: info.Size(), mtime: info.ModTime()})
+		return nil
+	})
+	return out, err
+}
+
+func (c *Cache) scanSize() int64 {
+	files, _ := c.files()
+	var n int64
+	for _, f := range files {
+		n += f.size
+	}
+	return n
+}
+
+// evictLocked removes the least recently used entries until the cache is
+// under evictTarget of its limit, returning the new size.
+func (c *Cache) evictLocked() int64 {
+	files, _ := c.files()
+	var size int64
+	for _, f := range files {
+		size += f.size
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].mtime.Before(files[j].mtime) })
+	target := int64(float64(c.maxBytes) * evictTarget)
+	for _, f := range files {
+		if size <= target {
+			break
+		}
+		if os.Remove(f.path) == nil {
+			size -= f.size
+		}
+	}
+	return size
+}
+
+// CacheStats summarizes the cache's contents.
+type CacheStats struct {
+	Dir      string         `json:"dir"`
+	Entries  int            `json:"entries"`
+	Bytes    int64          `json:"bytes"`
+	MaxBytes int64          `json:"max_bytes"`
+	Oldest   time.Time      `json:"oldest"`
+	Newest   time.Time      `json:"newest"`
+	ByModel  map[string]int `json:"by_model,omitempty"` // "provider/model" → entries
+}
+
+// Stats walks the cache and reports its size and per-model entry counts.
+func (c *Cache) Stats() (CacheStats, error) {
+	st := CacheStats{Dir: c.dir, MaxBytes: c.maxBytes, ByModel: make(map[string]int)}
+	files, err := c.files()
+	if err != nil {
+		return st, fmt.Errorf("read cache: %w", err)
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			continue
+		}
+		var e cacheEntry
+		if json.Unmarshal(data, &e) != nil {
+			continue
+		}
+		st.Entries++
+		st.Bytes += f.size
+		st.ByModel[e.Provider+"/"+e.Model]++
+		if st.Oldest.IsZero() || e.Created.Before(st.Oldest) {
+			st.Oldest = e.Created
+		}
+		if e.Created.After(st.Newest) {
+			st.Newest = e.Created
+		}
+	}
+	return st, nil
+}
+
+// Clear deletes every cached entry, returning how many were removed.
+func (c *Cache) Clear() (int, error) {
+	files, err := c.files()
+	if err != nil {
+		return 0, fmt.Errorf("read cache: %w", err)
+	}
+	n := 0
+	for _, f := range files {
+		if err := os.Remove(f.path); err == nil {
+			n++
+		}
+	}
+	// Drop the now-empty shard directories.
+	if shards, err := os.ReadDir(c.dir); err == nil {
+		for _, s := range shards {
+			if s.IsDir() {
+				os.Remove(filepath.Join(c.dir, s.Name()))
+			}
+		}
+	}
+	c.mu.Lock()
+	c.size = -1
+	c.mu.Unlock()
+	return n, nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package llm
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCache_HitSkipsProvider(t *testing.T) {
+	mock := &mockProvider{}
+	p := WithCache(mock, NewCache(t.TempDir(), 0), "m1")
+	req := Request{System: "sys", UserPrompt: "summarize", Temperature: 0.2, JSONMode: true}
+
+	for i := 0; i < 3; i++ {
+		resp, err := p.ChatCompletion(context.Background(), req)
+		if err != nil || resp.Content != "ok" {
+			t.Fatalf("call %d: %v %v", i, resp, err)
+		}
+	}
+	if mock.calls != 1 {
+		t.Errorf("provider calls = %d, want 1", mock.calls)
+	}
+}
+
+func TestWithCacheOnly_NeverCallsProvider(t *testing.T) {
+	mock := &mockProvider{}
+	cache := NewCache(t.TempDir(), 0)
+	req := Request{UserPrompt: "summarize"}
+
+	only := WithCacheOnly(mock, cache, "m1")
+	if _, err := only.ChatCompletion(context.Background(), req); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("miss: err = %v, want ErrCacheMiss", err)
+	}
+	if _, err := WithCache(mock, cache, "m1").ChatCompletion(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := only.ChatCompletion(context.Background(), req)
+	if err != nil || resp.Content != "ok" || !resp.Cached {
+		t.Errorf("hit: %+v, %v", resp, err)
+	}
+	if mock.calls != 1 {
+		t.Errorf("provider calls = %d, want 1 (the warming call)", mock.calls)
+	}
+	if _, err := WithCacheOnly(mock, nil, "m1").ChatCompletion(context.Background(), req); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("nil cache: err = %v, want ErrCacheMiss", err)
+	}
+}
+
+func TestCache_KeyCoversRequestFields(t *testing.T) {
+	c := NewCache(t.TempDir(), 0)
+	base := Request{System: "s", UserPrompt: "u", Temperature: 0.2}
+	k := c.Key("openai", "m", base)
+
+	variants := map[string]string{
+		"provider": c.Key("anthropic", "m", base),
+		"model":    c.Key("openai", "m2", base),
+	}
+	for name, mut := range map[string]func(*Request){
+		"system":      func(r *Request) { r.System = "s2" },
+		"user":        func(r *Request) { r.UserPrompt = "u2" },
+		"temperature": func(r *Request) { r.Temperature = 0.3 },
+		"json":        func(r *Request) { r.JSONMode = true },
+		"max_tokens":  func(r *Request) { r.MaxTokens = 100 },
+		"schema":      func(r *Request) { r.Schema = &Schema{Title: "t", Type: "object"} },
+	} {
+		r := base
+		mut(&r)
+		variants[name] = c.Key("openai", "m", r)
+	}
+	for name, v := range variants {
+		if v == k {
+			t.Errorf("changing %s did not change the key", name)
+		}
+	}
+	if c.Key("openai", "m", base) != k {
+		t.Error("key is not deterministic")
+	}
+}
+
+func TestCache_ErrorsAndEmptyNotCached(t *testing.T) {
+	dir := t.TempDir()
+	mock := &mockProvider{err: os.ErrDeadlineExceeded}
+	p := WithCache(mock, NewCache(dir, 0), "m")
+	p.ChatCompletion(context.Background(), Request{UserPrompt: "x"})
+	p.ChatCompletion(context.Background(), Request{UserPrompt: "x"})
+	if mock.calls != 2 {
+		t.Errorf("failed responses were cached: calls = %d", mock.calls)
+	}
+	if st, _ := NewCache(dir, 0).Stats(); st.Entries != 0 {
+		t.Errorf("entries = %d, want 0", st.Entries)
+	}
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	c := NewCache(dir, 0)
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { clock = clock.Add(time.Minute); return clock }
+
+	content := strings.Repeat("x", 1000)
+	keys := []string{}
+	for _, prompt := range []string{"a", "b", "c"} {
+		k := c.Key("p", "m", Request{UserPrompt: prompt})
+		keys = append(keys, k)
+		if err := c.Put(k, cacheEntry{Provider: "p", Model: "m", Content: content}); err != nil {
+			t.Fatal(err)
+		}
+		now := c.now()
+		os.Chtimes(c.path(k), now, now)
+	}
+	// Touch "a" so "b" becomes the least recently used entry.
+	if _, ok := c.Get(keys[0]); !ok {
+		t.Fatal("miss on a")
+	}
+
+	info, _ := os.Stat(c.path(keys[0]))
+	c.maxBytes = 3*info.Size() - 1
+	k := c.Key("p", "m", Request{UserPrompt: "d"})
+	if err := c.Put(k, cacheEntry{Provider: "p", Model: "m", Content: content}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []bool{true, false, false} {
+		if _, ok := c.Get(keys[i]); ok != want {
+			t.Errorf("entry %c present = %v, want %v", 'a'+i, ok, want)
+		}
+	}
+	if _, ok := c.Get(k); !ok {
+		t.Error("newest entry evicted")
+	}
+}
+
+func TestCache_StatsAndClear(t *testing.T) {
+	dir := t.TempDir()
+	c := NewCache(dir, 1<<20)
+	p := WithCache(&mockProvider{}, c, "m1")
+	p.ChatCompletion(context.Background(), Request{UserPrompt: "one"})
+	p.ChatCompletion(context.Background(), Request{UserPrompt: "two", Model: "m2"})
+
+	st, err := c.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Entries != 2 || st.ByModel["mock/m1"] != 1 || st.ByModel["mock/m2"] != 1 || st.Bytes == 0 {
+		t.Errorf("stats = %+v", st)
+	}
+
+	n, err := c.Clear()
+	if err != nil || n != 2 {
+		t.Fatalf("Clear = %d, %v", n, err)
+	}
+	if shards, _ := os.ReadDir(dir); len(shards) != 0 {
+		t.Errorf("shard dirs left behind: %v", shards)
+	}
+	if st, _ := c.Stats(); st.Entries != 0 {
+		t.Errorf("entries after clear = %d", st.Entries)
+	}
+	if _, err := NewCache(filepath.Join(dir, "missing"), 0).Stats(); err != nil {
+		t.Errorf("stats on missing dir: %v", err)
+	}
+}
+
+func TestWithCache_NilPassthrough(t *testing.T) {
+	mock := &mockProvider{}
+	if p := WithCache(mock, nil, "m"); p != Provider(mock) {
+		t.Error("nil cache should return the provider unchanged")
+	}
+	if p := WithCache(nil, NewCache(t.TempDir(), 0), "m"); p != nil {
+		t.Error("nil provider should stay nil")
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Cassette modes.
+const (
+	// CassetteReplay answers only from the cassette; a request with no
+	// recorded interaction fails with ErrCassetteMiss.
+	CassetteReplay = "replay"
+	// CassetteRecord replays recorded interactions and sends anything
+	// new to the inner provider, recording the result.
+	CassetteRecord = "record"
+)
+
+// ErrCassetteMiss is returned in replay mode for an unrecorded request.
+var ErrCassetteMiss = errors.New("no recorded interaction for request")
+
+const cassetteVersion = 1
+
+// Interaction is one recorded request/response pair. The request is kept
+// in full so a cassette reads as a reviewable transcript of what was sent.
+type Interaction struct {
+	Key      string           `json:"key"`
+	Provider string           `json:"provider"`
+	Model    string           `json:"model,omitempty"`
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest is the recorded form of a Request.
+type CassetteRequest struct {
+	System      string  `json:"system"`
+	UserPrompt  string  `json:"user_prompt"`
+	Temperature float64 `json:"temperature"`
+	JSONMode    bool    `json:"json_mode,omitempty"`
+	MaxTokens   int     `json:"max_tokens,omitempty"`
+	Schema      string  `json:"schema,omitempty"` // Schema.Title
+}
+
+// CassetteResponse is the recorded form of a Response.
+type CassetteResponse struct {
+	Content string     `json:"content"`
+	Usage   UsageStats `json:"usage"`
+}
+
+type cassetteFile struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Cassette is a Provider that replays recorded interactions from a JSON
+// file and, in record mode, captures new ones from an inner provider.
+// Requests are matched on prompt content alone — system and user prompt,
+// temperature, JSON mode, token cap, and schema — so a cassette replays
+// the same way whichever provider or model is configured. Call Save to
+// persist newly recorded interactions.
+type Cassette struct {
+	path  string
+	mode  string
+	inner Provider
+
+	mu           sync.Mutex
+	interactions []Interaction
+	index        map[string]int
+	hits, misses int
+	dirty        bool
+}
+
+// OpenCassette loads the cassette at path; a missing file starts empty.
+// inner may be nil in replay mode.
+func OpenCassette(path, mode string, inner Provider) (*Cassette, error) {
+	if mode != CassetteReplay && mode != CassetteRecord {
+		return nil, fmt.Errorf("unknown cassette mode %q", mode)
+	}
+	if mode == CassetteRecord && inner == nil {
+		return nil, fmt.Errorf("cassette record mode needs a provider")
+	}
+	c := &Cassette{path: path, mode: mode, inner: inner, index: make(map[string]int)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f cassetteFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse cassette %s: %w", path, err)
+	}
+	if f.Version != cassetteVersion {
+		return nil, fmt.Errorf("cassette %s: unsupported version %d", path, f.Version)
+	}
+	for _, in := range f.Interactions {
+		c.add(in)
+	}
+	return c, nil
+}
+
+// Name returns the inner provider's name, or "cassette" when replaying
+// without one.
+func (c *Cassette) Name() string {
+	if c.inner != nil {
+		return c.inner.Name()
+	}
+	return "cassette"
+}
+
+// ChatCompletion replays the recorded response for req, or in record mode
+// forwards an unrecorded request to the inner provider and records it.
+// Replayed responses carry the recorded usage and the recording's
+// provider and model.
+func (c *Cassette) ChatCompletion(ctx context.Context, req Request) (*Response, error) {
+	key := cassetteKey(req)
+	c.mu.Lock()
+	i, ok := c.index[key]
+	var in Interaction
+	if ok {
+		in = c.interactions[i]
+		c.hits++
+	} else {
+		c.misses++
+	}
+	c.mu.Unlock()
+	if ok {
+		return &Response{
+			Content:  in.Response.Content,
+			Usage:    in.Response.Usage,
+			Provider: in.Provider,
+			Model:    in.Model,
+		}, nil
+	}
+	if c.mode == CassetteReplay {
+		return nil, fmt.Errorf("cassette %s: %w", filepath.Base(c.path), ErrCassetteMiss)
+	}
+
+	resp, err := c.inner.ChatCompletion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	in = Interaction{
+		Key:      key,
+		Provider: c.inner.Name(),
+		Model:    req.Model,
+		Request:  recordRequest(req),
+		Response: CassetteResponse{Content: resp.Content, Usage: resp.Usage},
+	}
+	if resp.Provider != "" {
+		in.Provider, in.Model = resp.Provider, resp.Model
+	}
+	c.mu.Lock()
+	c.add(in)
+	c.dirty = true
+	c.mu.Unlock()
+	return resp, nil
+}
+
+func (c *Cassette) add(in Interaction) {
+	if i, ok := c.index[in.Key]; ok {
+		c.interactions[i] = in
+		return
+	}
+	c.index[in.Key] = len(c.interactions)
+	c.interactions = append(c.interactions, in)
+}
+
+// Stats reports how many requests were replayed and how many were not
+// found on the cassette (recorded, in record mode).
+func (c *Cassette) Stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// Save writes the cassette when it gained interactions.
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+	data, err := json.MarshalIndent(cassetteFile{Version: cassetteVersion, Interactions: c.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+func recordRequest(req Request) CassetteRequest {
+	r := CassetteRequest{
+		System:      req.System,
+		UserPrompt:  req.UserPrompt,
+		Temperature: req.Temperature,
+		JSONMode:    req.JSONMode,
+		MaxTokens:   req.MaxTokens,
+	}
+	if req.Schema != nil {
+		r.Schema = req.Schema.Title
+	}
+	return r
+}
+
+// cassetteKey hashes the prompt-defining fields of req. Unlike the
+// response cache key it leaves out provider and model.
+func cassetteKey(req Request) string {
+	data, _ := json.Marshal(cacheKey{
+		System:      req.System,
+		UserPrompt:  req.UserPrompt,
+		Temperature: req.Temperature,
+		JSONMode:    req.JSONMode,
+		MaxTokens:   req.MaxTokens,
+		Schema:      req.Schema.fingerprint(),
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// PromptVersion fingerprints the prompts and schemas that define an LLM
+// task: a 12-character hash that changes whenever any of them does. Eval
+// reports carry it so a diff can tell which prompt revision produced each.
+func PromptVersion(parts ...any) string {
+	h := sha256.New()
+	for _, p := range parts {
+		data, _ := json.Marshal(p)
+		h.Write(data)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
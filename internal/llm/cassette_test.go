@@ -0,0 +1,123 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package llm
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCassette_RecordThenReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	inner := &replyProvider{replies: []string{`{"tag":"a"}`, `{"tag":"b"}`}}
+	req := Request{Model: "gpt-4o-mini", System: "s", UserPrompt: "u1", Temperature: 0.3, Schema: testSchema}
+
+	rec, err := OpenCassette(path, CassetteRecord, inner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := rec.ChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A repeat within the session replays rather than calling again.
+	if _, err := rec.ChatCompletion(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if len(inner.reqs) != 1 {
+		t.Fatalf("inner calls = %d, want 1", len(inner.reqs))
+	}
+	if hits, misses := rec.Stats(); hits != 1 || misses != 1 {
+		t.Errorf("stats = %d hits, %d misses; want 1, 1", hits, misses)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replay under a different model: the key ignores provider and model.
+	play, err := OpenCassette(path, CassetteReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Model = "claude-haiku"
+	got, err := play.ChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Content != first.Content || got.Usage != first.Usage {
+		t.Errorf("replayed %+v, want %+v", got, first)
+	}
+	if got.Provider != "reply" || got.Model != "gpt-4o-mini" {
+		t.Errorf("replayed provider/model = %q/%q, want reply/gpt-4o-mini", got.Provider, got.Model)
+	}
+}
+
+func TestCassette_ReplayMiss(t *testing.T) {
+	play, err := OpenCassette(filepath.Join(t.TempDir(), "none.json"), CassetteReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = play.ChatCompletion(context.Background(), Request{UserPrompt: "u"})
+	if !errors.Is(err, ErrCassetteMiss) {
+		t.Fatalf("err = %v, want ErrCassetteMiss", err)
+	}
+}
+
+func TestCassette_KeyCoversPromptFields(t *testing.T) {
+	base := Request{System: "s", UserPrompt: "u", Temperature: 0.3}
+	variants := []Request{
+		{System: "s2", UserPrompt: "u", Temperature: 0.3},
+		{System: "s", UserPrompt: "u2", Temperature: 0.3},
+		{System: "s", UserPrompt: "u", Temperature: 0},
+		{System: "s", UserPrompt: "u", Temperature: 0.3, JSONMode: true},
+		{System: "s", UserPrompt: "u", Temperature: 0.3, Schema: testSchema},
+	}
+	for i, v := range variants {
+		if cassetteKey(v) == cassetteKey(base) {
+			t.Errorf("variant %d shares the base key", i)
+		}
+	}
+	same := base
+	same.Model = "other"
+	if cassetteKey(same) != cassetteKey(base) {
+		t.Error("model changed the key")
+	}
+}
+
+func TestCassette_SaveOnlyWhenDirty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	c, err := OpenCassette(path, CassetteRecord, &replyProvider{replies: []string{"x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("empty cassette was written: %v", err)
+	}
+}
+
+func TestCassette_RejectsUnknownVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := os.WriteFile(path, []byte(`{"version":99,"interactions":[]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenCassette(path, CassetteReplay, nil); err == nil {
+		t.Fatal("want error for unknown version")
+	}
+}
+
+func TestPromptVersion(t *testing.T) {
+	a := PromptVersion("system", testSchema)
+	if len(a) != 12 || a != PromptVersion("system", testSchema) {
+		t.Fatalf("PromptVersion = %q, want a stable 12-char hash", a)
+	}
+	if a == PromptVersion("system!", testSchema) || a == PromptVersion("system") {
+		t.Error("PromptVersion ignored a changed part")
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+)
+
+// BreakerFileName is the circuit breaker's state file under the state dir.
+const BreakerFileName = "llm-breaker.json"
+
+const (
+	// breakerThreshold is the consecutive failover-worthy failures after
+	// which a link's circuit opens.
+	breakerThreshold = 3
+	// breakerCooldown is how long an open circuit skips its link before a
+	// single trial call is let through.
+	breakerCooldown = 10 * time.Minute
+)
+
+// fallbackLink is one provider/model in a FallbackProvider chain.
+type fallbackLink struct {
+	provider string
+	model    string
+	p        Provider
+}
+
+func (l fallbackLink) key() string { return l.provider + "/" + l.model }
+
+// FallbackProvider tries an ordered chain of providers, failing over to
+// the next link on transient (429/5xx/network) and auth (401/403) errors.
+// Any other error — a malformed request, an unparseable body — is
+// returned as-is, since the next provider would likely fail the same way.
+// Responses carry the Provider and Model of the link that served them.
+type FallbackProvider struct {
+	links   []fallbackLink
+	breaker *Breaker
+}
+
+// Name returns the primary link's provider name.
+func (f *FallbackProvider) Name() string { return f.links[0].provider }
+
+// ChatCompletion sends req down the chain. req.Model, when set, applies
+// to the primary link only; fallbacks always use their configured model.
+// Links whose circuit is open are skipped.
+func (f *FallbackProvider) ChatCompletion(ctx context.Context, req Request) (*Response, error) {
+	var errs []error
+	for i, l := range f.links {
+		key := l.key()
+		if !f.breaker.Allow(key) {
+			errs = append(errs, fmt.Errorf("%s: circuit open", key))
+			continue
+		}
+		r := req
+		if i > 0 || r.Model == "" {
+			r.Model = l.model
+		}
+		resp, err := l.p.ChatCompletion(ctx, r)
+		if err == nil {
+			f.breaker.Success(key)
+			resp.Provider, resp.Model = l.provider, r.Model
+			return resp, nil
+		}
+		if ctx.Err() != nil || !(isTransient(err) || isAuth(err)) {
+			return nil, err
+		}
+		f.breaker.Failure(key)
+		errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		if i+1 < len(f.links) {
+			log.Printf("warning: llm %s failed, failing over: %v", key, err)
+		}
+	}
+	return nil, fmt.Errorf("all providers failed: %w", errors.Join(errs...))
+}
+
+// Breaker is a per-link circuit breaker. With a path its state persists
+// across processes, so a provider that failed in one hook invocation is
+// skipped by the next rather than retried on every session end.
+type Breaker struct {
+	path string // "" = in-memory only
+
+	mu    sync.Mutex
+	state map[string]breakerEntry
+	now   func() time.Time
+}
+
+type breakerEntry struct {
+	Failures  int       `json:"failures"`
+	OpenUntil time.Time `json:"open_until,omitzero"`
+}
+
+// NewBreaker returns a breaker persisting to path, or held in memory when
+// path is empty.
+func NewBreaker(path string) *Breaker {
+	return &Breaker{path: path, state: make(map[string]breakerEntry), now: time.Now}
+}
+
+// BreakerPath returns the breaker state file for cfg.
+func BreakerPath(cfg config.Config) string {
+	return filepath.Join(cfg.StateDir(), BreakerFileName)
+}
+
+// Allow reports whether key may be called: its circuit is closed, or its
+// cooldown has elapsed and a trial call is due.
+func (b *Breaker) Allow(key string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.load()
+	e := b.state[key]
+	return e.OpenUntil.IsZero() || !b.now().Before(e.OpenUntil)
+}
+
+// Failure counts a failover-worthy error for key, opening its circuit at
+// breakerThreshold consecutive failures. A failed trial call after the
+// cooldown reopens it immediately.
+func (b *Breaker) Failure(key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.load()
+	e := b.state[key]
+	e.Failures++
+	if e.Failures >= breakerThreshold {
+		e.OpenUntil = b.now().Add(breakerCooldown).UTC()
+	}
+	b.state[key] = e
+	b.save()
+}
+
+// Success closes key's circuit.
+func (b *Breaker) Success(key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.load()
+	if _, ok := b.state[key]; !ok {
+		return
+	}
+	delete(b.state, key)
+	b.save()
+}
+
+// load refreshes state from disk so concurrent processes see each
+// other's failures. A missing or corrupt file reads as all-closed.
+func (b *Breaker) load() {
+	if b.path == "" {
+		return
+	}
+	data, err := os.ReadFile(b.path)
+	if err != nil {
+		b.state = make(map[string]breakerEntry)
+		return
+	}
+	state := make(map[string]breakerEntry)
+	if json.Unmarshal(data, &state) == nil {
+		b.state = state
+	}
+}
+
+func (b *Breaker) save() {
+	if b.path == "" {
+		return
+	}
+	data, err := json.MarshalIndent(b.state, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(b.path), 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(b.path, data, 0o644)
+	}
+	if err != nil {
+		log.Printf("warning: llm breaker state: %v", err)
+	}
+}
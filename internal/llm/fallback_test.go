@@ -0,0 +1,182 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+)
+
+// scriptedProvider fails with err (when set) and records the models asked for.
+type scriptedProvider struct {
+	name   string
+	err    error
+	models []string
+}
+
+func (s *scriptedProvider) Name() string { return s.name }
+
+func (s *scriptedProvider) ChatCompletion(_ context.Context, req Request) (*Response, error) {
+	s.models = append(s.models, req.Model)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &Response{Content: "from " + s.name}, nil
+}
+
+func newTestChain(b *Breaker, ps ...*scriptedProvider) *FallbackProvider {
+	f := &FallbackProvider{breaker: b}
+	for i, p := range ps {
+		f.links = append(f.links, fallbackLink{provider: p.name, model: fmt.Sprintf("m%d", i), p: p})
+	}
+	return f
+}
+
+func TestFallback_FailsOverOnTransientAndAuth(t *testing.T) {
+	for name, err := range map[string]error{
+		"transient": &TransientError{Err: errors.New("503")},
+		"auth":      &AuthError{Err: errors.New("401")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			primary := &scriptedProvider{name: "grok", err: err}
+			backup := &scriptedProvider{name: "anthropic"}
+			f := newTestChain(NewBreaker(""), primary, backup)
+
+			resp, rerr := f.ChatCompletion(context.Background(), Request{UserPrompt: "x"})
+			if rerr != nil {
+				t.Fatal(rerr)
+			}
+			if resp.Content != "from anthropic" || resp.Provider != "anthropic" || resp.Model != "m1" {
+				t.Errorf("resp = %+v, want served by anthropic/m1", resp)
+			}
+			if f.Name() != "grok" {
+				t.Errorf("Name() = %q, want primary", f.Name())
+			}
+		})
+	}
+}
+
+func TestFallback_PermanentErrorStops(t *testing.T) {
+	primary := &scriptedProvider{name: "grok", err: errors.New("API error (status 400)")}
+	backup := &scriptedProvider{name: "anthropic"}
+	f := newTestChain(NewBreaker(""), primary, backup)
+
+	if _, err := f.ChatCompletion(context.Background(), Request{}); err == nil {
+		t.Fatal("expected the 400 to be returned")
+	}
+	if len(backup.models) != 0 {
+		t.Error("backup should not be called for a non-failover error")
+	}
+}
+
+func TestFallback_RequestModelAppliesToPrimaryOnly(t *testing.T) {
+	primary := &scriptedProvider{name: "grok", err: &TransientError{Err: errors.New("503")}}
+	backup := &scriptedProvider{name: "anthropic"}
+	f := newTestChain(NewBreaker(""), primary, backup)
+
+	if _, err := f.ChatCompletion(context.Background(), Request{Model: "override"}); err != nil {
+		t.Fatal(err)
+	}
+	if primary.models[0] != "override" || backup.models[0] != "m1" {
+		t.Errorf("models = %v / %v, want override / m1", primary.models, backup.models)
+	}
+}
+
+func TestFallback_AllFail(t *testing.T) {
+	f := newTestChain(NewBreaker(""),
+		&scriptedProvider{name: "grok", err: &TransientError{Err: errors.New("503")}},
+		&scriptedProvider{name: "anthropic", err: &AuthError{Err: errors.New("401")}},
+	)
+	_, err := f.ChatCompletion(context.Background(), Request{})
+	if err == nil || !isTransient(err) || !isAuth(err) {
+		t.Fatalf("err = %v, want both causes joined", err)
+	}
+}
+
+func TestFallback_BreakerSkipsDeadProviderAcrossProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), BreakerFileName)
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	primary := &scriptedProvider{name: "grok", err: &TransientError{Err: errors.New("503")}}
+	backup := &scriptedProvider{name: "anthropic"}
+	for i := 0; i < breakerThreshold; i++ {
+		// A fresh chain and breaker per call, as in separate hook processes.
+		b := NewBreaker(path)
+		b.now = clock
+		if _, err := newTestChain(b, primary, backup).ChatCompletion(context.Background(), Request{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(primary.models) != breakerThreshold {
+		t.Fatalf("primary calls = %d, want %d", len(primary.models), breakerThreshold)
+	}
+
+	b := NewBreaker(path)
+	b.now = clock
+	if _, err := newTestChain(b, primary, backup).ChatCompletion(context.Background(), Request{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(primary.models) != breakerThreshold {
+		t.Error("open circuit should skip the primary")
+	}
+
+	// After the cooldown one trial call goes through; success closes it.
+	now = now.Add(breakerCooldown + time.Second)
+	primary.err = nil
+	resp, err := newTestChain(b, primary, backup).ChatCompletion(context.Background(), Request{})
+	if err != nil || resp.Provider != "grok" {
+		t.Fatalf("trial call: %+v, %v", resp, err)
+	}
+	if !NewBreaker(path).Allow("grok/m0") {
+		t.Error("success should close the circuit on disk")
+	}
+}
+
+func TestNewProvider_FallbackChain(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("GOOGLE_API_KEY", "")
+	enrich := config.EnrichmentConfig{
+		Enabled:  true,
+		Provider: "grok",
+		Model:    "grok-4-fast",
+		Fallback: []config.ProviderModel{
+			{Provider: "anthropic", Model: "claude-haiku-4-5"},
+			{Provider: "google", Model: "gemini-2.5-flash"},
+		},
+	}
+	providers := config.ProvidersConfig{
+		Grok:   config.ProviderConfig{APIKey: "xai-key"},
+		Google: config.ProviderConfig{APIKey: "g-key"},
+	}
+
+	p, err := NewProvider(enrich, providers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, ok := p.(*FallbackProvider)
+	if !ok {
+		t.Fatalf("got %T, want *FallbackProvider", p)
+	}
+	var keys []string
+	for _, l := range f.links {
+		keys = append(keys, l.key())
+	}
+	if len(keys) != 2 || keys[0] != "grok/grok-4-fast" || keys[1] != "google/gemini-2.5-flash" {
+		t.Errorf("links = %v, want grok then google (anthropic has no key)", keys)
+	}
+
+	enrich.Fallback = nil
+	if p, _ := NewProvider(enrich, providers); p == nil {
+		t.Fatal("nil provider without fallbacks")
+	} else if _, ok := p.(*FallbackProvider); ok {
+		t.Error("no fallbacks should not build a chain")
+	}
+}
@@ -60,9 +60,12 @@ func (g *Google) ChatCompletion(ctx context.Context, req Request) (*Response, er
 		}
 	}
 
-	if req.JSONMode {
+	if req.JSONMode || req.Schema != nil {
 		body.GenerationConfig.ResponseMimeType = "application/json"
 	}
+	if req.Schema != nil {
+		body.GenerationConfig.ResponseSchema = req.Schema.geminiSchema()
+	}
 
 	// Gemini accepts maxOutputTokens as optional; omit when zero so the
 	// upstream service applies its own default. The struct field carries
@@ -77,31 +80,9 @@ func (g *Google) ChatCompletion(ctx context.Context, req Request) (*Response, er
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/models/%s:generateContent?key=%s",
-		g.baseURL, g.model, g.apiKey)
-
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	respBody, err := g.post(ctx, g.model, payload)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	resp, err := g.client.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("http request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read response: %w", err)
-	}
-
-	if isTransientStatus(resp.StatusCode) {
-		return nil, &TransientError{Err: fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))}
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+		return nil, err
 	}
 
 	var gemResp geminiResponse
@@ -129,7 +110,49 @@ func (g *Google) ChatCompletion(ctx context.Context, req Request) (*Response, er
 		return nil, fmt.Errorf("no text content in response")
 	}
 
-	return &Response{Content: text}, nil
+	return &Response{
+		Content: text,
+		Usage: UsageStats{
+			InputTokens:  gemResp.UsageMetadata.PromptTokenCount,
+			OutputTokens: gemResp.UsageMetadata.CandidatesTokenCount,
+		},
+	}, nil
+}
+
+// post sends a generateContent request body for model and returns the
+// response body, classifying HTTP failures as TransientError or
+// AuthError. Shared by ChatCompletion and GoogleAgentic.RunTools.
+func (g *Google) post(ctx context.Context, model string, payload []byte) ([]byte, error) {
+	url := fmt.Sprintf("%s/models/%s:generateContent?key=%s",
+		g.baseURL, model, g.apiKey)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if isTransientStatus(resp.StatusCode) {
+		return nil, &TransientError{Err: fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))}
+	}
+	if isAuthStatus(resp.StatusCode) {
+		return nil, &AuthError{Err: fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+	return respBody, nil
 }
 
 // Gemini API types
@@ -153,11 +176,19 @@ type geminiGenConfig struct {
 	Temperature      float64 `json:"temperature"`
 	MaxOutputTokens  int     `json:"maxOutputTokens,omitempty"`
 	ResponseMimeType string  `json:"responseMimeType,omitempty"`
+
+	ResponseSchema map[string]any `json:"responseSchema,omitempty"`
 }
 
 type geminiResponse struct {
-	Candidates []geminiCandidate `json:"candidates"`
-	Error      *geminiError      `json:"error,omitempty"`
+	Candidates    []geminiCandidate `json:"candidates"`
+	UsageMetadata geminiUsage       `json:"usageMetadata"`
+	Error         *geminiError      `json:"error,omitempty"`
+}
+
+type geminiUsage struct {
+	PromptTokenCount     int `json:"promptTokenCount"`
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
 }
 
 type geminiCandidate struct {
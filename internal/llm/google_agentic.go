@@ -0,0 +1,300 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// GoogleAgentic implements AgenticProvider for Gemini function calling and
+// delegates ChatCompletion to the embedded *Google.
+type GoogleAgentic struct {
+	*Google
+}
+
+// NewGoogleAgentic creates a GoogleAgentic provider. The signature mirrors
+// NewGoogle.
+func NewGoogleAgentic(apiKey, model string) (*GoogleAgentic, error) {
+	g, err := NewGoogle(apiKey, model)
+	if err != nil {
+		return nil, err
+	}
+	return &GoogleAgentic{Google: g}, nil
+}
+
+// RunTools drives the function-calling loop against generateContent. When
+// the model's turn contains functionCall parts, each is dispatched to the
+// ToolExecutor and answered with a functionResponse part in a following
+// user turn; a turn without calls ends the loop. Gemini only recently
+// began returning call IDs, so calls without one get a synthesized ID
+// that stays stable across the ToolsResponse round trip. The
+// MaxIterations cap behaves as in AnthropicAgentic.RunTools.
+func (g *GoogleAgentic) RunTools(ctx context.Context, req ToolsRequest) (*ToolsResponse, error) {
+	if req.ToolExecutor == nil {
+		return nil, fmt.Errorf("ToolsRequest.ToolExecutor is required")
+	}
+	maxIter := req.MaxIterations
+	if maxIter <= 0 {
+		maxIter = defaultMaxIterations
+	}
+	model := req.Model
+	if model == "" {
+		model = g.model
+	}
+
+	// names maps tool-use IDs to function names: a functionResponse must
+	// name its function, but public tool_result blocks carry only the ID.
+	names := make(map[string]string)
+	contents := make([]geminiToolsContent, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		if c, ok := toGeminiContent(m, names); ok {
+			contents = append(contents, c)
+		}
+	}
+
+	var tools []geminiTool
+	if len(req.Tools) > 0 {
+		decls := make([]geminiFunctionDecl, 0, len(req.Tools))
+		for _, t := range req.Tools {
+			decls = append(decls, geminiFunctionDecl{Name: t.Name, Description: t.Description, Parameters: t.InputSchema})
+		}
+		tools = []geminiTool{{FunctionDeclarations: decls}}
+	}
+
+	var system *geminiToolsContent
+	if req.System != "" {
+		system = &geminiToolsContent{Parts: []geminiToolsPart{{Text: req.System}}}
+	}
+	var genConfig *geminiGenConfig
+	if req.MaxTokens > 0 {
+		genConfig = &geminiGenConfig{MaxOutputTokens: req.MaxTokens}
+	}
+
+	var last []ContentBlock
+	var lastUsage, totalUsage UsageStats
+
+	for iter := 0; iter < maxIter; iter++ {
+		body := geminiToolsRequest{
+			Contents:          contents,
+			SystemInstruction: system,
+			Tools:             tools,
+			GenerationConfig:  genConfig,
+		}
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request: %w", err)
+		}
+		respBody, err := g.post(ctx, model, payload)
+		if err != nil {
+			return nil, err
+		}
+
+		var decoded geminiToolsResponse
+		if err := json.Unmarshal(respBody, &decoded); err != nil {
+			return nil, fmt.Errorf("unmarshal response: %w", err)
+		}
+		if decoded.Error != nil {
+			return nil, fmt.Errorf("API error: %s", decoded.Error.Message)
+		}
+		if len(decoded.Candidates) == 0 {
+			return nil, fmt.Errorf("empty candidates in response")
+		}
+
+		cand := decoded.Candidates[0]
+		for i := range cand.Content.Parts {
+			if fc := cand.Content.Parts[i].FunctionCall; fc != nil && fc.ID == "" {
+				fc.ID = fmt.Sprintf("call_%d_%d", iter, i)
+			}
+		}
+		last = fromGeminiParts(cand.Content.Parts)
+		lastUsage = UsageStats{
+			InputTokens:  decoded.UsageMetadata.PromptTokenCount,
+			OutputTokens: decoded.UsageMetadata.CandidatesTokenCount,
+		}
+		totalUsage.InputTokens += lastUsage.InputTokens
+		totalUsage.OutputTokens += lastUsage.OutputTokens
+
+		var responses []geminiToolsPart
+		for _, p := range cand.Content.Parts {
+			if p.FunctionCall == nil {
+				continue
+			}
+			output, isError := req.ToolExecutor(p.FunctionCall.Name, geminiArgs(p.FunctionCall.Args))
+			responses = append(responses, geminiToolsPart{FunctionResponse: &geminiFunctionResponse{
+				ID:       p.FunctionCall.ID,
+				Name:     p.FunctionCall.Name,
+				Response: geminiResponseObject(output, isError),
+			}})
+		}
+		if len(responses) == 0 {
+			return &ToolsResponse{
+				StopReason: normalizeGeminiFinish(cand.FinishReason),
+				Content:    last,
+				Usage:      lastUsage,
+				TotalUsage: totalUsage,
+			}, nil
+		}
+
+		contents = append(contents,
+			geminiToolsContent{Role: "model", Parts: cand.Content.Parts},
+			geminiToolsContent{Role: "user", Parts: responses},
+		)
+	}
+
+	return &ToolsResponse{
+		StopReason: "max_tokens",
+		Content:    last,
+		Usage:      lastUsage,
+		TotalUsage: totalUsage,
+	}, nil
+}
+
+// normalizeGeminiFinish maps finishReason onto the ToolsResponse enum.
+func normalizeGeminiFinish(wire string) string {
+	if wire == "MAX_TOKENS" {
+		return "max_tokens"
+	}
+	return "stop"
+}
+
+// toGeminiContent translates one public ToolsMessage, recording tool-use
+// names for later tool_result blocks. Returns false for an empty message.
+func toGeminiContent(m ToolsMessage, names map[string]string) (geminiToolsContent, bool) {
+	role := "user"
+	if m.Role == "assistant" {
+		role = "model"
+	}
+	c := geminiToolsContent{Role: role}
+	for _, b := range m.Content {
+		switch b.Type {
+		case "tool_use":
+			names[b.ToolUseID] = b.ToolName
+			c.Parts = append(c.Parts, geminiToolsPart{FunctionCall: &geminiFunctionCall{
+				ID:   b.ToolUseID,
+				Name: b.ToolName,
+				Args: geminiArgs(b.ToolInput),
+			}})
+		case "tool_result":
+			c.Parts = append(c.Parts, geminiToolsPart{FunctionResponse: &geminiFunctionResponse{
+				ID:       b.ToolUseID,
+				Name:     names[b.ToolUseID],
+				Response: geminiResponseObject(b.ToolResult, b.IsError),
+			}})
+		default:
+			if b.Text != "" {
+				c.Parts = append(c.Parts, geminiToolsPart{Text: b.Text})
+			}
+		}
+	}
+	return c, len(c.Parts) > 0
+}
+
+// fromGeminiParts converts a model turn into public blocks. Thought
+// summaries are reasoning, not answer text, and are left out.
+func fromGeminiParts(parts []geminiToolsPart) []ContentBlock {
+	var out []ContentBlock
+	for _, p := range parts {
+		switch {
+		case p.Thought:
+		case p.FunctionCall != nil:
+			out = append(out, ContentBlock{
+				Type:      "tool_use",
+				ToolUseID: p.FunctionCall.ID,
+				ToolName:  p.FunctionCall.Name,
+				ToolInput: geminiArgs(p.FunctionCall.Args),
+			})
+		case p.Text != "":
+			out = append(out, ContentBlock{Type: "text", Text: p.Text})
+		}
+	}
+	return out
+}
+
+// geminiArgs defaults missing function arguments to an empty object.
+func geminiArgs(args json.RawMessage) json.RawMessage {
+	if len(args) == 0 || string(args) == "null" {
+		return json.RawMessage("{}")
+	}
+	return args
+}
+
+// geminiResponseObject wraps executor output in the JSON object Gemini
+// requires for functionResponse.response: objects pass through, anything
+// else lands under "content" (or "error" for a failed call).
+func geminiResponseObject(output json.RawMessage, isError bool) json.RawMessage {
+	key := "content"
+	if isError {
+		key = "error"
+	} else if len(output) > 0 && output[0] == '{' && json.Valid(output) {
+		return output
+	}
+	if !json.Valid(output) {
+		output, _ = json.Marshal(string(output))
+	}
+	wrapped, _ := json.Marshal(map[string]json.RawMessage{key: output})
+	return wrapped
+}
+
+// Wire-format types for Gemini function calling. Separate from
+// geminiContent/geminiPart because tool turns carry functionCall and
+// functionResponse parts, which the text-only path never sends.
+
+type geminiToolsRequest struct {
+	Contents          []geminiToolsContent `json:"contents"`
+	SystemInstruction *geminiToolsContent  `json:"systemInstruction,omitempty"`
+	Tools             []geminiTool         `json:"tools,omitempty"`
+	GenerationConfig  *geminiGenConfig     `json:"generationConfig,omitempty"`
+}
+
+type geminiToolsContent struct {
+	Role  string            `json:"role,omitempty"`
+	Parts []geminiToolsPart `json:"parts"`
+}
+
+// geminiToolsPart is one part of a turn. Thinking models mark reasoning
+// summaries with Thought and attach an opaque ThoughtSignature to parts
+// (notably functionCall); the model turn is echoed back verbatim, and the
+// API rejects a follow-up whose function calls lost their signatures.
+type geminiToolsPart struct {
+	Text             string                  `json:"text,omitempty"`
+	Thought          bool                    `json:"thought,omitempty"`
+	ThoughtSignature string                  `json:"thoughtSignature,omitempty"`
+	FunctionCall     *geminiFunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *geminiFunctionResponse `json:"functionResponse,omitempty"`
+}
+
+type geminiFunctionCall struct {
+	ID   string          `json:"id,omitempty"`
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+type geminiFunctionResponse struct {
+	ID       string          `json:"id,omitempty"`
+	Name     string          `json:"name"`
+	Response json.RawMessage `json:"response"`
+}
+
+type geminiTool struct {
+	FunctionDeclarations []geminiFunctionDecl `json:"functionDeclarations"`
+}
+
+type geminiFunctionDecl struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+type geminiToolsResponse struct {
+	Candidates    []geminiToolsCandidate `json:"candidates"`
+	UsageMetadata geminiUsage            `json:"usageMetadata"`
+	Error         *geminiError           `json:"error,omitempty"`
+}
+
+type geminiToolsCandidate struct {
+	Content      geminiToolsContent `json:"content"`
+	FinishReason string             `json:"finishReason"`
+}
@@ -0,0 +1,231 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// geminiScriptedServer replies to generateContent with the supplied raw
+// JSON bodies in order and records every decoded request.
+func geminiScriptedServer(t *testing.T, responses []string) (*httptest.Server, *[]geminiToolsRequest) {
+	t.Helper()
+	var requests []geminiToolsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, ":generateContent") {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		raw, _ := io.ReadAll(r.Body)
+		var decoded geminiToolsRequest
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			t.Errorf("unmarshal request: %v\nraw: %s", err, raw)
+		}
+		requests = append(requests, decoded)
+		if len(requests) > len(responses) {
+			http.Error(w, "no more scripted responses", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, responses[len(requests)-1])
+	}))
+	return server, &requests
+}
+
+func newTestGoogleAgentic(t *testing.T, url string) *GoogleAgentic {
+	t.Helper()
+	p, err := NewGoogleAgentic("test-key", "gemini-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.baseURL = url
+	return p
+}
+
+func TestGoogleAgentic_ImplementsProvider(t *testing.T) {
+	var _ AgenticProvider = (*GoogleAgentic)(nil)
+}
+
+func TestGoogleAgentic_FunctionCallLoop(t *testing.T) {
+	server, requests := geminiScriptedServer(t, []string{
+		`{"candidates":[{"content":{"role":"model","parts":[
+			{"functionCall":{"name":"lookup","args":{"q":"hello"}}},
+			{"functionCall":{"id":"fc-2","name":"broken"}}
+		]},"finishReason":"STOP"}],"usageMetadata":{"promptTokenCount":10,"candidatesTokenCount":5}}`,
+		`{"candidates":[{"content":{"role":"model","parts":[{"text":"the answer is 42"}]},"finishReason":"STOP"}],
+		  "usageMetadata":{"promptTokenCount":30,"candidatesTokenCount":8}}`,
+	})
+	defer server.Close()
+
+	var calls []string
+	exec := func(name string, input json.RawMessage) (json.RawMessage, bool) {
+		calls = append(calls, name+":"+string(input))
+		if name == "broken" {
+			return json.RawMessage(`"no such thing"`), true
+		}
+		return json.RawMessage(`"42"`), false
+	}
+
+	p := newTestGoogleAgentic(t, server.URL)
+	resp, err := p.RunTools(context.Background(), ToolsRequest{
+		System:       "be brief",
+		Messages:     []ToolsMessage{{Role: "user", Content: []ContentBlock{{Type: "text", Text: "hi"}}}},
+		Tools:        []ToolSpec{{Name: "lookup", Description: "look it up", InputSchema: json.RawMessage(`{"type":"object"}`)}},
+		ToolExecutor: exec,
+	})
+	if err != nil {
+		t.Fatalf("RunTools: %v", err)
+	}
+
+	if len(calls) != 2 || calls[0] != `lookup:{"q":"hello"}` || calls[1] != "broken:{}" {
+		t.Errorf("executor calls = %q", calls)
+	}
+	if resp.StopReason != "stop" || len(resp.Content) != 1 || resp.Content[0].Text != "the answer is 42" {
+		t.Errorf("resp = %+v", resp)
+	}
+	if resp.TotalUsage != (UsageStats{InputTokens: 40, OutputTokens: 13}) {
+		t.Errorf("TotalUsage = %+v, want summed", resp.TotalUsage)
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("requests = %d, want 2", len(*requests))
+	}
+	first := (*requests)[0]
+	if first.SystemInstruction == nil || first.SystemInstruction.Parts[0].Text != "be brief" {
+		t.Errorf("systemInstruction = %+v", first.SystemInstruction)
+	}
+	if len(first.Tools) != 1 || first.Tools[0].FunctionDeclarations[0].Name != "lookup" {
+		t.Errorf("tools = %+v", first.Tools)
+	}
+
+	// Second turn: user, model functionCalls, user functionResponses.
+	contents := (*requests)[1].Contents
+	if len(contents) != 3 || contents[1].Role != "model" || contents[2].Role != "user" {
+		t.Fatalf("second request contents = %+v", contents)
+	}
+	if id := contents[1].Parts[0].FunctionCall.ID; id != "call_0_0" {
+		t.Errorf("synthesized call ID = %q", id)
+	}
+	parts := contents[2].Parts
+	if len(parts) != 2 {
+		t.Fatalf("functionResponse parts = %d, want 2", len(parts))
+	}
+	ok := parts[0].FunctionResponse
+	if ok.Name != "lookup" || ok.ID != "call_0_0" || string(ok.Response) != `{"content":"42"}` {
+		t.Errorf("lookup response = %+v (%s)", ok, ok.Response)
+	}
+	bad := parts[1].FunctionResponse
+	if bad.Name != "broken" || bad.ID != "fc-2" || string(bad.Response) != `{"error":"no such thing"}` {
+		t.Errorf("broken response = %+v (%s)", bad, bad.Response)
+	}
+}
+
+// A recorded gemini-2.5 thinking turn: a thought summary, then a
+// functionCall carrying the signature the API requires echoed back.
+const geminiThinkingTurn = `{"candidates":[{"content":{"role":"model","parts":[
+	{"text":"**Looking up the value**\n\nI should call lookup first.","thought":true},
+	{"functionCall":{"name":"lookup","args":{"q":"hello"}},
+	 "thoughtSignature":"CiQB0e2Kb7mZ3x1Q2lJ0p9Xq4yYb5c6d7e8f9g0h1i2j3k4l5m6n"}
+]},"finishReason":"STOP","index":0}],
+"usageMetadata":{"promptTokenCount":57,"candidatesTokenCount":15,"thoughtsTokenCount":41,"totalTokenCount":113},
+"modelVersion":"gemini-2.5-flash"}`
+
+func TestGoogleAgentic_PreservesThoughtSignature(t *testing.T) {
+	server, requests := geminiScriptedServer(t, []string{
+		geminiThinkingTurn,
+		`{"candidates":[{"content":{"role":"model","parts":[{"text":"42"}]},"finishReason":"STOP"}]}`,
+	})
+	defer server.Close()
+
+	p := newTestGoogleAgentic(t, server.URL)
+	_, err := p.RunTools(context.Background(), ToolsRequest{
+		Messages:     []ToolsMessage{{Role: "user", Content: []ContentBlock{{Type: "text", Text: "hi"}}}},
+		ToolExecutor: func(string, json.RawMessage) (json.RawMessage, bool) { return json.RawMessage(`"42"`), false },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*requests) != 2 {
+		t.Fatalf("requests = %d, want 2", len(*requests))
+	}
+	echoed := (*requests)[1].Contents[1].Parts
+	if len(echoed) != 2 || !echoed[0].Thought {
+		t.Fatalf("echoed model turn = %+v", echoed)
+	}
+	if sig := echoed[1].ThoughtSignature; sig != "CiQB0e2Kb7mZ3x1Q2lJ0p9Xq4yYb5c6d7e8f9g0h1i2j3k4l5m6n" {
+		t.Errorf("functionCall thoughtSignature = %q", sig)
+	}
+
+	// The thought summary is not surfaced as answer text.
+	blocks := fromGeminiParts(echoed)
+	if len(blocks) != 1 || blocks[0].Type != "tool_use" {
+		t.Errorf("public blocks = %+v", blocks)
+	}
+}
+
+func TestGoogleAgentic_MaxIterationsCap(t *testing.T) {
+	turn := `{"candidates":[{"content":{"role":"model","parts":[{"functionCall":{"name":"loop","args":{}}}]}}],
+	  "usageMetadata":{"promptTokenCount":1,"candidatesTokenCount":1}}`
+	server, requests := geminiScriptedServer(t, []string{turn, turn, turn})
+	defer server.Close()
+
+	p := newTestGoogleAgentic(t, server.URL)
+	resp, err := p.RunTools(context.Background(), ToolsRequest{
+		Messages:      []ToolsMessage{{Role: "user", Content: []ContentBlock{{Type: "text", Text: "go"}}}},
+		MaxIterations: 2,
+		ToolExecutor:  func(string, json.RawMessage) (json.RawMessage, bool) { return json.RawMessage(`{"ok":true}`), false },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StopReason != "max_tokens" || len(*requests) != 2 {
+		t.Errorf("StopReason = %q after %d requests; want max_tokens after 2", resp.StopReason, len(*requests))
+	}
+	// Object output passes through unwrapped.
+	if got := string((*requests)[1].Contents[2].Parts[0].FunctionResponse.Response); got != `{"ok":true}` {
+		t.Errorf("object response = %s", got)
+	}
+}
+
+func TestGoogleAgentic_ReplaysHistory(t *testing.T) {
+	server, requests := geminiScriptedServer(t, []string{
+		`{"candidates":[{"content":{"role":"model","parts":[{"text":"done"}]},"finishReason":"MAX_TOKENS"}]}`,
+	})
+	defer server.Close()
+
+	p := newTestGoogleAgentic(t, server.URL)
+	resp, err := p.RunTools(context.Background(), ToolsRequest{
+		Messages: []ToolsMessage{
+			{Role: "user", Content: []ContentBlock{{Type: "text", Text: "hi"}}},
+			{Role: "assistant", Content: []ContentBlock{{Type: "tool_use", ToolUseID: "t1", ToolName: "lookup", ToolInput: json.RawMessage(`{"q":1}`)}}},
+			{Role: "user", Content: []ContentBlock{{Type: "tool_result", ToolUseID: "t1", ToolResult: json.RawMessage(`"found"`)}}},
+		},
+		ToolExecutor: func(string, json.RawMessage) (json.RawMessage, bool) { return nil, false },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StopReason != "max_tokens" {
+		t.Errorf("StopReason = %q, want max_tokens", resp.StopReason)
+	}
+	contents := (*requests)[0].Contents
+	if len(contents) != 3 || contents[1].Role != "model" {
+		t.Fatalf("contents = %+v", contents)
+	}
+	if fr := contents[2].Parts[0].FunctionResponse; fr == nil || fr.Name != "lookup" || fr.ID != "t1" {
+		t.Errorf("replayed functionResponse = %+v", fr)
+	}
+}
+
+func TestGoogleAgentic_RequiresExecutor(t *testing.T) {
+	p := newTestGoogleAgentic(t, "http://unused.test")
+	if _, err := p.RunTools(context.Background(), ToolsRequest{}); err == nil {
+		t.Error("expected error for nil ToolExecutor")
+	}
+}
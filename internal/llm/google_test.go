@@ -130,6 +130,23 @@ func TestGoogleWithServer(t *testing.T) {
 	_ = p
 }
 
+func TestGoogleUsage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"ok"}]}}],` +
+			`"usageMetadata":{"promptTokenCount":90,"candidatesTokenCount":12,"totalTokenCount":102}}`))
+	}))
+	defer srv.Close()
+
+	p := &Google{baseURL: srv.URL, apiKey: "test-key", model: "gemini-2.5-flash", client: srv.Client()}
+	resp, err := p.ChatCompletion(context.Background(), Request{System: "sys", UserPrompt: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Usage != (UsageStats{InputTokens: 90, OutputTokens: 12}) {
+		t.Fatalf("usage = %+v, want 90 in / 12 out", resp.Usage)
+	}
+}
+
 // TestGoogleMaxTokens locks the wire-format contract for the MaxTokens
 // field: an explicit non-zero value renders as
 // "generationConfig":{...,"maxOutputTokens":<n>,...}, and the zero value
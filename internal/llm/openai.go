@@ -7,10 +7,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync/atomic"
 )
 
 // OpenAI implements Provider for OpenAI-compatible APIs.
@@ -20,6 +22,11 @@ type OpenAI struct {
 	apiKey  string
 	model   string
 	client  *http.Client
+
+	// noJSONSchema is set once the endpoint rejects a json_schema
+	// response_format; later requests ask for json_object instead and
+	// rely on CompleteStructured's validation and repair.
+	noJSONSchema atomic.Bool
 }
 
 // NewOpenAI creates an OpenAI-compatible provider.
@@ -48,7 +55,13 @@ func (o *OpenAI) ChatCompletion(ctx context.Context, req Request) (*Response, er
 		Messages:    messages,
 		Temperature: req.Temperature,
 	}
-	if req.JSONMode {
+	useSchema := req.Schema != nil && !o.noJSONSchema.Load()
+	if useSchema {
+		body.ResponseFormat = &oaiRespFormat{Type: "json_schema", JSONSchema: &oaiJSONSchema{
+			Name:   req.Schema.Title,
+			Schema: req.Schema.jsonSchema(),
+		}}
+	} else if req.JSONMode || req.Schema != nil {
 		body.ResponseFormat = &oaiRespFormat{Type: "json_object"}
 	}
 	// OpenAI accepts max_tokens as optional; omit when zero so the upstream
@@ -63,6 +76,45 @@ func (o *OpenAI) ChatCompletion(ctx context.Context, req Request) (*Response, er
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
+	respBody, err := o.post(ctx, payload)
+	if useSchema && rejectsResponseFormat(err) {
+		o.noJSONSchema.Store(true)
+		body.ResponseFormat = &oaiRespFormat{Type: "json_object"}
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, fmt.Errorf("marshal request: %w", err)
+		}
+		respBody, err = o.post(ctx, payload)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var oaiResp oaiResponse
+	if err := json.Unmarshal(respBody, &oaiResp); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	if oaiResp.Error != nil {
+		return nil, fmt.Errorf("API error: %s", oaiResp.Error.Message)
+	}
+
+	if len(oaiResp.Choices) == 0 {
+		return nil, fmt.Errorf("empty choices in response")
+	}
+
+	return &Response{
+		Content: oaiResp.Choices[0].Message.Content,
+		Usage: UsageStats{
+			InputTokens:  oaiResp.Usage.PromptTokens,
+			OutputTokens: oaiResp.Usage.CompletionTokens,
+		},
+	}, nil
+}
+
+// post sends a chat-completions request body and returns the response
+// body, classifying HTTP failures as TransientError or AuthError. Shared
+// by ChatCompletion and OpenAIAgentic.RunTools.
+func (o *OpenAI) post(ctx context.Context, payload []byte) ([]byte, error) {
 	url := o.baseURL + "/chat/completions"
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
 	if err != nil {
@@ -85,30 +137,43 @@ func (o *OpenAI) ChatCompletion(ctx context.Context, req Request) (*Response, er
 	if isTransientStatus(resp.StatusCode) {
 		return nil, &TransientError{Err: fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))}
 	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+	if isAuthStatus(resp.StatusCode) {
+		return nil, &AuthError{Err: fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))}
 	}
-
-	var oaiResp oaiResponse
-	if err := json.Unmarshal(respBody, &oaiResp); err != nil {
-		return nil, fmt.Errorf("unmarshal response: %w", err)
+	if resp.StatusCode != http.StatusOK {
+		return nil, &statusError{code: resp.StatusCode, body: string(respBody)}
 	}
+	return respBody, nil
+}
 
-	if oaiResp.Error != nil {
-		return nil, fmt.Errorf("API error: %s", oaiResp.Error.Message)
-	}
+// statusError is a non-OK response that is neither transient nor an
+// auth failure.
+type statusError struct {
+	code int
+	body string
+}
 
-	if len(oaiResp.Choices) == 0 {
-		return nil, fmt.Errorf("empty choices in response")
-	}
+func (e *statusError) Error() string {
+	return fmt.Sprintf("API error (status %d): %s", e.code, e.body)
+}
 
-	return &Response{Content: oaiResp.Choices[0].Message.Content}, nil
+// rejectsResponseFormat reports whether err is a 400 complaining about
+// the request's response_format, as endpoints that only support
+// json_object return for json_schema.
+func rejectsResponseFormat(err error) bool {
+	var se *statusError
+	return errors.As(err, &se) && se.code == http.StatusBadRequest &&
+		(strings.Contains(se.body, "response_format") || strings.Contains(se.body, "json_schema"))
 }
 
 func isTransientStatus(code int) bool {
 	return code == 429 || code == 500 || code == 502 || code == 503 || code == 504
 }
 
+func isAuthStatus(code int) bool {
+	return code == 401 || code == 403
+}
+
 // OpenAI API types
 
 type oaiRequest struct {
@@ -125,14 +190,26 @@ type oaiMessage struct {
 }
 
 type oaiRespFormat struct {
-	Type string `json:"type"`
+	Type       string         `json:"type"`
+	JSONSchema *oaiJSONSchema `json:"json_schema,omitempty"`
+}
+
+type oaiJSONSchema struct {
+	Name   string         `json:"name"`
+	Schema map[string]any `json:"schema"`
 }
 
 type oaiResponse struct {
 	Choices []oaiChoice `json:"choices"`
+	Usage   oaiUsage    `json:"usage"`
 	Error   *oaiError   `json:"error,omitempty"`
 }
 
+type oaiUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
 type oaiChoice struct {
 	Message oaiMessage `json:"message"`
 }
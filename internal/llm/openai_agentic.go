@@ -0,0 +1,276 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// OpenAIAgentic implements AgenticProvider for the OpenAI chat-completions
+// tool-call wire format. It covers every endpoint the single-turn OpenAI
+// provider does — OpenAI proper, xAI/Grok, and local OpenAI-compatible
+// servers — and delegates ChatCompletion to the embedded *OpenAI.
+type OpenAIAgentic struct {
+	*OpenAI
+}
+
+// NewOpenAIAgentic creates an OpenAIAgentic provider. The signature mirrors
+// NewOpenAI; wrap a NewGrok result directly for xAI's default base URL.
+func NewOpenAIAgentic(baseURL, apiKey, model string) (*OpenAIAgentic, error) {
+	o, err := NewOpenAI(baseURL, apiKey, model)
+	if err != nil {
+		return nil, err
+	}
+	return &OpenAIAgentic{OpenAI: o}, nil
+}
+
+// RunTools drives the tool-call loop against /chat/completions. Each
+// iteration sends the conversation plus the tool catalogue; when the
+// model finishes with "tool_calls", every call is dispatched to the
+// ToolExecutor, the assistant turn and one "tool" message per result are
+// appended, and the loop continues. Any other finish_reason ends the
+// loop. The MaxIterations cap behaves as in AnthropicAgentic.RunTools.
+func (o *OpenAIAgentic) RunTools(ctx context.Context, req ToolsRequest) (*ToolsResponse, error) {
+	if req.ToolExecutor == nil {
+		return nil, fmt.Errorf("ToolsRequest.ToolExecutor is required")
+	}
+	maxIter := req.MaxIterations
+	if maxIter <= 0 {
+		maxIter = defaultMaxIterations
+	}
+	model := req.Model
+	if model == "" {
+		model = o.model
+	}
+
+	var messages []oaiToolsMessage
+	if req.System != "" {
+		messages = append(messages, oaiToolsMessage{Role: "system", Content: oaiText(req.System)})
+	}
+	for _, m := range req.Messages {
+		messages = append(messages, toOAIMessages(m)...)
+	}
+
+	tools := make([]oaiTool, 0, len(req.Tools))
+	for _, t := range req.Tools {
+		tools = append(tools, oaiTool{Type: "function", Function: oaiFunction{
+			Name:        t.Name,
+			Description: t.Description,
+			Parameters:  t.InputSchema,
+		}})
+	}
+
+	var last oaiToolsMessage
+	var lastUsage, totalUsage UsageStats
+
+	for iter := 0; iter < maxIter; iter++ {
+		body := oaiToolsRequest{
+			Model:     model,
+			Messages:  messages,
+			Tools:     tools,
+			MaxTokens: req.MaxTokens,
+		}
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request: %w", err)
+		}
+		respBody, err := o.post(ctx, payload)
+		if err != nil {
+			return nil, err
+		}
+
+		var decoded oaiToolsResponse
+		if err := json.Unmarshal(respBody, &decoded); err != nil {
+			return nil, fmt.Errorf("unmarshal response: %w", err)
+		}
+		if decoded.Error != nil {
+			return nil, fmt.Errorf("API error: %s", decoded.Error.Message)
+		}
+		if len(decoded.Choices) == 0 {
+			return nil, fmt.Errorf("empty choices in response")
+		}
+
+		choice := decoded.Choices[0]
+		last = choice.Message
+		lastUsage = UsageStats{
+			InputTokens:  decoded.Usage.PromptTokens,
+			OutputTokens: decoded.Usage.CompletionTokens,
+		}
+		totalUsage.InputTokens += lastUsage.InputTokens
+		totalUsage.OutputTokens += lastUsage.OutputTokens
+
+		// Some OpenAI-compatible servers report "stop" alongside tool
+		// calls; the calls themselves are the signal.
+		if len(last.ToolCalls) == 0 {
+			return &ToolsResponse{
+				StopReason: normalizeOAIFinish(choice.FinishReason),
+				Content:    fromOAIMessage(last),
+				Usage:      lastUsage,
+				TotalUsage: totalUsage,
+			}, nil
+		}
+
+		messages = append(messages, oaiToolsMessage{Role: "assistant", Content: last.Content, ToolCalls: last.ToolCalls})
+		for _, call := range last.ToolCalls {
+			output, isError := req.ToolExecutor(call.Function.Name, oaiArguments(call.Function.Arguments))
+			text := toolResultText(output)
+			if isError {
+				text = "ERROR: " + text
+			}
+			messages = append(messages, oaiToolsMessage{Role: "tool", ToolCallID: call.ID, Content: oaiText(text)})
+		}
+	}
+
+	return &ToolsResponse{
+		StopReason: "max_tokens",
+		Content:    fromOAIMessage(last),
+		Usage:      lastUsage,
+		TotalUsage: totalUsage,
+	}, nil
+}
+
+// normalizeOAIFinish maps finish_reason onto the ToolsResponse enum.
+func normalizeOAIFinish(wire string) string {
+	switch wire {
+	case "length":
+		return "max_tokens"
+	case "tool_calls", "function_call":
+		return "tool_use"
+	default:
+		return "stop"
+	}
+}
+
+// toOAIMessages translates one public ToolsMessage into wire messages.
+// tool_result blocks each become a role "tool" message; text and
+// tool_use blocks fold into a single user or assistant message.
+func toOAIMessages(m ToolsMessage) []oaiToolsMessage {
+	var out []oaiToolsMessage
+	var text []string
+	var calls []oaiToolCall
+	for _, b := range m.Content {
+		switch b.Type {
+		case "tool_result":
+			content := toolResultText(b.ToolResult)
+			if b.IsError {
+				content = "ERROR: " + content
+			}
+			out = append(out, oaiToolsMessage{Role: "tool", ToolCallID: b.ToolUseID, Content: oaiText(content)})
+		case "tool_use":
+			args := string(b.ToolInput)
+			if args == "" {
+				args = "{}"
+			}
+			calls = append(calls, oaiToolCall{ID: b.ToolUseID, Type: "function", Function: oaiFunctionCall{Name: b.ToolName, Arguments: args}})
+		default:
+			if b.Text != "" {
+				text = append(text, b.Text)
+			}
+		}
+	}
+	if len(text) == 0 && len(calls) == 0 {
+		return out
+	}
+	role := m.Role
+	if role == "tool" {
+		role = "user"
+	}
+	msg := oaiToolsMessage{Role: role, ToolCalls: calls}
+	if len(text) > 0 {
+		msg.Content = oaiText(strings.Join(text, "\n\n"))
+	}
+	return append([]oaiToolsMessage{msg}, out...)
+}
+
+// fromOAIMessage converts an assistant wire message into public blocks.
+func fromOAIMessage(m oaiToolsMessage) []ContentBlock {
+	var out []ContentBlock
+	if m.Content != nil && *m.Content != "" {
+		out = append(out, ContentBlock{Type: "text", Text: *m.Content})
+	}
+	for _, c := range m.ToolCalls {
+		out = append(out, ContentBlock{
+			Type:      "tool_use",
+			ToolUseID: c.ID,
+			ToolName:  c.Function.Name,
+			ToolInput: oaiArguments(c.Function.Arguments),
+		})
+	}
+	return out
+}
+
+// oaiArguments turns the wire's JSON-encoded argument string into raw
+// JSON, defaulting to an empty object when the model sent nothing usable.
+func oaiArguments(args string) json.RawMessage {
+	if !json.Valid([]byte(args)) {
+		return json.RawMessage("{}")
+	}
+	return json.RawMessage(args)
+}
+
+// toolResultText renders executor output as plain text: a JSON string is
+// unquoted, anything else is passed through as its JSON text.
+func toolResultText(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return string(raw)
+}
+
+func oaiText(s string) *string { return &s }
+
+// Wire-format types for chat-completions tool calling. Separate from
+// oaiMessage because tool turns need nullable content, tool_calls, and
+// tool_call_id.
+
+type oaiToolsRequest struct {
+	Model     string            `json:"model"`
+	Messages  []oaiToolsMessage `json:"messages"`
+	Tools     []oaiTool         `json:"tools,omitempty"`
+	MaxTokens int               `json:"max_tokens,omitempty"`
+}
+
+type oaiToolsMessage struct {
+	Role       string        `json:"role"`
+	Content    *string       `json:"content"` // null on assistant turns that only call tools
+	ToolCalls  []oaiToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string        `json:"tool_call_id,omitempty"`
+}
+
+type oaiTool struct {
+	Type     string      `json:"type"` // "function"
+	Function oaiFunction `json:"function"`
+}
+
+type oaiFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+type oaiToolCall struct {
+	ID       string          `json:"id"`
+	Type     string          `json:"type"`
+	Function oaiFunctionCall `json:"function"`
+}
+
+type oaiFunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"` // JSON-encoded object
+}
+
+type oaiToolsResponse struct {
+	Choices []oaiToolsChoice `json:"choices"`
+	Usage   oaiUsage         `json:"usage"`
+	Error   *oaiError        `json:"error,omitempty"`
+}
+
+type oaiToolsChoice struct {
+	Message      oaiToolsMessage `json:"message"`
+	FinishReason string          `json:"finish_reason"`
+}
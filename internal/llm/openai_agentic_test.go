@@ -0,0 +1,194 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// oaiScriptedServer replies to /chat/completions with the supplied raw JSON
+// bodies in order and records every decoded request.
+func oaiScriptedServer(t *testing.T, responses []string) (*httptest.Server, *[]oaiToolsRequest) {
+	t.Helper()
+	var requests []oaiToolsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/chat/completions") {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		raw, _ := io.ReadAll(r.Body)
+		var decoded oaiToolsRequest
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			t.Errorf("unmarshal request: %v\nraw: %s", err, raw)
+		}
+		requests = append(requests, decoded)
+		if len(requests) > len(responses) {
+			http.Error(w, "no more scripted responses", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, responses[len(requests)-1])
+	}))
+	return server, &requests
+}
+
+func TestOpenAIAgentic_ImplementsProvider(t *testing.T) {
+	var _ AgenticProvider = (*OpenAIAgentic)(nil)
+}
+
+func TestOpenAIAgentic_ToolCallLoop(t *testing.T) {
+	server, requests := oaiScriptedServer(t, []string{
+		`{"choices":[{"message":{"role":"assistant","content":null,"tool_calls":[
+			{"id":"call_a","type":"function","function":{"name":"lookup","arguments":"{\"q\":\"hello\"}"}},
+			{"id":"call_b","type":"function","function":{"name":"broken","arguments":""}}
+		]},"finish_reason":"tool_calls"}],"usage":{"prompt_tokens":10,"completion_tokens":5}}`,
+		`{"choices":[{"message":{"role":"assistant","content":"the answer is 42"},"finish_reason":"stop"}],
+		  "usage":{"prompt_tokens":30,"completion_tokens":8}}`,
+	})
+	defer server.Close()
+
+	var calls []string
+	exec := func(name string, input json.RawMessage) (json.RawMessage, bool) {
+		calls = append(calls, name+":"+string(input))
+		if name == "broken" {
+			return json.RawMessage(`"no such thing"`), true
+		}
+		return json.RawMessage(`{"n":42}`), false
+	}
+
+	p, err := NewOpenAIAgentic(server.URL, "k", "gpt-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := p.RunTools(context.Background(), ToolsRequest{
+		System:       "be brief",
+		Messages:     []ToolsMessage{{Role: "user", Content: []ContentBlock{{Type: "text", Text: "hi"}}}},
+		Tools:        []ToolSpec{{Name: "lookup", Description: "look it up", InputSchema: json.RawMessage(`{"type":"object"}`)}},
+		ToolExecutor: exec,
+	})
+	if err != nil {
+		t.Fatalf("RunTools: %v", err)
+	}
+
+	if len(calls) != 2 || calls[0] != `lookup:{"q":"hello"}` || calls[1] != "broken:{}" {
+		t.Errorf("executor calls = %q", calls)
+	}
+	if resp.StopReason != "stop" || len(resp.Content) != 1 || resp.Content[0].Text != "the answer is 42" {
+		t.Errorf("resp = %+v", resp)
+	}
+	if resp.Usage != (UsageStats{InputTokens: 30, OutputTokens: 8}) {
+		t.Errorf("Usage = %+v, want final turn only", resp.Usage)
+	}
+	if resp.TotalUsage != (UsageStats{InputTokens: 40, OutputTokens: 13}) {
+		t.Errorf("TotalUsage = %+v, want summed", resp.TotalUsage)
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("requests = %d, want 2", len(*requests))
+	}
+	first := (*requests)[0]
+	if first.Model != "gpt-test" || len(first.Tools) != 1 || first.Tools[0].Type != "function" || first.Tools[0].Function.Name != "lookup" {
+		t.Errorf("first request = %+v", first)
+	}
+	if first.Messages[0].Role != "system" || *first.Messages[0].Content != "be brief" {
+		t.Errorf("system message = %+v", first.Messages[0])
+	}
+
+	// Second turn: system, user, assistant tool_calls, then one tool
+	// message per call keyed by tool_call_id.
+	msgs := (*requests)[1].Messages
+	if len(msgs) != 5 {
+		t.Fatalf("second request messages = %d, want 5", len(msgs))
+	}
+	if msgs[2].Role != "assistant" || len(msgs[2].ToolCalls) != 2 {
+		t.Errorf("assistant turn = %+v", msgs[2])
+	}
+	if msgs[3].Role != "tool" || msgs[3].ToolCallID != "call_a" || *msgs[3].Content != `{"n":42}` {
+		t.Errorf("tool result a = %+v", msgs[3])
+	}
+	if msgs[4].ToolCallID != "call_b" || *msgs[4].Content != "ERROR: no such thing" {
+		t.Errorf("tool result b = %+v", msgs[4])
+	}
+}
+
+func TestOpenAIAgentic_MaxIterationsCap(t *testing.T) {
+	turn := `{"choices":[{"message":{"role":"assistant","tool_calls":[
+		{"id":"c","type":"function","function":{"name":"loop","arguments":"{}"}}
+	]},"finish_reason":"tool_calls"}],"usage":{"prompt_tokens":1,"completion_tokens":1}}`
+	server, requests := oaiScriptedServer(t, []string{turn, turn, turn})
+	defer server.Close()
+
+	p, _ := NewOpenAIAgentic(server.URL, "k", "m")
+	resp, err := p.RunTools(context.Background(), ToolsRequest{
+		Messages:      []ToolsMessage{{Role: "user", Content: []ContentBlock{{Type: "text", Text: "go"}}}},
+		MaxIterations: 2,
+		ToolExecutor:  func(string, json.RawMessage) (json.RawMessage, bool) { return json.RawMessage(`"ok"`), false },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StopReason != "max_tokens" || len(*requests) != 2 {
+		t.Errorf("StopReason = %q after %d requests; want max_tokens after 2", resp.StopReason, len(*requests))
+	}
+	if resp.TotalUsage.InputTokens != 2 {
+		t.Errorf("TotalUsage = %+v", resp.TotalUsage)
+	}
+}
+
+func TestOpenAIAgentic_ReplaysHistory(t *testing.T) {
+	server, requests := oaiScriptedServer(t, []string{
+		`{"choices":[{"message":{"role":"assistant","content":"done"},"finish_reason":"stop"}]}`,
+	})
+	defer server.Close()
+
+	p, _ := NewOpenAIAgentic(server.URL, "k", "m")
+	_, err := p.RunTools(context.Background(), ToolsRequest{
+		Messages: []ToolsMessage{
+			{Role: "user", Content: []ContentBlock{{Type: "text", Text: "hi"}}},
+			{Role: "assistant", Content: []ContentBlock{{Type: "tool_use", ToolUseID: "t1", ToolName: "lookup", ToolInput: json.RawMessage(`{"q":1}`)}}},
+			{Role: "user", Content: []ContentBlock{{Type: "tool_result", ToolUseID: "t1", ToolResult: json.RawMessage(`"found"`)}}},
+		},
+		ToolExecutor: func(string, json.RawMessage) (json.RawMessage, bool) { return nil, false },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs := (*requests)[0].Messages
+	if len(msgs) != 3 {
+		t.Fatalf("messages = %+v", msgs)
+	}
+	if msgs[1].Content != nil || msgs[1].ToolCalls[0].Function.Arguments != `{"q":1}` {
+		t.Errorf("assistant replay = %+v", msgs[1])
+	}
+	if msgs[2].Role != "tool" || msgs[2].ToolCallID != "t1" || *msgs[2].Content != "found" {
+		t.Errorf("tool replay = %+v", msgs[2])
+	}
+}
+
+func TestOpenAIAgentic_RequiresExecutor(t *testing.T) {
+	p, _ := NewOpenAIAgentic("http://unused.test", "k", "m")
+	if _, err := p.RunTools(context.Background(), ToolsRequest{}); err == nil {
+		t.Error("expected error for nil ToolExecutor")
+	}
+}
+
+func TestOpenAIAgentic_AuthError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"message":"bad key"}}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	p, _ := NewOpenAIAgentic(server.URL, "k", "m")
+	_, err := p.RunTools(context.Background(), ToolsRequest{
+		ToolExecutor: func(string, json.RawMessage) (json.RawMessage, bool) { return nil, false },
+	})
+	if !isAuth(err) {
+		t.Errorf("err = %v, want AuthError", err)
+	}
+}
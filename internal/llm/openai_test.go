@@ -29,6 +29,7 @@ func TestOpenAIChatCompletion(t *testing.T) {
 			Choices: []oaiChoice{
 				{Message: oaiMessage{Content: `{"summary":"test"}`}},
 			},
+			Usage: oaiUsage{PromptTokens: 120, CompletionTokens: 30},
 		}
 		json.NewEncoder(w).Encode(resp)
 	}))
@@ -53,6 +54,9 @@ func TestOpenAIChatCompletion(t *testing.T) {
 	if resp.Content != `{"summary":"test"}` {
 		t.Fatalf("got %q, want %q", resp.Content, `{"summary":"test"}`)
 	}
+	if resp.Usage != (UsageStats{InputTokens: 120, OutputTokens: 30}) {
+		t.Fatalf("usage = %+v, want 120 in / 30 out", resp.Usage)
+	}
 }
 
 func TestOpenAITransientRetry(t *testing.T) {
@@ -108,6 +112,20 @@ func TestOpenAIPermanentError(t *testing.T) {
 	}
 }
 
+func TestOpenAIAuthError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("invalid api key"))
+	}))
+	defer server.Close()
+
+	p, _ := NewOpenAI(server.URL, "bad-key", "gpt-4")
+	_, err := p.ChatCompletion(context.Background(), Request{UserPrompt: "test"})
+	if !isAuth(err) || isTransient(err) {
+		t.Fatalf("err = %v, want an AuthError", err)
+	}
+}
+
 func TestOpenAIName(t *testing.T) {
 	p, _ := NewOpenAI("http://localhost", "key", "model")
 	if p.Name() != "openai" {
@@ -5,6 +5,7 @@ package llm
 
 import (
 	"fmt"
+	"log"
 	"os"
 
 	"github.com/suykerbuyk/vibe-vault/internal/config"
@@ -17,78 +18,155 @@ import (
 // When enrichment is enabled, the API key is resolved via ResolveAPIKey
 // (config-first, env-fallback, actionable-error-on-both-empty); a missing
 // key surfaces as an error so callers can guide the operator at use time.
+//
+// When enrich.Fallback is set the result is a FallbackProvider chaining
+// the primary and each fallback in order, with an in-memory circuit
+// breaker (NewConfiguredProvider persists it). Links whose key cannot be
+// resolved are left out with a warning; the error is returned only when
+// no link is usable.
 func NewProvider(enrich config.EnrichmentConfig, providers config.ProvidersConfig) (Provider, error) {
 	if !enrich.Enabled {
 		return nil, nil
 	}
 
-	// Normalize the provider name. The legacy switch below treats "" as
-	// "openai"; ResolveAPIKey requires a strict provider name from the
-	// supported set, so we collapse the empty form before resolving.
+	// Normalize the provider name. The legacy switch in newBaseProvider
+	// treats "" as "openai"; ResolveAPIKey requires a strict provider
+	// name from the supported set, so we collapse the empty form before
+	// resolving.
 	provider := enrich.Provider
 	if provider == "" {
 		provider = "openai"
 	}
 
-	apiKey, err := ResolveAPIKey(provider, providers)
-	if err != nil {
-		return nil, err
-	}
-
 	// Resolve the effective base URL for the provider per Decision C of
 	// grok-provider-support v3: providers.<P>.base_url (when non-empty)
 	// overrides enrichment.base_url. Operators on the legacy default config
 	// (provider = "openai" + enrichment.base_url = ".../x.ai/...") keep
 	// working unchanged because enrichment.base_url is still consulted as
 	// the fallback.
-	baseURL := resolveBaseURL(provider, enrich.BaseURL, providers)
+	primary, err := newBaseProvider(provider, enrich.Model, resolveBaseURL(provider, enrich.BaseURL, providers), providers)
+	if len(enrich.Fallback) == 0 {
+		if err != nil {
+			return nil, err
+		}
+		// Wrap with retry logic for transient failures.
+		return WithRetry(primary), nil
+	}
 
-	var base Provider
+	var links []fallbackLink
+	firstErr := err
+	if err == nil {
+		links = append(links, fallbackLink{provider: provider, model: enrich.Model, p: WithRetry(primary)})
+	} else {
+		log.Printf("warning: llm %s/%s left out of the fallback chain: %v", provider, enrich.Model, err)
+	}
+	for _, fb := range enrich.Fallback {
+		// enrichment.base_url belongs to the primary; fallbacks use their
+		// own providers.<P>.base_url or the canonical endpoint.
+		p, err := newBaseProvider(fb.Provider, fb.Model, resolveBaseURL(fb.Provider, "", providers), providers)
+		if err != nil {
+			log.Printf("warning: llm %s/%s left out of the fallback chain: %v", fb.Provider, fb.Model, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		links = append(links, fallbackLink{provider: fb.Provider, model: fb.Model, p: WithRetry(p)})
+	}
+	if len(links) == 0 {
+		return nil, firstErr
+	}
+	return &FallbackProvider{links: links, breaker: NewBreaker("")}, nil
+}
+
+// newBaseProvider resolves the key for provider and constructs its
+// unwrapped client.
+func newBaseProvider(provider, model, baseURL string, providers config.ProvidersConfig) (Provider, error) {
+	apiKey, err := ResolveAPIKey(provider, providers)
+	if err != nil {
+		return nil, err
+	}
 
-	switch enrich.Provider {
+	switch provider {
 	case "openai", "":
-		base, err = NewOpenAI(baseURL, apiKey, enrich.Model)
+		return NewOpenAI(baseURL, apiKey, model)
 	case "anthropic":
-		base, err = NewAnthropic(baseURL, apiKey, enrich.Model)
+		return NewAnthropic(baseURL, apiKey, model)
 	case "google":
-		base, err = NewGoogle(apiKey, enrich.Model)
+		return NewGoogle(apiKey, model)
 	case "grok":
-		base, err = NewGrok(baseURL, apiKey, enrich.Model)
+		return NewGrok(baseURL, apiKey, model)
 	default:
-		return nil, fmt.Errorf("unknown LLM provider: %q", enrich.Provider)
+		return nil, fmt.Errorf("unknown LLM provider: %q", provider)
 	}
-	if err != nil {
-		return nil, err
+}
+
+// NewConfiguredProvider is NewProvider wrapped the way every vv command
+// uses it: answered from the response cache configured by cfg.LLM, with
+// each call recorded in the usage ledger (cache hits included, flagged
+// as cached). Returns (nil, nil) when enrichment is disabled.
+func NewConfiguredProvider(cfg config.Config) (Provider, error) {
+	p, err := NewProvider(cfg.Enrichment, cfg.Providers)
+	if err != nil || p == nil {
+		return p, err
+	}
+	if fp, ok := p.(*FallbackProvider); ok {
+		fp.breaker = NewBreaker(BreakerPath(cfg))
 	}
+	model := cfg.Enrichment.Model
+	return WithLedger(WithCache(p, OpenCache(cfg), model), OpenLedger(cfg), model), nil
+}
 
-	// Wrap with retry logic for transient failures.
-	return WithRetry(base), nil
+// NewCacheOnlyProvider is NewConfiguredProvider for previews: it answers
+// only from the LLM cache, returning ErrCacheMiss instead of making a
+// call, so nothing is spent or recorded in the usage ledger.
+func NewCacheOnlyProvider(cfg config.Config) (Provider, error) {
+	p, err := NewProvider(cfg.Enrichment, cfg.Providers)
+	if err != nil || p == nil {
+		return p, err
+	}
+	return WithCacheOnly(p, OpenCache(cfg), cfg.Enrichment.Model), nil
 }
 
 // NewAgenticProvider creates an AgenticProvider (multi-turn tool-use)
-// from the providers config block. Today this is Anthropic-only; OpenAI-
-// compatible RunTools is deferred per the flowdoc-gen-source-ingestion
-// task anti-scope.
+// for enrich.Provider and enrich.Model: Anthropic tool use, the
+// chat-completions tool-call format for OpenAI, Grok, and local
+// OpenAI-compatible servers, or Gemini function calling. Unlike
+// NewProvider it ignores enrich.Enabled — `vv flowdoc gen` takes the
+// agentic path whether or not session enrichment is on — and never builds
+// a fallback chain.
 //
-// The Anthropic API key is resolved regardless of enrich.Provider so an
-// operator with a grok-configured single-shot enrichment can still take
-// the agentic path for `vv flowdoc gen` by configuring an Anthropic key
-// — the auto-strategy in cmd/vv/flowdoc.go preflights with this same
-// lookup. A missing key returns the same actionable error as the
-// single-shot path.
-//
-// The model argument is the per-call model (typically picked by the
-// caller — `vv flowdoc gen` uses defaultAgenticModel when no --model is
-// passed) rather than enrich.Model, because the agentic mode needs an
-// Anthropic model name that may differ from the operator's default
-// single-shot model.
-func NewAgenticProvider(model string, providers config.ProvidersConfig) (AgenticProvider, error) {
-	apiKey, err := ResolveAPIKey("anthropic", providers)
+// The key and base URL resolve exactly as in NewProvider, so an operator
+// whose enrichment runs on Grok gets agentic Grok with no extra config. A
+// missing key returns the same actionable error as the single-shot path,
+// prefixed to name the agentic mode.
+func NewAgenticProvider(enrich config.EnrichmentConfig, providers config.ProvidersConfig) (AgenticProvider, error) {
+	provider := enrich.Provider
+	if provider == "" {
+		provider = "openai"
+	}
+	apiKey, err := ResolveAPIKey(provider, providers)
 	if err != nil {
-		return nil, fmt.Errorf("agentic mode requires an Anthropic API key: %w", err)
+		return nil, fmt.Errorf("agentic mode requires a %s API key: %w", provider, err)
+	}
+	baseURL := resolveBaseURL(provider, enrich.BaseURL, providers)
+
+	switch provider {
+	case "anthropic":
+		return NewAnthropicAgentic(baseURL, apiKey, enrich.Model)
+	case "openai":
+		return NewOpenAIAgentic(baseURL, apiKey, enrich.Model)
+	case "grok":
+		g, err := NewGrok(baseURL, apiKey, enrich.Model)
+		if err != nil {
+			return nil, err
+		}
+		return &OpenAIAgentic{OpenAI: g}, nil
+	case "google":
+		return NewGoogleAgentic(apiKey, enrich.Model)
+	default:
+		return nil, fmt.Errorf("unknown LLM provider: %q", enrich.Provider)
 	}
-	baseURL := resolveBaseURL("anthropic", "", providers)
-	return NewAnthropicAgentic(baseURL, apiKey, model)
 }
 
 // resolveBaseURL implements the Decision C precedence rule:
@@ -4,6 +4,7 @@
 package llm
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/suykerbuyk/vibe-vault/internal/config"
@@ -215,3 +216,46 @@ func TestNewProvider_GrokRouting(t *testing.T) {
 		t.Fatal("NewProvider(grok) returned nil provider")
 	}
 }
+
+// TestNewAgenticProvider_SelectsByProvider locks the agentic dispatch:
+// each configured provider gets its tool-use implementation, and a
+// missing key fails with the agentic-mode hint.
+func TestNewAgenticProvider_SelectsByProvider(t *testing.T) {
+	for _, env := range []string{"ANTHROPIC_API_KEY", "OPENAI_API_KEY", "GOOGLE_API_KEY", "XAI_API_KEY"} {
+		t.Setenv(env, "")
+	}
+	providers := config.ProvidersConfig{
+		Anthropic: config.ProviderConfig{APIKey: "TESTKEY"},
+		OpenAI:    config.ProviderConfig{APIKey: "TESTKEY"},
+		Google:    config.ProviderConfig{APIKey: "TESTKEY"},
+		Grok:      config.ProviderConfig{APIKey: "TESTKEY"},
+	}
+	cases := []struct {
+		provider string
+		check    func(AgenticProvider) bool
+	}{
+		{"anthropic", func(p AgenticProvider) bool { _, ok := p.(*AnthropicAgentic); return ok }},
+		{"openai", func(p AgenticProvider) bool { _, ok := p.(*OpenAIAgentic); return ok }},
+		{"", func(p AgenticProvider) bool { _, ok := p.(*OpenAIAgentic); return ok }},
+		{"google", func(p AgenticProvider) bool { _, ok := p.(*GoogleAgentic); return ok }},
+		{"grok", func(p AgenticProvider) bool {
+			o, ok := p.(*OpenAIAgentic)
+			return ok && o.baseURL == GrokDefaultBaseURL
+		}},
+	}
+	for _, tc := range cases {
+		enrich := config.EnrichmentConfig{Provider: tc.provider, Model: "m"}
+		p, err := NewAgenticProvider(enrich, providers)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.provider, err)
+		}
+		if !tc.check(p) {
+			t.Errorf("%q: got %T", tc.provider, p)
+		}
+	}
+
+	_, err := NewAgenticProvider(config.EnrichmentConfig{Provider: "google"}, config.ProvidersConfig{})
+	if err == nil || !strings.Contains(err.Error(), "agentic mode requires a google API key") {
+		t.Errorf("missing key: err = %v", err)
+	}
+}
@@ -54,6 +54,21 @@ type TransientError struct {
 func (e *TransientError) Error() string { return e.Err.Error() }
 func (e *TransientError) Unwrap() error { return e.Err }
 
+// AuthError marks a rejected credential (HTTP 401/403). It is not
+// retried, but a fallback chain fails over past it: another provider's
+// key may still work.
+type AuthError struct {
+	Err error
+}
+
+func (e *AuthError) Error() string { return e.Err.Error() }
+func (e *AuthError) Unwrap() error { return e.Err }
+
+func isAuth(err error) bool {
+	var ae *AuthError
+	return errors.As(err, &ae)
+}
+
 func isTransient(err error) bool {
 	var te *TransientError
 	if errors.As(err, &te) {
@@ -0,0 +1,251 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Schema is the JSON Schema subset vibe-vault uses to describe structured
+// LLM output. One value drives both native structured output (OpenAI
+// response_format json_schema, Gemini responseSchema) and Validate, so
+// what the provider is asked for and what is enforced cannot drift.
+type Schema struct {
+	Title       string // schema name on the wire; [a-zA-Z0-9_-]
+	Type        string // "object" | "array" | "string" | "integer" | "boolean"
+	Description string
+	Properties  map[string]*Schema // object members
+	Required    []string           // object members that must be present
+	Items       *Schema            // array element schema
+	Enum        []string           // allowed string values
+	MinItems    int
+	MaxItems    int  // 0 = unbounded
+	Minimum     *int // integer lower bound
+	Nullable    bool // null is accepted in place of the value
+}
+
+// Validate checks data against s and returns one message per violation,
+// each prefixed with the JSON path it concerns. Unknown object members
+// are reported too: off-schema output is as much a failure as a missing
+// field. A nil result means data conforms.
+func (s *Schema) Validate(data []byte) []string {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return []string{fmt.Sprintf("not valid JSON: %v", err)}
+	}
+	var errs []string
+	s.validate("$", v, &errs)
+	return errs
+}
+
+func (s *Schema) validate(path string, v any, errs *[]string) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, path+": "+fmt.Sprintf(format, args...))
+	}
+	if v == nil {
+		if !s.Nullable {
+			fail("must not be null")
+		}
+		return
+	}
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			fail("must be an object")
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				fail("missing required field %q", name)
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			prop, ok := s.Properties[k]
+			if !ok {
+				fail("unexpected field %q", k)
+				continue
+			}
+			prop.validate(path+"."+k, obj[k], errs)
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			fail("must be an array")
+			return
+		}
+		if len(arr) < s.MinItems {
+			fail("must have at least %d items, got %d", s.MinItems, len(arr))
+		}
+		if s.MaxItems > 0 && len(arr) > s.MaxItems {
+			fail("must have at most %d items, got %d", s.MaxItems, len(arr))
+		}
+		if s.Items != nil {
+			for i, item := range arr {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+			}
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			fail("must be a string")
+			return
+		}
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, str) {
+			fail("must be one of %s, got %q", strings.Join(s.Enum, ", "), str)
+		}
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			fail("must be an integer")
+			return
+		}
+		i, err := n.Int64()
+		if err != nil {
+			fail("must be an integer, got %s", n)
+			return
+		}
+		if s.Minimum != nil && i < int64(*s.Minimum) {
+			fail("must be >= %d, got %d", *s.Minimum, i)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			fail("must be a boolean")
+		}
+	}
+}
+
+// jsonSchema renders s as standard JSON Schema for OpenAI's
+// response_format. Nullable becomes a type union.
+func (s *Schema) jsonSchema() map[string]any {
+	out := map[string]any{"type": s.Type}
+	if s.Nullable {
+		out["type"] = []string{s.Type, "null"}
+	}
+	s.fill(out, (*Schema).jsonSchema)
+	if s.Type == "object" {
+		out["additionalProperties"] = false
+	}
+	return out
+}
+
+// geminiSchema renders s in Gemini's OpenAPI-flavoured responseSchema
+// dialect: upper-case types, a nullable flag, no additionalProperties.
+func (s *Schema) geminiSchema() map[string]any {
+	out := map[string]any{"type": strings.ToUpper(s.Type)}
+	if s.Nullable {
+		out["nullable"] = true
+	}
+	s.fill(out, (*Schema).geminiSchema)
+	return out
+}
+
+func (s *Schema) fill(out map[string]any, render func(*Schema) map[string]any) {
+	if s.Description != "" {
+		out["description"] = s.Description
+	}
+	if len(s.Properties) > 0 {
+		props := make(map[string]any, len(s.Properties))
+		for k, p := range s.Properties {
+			props[k] = render(p)
+		}
+		out["properties"] = props
+	}
+	if len(s.Required) > 0 {
+		out["required"] = s.Required
+	}
+	if s.Items != nil {
+		out["items"] = render(s.Items)
+	}
+	if len(s.Enum) > 0 {
+		out["enum"] = s.Enum
+	}
+	if s.MinItems > 0 {
+		out["minItems"] = s.MinItems
+	}
+	if s.MaxItems > 0 {
+		out["maxItems"] = s.MaxItems
+	}
+	if s.Minimum != nil {
+		out["minimum"] = *s.Minimum
+	}
+}
+
+// fingerprint identifies s for the response cache.
+func (s *Schema) fingerprint() string {
+	if s == nil {
+		return ""
+	}
+	data, _ := json.Marshal(s.jsonSchema())
+	return s.Title + ":" + string(data)
+}
+
+// CompleteStructured sends req (which must carry a Schema) and checks the
+// reply against the schema plus check, an optional hook for rules a
+// static schema cannot express (e.g. indexes bounded by the input). When
+// the reply fails, one repair request quotes the validation errors back
+// to the model. It returns the last response — its Usage summed over
+// both calls — and the violations that remain, nil when it conforms.
+// Callers decide what to salvage from a reply that is still invalid.
+func CompleteStructured(ctx context.Context, p Provider, req Request, check func(content string) []string) (*Response, []string, error) {
+	if req.Schema == nil {
+		return nil, nil, fmt.Errorf("CompleteStructured: request has no schema")
+	}
+	req.JSONMode = true
+	resp, err := p.ChatCompletion(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	errs := structuredErrors(req.Schema, resp.Content, check)
+	if len(errs) == 0 {
+		return resp, nil, nil
+	}
+
+	repair := req
+	repair.Temperature = 0
+	repair.UserPrompt = buildRepairPrompt(req.UserPrompt, resp.Content, errs)
+	fixed, err := p.ChatCompletion(ctx, repair)
+	if err != nil {
+		// The first reply still stands; the caller salvages what it can.
+		return resp, errs, nil
+	}
+	fixed.Usage.InputTokens += resp.Usage.InputTokens
+	fixed.Usage.OutputTokens += resp.Usage.OutputTokens
+	fixed.Cached = fixed.Cached && resp.Cached
+	return fixed, structuredErrors(req.Schema, fixed.Content, check), nil
+}
+
+func structuredErrors(s *Schema, content string, check func(string) []string) []string {
+	errs := s.Validate([]byte(content))
+	if len(errs) == 0 && check != nil {
+		errs = check(content)
+	}
+	return errs
+}
+
+func buildRepairPrompt(prompt, reply string, errs []string) string {
+	var b strings.Builder
+	b.WriteString(prompt)
+	b.WriteString("\n\n## Your Previous Response\n")
+	b.WriteString(reply)
+	b.WriteString("\n\n## Validation Errors\n")
+	for _, e := range errs {
+		fmt.Fprintf(&b, "- %s\n", e)
+	}
+	b.WriteString("\nReturn the corrected JSON only, fixing every error above.")
+	return b.String()
+}
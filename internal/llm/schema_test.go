@@ -0,0 +1,202 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var zero = 0
+
+var testSchema = &Schema{
+	Title:    "test",
+	Type:     "object",
+	Required: []string{"tag"},
+	Properties: map[string]*Schema{
+		"tag":   {Type: "string", Enum: []string{"a", "b"}},
+		"items": {Type: "array", Items: &Schema{Type: "string"}, MaxItems: 2},
+		"index": {Type: "integer", Minimum: &zero},
+		"extra": {Type: "object", Nullable: true, Properties: map[string]*Schema{"x": {Type: "boolean"}}},
+	},
+}
+
+func TestSchemaValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+		want []string // substrings, one per expected violation
+	}{
+		{"valid", `{"tag":"a","items":["x"],"index":0,"extra":null}`, nil},
+		{"bad enum", `{"tag":"c"}`, []string{`$.tag: must be one of a, b, got "c"`}},
+		{"missing required", `{}`, []string{`$: missing required field "tag"`}},
+		{"too many items", `{"tag":"a","items":["x","y","z"]}`, []string{"$.items: must have at most 2 items, got 3"}},
+		{"wrong item type", `{"tag":"a","items":[1]}`, []string{"$.items[0]: must be a string"}},
+		{"negative index", `{"tag":"a","index":-1}`, []string{"$.index: must be >= 0, got -1"}},
+		{"fractional index", `{"tag":"a","index":1.5}`, []string{"$.index: must be an integer"}},
+		{"unexpected field", `{"tag":"a","bogus":1}`, []string{`$: unexpected field "bogus"`}},
+		{"nested", `{"tag":"a","extra":{"x":"yes"}}`, []string{"$.extra.x: must be a boolean"}},
+		{"not json", `{"tag":`, []string{"not valid JSON"}},
+	}
+	for _, tc := range cases {
+		got := testSchema.Validate([]byte(tc.data))
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: got %q, want %d violations", tc.name, got, len(tc.want))
+			continue
+		}
+		for i, w := range tc.want {
+			if !strings.Contains(got[i], w) {
+				t.Errorf("%s: violation %q, want %q", tc.name, got[i], w)
+			}
+		}
+	}
+}
+
+// replyProvider returns its replies in order and records each request.
+type replyProvider struct {
+	replies []string
+	reqs    []Request
+}
+
+func (r *replyProvider) Name() string { return "reply" }
+
+func (r *replyProvider) ChatCompletion(_ context.Context, req Request) (*Response, error) {
+	r.reqs = append(r.reqs, req)
+	content := r.replies[min(len(r.reqs), len(r.replies))-1]
+	return &Response{Content: content, Usage: UsageStats{InputTokens: 10, OutputTokens: 2}}, nil
+}
+
+func TestCompleteStructured_ValidFirstTime(t *testing.T) {
+	p := &replyProvider{replies: []string{`{"tag":"a"}`}}
+	resp, violations, err := CompleteStructured(context.Background(), p, Request{UserPrompt: "u", Schema: testSchema}, nil)
+	if err != nil || violations != nil || resp.Content != `{"tag":"a"}` {
+		t.Fatalf("got %+v, %v, %v", resp, violations, err)
+	}
+	if len(p.reqs) != 1 || !p.reqs[0].JSONMode {
+		t.Errorf("requests = %+v, want one JSON-mode call", p.reqs)
+	}
+}
+
+func TestCompleteStructured_RepairsOnce(t *testing.T) {
+	p := &replyProvider{replies: []string{`{"tag":"c"}`, `{"tag":"b"}`}}
+	resp, violations, err := CompleteStructured(context.Background(), p, Request{UserPrompt: "orig", Schema: testSchema}, nil)
+	if err != nil || violations != nil {
+		t.Fatalf("violations = %v, err = %v", violations, err)
+	}
+	if resp.Content != `{"tag":"b"}` || resp.Usage != (UsageStats{InputTokens: 20, OutputTokens: 4}) {
+		t.Errorf("resp = %+v, want repaired content with summed usage", resp)
+	}
+	repair := p.reqs[1].UserPrompt
+	for _, want := range []string{"orig", `{"tag":"c"}`, `$.tag: must be one of a, b, got "c"`} {
+		if !strings.Contains(repair, want) {
+			t.Errorf("repair prompt missing %q:\n%s", want, repair)
+		}
+	}
+	if p.reqs[1].Temperature != 0 {
+		t.Error("repair should run at temperature 0")
+	}
+}
+
+func TestCompleteStructured_CheckAndGiveUp(t *testing.T) {
+	p := &replyProvider{replies: []string{`{"tag":"a"}`}}
+	check := func(string) []string { return []string{"$.tag: a is not allowed today"} }
+	resp, violations, err := CompleteStructured(context.Background(), p, Request{Schema: testSchema}, check)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.reqs) != 2 {
+		t.Errorf("calls = %d, want exactly one repair", len(p.reqs))
+	}
+	if resp == nil || len(violations) != 1 {
+		t.Errorf("want the last reply plus its remaining violation, got %v", violations)
+	}
+}
+
+func TestOpenAIStructuredOutput(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		json.Unmarshal(raw, &body)
+		io.WriteString(w, `{"choices":[{"message":{"content":"{}"}}]}`)
+	}))
+	defer server.Close()
+
+	p, _ := NewOpenAI(server.URL, "k", "m")
+	if _, err := p.ChatCompletion(context.Background(), Request{JSONMode: true, Schema: testSchema}); err != nil {
+		t.Fatal(err)
+	}
+	rf := body["response_format"].(map[string]any)
+	js := rf["json_schema"].(map[string]any)
+	schema := js["schema"].(map[string]any)
+	if rf["type"] != "json_schema" || js["name"] != "test" || schema["additionalProperties"] != false {
+		t.Errorf("response_format = %v", rf)
+	}
+	extra := schema["properties"].(map[string]any)["extra"].(map[string]any)
+	if types, ok := extra["type"].([]any); !ok || len(types) != 2 || types[1] != "null" {
+		t.Errorf("nullable type = %v, want [object null]", extra["type"])
+	}
+}
+
+func TestOpenAIStructuredOutput_FallsBackToJSONObject(t *testing.T) {
+	var formats []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ResponseFormat oaiRespFormat `json:"response_format"`
+		}
+		raw, _ := io.ReadAll(r.Body)
+		json.Unmarshal(raw, &body)
+		formats = append(formats, body.ResponseFormat.Type)
+		if body.ResponseFormat.Type == "json_schema" {
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"error":{"message":"'response_format.type' must be one of 'json_object', 'text'"}}`)
+			return
+		}
+		io.WriteString(w, `{"choices":[{"message":{"content":"{\"tag\":\"a\"}"}}]}`)
+	}))
+	defer server.Close()
+
+	p, _ := NewOpenAI(server.URL, "k", "m")
+	for range 2 {
+		if _, _, err := CompleteStructured(context.Background(), p, Request{Schema: testSchema}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// One rejected json_schema attempt, then json_object from then on.
+	if want := []string{"json_schema", "json_object", "json_object"}; !slices.Equal(formats, want) {
+		t.Errorf("response_format types = %v, want %v", formats, want)
+	}
+}
+
+func TestGoogleStructuredOutput(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		json.Unmarshal(raw, &body)
+		io.WriteString(w, `{"candidates":[{"content":{"parts":[{"text":"{}"}]}}]}`)
+	}))
+	defer server.Close()
+
+	p := &Google{baseURL: server.URL, apiKey: "k", model: "m", client: server.Client()}
+	if _, err := p.ChatCompletion(context.Background(), Request{Schema: testSchema}); err != nil {
+		t.Fatal(err)
+	}
+	gc := body["generationConfig"].(map[string]any)
+	schema, ok := gc["responseSchema"].(map[string]any)
+	if gc["responseMimeType"] != "application/json" || !ok || schema["type"] != "OBJECT" {
+		t.Fatalf("generationConfig = %v", gc)
+	}
+	if _, ok := schema["additionalProperties"]; ok {
+		t.Error("Gemini rejects additionalProperties")
+	}
+	extra := schema["properties"].(map[string]any)["extra"].(map[string]any)
+	if extra["nullable"] != true {
+		t.Errorf("nullable = %v", extra)
+	}
+}
@@ -38,11 +38,27 @@ type Request struct {
 	// upstream service apply its own default. A non-zero value is passed
 	// through verbatim to every provider.
 	MaxTokens int
+
+	// Schema, when set, describes the JSON the reply must conform to.
+	// OpenAI-compatible and Gemini providers pass it as native structured
+	// output; Anthropic relies on the prompt. See CompleteStructured.
+	Schema *Schema
 }
 
 // Response holds the result of a chat completion call.
 type Response struct {
-	Content string // raw text response from the model
+	Content string     // raw text response from the model
+	Usage   UsageStats // tokens billed for the call; zero when the provider omits usage
+
+	// Cached is set when the response came from the LLM cache (see
+	// WithCache). Usage then reports what the original call consumed;
+	// nothing was billed for this one.
+	Cached bool
+
+	// Provider and Model name the link that served the call when the
+	// provider is a FallbackProvider; empty otherwise.
+	Provider string
+	Model    string
 }
 
 // AgenticProvider extends Provider with multi-turn tool-use support.
@@ -126,5 +142,6 @@ type UsageStats struct {
 type ToolsResponse struct {
 	StopReason string // "stop" | "tool_use" | "max_tokens"
 	Content    []ContentBlock
-	Usage      UsageStats
+	Usage      UsageStats // final turn only
+	TotalUsage UsageStats // summed across every turn of the loop — what was billed
 }
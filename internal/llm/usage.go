@@ -0,0 +1,325 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+)
+
+// LedgerFileName is the usage ledger's file name under the state dir.
+const LedgerFileName = "llm-usage.jsonl"
+
+// Purposes recorded in the usage ledger.
+const (
+	PurposeEnrichment = "enrichment"
+	PurposeSynthesis  = "synthesis"
+	PurposePrDescribe = "pr-describe"
+	PurposeFlowdoc    = "flowdoc"
+	PurposeEval       = "eval"
+)
+
+// UsageLabels attribute a call in the usage ledger. They travel on the
+// context so call sites label their requests without the provider
+// signature changing.
+type UsageLabels struct {
+	Purpose string
+	Project string
+	Session string
+}
+
+type usageLabelsKey struct{}
+
+// WithUsageLabels returns a context whose LLM calls are recorded under
+// labels.
+func WithUsageLabels(ctx context.Context, labels UsageLabels) context.Context {
+	return context.WithValue(ctx, usageLabelsKey{}, labels)
+}
+
+// UsageLabelsFrom returns the labels attached by WithUsageLabels, or the
+// zero value.
+func UsageLabelsFrom(ctx context.Context) UsageLabels {
+	l, _ := ctx.Value(usageLabelsKey{}).(UsageLabels)
+	return l
+}
+
+// UsageRecord is one line of the usage ledger: a single completed call.
+// Cached calls are recorded with the original call's token counts but
+// were not billed.
+type UsageRecord struct {
+	Time         time.Time `json:"time"`
+	Provider     string    `json:"provider"`
+	Model        string    `json:"model"`
+	Purpose      string    `json:"purpose,omitempty"`
+	Project      string    `json:"project,omitempty"`
+	Session      string    `json:"session,omitempty"`
+	InputTokens  int       `json:"input_tokens"`
+	OutputTokens int       `json:"output_tokens"`
+	Cached       bool      `json:"cached,omitempty"`
+}
+
+// Ledger is an append-only JSONL log of LLM calls. Write failures are
+// logged and never fail a call.
+type Ledger struct {
+	path string
+	mu   sync.Mutex
+	now  func() time.Time
+}
+
+// NewLedger returns a ledger writing to path. The file and its directory
+// are created on first append.
+func NewLedger(path string) *Ledger {
+	return &Ledger{path: path, now: time.Now}
+}
+
+// LedgerPath returns the usage ledger path for cfg.
+func LedgerPath(cfg config.Config) string {
+	return filepath.Join(cfg.StateDir(), LedgerFileName)
+}
+
+// OpenLedger returns the usage ledger for cfg.
+func OpenLedger(cfg config.Config) *Ledger {
+	return NewLedger(LedgerPath(cfg))
+}
+
+// Append writes one record, stamping Time when it is zero. Each record is
+// a single O_APPEND write, so concurrent hook and CLI processes interleave
+// whole lines.
+func (l *Ledger) Append(r UsageRecord) error {
+	if r.Time.IsZero() {
+		r.Time = l.now().UTC()
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	_, werr := f.Write(data)
+	if cerr := f.Close(); werr == nil {
+		werr = cerr
+	}
+	return werr
+}
+
+func (l *Ledger) record(ctx context.Context, provider, model string, u UsageStats, cached bool) {
+	labels := UsageLabelsFrom(ctx)
+	err := l.Append(UsageRecord{
+		Provider:     provider,
+		Model:        model,
+		Purpose:      labels.Purpose,
+		Project:      labels.Project,
+		Session:      labels.Session,
+		InputTokens:  u.InputTokens,
+		OutputTokens: u.OutputTokens,
+		Cached:       cached,
+	})
+	if err != nil {
+		log.Printf("warning: llm usage ledger: %v", err)
+	}
+}
+
+// ReadUsage returns every ledger record at or after since (zero = all),
+// oldest first. A missing ledger yields no records; malformed lines are
+// skipped.
+func ReadUsage(path string, since time.Time) ([]UsageRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("open usage ledger: %w", err)
+	}
+	defer f.Close()
+
+	var out []UsageRecord
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var r UsageRecord
+		if json.Unmarshal(sc.Bytes(), &r) != nil {
+			continue
+		}
+		if !since.IsZero() && r.Time.Before(since) {
+			continue
+		}
+		out = append(out, r)
+	}
+	if err := sc.Err(); err != nil {
+		return out, fmt.Errorf("read usage ledger: %w", err)
+	}
+	return out, nil
+}
+
+// ledgerProvider records every successful ChatCompletion in a Ledger.
+type ledgerProvider struct {
+	inner  Provider
+	ledger *Ledger
+	model  string // recorded model when a request leaves Model empty
+}
+
+// WithLedger wraps a Provider so each successful call is appended to l.
+// model is the provider's configured default model, recorded for
+// requests that leave Model empty. A nil ledger returns p unchanged.
+func WithLedger(p Provider, l *Ledger, model string) Provider {
+	if p == nil || l == nil {
+		return p
+	}
+	return &ledgerProvider{inner: p, ledger: l, model: model}
+}
+
+func (lp *ledgerProvider) Name() string { return lp.inner.Name() }
+
+func (lp *ledgerProvider) ChatCompletion(ctx context.Context, req Request) (*Response, error) {
+	resp, err := lp.inner.ChatCompletion(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	provider, model := lp.inner.Name(), req.Model
+	if model == "" {
+		model = lp.model
+	}
+	if resp.Provider != "" {
+		provider, model = resp.Provider, resp.Model
+	}
+	lp.ledger.record(ctx, provider, model, resp.Usage, resp.Cached)
+	return resp, nil
+}
+
+// ledgerAgenticProvider is ledgerProvider for AgenticProvider; a RunTools
+// loop is recorded as one call carrying its TotalUsage.
+type ledgerAgenticProvider struct {
+	ledgerProvider
+	agentic AgenticProvider
+}
+
+// WithAgenticLedger is WithLedger for an AgenticProvider.
+func WithAgenticLedger(p AgenticProvider, l *Ledger, model string) AgenticProvider {
+	if p == nil || l == nil {
+		return p
+	}
+	return &ledgerAgenticProvider{
+		ledgerProvider: ledgerProvider{inner: p, ledger: l, model: model},
+		agentic:        p,
+	}
+}
+
+func (lp *ledgerAgenticProvider) RunTools(ctx context.Context, req ToolsRequest) (*ToolsResponse, error) {
+	resp, err := lp.agentic.RunTools(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	model := req.Model
+	if model == "" {
+		model = lp.model
+	}
+	lp.ledger.record(ctx, lp.agentic.Name(), model, resp.TotalUsage, false)
+	return resp, nil
+}
+
+// UsageTotal aggregates ledger records sharing a key. CostUSD counts only
+// billed (uncached) calls.
+type UsageTotal struct {
+	Key          string  `json:"key"`
+	Calls        int     `json:"calls"`
+	CachedCalls  int     `json:"cached_calls"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+}
+
+// UsageSummary totals ledger records per provider/model and per purpose.
+type UsageSummary struct {
+	Since      time.Time    `json:"since,omitzero"`
+	Total      UsageTotal   `json:"total"`
+	ByProvider []UsageTotal `json:"by_provider"` // key "provider/model"
+	ByPurpose  []UsageTotal `json:"by_purpose"`  // key purpose, "unlabeled" when empty
+}
+
+// CostFunc prices one call's tokens for model; nil prices everything at 0.
+type CostFunc func(model string, u UsageStats) float64
+
+// SummarizeUsage totals records, pricing billed calls with cost. Cached
+// calls count toward Calls and CachedCalls but add no tokens or cost, so
+// the token columns reflect what was billed. Groups are ordered by cost,
+// then calls, descending.
+func SummarizeUsage(records []UsageRecord, cost CostFunc) UsageSummary {
+	var s UsageSummary
+	byProvider := make(map[string]*UsageTotal)
+	byPurpose := make(map[string]*UsageTotal)
+	add := func(m map[string]*UsageTotal, key string, r UsageRecord, c float64) {
+		t, ok := m[key]
+		if !ok {
+			t = &UsageTotal{Key: key}
+			m[key] = t
+		}
+		t.add(r, c)
+	}
+	for _, r := range records {
+		c := 0.0
+		if !r.Cached && cost != nil {
+			c = cost(r.Model, UsageStats{InputTokens: r.InputTokens, OutputTokens: r.OutputTokens})
+		}
+		s.Total.add(r, c)
+		add(byProvider, r.Provider+"/"+r.Model, r, c)
+		purpose := r.Purpose
+		if purpose == "" {
+			purpose = "unlabeled"
+		}
+		add(byPurpose, purpose, r, c)
+	}
+	s.Total.Key = "total"
+	s.ByProvider = sortedTotals(byProvider)
+	s.ByPurpose = sortedTotals(byPurpose)
+	return s
+}
+
+func (t *UsageTotal) add(r UsageRecord, cost float64) {
+	t.Calls++
+	if r.Cached {
+		t.CachedCalls++
+		return
+	}
+	t.InputTokens += r.InputTokens
+	t.OutputTokens += r.OutputTokens
+	t.CostUSD += cost
+}
+
+func sortedTotals(m map[string]*UsageTotal) []UsageTotal {
+	out := make([]UsageTotal, 0, len(m))
+	for _, t := range m {
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].CostUSD != out[j].CostUSD {
+			return out[i].CostUSD > out[j].CostUSD
+		}
+		if out[i].Calls != out[j].Calls {
+			return out[i].Calls > out[j].Calls
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
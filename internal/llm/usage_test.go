@@ -0,0 +1,125 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package llm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// usageProvider returns a fixed response with token usage.
+type usageProvider struct{ calls int }
+
+func (u *usageProvider) Name() string { return "openai" }
+
+func (u *usageProvider) ChatCompletion(_ context.Context, _ Request) (*Response, error) {
+	u.calls++
+	return &Response{Content: "ok", Usage: UsageStats{InputTokens: 100, OutputTokens: 20}}, nil
+}
+
+func TestLedger_RecordsLabelsAndCacheHits(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, LedgerFileName)
+	inner := &usageProvider{}
+	p := WithLedger(WithCache(inner, NewCache(filepath.Join(dir, CacheDirName), 0), "grok-3"), NewLedger(path), "grok-3")
+
+	ctx := WithUsageLabels(context.Background(), UsageLabels{Purpose: PurposeEnrichment, Project: "vv", Session: "s1"})
+	for i := 0; i < 2; i++ {
+		resp, err := p.ChatCompletion(ctx, Request{UserPrompt: "same"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Usage.InputTokens != 100 || resp.Cached != (i == 1) {
+			t.Fatalf("call %d: usage %+v cached %v", i, resp.Usage, resp.Cached)
+		}
+	}
+	if inner.calls != 1 {
+		t.Errorf("provider calls = %d, want 1", inner.calls)
+	}
+
+	records, err := ReadUsage(path, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("records = %d, want 2", len(records))
+	}
+	r := records[0]
+	if r.Provider != "openai" || r.Model != "grok-3" || r.Purpose != PurposeEnrichment ||
+		r.Project != "vv" || r.Session != "s1" || r.InputTokens != 100 || r.OutputTokens != 20 || r.Cached {
+		t.Errorf("first record = %+v", r)
+	}
+	if !records[1].Cached {
+		t.Error("second record should be flagged cached")
+	}
+}
+
+func TestReadUsage_SinceAndMalformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LedgerFileName)
+	l := NewLedger(path)
+	old := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	recent := time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC)
+	if err := l.Append(UsageRecord{Time: old, Provider: "openai", Model: "m"}); err != nil {
+		t.Fatal(err)
+	}
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	f.WriteString("not json\n")
+	f.Close()
+	if err := l.Append(UsageRecord{Time: recent, Provider: "openai", Model: "m"}); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := ReadUsage(path, time.Time{})
+	if err != nil || len(all) != 2 {
+		t.Fatalf("all = %d records, err %v; want 2", len(all), err)
+	}
+	since, _ := ReadUsage(path, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	if len(since) != 1 || !since[0].Time.Equal(recent) {
+		t.Fatalf("since = %+v, want only the October record", since)
+	}
+
+	missing, err := ReadUsage(filepath.Join(t.TempDir(), "none.jsonl"), time.Time{})
+	if err != nil || missing != nil {
+		t.Fatalf("missing ledger = %v, %v; want nil, nil", missing, err)
+	}
+}
+
+func TestSummarizeUsage(t *testing.T) {
+	records := []UsageRecord{
+		{Provider: "openai", Model: "grok", Purpose: PurposeEnrichment, InputTokens: 1000, OutputTokens: 100},
+		{Provider: "openai", Model: "grok", Purpose: PurposeEnrichment, InputTokens: 1000, OutputTokens: 100, Cached: true},
+		{Provider: "anthropic", Model: "claude", Purpose: PurposeFlowdoc, InputTokens: 5000, OutputTokens: 500},
+		{Provider: "openai", Model: "grok", InputTokens: 10, OutputTokens: 1},
+	}
+	cost := func(model string, u UsageStats) float64 {
+		if model == "claude" {
+			return float64(u.InputTokens+u.OutputTokens) / 1000
+		}
+		return float64(u.InputTokens+u.OutputTokens) / 10000
+	}
+	s := SummarizeUsage(records, cost)
+
+	if s.Total.Calls != 4 || s.Total.CachedCalls != 1 || s.Total.InputTokens != 6010 || s.Total.OutputTokens != 601 {
+		t.Errorf("total = %+v", s.Total)
+	}
+	if len(s.ByProvider) != 2 || s.ByProvider[0].Key != "anthropic/claude" {
+		t.Fatalf("by provider = %+v, want anthropic/claude first (highest cost)", s.ByProvider)
+	}
+	if g := s.ByProvider[1]; g.Calls != 3 || g.CachedCalls != 1 || g.InputTokens != 1010 {
+		t.Errorf("openai/grok = %+v", g)
+	}
+	purposes := map[string]UsageTotal{}
+	for _, p := range s.ByPurpose {
+		purposes[p.Key] = p
+	}
+	if purposes["unlabeled"].Calls != 1 || purposes[PurposeEnrichment].Calls != 2 || purposes[PurposeFlowdoc].Calls != 1 {
+		t.Errorf("by purpose = %+v", s.ByPurpose)
+	}
+	if c := purposes[PurposeEnrichment].CostUSD; c < 0.109 || c > 0.111 {
+		t.Errorf("enrichment cost = %v, want 0.11 (cached call unbilled)", c)
+	}
+}
@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"log"
@@ -19,7 +20,7 @@ func TestRunChecksAllPass(t *testing.T) {
 			Description: "echoes input",
 			InputSchema: json.RawMessage(`{"type":"object","properties":{"msg":{"type":"string"}}}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) { return "ok", nil },
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) { return "ok", nil },
 	})
 	srv.RegisterPrompt(Prompt{
 		Definition: PromptDef{
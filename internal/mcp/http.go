@@ -0,0 +1,459 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HTTPPath is the endpoint the Streamable HTTP transport serves.
+const HTTPPath = "/mcp"
+
+// SessionHeader carries the session ID the server assigns on initialize.
+const SessionHeader = "Mcp-Session-Id"
+
+const (
+	httpMaxBody     = 10 * 1024 * 1024 // matches Serve's line limit
+	httpSessionIdle = 24 * time.Hour   // sessions unused this long are dropped
+	httpMaxSessions = 64               // live sessions at once
+	httpEventBuffer = 64               // queued notifications per session
+	httpKeepAlive   = 30 * time.Second // SSE comment interval
+)
+
+// errTooManySessions refuses an initialize when every session is in use.
+var errTooManySessions = errors.New("too many sessions")
+
+// HTTPOptions configures the Streamable HTTP transport.
+type HTTPOptions struct {
+	// Token, when set, must arrive as "Authorization: Bearer <token>".
+	Token string
+}
+
+// HTTPHandler serves a Server over the MCP Streamable HTTP transport.
+// Clients POST JSON-RPC messages and receive responses in the reply body;
+// a GET held open is an SSE stream carrying server-initiated
+// notifications; DELETE ends the session. Each initialize starts a
+// session named by the Mcp-Session-Id header. Tools, prompts, and
+// resources are shared by every session; subscriptions are per session.
+type HTTPHandler struct {
+	srv         *Server
+	token       string
+	idle        time.Duration // httpSessionIdle; tests shorten it
+	maxSessions int           // httpMaxSessions; tests lower it
+
+	mu       sync.Mutex
+	sessions map[string]*httpSession
+}
+
+type httpSession struct {
+	*conn
+	id     string
+	events chan []byte
+	done   chan struct{} // closed when the session ends
+
+	// Guarded by HTTPHandler.mu.
+	lastSeen  time.Time
+	streaming bool
+}
+
+// NewHTTPHandler returns a handler serving srv at HTTPPath.
+func NewHTTPHandler(srv *Server, opts HTTPOptions) *HTTPHandler {
+	return &HTTPHandler{
+		srv:         srv,
+		token:       opts.Token,
+		idle:        httpSessionIdle,
+		maxSessions: httpMaxSessions,
+		sessions:    make(map[string]*httpSession),
+	}
+}
+
+// ServeStreamableHTTP serves srv over Streamable HTTP on ln until ctx is
+// cancelled.
+func (s *Server) ServeStreamableHTTP(ctx context.Context, ln net.Listener, opts HTTPOptions) error {
+	h := NewHTTPHandler(s, opts)
+	defer h.Close()
+	mux := http.NewServeMux()
+	mux.Handle(HTTPPath, h)
+	hs := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- hs.Serve(ln) }()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	// End the SSE streams first; Shutdown waits for open handlers.
+	h.Close()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := hs.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Close ends every session.
+func (h *HTTPHandler) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, hs := range h.sessions {
+		h.endLocked(hs)
+	}
+}
+
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !allowedOrigin(r.Header.Get("Origin")) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="vibe-vault"`)
+		http.Error(w, "missing or invalid bearer token", http.StatusUnauthorized)
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		h.handlePost(w, r)
+	case http.MethodGet:
+		h.handleStream(w, r)
+	case http.MethodDelete:
+		h.handleDelete(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// allowedOrigin rejects browser requests from non-local pages, which
+// could otherwise reach a loopback server through DNS rebinding.
+// Requests without an Origin (editors, CLIs) are allowed: browsers send
+// Origin on every cross-origin POST, so a missing header means a
+// non-browser client, which is safe only because the server listens on
+// loopback (or behind a bearer token). Do not relax this further, e.g.
+// to accept "null" or a non-loopback host.
+func allowedOrigin(origin string) bool {
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (h *HTTPHandler) authorized(r *http.Request) bool {
+	if h.token == "" {
+		return true
+	}
+	got := r.Header.Get("Authorization")
+	return subtle.ConstantTimeCompare([]byte(got), []byte("Bearer "+h.token)) == 1
+}
+
+func (h *HTTPHandler) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, httpMaxBody))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "read request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	body = bytes.TrimSpace(body)
+	if h.srv.debug {
+		h.srv.logger.Printf("[MCP] <- %s", body)
+	}
+
+	batch := len(body) > 0 && body[0] == '['
+	raws := []json.RawMessage{body}
+	if batch {
+		err = json.Unmarshal(body, &raws)
+	}
+	reqs := make([]Request, len(raws))
+	for i, raw := range raws {
+		if err == nil {
+			err = json.Unmarshal(raw, &reqs[i])
+		}
+	}
+	if err != nil || len(reqs) == 0 {
+		h.writeJSON(w, http.StatusBadRequest, Response{
+			JSONRPC: "2.0",
+			Error:   &RPCError{Code: CodeParseError, Message: "parse error"},
+		})
+		return
+	}
+
+	var hs *httpSession
+	if len(reqs) == 1 && reqs[0].Method == "initialize" {
+		if hs, err = h.newSession(); errors.Is(err, errTooManySessions) {
+			w.Header().Set("Retry-After", "60")
+			http.Error(w, "too many sessions; end one with DELETE or retry later", http.StatusServiceUnavailable)
+			return
+		} else if err != nil {
+			h.srv.logger.Printf("mcp http: start session: %v", err)
+			http.Error(w, "could not start session", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set(SessionHeader, hs.id)
+		h.srv.logger.Printf("mcp http: session %s started", hs.id)
+	} else if hs = h.lookup(w, r); hs == nil {
+		return
+	}
+
+	// Tool calls in a batch run concurrently; each answer keeps its
+	// request's place. A call cancelled by notifications/cancelled or
+	// by the client hanging up has no answer.
+	answers := make([]*Response, len(reqs))
+	var calls sync.WaitGroup
+	for i, req := range reqs {
+		// A message without a method is the client answering a
+		// server request; vv sends none.
+		if req.Method == "" {
+			continue
+		}
+		if req.Method == "tools/call" && req.ID != nil {
+			ctx, done, ok := hs.begin(r.Context(), req.ID)
+			if !ok {
+				resp := duplicateIDResponse(req.ID)
+				answers[i] = &resp
+				continue
+			}
+			calls.Add(1)
+			go func() {
+				defer calls.Done()
+				defer done()
+				if resp, ok := h.srv.call(ctx, hs.conn, req); ok {
+					answers[i] = &resp
+				}
+			}()
+			continue
+		}
+		resp := h.srv.dispatch(r.Context(), hs.conn, req)
+		if req.ID != nil {
+			answers[i] = &resp
+		}
+	}
+	calls.Wait()
+
+	var resps []Response
+	for _, resp := range answers {
+		if resp != nil {
+			resps = append(resps, *resp)
+		}
+	}
+
+	switch {
+	case len(resps) == 0:
+		w.WriteHeader(http.StatusAccepted)
+	case batch:
+		h.writeJSON(w, http.StatusOK, resps)
+	default:
+		h.writeJSON(w, http.StatusOK, resps[0])
+	}
+}
+
+// handleStream holds a GET open as the session's SSE stream.
+func (h *HTTPHandler) handleStream(w http.ResponseWriter, r *http.Request) {
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		http.Error(w, "GET requires Accept: text/event-stream", http.StatusNotAcceptable)
+		return
+	}
+	hs := h.lookup(w, r)
+	if hs == nil {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	h.mu.Lock()
+	if hs.streaming {
+		h.mu.Unlock()
+		http.Error(w, "a stream is already open for this session", http.StatusConflict)
+		return
+	}
+	hs.streaming = true
+	h.mu.Unlock()
+	defer func() {
+		h.mu.Lock()
+		hs.streaming = false
+		hs.lastSeen = time.Now()
+		h.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(httpKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		var err error
+		select {
+		case data := <-hs.events:
+			_, err = fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+		case <-keepAlive.C:
+			_, err = io.WriteString(w, ": keepalive\n\n")
+		case <-hs.done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+		if err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+func (h *HTTPHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	hs := h.lookup(w, r)
+	if hs == nil {
+		return
+	}
+	h.mu.Lock()
+	h.endLocked(hs)
+	h.mu.Unlock()
+	h.srv.logger.Printf("mcp http: session %s ended", hs.id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// newSession starts a session. At the session limit the least recently
+// used one without an open stream or a running call is ended to make
+// room; when every session is in use it returns errTooManySessions.
+func (h *HTTPHandler) newSession() (*httpSession, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+	hs := &httpSession{
+		id:       id,
+		events:   make(chan []byte, httpEventBuffer),
+		done:     make(chan struct{}),
+		lastSeen: time.Now(),
+	}
+	hs.conn = newConn(func(msg any) {
+		data, err := h.srv.marshalMessage(msg)
+		if err != nil {
+			return
+		}
+		select {
+		case hs.events <- data:
+		default:
+			h.srv.logger.Printf("mcp http: session %s: event queue full, dropping message", hs.id)
+		}
+	}, maxCallsPerSession)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.expireLocked()
+	if len(h.sessions) >= h.maxSessions {
+		var lru *httpSession
+		for _, old := range h.sessions {
+			if !old.streaming && !old.busy() && (lru == nil || old.lastSeen.Before(lru.lastSeen)) {
+				lru = old
+			}
+		}
+		if lru == nil {
+			hs.conn.close()
+			return nil, errTooManySessions
+		}
+		h.endLocked(lru)
+		h.srv.logger.Printf("mcp http: session %s evicted to make room", lru.id)
+	}
+	h.sessions[hs.id] = hs
+	return hs, nil
+}
+
+// expireLocked ends sessions left idle by clients that went away
+// without a DELETE. It runs on every session lookup and start.
+func (h *HTTPHandler) expireLocked() {
+	for _, old := range h.sessions {
+		if !old.streaming && !old.busy() && time.Since(old.lastSeen) > h.idle {
+			h.endLocked(old)
+			h.srv.logger.Printf("mcp http: session %s expired", old.id)
+		}
+	}
+}
+
+// lookup finds the request's session, answering 400 when the header is
+// missing and 404 when the session is unknown or ended; the client
+// starts over with initialize on 404.
+func (h *HTTPHandler) lookup(w http.ResponseWriter, r *http.Request) *httpSession {
+	id := r.Header.Get(SessionHeader)
+	if id == "" {
+		http.Error(w, "missing "+SessionHeader+" header", http.StatusBadRequest)
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.expireLocked()
+	hs, ok := h.sessions[id]
+	if !ok {
+		http.Error(w, "unknown or expired session", http.StatusNotFound)
+		return nil
+	}
+	hs.lastSeen = time.Now()
+	return hs
+}
+
+func (h *HTTPHandler) endLocked(hs *httpSession) {
+	if _, ok := h.sessions[hs.id]; !ok {
+		return
+	}
+	delete(h.sessions, hs.id)
+	close(hs.done)
+	hs.conn.close()
+}
+
+func (h *HTTPHandler) writeJSON(w http.ResponseWriter, status int, msg any) {
+	data, err := h.srv.marshalMessage(msg)
+	if err != nil {
+		http.Error(w, "marshal response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// newSessionID returns a random session ID. The ID is the only thing
+// tying requests to a session, so a failed read is an error rather than
+// a predictable ID.
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate session id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,370 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// httpClient drives one MCP session against a test server.
+type httpClient struct {
+	t       *testing.T
+	url     string
+	token   string
+	session string
+}
+
+func newHTTPTest(t *testing.T, srv *Server, opts HTTPOptions) *httptest.Server {
+	t.Helper()
+	return serveHTTPTest(t, NewHTTPHandler(srv, opts))
+}
+
+func serveHTTPTest(t *testing.T, h *HTTPHandler) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(h)
+	t.Cleanup(func() {
+		h.Close()
+		ts.Close()
+	})
+	return ts
+}
+
+func (c *httpClient) do(method, body string, header ...string) *http.Response {
+	c.t.Helper()
+	req, _ := http.NewRequest(method, c.url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.session != "" {
+		req.Header.Set(SessionHeader, c.session)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return resp
+}
+
+// call POSTs body and decodes the JSON-RPC response.
+func (c *httpClient) call(body string) Response {
+	c.t.Helper()
+	resp := c.do(http.MethodPost, body)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		c.t.Fatalf("POST %s: status %d", body, resp.StatusCode)
+	}
+	var r Response
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		c.t.Fatal(err)
+	}
+	return r
+}
+
+func (c *httpClient) initialize() {
+	c.t.Helper()
+	resp := c.do(http.MethodPost, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		c.t.Fatalf("initialize: status %d", resp.StatusCode)
+	}
+	if c.session = resp.Header.Get(SessionHeader); c.session == "" {
+		c.t.Fatal("initialize returned no session ID")
+	}
+}
+
+func TestHTTP_SessionLifecycle(t *testing.T) {
+	ts := newHTTPTest(t, testServer(), HTTPOptions{})
+	c := &httpClient{t: t, url: ts.URL + HTTPPath}
+
+	// Anything but initialize needs a session.
+	if resp := c.do(http.MethodPost, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("no session: status %d, want 400", resp.StatusCode)
+	}
+	c.initialize()
+
+	r := c.call(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{"msg":"hi"}}}`)
+	data, _ := json.Marshal(r.Result)
+	if !strings.Contains(string(data), `"text":"hi"`) {
+		t.Errorf("tools/call result = %s", data)
+	}
+	if resp := c.do(http.MethodPost, `{"jsonrpc":"2.0","method":"notifications/initialized"}`); resp.StatusCode != http.StatusAccepted {
+		t.Errorf("notification: status %d, want 202", resp.StatusCode)
+	}
+
+	// A batch gets an array back, with no entry for its notification.
+	resp := c.do(http.MethodPost, `[{"jsonrpc":"2.0","id":3,"method":"tools/list"},{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","id":4,"method":"prompts/list"}]`)
+	var batch []Response
+	json.NewDecoder(resp.Body).Decode(&batch)
+	resp.Body.Close()
+	if len(batch) != 2 || string(batch[0].ID) != "3" || string(batch[1].ID) != "4" {
+		t.Errorf("batch responses = %+v", batch)
+	}
+
+	if resp := c.do(http.MethodPost, `{not json`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("parse error: status %d, want 400", resp.StatusCode)
+	}
+	if resp := c.do(http.MethodPut, ``); resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("PUT: status %d, want 405", resp.StatusCode)
+	}
+
+	if resp := c.do(http.MethodDelete, ``); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("DELETE: status %d, want 204", resp.StatusCode)
+	}
+	if resp := c.do(http.MethodPost, `{"jsonrpc":"2.0","id":5,"method":"tools/list"}`); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("ended session: status %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestHTTP_BearerTokenAndOrigin(t *testing.T) {
+	ts := newHTTPTest(t, testServer(), HTTPOptions{Token: "s3cret"})
+	init := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`
+
+	for _, token := range []string{"", "wrong"} {
+		c := &httpClient{t: t, url: ts.URL + HTTPPath, token: token}
+		resp := c.do(http.MethodPost, init)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") == "" {
+			t.Errorf("token %q: status %d, want 401 with challenge", token, resp.StatusCode)
+		}
+	}
+
+	c := &httpClient{t: t, url: ts.URL + HTTPPath, token: "s3cret"}
+	c.initialize()
+
+	for origin, want := range map[string]int{
+		"https://evil.example":  http.StatusForbidden,
+		"http://localhost:3000": http.StatusOK,
+		"http://127.0.0.1":      http.StatusOK,
+	} {
+		resp := c.do(http.MethodPost, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`, "Origin", origin)
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("Origin %s: status %d, want %d", origin, resp.StatusCode, want)
+		}
+	}
+}
+
+func TestHTTP_StreamCarriesSessionNotifications(t *testing.T) {
+	srv, cfg := resourceVault(t)
+	ts := newHTTPTest(t, srv, HTTPOptions{})
+	a := &httpClient{t: t, url: ts.URL + HTTPPath}
+	b := &httpClient{t: t, url: ts.URL + HTTPPath}
+	a.initialize()
+	b.initialize()
+	if a.session == b.session {
+		t.Fatal("sessions share an ID")
+	}
+
+	resp := a.do(http.MethodGet, ``)
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("GET: status %d, type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	defer resp.Body.Close()
+	stream := bufio.NewReader(resp.Body)
+	if resp := a.do(http.MethodGet, ``); resp.StatusCode != http.StatusConflict {
+		t.Errorf("second stream: status %d, want 409", resp.StatusCode)
+	}
+	// Another session's stream is independent.
+	if resp := b.do(http.MethodGet, ``); resp.StatusCode != http.StatusOK {
+		t.Errorf("GET b: status %d", resp.StatusCode)
+	} else {
+		resp.Body.Close()
+	}
+	if r := a.call(`{"jsonrpc":"2.0","id":2,"method":"resources/subscribe","params":{"uri":"vv://project/acme/resume"}}`); r.Error != nil {
+		t.Fatalf("subscribe: %s", r.Error.Message)
+	}
+
+	resume := filepath.Join(cfg.ProjectsDir(), "acme", "agentctx", "resume.md")
+	os.WriteFile(resume, []byte("# Resume v2\n"), 0o644)
+
+	lines := make(chan string, 8)
+	go func() {
+		for {
+			line, err := stream.ReadString('\n')
+			if err != nil {
+				return
+			}
+			lines <- line
+		}
+	}()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case line := <-lines:
+			if strings.HasPrefix(line, "data: ") {
+				if !strings.Contains(line, `"method":"notifications/resources/updated"`) || !strings.Contains(line, "vv://project/acme/resume") {
+					t.Errorf("event = %s", line)
+				}
+				return
+			}
+		case <-timeout:
+			t.Fatal("no notification on the subscribed session's stream")
+		}
+	}
+}
+
+func TestHTTP_CallsRunConcurrentlyAndCancel(t *testing.T) {
+	srv := testServer()
+	w := newWaitTool(srv)
+	ts := newHTTPTest(t, srv, HTTPOptions{})
+	c := &httpClient{t: t, url: ts.URL + HTTPPath}
+	c.initialize()
+
+	status := make(chan int, 1)
+	go func() {
+		resp := c.do(http.MethodPost, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"wait"}}`)
+		resp.Body.Close()
+		status <- resp.StatusCode
+	}()
+	<-w.started
+
+	// The session keeps answering while the call runs.
+	if r := c.call(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"msg":"hi"}}}`); r.Error != nil {
+		t.Fatalf("echo: %s", r.Error.Message)
+	}
+
+	if resp := c.do(http.MethodPost, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":2}}`); resp.StatusCode != http.StatusAccepted {
+		t.Errorf("cancel: status %d, want 202", resp.StatusCode)
+	}
+	if err := <-w.ended; !errors.Is(err, context.Canceled) {
+		t.Errorf("handler ended with %v, want context.Canceled", err)
+	}
+	// A cancelled call has no response, so its POST is answered like a
+	// notification.
+	if got := <-status; got != http.StatusAccepted {
+		t.Errorf("cancelled call: status %d, want 202", got)
+	}
+}
+
+func TestHTTP_SessionCallLimit(t *testing.T) {
+	srv := testServer()
+	w := newWaitTool(srv)
+	ts := newHTTPTest(t, srv, HTTPOptions{})
+	greedy := &httpClient{t: t, url: ts.URL + HTTPPath}
+	greedy.initialize()
+	other := &httpClient{t: t, url: ts.URL + HTTPPath}
+	other.initialize()
+
+	var wg sync.WaitGroup
+	post := func(c *httpClient, id int) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.do(http.MethodPost, `{"jsonrpc":"2.0","id":`+strconv.Itoa(id)+`,"method":"tools/call","params":{"name":"wait"}}`).Body.Close()
+		}()
+	}
+	for id := 1; id <= maxCallsPerSession+1; id++ {
+		post(greedy, id)
+	}
+	for range maxCallsPerSession {
+		<-w.started
+	}
+	select {
+	case <-w.started:
+		t.Fatalf("one session ran more than %d calls at once", maxCallsPerSession)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Another session still gets a server slot, and a reused in-flight
+	// ID is refused rather than replacing the running call.
+	post(other, 1)
+	select {
+	case <-w.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("a busy session starved another session's call")
+	}
+	if r := greedy.call(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"msg":"dup"}}}`); r.Error == nil || r.Error.Code != CodeInvalidRequest {
+		t.Errorf("duplicate in-flight id: %+v", r)
+	}
+
+	close(w.release)
+	wg.Wait()
+}
+
+func TestHTTP_SessionCapEvictsLeastRecentlyUsed(t *testing.T) {
+	h := NewHTTPHandler(testServer(), HTTPOptions{})
+	h.maxSessions = 2
+	ts := serveHTTPTest(t, h)
+	clients := make([]*httpClient, 3)
+	for i := range clients {
+		clients[i] = &httpClient{t: t, url: ts.URL + HTTPPath}
+	}
+	clients[0].initialize()
+	clients[1].initialize()
+	clients[0].call(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+
+	// The third session pushes out the one used longest ago.
+	clients[2].initialize()
+	if resp := clients[1].do(http.MethodPost, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("evicted session: status %d, want 404", resp.StatusCode)
+	}
+	for _, c := range []*httpClient{clients[0], clients[2]} {
+		c.call(`{"jsonrpc":"2.0","id":3,"method":"tools/list"}`)
+	}
+}
+
+func TestHTTP_SessionCapRefusesWhenAllBusy(t *testing.T) {
+	srv := testServer()
+	w := newWaitTool(srv)
+	h := NewHTTPHandler(srv, HTTPOptions{})
+	h.maxSessions = 1
+	ts := serveHTTPTest(t, h)
+	busy := &httpClient{t: t, url: ts.URL + HTTPPath}
+	busy.initialize()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		busy.do(http.MethodPost, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"wait"}}`).Body.Close()
+	}()
+	<-w.started
+
+	next := &httpClient{t: t, url: ts.URL + HTTPPath}
+	resp := next.do(http.MethodPost, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || resp.Header.Get("Retry-After") == "" {
+		t.Errorf("initialize at the cap with a running call: status %d, Retry-After %q", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+
+	close(w.release)
+	<-done
+	next.initialize()
+}
+
+func TestHTTP_IdleSessionExpiresOnLookup(t *testing.T) {
+	h := NewHTTPHandler(testServer(), HTTPOptions{})
+	h.idle = 50 * time.Millisecond
+	ts := serveHTTPTest(t, h)
+	c := &httpClient{t: t, url: ts.URL + HTTPPath}
+	c.initialize()
+	c.call(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+
+	time.Sleep(100 * time.Millisecond)
+	if resp := c.do(http.MethodPost, `{"jsonrpc":"2.0","id":3,"method":"tools/list"}`); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("idle session: status %d, want 404", resp.StatusCode)
+	}
+	h.mu.Lock()
+	n := len(h.sessions)
+	h.mu.Unlock()
+	if n != 0 {
+		t.Errorf("%d sessions left after expiry", n)
+	}
+}
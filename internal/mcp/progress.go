@@ -0,0 +1,48 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// progressKey is the context key under which a tool call's progress
+// reporter travels to its handler.
+type progressKey struct{}
+
+type progressReporter struct {
+	c     *conn
+	token json.RawMessage
+}
+
+// withProgress attaches a reporter to ctx when the client sent a
+// progressToken with the call.
+func withProgress(ctx context.Context, c *conn, meta *RequestMeta) context.Context {
+	if meta == nil || len(meta.ProgressToken) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, &progressReporter{c: c, token: meta.ProgressToken})
+}
+
+// ReportProgress sends notifications/progress for the tool call running
+// under ctx. It does nothing unless the client asked for progress with a
+// progressToken, so handlers may call it unconditionally. progress must
+// increase from one call to the next; total is 0 when unknown.
+func ReportProgress(ctx context.Context, progress, total float64, message string) {
+	p, ok := ctx.Value(progressKey{}).(*progressReporter)
+	if !ok || ctx.Err() != nil {
+		return
+	}
+	p.c.send(Notification{
+		JSONRPC: "2.0",
+		Method:  "notifications/progress",
+		Params: ProgressParams{
+			ProgressToken: p.token,
+			Progress:      progress,
+			Total:         total,
+			Message:       message,
+		},
+	})
+}
@@ -8,9 +8,14 @@ import "encoding/json"
 // JSON-RPC 2.0 error codes.
 const (
 	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
 	CodeMethodNotFound = -32601
 	CodeInvalidParams  = -32602
 	CodeInternalError  = -32603
+
+	// CodeResourceNotFound is MCP's error for resources/read of an
+	// unknown or missing resource.
+	CodeResourceNotFound = -32002
 )
 
 // MCP protocol version bounds for negotiation.
@@ -27,6 +32,13 @@ type Request struct {
 	Params  json.RawMessage `json:"params,omitempty"`
 }
 
+// Notification is a JSON-RPC 2.0 notification sent by the server.
+type Notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
 // Response is a JSON-RPC 2.0 response.
 type Response struct {
 	JSONRPC string          `json:"jsonrpc"`
@@ -71,8 +83,9 @@ type ServerInfo struct {
 
 // Capabilities declares what the server supports.
 type Capabilities struct {
-	Tools   *ToolsCap   `json:"tools,omitempty"`
-	Prompts *PromptsCap `json:"prompts,omitempty"`
+	Tools     *ToolsCap     `json:"tools,omitempty"`
+	Prompts   *PromptsCap   `json:"prompts,omitempty"`
+	Resources *ResourcesCap `json:"resources,omitempty"`
 }
 
 // ToolsCap declares tool support.
@@ -100,6 +113,14 @@ type ToolDef struct {
 type ToolsCallParams struct {
 	Name      string          `json:"name"`
 	Arguments json.RawMessage `json:"arguments,omitempty"`
+	Meta      *RequestMeta    `json:"_meta,omitempty"`
+}
+
+// RequestMeta is the _meta object a client may attach to a request.
+type RequestMeta struct {
+	// ProgressToken, when set, asks for notifications/progress while the
+	// request runs. It is a string or number, echoed back as sent.
+	ProgressToken json.RawMessage `json:"progressToken,omitempty"`
 }
 
 // ToolsCallResult is the response to tools/call.
@@ -159,3 +180,72 @@ type PromptMessage struct {
 	Role    string       `json:"role"`
 	Content ContentBlock `json:"content"`
 }
+
+// --- Resources ---
+
+// ResourcesCap declares resource support.
+type ResourcesCap struct {
+	Subscribe   bool `json:"subscribe"`
+	ListChanged bool `json:"listChanged"`
+}
+
+// ResourceDef describes a concrete resource in resources/list.
+type ResourceDef struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceTemplate describes a family of resources by RFC 6570 URI
+// template, in resources/templates/list.
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourcesListResult is the response to resources/list.
+type ResourcesListResult struct {
+	Resources []ResourceDef `json:"resources"`
+}
+
+// ResourceTemplatesListResult is the response to resources/templates/list.
+type ResourceTemplatesListResult struct {
+	ResourceTemplates []ResourceTemplate `json:"resourceTemplates"`
+}
+
+// ResourceURIParams is the request params for resources/read,
+// resources/subscribe, and resources/unsubscribe, and the params of
+// notifications/resources/updated.
+type ResourceURIParams struct {
+	URI string `json:"uri"`
+}
+
+// ResourcesReadResult is the response to resources/read.
+type ResourcesReadResult struct {
+	Contents []ResourceContents `json:"contents"`
+}
+
+// ResourceContents is the text of one resource.
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
+
+// CancelledParams is the params of notifications/cancelled, which asks
+// the server to abandon an in-flight request.
+type CancelledParams struct {
+	RequestID json.RawMessage `json:"requestId"`
+	Reason    string          `json:"reason,omitempty"`
+}
+
+// ProgressParams is the params of notifications/progress.
+type ProgressParams struct {
+	ProgressToken json.RawMessage `json:"progressToken"`
+	Progress      float64         `json:"progress"`
+	Total         float64         `json:"total,omitempty"`
+	Message       string          `json:"message,omitempty"`
+}
@@ -0,0 +1,287 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Resource serves one family of vv:// URIs, described to clients by its
+// URI template. Every resource is backed by one vault file, which is what
+// resources/subscribe watches.
+type Resource struct {
+	Template ResourceTemplate
+
+	// List enumerates the family's current resources for resources/list.
+	List func() ([]ResourceDef, error)
+
+	// Resolve maps a URI to its backing file. ok is false when the URI
+	// belongs to another family; err reports a URI of this family that
+	// is malformed or names nothing.
+	Resolve func(uri string) (path string, ok bool, err error)
+}
+
+// errResourceNotFound marks Resolve and read failures that map to
+// CodeResourceNotFound.
+var errResourceNotFound = errors.New("resource not found")
+
+// RegisterResource adds a resource family to the server.
+func (s *Server) RegisterResource(r Resource) {
+	s.resources = append(s.resources, r)
+}
+
+// resolveResource finds the family a URI belongs to and its backing file.
+func (s *Server) resolveResource(uri string) (Resource, string, error) {
+	for _, r := range s.resources {
+		path, ok, err := r.Resolve(uri)
+		if !ok {
+			continue
+		}
+		return r, path, err
+	}
+	return Resource{}, "", fmt.Errorf("%w: %s", errResourceNotFound, uri)
+}
+
+func (s *Server) handleResourcesList(req Request) Response {
+	defs := []ResourceDef{}
+	for _, r := range s.resources {
+		list, err := r.List()
+		if err != nil {
+			return Response{
+				JSONRPC: "2.0",
+				ID:      req.ID,
+				Error:   &RPCError{Code: CodeInternalError, Message: err.Error()},
+			}
+		}
+		defs = append(defs, list...)
+	}
+	return Response{JSONRPC: "2.0", ID: req.ID, Result: ResourcesListResult{Resources: defs}}
+}
+
+func (s *Server) handleResourceTemplatesList(req Request) Response {
+	templates := make([]ResourceTemplate, 0, len(s.resources))
+	for _, r := range s.resources {
+		templates = append(templates, r.Template)
+	}
+	return Response{JSONRPC: "2.0", ID: req.ID, Result: ResourceTemplatesListResult{ResourceTemplates: templates}}
+}
+
+func (s *Server) handleResourcesRead(req Request) Response {
+	var params ResourceURIParams
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+		return Response{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   &RPCError{Code: CodeInvalidParams, Message: "invalid params"},
+		}
+	}
+
+	s.logger.Printf("resources/read: %s", params.URI)
+
+	r, path, err := s.resolveResource(params.URI)
+	if err != nil {
+		return resourceError(req, err)
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return resourceError(req, fmt.Errorf("%w: %s", errResourceNotFound, params.URI))
+	}
+	if err != nil {
+		return Response{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   &RPCError{Code: CodeInternalError, Message: err.Error()},
+		}
+	}
+
+	return Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result: ResourcesReadResult{Contents: []ResourceContents{{
+			URI:      params.URI,
+			MimeType: r.Template.MimeType,
+			Text:     string(data),
+		}}},
+	}
+}
+
+func (s *Server) handleResourcesSubscribe(c *conn, req Request, subscribe bool) Response {
+	var params ResourceURIParams
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+		return Response{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   &RPCError{Code: CodeInvalidParams, Message: "invalid params"},
+		}
+	}
+
+	if !subscribe {
+		c.subs.remove(params.URI)
+		return Response{JSONRPC: "2.0", ID: req.ID, Result: struct{}{}}
+	}
+	_, path, err := s.resolveResource(params.URI)
+	if err != nil {
+		return resourceError(req, err)
+	}
+	if err := c.subs.add(params.URI, path); err != nil {
+		return Response{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   &RPCError{Code: CodeInternalError, Message: err.Error()},
+		}
+	}
+	s.logger.Printf("resources/subscribe: %s", params.URI)
+	return Response{JSONRPC: "2.0", ID: req.ID, Result: struct{}{}}
+}
+
+// resourceError answers a URI that names nothing with
+// CodeResourceNotFound and a malformed one with CodeInvalidParams.
+func resourceError(req Request, err error) Response {
+	code := CodeInvalidParams
+	if errors.Is(err, errResourceNotFound) {
+		code = CodeResourceNotFound
+	}
+	return Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Error:   &RPCError{Code: code, Message: err.Error()},
+	}
+}
+
+// updateDebounce coalesces the burst of events one atomic write produces
+// (temp file, rename) into one notification.
+const updateDebounce = 100 * time.Millisecond
+
+// subscriptions watches the files behind subscribed resources and calls
+// notify with a URI when its file is written, replaced, or removed.
+// Directories are watched rather than files so atomic renames are seen.
+type subscriptions struct {
+	notify func(uri string)
+
+	mu      sync.Mutex
+	watcher *fsnotify.Watcher
+	paths   map[string]string // uri → backing file
+	dirs    map[string]int    // watched directory → subscriptions in it
+	pending map[string]*time.Timer
+}
+
+func newSubscriptions(notify func(uri string)) *subscriptions {
+	return &subscriptions{
+		notify:  notify,
+		paths:   make(map[string]string),
+		dirs:    make(map[string]int),
+		pending: make(map[string]*time.Timer),
+	}
+}
+
+func (sb *subscriptions) add(uri, path string) error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	if _, ok := sb.paths[uri]; ok {
+		return nil
+	}
+	if sb.watcher == nil {
+		w, err := fsnotify.NewWatcher()
+		if err != nil {
+			return fmt.Errorf("watch: %w", err)
+		}
+		sb.watcher = w
+		go sb.run(w)
+	}
+	path = filepath.Clean(path)
+	dir := filepath.Dir(path)
+	if sb.dirs[dir] == 0 {
+		if err := sb.watcher.Add(dir); err != nil {
+			return fmt.Errorf("watch %s: %w", dir, err)
+		}
+	}
+	sb.dirs[dir]++
+	sb.paths[uri] = path
+	return nil
+}
+
+func (sb *subscriptions) remove(uri string) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	path, ok := sb.paths[uri]
+	if !ok {
+		return
+	}
+	delete(sb.paths, uri)
+	if t := sb.pending[uri]; t != nil {
+		t.Stop()
+		delete(sb.pending, uri)
+	}
+	dir := filepath.Dir(path)
+	if sb.dirs[dir]--; sb.dirs[dir] == 0 {
+		delete(sb.dirs, dir)
+		sb.watcher.Remove(dir)
+	}
+}
+
+// close stops watching; the conn calls it when the client goes away.
+func (sb *subscriptions) close() {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	if sb.watcher != nil {
+		sb.watcher.Close()
+		sb.watcher = nil
+	}
+	for _, t := range sb.pending {
+		t.Stop()
+	}
+	clear(sb.paths)
+	clear(sb.dirs)
+	clear(sb.pending)
+}
+
+func (sb *subscriptions) run(w *fsnotify.Watcher) {
+	for {
+		select {
+		case event, ok := <-w.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			sb.changed(filepath.Clean(event.Name))
+		case _, ok := <-w.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+func (sb *subscriptions) changed(path string) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	for uri, p := range sb.paths {
+		if p != path {
+			continue
+		}
+		if t := sb.pending[uri]; t != nil {
+			t.Reset(updateDebounce)
+			continue
+		}
+		sb.pending[uri] = time.AfterFunc(updateDebounce, func() {
+			sb.mu.Lock()
+			_, subscribed := sb.paths[uri]
+			delete(sb.pending, uri)
+			sb.mu.Unlock()
+			if subscribed {
+				sb.notify(uri)
+			}
+		})
+	}
+}
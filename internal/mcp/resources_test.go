@@ -0,0 +1,236 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/index"
+)
+
+// resourceVault builds a vault with one of each resource kind and
+// returns a server with the vault resources registered.
+func resourceVault(t *testing.T) (*Server, config.Config) {
+	t.Helper()
+	cfg := config.Config{VaultPath: t.TempDir()}
+	files := map[string]string{
+		"Projects/acme/agentctx/resume.md":               "# Resume\n",
+		"Projects/acme/knowledge.md":                     "# Knowledge\n",
+		"Projects/acme/agentctx/tasks/fix-login.md":      "# Fix login\n",
+		"Projects/acme/agentctx/tasks/done/old-thing.md": "# Old thing\n",
+		"Projects/acme/sessions/2026-10-01-01.md":        "# Session one\n",
+		"Knowledge/learnings/go-errors.md":               "---\nname: Go errors\ndescription: wrap with %w\ntype: user\n---\n\nbody\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(cfg.VaultPath, rel)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	idx, _ := index.Load(cfg.StateDir())
+	idx.Entries["sess-1"] = index.SessionEntry{
+		SessionID: "sess-1",
+		NotePath:  "Projects/acme/sessions/2026-10-01-01.md",
+		Project:   "acme",
+		Date:      "2026-10-01",
+		Iteration: 1,
+		Title:     "First session",
+	}
+	if err := idx.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := NewServer(ServerInfo{Name: "test-server", Version: "0.1.0"}, log.New(io.Discard, "", 0))
+	RegisterVaultResources(srv, cfg)
+	return srv, cfg
+}
+
+func TestResources_InitializeAdvertisesCapability(t *testing.T) {
+	srv, _ := resourceVault(t)
+	resps := sendAndReceive(t, srv, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	data, _ := json.Marshal(resps[0].Result)
+	if !strings.Contains(string(data), `"subscribe":true`) {
+		t.Errorf("capabilities = %s", data)
+	}
+
+	// A server without resources does not advertise them.
+	resps = sendAndReceive(t, testServer(), `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	data, _ = json.Marshal(resps[0].Result)
+	if strings.Contains(string(data), `"resources"`) {
+		t.Errorf("capabilities without resources = %s", data)
+	}
+}
+
+func TestResources_ListAndTemplates(t *testing.T) {
+	srv, _ := resourceVault(t)
+	resps := sendAndReceive(t, srv,
+		`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"resources/templates/list"}`,
+	)
+
+	var list ResourcesListResult
+	data, _ := json.Marshal(resps[0].Result)
+	json.Unmarshal(data, &list)
+	got := map[string]string{}
+	for _, r := range list.Resources {
+		got[r.URI] = r.MimeType
+	}
+	for _, uri := range []string{
+		"vv://project/acme/resume",
+		"vv://project/acme/knowledge",
+		"vv://project/acme/tasks/fix-login",
+		"vv://session/sess-1",
+		"vv://learning/go-errors",
+	} {
+		if got[uri] != "text/markdown" {
+			t.Errorf("resources/list missing %s (got %v)", uri, got)
+		}
+	}
+	if _, ok := got["vv://project/acme/tasks/old-thing"]; ok {
+		t.Error("completed task listed")
+	}
+
+	var templates ResourceTemplatesListResult
+	data, _ = json.Marshal(resps[1].Result)
+	json.Unmarshal(data, &templates)
+	if len(templates.ResourceTemplates) != 5 {
+		t.Fatalf("templates = %+v", templates.ResourceTemplates)
+	}
+	if templates.ResourceTemplates[2].URITemplate != "vv://project/{project}/tasks/{slug}" {
+		t.Errorf("task template = %q", templates.ResourceTemplates[2].URITemplate)
+	}
+}
+
+func TestResources_Read(t *testing.T) {
+	srv, _ := resourceVault(t)
+	tests := []struct {
+		uri      string
+		wantText string
+		wantCode int
+	}{
+		{uri: "vv://project/acme/resume", wantText: "# Resume\n"},
+		{uri: "vv://project/acme/knowledge", wantText: "# Knowledge\n"},
+		{uri: "vv://project/acme/tasks/fix-login", wantText: "# Fix login\n"},
+		{uri: "vv://project/acme/tasks/old-thing", wantText: "# Old thing\n"},
+		{uri: "vv://session/sess-1", wantText: "# Session one\n"},
+		{uri: "vv://learning/go-errors", wantText: "name: Go errors"},
+		{uri: "vv://project/ghost/resume", wantCode: CodeResourceNotFound},
+		{uri: "vv://session/nope", wantCode: CodeResourceNotFound},
+		{uri: "vv://unknown/thing", wantCode: CodeResourceNotFound},
+		{uri: "vv://project/../resume", wantCode: CodeInvalidParams},
+		{uri: "vv://learning/..", wantCode: CodeInvalidParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			params, _ := json.Marshal(ResourceURIParams{URI: tt.uri})
+			resps := sendAndReceive(t, srv, `{"jsonrpc":"2.0","id":1,"method":"resources/read","params":`+string(params)+`}`)
+			if tt.wantCode != 0 {
+				if resps[0].Error == nil || resps[0].Error.Code != tt.wantCode {
+					t.Fatalf("error = %+v, want code %d", resps[0].Error, tt.wantCode)
+				}
+				return
+			}
+			if resps[0].Error != nil {
+				t.Fatalf("error: %s", resps[0].Error.Message)
+			}
+			var result ResourcesReadResult
+			data, _ := json.Marshal(resps[0].Result)
+			json.Unmarshal(data, &result)
+			if len(result.Contents) != 1 {
+				t.Fatalf("contents = %+v", result.Contents)
+			}
+			c := result.Contents[0]
+			if c.URI != tt.uri || c.MimeType != "text/markdown" || !strings.Contains(c.Text, tt.wantText) {
+				t.Errorf("contents = %+v", c)
+			}
+		})
+	}
+}
+
+// lockedBuffer is an io.Writer safe to read while Serve writes to it.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf strings.Builder
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestResources_SubscribeNotifiesOnChange(t *testing.T) {
+	srv, cfg := resourceVault(t)
+	inR, inW := io.Pipe()
+	var out lockedBuffer
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(context.Background(), inR, &out) }()
+	defer func() {
+		inW.Close()
+		<-done
+	}()
+
+	waitForLine := func(what, substr string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			scanner := bufio.NewScanner(strings.NewReader(out.String()))
+			for scanner.Scan() {
+				if strings.Contains(scanner.Text(), substr) {
+					return
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("timed out waiting for %s; output:\n%s", what, out.String())
+	}
+
+	io.WriteString(inW, `{"jsonrpc":"2.0","id":7,"method":"resources/subscribe","params":{"uri":"vv://project/acme/resume"}}`+"\n")
+	waitForLine("subscribe response", `"id":7`)
+
+	// Replace the file the way atomicfile does: write a sibling, rename.
+	resume := filepath.Join(cfg.ProjectsDir(), "acme", "agentctx", "resume.md")
+	tmp := resume + ".tmp"
+	os.WriteFile(tmp, []byte("# Resume v2\n"), 0o644)
+	if err := os.Rename(tmp, resume); err != nil {
+		t.Fatal(err)
+	}
+	waitForLine("update notification", `"method":"notifications/resources/updated","params":{"uri":"vv://project/acme/resume"}`)
+
+	// Once unsubscribed, further writes are not reported.
+	io.WriteString(inW, `{"jsonrpc":"2.0","id":8,"method":"resources/unsubscribe","params":{"uri":"vv://project/acme/resume"}}`+"\n")
+	waitForLine("unsubscribe response", `"id":8`)
+	before := strings.Count(out.String(), "notifications/resources/updated")
+	os.WriteFile(resume, []byte("# Resume v3\n"), 0o644)
+	time.Sleep(3 * updateDebounce)
+	if after := strings.Count(out.String(), "notifications/resources/updated"); after != before {
+		t.Errorf("notified after unsubscribe: %d → %d", before, after)
+	}
+}
+
+func TestResources_SubscribeUnknownURI(t *testing.T) {
+	srv, _ := resourceVault(t)
+	resps := sendAndReceive(t, srv, `{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"vv://session/nope"}}`)
+	if resps[0].Error == nil || resps[0].Error.Code != CodeResourceNotFound {
+		t.Errorf("error = %+v", resps[0].Error)
+	}
+}
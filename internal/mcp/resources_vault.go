@@ -0,0 +1,285 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package mcp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/index"
+	"github.com/suykerbuyk/vibe-vault/internal/knowledge"
+)
+
+const markdownMIME = "text/markdown"
+
+// sessionResourceLimit caps vv://session/ entries in resources/list to
+// the most recent sessions; older ones stay readable through the
+// template.
+const sessionResourceLimit = 50
+
+// RegisterVaultResources registers the vv:// resource families:
+//
+//	vv://project/{project}/resume        agentctx/resume.md
+//	vv://project/{project}/knowledge     knowledge.md
+//	vv://project/{project}/tasks/{slug}  agentctx/tasks/{slug}.md (or done/, cancelled/)
+//	vv://session/{session_id}            the session note
+//	vv://learning/{slug}                 Knowledge/learnings/{slug}.md
+func RegisterVaultResources(srv *Server, cfg config.Config) {
+	srv.RegisterResource(NewProjectFileResource(cfg, "resume", "Project resume",
+		"Current state, open threads, and active tasks for a project (agentctx/resume.md).",
+		filepath.Join("agentctx", "resume.md")))
+	srv.RegisterResource(NewProjectFileResource(cfg, "knowledge", "Project knowledge",
+		"Accumulated decisions, patterns, and learnings for a project (knowledge.md).",
+		"knowledge.md"))
+	srv.RegisterResource(NewTaskResource(cfg))
+	srv.RegisterResource(NewSessionResource(cfg))
+	srv.RegisterResource(NewLearningResource(cfg))
+}
+
+// validateSlug rejects a task or learning slug that would leave its
+// directory.
+func validateSlug(slug string) error {
+	if slug == "" || strings.ContainsAny(slug, "/\\") || strings.Contains(slug, "..") {
+		return fmt.Errorf("invalid slug: %q", slug)
+	}
+	return nil
+}
+
+// vaultProjects lists the project directories under the vault.
+func vaultProjects(cfg config.Config) ([]string, error) {
+	entries, err := os.ReadDir(cfg.ProjectsDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var projects []string
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			projects = append(projects, e.Name())
+		}
+	}
+	return projects, nil
+}
+
+// NewProjectFileResource serves vv://project/{project}/{kind}, backed by
+// rel under the project's vault directory.
+func NewProjectFileResource(cfg config.Config, kind, name, desc, rel string) Resource {
+	suffix := "/" + kind
+	pathFor := func(project string) string {
+		return filepath.Join(cfg.ProjectsDir(), project, rel)
+	}
+	return Resource{
+		Template: ResourceTemplate{
+			URITemplate: "vv://project/{project}" + suffix,
+			Name:        name,
+			Description: desc,
+			MimeType:    markdownMIME,
+		},
+		List: func() ([]ResourceDef, error) {
+			projects, err := vaultProjects(cfg)
+			if err != nil {
+				return nil, err
+			}
+			var defs []ResourceDef
+			for _, p := range projects {
+				if _, err := os.Stat(pathFor(p)); err != nil {
+					continue
+				}
+				defs = append(defs, ResourceDef{
+					URI:      "vv://project/" + p + suffix,
+					Name:     p + " " + kind,
+					MimeType: markdownMIME,
+				})
+			}
+			return defs, nil
+		},
+		Resolve: func(uri string) (string, bool, error) {
+			rest, ok := strings.CutPrefix(uri, "vv://project/")
+			if !ok {
+				return "", false, nil
+			}
+			project, ok := strings.CutSuffix(rest, suffix)
+			if !ok || strings.Contains(project, "/") {
+				return "", false, nil
+			}
+			if err := validateProjectName(project); err != nil {
+				return "", true, err
+			}
+			path, err := vaultPrefixCheck(pathFor(project), cfg.VaultPath)
+			return path, true, err
+		},
+	}
+}
+
+// NewTaskResource serves vv://project/{project}/tasks/{slug}. Resolution
+// follows vv_get_task: active tasks first, then done/ and cancelled/.
+// Only active tasks are listed.
+func NewTaskResource(cfg config.Config) Resource {
+	tasksDir := func(project string) string {
+		return filepath.Join(cfg.ProjectsDir(), project, "agentctx", "tasks")
+	}
+	return Resource{
+		Template: ResourceTemplate{
+			URITemplate: "vv://project/{project}/tasks/{slug}",
+			Name:        "Project task",
+			Description: "A task file from a project's agentctx/tasks/ (active, done, or cancelled).",
+			MimeType:    markdownMIME,
+		},
+		List: func() ([]ResourceDef, error) {
+			projects, err := vaultProjects(cfg)
+			if err != nil {
+				return nil, err
+			}
+			var defs []ResourceDef
+			for _, p := range projects {
+				files, _ := filepath.Glob(filepath.Join(tasksDir(p), "*.md"))
+				for _, f := range files {
+					slug := strings.TrimSuffix(filepath.Base(f), ".md")
+					defs = append(defs, ResourceDef{
+						URI:      "vv://project/" + p + "/tasks/" + slug,
+						Name:     p + " task " + slug,
+						MimeType: markdownMIME,
+					})
+				}
+			}
+			return defs, nil
+		},
+		Resolve: func(uri string) (string, bool, error) {
+			rest, ok := strings.CutPrefix(uri, "vv://project/")
+			if !ok {
+				return "", false, nil
+			}
+			project, slug, ok := strings.Cut(rest, "/tasks/")
+			if !ok {
+				return "", false, nil
+			}
+			if err := validateProjectName(project); err != nil {
+				return "", true, err
+			}
+			if err := validateSlug(slug); err != nil {
+				return "", true, err
+			}
+			dir := tasksDir(project)
+			path := filepath.Join(dir, slug+".md")
+			for _, candidate := range []string{
+				path,
+				filepath.Join(dir, "done", slug+".md"),
+				filepath.Join(dir, "cancelled", slug+".md"),
+			} {
+				if _, err := os.Stat(candidate); err == nil {
+					path = candidate
+					break
+				}
+			}
+			path, err := vaultPrefixCheck(path, cfg.VaultPath)
+			return path, true, err
+		},
+	}
+}
+
+// NewSessionResource serves vv://session/{session_id}: the session note
+// the index records for that session. Encrypted notes are served as
+// their stub, as vv_get_session_detail does.
+func NewSessionResource(cfg config.Config) Resource {
+	return Resource{
+		Template: ResourceTemplate{
+			URITemplate: "vv://session/{session_id}",
+			Name:        "Session note",
+			Description: "The vault note for a captured session, by session ID.",
+			MimeType:    markdownMIME,
+		},
+		List: func() ([]ResourceDef, error) {
+			idx, err := index.Load(cfg.StateDir())
+			if err != nil {
+				return nil, fmt.Errorf("load index: %w", err)
+			}
+			entries := make([]index.SessionEntry, 0, len(idx.Entries))
+			for _, e := range idx.Entries {
+				entries = append(entries, e)
+			}
+			sort.Slice(entries, func(i, j int) bool {
+				if entries[i].Date != entries[j].Date {
+					return entries[i].Date > entries[j].Date
+				}
+				return entries[i].NotePath > entries[j].NotePath
+			})
+			if len(entries) > sessionResourceLimit {
+				entries = entries[:sessionResourceLimit]
+			}
+			defs := make([]ResourceDef, 0, len(entries))
+			for _, e := range entries {
+				defs = append(defs, ResourceDef{
+					URI:         "vv://session/" + e.SessionID,
+					Name:        fmt.Sprintf("%s %s #%d", e.Project, e.Date, e.Iteration),
+					Description: e.Title,
+					MimeType:    markdownMIME,
+				})
+			}
+			return defs, nil
+		},
+		Resolve: func(uri string) (string, bool, error) {
+			id, ok := strings.CutPrefix(uri, "vv://session/")
+			if !ok {
+				return "", false, nil
+			}
+			idx, err := index.Load(cfg.StateDir())
+			if err != nil {
+				return "", true, fmt.Errorf("load index: %w", err)
+			}
+			e, found := idx.Entries[id]
+			if !found || e.NotePath == "" {
+				return "", true, fmt.Errorf("%w: no session %q in the index", errResourceNotFound, id)
+			}
+			path, err := vaultPrefixCheck(filepath.Join(cfg.VaultPath, e.NotePath), cfg.VaultPath)
+			return path, true, err
+		},
+	}
+}
+
+// NewLearningResource serves vv://learning/{slug}: a cross-project
+// learning from Knowledge/learnings/, frontmatter included.
+func NewLearningResource(cfg config.Config) Resource {
+	dir := filepath.Join(cfg.VaultPath, "Knowledge", "learnings")
+	return Resource{
+		Template: ResourceTemplate{
+			URITemplate: "vv://learning/{slug}",
+			Name:        "Learning",
+			Description: "A cross-project learning from Knowledge/learnings/.",
+			MimeType:    markdownMIME,
+		},
+		List: func() ([]ResourceDef, error) {
+			learnings, err := knowledge.List(cfg.VaultPath, "")
+			if err != nil {
+				return nil, err
+			}
+			defs := make([]ResourceDef, 0, len(learnings))
+			for _, l := range learnings {
+				defs = append(defs, ResourceDef{
+					URI:         "vv://learning/" + l.Slug,
+					Name:        l.Name,
+					Description: l.Description,
+					MimeType:    markdownMIME,
+				})
+			}
+			return defs, nil
+		},
+		Resolve: func(uri string) (string, bool, error) {
+			slug, ok := strings.CutPrefix(uri, "vv://learning/")
+			if !ok {
+				return "", false, nil
+			}
+			if err := validateSlug(slug); err != nil {
+				return "", true, err
+			}
+			path, err := vaultPrefixCheck(filepath.Join(dir, slug+".md"), cfg.VaultPath)
+			return path, true, err
+		},
+	}
+}
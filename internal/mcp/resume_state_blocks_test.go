@@ -106,7 +106,7 @@ func TestAutoHealAppendIteration_RegeneratesStateBlocks(t *testing.T) {
 		"narrative": "body",
 		"date":      "2026-04-26",
 	})
-	if _, err := tool.Handler(args); err != nil {
+	if _, err := tool.Handler(context.Background(), args); err != nil {
 		t.Fatalf("AppendIteration: %v", err)
 	}
 
@@ -152,7 +152,7 @@ func TestAutoHealUpdateResume_RegeneratesStateBlocks(t *testing.T) {
 		"section": "Current State",
 		"content": "<!-- vv:current-state:start -->\nold current state\n<!-- vv:current-state:end -->\n",
 	})
-	if _, err := tool.Handler(args); err != nil {
+	if _, err := tool.Handler(context.Background(), args); err != nil {
 		t.Fatalf("UpdateResume: %v", err)
 	}
 
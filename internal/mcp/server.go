@@ -12,14 +12,22 @@ import (
 	"log"
 	"regexp"
 	"sort"
+	"sync"
 
 	"github.com/suykerbuyk/vibe-vault/internal/config"
 )
 
-// Tool pairs a definition with its handler.
+// Tool pairs a definition with its handler. ctx is cancelled when the
+// client cancels the call or goes away; long-running handlers should
+// honor it and may report progress with ReportProgress.
+//
+// Exclusive tools write the vault with a read-modify-write; the server
+// runs at most one of them at a time so overlapping calls cannot lose
+// each other's updates.
 type Tool struct {
 	Definition ToolDef
-	Handler    func(params json.RawMessage) (string, error)
+	Handler    func(ctx context.Context, params json.RawMessage) (string, error)
+	Exclusive  bool
 }
 
 // Prompt pairs a definition with its handler.
@@ -28,23 +36,131 @@ type Prompt struct {
 	Handler    func(args map[string]string) (PromptsGetResult, error)
 }
 
-// Server is a JSON-RPC 2.0 stdio MCP server.
+// maxConcurrentCalls bounds the tool calls running at once across every
+// connection. Other methods are cheap and answered inline.
+const maxConcurrentCalls = 4
+
+// maxCallsPerSession bounds one HTTP session's running tool calls, so a
+// single client cannot hold every server slot and starve the others.
+const maxCallsPerSession = 2
+
+// Server is a JSON-RPC 2.0 MCP server. Serve speaks it over stdio;
+// NewHTTPHandler serves it to many clients over Streamable HTTP.
 type Server struct {
 	tools        map[string]Tool
 	prompts      map[string]Prompt
+	resources    []Resource
 	info         ServerInfo
 	logger       *log.Logger
 	instructions string
 	debug        bool
+
+	// slots holds one token per running tool call.
+	slots chan struct{}
+	// exclusive is held while an Exclusive tool runs.
+	exclusive chan struct{}
+}
+
+// conn is one client's connection state: where server-initiated
+// messages go, which resources it subscribes to, and which of its tool
+// calls are in flight. Serve has one; the HTTP transport has one per
+// Mcp-Session-Id.
+type conn struct {
+	send  func(msg any)
+	subs  *subscriptions
+	slots chan struct{} // one token per running tool call on this conn
+
+	mu       sync.Mutex
+	inFlight map[string]context.CancelFunc // request ID → cancel
+}
+
+// newConn returns a connection running at most maxCalls tool calls at
+// once.
+func newConn(send func(msg any), maxCalls int) *conn {
+	return &conn{
+		send:  send,
+		slots: make(chan struct{}, maxCalls),
+		subs: newSubscriptions(func(uri string) {
+			send(Notification{
+				JSONRPC: "2.0",
+				Method:  "notifications/resources/updated",
+				Params:  ResourceURIParams{URI: uri},
+			})
+		}),
+		inFlight: make(map[string]context.CancelFunc),
+	}
+}
+
+// close drops the connection's subscriptions and cancels its in-flight
+// tool calls.
+func (c *conn) close() {
+	c.subs.close()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, cancel := range c.inFlight {
+		cancel()
+	}
+}
+
+// busy reports whether any tool call is in flight.
+func (c *conn) busy() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.inFlight) > 0
+}
+
+// begin registers a tool call so notifications/cancelled can reach it.
+// The returned func cancels the call's context and unregisters it. ok is
+// false when a call with the same ID is still in flight: its cancel
+// func would be lost, so the new call must be refused.
+func (c *conn) begin(ctx context.Context, id json.RawMessage) (_ context.Context, done func(), ok bool) {
+	key := string(id)
+	c.mu.Lock()
+	if _, dup := c.inFlight[key]; dup {
+		c.mu.Unlock()
+		return nil, nil, false
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	c.inFlight[key] = cancel
+	c.mu.Unlock()
+	return ctx, func() {
+		cancel()
+		c.mu.Lock()
+		delete(c.inFlight, key)
+		c.mu.Unlock()
+	}, true
+}
+
+// duplicateIDResponse refuses a tools/call whose ID is already in flight
+// on the same connection.
+func duplicateIDResponse(id json.RawMessage) Response {
+	return Response{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   &RPCError{Code: CodeInvalidRequest, Message: fmt.Sprintf("request id %s is already in flight", id)},
+	}
+}
+
+// cancel abandons the in-flight call with the given request ID, if any.
+func (c *conn) cancel(id json.RawMessage) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cancel, ok := c.inFlight[string(id)]
+	if ok {
+		cancel()
+	}
+	return ok
 }
 
 // NewServer creates a new MCP server.
 func NewServer(info ServerInfo, logger *log.Logger) *Server {
 	return &Server{
-		tools:   make(map[string]Tool),
-		prompts: make(map[string]Prompt),
-		info:    info,
-		logger:  logger,
+		tools:     make(map[string]Tool),
+		prompts:   make(map[string]Prompt),
+		info:      info,
+		logger:    logger,
+		slots:     make(chan struct{}, maxConcurrentCalls),
+		exclusive: make(chan struct{}, 1),
 	}
 }
 
@@ -53,7 +169,8 @@ func (s *Server) RegisterTool(t Tool) {
 	s.tools[t.Definition.Name] = t
 }
 
-// RegisterAllTools registers every production tool + prompt on srv. This is
+// RegisterAllTools registers every production tool, prompt, and resource
+// family on srv. This is
 // the single source of truth for the MCP-tool inventory: cmd/vv/main.go calls
 // it from `vv mcp` server startup, and the resume.md state-region renderer
 // calls it on a throw-away Server to count tools for the `current-state`
@@ -68,6 +185,7 @@ func RegisterAllTools(srv *Server, cfg config.Config) {
 	srv.RegisterTool(NewSearchSessionsTool(cfg))
 	srv.RegisterTool(NewGetKnowledgeTool(cfg))
 	srv.RegisterTool(NewGetSessionDetailTool(cfg))
+	srv.RegisterTool(NewGetFileHistoryTool(cfg))
 	srv.RegisterTool(NewGetFrictionTrendsTool(cfg))
 	srv.RegisterTool(NewGetEffectivenessTool(cfg))
 	srv.RegisterTool(NewCaptureSessionTool(cfg))
@@ -82,6 +200,7 @@ func RegisterAllTools(srv *Server, cfg config.Config) {
 	srv.RegisterTool(NewBootstrapContextTool(cfg))
 	srv.RegisterTool(NewListLearningsTool(cfg))
 	srv.RegisterTool(NewGetLearningTool(cfg))
+	srv.RegisterTool(NewADRTool(cfg))
 	srv.RegisterTool(NewGetIterationsTool(cfg))
 	srv.RegisterTool(NewGetProjectRootTool(cfg))
 	srv.RegisterTool(NewSetCommitMsgTool(cfg))
@@ -109,6 +228,7 @@ func RegisterAllTools(srv *Server, cfg config.Config) {
 	srv.RegisterPrompt(NewSessionGuidelinesPrompt())
 	srv.RegisterPrompt(NewRestartPrompt())
 	srv.RegisterPrompt(NewWrapPrompt())
+	RegisterVaultResources(srv, cfg)
 }
 
 // ToolNames returns the registered tool names in stable alphabetical order.
@@ -153,8 +273,28 @@ func (s *Server) SetDebug(on bool) {
 }
 
 // Serve reads JSON-RPC requests from in and writes responses to out.
-// It returns on EOF or context cancellation.
+// Tool calls run concurrently, so their responses may arrive out of
+// order; everything else is answered in order. It returns on EOF, once
+// running calls finish, or on context cancellation, which cancels them.
 func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	// outMu serializes responses with resource-update notifications,
+	// which the subscription watcher sends from its own goroutine.
+	var outMu sync.Mutex
+	c := newConn(func(msg any) {
+		data, err := s.marshalMessage(msg)
+		if err != nil {
+			return
+		}
+		outMu.Lock()
+		defer outMu.Unlock()
+		if _, err := out.Write(append(data, '\n')); err != nil {
+			s.logger.Printf("write response: %v", err)
+		}
+	}, maxConcurrentCalls)
+	defer c.close()
+	var calls sync.WaitGroup
+	defer calls.Wait()
+
 	scanner := bufio.NewScanner(in)
 	scanner.Buffer(make([]byte, 0, 10*1024*1024), 10*1024*1024) // 10MB max line
 
@@ -181,11 +321,30 @@ func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
 				ID:      nil,
 				Error:   &RPCError{Code: CodeParseError, Message: "parse error"},
 			}
-			s.writeResponse(out, resp)
+			c.send(resp)
+			continue
+		}
+
+		if req.Method == "tools/call" && req.ID != nil {
+			// Register before the next line is read so a cancellation
+			// that follows immediately finds the call.
+			callCtx, done, ok := c.begin(ctx, req.ID)
+			if !ok {
+				c.send(duplicateIDResponse(req.ID))
+				continue
+			}
+			calls.Add(1)
+			go func() {
+				defer calls.Done()
+				defer done()
+				if resp, ok := s.call(callCtx, c, req); ok {
+					c.send(resp)
+				}
+			}()
 			continue
 		}
 
-		resp := s.dispatch(req)
+		resp := s.dispatch(ctx, c, req)
 
 		// Notifications (nil ID) get no response.
 		if req.ID == nil {
@@ -195,7 +354,7 @@ func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
 			continue
 		}
 
-		s.writeResponse(out, resp)
+		c.send(resp)
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -204,20 +363,37 @@ func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
 	return nil
 }
 
-func (s *Server) dispatch(req Request) Response {
+// dispatch answers one request. Transports route tools/call through
+// call instead, so it runs under a worker slot and can be cancelled;
+// dispatch handles it inline only for a tools/call sent as a
+// notification, which gets no response either way.
+func (s *Server) dispatch(ctx context.Context, c *conn, req Request) Response {
 	switch req.Method {
 	case "initialize":
 		return s.handleInitialize(req)
 	case "notifications/initialized":
 		return Response{} // no-op, no response sent
+	case "notifications/cancelled":
+		s.handleCancelled(c, req)
+		return Response{}
 	case "tools/list":
 		return s.handleToolsList(req)
 	case "tools/call":
-		return s.handleToolsCall(req)
+		return s.handleToolsCall(ctx, c, req)
 	case "prompts/list":
 		return s.handlePromptsList(req)
 	case "prompts/get":
 		return s.handlePromptsGet(req)
+	case "resources/list":
+		return s.handleResourcesList(req)
+	case "resources/templates/list":
+		return s.handleResourceTemplatesList(req)
+	case "resources/read":
+		return s.handleResourcesRead(req)
+	case "resources/subscribe":
+		return s.handleResourcesSubscribe(c, req, true)
+	case "resources/unsubscribe":
+		return s.handleResourcesSubscribe(c, req, false)
 	default:
 		return Response{
 			JSONRPC: "2.0",
@@ -263,6 +439,9 @@ func (s *Server) handleInitialize(req Request) Response {
 	if len(s.prompts) > 0 {
 		caps.Prompts = &PromptsCap{}
 	}
+	if len(s.resources) > 0 {
+		caps.Resources = &ResourcesCap{Subscribe: true}
+	}
 	result := InitializeResult{
 		ProtocolVersion: negotiated,
 		ServerInfo:      s.info,
@@ -283,7 +462,44 @@ func (s *Server) handleToolsList(req Request) Response {
 	return Response{JSONRPC: "2.0", ID: req.ID, Result: ToolsListResult{Tools: defs}}
 }
 
-func (s *Server) handleToolsCall(req Request) Response {
+// call runs a tools/call once both a connection slot and a server slot
+// free up. ok is false when ctx was cancelled first: the client has
+// abandoned the request and expects no response.
+func (s *Server) call(ctx context.Context, c *conn, req Request) (resp Response, ok bool) {
+	select {
+	case c.slots <- struct{}{}:
+	case <-ctx.Done():
+		return Response{}, false
+	}
+	defer func() { <-c.slots }()
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		return Response{}, false
+	}
+	defer func() { <-s.slots }()
+
+	resp = s.handleToolsCall(ctx, c, req)
+	if ctx.Err() != nil {
+		s.logger.Printf("tools/call %s: cancelled", req.ID)
+		return Response{}, false
+	}
+	return resp, true
+}
+
+// handleCancelled honors notifications/cancelled. Unknown or finished
+// requests are ignored, as the spec allows for the race.
+func (s *Server) handleCancelled(c *conn, req Request) {
+	var params CancelledParams
+	if err := json.Unmarshal(req.Params, &params); err != nil || len(params.RequestID) == 0 {
+		return
+	}
+	if c.cancel(params.RequestID) {
+		s.logger.Printf("cancelled request %s: %s", params.RequestID, params.Reason)
+	}
+}
+
+func (s *Server) handleToolsCall(ctx context.Context, c *conn, req Request) Response {
 	var params ToolsCallParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
 		return Response{
@@ -307,7 +523,17 @@ func (s *Server) handleToolsCall(req Request) Response {
 
 	s.logger.Printf("tools/call: %s", params.Name)
 
-	text, err := tool.Handler(params.Arguments)
+	if tool.Exclusive {
+		select {
+		case s.exclusive <- struct{}{}:
+		case <-ctx.Done():
+			// call drops the response once ctx is done.
+			return Response{JSONRPC: "2.0", ID: req.ID}
+		}
+		defer func() { <-s.exclusive }()
+	}
+
+	text, err := tool.Handler(withProgress(ctx, c, params.Meta), params.Arguments)
 	if err != nil {
 		return Response{
 			JSONRPC: "2.0",
@@ -370,17 +596,16 @@ func (s *Server) handlePromptsGet(req Request) Response {
 	return Response{JSONRPC: "2.0", ID: req.ID, Result: result}
 }
 
-func (s *Server) writeResponse(out io.Writer, resp Response) {
-	data, err := json.Marshal(resp)
+// marshalMessage encodes one outgoing JSON-RPC message (a Response or
+// Notification), logging it in debug mode.
+func (s *Server) marshalMessage(msg any) ([]byte, error) {
+	data, err := json.Marshal(msg)
 	if err != nil {
 		s.logger.Printf("marshal response: %v", err)
-		return
+		return nil, err
 	}
 	if s.debug {
 		s.logger.Printf("[MCP] -> %s", data)
 	}
-	data = append(data, '\n')
-	if _, err := out.Write(data); err != nil {
-		s.logger.Printf("write response: %v", err)
-	}
+	return data, nil
 }
@@ -6,10 +6,13 @@ package mcp
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"io"
 	"log"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"context"
 )
@@ -23,7 +26,7 @@ func testServer() *Server {
 			Description: "echoes input",
 			InputSchema: json.RawMessage(`{"type":"object","properties":{"msg":{"type":"string"}}}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Msg string `json:"msg"`
 			}
@@ -422,7 +425,7 @@ func TestInitializeLogsClientInfo(t *testing.T) {
 			Description: "echoes input",
 			InputSchema: json.RawMessage(`{"type":"object","properties":{}}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) { return "ok", nil },
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) { return "ok", nil },
 	})
 
 	responses := sendAndReceive(t, srv,
@@ -681,7 +684,7 @@ func TestDebugLogsRequests(t *testing.T) {
 			Description: "echoes",
 			InputSchema: json.RawMessage(`{"type":"object","properties":{}}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) { return "ok", nil },
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) { return "ok", nil },
 	})
 	srv.SetDebug(true)
 
@@ -704,7 +707,7 @@ func TestDebugLogsResponses(t *testing.T) {
 			Description: "echoes",
 			InputSchema: json.RawMessage(`{"type":"object","properties":{}}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) { return "ok", nil },
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) { return "ok", nil },
 	})
 	srv.SetDebug(true)
 
@@ -729,7 +732,7 @@ func TestToolNamesSorted(t *testing.T) {
 				Description: "",
 				InputSchema: json.RawMessage(`{"type":"object","properties":{}}`),
 			},
-			Handler: func(params json.RawMessage) (string, error) { return "", nil },
+			Handler: func(_ context.Context, params json.RawMessage) (string, error) { return "", nil },
 		})
 	}
 
@@ -755,7 +758,7 @@ func TestDebugOffNoExtraLogs(t *testing.T) {
 			Description: "echoes",
 			InputSchema: json.RawMessage(`{"type":"object","properties":{}}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) { return "ok", nil },
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) { return "ok", nil },
 	})
 	// debug is off by default
 
@@ -767,3 +770,190 @@ func TestDebugOffNoExtraLogs(t *testing.T) {
 		t.Errorf("expected no [MCP] prefixes with debug off, got: %s", logBuf.String())
 	}
 }
+
+// waitTool is a tool that reports progress, then blocks until release
+// is closed or its call is cancelled.
+type waitTool struct {
+	release chan struct{}
+	started chan struct{}
+	ended   chan error // nil when released, ctx.Err() when cancelled
+}
+
+func newWaitTool(srv *Server) *waitTool {
+	w := &waitTool{
+		release: make(chan struct{}),
+		started: make(chan struct{}, 16),
+		ended:   make(chan error, 16),
+	}
+	srv.RegisterTool(Tool{
+		Definition: ToolDef{Name: "wait", InputSchema: json.RawMessage(`{"type":"object"}`)},
+		Handler: func(ctx context.Context, _ json.RawMessage) (string, error) {
+			w.started <- struct{}{}
+			ReportProgress(ctx, 1, 2, "waiting")
+			select {
+			case <-w.release:
+				w.ended <- nil
+				return "released", nil
+			case <-ctx.Done():
+				w.ended <- ctx.Err()
+				return "", ctx.Err()
+			}
+		},
+	})
+	return w
+}
+
+// servePipe runs Serve on a pipe. send writes one request line; stop
+// closes the input and waits for Serve, and so for every call, to end.
+func servePipe(t *testing.T, srv *Server) (send func(string), out *lockedBuffer, stop func()) {
+	t.Helper()
+	inR, inW := io.Pipe()
+	out = &lockedBuffer{}
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(context.Background(), inR, out) }()
+	var stopped bool
+	stop = func() {
+		if stopped {
+			return
+		}
+		stopped = true
+		inW.Close()
+		if err := <-done; err != nil {
+			t.Errorf("serve: %v", err)
+		}
+	}
+	t.Cleanup(stop)
+	send = func(line string) { io.WriteString(inW, line+"\n") }
+	return send, out, stop
+}
+
+func waitForOutput(t *testing.T, out *lockedBuffer, substr string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if strings.Contains(out.String(), substr) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s; output:\n%s", substr, out.String())
+}
+
+func TestServe_SlowCallDoesNotBlockOthers(t *testing.T) {
+	srv := testServer()
+	w := newWaitTool(srv)
+	send, out, stop := servePipe(t, srv)
+
+	send(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"wait","_meta":{"progressToken":"tok-1"}}}`)
+	waitForOutput(t, out, `{"jsonrpc":"2.0","method":"notifications/progress","params":{"progressToken":"tok-1","progress":1,"total":2,"message":"waiting"}}`)
+
+	send(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{"msg":"fast"}}}`)
+	send(`{"jsonrpc":"2.0","id":3,"method":"tools/list"}`)
+	waitForOutput(t, out, `"id":2,"result"`)
+	waitForOutput(t, out, `"id":3,"result"`)
+	if strings.Contains(out.String(), `"id":1,`) {
+		t.Fatal("slow call answered before it was released")
+	}
+
+	close(w.release)
+	stop()
+	if !strings.Contains(out.String(), `"id":1,"result":{"content":[{"type":"text","text":"released"}]}`) {
+		t.Errorf("released call not answered; output:\n%s", out.String())
+	}
+}
+
+func TestServe_CancelledCallGetsNoResponse(t *testing.T) {
+	srv := testServer()
+	w := newWaitTool(srv)
+	send, out, stop := servePipe(t, srv)
+
+	send(`{"jsonrpc":"2.0","id":"call-1","method":"tools/call","params":{"name":"wait"}}`)
+	<-w.started
+	send(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"call-1","reason":"user abort"}}`)
+	if err := <-w.ended; !errors.Is(err, context.Canceled) {
+		t.Errorf("handler ended with %v, want context.Canceled", err)
+	}
+	// Cancelling an unknown or finished request is ignored.
+	send(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"nope"}}`)
+	send(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+	stop()
+
+	if got := out.String(); strings.Contains(got, `"call-1"`) || strings.Contains(got, "notifications/progress") {
+		t.Errorf("cancelled call answered, or progress sent without a token:\n%s", got)
+	}
+	if !strings.Contains(out.String(), `"id":2,"result"`) {
+		t.Errorf("server stopped answering after a cancellation:\n%s", out.String())
+	}
+}
+
+func TestServe_BoundsConcurrentCalls(t *testing.T) {
+	srv := testServer()
+	w := newWaitTool(srv)
+	send, out, stop := servePipe(t, srv)
+
+	for i := 1; i <= maxConcurrentCalls+1; i++ {
+		send(`{"jsonrpc":"2.0","id":` + strconv.Itoa(i) + `,"method":"tools/call","params":{"name":"wait"}}`)
+	}
+	for range maxConcurrentCalls {
+		<-w.started
+	}
+	select {
+	case <-w.started:
+		t.Fatalf("more than %d calls ran at once", maxConcurrentCalls)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(w.release)
+	stop()
+	if n := strings.Count(out.String(), `"text":"released"`); n != maxConcurrentCalls+1 {
+		t.Errorf("%d calls answered, want %d", n, maxConcurrentCalls+1)
+	}
+}
+
+func TestServe_RejectsDuplicateInFlightID(t *testing.T) {
+	srv := testServer()
+	w := newWaitTool(srv)
+	send, out, stop := servePipe(t, srv)
+
+	send(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"wait"}}`)
+	<-w.started
+	send(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"echo","arguments":{"msg":"again"}}}`)
+	waitForOutput(t, out, `{"jsonrpc":"2.0","id":7,"error":{"code":-32600,"message":"request id 7 is already in flight"}}`)
+
+	// The original call is still cancellable and, once released, answered.
+	close(w.release)
+	stop()
+	if !strings.Contains(out.String(), `"id":7,"result":{"content":[{"type":"text","text":"released"}]}`) {
+		t.Errorf("original call not answered; output:\n%s", out.String())
+	}
+}
+
+func TestServe_RunsExclusiveToolsOneAtATime(t *testing.T) {
+	srv := testServer()
+	w := newWaitTool(srv)
+	srv.RegisterTool(Tool{
+		Definition: ToolDef{Name: "wait_exclusive", InputSchema: json.RawMessage(`{"type":"object"}`)},
+		Handler:    srv.tools["wait"].Handler,
+		Exclusive:  true,
+	})
+	send, out, stop := servePipe(t, srv)
+
+	send(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"wait_exclusive"}}`)
+	send(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"wait_exclusive"}}`)
+	<-w.started
+	select {
+	case <-w.started:
+		t.Fatal("two exclusive calls ran at once")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Other tools are not held up by the exclusive one.
+	send(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"msg":"fast"}}}`)
+	waitForOutput(t, out, `"id":3,"result"`)
+
+	close(w.release)
+	stop()
+	if n := strings.Count(out.String(), `"text":"released"`); n != 2 {
+		t.Errorf("%d exclusive calls answered, want 2; output:\n%s", n, out.String())
+	}
+}
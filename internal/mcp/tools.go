@@ -63,7 +63,7 @@ func NewGetProjectContextTool(cfg config.Config) Tool {
 				}
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project   string   `json:"project"`
 				Sections  []string `json:"sections"`
@@ -114,7 +114,7 @@ func NewListProjectsTool(cfg config.Config) Tool {
 				"properties": {}
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			idx, err := index.Load(cfg.StateDir())
 			if err != nil {
 				return "", fmt.Errorf("load index: %w", err)
@@ -221,7 +221,7 @@ func NewSearchSessionsTool(cfg config.Config) Tool {
 				}
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Query       string   `json:"query"`
 				Project     string   `json:"project"`
@@ -357,7 +357,7 @@ func NewGetKnowledgeTool(cfg config.Config) Tool {
 				"required": ["project"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project string `json:"project"`
 			}
@@ -418,7 +418,7 @@ func NewGetSessionDetailTool(cfg config.Config) Tool {
 				"required": ["project", "date"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project   string `json:"project"`
 				Date      string `json:"date"`
@@ -509,7 +509,7 @@ func NewGetFrictionTrendsTool(cfg config.Config) Tool {
 				}
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project string `json:"project"`
 				Weeks   int    `json:"weeks"`
@@ -554,7 +554,7 @@ func NewGetEffectivenessTool(cfg config.Config) Tool {
 				}
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project string `json:"project"`
 			}
@@ -624,7 +624,7 @@ func NewCaptureSessionTool(cfg config.Config) Tool {
 				"required": ["summary"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Summary      string   `json:"summary"`
 				Title        string   `json:"title"`
@@ -752,6 +752,7 @@ func NewCaptureSessionTool(cfg config.Config) Tool {
 			data, _ := json.Marshal(resp)
 			return string(data), nil
 		},
+		Exclusive: true,
 	}
 }
 
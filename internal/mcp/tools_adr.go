@@ -0,0 +1,167 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/suykerbuyk/vibe-vault/internal/adr"
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/index"
+)
+
+// NewADRTool creates the vv_adr tool: list, promote, accept, and supersede
+// Architecture Decision Records under Projects/<p>/decisions/. Mirrors the
+// `vv adr` CLI.
+func NewADRTool(cfg config.Config) Tool {
+	return Tool{
+		Definition: ToolDef{
+			Name:        "vv_adr",
+			Description: "Manage Architecture Decision Records (Projects/<p>/decisions/NNNN-slug.md). Actions: list (existing ADRs), candidates (session decisions worth promoting), promote (record a decision; dedupes against live ADRs), accept (proposed → accepted), supersede (replace an ADR with a new decision or an existing ADR).",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"project": {
+						"type": "string",
+						"description": "Project name. If omitted, detected from working directory."
+					},
+					"action": {
+						"type": "string",
+						"enum": ["list", "candidates", "promote", "accept", "supersede"],
+						"description": "Action to perform."
+					},
+					"decision": {
+						"type": "string",
+						"description": "Decision text, optionally \"Decision — rationale\" (promote; supersede when by is omitted)."
+					},
+					"number": {
+						"type": "integer",
+						"description": "ADR number to accept or supersede."
+					},
+					"by": {
+						"type": "integer",
+						"description": "Existing ADR number that supersedes number (supersede)."
+					},
+					"status": {
+						"type": "string",
+						"enum": ["proposed", "accepted"],
+						"description": "Initial status for promote (default accepted)."
+					},
+					"sessions": {
+						"type": "array",
+						"items": {"type": "string"},
+						"description": "Source session note names, e.g. 2026-02-22-01."
+					}
+				},
+				"required": ["action"]
+			}`),
+		},
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
+			var args struct {
+				Project  string   `json:"project"`
+				Action   string   `json:"action"`
+				Decision string   `json:"decision"`
+				Number   int      `json:"number"`
+				By       int      `json:"by"`
+				Status   string   `json:"status"`
+				Sessions []string `json:"sessions"`
+			}
+			if len(params) > 0 {
+				if err := json.Unmarshal(params, &args); err != nil {
+					return "", fmt.Errorf("invalid arguments: %w", err)
+				}
+			}
+			project, err := resolveProject(args.Project)
+			if err != nil {
+				return "", err
+			}
+			idx, err := index.Load(cfg.StateDir())
+			if err != nil {
+				return "", fmt.Errorf("load index: %w", err)
+			}
+			opts := adr.Options{
+				Sessions: args.Sessions,
+				Flavor:   cfg.Flavor(),
+				Links:    idx.NoteLinks(project),
+			}
+
+			var result any
+			switch args.Action {
+			case "list":
+				records, err := adr.List(cfg.VaultPath, project)
+				if err != nil {
+					return "", err
+				}
+				if records == nil {
+					records = []adr.Record{}
+				}
+				result = records
+
+			case "candidates":
+				records, err := adr.List(cfg.VaultPath, project)
+				if err != nil {
+					return "", err
+				}
+				cands := adr.Candidates(idx.ADRSources(project), records)
+				if cands == nil {
+					cands = []adr.Candidate{}
+				}
+				result = cands
+
+			case "promote":
+				if args.Decision == "" {
+					return "", fmt.Errorf("decision is required for promote")
+				}
+				if opts.Status, err = adr.ParseStatus(args.Status); err != nil {
+					return "", err
+				}
+				r, created, err := adr.Promote(cfg.VaultPath, project, args.Decision, opts)
+				if err != nil {
+					return "", err
+				}
+				result = map[string]any{"created": created, "adr": r}
+
+			case "accept":
+				if args.Number <= 0 {
+					return "", fmt.Errorf("number is required for accept")
+				}
+				r, err := adr.Accept(cfg.VaultPath, project, args.Number, opts)
+				if err != nil {
+					return "", err
+				}
+				result = r
+
+			case "supersede":
+				if args.Number <= 0 {
+					return "", fmt.Errorf("number is required for supersede")
+				}
+				next, prev, err := adr.Supersede(cfg.VaultPath, project, args.Number, args.Decision, args.By, opts)
+				if err != nil {
+					return "", err
+				}
+				result = map[string]any{"superseded": prev, "by": next}
+
+			default:
+				return "", fmt.Errorf("unknown action %q — expected list, candidates, promote, accept, or supersede", args.Action)
+			}
+
+			if args.Action != "list" && args.Action != "candidates" {
+				// Keep history.md's Architecture Decisions section current.
+				if _, err := index.GenerateContext(idx, cfg.VaultPath, index.ContextOptionsFor(cfg)); err != nil {
+					log.Printf("vv_adr: regenerate history: %v", err)
+				}
+			}
+
+			data, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				return "", fmt.Errorf("marshal: %w", err)
+			}
+			return string(data) + "\n", nil
+		},
+		Exclusive: true,
+	}
+}
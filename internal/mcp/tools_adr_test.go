@@ -0,0 +1,62 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/suykerbuyk/vibe-vault/internal/index"
+)
+
+func TestADRTool_PromoteCandidatesSupersede(t *testing.T) {
+	cfg := writeTestVault(t, map[string]index.SessionEntry{
+		"s1": {SessionID: "s1", Project: "proj", Date: "2026-03-01", Iteration: 1,
+			NotePath:  "Projects/proj/sessions/2026-03-01-01.md",
+			Decisions: []string{"Use Postgres for the event store"}},
+		"s2": {SessionID: "s2", Project: "proj", Date: "2026-03-02", Iteration: 1,
+			NotePath: "Projects/proj/sessions/2026-03-02-01.md",
+			Summary:  "Tuned the Postgres event store indexes"},
+	}, nil)
+	tool := NewADRTool(cfg)
+	call := func(params string) string {
+		t.Helper()
+		out, err := tool.Handler(context.Background(), json.RawMessage(params))
+		if err != nil {
+			t.Fatalf("%s: %v", params, err)
+		}
+		return out
+	}
+
+	out := call(`{"project":"proj","action":"candidates"}`)
+	if !strings.Contains(out, `"text": "Use Postgres for the event store"`) || !strings.Contains(out, "2026-03-02-01") {
+		t.Errorf("candidates = %s", out)
+	}
+
+	out = call(`{"project":"proj","action":"promote","decision":"Use Postgres for the event store","sessions":["2026-03-01-01"]}`)
+	if !strings.Contains(out, `"created": true`) || !strings.Contains(out, `"number": 1`) {
+		t.Errorf("promote = %s", out)
+	}
+	if out := call(`{"project":"proj","action":"candidates"}`); strings.TrimSpace(out) != "[]" {
+		t.Errorf("promoted decision still a candidate: %s", out)
+	}
+
+	out = call(`{"project":"proj","action":"supersede","number":1,"decision":"Move the event store to SQLite"}`)
+	if !strings.Contains(out, `"superseded_by": 2`) {
+		t.Errorf("supersede = %s", out)
+	}
+
+	history, err := os.ReadFile(filepath.Join(cfg.VaultPath, "Projects", "proj", "history.md"))
+	if err != nil {
+		t.Fatalf("history.md not regenerated: %v", err)
+	}
+	if !strings.Contains(string(history), "[[0002-move-the-event-store-to-sqlite]]") || strings.Contains(string(history), "[[0001-") {
+		t.Errorf("history.md ADR section wrong:\n%s", history)
+	}
+
+	if _, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"proj","action":"promote"}`)); err == nil {
+		t.Error("expected error for promote without decision")
+	}
+}
@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -45,7 +46,7 @@ func NewGetAgentDefinitionTool() Tool {
 				"required": ["name"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Name string `json:"name"`
 			}
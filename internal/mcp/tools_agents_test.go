@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 	"testing"
@@ -19,7 +20,7 @@ func TestVVGetAgentDefinition_NotFound(t *testing.T) {
 	tool := NewGetAgentDefinitionTool()
 	params, _ := json.Marshal(map[string]string{"name": "does-not-exist"})
 
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil {
 		t.Fatal("expected error for unknown agent name")
 	}
@@ -32,7 +33,7 @@ func TestVVGetAgentDefinition_MissingName(t *testing.T) {
 	tool := NewGetAgentDefinitionTool()
 	params, _ := json.Marshal(map[string]string{})
 
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil {
 		t.Fatal("expected error for missing name argument")
 	}
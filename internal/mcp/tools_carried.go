@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -81,7 +82,7 @@ func NewCarriedAddTool(cfg config.Config) Tool {
 				"required": ["slug", "title"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project string `json:"project"`
 				Slug    string `json:"slug"`
@@ -117,6 +118,7 @@ func NewCarriedAddTool(cfg config.Config) Tool {
 
 			return writeCarriedResume(cfg, absPath, project, updated, args.Slug)
 		},
+		Exclusive: true,
 	}
 }
 
@@ -143,7 +145,7 @@ func NewCarriedRemoveTool(cfg config.Config) Tool {
 				"required": ["slug"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project string `json:"project"`
 				Slug    string `json:"slug"`
@@ -174,6 +176,7 @@ func NewCarriedRemoveTool(cfg config.Config) Tool {
 
 			return writeCarriedResume(cfg, absPath, project, updated, args.Slug)
 		},
+		Exclusive: true,
 	}
 }
 
@@ -205,7 +208,7 @@ func NewCarriedPromoteToTaskTool(cfg config.Config) Tool {
 				"required": ["slug", "new_task_slug"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project     string `json:"project"`
 				Slug        string `json:"slug"`
@@ -286,6 +289,7 @@ func NewCarriedPromoteToTaskTool(cfg config.Config) Tool {
 			}
 			return string(data) + "\n", nil
 		},
+		Exclusive: true,
 	}
 }
 
@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -88,7 +89,7 @@ func TestCarriedAdd_ToEmpty(t *testing.T) {
 		"title":   "a new carried item",
 		"body":    "detail text here",
 	})
-	result, err := tool.Handler(params)
+	result, err := tool.Handler(context.Background(), params)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -123,7 +124,7 @@ func TestCarriedAdd_ToSingle(t *testing.T) {
 		"title":   "new title",
 		"body":    "",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -155,7 +156,7 @@ func TestCarriedAdd_ToMulti(t *testing.T) {
 		"title":   "new title",
 		"body":    "body text",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -187,7 +188,7 @@ func TestCarriedAdd_SlugAlreadyExists(t *testing.T) {
 		"title":   "dup",
 		"body":    "",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil || !strings.Contains(err.Error(), "already exists") {
 		t.Fatalf("want already-exists error, got %v", err)
 	}
@@ -204,7 +205,7 @@ func TestCarriedAdd_SlugAlreadyExists_CaseInsensitive(t *testing.T) {
 		"title":   "dup",
 		"body":    "",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil || !strings.Contains(err.Error(), "already exists") {
 		t.Fatalf("want already-exists (case-insensitive) error, got %v", err)
 	}
@@ -220,7 +221,7 @@ func TestCarriedAdd_MissingSlug(t *testing.T) {
 		"slug":    "",
 		"title":   "title",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil || !strings.Contains(err.Error(), "slug is required") {
 		t.Fatalf("want slug-required error, got %v", err)
 	}
@@ -236,7 +237,7 @@ func TestCarriedAdd_MissingTitle(t *testing.T) {
 		"slug":    "new-slug",
 		"title":   "",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil || !strings.Contains(err.Error(), "title is required") {
 		t.Fatalf("want title-required error, got %v", err)
 	}
@@ -250,7 +251,7 @@ func TestCarriedAdd_ProjectNotFound(t *testing.T) {
 		"slug":    "new",
 		"title":   "title",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil {
 		t.Fatal("want error for missing project")
 	}
@@ -267,7 +268,7 @@ func TestCarriedAdd_CanonicalBulletForm(t *testing.T) {
 		"title":   "my title",
 		"body":    "",
 	})
-	if _, err := tool.Handler(params); err != nil {
+	if _, err := tool.Handler(context.Background(), params); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	data, _ := os.ReadFile(filepath.Join(c.VaultPath, "Projects", "proj", "agentctx", "resume.md"))
@@ -289,7 +290,7 @@ func TestCarriedRemove_Single(t *testing.T) {
 		"project": "proj",
 		"slug":    "only-bullet",
 	})
-	result, err := tool.Handler(params)
+	result, err := tool.Handler(context.Background(), params)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -316,7 +317,7 @@ func TestCarriedRemove_Multi_First(t *testing.T) {
 		"project": "proj",
 		"slug":    "mcp subtest",
 	})
-	if _, err := tool.Handler(params); err != nil {
+	if _, err := tool.Handler(context.Background(), params); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	data, _ := os.ReadFile(filepath.Join(c.VaultPath, "Projects", "proj", "agentctx", "resume.md"))
@@ -338,7 +339,7 @@ func TestCarriedRemove_Multi_Last(t *testing.T) {
 		"project": "proj",
 		"slug":    "session synthesis agent",
 	})
-	if _, err := tool.Handler(params); err != nil {
+	if _, err := tool.Handler(context.Background(), params); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	data, _ := os.ReadFile(filepath.Join(c.VaultPath, "Projects", "proj", "agentctx", "resume.md"))
@@ -360,7 +361,7 @@ func TestCarriedRemove_CaseInsensitive(t *testing.T) {
 		"project": "proj",
 		"slug":    "ONLY-BULLET",
 	})
-	if _, err := tool.Handler(params); err != nil {
+	if _, err := tool.Handler(context.Background(), params); err != nil {
 		t.Fatalf("case-insensitive remove failed: %v", err)
 	}
 	data, _ := os.ReadFile(filepath.Join(c.VaultPath, "Projects", "proj", "agentctx", "resume.md"))
@@ -378,7 +379,7 @@ func TestCarriedRemove_SlugNotFound(t *testing.T) {
 		"project": "proj",
 		"slug":    "ghost-slug",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil || !strings.Contains(err.Error(), "ghost-slug") {
 		t.Fatalf("want not-found error, got %v", err)
 	}
@@ -397,7 +398,7 @@ func TestCarriedRemove_MissingSlug(t *testing.T) {
 		"project": "proj",
 		"slug":    "",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil || !strings.Contains(err.Error(), "slug is required") {
 		t.Fatalf("want slug-required error, got %v", err)
 	}
@@ -415,7 +416,7 @@ func TestCarriedPromote_Basic(t *testing.T) {
 		"slug":          "mcp subtest",
 		"new_task_slug": "fix-mcp-subtest",
 	})
-	result, err := tool.Handler(params)
+	result, err := tool.Handler(context.Background(), params)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -473,7 +474,7 @@ func TestCarriedPromote_SingleBullet(t *testing.T) {
 		"slug":          "only-bullet",
 		"new_task_slug": "only-bullet-task",
 	})
-	if _, err := tool.Handler(params); err != nil {
+	if _, err := tool.Handler(context.Background(), params); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	resumeData, _ := os.ReadFile(filepath.Join(c.VaultPath, "Projects", "proj", "agentctx", "resume.md"))
@@ -492,7 +493,7 @@ func TestCarriedPromote_SlugNotFound(t *testing.T) {
 		"slug":          "ghost-slug",
 		"new_task_slug": "ghost-task",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil || !strings.Contains(err.Error(), "ghost-slug") {
 		t.Fatalf("want not-found error, got %v", err)
 	}
@@ -512,7 +513,7 @@ func TestCarriedPromote_TaskAlreadyExists(t *testing.T) {
 		"slug":          "mcp subtest",
 		"new_task_slug": "existing-task",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil || !strings.Contains(err.Error(), "already exists") {
 		t.Fatalf("want task-already-exists error, got %v", err)
 	}
@@ -528,7 +529,7 @@ func TestCarriedPromote_MissingSlug(t *testing.T) {
 		"slug":          "",
 		"new_task_slug": "task",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil || !strings.Contains(err.Error(), "slug is required") {
 		t.Fatalf("want slug-required error, got %v", err)
 	}
@@ -544,7 +545,7 @@ func TestCarriedPromote_MissingNewTaskSlug(t *testing.T) {
 		"slug":          "mcp subtest",
 		"new_task_slug": "",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil || !strings.Contains(err.Error(), "new_task_slug is required") {
 		t.Fatalf("want new_task_slug-required error, got %v", err)
 	}
@@ -560,7 +561,7 @@ func TestCarriedPromote_TaskFrontmatterShape(t *testing.T) {
 		"slug":          "only-bullet",
 		"new_task_slug": "my-new-task",
 	})
-	result, err := tool.Handler(params)
+	result, err := tool.Handler(context.Background(), params)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -42,7 +43,7 @@ func NewCheckToolchainTool() Tool {
 				"properties": {}
 			}`),
 		},
-		Handler: func(_ json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, _ json.RawMessage) (string, error) {
 			results := check.CheckToolchain()
 			// Project to MCP wire shape: lowercase status, name/status/detail keys.
 			type entry struct {
@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 	"testing"
@@ -47,7 +48,7 @@ func TestNewCheckToolchainTool_Schema(t *testing.T) {
 func TestNewCheckToolchainTool_HandlerReturnsValidJSON(t *testing.T) {
 	tool := NewCheckToolchainTool()
 
-	out, err := tool.Handler(nil)
+	out, err := tool.Handler(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Handler returned error: %v", err)
 	}
@@ -80,7 +81,7 @@ func TestNewCheckToolchainTool_HandlerReturnsValidJSON(t *testing.T) {
 func TestNewCheckToolchainTool_HandlerStatusValues(t *testing.T) {
 	tool := NewCheckToolchainTool()
 
-	out, err := tool.Handler(nil)
+	out, err := tool.Handler(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Handler returned error: %v", err)
 	}
@@ -111,7 +112,7 @@ func TestNewCheckToolchainTool_HandlerStatusValues(t *testing.T) {
 func TestNewCheckToolchainTool_HandlerNamesPrefixed(t *testing.T) {
 	tool := NewCheckToolchainTool()
 
-	out, err := tool.Handler(nil)
+	out, err := tool.Handler(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Handler returned error: %v", err)
 	}
@@ -140,7 +141,7 @@ func TestNewCheckToolchainTool_HandlerNamesPrefixed(t *testing.T) {
 func TestNewCheckToolchainTool_HandlerOrderMatchesSpecs(t *testing.T) {
 	tool := NewCheckToolchainTool()
 
-	out, err := tool.Handler(nil)
+	out, err := tool.Handler(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Handler returned error: %v", err)
 	}
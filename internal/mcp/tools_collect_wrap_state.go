@@ -347,8 +347,10 @@ func oldestRootCommit(ctx context.Context, projectDir string) (string, error) {
 // collectWrapState assembles the full wrap-state record for a project.
 // Pure orchestration: every input is computed by a helper above, and
 // the resulting struct is the JSON shape returned by
-// vv_collect_wrap_state.
+// vv_collect_wrap_state. Each of its stages is reported to ctx's
+// progress reporter as it completes.
 func collectWrapState(ctx context.Context, cfg config.Config, project, cwd string) (CollectWrapStateResult, error) {
+	const stages = 5
 	res := CollectWrapStateResult{}
 
 	n, err := nextIterFromIterationsMD(cfg.VaultPath, project)
@@ -392,6 +394,7 @@ func collectWrapState(ctx context.Context, cfg config.Config, project, cwd strin
 		files = []string{}
 	}
 	res.FilesChanged = files
+	ReportProgress(ctx, 1, stages, "scanned commits since last iteration")
 
 	// task_deltas — set-difference of last-tasks-snapshot.json against
 	// the live tasks/ tree. Bootstraps gracefully when the snapshot is
@@ -415,6 +418,7 @@ func collectWrapState(ctx context.Context, cfg config.Config, project, cwd strin
 	if res.TaskDeltas.Cancelled == nil {
 		res.TaskDeltas.Cancelled = []string{}
 	}
+	ReportProgress(ctx, 2, stages, "computed task deltas")
 
 	// test counts — best-effort parse of doc/TESTING.md headline.
 	u, i, l, warn := testCountsFromTestingMD(filepath.Join(cwd, "doc", "TESTING.md"))
@@ -424,6 +428,10 @@ func collectWrapState(ctx context.Context, cfg config.Config, project, cwd strin
 		Lint:        l,
 		Warning:     warn,
 	}
+	ReportProgress(ctx, 3, stages, "read test counts")
+	if err := ctx.Err(); err != nil {
+		return res, err
+	}
 
 	// vault and project dirty flags. The vault probe is scoped to
 	// `Projects/<project>/` so a sibling project's uncommitted writes
@@ -435,12 +443,14 @@ func collectWrapState(ctx context.Context, cfg config.Config, project, cwd strin
 		return res, fmt.Errorf("vault git status: %w", err)
 	}
 	res.VaultHasUncommittedWrites = vaultDirty
+	ReportProgress(ctx, 4, stages, "probed vault status")
 
 	projectDirty, err := projectHasUncommittedWrites(cwd)
 	if err != nil {
 		return res, fmt.Errorf("project git status: %w", err)
 	}
 	res.ProjectHasUncommittedWrites = projectDirty
+	ReportProgress(ctx, 5, stages, "probed project status")
 
 	// shape: classifier runs on the fully-populated state.
 	res.Shape = ClassifyWrapShape(res)
@@ -476,7 +486,7 @@ func NewCollectWrapStateTool(cfg config.Config) Tool {
 				}
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(ctx context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project string `json:"project"`
 			}
@@ -496,7 +506,7 @@ func NewCollectWrapStateTool(cfg config.Config) Tool {
 				return "", fmt.Errorf("get working directory: %w", err)
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 			defer cancel()
 
 			res, err := collectWrapState(ctx, cfg, project, cwd)
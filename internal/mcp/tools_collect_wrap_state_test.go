@@ -530,7 +530,7 @@ func TestCollectWrapState_Basic(t *testing.T) {
 	t.Chdir(projDir)
 
 	tool := NewCollectWrapStateTool(cfg)
-	out, err := tool.Handler(json.RawMessage(`{"project":"myproj"}`))
+	out, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"myproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -559,6 +559,40 @@ func TestCollectWrapState_Basic(t *testing.T) {
 	}
 }
 
+// TestCollectWrapState_ReportsProgress asserts each collection stage
+// is reported, in order, when the caller sent a progressToken.
+func TestCollectWrapState_ReportsProgress(t *testing.T) {
+	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
+	initGitRepo(t, cfg.VaultPath)
+	commitAllInRepo(t, cfg.VaultPath, "initial vault state")
+
+	projDir := t.TempDir()
+	initGitRepo(t, projDir)
+	gitCommit(t, projDir, "initial commit", "")
+	t.Chdir(projDir)
+
+	var got []ProgressParams
+	c := newConn(func(msg any) {
+		if n, ok := msg.(Notification); ok && n.Method == "notifications/progress" {
+			got = append(got, n.Params.(ProgressParams))
+		}
+	}, 1)
+	defer c.close()
+	ctx := withProgress(context.Background(), c, &RequestMeta{ProgressToken: json.RawMessage(`7`)})
+
+	if _, err := NewCollectWrapStateTool(cfg).Handler(ctx, json.RawMessage(`{"project":"myproj"}`)); err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	if len(got) != 5 {
+		t.Fatalf("got %d progress notifications, want 5: %+v", len(got), got)
+	}
+	for i, p := range got {
+		if p.Progress != float64(i+1) || p.Total != 5 || string(p.ProgressToken) != "7" || p.Message == "" {
+			t.Errorf("notification %d = %+v", i, p)
+		}
+	}
+}
+
 // TestCollectWrapState_DirtyVault asserts vault_has_uncommitted_writes
 // flips to true when the project's own subtree under
 // Projects/<project>/ has an uncommitted file. Per the C4-followup fix,
@@ -583,7 +617,7 @@ func TestCollectWrapState_DirtyVault(t *testing.T) {
 	t.Chdir(projDir)
 
 	tool := NewCollectWrapStateTool(cfg)
-	out, err := tool.Handler(json.RawMessage(`{"project":"myproj"}`))
+	out, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"myproj"}`))
 	if err != nil {
 		t.Fatalf("handler: %v", err)
 	}
@@ -622,7 +656,7 @@ func TestCollectWrapState_OtherProjectDirty_NoFalsePositive(t *testing.T) {
 	t.Chdir(projDir)
 
 	tool := NewCollectWrapStateTool(cfg)
-	out, err := tool.Handler(json.RawMessage(`{"project":"myproj"}`))
+	out, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"myproj"}`))
 	if err != nil {
 		t.Fatalf("handler: %v", err)
 	}
@@ -654,7 +688,7 @@ func TestCollectWrapState_VaultRootDirty_NoFalsePositive(t *testing.T) {
 	t.Chdir(projDir)
 
 	tool := NewCollectWrapStateTool(cfg)
-	out, err := tool.Handler(json.RawMessage(`{"project":"myproj"}`))
+	out, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"myproj"}`))
 	if err != nil {
 		t.Fatalf("handler: %v", err)
 	}
@@ -694,7 +728,7 @@ func TestCollectWrapState_PriorIterAnchorFound(t *testing.T) {
 	}
 
 	tool := NewCollectWrapStateTool(cfg)
-	out, err := tool.Handler(json.RawMessage(`{"project":"myproj"}`))
+	out, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"myproj"}`))
 	if err != nil {
 		t.Fatalf("handler: %v", err)
 	}
@@ -737,7 +771,7 @@ func TestCollectWrapState_BranchDetection(t *testing.T) {
 	t.Chdir(projDir)
 
 	tool := NewCollectWrapStateTool(cfg)
-	out, err := tool.Handler(json.RawMessage(`{"project":"myproj"}`))
+	out, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"myproj"}`))
 	if err != nil {
 		t.Fatalf("handler: %v", err)
 	}
@@ -756,7 +790,7 @@ func TestCollectWrapState_InvalidProjectName(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewCollectWrapStateTool(cfg)
-	if _, err := tool.Handler(json.RawMessage(`{"project":"../etc"}`)); err == nil {
+	if _, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"../etc"}`)); err == nil {
 		t.Fatal("want project-validation error, got nil")
 	}
 }
@@ -774,7 +808,7 @@ func TestCollectWrapState_NoVaultGit(t *testing.T) {
 	t.Chdir(projDir)
 
 	tool := NewCollectWrapStateTool(cfg)
-	out, err := tool.Handler(json.RawMessage(`{"project":"myproj"}`))
+	out, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"myproj"}`))
 	if err != nil {
 		t.Fatalf("handler: %v", err)
 	}
@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -46,7 +47,7 @@ func NewSetCommitMsgTool(cfg config.Config) Tool {
 				"required": ["project_path", "content"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project     string `json:"project"`
 				ProjectPath string `json:"project_path"`
@@ -109,6 +110,7 @@ func NewSetCommitMsgTool(cfg config.Config) Tool {
 			}
 			return string(out) + "\n", nil
 		},
+		Exclusive: true,
 	}
 }
 
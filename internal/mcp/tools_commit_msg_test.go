@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -32,7 +33,7 @@ func TestSetCommitMsg_CreatesMissing(t *testing.T) {
 		"project_path": projectRoot,
 		"content":      "feat(foo): add bar\n\nThis is a commit message.\n",
 	})
-	result, err := tool.Handler(params)
+	result, err := tool.Handler(context.Background(), params)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -85,7 +86,7 @@ func TestSetCommitMsg_OverwritesExisting(t *testing.T) {
 		"project_path": projectRoot,
 		"content":      "new message\n",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -110,7 +111,7 @@ func TestSetCommitMsg_ProjectPathRequired(t *testing.T) {
 		"project": "myproject",
 		"content": "some message",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil {
 		t.Fatal("expected error for missing project_path")
 	}
@@ -127,7 +128,7 @@ func TestSetCommitMsg_ContentRequired(t *testing.T) {
 		"project":      "myproject",
 		"project_path": "/some/path",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil {
 		t.Fatal("expected error for missing content")
 	}
@@ -154,7 +155,7 @@ func TestSetCommitMsg_PartialSuccessDiagnostic(t *testing.T) {
 		"project_path": badRoot,
 		"content":      "test message\n",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil {
 		t.Fatal("expected error when project-root copy fails")
 	}
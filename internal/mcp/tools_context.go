@@ -5,6 +5,7 @@ package mcp
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/fs"
@@ -77,7 +78,7 @@ func NewGetWorkflowTool(cfg config.Config) Tool {
 				}
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project string `json:"project"`
 			}
@@ -131,7 +132,7 @@ func NewGetResumeTool(cfg config.Config) Tool {
 				}
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project string `json:"project"`
 			}
@@ -253,7 +254,7 @@ func NewListTasksTool(cfg config.Config) Tool {
 				}
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project     string `json:"project"`
 				IncludeDone bool   `json:"include_done"`
@@ -339,7 +340,7 @@ func NewGetTaskTool(cfg config.Config) Tool {
 				"required": ["task"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Task    string `json:"task"`
 				Project string `json:"project"`
@@ -408,7 +409,7 @@ func NewBootstrapContextTool(cfg config.Config) Tool {
 				}
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project   string `json:"project"`
 				MaxTokens int    `json:"max_tokens"`
@@ -571,7 +572,7 @@ func NewListLearningsTool(cfg config.Config) Tool {
 				}
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				FilterType string `json:"filter_type"`
 			}
@@ -615,7 +616,7 @@ func NewGetLearningTool(cfg config.Config) Tool {
 				"required": ["slug"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Slug string `json:"slug"`
 			}
@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -34,7 +35,7 @@ func TestGetWorkflowBasic(t *testing.T) {
 	})
 
 	tool := NewGetWorkflowTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -47,7 +48,7 @@ func TestGetWorkflowFallbackToTemplate(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewGetWorkflowTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"newproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"newproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -64,7 +65,7 @@ func TestGetWorkflowPathTraversal(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewGetWorkflowTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"../etc/passwd"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"../etc/passwd"}`))
 	if err == nil {
 		t.Fatal("expected error for path traversal")
 	}
@@ -78,7 +79,7 @@ func TestGetResumeBasic(t *testing.T) {
 	})
 
 	tool := NewGetResumeTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -91,7 +92,7 @@ func TestGetResumeMissing(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewGetResumeTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"noproject"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"noproject"}`))
 	if err == nil {
 		t.Fatal("expected error for missing resume.md")
 	}
@@ -104,7 +105,7 @@ func TestGetResumePathTraversal(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewGetResumeTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"../../etc"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"../../etc"}`))
 	if err == nil {
 		t.Fatal("expected error for path traversal")
 	}
@@ -122,7 +123,7 @@ func TestListTasksBasic(t *testing.T) {
 	})
 
 	tool := NewListTasksTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -166,7 +167,7 @@ func TestListTasksIncludeDone(t *testing.T) {
 	tool := NewListTasksTool(cfg)
 
 	// Without include_done
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -179,7 +180,7 @@ func TestListTasksIncludeDone(t *testing.T) {
 	}
 
 	// With include_done
-	result, err = tool.Handler(json.RawMessage(`{"project":"testproj","include_done":true}`))
+	result, err = tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","include_done":true}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -204,7 +205,7 @@ func TestListTasksEmpty(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewListTasksTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -222,7 +223,7 @@ func TestListTasksPathTraversal(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewListTasksTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"../etc/passwd"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"../etc/passwd"}`))
 	if err == nil {
 		t.Fatal("expected error for path traversal")
 	}
@@ -235,7 +236,7 @@ func TestListTasksStatusFormats(t *testing.T) {
 	})
 
 	tool := NewListTasksTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -270,7 +271,7 @@ func TestListTasksOmitsEmptyMetadata(t *testing.T) {
 	})
 
 	tool := NewListTasksTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -340,7 +341,7 @@ func TestListTasksIncludeDoneRetainsTrueFlag(t *testing.T) {
 	})
 
 	tool := NewListTasksTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","include_done":true}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","include_done":true}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -358,7 +359,7 @@ func TestGetTaskBasic(t *testing.T) {
 	})
 
 	tool := NewGetTaskTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"task":"my-task","project":"testproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"task":"my-task","project":"testproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -374,7 +375,7 @@ func TestGetTaskFallbackToDone(t *testing.T) {
 	})
 
 	tool := NewGetTaskTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"task":"old-task","project":"testproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"task":"old-task","project":"testproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -390,7 +391,7 @@ func TestGetTaskFallbackToCancelled(t *testing.T) {
 	})
 
 	tool := NewGetTaskTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"task":"nope","project":"testproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"task":"nope","project":"testproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -403,7 +404,7 @@ func TestGetTaskNotFound(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewGetTaskTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"task":"nonexistent","project":"testproj"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"task":"nonexistent","project":"testproj"}`))
 	if err == nil {
 		t.Fatal("expected error for missing task")
 	}
@@ -416,7 +417,7 @@ func TestGetTaskPathTraversal(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewGetTaskTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"task":"../../../etc/passwd","project":"testproj"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"task":"../../../etc/passwd","project":"testproj"}`))
 	if err == nil {
 		t.Fatal("expected error for path traversal")
 	}
@@ -426,7 +427,7 @@ func TestGetTaskMissingName(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewGetTaskTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"testproj"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj"}`))
 	if err == nil {
 		t.Fatal("expected error for missing task name")
 	}
@@ -451,7 +452,7 @@ func TestBootstrapContextBasic(t *testing.T) {
 	})
 
 	tool := NewBootstrapContextTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -492,7 +493,7 @@ func TestBootstrapContextMissingResume(t *testing.T) {
 	})
 
 	tool := NewBootstrapContextTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -512,7 +513,7 @@ func TestBootstrapContextWorkflowFallback(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewBootstrapContextTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"newproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"newproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -537,7 +538,7 @@ func TestBootstrapContextNoTasks(t *testing.T) {
 	})
 
 	tool := NewBootstrapContextTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -575,7 +576,7 @@ func TestBootstrapContextTokenBudget(t *testing.T) {
 
 	tool := NewBootstrapContextTool(cfg)
 	// Use a small token budget to force truncation
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","max_tokens":100}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","max_tokens":100}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -596,7 +597,7 @@ func TestBootstrapContextPathTraversal(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewBootstrapContextTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"../etc/passwd"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"../etc/passwd"}`))
 	if err == nil {
 		t.Fatal("expected error for path traversal")
 	}
@@ -657,7 +658,7 @@ func TestListLearningsEmptyDirectoryReturnsEmptyArray(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewListLearningsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -678,7 +679,7 @@ func TestListLearningsReturnsMetadata(t *testing.T) {
 	})
 
 	tool := NewListLearningsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -710,7 +711,7 @@ func TestListLearningsFilterType(t *testing.T) {
 	})
 
 	tool := NewListLearningsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"filter_type":"feedback"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"filter_type":"feedback"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -734,7 +735,7 @@ func TestGetLearningReturnsFullContent(t *testing.T) {
 	})
 
 	tool := NewGetLearningTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"slug":"testing"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"slug":"testing"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -761,7 +762,7 @@ func TestGetLearningUnknownSlug(t *testing.T) {
 	})
 
 	tool := NewGetLearningTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"slug":"missing"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"slug":"missing"}`))
 	if err == nil {
 		t.Fatal("expected error for unknown slug")
 	}
@@ -773,7 +774,7 @@ func TestGetLearningUnknownSlug(t *testing.T) {
 func TestGetLearningMissingSlugParam(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 	tool := NewGetLearningTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{}`))
 	if err == nil {
 		t.Fatal("expected error for missing slug")
 	}
@@ -787,7 +788,7 @@ func TestBootstrapContextOmitsLearningsFieldWhenEmpty(t *testing.T) {
 	})
 
 	tool := NewBootstrapContextTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj"}`))
 	if err != nil {
 		t.Fatalf("handler: %v", err)
 	}
@@ -806,7 +807,7 @@ func TestBootstrapContextEmitsLearningsFieldWhenPopulated(t *testing.T) {
 	})
 
 	tool := NewBootstrapContextTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj"}`))
 	if err != nil {
 		t.Fatalf("handler: %v", err)
 	}
@@ -848,7 +849,7 @@ func TestEndToEndDropFileAndList(t *testing.T) {
 	}
 
 	listTool := NewListLearningsTool(cfg)
-	result, err := listTool.Handler(json.RawMessage(`{}`))
+	result, err := listTool.Handler(context.Background(), json.RawMessage(`{}`))
 	if err != nil {
 		t.Fatalf("list: %v", err)
 	}
@@ -857,7 +858,7 @@ func TestEndToEndDropFileAndList(t *testing.T) {
 	}
 
 	getTool := NewGetLearningTool(cfg)
-	getRes, err := getTool.Handler(json.RawMessage(`{"slug":"dropped"}`))
+	getRes, err := getTool.Handler(context.Background(), json.RawMessage(`{"slug":"dropped"}`))
 	if err != nil {
 		t.Fatalf("get: %v", err)
 	}
@@ -867,7 +868,7 @@ func TestEndToEndDropFileAndList(t *testing.T) {
 
 	// And the bootstrap hint should now appear.
 	bootstrapTool := NewBootstrapContextTool(cfg)
-	bootRes, err := bootstrapTool.Handler(json.RawMessage(`{"project":"bootproj"}`))
+	bootRes, err := bootstrapTool.Handler(context.Background(), json.RawMessage(`{"project":"bootproj"}`))
 	if err != nil {
 		t.Fatalf("bootstrap: %v", err)
 	}
@@ -943,7 +944,7 @@ func TestBootstrapContext_DriftWarning(t *testing.T) {
 	chdirT(t, repo)
 
 	tool := NewBootstrapContextTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -986,7 +987,7 @@ func TestBootstrapContext_NoWarning_FeatureBranch(t *testing.T) {
 	chdirT(t, repo)
 
 	tool := NewBootstrapContextTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
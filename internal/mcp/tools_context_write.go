@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -46,7 +47,7 @@ func NewUpdateResumeTool(cfg config.Config) Tool {
 				"required": ["section", "content"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project string `json:"project"`
 				Section string `json:"section"`
@@ -121,6 +122,7 @@ func NewUpdateResumeTool(cfg config.Config) Tool {
 
 			return fmt.Sprintf("Updated section %q in resume.md for project %q", args.Section, project), nil
 		},
+		Exclusive: true,
 	}
 }
 
@@ -287,7 +289,7 @@ func NewAppendIterationTool(cfg config.Config) Tool {
 				"required": ["title", "narrative"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project   string `json:"project"`
 				Iteration *int   `json:"iteration"`
@@ -435,6 +437,7 @@ func NewAppendIterationTool(cfg config.Config) Tool {
 			}
 			return string(out) + "\n", nil
 		},
+		Exclusive: true,
 	}
 }
 
@@ -472,7 +475,7 @@ func NewManageTaskTool(cfg config.Config) Tool {
 				"required": ["task", "action"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project string `json:"project"`
 				Task    string `json:"task"`
@@ -577,6 +580,7 @@ func NewManageTaskTool(cfg config.Config) Tool {
 				return "", fmt.Errorf("unknown action %q — expected create, update_status, retire, or cancel", args.Action)
 			}
 		},
+		Exclusive: true,
 	}
 }
 
@@ -625,7 +629,7 @@ func NewRefreshIndexTool(cfg config.Config) Tool {
 				}
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			idx, count, err := index.Rebuild(cfg.ProjectsDir(), cfg.StateDir())
 			if err != nil {
 				return "", fmt.Errorf("rebuild index: %w", err)
@@ -635,14 +639,7 @@ func NewRefreshIndexTool(cfg config.Config) Tool {
 				return "", fmt.Errorf("save index: %w", saveErr)
 			}
 
-			opts := index.ContextOptions{
-				AlertThreshold:       cfg.Friction.AlertThreshold,
-				TimelineRecentDays:   cfg.History.TimelineRecentDays,
-				TimelineWindowDays:   cfg.History.TimelineWindowDays,
-				DecisionStaleDays:    cfg.History.DecisionStaleDays,
-				KeyFilesRecencyBoost: cfg.History.KeyFilesRecencyBoost,
-			}
-			genResult, err := index.GenerateContext(idx, cfg.VaultPath, opts)
+			genResult, err := index.GenerateContext(idx, cfg.VaultPath, index.ContextOptionsFor(cfg))
 			if err != nil {
 				return "", fmt.Errorf("generate context: %w", err)
 			}
@@ -668,5 +665,6 @@ func NewRefreshIndexTool(cfg config.Config) Tool {
 			}
 			return string(data) + "\n", nil
 		},
+		Exclusive: true,
 	}
 }
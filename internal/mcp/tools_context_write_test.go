@@ -4,7 +4,11 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -22,7 +26,7 @@ func TestUpdateResumeBasic(t *testing.T) {
 	})
 
 	tool := NewUpdateResumeTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","section":"Current Focus","content":"New focus content here."}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","section":"Current Focus","content":"New focus content here."}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -48,7 +52,7 @@ func TestUpdateResumeSectionNotFound(t *testing.T) {
 	})
 
 	tool := NewUpdateResumeTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"testproj","section":"Nonexistent Section","content":"stuff"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","section":"Nonexistent Section","content":"stuff"}`))
 	if err == nil {
 		t.Fatal("expected error for missing section")
 	}
@@ -61,7 +65,7 @@ func TestUpdateResumeFileNotFound(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewUpdateResumeTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"testproj","section":"Focus","content":"stuff"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","section":"Focus","content":"stuff"}`))
 	if err == nil {
 		t.Fatal("expected error for missing resume.md")
 	}
@@ -77,7 +81,7 @@ func TestUpdateResumePreservesOtherSections(t *testing.T) {
 	})
 
 	tool := NewUpdateResumeTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"testproj","section":"Section B","content":"Updated B."}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","section":"Section B","content":"Updated B."}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -105,7 +109,7 @@ func TestUpdateResumeLastSection(t *testing.T) {
 	})
 
 	tool := NewUpdateResumeTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"testproj","section":"Last","content":"New last content."}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","section":"Last","content":"New last content."}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -124,7 +128,7 @@ func TestUpdateResumePathTraversal(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewUpdateResumeTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"../etc","section":"Focus","content":"stuff"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"../etc","section":"Focus","content":"stuff"}`))
 	if err == nil {
 		t.Fatal("expected error for path traversal")
 	}
@@ -159,7 +163,7 @@ func TestUpdateResumeV10CurrentStateValid(t *testing.T) {
 
 	body := "- **Tests:** 1409 across 36 packages.\n- **Lint:** clean.\n- **Schema:** v10.\n"
 	tool := NewUpdateResumeTool(cfg)
-	if _, err := tool.Handler(updateResumePayload(t, "testproj", "Current State", body)); err != nil {
+	if _, err := tool.Handler(context.Background(), updateResumePayload(t, "testproj", "Current State", body)); err != nil {
 		t.Fatalf("expected success, got error: %v", err)
 	}
 
@@ -178,7 +182,7 @@ func TestUpdateResumeV10RejectsNarrative(t *testing.T) {
 
 	body := "This is a paragraph of project narrative that does not belong here."
 	tool := NewUpdateResumeTool(cfg)
-	_, err := tool.Handler(updateResumePayload(t, "testproj", "Current State", body))
+	_, err := tool.Handler(context.Background(), updateResumePayload(t, "testproj", "Current State", body))
 	if err == nil {
 		t.Fatal("expected error for narrative in v10 Current State")
 	}
@@ -201,7 +205,7 @@ func TestUpdateResumeV10RejectsNonWhitelistedKey(t *testing.T) {
 
 	body := "- **Frobnicator:** enabled.\n"
 	tool := NewUpdateResumeTool(cfg)
-	_, err := tool.Handler(updateResumePayload(t, "testproj", "Current State", body))
+	_, err := tool.Handler(context.Background(), updateResumePayload(t, "testproj", "Current State", body))
 	if err == nil {
 		t.Fatal("expected error for non-whitelisted key")
 	}
@@ -219,7 +223,7 @@ func TestUpdateResumeV10RejectsOverCapTrailing(t *testing.T) {
 
 	body := "- **Tests:** " + strings.Repeat("x", 201) + "\n"
 	tool := NewUpdateResumeTool(cfg)
-	_, err := tool.Handler(updateResumePayload(t, "testproj", "Current State", body))
+	_, err := tool.Handler(context.Background(), updateResumePayload(t, "testproj", "Current State", body))
 	if err == nil {
 		t.Fatal("expected error for over-cap trailing content")
 	}
@@ -238,7 +242,7 @@ func TestUpdateResumeV10MultilineCommentAllowed(t *testing.T) {
 		"-->\n" +
 		"- **MCP:** 20 tools + 1 prompt.\n"
 	tool := NewUpdateResumeTool(cfg)
-	if _, err := tool.Handler(updateResumePayload(t, "testproj", "Current State", body)); err != nil {
+	if _, err := tool.Handler(context.Background(), updateResumePayload(t, "testproj", "Current State", body)); err != nil {
 		t.Fatalf("multi-line comment should be skipped, got error: %v", err)
 	}
 }
@@ -253,7 +257,7 @@ func TestUpdateResumeV10GuardIsSectionSpecific(t *testing.T) {
 	// Narrative prose is legitimate in Open Threads.
 	body := "This is a paragraph describing an unresolved question that needs follow-up."
 	tool := NewUpdateResumeTool(cfg)
-	if _, err := tool.Handler(updateResumePayload(t, "testproj", "Open Threads", body)); err != nil {
+	if _, err := tool.Handler(context.Background(), updateResumePayload(t, "testproj", "Open Threads", body)); err != nil {
 		t.Fatalf("narrative in Open Threads must be accepted, got error: %v", err)
 	}
 }
@@ -267,7 +271,7 @@ func TestUpdateResumePreV10NoVersionGrandfathered(t *testing.T) {
 
 	body := "Arbitrary pre-v10 narrative that must be accepted."
 	tool := NewUpdateResumeTool(cfg)
-	if _, err := tool.Handler(updateResumePayload(t, "testproj", "Current State", body)); err != nil {
+	if _, err := tool.Handler(context.Background(), updateResumePayload(t, "testproj", "Current State", body)); err != nil {
 		t.Fatalf("pre-v10 narrative must be accepted, got error: %v", err)
 	}
 }
@@ -281,7 +285,7 @@ func TestUpdateResumePreV10V9Grandfathered(t *testing.T) {
 
 	body := "Arbitrary v9 narrative that must be accepted."
 	tool := NewUpdateResumeTool(cfg)
-	if _, err := tool.Handler(updateResumePayload(t, "testproj", "Current State", body)); err != nil {
+	if _, err := tool.Handler(context.Background(), updateResumePayload(t, "testproj", "Current State", body)); err != nil {
 		t.Fatalf("v9 narrative must be accepted, got error: %v", err)
 	}
 }
@@ -295,7 +299,7 @@ func TestUpdateResumeMalformedVersionGrandfathered(t *testing.T) {
 
 	body := "Narrative prose — malformed .version should silent-skip the guard."
 	tool := NewUpdateResumeTool(cfg)
-	if _, err := tool.Handler(updateResumePayload(t, "testproj", "Current State", body)); err != nil {
+	if _, err := tool.Handler(context.Background(), updateResumePayload(t, "testproj", "Current State", body)); err != nil {
 		t.Fatalf("malformed .version must silent-skip guard, got error: %v", err)
 	}
 }
@@ -310,7 +314,7 @@ func TestUpdateResumeV10ErrorMessageTruncated(t *testing.T) {
 	// Long narrative line — single paragraph with 300+ chars.
 	body := strings.Repeat("lorem ipsum dolor sit amet ", 20)
 	tool := NewUpdateResumeTool(cfg)
-	_, err := tool.Handler(updateResumePayload(t, "testproj", "Current State", body))
+	_, err := tool.Handler(context.Background(), updateResumePayload(t, "testproj", "Current State", body))
 	if err == nil {
 		t.Fatal("expected error for long narrative")
 	}
@@ -328,7 +332,7 @@ func TestAppendIterationAutoIncrement(t *testing.T) {
 	})
 
 	tool := NewAppendIterationTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","title":"Phase 2","narrative":"Added features.","date":"2026-03-12"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","title":"Phase 2","narrative":"Added features.","date":"2026-03-12"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -353,7 +357,7 @@ func TestAppendIterationExplicitNumber(t *testing.T) {
 	})
 
 	tool := NewAppendIterationTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","iteration":5,"title":"Jump Ahead","narrative":"Skipped some.","date":"2026-03-12"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","iteration":5,"title":"Jump Ahead","narrative":"Skipped some.","date":"2026-03-12"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -368,7 +372,7 @@ func TestAppendIterationCreatesFile(t *testing.T) {
 	})
 
 	tool := NewAppendIterationTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"testproj","title":"First","narrative":"Starting fresh.","date":"2026-03-12"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","title":"First","narrative":"Starting fresh.","date":"2026-03-12"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -392,7 +396,7 @@ func TestAppendIterationInvalidDate(t *testing.T) {
 	})
 
 	tool := NewAppendIterationTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"testproj","title":"Bad","narrative":"Nope.","date":"March 12"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","title":"Bad","narrative":"Nope.","date":"March 12"}`))
 	if err == nil {
 		t.Fatal("expected error for invalid date")
 	}
@@ -407,7 +411,7 @@ func TestAppendIterationDefaultDate(t *testing.T) {
 	})
 
 	tool := NewAppendIterationTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"testproj","title":"Today","narrative":"Using default date."}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","title":"Today","narrative":"Using default date."}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -438,7 +442,7 @@ func TestAppendIterationWithSummaryWritesFrontmatter(t *testing.T) {
 		"shape":     "fresh-feature",
 	}
 	payload, _ := json.Marshal(args)
-	if _, err := tool.Handler(payload); err != nil {
+	if _, err := tool.Handler(context.Background(), payload); err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
 	data, _ := os.ReadFile(filepath.Join(cfg.VaultPath, "Projects", "testproj", "agentctx", "iterations.md"))
@@ -465,7 +469,7 @@ func TestAppendIterationShapeOnlyFrontmatter(t *testing.T) {
 		"shape":     "planning",
 	}
 	payload, _ := json.Marshal(args)
-	if _, err := tool.Handler(payload); err != nil {
+	if _, err := tool.Handler(context.Background(), payload); err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
 	data, _ := os.ReadFile(filepath.Join(cfg.VaultPath, "Projects", "testproj", "agentctx", "iterations.md"))
@@ -492,7 +496,7 @@ func TestAppendIterationLegacyShape(t *testing.T) {
 		"date":      "2026-04-30",
 	}
 	payload, _ := json.Marshal(args)
-	if _, err := tool.Handler(payload); err != nil {
+	if _, err := tool.Handler(context.Background(), payload); err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
 	data, _ := os.ReadFile(filepath.Join(cfg.VaultPath, "Projects", "testproj", "agentctx", "iterations.md"))
@@ -525,12 +529,12 @@ func TestAppendIterationRoundTripsThroughSummary(t *testing.T) {
 		"shape":     "bookkeeping",
 	}
 	payload, _ := json.Marshal(args)
-	if _, err := appendTool.Handler(payload); err != nil {
+	if _, err := appendTool.Handler(context.Background(), payload); err != nil {
 		t.Fatalf("append handler: %v", err)
 	}
 
 	getTool := NewGetIterationsTool(cfg)
-	out, err := getTool.Handler(json.RawMessage(`{"project":"testproj","format":"summary"}`))
+	out, err := getTool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","format":"summary"}`))
 	if err != nil {
 		t.Fatalf("get handler: %v", err)
 	}
@@ -588,7 +592,7 @@ func TestManageTaskCreate(t *testing.T) {
 	content := "# New Task\nStatus: pending\nPriority: high\n\nDescription."
 	params := map[string]string{"project": "testproj", "task": "new-task", "action": "create", "content": content}
 	data, _ := json.Marshal(params)
-	result, err := tool.Handler(json.RawMessage(data))
+	result, err := tool.Handler(context.Background(), json.RawMessage(data))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -612,7 +616,7 @@ func TestManageTaskCreateAlreadyExists(t *testing.T) {
 	})
 
 	tool := NewManageTaskTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"testproj","task":"existing","action":"create","content":"stuff"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","task":"existing","action":"create","content":"stuff"}`))
 	if err == nil {
 		t.Fatal("expected error for existing task")
 	}
@@ -625,7 +629,7 @@ func TestManageTaskCreateNoContent(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewManageTaskTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"testproj","task":"no-content","action":"create"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","task":"no-content","action":"create"}`))
 	if err == nil {
 		t.Fatal("expected error for missing content")
 	}
@@ -640,7 +644,7 @@ func TestManageTaskUpdateStatus(t *testing.T) {
 	})
 
 	tool := NewManageTaskTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"testproj","task":"my-task","action":"update_status","status":"in-progress"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","task":"my-task","action":"update_status","status":"in-progress"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -661,7 +665,7 @@ func TestManageTaskUpdateStatusNoStatus(t *testing.T) {
 	})
 
 	tool := NewManageTaskTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"testproj","task":"my-task","action":"update_status"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","task":"my-task","action":"update_status"}`))
 	if err == nil {
 		t.Fatal("expected error for missing status")
 	}
@@ -674,7 +678,7 @@ func TestManageTaskUpdateStatusNotFound(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewManageTaskTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"testproj","task":"nonexistent","action":"update_status","status":"done"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","task":"nonexistent","action":"update_status","status":"done"}`))
 	if err == nil {
 		t.Fatal("expected error for missing task")
 	}
@@ -689,7 +693,7 @@ func TestManageTaskRetire(t *testing.T) {
 	})
 
 	tool := NewManageTaskTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","task":"old-task","action":"retire"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","task":"old-task","action":"retire"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -717,7 +721,7 @@ func TestManageTaskRetireNotFound(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewManageTaskTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"testproj","task":"nonexistent","action":"retire"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","task":"nonexistent","action":"retire"}`))
 	if err == nil {
 		t.Fatal("expected error for missing task")
 	}
@@ -730,7 +734,7 @@ func TestManageTaskUnknownAction(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewManageTaskTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"testproj","task":"my-task","action":"delete"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","task":"my-task","action":"delete"}`))
 	if err == nil {
 		t.Fatal("expected error for unknown action")
 	}
@@ -743,7 +747,7 @@ func TestManageTaskPathTraversal(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewManageTaskTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"testproj","task":"../../../etc/passwd","action":"create","content":"hack"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","task":"../../../etc/passwd","action":"create","content":"hack"}`))
 	if err == nil {
 		t.Fatal("expected error for path traversal")
 	}
@@ -757,7 +761,7 @@ func TestRefreshIndexBasic(t *testing.T) {
 	})
 
 	tool := NewRefreshIndexTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -781,7 +785,7 @@ func TestRefreshIndexEmptyVault(t *testing.T) {
 	})
 
 	tool := NewRefreshIndexTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -821,3 +825,40 @@ func TestReplaceStatusHeadingFormat(t *testing.T) {
 		t.Errorf("expected '## Status: done', got:\n%s", got)
 	}
 }
+
+func TestContextWriteTools_ConcurrentCallsAllLand(t *testing.T) {
+	resume := "# Resume\n\n## Current Focus\n\nold\n\n## Open Threads\n\nold\n\n## Decisions\n\nold\n"
+	cfg := writeTestVault(t, map[string]index.SessionEntry{}, map[string]string{
+		"Projects/testproj/agentctx/resume.md":     resume,
+		"Projects/testproj/agentctx/iterations.md": "# Iterations\n\n### Iteration 1 — Setup (2026-03-01)\n\nInitial setup.\n",
+	})
+	srv := NewServer(ServerInfo{Name: "test-server", Version: "0.1.0"}, log.New(io.Discard, "", 0))
+	srv.RegisterTool(NewUpdateResumeTool(cfg))
+	srv.RegisterTool(NewAppendIterationTool(cfg))
+	send, out, stop := servePipe(t, srv)
+
+	for i, section := range []string{"Current Focus", "Open Threads", "Decisions"} {
+		send(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"vv_update_resume","arguments":{"project":"testproj","section":%q,"content":"new %d"}}}`, i+1, section, i))
+	}
+	for i, title := range []string{"Alpha", "Beta"} {
+		send(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"vv_append_iteration","arguments":{"project":"testproj","title":%q,"narrative":"Did %s.","date":"2026-03-12"}}}`, i+10, title, title))
+	}
+	stop()
+	if strings.Contains(out.String(), `"isError":true`) {
+		t.Fatalf("a call failed:\n%s", out.String())
+	}
+
+	agentctx := filepath.Join(cfg.VaultPath, "Projects", "testproj", "agentctx")
+	data, _ := os.ReadFile(filepath.Join(agentctx, "resume.md"))
+	for i := range 3 {
+		if want := fmt.Sprintf("new %d", i); !strings.Contains(string(data), want) {
+			t.Errorf("resume.md lost update %q:\n%s", want, data)
+		}
+	}
+	data, _ = os.ReadFile(filepath.Join(agentctx, "iterations.md"))
+	for _, want := range []string{"### Iteration 2 — ", "### Iteration 3 — ", "Did Alpha.", "Did Beta."} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("iterations.md missing %q:\n%s", want, data)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/index"
+)
+
+// NewGetFileHistoryTool creates the vv_get_file_history tool: the file
+// dossier (sessions, commits, decisions, open threads, churn) that
+// `vv files dossier` prints. Meant to be called before editing a file.
+func NewGetFileHistoryTool(cfg config.Config) Tool {
+	return Tool{
+		Definition: ToolDef{
+			Name:        "vv_get_file_history",
+			Description: "Get the session history of a file before editing it: every session that changed it (summary, commits), decisions and open threads from those sessions or naming the file, and per-month churn. The path may be project-relative or a trailing part such as index/context.go.",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"path": {
+						"type": "string",
+						"description": "File path as recorded in session notes, e.g. internal/index/context.go."
+					},
+					"project": {
+						"type": "string",
+						"description": "Project name. If omitted, detected from working directory."
+					},
+					"max_sessions": {
+						"type": "integer",
+						"description": "Return only the most recent N sessions (default all)."
+					}
+				},
+				"required": ["path"]
+			}`),
+		},
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
+			var args struct {
+				Path        string `json:"path"`
+				Project     string `json:"project"`
+				MaxSessions int    `json:"max_sessions"`
+			}
+			if len(params) > 0 {
+				if err := json.Unmarshal(params, &args); err != nil {
+					return "", fmt.Errorf("invalid arguments: %w", err)
+				}
+			}
+			if args.Path == "" {
+				return "", fmt.Errorf("path is required")
+			}
+			project, err := resolveProject(args.Project)
+			if err != nil {
+				return "", err
+			}
+			idx, err := index.Load(cfg.StateDir())
+			if err != nil {
+				return "", fmt.Errorf("load index: %w", err)
+			}
+
+			d := idx.FileDossier(project, args.Path)
+			if args.MaxSessions > 0 && len(d.Sessions) > args.MaxSessions {
+				d.Sessions = d.Sessions[len(d.Sessions)-args.MaxSessions:]
+			}
+			if d.Sessions == nil {
+				d.Sessions = []index.FileSession{}
+			}
+
+			data, err := json.MarshalIndent(d, "", "  ")
+			if err != nil {
+				return "", fmt.Errorf("marshal: %w", err)
+			}
+			return string(data) + "\n", nil
+		},
+	}
+}
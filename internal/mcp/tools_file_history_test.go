@@ -0,0 +1,56 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/suykerbuyk/vibe-vault/internal/index"
+)
+
+func TestGetFileHistoryTool(t *testing.T) {
+	cfg := writeTestVault(t, map[string]index.SessionEntry{
+		"s1": {SessionID: "s1", Project: "proj", Date: "2026-03-01", Iteration: 1,
+			NotePath: "Projects/proj/sessions/2026-03-01-01.md", Summary: "First pass",
+			FilesChanged: []string{"internal/auth/token.go"}, Commits: []string{"aaa1111"}},
+		"s2": {SessionID: "s2", Project: "proj", Date: "2026-03-04", Iteration: 1,
+			NotePath: "Projects/proj/sessions/2026-03-04-01.md", Summary: "Refresh tokens",
+			FilesChanged: []string{"internal/auth/token.go"},
+			Decisions:    []string{"Rotate refresh tokens on use"}},
+		"s3": {SessionID: "s3", Project: "proj", Date: "2026-03-05", Iteration: 1,
+			NotePath:     "Projects/proj/sessions/2026-03-05-01.md",
+			FilesChanged: []string{"cmd/main.go"}},
+	}, nil)
+	tool := NewGetFileHistoryTool(cfg)
+
+	out, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"proj","path":"auth/token.go"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d index.FileDossier
+	if err := json.Unmarshal([]byte(out), &d); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if d.Path != "internal/auth/token.go" || len(d.Sessions) != 2 || d.Sessions[0].Commits[0] != "aaa1111" {
+		t.Errorf("dossier = %+v", d)
+	}
+	if len(d.Decisions) != 1 || d.Decisions[0].Note != "2026-03-04-01" {
+		t.Errorf("decisions = %+v", d.Decisions)
+	}
+
+	out, _ = tool.Handler(context.Background(), json.RawMessage(`{"project":"proj","path":"auth/token.go","max_sessions":1}`))
+	json.Unmarshal([]byte(out), &d)
+	if len(d.Sessions) != 1 || d.Sessions[0].Note != "2026-03-04-01" {
+		t.Errorf("max_sessions kept %+v", d.Sessions)
+	}
+
+	out, _ = tool.Handler(context.Background(), json.RawMessage(`{"project":"proj","path":"missing.go"}`))
+	if !json.Valid([]byte(out)) || !strings.Contains(out, `"sessions": []`) {
+		t.Errorf("unmatched file = %s", out)
+	}
+
+	if _, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"proj"}`)); err == nil {
+		t.Error("expected error without path")
+	}
+}
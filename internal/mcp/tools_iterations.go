@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -239,7 +240,7 @@ func NewGetIterationsTool(cfg config.Config) Tool {
 				}
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project        string `json:"project"`
 				Limit          *int   `json:"limit"`
@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -50,7 +51,7 @@ func TestAppendIterationIdempotent_HeadingAbsent(t *testing.T) {
 		"Projects/testproj/agentctx/iterations.md": "# Iterations\n",
 	})
 	tool := NewAppendIterationTool(cfg)
-	out, err := tool.Handler(json.RawMessage(
+	out, err := tool.Handler(context.Background(), json.RawMessage(
 		`{"project":"testproj","iteration":7,"title":"First","narrative":"Body line.","date":"2026-05-05"}`))
 	if err != nil {
 		t.Fatalf("handler: %v", err)
@@ -84,12 +85,12 @@ func TestAppendIterationIdempotent_BodyIdentical(t *testing.T) {
 	tool := NewAppendIterationTool(cfg)
 	args := `{"project":"testproj","iteration":12,"title":"Once","narrative":"The body line.","date":"2026-05-05","summary":"Short summary.","shape":"bookkeeping"}`
 
-	if _, err := tool.Handler(json.RawMessage(args)); err != nil {
+	if _, err := tool.Handler(context.Background(), json.RawMessage(args)); err != nil {
 		t.Fatalf("first handler: %v", err)
 	}
 	dataAfter1, _ := os.ReadFile(filepath.Join(cfg.VaultPath, "Projects", "testproj", "agentctx", "iterations.md"))
 
-	out2, err := tool.Handler(json.RawMessage(args))
+	out2, err := tool.Handler(context.Background(), json.RawMessage(args))
 	if err != nil {
 		t.Fatalf("second handler: %v", err)
 	}
@@ -124,10 +125,10 @@ func TestAppendIterationIdempotent_BodyDiffers(t *testing.T) {
 	args1 := `{"project":"testproj","iteration":42,"title":"Original","narrative":"First version body.","date":"2026-05-05"}`
 	args2 := `{"project":"testproj","iteration":42,"title":"Original","narrative":"REFINED version body, with new prose.","date":"2026-05-05"}`
 
-	if _, err := tool.Handler(json.RawMessage(args1)); err != nil {
+	if _, err := tool.Handler(context.Background(), json.RawMessage(args1)); err != nil {
 		t.Fatalf("first handler: %v", err)
 	}
-	out2, err := tool.Handler(json.RawMessage(args2))
+	out2, err := tool.Handler(context.Background(), json.RawMessage(args2))
 	if err != nil {
 		t.Fatalf("second handler: %v", err)
 	}
@@ -184,7 +185,7 @@ func TestAppendIterationIdempotent_RevisionCounterIncrement(t *testing.T) {
 			"narrative": narrative,
 			"date":      "2026-05-05",
 		})
-		out, err := tool.Handler(payload)
+		out, err := tool.Handler(context.Background(), payload)
 		if err != nil {
 			t.Fatalf("call %d handler: %v", i+1, err)
 		}
@@ -233,7 +234,7 @@ func TestAppendIterationIdempotent_TrailingNewlineNormalization(t *testing.T) {
 		"Projects/testproj/agentctx/iterations.md": "# Iterations\n",
 	})
 	tool := NewAppendIterationTool(cfg)
-	if _, err := tool.Handler(json.RawMessage(
+	if _, err := tool.Handler(context.Background(), json.RawMessage(
 		`{"project":"testproj","iteration":99,"title":"WS","narrative":"Body line.","date":"2026-05-05"}`)); err != nil {
 		t.Fatalf("first: %v", err)
 	}
@@ -241,7 +242,7 @@ func TestAppendIterationIdempotent_TrailingNewlineNormalization(t *testing.T) {
 	// Second call: same narrative but with extra trailing newlines —
 	// the writer strips trailing whitespace before storing, so the
 	// canonical body should still match.
-	out, err := tool.Handler(json.RawMessage(
+	out, err := tool.Handler(context.Background(), json.RawMessage(
 		`{"project":"testproj","iteration":99,"title":"WS","narrative":"Body line.\n\n\n","date":"2026-05-05"}`))
 	if err != nil {
 		t.Fatalf("second: %v", err)
@@ -273,11 +274,11 @@ func TestAppendIterationIdempotent_InternalWhitespaceDiffers(t *testing.T) {
 		"Projects/testproj/agentctx/iterations.md": "# Iterations\n",
 	})
 	tool := NewAppendIterationTool(cfg)
-	if _, err := tool.Handler(json.RawMessage(
+	if _, err := tool.Handler(context.Background(), json.RawMessage(
 		`{"project":"testproj","iteration":50,"title":"WS","narrative":"- bullet one\n  - nested","date":"2026-05-05"}`)); err != nil {
 		t.Fatalf("first: %v", err)
 	}
-	out, err := tool.Handler(json.RawMessage(
+	out, err := tool.Handler(context.Background(), json.RawMessage(
 		`{"project":"testproj","iteration":50,"title":"WS","narrative":"- bullet one\n    - nested","date":"2026-05-05"}`))
 	if err != nil {
 		t.Fatalf("second: %v", err)
@@ -317,7 +318,7 @@ func TestAppendIterationIdempotent_DownstreamParsersDedupeRevisions(t *testing.T
 		`{"project":"testproj","iteration":1,"title":"One","narrative":"v1 again.","date":"2026-05-05"}`,
 		`{"project":"testproj","iteration":2,"title":"Two","narrative":"v2.","date":"2026-05-05"}`,
 	} {
-		if _, err := tool.Handler(json.RawMessage(args)); err != nil {
+		if _, err := tool.Handler(context.Background(), json.RawMessage(args)); err != nil {
 			t.Fatalf("handler: %v", err)
 		}
 	}
@@ -334,7 +335,7 @@ func TestAppendIterationIdempotent_DownstreamParsersDedupeRevisions(t *testing.T
 	// vv_get_iterations table format must return exactly 2 entries
 	// (one per unique iteration number).
 	getTool := NewGetIterationsTool(cfg)
-	out, err := getTool.Handler(json.RawMessage(`{"project":"testproj","format":"table"}`))
+	out, err := getTool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","format":"table"}`))
 	if err != nil {
 		t.Fatalf("get table: %v", err)
 	}
@@ -358,7 +359,7 @@ func TestAppendIterationIdempotent_DownstreamParsersDedupeRevisions(t *testing.T
 	}
 
 	// vv_get_iterations summary format must also dedupe.
-	out, err = getTool.Handler(json.RawMessage(`{"project":"testproj","format":"summary"}`))
+	out, err = getTool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","format":"summary"}`))
 	if err != nil {
 		t.Fatalf("get summary: %v", err)
 	}
@@ -401,7 +402,7 @@ func TestAppendIterationIdempotent_ProvenanceTrailerStrippedFromCompare(t *testi
 		"Projects/testproj/agentctx/iterations.md": existing,
 	})
 	tool := NewAppendIterationTool(cfg)
-	out, err := tool.Handler(json.RawMessage(
+	out, err := tool.Handler(context.Background(), json.RawMessage(
 		`{"project":"testproj","iteration":88,"title":"Multi-host","narrative":"Shared body.","date":"2026-05-05"}`))
 	if err != nil {
 		t.Fatalf("handler: %v", err)
@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 	"testing"
@@ -60,7 +61,7 @@ func TestGetIterationsBasic(t *testing.T) {
 	})
 
 	tool := NewGetIterationsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -91,7 +92,7 @@ func TestGetIterationsTableFormatOmitsNarrativeInJSON(t *testing.T) {
 		"Projects/testproj/agentctx/iterations.md": fixtureIterations,
 	})
 	tool := NewGetIterationsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","format":"table"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","format":"table"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -108,7 +109,7 @@ func TestGetIterationsFullFormat(t *testing.T) {
 		"Projects/testproj/agentctx/iterations.md": fixtureIterations,
 	})
 	tool := NewGetIterationsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","format":"full","limit":1}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","format":"full","limit":1}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -126,7 +127,7 @@ func TestGetIterationsLimit(t *testing.T) {
 		"Projects/testproj/agentctx/iterations.md": fixtureIterations,
 	})
 	tool := NewGetIterationsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","limit":2}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","limit":2}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -148,7 +149,7 @@ func TestGetIterationsSinceIteration(t *testing.T) {
 		"Projects/testproj/agentctx/iterations.md": fixtureIterations,
 	})
 	tool := NewGetIterationsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","since_iteration":3}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","since_iteration":3}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -171,7 +172,7 @@ func TestGetIterationsSinceIterationWithLimit(t *testing.T) {
 		"Projects/testproj/agentctx/iterations.md": fixtureIterations,
 	})
 	tool := NewGetIterationsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","since_iteration":2,"limit":2}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","since_iteration":2,"limit":2}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -189,7 +190,7 @@ func TestGetIterationsEmDashInTitle(t *testing.T) {
 		"Projects/testproj/agentctx/iterations.md": fixtureIterations,
 	})
 	tool := NewGetIterationsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","since_iteration":3,"limit":1}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","since_iteration":3,"limit":1}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -197,7 +198,7 @@ func TestGetIterationsEmDashInTitle(t *testing.T) {
 	// Iter 3 title is "Third thing with — an em dash" — the em dash inside
 	// the title must not be swallowed by the regex separator.
 	// But since=3,limit=1 returns iter 5 (newest-first of 3,4,5). Re-query for iter 3:
-	result, err = tool.Handler(json.RawMessage(`{"project":"testproj","since_iteration":3,"limit":3}`))
+	result, err = tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","since_iteration":3,"limit":3}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -223,7 +224,7 @@ func TestGetIterationsEmDashInTitle(t *testing.T) {
 func TestGetIterationsMissing(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 	tool := NewGetIterationsTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"noproject"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"noproject"}`))
 	if err == nil {
 		t.Fatal("expected error for missing iterations.md")
 	}
@@ -235,7 +236,7 @@ func TestGetIterationsMissing(t *testing.T) {
 func TestGetIterationsPathTraversal(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 	tool := NewGetIterationsTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"../../etc"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"../../etc"}`))
 	if err == nil {
 		t.Fatal("expected error for path traversal")
 	}
@@ -249,7 +250,7 @@ func TestGetIterationsInvalidFormat(t *testing.T) {
 		"Projects/testproj/agentctx/iterations.md": fixtureIterations,
 	})
 	tool := NewGetIterationsTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"testproj","format":"csv"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","format":"csv"}`))
 	if err == nil {
 		t.Fatal("expected error for invalid format")
 	}
@@ -263,7 +264,7 @@ func TestGetIterationsInvalidLimit(t *testing.T) {
 		"Projects/testproj/agentctx/iterations.md": fixtureIterations,
 	})
 	tool := NewGetIterationsTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"testproj","limit":0}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","limit":0}`))
 	if err == nil {
 		t.Fatal("expected error for limit=0")
 	}
@@ -293,7 +294,7 @@ Body.
 		"Projects/testproj/agentctx/iterations.md": content,
 	})
 	tool := NewGetIterationsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","format":"full"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","format":"full"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -630,7 +631,7 @@ Body.
 	})
 	tool := NewGetIterationsTool(cfg)
 	for _, format := range []string{"table", "full"} {
-		result, err := tool.Handler(json.RawMessage(`{"project":"testproj","format":"` + format + `"}`))
+		result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","format":"`+format+`"}`))
 		if err != nil {
 			t.Fatalf("handler error (format=%s): %v", format, err)
 		}
@@ -678,7 +679,7 @@ Body that should NOT appear in summary.
 		"Projects/testproj/agentctx/iterations.md": content,
 	})
 	tool := NewGetIterationsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","format":"summary"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","format":"summary"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -713,7 +714,7 @@ Second paragraph is ignored by the fallback.
 		"Projects/testproj/agentctx/iterations.md": content,
 	})
 	tool := NewGetIterationsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","format":"summary"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","format":"summary"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -745,7 +746,7 @@ func TestGetIterationsSummaryTruncatesAtWordBoundary(t *testing.T) {
 		"Projects/testproj/agentctx/iterations.md": content,
 	})
 	tool := NewGetIterationsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","format":"summary"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","format":"summary"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -781,7 +782,7 @@ func TestGetIterationsSummaryStripsBoldPrefix(t *testing.T) {
 		"Projects/testproj/agentctx/iterations.md": content,
 	})
 	tool := NewGetIterationsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","format":"summary"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","format":"summary"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -816,7 +817,7 @@ Real prose paragraph.
 		"Projects/testproj/agentctx/iterations.md": content,
 	})
 	tool := NewGetIterationsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","format":"summary"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","format":"summary"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -838,7 +839,7 @@ func TestGetIterationsSummaryHandlesCodeFence(t *testing.T) {
 		"Projects/testproj/agentctx/iterations.md": content,
 	})
 	tool := NewGetIterationsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","format":"summary"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","format":"summary"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -860,7 +861,7 @@ func TestGetIterationsSummaryEmptyBody(t *testing.T) {
 		"Projects/testproj/agentctx/iterations.md": content,
 	})
 	tool := NewGetIterationsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","format":"summary"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","format":"summary"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -881,7 +882,7 @@ func TestGetIterationsSummaryOnlyTrailer(t *testing.T) {
 		"Projects/testproj/agentctx/iterations.md": content,
 	})
 	tool := NewGetIterationsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","format":"summary"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","format":"summary"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -903,7 +904,7 @@ func TestGetIterationsSummarySinceFilter(t *testing.T) {
 		"Projects/testproj/agentctx/iterations.md": fixtureIterations,
 	})
 	tool := NewGetIterationsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","format":"summary","since_iteration":3}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","format":"summary","since_iteration":3}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -924,7 +925,7 @@ func TestGetIterationsSummaryLimit(t *testing.T) {
 		"Projects/testproj/agentctx/iterations.md": fixtureIterations,
 	})
 	tool := NewGetIterationsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj","format":"summary","limit":2}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj","format":"summary","limit":2}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -943,7 +944,7 @@ func TestGetIterationsEmptyVault(t *testing.T) {
 		"Projects/testproj/agentctx/iterations.md": "# No iterations yet\n",
 	})
 	tool := NewGetIterationsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"testproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"testproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -10,6 +10,7 @@ package mcp
 // `tier` parameter and no `[wrap.tiers]` lookup.
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -138,7 +139,7 @@ func NewPreflightWrapTool(cfg config.Config) Tool {
 				}
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project string `json:"project"`
 			}
@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -39,7 +40,7 @@ func TestPreflightWrap_AllClean(t *testing.T) {
 	withSurfaceCheck(t, func(_ string) error { return nil })
 
 	tool := NewPreflightWrapTool(cfg)
-	out, err := tool.Handler(json.RawMessage(`{}`))
+	out, err := tool.Handler(context.Background(), json.RawMessage(`{}`))
 	if err != nil {
 		t.Fatalf("handler: %v", err)
 	}
@@ -83,7 +84,7 @@ func TestPreflightWrap_VaultDirty(t *testing.T) {
 	withSurfaceCheck(t, func(_ string) error { return nil })
 
 	tool := NewPreflightWrapTool(cfg)
-	out, err := tool.Handler(json.RawMessage(`{"project":"myproj"}`))
+	out, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"myproj"}`))
 	if err != nil {
 		t.Fatalf("handler: %v", err)
 	}
@@ -127,7 +128,7 @@ func TestPreflightWrap_OtherProjectDirty_NoFalsePositive(t *testing.T) {
 	withSurfaceCheck(t, func(_ string) error { return nil })
 
 	tool := NewPreflightWrapTool(cfg)
-	out, err := tool.Handler(json.RawMessage(`{"project":"myproj"}`))
+	out, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"myproj"}`))
 	if err != nil {
 		t.Fatalf("handler: %v", err)
 	}
@@ -163,7 +164,7 @@ func TestPreflightWrap_ProjectDirty(t *testing.T) {
 	withSurfaceCheck(t, func(_ string) error { return nil })
 
 	tool := NewPreflightWrapTool(cfg)
-	out, err := tool.Handler(json.RawMessage(`{}`))
+	out, err := tool.Handler(context.Background(), json.RawMessage(`{}`))
 	if err != nil {
 		t.Fatalf("handler: %v", err)
 	}
@@ -208,7 +209,7 @@ func TestPreflightWrap_BothDirty(t *testing.T) {
 	withSurfaceCheck(t, func(_ string) error { return nil })
 
 	tool := NewPreflightWrapTool(cfg)
-	out, err := tool.Handler(json.RawMessage(`{"project":"myproj"}`))
+	out, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"myproj"}`))
 	if err != nil {
 		t.Fatalf("handler: %v", err)
 	}
@@ -257,7 +258,7 @@ func TestPreflightWrap_SurfaceFail(t *testing.T) {
 	})
 
 	tool := NewPreflightWrapTool(cfg)
-	out, err := tool.Handler(json.RawMessage(`{}`))
+	out, err := tool.Handler(context.Background(), json.RawMessage(`{}`))
 	if err != nil {
 		t.Fatalf("handler: %v", err)
 	}
@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -30,7 +31,7 @@ func TestGetProjectRoot_GitDir(t *testing.T) {
 	tool := NewGetProjectRootTool(cfg)
 
 	params, _ := json.Marshal(map[string]string{"cwd": sub})
-	result, err := tool.Handler(params)
+	result, err := tool.Handler(context.Background(), params)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -59,7 +60,7 @@ func TestGetProjectRoot_AgentctxDir(t *testing.T) {
 	tool := NewGetProjectRootTool(cfg)
 
 	params, _ := json.Marshal(map[string]string{"cwd": sub})
-	result, err := tool.Handler(params)
+	result, err := tool.Handler(context.Background(), params)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -85,7 +86,7 @@ func TestGetProjectRoot_VaultRootRefused(t *testing.T) {
 
 	tool := NewGetProjectRootTool(cfg)
 	params, _ := json.Marshal(map[string]string{"cwd": vaultRoot})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil {
 		t.Fatal("expected error for vault root, got nil")
 	}
@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -21,7 +22,7 @@ func NewRenderWrapTextTool() Tool {
 			Description: "[RETIRED in v15] Run `vv context sync` to update your project's wrap.md. The orchestrator now writes iter narratives inline. See DESIGN #104.",
 			InputSchema: json.RawMessage(`{"type": "object"}`),
 		},
-		Handler: func(_ json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, _ json.RawMessage) (string, error) {
 			return "", fmt.Errorf(
 				"vv_render_wrap_text was retired in MCP surface v15 (DESIGN #104); " +
 					"the orchestrator now writes iter narratives inline; " +
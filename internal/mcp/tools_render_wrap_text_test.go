@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 	"testing"
@@ -11,7 +12,7 @@ import (
 
 func TestRenderWrapText_DeprecationShimErrors(t *testing.T) {
 	tool := NewRenderWrapTextTool()
-	_, err := tool.Handler(json.RawMessage(`{}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{}`))
 	if err == nil {
 		t.Fatal("expected error from deprecation shim, got nil")
 	}
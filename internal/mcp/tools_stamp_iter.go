@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -55,7 +56,7 @@ func NewStampIterTool(cfg config.Config) Tool {
 				"required": ["project_path", "iter"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project     string `json:"project"`
 				ProjectPath string `json:"project_path"`
@@ -123,6 +124,7 @@ func NewStampIterTool(cfg config.Config) Tool {
 			}
 			return string(out) + "\n", nil
 		},
+		Exclusive: true,
 	}
 }
 
@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -34,7 +35,7 @@ func TestStampIter_Basic(t *testing.T) {
 	projectRoot := t.TempDir()
 
 	tool := NewStampIterTool(cfg)
-	result, err := tool.Handler(stampIterArgs(t, "myproject", projectRoot, 7))
+	result, err := tool.Handler(context.Background(), stampIterArgs(t, "myproject", projectRoot, 7))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -69,10 +70,10 @@ func TestStampIter_Idempotent(t *testing.T) {
 	projectRoot := t.TempDir()
 
 	tool := NewStampIterTool(cfg)
-	if _, err := tool.Handler(stampIterArgs(t, "myproject", projectRoot, 12)); err != nil {
+	if _, err := tool.Handler(context.Background(), stampIterArgs(t, "myproject", projectRoot, 12)); err != nil {
 		t.Fatalf("first call: %v", err)
 	}
-	if _, err := tool.Handler(stampIterArgs(t, "myproject", projectRoot, 12)); err != nil {
+	if _, err := tool.Handler(context.Background(), stampIterArgs(t, "myproject", projectRoot, 12)); err != nil {
 		t.Fatalf("second call: %v", err)
 	}
 
@@ -90,7 +91,7 @@ func TestStampIter_RejectsZero(t *testing.T) {
 	projectRoot := t.TempDir()
 
 	tool := NewStampIterTool(cfg)
-	_, err := tool.Handler(stampIterArgs(t, "myproject", projectRoot, 0))
+	_, err := tool.Handler(context.Background(), stampIterArgs(t, "myproject", projectRoot, 0))
 	if err == nil {
 		t.Fatal("expected error for iter=0")
 	}
@@ -104,7 +105,7 @@ func TestStampIter_RejectsNegative(t *testing.T) {
 	projectRoot := t.TempDir()
 
 	tool := NewStampIterTool(cfg)
-	_, err := tool.Handler(stampIterArgs(t, "myproject", projectRoot, -3))
+	_, err := tool.Handler(context.Background(), stampIterArgs(t, "myproject", projectRoot, -3))
 	if err == nil {
 		t.Fatal("expected error for negative iter")
 	}
@@ -121,7 +122,7 @@ func TestStampIter_RejectsMissingProjectPath(t *testing.T) {
 		"project": "myproject",
 		"iter":    1,
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil {
 		t.Fatal("expected error for missing project_path")
 	}
@@ -134,7 +135,7 @@ func TestStampIter_RejectsRelativeProjectPath(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewStampIterTool(cfg)
-	_, err := tool.Handler(stampIterArgs(t, "myproject", "relative/path", 1))
+	_, err := tool.Handler(context.Background(), stampIterArgs(t, "myproject", "relative/path", 1))
 	if err == nil {
 		t.Fatal("expected error for relative project_path")
 	}
@@ -154,7 +155,7 @@ func TestStampIter_CreatesParentDir(t *testing.T) {
 	}
 
 	tool := NewStampIterTool(cfg)
-	if _, err := tool.Handler(stampIterArgs(t, "myproject", projectRoot, 4)); err != nil {
+	if _, err := tool.Handler(context.Background(), stampIterArgs(t, "myproject", projectRoot, 4)); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
@@ -184,7 +185,7 @@ func TestStampIter_AtomicReplace(t *testing.T) {
 	}
 
 	tool := NewStampIterTool(cfg)
-	if _, err := tool.Handler(stampIterArgs(t, "myproject", projectRoot, 100)); err != nil {
+	if _, err := tool.Handler(context.Background(), stampIterArgs(t, "myproject", projectRoot, 100)); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
@@ -238,7 +239,7 @@ func TestStampIter_WritesTasksSnapshot(t *testing.T) {
 
 	projectRoot := t.TempDir()
 	tool := NewStampIterTool(cfg)
-	resultJSON, err := tool.Handler(stampIterArgs(t, "myproj", projectRoot, 218))
+	resultJSON, err := tool.Handler(context.Background(), stampIterArgs(t, "myproj", projectRoot, 218))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -298,7 +299,7 @@ func TestStampIter_SnapshotMissingTasksDir(t *testing.T) {
 	projectRoot := t.TempDir()
 
 	tool := NewStampIterTool(cfg)
-	if _, err := tool.Handler(stampIterArgs(t, "bootstrap-proj", projectRoot, 1)); err != nil {
+	if _, err := tool.Handler(context.Background(), stampIterArgs(t, "bootstrap-proj", projectRoot, 1)); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
@@ -334,7 +335,7 @@ func TestStampIter_ContentExactlyIntegerNewline(t *testing.T) {
 	for _, tc := range cases {
 		projectRoot := t.TempDir()
 		tool := NewStampIterTool(cfg)
-		if _, err := tool.Handler(stampIterArgs(t, "myproject", projectRoot, tc.iter)); err != nil {
+		if _, err := tool.Handler(context.Background(), stampIterArgs(t, "myproject", projectRoot, tc.iter)); err != nil {
 			t.Fatalf("iter=%d: unexpected error: %v", tc.iter, err)
 		}
 		data, err := os.ReadFile(filepath.Join(projectRoot, ".vibe-vault", "last-iter"))
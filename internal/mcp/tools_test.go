@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -87,7 +88,7 @@ func TestGetProjectContextBasic(t *testing.T) {
 	})
 
 	tool := NewGetProjectContextTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"myproject"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"myproject"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -118,7 +119,7 @@ func TestGetProjectContextWithSections(t *testing.T) {
 	})
 
 	tool := NewGetProjectContextTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"proj","sections":["summary","sessions"]}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"proj","sections":["summary","sessions"]}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -138,7 +139,7 @@ func TestGetProjectContextEmptyIndex(t *testing.T) {
 	cfg := writeTestIndex(t, map[string]index.SessionEntry{})
 
 	tool := NewGetProjectContextTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"empty"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"empty"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -160,7 +161,7 @@ func TestGetProjectContextDefaultMaxTokens(t *testing.T) {
 
 	tool := NewGetProjectContextTool(cfg)
 	// Call without max_tokens — should use config default
-	_, err := tool.Handler(json.RawMessage(`{"project":"p"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"p"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -174,7 +175,7 @@ func TestListProjectsBasic(t *testing.T) {
 	})
 
 	tool := NewListProjectsTool(cfg)
-	result, err := tool.Handler(nil)
+	result, err := tool.Handler(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -202,7 +203,7 @@ func TestListProjectsEmptyIndex(t *testing.T) {
 	cfg := writeTestIndex(t, map[string]index.SessionEntry{})
 
 	tool := NewListProjectsTool(cfg)
-	result, err := tool.Handler(nil)
+	result, err := tool.Handler(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -225,7 +226,7 @@ func TestSearchSessionsQueryFilter(t *testing.T) {
 	})
 
 	tool := NewSearchSessionsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"query":"oauth"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"query":"oauth"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -247,7 +248,7 @@ func TestSearchSessionsProjectFilter(t *testing.T) {
 	})
 
 	tool := NewSearchSessionsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"alpha"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"alpha"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -267,7 +268,7 @@ func TestSearchSessionsDateFilter(t *testing.T) {
 	})
 
 	tool := NewSearchSessionsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"date_from":"2027-06-09","date_to":"2027-06-12"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"date_from":"2027-06-09","date_to":"2027-06-12"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -289,7 +290,7 @@ func TestSearchSessionsFrictionFilter(t *testing.T) {
 	})
 
 	tool := NewSearchSessionsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"min_friction":30}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"min_friction":30}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -308,7 +309,7 @@ func TestSearchSessionsFileFilter(t *testing.T) {
 	})
 
 	tool := NewSearchSessionsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"files":["*.go"]}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"files":["*.go"]}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -335,7 +336,7 @@ func TestSearchSessionsMaxResults(t *testing.T) {
 	cfg := writeTestIndex(t, entries)
 
 	tool := NewSearchSessionsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"max_results":5}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"max_results":5}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -351,7 +352,7 @@ func TestSearchSessionsEmpty(t *testing.T) {
 	cfg := writeTestIndex(t, map[string]index.SessionEntry{})
 
 	tool := NewSearchSessionsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"query":"nothing"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"query":"nothing"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -371,7 +372,7 @@ func TestSearchSessionsCombinedFilters(t *testing.T) {
 	})
 
 	tool := NewSearchSessionsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"query":"oauth","project":"alpha","min_friction":30}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"query":"oauth","project":"alpha","min_friction":30}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -394,7 +395,7 @@ func TestGetKnowledgeBasic(t *testing.T) {
 	})
 
 	tool := NewGetKnowledgeTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"myproj"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"myproj"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -407,7 +408,7 @@ func TestGetKnowledgeMissing(t *testing.T) {
 	cfg := writeTestIndex(t, map[string]index.SessionEntry{})
 
 	tool := NewGetKnowledgeTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"nonexistent"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"nonexistent"}`))
 	if err == nil {
 		t.Fatal("expected error for missing knowledge.md")
 	}
@@ -420,7 +421,7 @@ func TestGetKnowledgePathTraversal(t *testing.T) {
 	cfg := writeTestIndex(t, map[string]index.SessionEntry{})
 
 	tool := NewGetKnowledgeTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"../../etc"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"../../etc"}`))
 	if err == nil {
 		t.Fatal("expected error for path traversal")
 	}
@@ -433,7 +434,7 @@ func TestGetKnowledgeEmptyProject(t *testing.T) {
 	cfg := writeTestIndex(t, map[string]index.SessionEntry{})
 
 	tool := NewGetKnowledgeTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":""}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":""}`))
 	if err == nil {
 		t.Fatal("expected error for empty project")
 	}
@@ -455,7 +456,7 @@ func TestGetSessionDetailBasic(t *testing.T) {
 	})
 
 	tool := NewGetSessionDetailTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"myproj","date":"2027-06-15"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"myproj","date":"2027-06-15"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -481,7 +482,7 @@ func TestGetSessionDetailIteration(t *testing.T) {
 	})
 
 	tool := NewGetSessionDetailTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"myproj","date":"2027-06-15","iteration":2}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"myproj","date":"2027-06-15","iteration":2}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -494,7 +495,7 @@ func TestGetSessionDetailMissing(t *testing.T) {
 	cfg := writeTestIndex(t, map[string]index.SessionEntry{})
 
 	tool := NewGetSessionDetailTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"p","date":"2027-06-15"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"p","date":"2027-06-15"}`))
 	if err == nil {
 		t.Fatal("expected error for missing session")
 	}
@@ -504,7 +505,7 @@ func TestGetSessionDetailPathTraversal(t *testing.T) {
 	cfg := writeTestIndex(t, map[string]index.SessionEntry{})
 
 	tool := NewGetSessionDetailTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"../../etc","date":"2027-06-15"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"../../etc","date":"2027-06-15"}`))
 	if err == nil {
 		t.Fatal("expected error for path traversal")
 	}
@@ -514,7 +515,7 @@ func TestGetSessionDetailBadDate(t *testing.T) {
 	cfg := writeTestIndex(t, map[string]index.SessionEntry{})
 
 	tool := NewGetSessionDetailTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{"project":"p","date":"not-a-date"}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"p","date":"not-a-date"}`))
 	if err == nil {
 		t.Fatal("expected error for bad date format")
 	}
@@ -542,7 +543,7 @@ func TestGetSessionDetail_Tiebreaker(t *testing.T) {
 	})
 
 	tool := NewGetSessionDetailTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"p","date":"2026-05-02","iteration":1}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"p","date":"2026-05-02","iteration":1}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -585,7 +586,7 @@ func TestGetSessionDetail_MixedFormats(t *testing.T) {
 	}
 	for _, tc := range cases {
 		params := fmt.Sprintf(`{"project":"p","date":"2026-05-02","iteration":%d}`, tc.iter)
-		got, err := tool.Handler(json.RawMessage(params))
+		got, err := tool.Handler(context.Background(), json.RawMessage(params))
 		if err != nil {
 			t.Fatalf("iter=%d handler error: %v", tc.iter, err)
 		}
@@ -604,7 +605,7 @@ func TestGetFrictionTrendsBasic(t *testing.T) {
 	})
 
 	tool := NewGetFrictionTrendsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"p"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"p"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -625,7 +626,7 @@ func TestGetFrictionTrendsEmpty(t *testing.T) {
 	cfg := writeTestIndex(t, map[string]index.SessionEntry{})
 
 	tool := NewGetFrictionTrendsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -643,7 +644,7 @@ func TestGetFrictionTrendsCustomWeeks(t *testing.T) {
 	})
 
 	tool := NewGetFrictionTrendsTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"weeks":4}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"weeks":4}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -667,7 +668,7 @@ func TestGetEffectivenessBasic(t *testing.T) {
 	})
 
 	tool := NewGetEffectivenessTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"project":"p"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"project":"p"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -700,7 +701,7 @@ func TestGetEffectivenessEmpty(t *testing.T) {
 	cfg := writeTestIndex(t, map[string]index.SessionEntry{})
 
 	tool := NewGetEffectivenessTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -726,7 +727,7 @@ func TestCaptureSessionTool_Success(t *testing.T) {
 	withNilClaim(t)
 
 	tool := NewCaptureSessionTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{
 		"summary": "Implemented OAuth login flow with Google provider.",
 		"title": "OAuth login implementation",
 		"tag": "implementation",
@@ -783,7 +784,7 @@ func TestCaptureSessionTool_MissingSummary(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewCaptureSessionTool(cfg)
-	_, err := tool.Handler(json.RawMessage(`{}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{}`))
 	if err == nil {
 		t.Fatal("expected error for missing summary")
 	}
@@ -796,7 +797,7 @@ func TestCaptureSessionTool_MinimalInput(t *testing.T) {
 	cfg := writeTestVault(t, map[string]index.SessionEntry{}, nil)
 
 	tool := NewCaptureSessionTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"summary": "Fixed a bug in the login flow."}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"summary": "Fixed a bug in the login flow."}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -893,7 +894,7 @@ func TestCaptureSessionTool_SourceFlip_ClaudeCode(t *testing.T) {
 	}, nil)
 
 	tool := NewCaptureSessionTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"summary": "test claude-code source flip"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"summary": "test claude-code source flip"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -926,7 +927,7 @@ func TestCaptureSessionTool_SourceFlip_ZedMCP(t *testing.T) {
 	}, nil)
 
 	tool := NewCaptureSessionTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"summary": "test zed-mcp source flip"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"summary": "test zed-mcp source flip"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -956,7 +957,7 @@ func TestCaptureSessionTool_NilClaimFallback(t *testing.T) {
 	setSessionclaimSeam(t, nil, errors.New("simulated I/O failure"))
 
 	tool := NewCaptureSessionTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{"summary": "test H6 fallback"}`))
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{"summary": "test H6 fallback"}`))
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -997,7 +998,7 @@ func TestCaptureSessionTool_Phase2_StagingRoute(t *testing.T) {
 	withNilClaim(t)
 
 	tool := NewCaptureSessionTool(cfg)
-	result, err := tool.Handler(json.RawMessage(`{
+	result, err := tool.Handler(context.Background(), json.RawMessage(`{
 		"summary": "Phase 2 staging route smoke test."
 	}`))
 	if err != nil {
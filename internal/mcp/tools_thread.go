@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -120,7 +121,7 @@ func NewThreadInsertTool(cfg config.Config) Tool {
 				"required": ["position", "slug", "body"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project  string `json:"project"`
 				Position struct {
@@ -170,6 +171,7 @@ func NewThreadInsertTool(cfg config.Config) Tool {
 			}
 			return writeResume(cfg, absPath, project, updated, args.Slug, posLabel)
 		},
+		Exclusive: true,
 	}
 }
 
@@ -200,7 +202,7 @@ func NewThreadReplaceTool(cfg config.Config) Tool {
 				"required": ["slug", "body"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project string `json:"project"`
 				Slug    string `json:"slug"`
@@ -240,6 +242,7 @@ func NewThreadReplaceTool(cfg config.Config) Tool {
 			}
 			return writeResume(cfg, absPath, project, updated, args.Slug, "")
 		},
+		Exclusive: true,
 	}
 }
 
@@ -265,7 +268,7 @@ func NewThreadRemoveTool(cfg config.Config) Tool {
 				"required": ["slug"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Project string `json:"project"`
 				Slug    string `json:"slug"`
@@ -299,5 +302,6 @@ func NewThreadRemoveTool(cfg config.Config) Tool {
 			}
 			return writeResume(cfg, absPath, project, updated, args.Slug, "")
 		},
+		Exclusive: true,
 	}
 }
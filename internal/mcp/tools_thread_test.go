@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -62,7 +63,7 @@ func TestThreadInsert_ModeTop(t *testing.T) {
 		"slug":     "new-thread",
 		"body":     "new thread body",
 	})
-	result, err := tool.Handler(params)
+	result, err := tool.Handler(context.Background(), params)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -97,7 +98,7 @@ func TestThreadInsert_ModeBottom(t *testing.T) {
 		"slug":     "new-thread",
 		"body":     "new thread body",
 	})
-	result, err := tool.Handler(params)
+	result, err := tool.Handler(context.Background(), params)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -129,7 +130,7 @@ func TestThreadInsert_ModeAfter(t *testing.T) {
 		"slug":     "middle",
 		"body":     "middle body",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -154,7 +155,7 @@ func TestThreadInsert_SlugAlreadyExists(t *testing.T) {
 		"slug":     "alpha",
 		"body":     "dup body",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil || !strings.Contains(err.Error(), "already exists") {
 		t.Fatalf("want already-exists error, got %v", err)
 	}
@@ -171,7 +172,7 @@ func TestThreadInsert_MissingSlug(t *testing.T) {
 		"slug":     "",
 		"body":     "body",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil || !strings.Contains(err.Error(), "slug is required") {
 		t.Fatalf("want slug-required error, got %v", err)
 	}
@@ -186,7 +187,7 @@ func TestThreadInsert_ProjectNotFound(t *testing.T) {
 		"slug":     "new",
 		"body":     "body",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil {
 		t.Fatal("want error for missing project")
 	}
@@ -204,7 +205,7 @@ func TestThreadReplace_Basic(t *testing.T) {
 		"slug":    "alpha",
 		"body":    "updated alpha body",
 	})
-	result, err := tool.Handler(params)
+	result, err := tool.Handler(context.Background(), params)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -237,7 +238,7 @@ func TestThreadReplace_SlugWithEmDash(t *testing.T) {
 		"slug":    "beta",
 		"body":    "updated beta body",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -257,7 +258,7 @@ func TestThreadReplace_SlugNotFound(t *testing.T) {
 		"slug":    "ghost",
 		"body":    "body",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil || !strings.Contains(err.Error(), "ghost") {
 		t.Fatalf("want not-found error, got %v", err)
 	}
@@ -277,7 +278,7 @@ func TestThreadReplace_CarriedForwardRejected(t *testing.T) {
 		"slug":    "Carried forward",
 		"body":    "new bullets",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil || !strings.Contains(err.Error(), "refusing to replace") {
 		t.Fatalf("want carried-forward rejection, got %v", err)
 	}
@@ -308,7 +309,7 @@ body2
 		"body":    "new body",
 	})
 	// Direction-C D9: multi-match is now a hard error.
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil {
 		t.Fatal("multi-match should return hard error")
 	}
@@ -346,7 +347,7 @@ alpha body
 		"slug":    "alpha",
 		"body":    "new body",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil || !strings.Contains(err.Error(), "marker preservation") {
 		t.Fatalf("want marker-preservation error, got %v", err)
 	}
@@ -361,7 +362,7 @@ func TestThreadReplace_MissingSlug(t *testing.T) {
 		"project": "proj",
 		"body":    "body",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil || !strings.Contains(err.Error(), "slug is required") {
 		t.Fatalf("want slug-required error, got %v", err)
 	}
@@ -378,7 +379,7 @@ func TestThreadRemove_Basic(t *testing.T) {
 		"project": "proj",
 		"slug":    "alpha",
 	})
-	result, err := tool.Handler(params)
+	result, err := tool.Handler(context.Background(), params)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -408,7 +409,7 @@ func TestThreadRemove_CarriedForwardRejected(t *testing.T) {
 		"project": "proj",
 		"slug":    "Carried forward",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil || !strings.Contains(err.Error(), "refusing to remove") {
 		t.Fatalf("want carried-forward rejection, got %v", err)
 	}
@@ -423,7 +424,7 @@ func TestThreadRemove_SlugNotFound(t *testing.T) {
 		"project": "proj",
 		"slug":    "ghost",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil || !strings.Contains(err.Error(), "ghost") {
 		t.Fatalf("want not-found error, got %v", err)
 	}
@@ -453,7 +454,7 @@ body2
 		"slug":    "dup",
 	})
 	// Direction-C D9: multi-match is now a hard error.
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil {
 		t.Fatal("multi-match should return hard error")
 	}
@@ -475,7 +476,7 @@ func TestThreadRemove_MissingSlug(t *testing.T) {
 	params, _ := json.Marshal(map[string]any{
 		"project": "proj",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil || !strings.Contains(err.Error(), "slug is required") {
 		t.Fatalf("want slug-required error, got %v", err)
 	}
@@ -490,7 +491,7 @@ func TestThreadRemove_EmptyOpenThreads(t *testing.T) {
 		"project": "proj",
 		"slug":    "ghost",
 	})
-	_, err := tool.Handler(params)
+	_, err := tool.Handler(context.Background(), params)
 	if err == nil {
 		t.Fatal("want error for empty Open Threads section")
 	}
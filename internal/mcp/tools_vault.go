@@ -18,6 +18,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -57,7 +58,7 @@ func NewVaultReadTool(cfg config.Config) Tool {
 				"required": ["path"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Path     string `json:"path"`
 				MaxBytes int64  `json:"max_bytes"`
@@ -108,7 +109,7 @@ func NewVaultListTool(cfg config.Config) Tool {
 				"required": ["path"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Path          string `json:"path"`
 				IncludeSha256 bool   `json:"include_sha256"`
@@ -158,7 +159,7 @@ func NewVaultExistsTool(cfg config.Config) Tool {
 				"required": ["path"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Path string `json:"path"`
 			}
@@ -203,7 +204,7 @@ func NewVaultSha256Tool(cfg config.Config) Tool {
 				"required": ["path"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Path string `json:"path"`
 			}
@@ -260,7 +261,7 @@ func NewVaultWriteTool(cfg config.Config) Tool {
 				"required": ["path", "content"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Path           string `json:"path"`
 				Content        string `json:"content"`
@@ -280,6 +281,7 @@ func NewVaultWriteTool(cfg config.Config) Tool {
 			}
 			return marshalVaultResult(res)
 		},
+		Exclusive: true,
 	}
 }
 
@@ -325,7 +327,7 @@ func NewVaultEditTool(cfg config.Config) Tool {
 				"required": ["path", "old_string"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Path           string `json:"path"`
 				OldString      string `json:"old_string"`
@@ -350,6 +352,7 @@ func NewVaultEditTool(cfg config.Config) Tool {
 			}
 			return marshalVaultResult(res)
 		},
+		Exclusive: true,
 	}
 }
 
@@ -381,7 +384,7 @@ func NewVaultDeleteTool(cfg config.Config) Tool {
 				"required": ["path"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				Path           string `json:"path"`
 				ExpectedSha256 string `json:"expected_sha256"`
@@ -400,6 +403,7 @@ func NewVaultDeleteTool(cfg config.Config) Tool {
 			}
 			return marshalVaultResult(res)
 		},
+		Exclusive: true,
 	}
 }
 
@@ -432,7 +436,7 @@ func NewVaultMoveTool(cfg config.Config) Tool {
 				"required": ["from_path", "to_path"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(_ context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				FromPath string `json:"from_path"`
 				ToPath   string `json:"to_path"`
@@ -454,6 +458,7 @@ func NewVaultMoveTool(cfg config.Config) Tool {
 			}
 			return marshalVaultResult(res)
 		},
+		Exclusive: true,
 	}
 }
 
@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -51,7 +52,7 @@ func NewWorktreeGCTool(_ config.Config) Tool {
 				"required": ["project_path"]
 			}`),
 		},
-		Handler: func(params json.RawMessage) (string, error) {
+		Handler: func(ctx context.Context, params json.RawMessage) (string, error) {
 			var args struct {
 				ProjectPath      string   `json:"project_path"`
 				DryRun           bool     `json:"dry_run"`
@@ -71,8 +72,12 @@ func NewWorktreeGCTool(_ config.Config) Tool {
 				DryRun:           args.DryRun,
 				CandidateParents: args.CandidateParents,
 				ForceUncaptured:  args.ForceUncaptured,
+				Progress: func(done, total int) {
+					ReportProgress(ctx, float64(done), float64(total),
+						fmt.Sprintf("%d of %d locked worktrees checked", done, total))
+				},
 			}
-			res, err := worktreegc.Run(args.ProjectPath, opts)
+			res, err := worktreegc.RunContext(ctx, args.ProjectPath, opts)
 			if err != nil {
 				return "", err
 			}
@@ -4,6 +4,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -29,7 +30,7 @@ func TestWorktreeGCTool_HappyPath(t *testing.T) {
 	tool := NewWorktreeGCTool(config.DefaultConfig())
 	params := json.RawMessage(fmt.Sprintf(`{"project_path": %q, "dry_run": true}`, repo))
 
-	out, err := tool.Handler(params)
+	out, err := tool.Handler(context.Background(), params)
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -57,7 +58,7 @@ func TestWorktreeGCTool_HappyPath(t *testing.T) {
 // request with a validation error before invoking Run.
 func TestWorktreeGCTool_MissingProjectPath(t *testing.T) {
 	tool := NewWorktreeGCTool(config.DefaultConfig())
-	_, err := tool.Handler(json.RawMessage(`{"dry_run": true}`))
+	_, err := tool.Handler(context.Background(), json.RawMessage(`{"dry_run": true}`))
 	if err == nil {
 		t.Fatal("want validation error for missing project_path; got nil")
 	}
@@ -78,7 +79,7 @@ func TestWorktreeGCTool_CandidateParentsFlowThrough(t *testing.T) {
 		repo,
 	))
 
-	out, err := tool.Handler(params)
+	out, err := tool.Handler(context.Background(), params)
 	if err != nil {
 		t.Fatalf("handler error: %v", err)
 	}
@@ -239,6 +239,26 @@ func activityDescription(segments []Segment) string {
 	return ""
 }
 
+// IntentPrefix returns the conventional-commit intent ("feat", "fix",
+// "refactor", ...) for a session, using the same inference as the
+// heuristic summary: the last commit's prefix when it has one, otherwise
+// the activity mix. Returns "" when nothing can be inferred.
+func IntentPrefix(segments []Segment, commits []Commit) string {
+	return inferIntentPrefix(segments, commits)
+}
+
+// ConventionalPrefix returns the conventional-commit prefix of msg
+// ("feat", "fix", ...) or "" when msg does not start with one.
+func ConventionalPrefix(msg string) string {
+	return extractConventionalPrefix(msg)
+}
+
+// StripConventionalPrefix removes a leading "feat: " / "fix(scope): "
+// style prefix from msg.
+func StripConventionalPrefix(msg string) string {
+	return stripConventionalPrefix(msg)
+}
+
 // inferIntentPrefix determines the conventional commit prefix from commits or activity patterns.
 func inferIntentPrefix(segments []Segment, commits []Commit) string {
 	// Priority 1: conventional prefix from last commit message (the deliverable)
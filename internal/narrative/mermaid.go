@@ -0,0 +1,260 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package narrative
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Diagram styles accepted by RenderMermaid.
+const (
+	DiagramGantt    = "gantt"
+	DiagramTimeline = "timeline"
+)
+
+// maxDiagramEvents caps the markers drawn per diagram. Beyond this, Mermaid
+// output becomes unreadable and slow to render in Obsidian.
+const maxDiagramEvents = 60
+
+const mermaidTime = "2006-01-02 15:04:05"
+
+// String returns a short, stable name for the kind. Used as the key for
+// per-session activity counts and as the label in activity-mix charts.
+func (k ActivityKind) String() string {
+	switch k {
+	case KindFileCreate:
+		return "create"
+	case KindFileModify:
+		return "modify"
+	case KindTestRun:
+		return "test"
+	case KindGitCommit:
+		return "commit"
+	case KindGitPush:
+		return "push"
+	case KindBuild:
+		return "build"
+	case KindCommand:
+		return "command"
+	case KindDecision:
+		return "decision"
+	case KindPlanMode:
+		return "plan"
+	case KindDelegation:
+		return "delegation"
+	case KindExplore:
+		return "explore"
+	case KindError:
+		return "error"
+	default:
+		return "other"
+	}
+}
+
+// ActivityMix counts activities by kind name across all segments.
+func ActivityMix(segments []Segment) map[string]int {
+	mix := make(map[string]int)
+	for _, seg := range segments {
+		for _, a := range seg.Activities {
+			mix[a.Kind.String()]++
+		}
+	}
+	if len(mix) == 0 {
+		return nil
+	}
+	return mix
+}
+
+// diagramEvent is one marker on a session diagram.
+type diagramEvent struct {
+	at    time.Time
+	until time.Time // zero for point events
+	label string
+	tags  string // gantt task tags, e.g. "crit, milestone"
+}
+
+// RenderMermaid renders a fenced Mermaid block charting segments,
+// compaction boundaries, test runs, commits, and error/recovery pairs.
+// style is DiagramGantt or DiagramTimeline. Returns empty string for an
+// unknown style or trivial sessions (<=5 activities, same threshold as
+// RenderTimeline) and when no activity carries a timestamp.
+func RenderMermaid(segments []Segment, style string) string {
+	if style != DiagramGantt && style != DiagramTimeline {
+		return ""
+	}
+	total := 0
+	for _, seg := range segments {
+		total += len(seg.Activities)
+	}
+	if total <= 5 {
+		return ""
+	}
+
+	var b strings.Builder
+	drawn, sections := 0, 0
+	switch style {
+	case DiagramGantt:
+		b.WriteString("```mermaid\ngantt\n")
+		b.WriteString("    title Session activity\n")
+		b.WriteString("    dateFormat YYYY-MM-DD HH:mm:ss\n")
+		b.WriteString("    axisFormat %H:%M\n")
+		for _, seg := range segments {
+			start, end := segmentSpan(seg)
+			if start.IsZero() {
+				continue
+			}
+			sections++
+			fmt.Fprintf(&b, "    section %s\n", segmentLabel(seg))
+			fmt.Fprintf(&b, "    Segment %d :active, %s, %s\n", seg.Index+1, start.Format(mermaidTime), end.Format(mermaidTime))
+			for _, ev := range segmentEvents(seg, start) {
+				if drawn >= maxDiagramEvents {
+					break
+				}
+				drawn++
+				if ev.until.IsZero() {
+					fmt.Fprintf(&b, "    %s :%s, %s, 0m\n", ev.label, ev.tags, ev.at.Format(mermaidTime))
+				} else {
+					fmt.Fprintf(&b, "    %s :%s, %s, %s\n", ev.label, ev.tags, ev.at.Format(mermaidTime), ev.until.Format(mermaidTime))
+				}
+			}
+		}
+	case DiagramTimeline:
+		b.WriteString("```mermaid\ntimeline\n")
+		b.WriteString("    title Session timeline\n")
+		for _, seg := range segments {
+			start, _ := segmentSpan(seg)
+			if start.IsZero() {
+				continue
+			}
+			sections++
+			fmt.Fprintf(&b, "    section %s\n", segmentLabel(seg))
+			var order []string
+			byMinute := make(map[string][]string)
+			add := func(at time.Time, label string) {
+				key := at.Format("15:04")
+				if _, ok := byMinute[key]; !ok {
+					order = append(order, key)
+				}
+				byMinute[key] = append(byMinute[key], label)
+			}
+			add(start, fmt.Sprintf("Segment %d starts", seg.Index+1))
+			for _, ev := range segmentEvents(seg, start) {
+				if drawn >= maxDiagramEvents {
+					break
+				}
+				drawn++
+				add(ev.at, ev.label)
+			}
+			for _, key := range order {
+				fmt.Fprintf(&b, "        %s : %s\n", key, strings.Join(byMinute[key], " : "))
+			}
+		}
+	}
+
+	if sections == 0 {
+		return ""
+	}
+	b.WriteString("```\n")
+	return b.String()
+}
+
+// segmentSpan returns the segment's time range, falling back to its
+// activity timestamps when the boundary times were not recorded.
+func segmentSpan(seg Segment) (time.Time, time.Time) {
+	start, end := seg.StartTime, seg.EndTime
+	for _, a := range seg.Activities {
+		if a.Timestamp.IsZero() {
+			continue
+		}
+		if start.IsZero() || a.Timestamp.Before(start) {
+			start = a.Timestamp
+		}
+		if end.IsZero() || a.Timestamp.After(end) {
+			end = a.Timestamp
+		}
+	}
+	if end.Before(start) {
+		end = start
+	}
+	return start, end
+}
+
+func segmentLabel(seg Segment) string {
+	label := fmt.Sprintf("Segment %d", seg.Index+1)
+	if seg.UserRequest != "" {
+		label += " - " + mermaidLabel(seg.UserRequest, 40)
+	}
+	return label
+}
+
+// segmentEvents collects the notable markers in one segment, in time order.
+func segmentEvents(seg Segment, start time.Time) []diagramEvent {
+	var events []diagramEvent
+	if seg.TokensBefore > 0 {
+		events = append(events, diagramEvent{
+			at:    start,
+			label: fmt.Sprintf("Compacted at %dk tokens", seg.TokensBefore/1000),
+			tags:  "milestone",
+		})
+	}
+
+	acts := seg.Activities
+	for i, a := range acts {
+		if a.Timestamp.IsZero() {
+			continue
+		}
+		switch {
+		case a.IsError && a.Recovered:
+			ev := diagramEvent{at: a.Timestamp, label: errorLabel(a.Kind, true), tags: "crit"}
+			// Mirror detectRecoveries: the fix is the next same-kind
+			// success within three activities.
+			for j := i + 1; j < len(acts) && j <= i+3; j++ {
+				if acts[j].Kind == a.Kind && !acts[j].IsError && !acts[j].Timestamp.IsZero() {
+					ev.until = acts[j].Timestamp
+					break
+				}
+			}
+			if ev.until.IsZero() || !ev.until.After(ev.at) {
+				ev.until = time.Time{}
+				ev.tags = "crit, milestone"
+			}
+			events = append(events, ev)
+		case a.IsError:
+			events = append(events, diagramEvent{at: a.Timestamp, label: errorLabel(a.Kind, false), tags: "crit, milestone"})
+		case a.Kind == KindTestRun:
+			events = append(events, diagramEvent{at: a.Timestamp, label: "Tests passed", tags: "done, milestone"})
+		case a.Kind == KindGitCommit:
+			events = append(events, diagramEvent{at: a.Timestamp, label: mermaidLabel(a.Description, 40), tags: "milestone"})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].at.Before(events[j].at) })
+	return events
+}
+
+func errorLabel(kind ActivityKind, recovered bool) string {
+	label := kind.String() + " error"
+	if kind == KindTestRun {
+		label = "Tests failed"
+	}
+	if recovered {
+		label += " → recovered"
+	}
+	return label
+}
+
+// mermaidLabel strips characters that Mermaid treats as syntax in task
+// names and timeline events, and truncates to max runes.
+func mermaidLabel(s string, max int) string {
+	s = strings.NewReplacer(
+		":", " ", ";", ",", "#", "", "`", "", "\"", "'", "\n", " ", "\r", " ",
+	).Replace(s)
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > max {
+		s = string(r[:max-1]) + "…"
+	}
+	return s
+}
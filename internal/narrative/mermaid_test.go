@@ -0,0 +1,96 @@
+package narrative
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func mermaidSegments() []Segment {
+	base := time.Date(2026, 2, 22, 14, 0, 0, 0, time.UTC)
+	return []Segment{
+		{
+			Index:       0,
+			StartTime:   base,
+			EndTime:     base.Add(20 * time.Minute),
+			UserRequest: "Add backfill: resume support",
+			Activities: []Activity{
+				{Timestamp: base.Add(1 * time.Minute), Kind: KindFileCreate, Description: "Created `a.go`"},
+				{Timestamp: base.Add(2 * time.Minute), Kind: KindTestRun, Description: "Ran tests (failed)", IsError: true, Recovered: true},
+				{Timestamp: base.Add(3 * time.Minute), Kind: KindFileModify, Description: "Modified `a.go`"},
+				{Timestamp: base.Add(6 * time.Minute), Kind: KindTestRun, Description: "Ran tests (success)"},
+				{Timestamp: base.Add(8 * time.Minute), Kind: KindGitCommit, Description: `Committed: "feat: backfill"`},
+			},
+		},
+		{
+			Index:        1,
+			StartTime:    base.Add(30 * time.Minute),
+			EndTime:      base.Add(40 * time.Minute),
+			TokensBefore: 155000,
+			Activities: []Activity{
+				{Timestamp: base.Add(31 * time.Minute), Kind: KindBuild, Description: "Built project (failed)", IsError: true},
+				{Timestamp: base.Add(32 * time.Minute), Kind: KindFileModify, Description: "Modified `b.go`"},
+			},
+		},
+	}
+}
+
+func TestRenderMermaid_Gantt(t *testing.T) {
+	out := RenderMermaid(mermaidSegments(), DiagramGantt)
+	for _, want := range []string{
+		"```mermaid\ngantt\n",
+		"dateFormat YYYY-MM-DD HH:mm:ss",
+		"section Segment 1 - Add backfill resume support",
+		"Segment 1 :active, 2026-02-22 14:00:00, 2026-02-22 14:20:00",
+		"Tests failed → recovered :crit, 2026-02-22 14:02:00, 2026-02-22 14:06:00",
+		"Tests passed :done, milestone, 2026-02-22 14:06:00, 0m",
+		"Committed 'feat backfill' :milestone, 2026-02-22 14:08:00, 0m",
+		"Compacted at 155k tokens :milestone, 2026-02-22 14:30:00, 0m",
+		"build error :crit, milestone, 2026-02-22 14:31:00, 0m",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("gantt missing %q\n%s", want, out)
+		}
+	}
+	if !strings.HasSuffix(out, "```\n") {
+		t.Errorf("block not closed:\n%s", out)
+	}
+}
+
+func TestRenderMermaid_Timeline(t *testing.T) {
+	out := RenderMermaid(mermaidSegments(), DiagramTimeline)
+	for _, want := range []string{
+		"```mermaid\ntimeline\n",
+		"14:00 : Segment 1 starts",
+		"14:02 : Tests failed → recovered",
+		"14:30 : Segment 2 starts : Compacted at 155k tokens",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("timeline missing %q\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderMermaid_SkipsTrivialAndUnknown(t *testing.T) {
+	segs := mermaidSegments()
+	if out := RenderMermaid(segs, "flowchart"); out != "" {
+		t.Errorf("unknown style: got %q", out)
+	}
+	if out := RenderMermaid(segs[1:], DiagramGantt); out != "" {
+		t.Errorf("trivial session: got %q", out)
+	}
+	untimed := []Segment{{Activities: make([]Activity, 6)}}
+	if out := RenderMermaid(untimed, DiagramGantt); out != "" {
+		t.Errorf("no timestamps: got %q", out)
+	}
+}
+
+func TestActivityMix(t *testing.T) {
+	mix := ActivityMix(mermaidSegments())
+	if mix["modify"] != 2 || mix["test"] != 2 || mix["commit"] != 1 || mix["build"] != 1 {
+		t.Errorf("mix = %v", mix)
+	}
+	if ActivityMix(nil) != nil {
+		t.Error("expected nil mix for no segments")
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Package notediff compares a session note on disk with a fresh render of
+// the same session, section by section, and merges selected sections of
+// the render into the existing note. It backs `vv reprocess --diff`.
+package notediff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/suykerbuyk/vibe-vault/internal/templates"
+)
+
+// Section keys for the parts of a note that are not "## " sections.
+const (
+	KeyFrontmatter = "frontmatter"
+	KeyTitle       = "title" // "# Title" line plus anything before the first "## "
+)
+
+// Section is one addressable part of a note. Text holds the section's
+// lines verbatim, including its heading and trailing blank lines, so
+// concatenating every Section of a note reproduces it exactly.
+type Section struct {
+	Key     string
+	Heading string
+	Text    string
+}
+
+// Split breaks note into its frontmatter, title block, and "## " sections.
+// Keys for "## " sections are the heading slugged ("What Happened" →
+// "what-happened"); a repeated heading gets a numeric suffix.
+func Split(note string) []Section {
+	if note == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(note, "\n")
+	var out []Section
+	i := 0
+	if strings.TrimRight(lines[0], "\r\n") == "---" {
+		for j := 1; j < len(lines); j++ {
+			if strings.TrimRight(lines[j], "\r\n") == "---" {
+				// Blank lines after the closing fence belong to the frontmatter.
+				k := j + 1
+				for k < len(lines) && strings.TrimSpace(lines[k]) == "" && lines[k] != "" {
+					k++
+				}
+				out = append(out, Section{Key: KeyFrontmatter, Text: strings.Join(lines[:k], "")})
+				i = k
+				break
+			}
+		}
+	}
+
+	seen := make(map[string]int)
+	cur := Section{Key: KeyTitle}
+	var b strings.Builder
+	flush := func() {
+		if b.Len() > 0 || cur.Key != KeyTitle {
+			cur.Text = b.String()
+			out = append(out, cur)
+		}
+		b.Reset()
+	}
+	fenced := false
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			fenced = !fenced
+		}
+		if !fenced && strings.HasPrefix(trimmed, "## ") {
+			flush()
+			heading := strings.TrimSpace(strings.TrimPrefix(trimmed, "## "))
+			key := Slug(heading)
+			seen[key]++
+			if n := seen[key]; n > 1 {
+				key = fmt.Sprintf("%s-%d", key, n)
+			}
+			cur = Section{Key: key, Heading: heading}
+		}
+		b.WriteString(line)
+	}
+	flush()
+	return out
+}
+
+// Slug lowercases heading and joins its words with hyphens.
+func Slug(heading string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+		default:
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// Change is one section that differs between the old and new note.
+// Old or New is empty when the section exists on only one side.
+type Change struct {
+	Key string
+	Old string
+	New string
+}
+
+// Compare returns the sections that differ between oldNote and newNote,
+// in newNote's order followed by sections only oldNote has. Trailing
+// whitespace differences are ignored.
+func Compare(oldNote, newNote string) []Change {
+	oldSecs := index(Split(oldNote))
+	var changes []Change
+	seen := make(map[string]bool)
+	for _, s := range Split(newNote) {
+		seen[s.Key] = true
+		prev := oldSecs[s.Key]
+		if strings.TrimRight(prev, "\n ") != strings.TrimRight(s.Text, "\n ") {
+			changes = append(changes, Change{Key: s.Key, Old: prev, New: s.Text})
+		}
+	}
+	for _, s := range Split(oldNote) {
+		if !seen[s.Key] {
+			changes = append(changes, Change{Key: s.Key, Old: s.Text})
+		}
+	}
+	return changes
+}
+
+// Diff renders a unified diff of changes, one hunk set per section,
+// labelled with name and the section key.
+func Diff(name string, changes []Change) string {
+	var parts []string
+	for _, c := range changes {
+		parts = append(parts, templates.UnifiedDiff(
+			fmt.Sprintf("a/%s#%s", name, c.Key),
+			fmt.Sprintf("b/%s#%s", name, c.Key),
+			c.Old, c.New))
+	}
+	return strings.Join(parts, "")
+}
+
+// Merge returns oldNote with each section whose key is in accept taken
+// from newNote instead. Sections follow newNote's order; sections only
+// oldNote has are kept at the end unless accepted, in which case the
+// new render's omission wins and they are dropped. With an empty
+// oldNote the result is newNote restricted to accepted sections.
+func Merge(oldNote, newNote string, accept map[string]bool) string {
+	oldSections := Split(oldNote)
+	oldSecs := index(oldSections)
+	var b strings.Builder
+	seen := make(map[string]bool)
+	for _, s := range Split(newNote) {
+		seen[s.Key] = true
+		switch {
+		case accept[s.Key]:
+			b.WriteString(s.Text)
+		default:
+			b.WriteString(oldSecs[s.Key])
+		}
+	}
+	for _, s := range oldSections {
+		if !seen[s.Key] && !accept[s.Key] {
+			b.WriteString(s.Text)
+		}
+	}
+	return b.String()
+}
+
+func index(secs []Section) map[string]string {
+	m := make(map[string]string, len(secs))
+	for _, s := range secs {
+		m[s.Key] = s.Text
+	}
+	return m
+}
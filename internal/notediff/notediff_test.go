@@ -0,0 +1,121 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package notediff
+
+import (
+	"strings"
+	"testing"
+)
+
+const oldNote = `---
+date: 2026-03-08
+summary: "Old summary"
+---
+
+# Session title
+
+## What Happened
+
+Did the thing.
+
+## Key Decisions
+
+- Keep it simple
+
+## My Notes
+
+Hand-written.
+`
+
+const newNote = `---
+date: 2026-03-08
+summary: "New summary"
+---
+
+# Session title
+
+## What Happened
+
+Did the thing, better.
+
+## Key Decisions
+
+- Keep it simple
+
+## Tool Usage
+
+- Bash: 3
+`
+
+func TestSplit_RoundTrips(t *testing.T) {
+	secs := Split(oldNote)
+	var keys []string
+	var b strings.Builder
+	for _, s := range secs {
+		keys = append(keys, s.Key)
+		b.WriteString(s.Text)
+	}
+	if got := strings.Join(keys, ","); got != "frontmatter,title,what-happened,key-decisions,my-notes" {
+		t.Errorf("keys = %s", got)
+	}
+	if b.String() != oldNote {
+		t.Errorf("sections do not reassemble the note:\n%s", b.String())
+	}
+}
+
+func TestSplit_IgnoresHeadingsInCodeFences(t *testing.T) {
+	note := "# T\n\n## Timeline\n\n```\n## not a heading\n```\n"
+	secs := Split(note)
+	if len(secs) != 2 || secs[1].Key != "timeline" {
+		t.Errorf("sections = %+v", secs)
+	}
+}
+
+func TestSlug(t *testing.T) {
+	for in, want := range map[string]string{
+		"What Happened":       "what-happened",
+		"Friction Signals":    "friction-signals",
+		"  Q&A — follow-ups ": "q-a-follow-ups",
+	} {
+		if got := Slug(in); got != want {
+			t.Errorf("Slug(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	var keys []string
+	for _, c := range Compare(oldNote, newNote) {
+		keys = append(keys, c.Key)
+	}
+	if got := strings.Join(keys, ","); got != "frontmatter,what-happened,tool-usage,my-notes" {
+		t.Errorf("changed = %s", got)
+	}
+	if len(Compare(oldNote, oldNote)) != 0 {
+		t.Error("identical notes reported changes")
+	}
+}
+
+func TestDiff_LabelsSections(t *testing.T) {
+	d := Diff("Projects/p/sessions/n.md", Compare(oldNote, newNote)[:1])
+	if !strings.Contains(d, "--- a/Projects/p/sessions/n.md#frontmatter") ||
+		!strings.Contains(d, `+summary: "New summary"`) {
+		t.Errorf("diff:\n%s", d)
+	}
+}
+
+func TestMerge_AcceptsSelectedSections(t *testing.T) {
+	got := Merge(oldNote, newNote, map[string]bool{"frontmatter": true, "tool-usage": true})
+	for _, want := range []string{`summary: "New summary"`, "Did the thing.\n", "## Tool Usage", "## My Notes\n\nHand-written."} {
+		if !strings.Contains(got, want) {
+			t.Errorf("merged note missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "better") {
+		t.Errorf("unaccepted section was replaced:\n%s", got)
+	}
+	if Merge(oldNote, newNote, nil) != oldNote {
+		t.Error("empty accept set changed the note")
+	}
+}
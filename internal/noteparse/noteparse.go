@@ -4,11 +4,16 @@
 package noteparse
 
 import (
+	"bytes"
+	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/suykerbuyk/vibe-vault/internal/flavor"
 	"github.com/suykerbuyk/vibe-vault/internal/frontmatter"
+	"github.com/suykerbuyk/vibe-vault/internal/vaultcrypt"
 )
 
 // Note represents a parsed session note with frontmatter and body sections.
@@ -34,16 +39,44 @@ type Note struct {
 	OpenThreads  []string // from ## Open Threads
 	FilesChanged []string // from ## What Changed
 	Commits      []string // from ## Commits
+
+	// Sealed is set when the note is encrypted at rest and no key was
+	// available: only the stub's identifying frontmatter was parsed.
+	Sealed bool
 }
 
-// ParseFile reads and parses a session note from disk.
+// ParseFile reads and parses a session note from disk. An encrypted
+// note's stub is resolved to its payload, decrypted with the key
+// installed by vaultcrypt.SetDefault; without a key the stub itself is
+// parsed and the note marked Sealed.
 func ParseFile(path string) (*Note, error) {
-	f, err := os.Open(path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
-	return Parse(f)
+	note, err := Parse(bytes.NewReader(data))
+	if err != nil || note.Frontmatter["encrypted"] != "true" {
+		return note, err
+	}
+
+	k := vaultcrypt.Default()
+	if k == nil {
+		note.Sealed = true
+		return note, nil
+	}
+	payload := note.Frontmatter["payload"]
+	if payload == "" {
+		payload = filepath.Base(path) + ".enc"
+	}
+	sealed, err := os.ReadFile(filepath.Join(filepath.Dir(path), payload))
+	if err != nil {
+		return nil, fmt.Errorf("read encrypted payload: %w", err)
+	}
+	plain, err := k.Open(sealed)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return Parse(bytes.NewReader(plain))
 }
 
 // Parse reads and parses a session note from a reader.
@@ -53,6 +86,14 @@ func Parse(r io.Reader) (*Note, error) {
 		return nil, err
 	}
 
+	// Logseq-flavored notes carry "key:: value" page properties instead
+	// of YAML frontmatter; they map onto the same field names.
+	if len(res.Fields) == 0 {
+		if fields, rest, ok := flavor.ParseProperties(res.Body); ok {
+			res.Fields, res.Body = fields, rest
+		}
+	}
+
 	note := &Note{Frontmatter: res.Fields}
 
 	// Map frontmatter to typed fields
@@ -120,13 +161,17 @@ func extractBulletSection(lines []string, heading string) []string {
 }
 
 // extractCheckboxSection extracts unchecked checkbox items from a markdown section.
-// Checked items (- [x]) are treated as resolved and skipped.
+// Checked items (- [x]) are treated as resolved and skipped. Logseq
+// "- TODO" items count as open; "- DONE" items are skipped.
 func extractCheckboxSection(lines []string, heading string) []string {
 	return extractSection(lines, heading, func(line string) (string, bool) {
 		trimmed := strings.TrimSpace(line)
 		if strings.HasPrefix(trimmed, "- [ ] ") {
 			return strings.TrimSpace(trimmed[6:]), true
 		}
+		if strings.HasPrefix(trimmed, "- TODO ") {
+			return strings.TrimSpace(trimmed[7:]), true
+		}
 		// Skip resolved/checked items.
 		return "", false
 	})
@@ -3,6 +3,8 @@ package noteparse
 import (
 	"strings"
 	"testing"
+
+	"github.com/suykerbuyk/vibe-vault/internal/flavor"
 )
 
 const sampleNote = `---
@@ -88,6 +90,28 @@ func TestParseFrontmatter(t *testing.T) {
 	}
 }
 
+func TestParseLogseqProperties(t *testing.T) {
+	note, err := Parse(strings.NewReader(flavor.Convert(sampleNote, flavor.Logseq, nil)))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if note.SessionID != "abc-123" || note.Project != "vibe-vault" || note.Iteration != "2" {
+		t.Errorf("properties not mapped: %+v", note)
+	}
+	if note.Tag != "implementation" || len(note.Tags) != 2 {
+		t.Errorf("tags = %v (tag %q)", note.Tags, note.Tag)
+	}
+	if note.Previous != "[[2026-02-24-01]]" {
+		t.Errorf("Previous = %q", note.Previous)
+	}
+	if len(note.OpenThreads) != 2 || note.OpenThreads[0] != "Add progress bar for large vaults" {
+		t.Errorf("OpenThreads = %v, want TODO items", note.OpenThreads)
+	}
+	if len(note.Commits) != 2 || len(note.Decisions) != 2 {
+		t.Errorf("body sections lost: commits=%v decisions=%v", note.Commits, note.Decisions)
+	}
+}
+
 func TestParseBracketList(t *testing.T) {
 	note, err := Parse(strings.NewReader(sampleNote))
 	if err != nil {
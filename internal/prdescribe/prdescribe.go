@@ -0,0 +1,355 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Package prdescribe assembles a pull-request description from the
+// sessions recorded against a feature branch. Collection and rendering are
+// pure functions over index entries; the caller supplies test evidence
+// (re-extracted from transcripts) and optionally an LLM provider for a
+// final polish pass.
+package prdescribe
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/suykerbuyk/vibe-vault/internal/index"
+	"github.com/suykerbuyk/vibe-vault/internal/llm"
+	"github.com/suykerbuyk/vibe-vault/internal/narrative"
+)
+
+// maxFiles caps the "Files Changed" list; the remainder is summarized.
+const maxFiles = 40
+
+// Options selects which sessions belong to the PR.
+type Options struct {
+	Project string // empty = all projects
+	Branch  string // feature branch name (required)
+	Base    string // merge target, rendered in the header only
+
+	// Commits, when non-empty, holds the SHAs in base..branch. Sessions
+	// whose recorded commits match any of them are included even when
+	// their Branch field differs (e.g. work done on a worktree branch
+	// that was later merged into the feature branch).
+	Commits []string
+}
+
+// TestRun is one test invocation observed in a session transcript.
+type TestRun struct {
+	SessionID string
+	Note      string // wikilink-free note name, e.g. "2026-03-01-143025123"
+	Result    string // "Ran tests (success)"
+	Command   string // truncated command text
+	Failed    bool
+	Recovered bool
+}
+
+// FileChange aggregates one path across the collected sessions.
+type FileChange struct {
+	Path     string
+	Sessions int
+}
+
+// Description is the structured PR description before rendering.
+type Description struct {
+	Project      string
+	Branch       string
+	Base         string
+	Sessions     []index.SessionEntry
+	Decisions    []string
+	FilesChanged []FileChange
+	Commits      []string
+	TestRuns     []TestRun
+	OpenThreads  []string
+}
+
+// Collect returns the sessions that belong to the branch, oldest first.
+// Checkpoint entries are included — a branch's latest session is often
+// still provisional when the PR is opened.
+func Collect(entries map[string]index.SessionEntry, opts Options) []index.SessionEntry {
+	var out []index.SessionEntry
+	for _, e := range entries {
+		if opts.Project != "" && e.Project != opts.Project {
+			continue
+		}
+		if (opts.Branch != "" && e.Branch == opts.Branch) || sharesCommit(e.Commits, opts.Commits) {
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		if out[i].Date != out[j].Date {
+			return out[i].Date < out[j].Date
+		}
+		if out[i].Iteration != out[j].Iteration {
+			return out[i].Iteration < out[j].Iteration
+		}
+		return out[i].SessionID < out[j].SessionID
+	})
+	return out
+}
+
+// Build aggregates the collected sessions into a Description. tests maps
+// session ID to the KindTestRun activities re-extracted from that
+// session's transcript; sessions without a transcript simply contribute
+// no test evidence.
+func Build(sessions []index.SessionEntry, tests map[string][]narrative.Activity, opts Options) Description {
+	d := Description{
+		Project:  opts.Project,
+		Branch:   opts.Branch,
+		Base:     opts.Base,
+		Sessions: sessions,
+	}
+	if d.Project == "" && len(sessions) > 0 {
+		d.Project = sessions[0].Project
+	}
+
+	seenDecision := make(map[string]bool)
+	seenCommit := make(map[string]bool)
+	fileCounts := make(map[string]int)
+	var threads []string
+
+	for _, s := range sessions {
+		for _, dec := range s.Decisions {
+			key := strings.ToLower(strings.TrimSpace(dec))
+			if key == "" || seenDecision[key] {
+				continue
+			}
+			seenDecision[key] = true
+			d.Decisions = append(d.Decisions, dec)
+		}
+		for _, c := range s.Commits {
+			if !seenCommit[c] {
+				seenCommit[c] = true
+				d.Commits = append(d.Commits, c)
+			}
+		}
+		seenFile := make(map[string]bool)
+		for _, f := range s.FilesChanged {
+			if !seenFile[f] {
+				seenFile[f] = true
+				fileCounts[f]++
+			}
+		}
+		for _, a := range tests[s.SessionID] {
+			if a.Kind != narrative.KindTestRun {
+				continue
+			}
+			d.TestRuns = append(d.TestRuns, TestRun{
+				SessionID: s.SessionID,
+				Note:      noteName(s.NotePath),
+				Result:    a.Description,
+				Command:   a.Detail,
+				Failed:    a.IsError,
+				Recovered: a.Recovered,
+			})
+		}
+		threads = append(threads, s.OpenThreads...)
+	}
+
+	for path, n := range fileCounts {
+		d.FilesChanged = append(d.FilesChanged, FileChange{Path: path, Sessions: n})
+	}
+	sort.Slice(d.FilesChanged, func(i, j int) bool {
+		return d.FilesChanged[i].Path < d.FilesChanged[j].Path
+	})
+
+	d.OpenThreads = openThreads(sessions, threads)
+	return d
+}
+
+// openThreads keeps the latest session's threads verbatim (the most
+// current view of what remains) and appends earlier threads that were not
+// restated, deduplicated case-insensitively.
+func openThreads(sessions []index.SessionEntry, all []string) []string {
+	if len(sessions) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var out []string
+	add := func(t string) {
+		key := strings.ToLower(strings.TrimSpace(t))
+		if key == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		out = append(out, t)
+	}
+	for _, t := range sessions[len(sessions)-1].OpenThreads {
+		add(t)
+	}
+	for _, t := range all {
+		add(t)
+	}
+	return out
+}
+
+// Render formats a Description as GitHub-flavored markdown.
+func Render(d Description) string {
+	var b strings.Builder
+
+	if d.Base != "" {
+		fmt.Fprintf(&b, "# %s → %s\n\n", d.Branch, d.Base)
+	} else {
+		fmt.Fprintf(&b, "# %s\n\n", d.Branch)
+	}
+
+	if len(d.Sessions) == 0 {
+		fmt.Fprintf(&b, "_No vv sessions recorded for branch `%s`._\n", d.Branch)
+		return b.String()
+	}
+
+	b.WriteString("## Summary\n\n")
+	for _, s := range d.Sessions {
+		summary := strings.TrimSpace(s.Summary)
+		if summary == "" {
+			summary = s.Title
+		}
+		fmt.Fprintf(&b, "- %s (%s)\n", summary, sessionRef(s))
+	}
+	b.WriteString("\n")
+
+	if len(d.Decisions) > 0 {
+		b.WriteString("## Key Decisions\n\n")
+		for _, dec := range d.Decisions {
+			what, why := splitDecision(dec)
+			if why != "" {
+				fmt.Fprintf(&b, "- **%s** — %s\n", what, why)
+			} else {
+				fmt.Fprintf(&b, "- %s\n", what)
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if len(d.FilesChanged) > 0 {
+		b.WriteString("## Files Changed\n\n")
+		for i, f := range d.FilesChanged {
+			if i == maxFiles {
+				fmt.Fprintf(&b, "- ... and %d more\n", len(d.FilesChanged)-maxFiles)
+				break
+			}
+			if f.Sessions > 1 {
+				fmt.Fprintf(&b, "- `%s` (%d sessions)\n", f.Path, f.Sessions)
+			} else {
+				fmt.Fprintf(&b, "- `%s`\n", f.Path)
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if len(d.Commits) > 0 {
+		b.WriteString("## Commits\n\n")
+		for _, c := range d.Commits {
+			fmt.Fprintf(&b, "- `%s`\n", c)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Test Evidence\n\n")
+	if len(d.TestRuns) == 0 {
+		b.WriteString("_No test runs recorded in session transcripts._\n\n")
+	} else {
+		for _, tr := range d.TestRuns {
+			line := tr.Result
+			if tr.Command != "" {
+				line += fmt.Sprintf(": `%s`", tr.Command)
+			}
+			if tr.Failed && tr.Recovered {
+				line += " — fixed later in session"
+			}
+			fmt.Fprintf(&b, "- %s (%s)\n", line, tr.Note)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(d.OpenThreads) > 0 {
+		b.WriteString("## Open Threads\n\n")
+		for _, t := range d.OpenThreads {
+			fmt.Fprintf(&b, "- [ ] %s\n", t)
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "---\n*Generated by vv from %d session(s)*\n", len(d.Sessions))
+	return b.String()
+}
+
+const polishSystemPrompt = `You edit pull-request descriptions written from developer session notes.
+
+Rewrite the draft into a clear, reviewer-friendly PR description in GitHub markdown.
+Rules:
+- Open with a 2-4 sentence overview of what the branch changes and why.
+- Keep the "Key Decisions", "Files Changed", "Test Evidence" and "Open Threads" sections and their facts. Do not invent tests, files, commits or decisions.
+- Collapse repetitive per-session summary bullets into prose.
+- Output markdown only. No preamble, no code fences around the whole document.`
+
+// Polish asks the LLM to rewrite the rendered draft. Returns the draft
+// unchanged when provider is nil.
+func Polish(ctx context.Context, provider llm.Provider, draft string) (string, error) {
+	if provider == nil {
+		return draft, nil
+	}
+	resp, err := provider.ChatCompletion(ctx, llm.Request{
+		System:      polishSystemPrompt,
+		UserPrompt:  draft,
+		Temperature: 0.3,
+	})
+	if err != nil {
+		return "", fmt.Errorf("polish: %w", err)
+	}
+	out := strings.TrimSpace(resp.Content)
+	if out == "" {
+		return "", fmt.Errorf("polish: empty response")
+	}
+	return out + "\n", nil
+}
+
+// splitDecision separates the "Decision — rationale" form produced by
+// enrichment. Decisions without an em-dash separator return an empty why.
+func splitDecision(dec string) (what, why string) {
+	for _, sep := range []string{" — ", " -- ", " - "} {
+		if i := strings.Index(dec, sep); i > 0 {
+			return strings.TrimSpace(dec[:i]), strings.TrimSpace(dec[i+len(sep):])
+		}
+	}
+	return strings.TrimSpace(dec), ""
+}
+
+// sharesCommit reports whether any recorded SHA matches a range SHA. Short
+// SHAs of differing lengths match when one is a prefix of the other.
+func sharesCommit(recorded, rangeSHAs []string) bool {
+	for _, r := range recorded {
+		for _, c := range rangeSHAs {
+			if r == "" || c == "" {
+				continue
+			}
+			if strings.HasPrefix(c, r) || strings.HasPrefix(r, c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sessionRef(s index.SessionEntry) string {
+	name := noteName(s.NotePath)
+	if name == "" {
+		return s.Date
+	}
+	return name
+}
+
+func noteName(notePath string) string {
+	if notePath == "" {
+		return ""
+	}
+	base := notePath
+	if i := strings.LastIndexAny(base, `/\`); i >= 0 {
+		base = base[i+1:]
+	}
+	return strings.TrimSuffix(base, ".md")
+}
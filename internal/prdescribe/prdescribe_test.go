@@ -0,0 +1,192 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package prdescribe
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/suykerbuyk/vibe-vault/internal/index"
+	"github.com/suykerbuyk/vibe-vault/internal/llm"
+	"github.com/suykerbuyk/vibe-vault/internal/narrative"
+)
+
+func fixtureEntries() map[string]index.SessionEntry {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	return map[string]index.SessionEntry{
+		"s2": {
+			SessionID: "s2", Project: "p", Branch: "feat/auth", CreatedAt: t0.Add(2 * time.Hour),
+			NotePath: "Projects/p/sessions/2026-03-01-110000000.md", Date: "2026-03-01",
+			Summary:      "Added token refresh.",
+			Decisions:    []string{"Refresh in middleware — keeps handlers simple", "Use JWT — stateless"},
+			FilesChanged: []string{"auth/refresh.go", "auth/handler.go"},
+			Commits:      []string{"bbb2222"},
+			OpenThreads:  []string{"Document refresh flow"},
+		},
+		"s1": {
+			SessionID: "s1", Project: "p", Branch: "feat/auth", CreatedAt: t0,
+			NotePath: "Projects/p/sessions/2026-03-01-090000000.md", Date: "2026-03-01",
+			Summary:      "Scaffolded auth handler.",
+			Decisions:    []string{"Use JWT — stateless"},
+			FilesChanged: []string{"auth/handler.go"},
+			Commits:      []string{"aaa1111"},
+			OpenThreads:  []string{"Add refresh tokens"},
+		},
+		"other-branch": {
+			SessionID: "other-branch", Project: "p", Branch: "main", CreatedAt: t0,
+			Commits: []string{"ccc3333"},
+		},
+		"other-project": {
+			SessionID: "other-project", Project: "q", Branch: "feat/auth", CreatedAt: t0,
+		},
+	}
+}
+
+func TestCollect_FiltersAndOrders(t *testing.T) {
+	got := Collect(fixtureEntries(), Options{Project: "p", Branch: "feat/auth"})
+	if len(got) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(got))
+	}
+	if got[0].SessionID != "s1" || got[1].SessionID != "s2" {
+		t.Errorf("order = %s,%s; want s1,s2", got[0].SessionID, got[1].SessionID)
+	}
+}
+
+func TestCollect_MatchesRangeCommits(t *testing.T) {
+	got := Collect(fixtureEntries(), Options{Project: "p", Branch: "feat/auth", Commits: []string{"ccc33334444"}})
+	if len(got) != 3 {
+		t.Fatalf("expected 3 sessions (branch + commit match), got %d", len(got))
+	}
+}
+
+func TestBuild_AggregatesAndDedupes(t *testing.T) {
+	opts := Options{Project: "p", Branch: "feat/auth", Base: "main"}
+	sessions := Collect(fixtureEntries(), opts)
+	tests := map[string][]narrative.Activity{
+		"s2": {
+			{Kind: narrative.KindTestRun, Description: "Ran tests (failed)", Detail: "go test ./auth/...", IsError: true, Recovered: true},
+			{Kind: narrative.KindFileModify, Description: "Modified `auth/refresh.go`"},
+			{Kind: narrative.KindTestRun, Description: "Ran tests (success)", Detail: "go test ./auth/..."},
+		},
+	}
+	d := Build(sessions, tests, opts)
+
+	if len(d.Decisions) != 2 {
+		t.Errorf("decisions = %v, want 2 deduplicated", d.Decisions)
+	}
+	if len(d.FilesChanged) != 2 {
+		t.Fatalf("files = %v, want 2", d.FilesChanged)
+	}
+	if d.FilesChanged[0].Path != "auth/handler.go" || d.FilesChanged[0].Sessions != 2 {
+		t.Errorf("files[0] = %+v, want auth/handler.go touched by 2 sessions", d.FilesChanged[0])
+	}
+	if len(d.TestRuns) != 2 {
+		t.Errorf("test runs = %d, want 2 (non-test activities excluded)", len(d.TestRuns))
+	}
+	if len(d.Commits) != 2 || d.Commits[0] != "aaa1111" {
+		t.Errorf("commits = %v, want chronological [aaa1111 bbb2222]", d.Commits)
+	}
+	if len(d.OpenThreads) != 2 || d.OpenThreads[0] != "Document refresh flow" {
+		t.Errorf("threads = %v, want latest session's thread first", d.OpenThreads)
+	}
+}
+
+func TestRender_Sections(t *testing.T) {
+	opts := Options{Project: "p", Branch: "feat/auth", Base: "main"}
+	d := Build(Collect(fixtureEntries(), opts), map[string][]narrative.Activity{
+		"s1": {{Kind: narrative.KindTestRun, Description: "Ran tests (success)", Detail: "go test ./..."}},
+	}, opts)
+	out := Render(d)
+
+	for _, want := range []string{
+		"# feat/auth → main",
+		"## Summary",
+		"- Scaffolded auth handler. (2026-03-01-090000000)",
+		"## Key Decisions",
+		"- **Use JWT** — stateless",
+		"## Files Changed",
+		"- `auth/handler.go` (2 sessions)",
+		"## Test Evidence",
+		"- Ran tests (success): `go test ./...` (2026-03-01-090000000)",
+		"## Open Threads",
+		"- [ ] Document refresh flow",
+		"*Generated by vv from 2 session(s)*",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("render missing %q\n%s", want, out)
+		}
+	}
+}
+
+func TestRender_NoSessions(t *testing.T) {
+	out := Render(Description{Branch: "feat/x"})
+	if !strings.Contains(out, "No vv sessions recorded for branch `feat/x`") {
+		t.Errorf("unexpected empty render:\n%s", out)
+	}
+}
+
+func TestRender_NoTestEvidence(t *testing.T) {
+	opts := Options{Project: "p", Branch: "feat/auth"}
+	out := Render(Build(Collect(fixtureEntries(), opts), nil, opts))
+	if !strings.Contains(out, "_No test runs recorded in session transcripts._") {
+		t.Errorf("expected placeholder for missing test evidence:\n%s", out)
+	}
+}
+
+func TestSplitDecision(t *testing.T) {
+	tests := []struct {
+		in, what, why string
+	}{
+		{"Use JWT — stateless", "Use JWT", "stateless"},
+		{"Use JWT -- stateless", "Use JWT", "stateless"},
+		{"Use JWT", "Use JWT", ""},
+	}
+	for _, tt := range tests {
+		what, why := splitDecision(tt.in)
+		if what != tt.what || why != tt.why {
+			t.Errorf("splitDecision(%q) = (%q, %q), want (%q, %q)", tt.in, what, why, tt.what, tt.why)
+		}
+	}
+}
+
+// mockProvider implements llm.Provider for testing.
+type mockProvider struct {
+	response *llm.Response
+	err      error
+	req      llm.Request
+}
+
+func (m *mockProvider) Name() string { return "mock" }
+func (m *mockProvider) ChatCompletion(_ context.Context, req llm.Request) (*llm.Response, error) {
+	m.req = req
+	return m.response, m.err
+}
+
+func TestPolish(t *testing.T) {
+	draft := "# feat/x\n"
+	got, err := Polish(context.Background(), nil, draft)
+	if err != nil || got != draft {
+		t.Errorf("nil provider: got (%q, %v), want draft unchanged", got, err)
+	}
+
+	mock := &mockProvider{response: &llm.Response{Content: "# Polished\n\nBody"}}
+	got, err = Polish(context.Background(), mock, draft)
+	if err != nil {
+		t.Fatalf("Polish: %v", err)
+	}
+	if got != "# Polished\n\nBody\n" {
+		t.Errorf("got %q", got)
+	}
+	if mock.req.UserPrompt != draft {
+		t.Errorf("draft not forwarded as user prompt: %q", mock.req.UserPrompt)
+	}
+
+	_, err = Polish(context.Background(), &mockProvider{err: errors.New("boom")}, draft)
+	if err == nil {
+		t.Error("expected provider error to propagate")
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package render
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EncryptedPayloadExt is appended to a session note's path to name the
+// encrypted copy of its full content.
+const EncryptedPayloadExt = ".enc"
+
+// stubFrontmatterKeys are the frontmatter keys an encrypted note's stub
+// keeps in the clear: enough for Dataview dashboards, index rebuilds,
+// and previous/next navigation, none of the session's content.
+var stubFrontmatterKeys = []string{
+	"date", "type", "project", "domain", "session_id", "iteration", "status", "tags", "previous",
+}
+
+// EncryptedStub renders the Obsidian-visible stand-in for an encrypted
+// session note: the identifying frontmatter from markdown, an
+// `encrypted: true` marker, and a body pointing at the payload file.
+func EncryptedStub(markdown, payloadName string) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	if lines, _, ok := splitFrontmatter(markdown); ok {
+		for _, key := range stubFrontmatterKeys {
+			if start, end := blockRange(lines, key); start >= 0 {
+				b.WriteString(strings.Join(lines[start:end], "\n"))
+				b.WriteByte('\n')
+			}
+		}
+	}
+	fmt.Fprintf(&b, "encrypted: true\npayload: %q\n---\n\n", payloadName)
+	b.WriteString("# Encrypted session note\n\n")
+	fmt.Fprintf(&b, "This session's note is encrypted at rest in `%s`, next to this file.\n", payloadName)
+	b.WriteString("Read it with `vv decrypt <this note>`. vv decrypts it transparently when\n")
+	b.WriteString("re-rendering the note (reprocess, checkpoint updates) if the key is configured.\n")
+	return b.String()
+}
+
+// IsEncryptedStub reports whether markdown is a stub written by
+// EncryptedStub.
+func IsEncryptedStub(markdown string) bool {
+	lines, _, ok := splitFrontmatter(markdown)
+	if !ok {
+		return false
+	}
+	for _, l := range lines {
+		if l == "encrypted: true" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncryptedStub_KeepsOnlyIdentifyingFrontmatter(t *testing.T) {
+	stub := EncryptedStub(annotatedNote, "2026-03-08-01.md.enc")
+	if !IsEncryptedStub(stub) {
+		t.Fatalf("stub not recognized:\n%s", stub)
+	}
+	for _, want := range []string{"date: 2026-03-08\n", "tags:\n  - vv-session\n  - keeper\n", `payload: "2026-03-08-01.md.enc"`} {
+		if !strings.Contains(stub, want) {
+			t.Errorf("stub missing %q:\n%s", want, stub)
+		}
+	}
+	for _, leak := range []string{"summary", "Old prose", "API team"} {
+		if strings.Contains(stub, leak) {
+			t.Errorf("stub leaks %q:\n%s", leak, stub)
+		}
+	}
+	if IsEncryptedStub(annotatedNote) {
+		t.Error("plain note recognized as a stub")
+	}
+}
@@ -73,7 +73,9 @@ type NoteData struct {
 	AllBranches         []string // all observed git branches
 	AutoCompactions     int      // auto-compaction count
 	Timeline            string   // rendered timeline section
+	Diagram             string   // fenced Mermaid activity diagram (empty = skip)
 	EstimatedCostUSD    float64  // estimated session cost in USD
+	EnrichmentCostUSD   float64  // estimated cost of vv's own enrichment call in USD
 	ToolEffectiveness   string   // rendered tool effectiveness section (empty = skip)
 	ParentSession       string   // parent entry UUID (non-empty = /continue session)
 	SessionTags         []string // pre-built tag list (e.g. ["vv-session", "implementation"])
@@ -163,6 +165,9 @@ func SessionNote(d NoteData) string {
 	if d.EstimatedCostUSD > 0 {
 		fmt.Fprintf(&b, "estimated_cost_usd: %.2f\n", d.EstimatedCostUSD)
 	}
+	if d.EnrichmentCostUSD > 0 {
+		fmt.Fprintf(&b, "enrichment_cost_usd: %.4f\n", d.EnrichmentCostUSD)
+	}
 	if len(d.SessionTags) > 0 {
 		fmt.Fprintf(&b, "tags: [%s]\n", strings.Join(d.SessionTags, ", "))
 	} else if d.Tag != "" {
@@ -289,6 +294,13 @@ func SessionNote(d NoteData) string {
 		b.WriteString("\n")
 	}
 
+	// Activity Diagram (Mermaid, opt-in via [notes] diagram)
+	if d.Diagram != "" {
+		b.WriteString("## Activity Diagram\n\n")
+		b.WriteString(d.Diagram)
+		b.WriteString("\n")
+	}
+
 	// Timeline (Task 21)
 	if d.Timeline != "" {
 		b.WriteString("## Timeline\n\n")
@@ -315,6 +327,9 @@ func SessionNote(d NoteData) string {
 		b.WriteString("\n")
 	}
 
+	// User region: kept verbatim when the note is re-rendered
+	b.WriteString(UserRegionStart + "\n" + UserRegionEnd + "\n\n")
+
 	// Footer
 	b.WriteString("---\n")
 	if d.EnrichedBy != "" {
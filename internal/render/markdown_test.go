@@ -740,3 +740,16 @@ func extractLine(text, substr string) string {
 	}
 	return "<not found>"
 }
+
+func TestSessionNote_Diagram(t *testing.T) {
+	d := NoteData{Date: "2026-01-01", Project: "p", Domain: "d", SessionID: "s", Title: "T", Summary: "S"}
+	if strings.Contains(SessionNote(d), "## Activity Diagram") {
+		t.Error("diagram section rendered without diagram data")
+	}
+	d.Diagram = "```mermaid\ngantt\n```\n"
+	d.Timeline = "14:00  Created x\n"
+	out := SessionNote(d)
+	if !strings.Contains(out, "## Activity Diagram\n\n```mermaid\ngantt\n```\n\n## Timeline") {
+		t.Errorf("diagram section missing or misplaced:\n%s", out)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package render
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/suykerbuyk/vibe-vault/internal/narrative"
+	"github.com/suykerbuyk/vibe-vault/internal/scaffold"
+)
+
+// SessionTemplateName is the file under the vault's Templates/ directory
+// that overrides the built-in session note layout.
+const SessionTemplateName = "session-note.tmpl"
+
+// DefaultSessionTemplate returns the embedded session template. Executing
+// it produces the same output as SessionNote; it is the starting point for
+// vault customizations and the baseline for `vv templates diff`.
+func DefaultSessionTemplate() []byte {
+	data, _ := scaffold.EmbeddedFS().ReadFile("templates/Templates/" + SessionTemplateName)
+	return data
+}
+
+// shippedSessionTemplates holds the SHA-256 of each session template a
+// vv release has embedded as its default. A vault copy matching one was
+// scaffolded rather than edited, so it tracks the current default
+// instead of pinning the layout it was installed with. When a release
+// changes the embedded template, append the new hash and keep the old.
+var shippedSessionTemplates = []string{
+	"9556f013659d02e66664e73211864f03ef1a152d5aae666986405033ae0bd0bd",
+}
+
+// isShippedSessionTemplate reports whether data is a session template vv
+// has shipped as its default.
+func isShippedSessionTemplate(data []byte) bool {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	for _, h := range shippedSessionTemplates {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// TemplateFuncs returns the helper functions available to session
+// templates, in addition to text/template's builtins.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"yaml":       escapeYAML,
+		"wikilink":   func(name string) string { return "[[" + name + "]]" },
+		"join":       func(sep string, list []string) string { return strings.Join(list, sep) },
+		"sortedKeys": sortedKeys,
+		"shas":       commitSHAs,
+		"default": func(def, s string) string {
+			if s == "" {
+				return def
+			}
+			return s
+		},
+		"lower":    strings.ToLower,
+		"upper":    strings.ToUpper,
+		"trim":     strings.TrimSpace,
+		"replace":  func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains": func(substr, s string) bool { return strings.Contains(s, substr) },
+		"find": func(pattern, s string) (string, error) {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return "", err
+			}
+			return re.FindString(s), nil
+		},
+	}
+}
+
+// ParseSessionTemplate parses text as a session template with the helper
+// funcs installed.
+func ParseSessionTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(TemplateFuncs()).Parse(text)
+}
+
+// LoadSessionTemplate reads Templates/session-note.tmpl from the vault.
+// It returns nil, nil when the file is absent or identical to a default
+// vv has shipped, so callers keep using SessionNote for uncustomized
+// vaults.
+func LoadSessionTemplate(vaultPath string) (*template.Template, error) {
+	if vaultPath == "" {
+		return nil, nil
+	}
+	path := filepath.Join(vaultPath, "Templates", SessionTemplateName)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read session template: %w", err)
+	}
+	if isShippedSessionTemplate(data) {
+		return nil, nil
+	}
+	tmpl, err := ParseSessionTemplate(SessionTemplateName, string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return tmpl, nil
+}
+
+// ExecuteSessionTemplate renders d through tmpl.
+func ExecuteSessionTemplate(tmpl *template.Template, d NoteData) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, d); err != nil {
+		return "", fmt.Errorf("execute %s: %w", tmpl.Name(), err)
+	}
+	return b.String(), nil
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func commitSHAs(commits []narrative.Commit) []string {
+	shas := make([]string, 0, len(commits))
+	for _, c := range commits {
+		shas = append(shas, c.SHA)
+	}
+	return shas
+}
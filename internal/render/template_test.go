@@ -0,0 +1,127 @@
+package render
+
+import (
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/suykerbuyk/vibe-vault/internal/narrative"
+)
+
+func templateFixtures() map[string]NoteData {
+	return map[string]NoteData{
+		"minimal": {Date: "2026-01-01", Project: "p", Domain: "d", SessionID: "s", Title: "T", Summary: "S"},
+		"full": {
+			Date: "2026-02-22", Project: "vibe-vault", Branch: "feature/auth", Domain: "work",
+			Model: "claude-opus-4-6", Source: "zed", SessionID: "sess-abc", Iteration: 2,
+			Duration: 15, Messages: 8, InputTokens: 5000, OutputTokens: 2000,
+			Title: "Implement auth", Summary: `Added "JWT" auth`, PreviousNote: "2026-02-21-03",
+			FilesChanged:        []string{"auth.go", "middleware.go"},
+			Decisions:           []string{"Use JWT over sessions"},
+			OpenThreads:         []string{"Add refresh tokens"},
+			EnrichedBy:          "grok-3-mini-fast",
+			Tag:                 "implementation",
+			RelatedNotes:        []RelatedNote{{Name: "a", Reason: "2 shared files"}, {Name: "b", Reason: "branch"}},
+			ToolCounts:          map[string]int{"Read": 3, "Edit": 2},
+			TotalTools:          5,
+			Status:              "checkpoint",
+			Commits:             []narrative.Commit{{SHA: "abc1234", Message: "feat: auth"}},
+			WorkPerformed:       "- did things\n",
+			ProseDialogue:       "> **User:** hi\n",
+			FrictionScore:       40,
+			Corrections:         2,
+			FrictionSignals:     []string{"2 corrections"},
+			ThinkingBlocks:      3,
+			CognitiveComplexity: "medium",
+			ReasoningHighlights: []string{"considered sessions"},
+			AvgTurnMs:           1200,
+			MaxTurnMs:           5000,
+			SessionName:         `auth "v2"`,
+			CCVersion:           "2.1.0",
+			AllBranches:         []string{"main", "feature/auth"},
+			AutoCompactions:     1,
+			Timeline:            "- 10:00 start\n",
+			Diagram:             "```mermaid\ngantt\n```\n",
+			EstimatedCostUSD:    1.234,
+			EnrichmentCostUSD:   0.00042,
+			ToolEffectiveness:   "- Edit retried\n\n",
+			ParentSession:       "uuid-1",
+			SessionTags:         []string{"vv-session", "implementation"},
+			Host:                "box",
+			User:                "me",
+			CWD:                 "~/code/vv",
+			OriginProject:       "vibe-vault",
+		},
+	}
+}
+
+func TestDefaultSessionTemplate_MatchesSessionNote(t *testing.T) {
+	tmpl, err := ParseSessionTemplate(SessionTemplateName, string(DefaultSessionTemplate()))
+	if err != nil {
+		t.Fatalf("parse default template: %v", err)
+	}
+	for name, d := range templateFixtures() {
+		got, err := ExecuteSessionTemplate(tmpl, d)
+		if err != nil {
+			t.Fatalf("%s: execute: %v", name, err)
+		}
+		if want := SessionNote(d); got != want {
+			t.Errorf("%s: default template drifted from SessionNote\n--- template ---\n%s\n--- SessionNote ---\n%s", name, got, want)
+		}
+	}
+}
+
+func TestShippedSessionTemplates_IncludesCurrentDefault(t *testing.T) {
+	if !isShippedSessionTemplate(DefaultSessionTemplate()) {
+		sum := sha256.Sum256(DefaultSessionTemplate())
+		t.Errorf("embedded session template changed; append %x to shippedSessionTemplates", sum)
+	}
+}
+
+func TestLoadSessionTemplate(t *testing.T) {
+	vault := t.TempDir()
+	tmplDir := filepath.Join(vault, "Templates")
+	os.MkdirAll(tmplDir, 0o755)
+	path := filepath.Join(tmplDir, SessionTemplateName)
+
+	if tmpl, err := LoadSessionTemplate(vault); tmpl != nil || err != nil {
+		t.Errorf("missing file: got (%v, %v), want (nil, nil)", tmpl, err)
+	}
+
+	os.WriteFile(path, DefaultSessionTemplate(), 0o644)
+	if tmpl, err := LoadSessionTemplate(vault); tmpl != nil || err != nil {
+		t.Errorf("default copy: got (%v, %v), want (nil, nil)", tmpl, err)
+	}
+
+	custom := "---\nticket: {{ find `[A-Z]+-[0-9]+` .Branch }}\n---\n\n# {{ .Title }}\n\n" +
+		"{{ range .Decisions }}- {{ . }}\n{{ end }}"
+	os.WriteFile(path, []byte(custom), 0o644)
+	tmpl, err := LoadSessionTemplate(vault)
+	if err != nil || tmpl == nil {
+		t.Fatalf("custom: got (%v, %v)", tmpl, err)
+	}
+	out, err := ExecuteSessionTemplate(tmpl, NoteData{Title: "T", Branch: "feat/ABC-123-login", Decisions: []string{"X"}})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if out != "---\nticket: ABC-123\n---\n\n# T\n\n- X\n" {
+		t.Errorf("got %q", out)
+	}
+
+	os.WriteFile(path, []byte("{{ .Title "), 0o644)
+	if _, err := LoadSessionTemplate(vault); err == nil {
+		t.Error("expected parse error for malformed template")
+	}
+}
+
+func TestExecuteSessionTemplate_UnknownField(t *testing.T) {
+	tmpl, err := ParseSessionTemplate("t", "{{ .NoSuchField }}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExecuteSessionTemplate(tmpl, NoteData{}); err == nil || !strings.Contains(err.Error(), "NoSuchField") {
+		t.Errorf("expected execution error naming the field, got %v", err)
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package render
+
+import (
+	"regexp"
+	"strings"
+)
+
+// User-owned parts of a session note. Everything between the user-region
+// markers, and the frontmatter keys in UserFrontmatterKeys, belong to the
+// person annotating the note: re-rendering a session (SessionEnd after a
+// checkpoint, vv reprocess) carries them forward verbatim. Tags are
+// shared: vv regenerates its own on every render and keeps the ones the
+// user added.
+const (
+	UserRegionStart = "<!-- vv:user:start -->"
+	UserRegionEnd   = "<!-- vv:user:end -->"
+)
+
+// UserFrontmatterKeys are the frontmatter keys vv never overwrites once a
+// note has them.
+var UserFrontmatterKeys = []string{"rating", "notes"}
+
+// ActivityTags are the activity classifications vv assigns a session,
+// from enrichment or the narrative heuristic. A re-render may swap one
+// for another, so none of them counts as a user-added tag.
+var ActivityTags = []string{"implementation", "debugging", "review", "planning", "exploration", "research"}
+
+var logseqPropertyRe = regexp.MustCompile(`^[A-Za-z0-9_-]+::( |$)`)
+
+// CarryUserContent returns next with prev's user region and user-owned
+// frontmatter keys carried over. A user region replaces the body of
+// next's marker pair, or is appended when next (e.g. from a customized
+// template) has none. A user key block — the "key:" line plus any
+// indented or list continuation lines — replaces next's block for that
+// key in place, or is added after the key it followed in prev. Tags in
+// prev that next lacks and that are not activity tags were added by the
+// user and are appended to next's list. Logseq notes, whose properties
+// are a leading "key:: value" block, are handled the same way. Notes
+// with neither keep only the user region.
+func CarryUserContent(prev, next string) string {
+	if prev == "" {
+		return next
+	}
+	if body, ok := userRegionBody(prev); ok {
+		next = setUserRegion(next, body)
+	}
+	split, join := splitFrontmatter, joinFrontmatter
+	if !strings.HasPrefix(next, "---\n") {
+		split, join = splitProperties, joinProperties
+	}
+	prevFM, _, ok := split(prev)
+	if !ok {
+		return next
+	}
+	nextFM, rest, ok := split(next)
+	if !ok {
+		return next
+	}
+	for _, key := range UserFrontmatterKeys {
+		nextFM = carryFrontmatterKey(prevFM, nextFM, key)
+	}
+	nextFM = carryUserTags(prevFM, nextFM)
+	return join(nextFM, rest)
+}
+
+// userRegionBody returns the text between the user-region markers.
+func userRegionBody(note string) (string, bool) {
+	start := strings.Index(note, UserRegionStart)
+	if start < 0 {
+		return "", false
+	}
+	from := start + len(UserRegionStart)
+	end := strings.Index(note[from:], UserRegionEnd)
+	if end < 0 {
+		return "", false
+	}
+	return note[from : from+end], true
+}
+
+func setUserRegion(note, body string) string {
+	start := strings.Index(note, UserRegionStart)
+	if start >= 0 {
+		from := start + len(UserRegionStart)
+		if end := strings.Index(note[from:], UserRegionEnd); end >= 0 {
+			return note[:from] + body + note[from+end:]
+		}
+	}
+	if !strings.HasSuffix(note, "\n") {
+		note += "\n"
+	}
+	return note + "\n" + UserRegionStart + body + UserRegionEnd + "\n"
+}
+
+// splitFrontmatter returns the lines between a leading "---" fence pair
+// and everything after the closing fence's newline.
+func splitFrontmatter(note string) (lines []string, rest string, ok bool) {
+	if !strings.HasPrefix(note, "---\n") {
+		return nil, "", false
+	}
+	body := note[len("---\n"):]
+	end := strings.Index(body, "\n---\n")
+	if end < 0 {
+		return nil, "", false
+	}
+	return strings.Split(body[:end], "\n"), body[end+len("\n---\n"):], true
+}
+
+func joinFrontmatter(lines []string, rest string) string {
+	return "---\n" + strings.Join(lines, "\n") + "\n---\n" + rest
+}
+
+// splitProperties returns the lines of a leading Logseq page-properties
+// block ("key:: value" lines) and everything after it.
+func splitProperties(note string) (lines []string, rest string, ok bool) {
+	for rest = note; rest != ""; {
+		line, after, _ := strings.Cut(rest, "\n")
+		if !logseqPropertyRe.MatchString(line) {
+			break
+		}
+		lines = append(lines, line)
+		rest = after
+	}
+	return lines, rest, len(lines) > 0
+}
+
+func joinProperties(lines []string, rest string) string {
+	return strings.Join(lines, "\n") + "\n" + rest
+}
+
+// blockRange locates key's block in frontmatter lines; end is exclusive.
+func blockRange(lines []string, key string) (start, end int) {
+	for i, l := range lines {
+		if !strings.HasPrefix(l, key+":") {
+			continue
+		}
+		end = i + 1
+		for end < len(lines) && (strings.HasPrefix(lines[end], " ") ||
+			strings.HasPrefix(lines[end], "\t") || strings.HasPrefix(lines[end], "- ")) {
+			end++
+		}
+		return i, end
+	}
+	return -1, -1
+}
+
+// carryFrontmatterKey copies key's block from prev into next. A key next
+// lacks is placed after the nearest preceding prev key next also has, so
+// re-renders do not shuffle the user's frontmatter.
+func carryFrontmatterKey(prev, next []string, key string) []string {
+	start, end := blockRange(prev, key)
+	if start < 0 {
+		return next
+	}
+	block := prev[start:end]
+	at := 0
+	if s, e := blockRange(next, key); s >= 0 {
+		at = s
+		next = append(next[:s:s], next[e:]...)
+	} else {
+		for i := start - 1; i >= 0; i-- {
+			if k, ok := topLevelKey(prev[i]); ok {
+				if _, e := blockRange(next, k); e >= 0 {
+					at = e
+					break
+				}
+			}
+		}
+	}
+	out := make([]string, 0, len(next)+len(block))
+	out = append(out, next[:at]...)
+	out = append(out, block...)
+	return append(out, next[at:]...)
+}
+
+// carryUserTags rewrites next's tags as its regenerated tags followed by
+// the tags the user added in prev.
+func carryUserTags(prev, next []string) []string {
+	ps, pe := blockRange(prev, "tags")
+	ns, ne := blockRange(next, "tags")
+	if ps < 0 || ns < 0 {
+		return next
+	}
+	tags := parseTags(next[ns:ne])
+	seen := make(map[string]bool)
+	for _, t := range append(tags, ActivityTags...) {
+		seen[t] = true
+	}
+	added := false
+	for _, t := range parseTags(prev[ps:pe]) {
+		if !seen[t] {
+			seen[t] = true
+			tags = append(tags, t)
+			added = true
+		}
+	}
+	if !added {
+		return next
+	}
+	out := make([]string, 0, len(next))
+	out = append(out, next[:ns]...)
+	if strings.HasPrefix(next[ns], "tags::") {
+		out = append(out, "tags:: "+strings.Join(tags, ", "))
+	} else {
+		out = append(out, "tags: ["+strings.Join(tags, ", ")+"]")
+	}
+	return append(out, next[ne:]...)
+}
+
+// parseTags reads a tags block written inline ("tags: [a, b]"), as a
+// scalar, as a YAML list, or as a Logseq property ("tags:: a, b").
+func parseTags(block []string) []string {
+	var tags []string
+	add := func(t string) {
+		if t = strings.Trim(strings.TrimSpace(t), `"'`); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	_, v, _ := strings.Cut(block[0], ":")
+	if logseq, ok := strings.CutPrefix(v, ":"); ok {
+		for _, t := range strings.Split(logseq, ",") {
+			add(t)
+		}
+	} else if v = strings.TrimSpace(v); strings.HasPrefix(v, "[") && strings.HasSuffix(v, "]") {
+		for _, t := range strings.Split(v[1:len(v)-1], ",") {
+			add(t)
+		}
+	} else {
+		add(v)
+	}
+	for _, l := range block[1:] {
+		if t, ok := strings.CutPrefix(strings.TrimSpace(l), "- "); ok {
+			add(t)
+		}
+	}
+	return tags
+}
+
+// topLevelKey returns the key of a "key: value" frontmatter line.
+func topLevelKey(line string) (string, bool) {
+	if line == "" || line[0] == ' ' || line[0] == '\t' || line[0] == '-' {
+		return "", false
+	}
+	k, _, ok := strings.Cut(line, ":")
+	return k, ok
+}
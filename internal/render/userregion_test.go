@@ -0,0 +1,153 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suykerbuyk/vibe-vault/internal/flavor"
+)
+
+const annotatedNote = `---
+date: 2026-03-08
+rating: 4
+tags:
+  - vv-session
+  - keeper
+summary: "Old"
+---
+
+# Title
+
+## What Happened
+
+Old prose.
+
+<!-- vv:user:start -->
+Follow up with the API team.
+
+- [ ] file ticket
+<!-- vv:user:end -->
+
+---
+*vv v0.1.0*
+`
+
+const freshNote = `---
+date: 2026-03-08
+tags: [vv-session, implementation]
+summary: "New"
+---
+
+# Title
+
+## What Happened
+
+New prose.
+
+<!-- vv:user:start -->
+<!-- vv:user:end -->
+
+---
+*vv v0.1.0*
+`
+
+func TestCarryUserContent(t *testing.T) {
+	got := CarryUserContent(annotatedNote, freshNote)
+	want := `---
+date: 2026-03-08
+rating: 4
+tags: [vv-session, implementation, keeper]
+summary: "New"
+---
+
+# Title
+
+## What Happened
+
+New prose.
+
+<!-- vv:user:start -->
+Follow up with the API team.
+
+- [ ] file ticket
+<!-- vv:user:end -->
+
+---
+*vv v0.1.0*
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if again := CarryUserContent(got, freshNote); again != got {
+		t.Errorf("carry is not idempotent:\n%s", again)
+	}
+}
+
+func TestCarryUserContent_RegeneratedTagsWin(t *testing.T) {
+	// The first render tagged the session debugging; the user added
+	// keeper. A re-render classifies it as implementation.
+	prev := "---\ntags: [vv-session, debugging, keeper]\n---\n\n# Title\n"
+	next := "---\ntags: [vv-session, implementation]\n---\n\n# Title\n"
+	want := "---\ntags: [vv-session, implementation, keeper]\n---\n\n# Title\n"
+	if got := CarryUserContent(prev, next); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// With nothing user-added, the regenerated tags stand as rendered.
+	prev = "---\ntags: [vv-session, debugging]\n---\n\n# Title\n"
+	if got := CarryUserContent(prev, next); got != next {
+		t.Errorf("stale generated tags carried:\n%s", got)
+	}
+}
+
+func TestCarryUserContent_Logseq(t *testing.T) {
+	// A Logseq vault stores the rendered frontmatter as page properties;
+	// the user rated the note, added a tag, and wrote in the user region.
+	next := flavor.Convert(freshNote, flavor.Logseq, nil)
+	prev := strings.NewReplacer(
+		"date:: 2026-03-08\n", "date:: 2026-03-08\nrating:: 4\nnotes:: revisit\n",
+		"tags:: vv-session, implementation", "tags:: vv-session, implementation, keeper",
+		"New prose.", "Old prose.",
+		UserRegionStart+"\n", UserRegionStart+"\nFollow up with the API team.\n",
+	).Replace(next)
+
+	got := CarryUserContent(prev, next)
+	want := "date:: 2026-03-08\nrating:: 4\nnotes:: revisit\ntags:: vv-session, implementation, keeper\nsummary:: New\n\n# Title\n"
+	if !strings.HasPrefix(got, want) {
+		t.Errorf("properties not carried:\n%s", got)
+	}
+	if !strings.Contains(got, "New prose.") || !strings.Contains(got, UserRegionStart+"\nFollow up with the API team.") {
+		t.Errorf("body or user region wrong:\n%s", got)
+	}
+	if again := CarryUserContent(got, next); again != got {
+		t.Errorf("carry is not idempotent:\n%s", again)
+	}
+}
+
+func TestCarryUserContent_NoMarkersInNewRender(t *testing.T) {
+	next := "---\nsummary: \"New\"\n---\n\n# Title\n"
+	got := CarryUserContent(annotatedNote, next)
+	if !strings.HasSuffix(got, "# Title\n\n"+UserRegionStart+"\nFollow up with the API team.\n\n- [ ] file ticket\n"+UserRegionEnd+"\n") {
+		t.Errorf("user region not appended:\n%s", got)
+	}
+}
+
+func TestCarryUserContent_NothingToCarry(t *testing.T) {
+	if got := CarryUserContent("", freshNote); got != freshNote {
+		t.Errorf("empty prev changed the note:\n%s", got)
+	}
+	plain := "# Title\n\nno frontmatter, no markers\n"
+	if got := CarryUserContent(plain, freshNote); got != freshNote {
+		t.Errorf("unannotated prev changed the note:\n%s", got)
+	}
+}
+
+func TestSessionNote_EmitsEmptyUserRegion(t *testing.T) {
+	out := SessionNote(NoteData{Title: "T", Date: "2026-03-08"})
+	if !strings.Contains(out, UserRegionStart+"\n"+UserRegionEnd+"\n\n---\n*vv v0.1.0*") {
+		t.Errorf("missing user region before footer:\n%s", out)
+	}
+}
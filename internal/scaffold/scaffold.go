@@ -4,24 +4,35 @@
 package scaffold
 
 import (
+	"bytes"
 	"embed"
 	"fmt"
 	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+
+	"github.com/suykerbuyk/vibe-vault/internal/flavor"
 )
 
 //go:embed all:templates
 var templates embed.FS
 
+// flavors holds the per-flavor overlays for non-Obsidian vaults. The
+// Obsidian files (.obsidian/, Dataview dashboards) live in templates.
+//
+//go:embed all:flavors
+var flavors embed.FS
+
 // EmbeddedFS returns the embedded template filesystem.
 func EmbeddedFS() embed.FS { return templates }
 
 // Options controls scaffold behavior.
 type Options struct {
-	GitInit bool // run git init after scaffolding
+	GitInit bool   // run git init after scaffolding
+	Flavor  string // "obsidian" (default), "logseq", or "markdown"
 }
 
 // vaultState describes what already exists at a target path.
@@ -39,9 +50,13 @@ func detectVault(targetPath string) vaultState {
 	hasObsidian := dirExists(filepath.Join(targetPath, ".obsidian"))
 	hasProjects := dirExists(filepath.Join(targetPath, "Projects"))
 	hasState := dirExists(filepath.Join(targetPath, ".vibe-vault"))
+	// Logseq and plain-markdown vaults have no .obsidian/; recognize them
+	// by their flavor marker or the scaffolded Dashboards/.
+	hasFlavorMarker := dirExists(filepath.Join(targetPath, "logseq")) ||
+		dirExists(filepath.Join(targetPath, "Dashboards"))
 
 	switch {
-	case hasObsidian && hasProjects:
+	case hasProjects && (hasObsidian || hasState || hasFlavorMarker):
 		return vaultVibeVault
 	case hasObsidian:
 		return vaultObsidian
@@ -52,8 +67,8 @@ func detectVault(targetPath string) vaultState {
 	}
 }
 
-// Init creates or adopts a vibe-vault Obsidian vault at targetPath.
-// It returns an action string ("created" or "adopted") and any error.
+// Init creates or adopts a vibe-vault at targetPath. opts.Flavor selects
+// the dashboards and app config written for a new vault. It returns an action string ("created" or "adopted") and any error.
 func Init(targetPath string, opts Options) (string, error) {
 	targetPath, err := filepath.Abs(targetPath)
 	if err != nil {
@@ -72,6 +87,10 @@ func Init(targetPath string, opts Options) (string, error) {
 
 	// vaultNone — scaffold a new vault.
 	vaultName := filepath.Base(targetPath)
+	f, err := flavor.Parse(opts.Flavor)
+	if err != nil {
+		return "", err
+	}
 
 	// Walk embedded templates and copy to target.
 	err = fs.WalkDir(templates, "templates", func(path string, d fs.DirEntry, err error) error {
@@ -87,6 +106,12 @@ func Init(targetPath string, opts Options) (string, error) {
 		if rel == "." {
 			return nil
 		}
+		if f != flavor.Obsidian && isObsidianOnly(rel) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
 
 		dest := filepath.Join(targetPath, rel)
 
@@ -115,6 +140,18 @@ func Init(targetPath string, opts Options) (string, error) {
 		return "", fmt.Errorf("scaffold vault: %w", err)
 	}
 
+	if f != flavor.Obsidian {
+		for rel, data := range flavorFiles(f) {
+			dest := filepath.Join(targetPath, rel)
+			if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+				return "", fmt.Errorf("scaffold vault: %w", err)
+			}
+			if err := os.WriteFile(dest, data, filePermission(rel)); err != nil {
+				return "", fmt.Errorf("scaffold vault: %w", err)
+			}
+		}
+	}
+
 	if opts.GitInit {
 		cmd := exec.Command("git", "init", targetPath)
 		cmd.Stdout = os.Stdout
@@ -127,6 +164,123 @@ func Init(targetPath string, opts Options) (string, error) {
 	return "created", nil
 }
 
+// isObsidianOnly reports whether a template path belongs to the Obsidian
+// flavor only: app settings and the Dataview-powered dashboards.
+func isObsidianOnly(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	for _, dir := range []string{".obsidian", "Dashboards"} {
+		if rel == dir || strings.HasPrefix(rel, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// flavorFiles returns the flavor-specific files keyed by vault-relative
+// slash path. For Obsidian these are the Obsidian-only template files.
+func flavorFiles(f flavor.Flavor) map[string][]byte {
+	out := make(map[string][]byte)
+	root, fsys := "flavors/"+string(f), fs.FS(flavors)
+	if f == flavor.Obsidian {
+		root, fsys = "templates", templates
+	}
+	fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		rel := strings.TrimPrefix(path, root+"/")
+		if f == flavor.Obsidian && !isObsidianOnly(rel) {
+			return nil
+		}
+		if data, err := fs.ReadFile(fsys, path); err == nil {
+			out[rel] = data
+		}
+		return nil
+	})
+	return out
+}
+
+// FlavorAction describes one file change made by ApplyFlavor.
+type FlavorAction struct {
+	RelPath string
+	Action  string // "created", "replaced", or "archived"
+}
+
+// flavorBackupDir holds files displaced by ApplyFlavor.
+const flavorBackupDir = "_archive/flavor-backup"
+
+// ApplyFlavor converts an existing vault's scaffolding to flavor f: it
+// writes f's dashboards and app config and moves dashboards belonging to
+// other flavors into _archive/flavor-backup/. Files that would be
+// overwritten with different content are backed up there first. Notes are
+// not touched — re-render them with vv reprocess.
+func ApplyFlavor(targetPath string, f flavor.Flavor) ([]FlavorAction, error) {
+	want := flavorFiles(f)
+	var actions []FlavorAction
+
+	backup := func(rel string) error {
+		dest := filepath.Join(targetPath, flavorBackupDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return err
+		}
+		return os.Rename(filepath.Join(targetPath, filepath.FromSlash(rel)), dest)
+	}
+
+	rels := make([]string, 0, len(want))
+	for rel := range want {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+	for _, rel := range rels {
+		dest := filepath.Join(targetPath, filepath.FromSlash(rel))
+		action := "created"
+		if existing, err := os.ReadFile(dest); err == nil {
+			if bytes.Equal(existing, want[rel]) {
+				continue
+			}
+			if err := backup(rel); err != nil {
+				return actions, fmt.Errorf("back up %s: %w", rel, err)
+			}
+			action = "replaced"
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return actions, err
+		}
+		if err := os.WriteFile(dest, want[rel], filePermission(rel)); err != nil {
+			return actions, fmt.Errorf("write %s: %w", rel, err)
+		}
+		actions = append(actions, FlavorAction{RelPath: rel, Action: action})
+	}
+
+	// Dashboards shipped by other flavors are stale under f.
+	stale := make(map[string]bool)
+	for _, other := range flavor.All {
+		if other == f {
+			continue
+		}
+		for rel := range flavorFiles(other) {
+			if _, keep := want[rel]; !keep && strings.HasPrefix(rel, "Dashboards/") {
+				stale[rel] = true
+			}
+		}
+	}
+	staleRels := make([]string, 0, len(stale))
+	for rel := range stale {
+		staleRels = append(staleRels, rel)
+	}
+	sort.Strings(staleRels)
+	for _, rel := range staleRels {
+		if _, err := os.Stat(filepath.Join(targetPath, filepath.FromSlash(rel))); err != nil {
+			continue
+		}
+		if err := backup(rel); err != nil {
+			return actions, fmt.Errorf("archive %s: %w", rel, err)
+		}
+		actions = append(actions, FlavorAction{RelPath: rel, Action: "archived"})
+	}
+	return actions, nil
+}
+
 // filePermission returns 0o755 for shell scripts and git hooks, 0o644 for everything else.
 func filePermission(rel string) os.FileMode {
 	if strings.HasSuffix(rel, ".sh") {
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/suykerbuyk/vibe-vault/internal/flavor"
 )
 
 func TestInit_CreatesVault(t *testing.T) {
@@ -152,3 +154,62 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestInit_LogseqFlavor(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "vault")
+	if _, err := Init(target, Options{Flavor: "logseq"}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	for _, rel := range []string{"logseq/config.edn", "Dashboards/sessions.md", "Templates/session-summary.md"} {
+		if _, err := os.Stat(filepath.Join(target, rel)); err != nil {
+			t.Errorf("missing %s", rel)
+		}
+	}
+	for _, rel := range []string{".obsidian", "Dashboards/analytics.md"} {
+		if _, err := os.Stat(filepath.Join(target, rel)); err == nil {
+			t.Errorf("unexpected %s in logseq vault", rel)
+		}
+	}
+	if detectVault(target) != vaultVibeVault {
+		t.Error("logseq vault not detected as a vibe-vault")
+	}
+
+	if _, err := Init(target, Options{Flavor: "roam"}); err != nil {
+		t.Fatalf("existing vault should be adopted before flavor validation: %v", err)
+	}
+	if _, err := Init(filepath.Join(t.TempDir(), "v"), Options{Flavor: "roam"}); err == nil {
+		t.Error("expected error for unknown flavor")
+	}
+}
+
+func TestApplyFlavor(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "vault")
+	if _, err := Init(target, Options{}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	os.WriteFile(filepath.Join(target, "Dashboards", "sessions.md"), []byte("my edits"), 0o644)
+
+	actions, err := ApplyFlavor(target, flavor.Logseq)
+	if err != nil {
+		t.Fatalf("ApplyFlavor: %v", err)
+	}
+	got := make(map[string]string)
+	for _, a := range actions {
+		got[a.RelPath] = a.Action
+	}
+	if got["logseq/config.edn"] != "created" || got["Dashboards/sessions.md"] != "replaced" || got["Dashboards/analytics.md"] != "archived" {
+		t.Errorf("actions = %v", got)
+	}
+	backup, err := os.ReadFile(filepath.Join(target, "_archive", "flavor-backup", "Dashboards", "sessions.md"))
+	if err != nil || string(backup) != "my edits" {
+		t.Errorf("backup = %q, %v", backup, err)
+	}
+	if _, err := os.Stat(filepath.Join(target, "Dashboards", "analytics.md")); err == nil {
+		t.Error("obsidian-only dashboard left in place")
+	}
+
+	// Re-applying is a no-op.
+	if actions, _ := ApplyFlavor(target, flavor.Logseq); len(actions) != 0 {
+		t.Errorf("second apply: %v", actions)
+	}
+}
@@ -5,6 +5,7 @@ package session
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -13,9 +14,9 @@ import (
 	"strings"
 	"time"
 
-	"github.com/suykerbuyk/vibe-vault/internal/atomicfile"
 	"github.com/suykerbuyk/vibe-vault/internal/config"
 	"github.com/suykerbuyk/vibe-vault/internal/enrichment"
+	"github.com/suykerbuyk/vibe-vault/internal/flavor"
 	"github.com/suykerbuyk/vibe-vault/internal/friction"
 	"github.com/suykerbuyk/vibe-vault/internal/index"
 	"github.com/suykerbuyk/vibe-vault/internal/llm"
@@ -58,13 +59,15 @@ type CaptureOpts struct {
 	TranscriptPath string
 	CWD            string
 	SessionID      string
-	Source         string       // source identifier (e.g. "zed"); empty = "claude-code"
-	Force          bool         // skip dedup, overwrite existing note
-	Checkpoint     bool         // provisional capture (Stop hook)
-	SkipEnrichment bool         // skip LLM enrichment
-	Provider       llm.Provider // LLM provider (nil = heuristic only)
-	Index          *index.Index // shared index for batch operations (nil = load/save per call)
-	AutoCaptured   bool         // mark note as auto-captured (lower confidence)
+	Source         string             // source identifier (e.g. "zed"); empty = "claude-code"
+	Force          bool               // skip dedup, overwrite existing note
+	Checkpoint     bool               // provisional capture (Stop hook)
+	SkipEnrichment bool               // skip LLM enrichment
+	Provider       llm.Provider       // LLM provider (nil = heuristic only)
+	Enrichment     *enrichment.Result // result from Enrich, applied instead of calling Provider
+	Index          *index.Index       // shared index for batch operations (nil = load/save per call)
+	AutoCaptured   bool               // mark note as auto-captured (lower confidence)
+	DryRun         bool               // render only: fill CaptureResult.Markdown, write nothing
 	// ProjectRoot is the absolute project-root path; resolved by caller via
 	// session.DetectProjectRoot. Used by CaptureFromParsed for sessionclaim
 	// integration (M8 architectural cleanup, Phase 4 of
@@ -112,8 +115,11 @@ type CaptureResult struct {
 	Reason              string
 	FrictionScore       int
 	FrictionAlert       string
-	EnrichmentAttempted bool // true if the enrichment LLM call was made (regardless of outcome)
-	EnrichmentApplied   bool // true when enrichment returned usable content and populated note fields
+	EnrichmentAttempted bool                // true if the enrichment LLM call was made (regardless of outcome)
+	EnrichmentApplied   bool                // true when enrichment returned usable content and populated note fields
+	EnrichedBy          string              // model (or provider/model when a fallback chain served it) behind the enrichment
+	Markdown            string              // rendered note; set only for DryRun captures
+	Entry               *index.SessionEntry // entry a real capture would index; set only for DryRun captures
 }
 
 // Capture processes a transcript and writes a session note.
@@ -165,7 +171,7 @@ func Capture(opts CaptureOpts, cfg config.Config) (*CaptureResult, error) {
 	// first or hook fired first). UpdateHarnessSessionID self-bootstraps via
 	// AcquireOrRefresh (H5), so a fresh hook-only short session still gets
 	// a valid claim file. Errors are logged warnings, never propagated.
-	if opts.ProjectRoot != "" && sessionID != "" {
+	if opts.ProjectRoot != "" && sessionID != "" && !opts.DryRun {
 		if updateErr := updateHarnessSessionID(opts.ProjectRoot, sessionID); updateErr != nil {
 			log.Printf("warning: sessionclaim.UpdateHarnessSessionID: %v", updateErr)
 		}
@@ -196,7 +202,7 @@ func CaptureFromParsed(t *transcript.Transcript, info Info,
 	transcriptPath := opts.TranscriptPath
 
 	// Skip trivial sessions (< 2 user messages)
-	if t.Stats.UserMessages < 2 && t.Stats.AssistantMessages < 2 {
+	if trivial(t) {
 		return &CaptureResult{Skipped: true, Reason: "trivial session (< 2 messages)"}, nil
 	}
 
@@ -336,6 +342,7 @@ func CaptureFromParsed(t *transcript.Transcript, info Info,
 
 		// Timeline (Phase 5 Task 21)
 		noteData.Timeline = narrative.RenderTimeline(narr.Segments)
+		noteData.Diagram = narrative.RenderMermaid(narr.Segments, cfg.Notes.Diagram)
 	}
 
 	// Reasoning highlights from thinking blocks (Phase 4 Task 15)
@@ -414,55 +421,39 @@ func CaptureFromParsed(t *transcript.Transcript, info Info,
 	// LLM enrichment (graceful: skip on error or if disabled)
 	// Skip when prose extraction produced output — the prose subsumes enrichment's purpose.
 	var enrichmentAttempted, enrichmentApplied bool
-	if !opts.SkipEnrichment && opts.Provider != nil && noteData.ProseDialogue == "" {
-		enrichmentAttempted = true
-		var filesChanged []string
-		for f := range t.Stats.FilesWritten {
-			filesChanged = append(filesChanged, sanitize.CompressHome(f))
-		}
-		sort.Strings(filesChanged)
-
-		enrichInput := enrichment.PromptInput{
-			UserText:      transcript.UserText(t),
-			AssistantText: transcript.AssistantText(t),
-			FilesChanged:  filesChanged,
-			ToolCounts:    t.Stats.ToolCounts,
-			Duration:      int(t.Stats.Duration.Minutes()),
-			UserMessages:  t.Stats.UserMessages,
-			AsstMessages:  t.Stats.AssistantMessages,
-		}
-
-		// Pass narrative context to enrichment for refinement
-		if narr != nil {
-			enrichInput.NarrativeSummary = narr.Summary
-			enrichInput.NarrativeTag = narr.Tag
-			for _, seg := range narr.Segments {
-				for _, a := range seg.Activities {
-					enrichInput.Activities = append(enrichInput.Activities, a.Description)
-				}
-			}
-		}
-
-		timeout := time.Duration(cfg.Enrichment.TimeoutSeconds) * time.Second
-		if timeout == 0 {
-			timeout = 10 * time.Second
-		}
-		enrichCtx, enrichCancel := context.WithTimeout(context.Background(), timeout)
-		defer enrichCancel()
-
-		enrichResult, enrichErr := enrichment.Generate(enrichCtx, opts.Provider, enrichInput)
-		if enrichErr != nil {
-			log.Printf("warning: enrichment failed: %v", enrichErr)
+	var enrichedBy string
+	if !opts.SkipEnrichment && noteData.ProseDialogue == "" {
+		enrichResult := opts.Enrichment
+		if enrichResult == nil && opts.Provider != nil {
+			enrichmentAttempted = true
+			enrichResult = generateEnrichment(context.Background(), opts.Provider, t, narr, info.Project, sessionID, cfg)
 		}
 		if enrichResult != nil {
+			enrichmentAttempted = true
 			if enrichResult.Summary != "" {
 				noteData.Summary = enrichResult.Summary
 			}
 			noteData.Decisions = enrichResult.Decisions
 			noteData.OpenThreads = enrichResult.OpenThreads
 			noteData.Tag = enrichResult.Tag
-			noteData.EnrichedBy = cfg.Enrichment.Model
+			// A fallback chain reports the link that answered.
+			enrichModel := cfg.Enrichment.Model
+			noteData.EnrichedBy = enrichModel
+			if enrichResult.Provider != "" {
+				enrichModel = enrichResult.Model
+				noteData.EnrichedBy = enrichResult.Provider + "/" + enrichResult.Model
+			}
+			enrichedBy = noteData.EnrichedBy
 			enrichmentApplied = true
+			// What producing this enrichment cost, even when a reprocess
+			// served it from the LLM cache.
+			if cfg.Pricing.Enabled {
+				noteData.EnrichmentCostUSD = stats.EstimateCost(cfg.Pricing, stats.CostInput{
+					Model:        enrichModel,
+					InputTokens:  enrichResult.Usage.InputTokens,
+					OutputTokens: enrichResult.Usage.OutputTokens,
+				})
+			}
 		}
 	}
 
@@ -478,7 +469,7 @@ func CaptureFromParsed(t *transcript.Transcript, info Info,
 	// cfg.Staging.Root override flows through; without this, EnsureInit
 	// would re-resolve via Root() (XDG default) and bootstrap the wrong
 	// dir while the write went to the cfg path.
-	if opts.StagingRoot != "" {
+	if opts.StagingRoot != "" && !opts.DryRun {
 		if initErr := staging.EnsureInitAt(opts.StagingRoot, info.Project); initErr != nil {
 			log.Printf("warning: staging EnsureInitAt(%s, %s): %v", opts.StagingRoot, info.Project, initErr)
 		}
@@ -562,8 +553,88 @@ func CaptureFromParsed(t *transcript.Transcript, info Info,
 	// Build session tags from config
 	noteData.SessionTags = cfg.SessionTags(noteData.Tag)
 
-	// Render markdown
-	markdown := render.SessionNote(noteData)
+	// Render markdown, then adapt it to the configured vault flavor
+	markdown := renderSessionNote(cfg.VaultPath, noteData)
+	if f := cfg.Flavor(); f != flavor.Obsidian {
+		targets := make(map[string]string)
+		if previousNotePath != "" {
+			targets[filenameNoExt(previousNotePath)] = previousNotePath
+		}
+		for _, r := range related {
+			targets[filenameNoExt(r.Entry.NotePath)] = r.Entry.NotePath
+		}
+		markdown = flavor.Convert(markdown, f, noteResolver(relPath, targets))
+	}
+
+	// Re-rendering the same session (SessionEnd after a checkpoint,
+	// reprocess) keeps the prior note's user region and user-owned
+	// frontmatter keys.
+	if prevPath != "" {
+		prev, readErr := ReadNote(prevPath, cfg)
+		if readErr == nil {
+			markdown = render.CarryUserContent(prev, markdown)
+		} else if !os.IsNotExist(readErr) {
+			log.Printf("warning: could not read prior note %s, annotations not carried: %v", prevPath, readErr)
+		}
+	}
+
+	// Probe context availability for effectiveness measurement
+	ctxAvail := probeContextAvailable(cfg.VaultPath, info.Project, idx)
+
+	// The index entry for this capture; a dry run hands it back unsaved.
+	entry := index.SessionEntry{
+		SessionID:         sessionID,
+		NotePath:          relPath,
+		Project:           info.Project,
+		Domain:            info.Domain,
+		Date:              frontmatterDate,
+		Iteration:         iteration,
+		Title:             noteData.Title,
+		Model:             info.Model,
+		Duration:          int(t.Stats.Duration.Minutes()),
+		CreatedAt:         time.Now(),
+		Summary:           noteData.Summary,
+		Decisions:         noteData.Decisions,
+		OpenThreads:       noteData.OpenThreads,
+		Tag:               noteData.Tag,
+		FilesChanged:      noteData.FilesChanged,
+		Commits:           commitSHAs(commits),
+		Branch:            info.Branch,
+		TranscriptPath:    transcriptPath,
+		Checkpoint:        opts.Checkpoint,
+		Source:            opts.Source,
+		ToolCounts:        t.Stats.ToolCounts,
+		ActivityCounts:    activityMix(narr),
+		ToolUses:          t.Stats.ToolUses,
+		TokensIn:          noteData.InputTokens,
+		TokensOut:         noteData.OutputTokens,
+		Messages:          noteData.Messages,
+		Corrections:       frictionCorrections(frictionResult),
+		FrictionScore:     frictionScore(frictionResult),
+		EstimatedCostUSD:  noteData.EstimatedCostUSD,
+		EnrichmentCostUSD: noteData.EnrichmentCostUSD,
+		ParentUUID:        t.Stats.ParentUUID,
+		Context:           ctxAvail,
+	}
+
+	// Dry run: hand back the render without touching the note, the
+	// staging repo, or the index.
+	if opts.DryRun {
+		return &CaptureResult{
+			NotePath:            relPath,
+			Project:             info.Project,
+			Domain:              info.Domain,
+			Iteration:           iteration,
+			Title:               noteData.Title,
+			FrictionScore:       frictionScore(frictionResult),
+			FrictionAlert:       frictionAlert,
+			EnrichmentAttempted: enrichmentAttempted,
+			EnrichmentApplied:   enrichmentApplied,
+			EnrichedBy:          enrichedBy,
+			Markdown:            markdown,
+			Entry:               &entry,
+		}, nil
+	}
 
 	// Mechanism 3: remove prior session note before writing the new one
 	// (only when the prior path differs from the candidate path, which
@@ -573,6 +644,7 @@ func CaptureFromParsed(t *transcript.Transcript, info Info,
 		if removeErr := os.Remove(prevPath); removeErr != nil && !os.IsNotExist(removeErr) {
 			log.Printf("warning: could not remove prior session note %s: %v", prevPath, removeErr)
 		}
+		_ = os.Remove(prevPath + render.EncryptedPayloadExt)
 	}
 
 	if err := os.MkdirAll(filepath.Dir(absPath), 0o755); err != nil {
@@ -587,7 +659,8 @@ func CaptureFromParsed(t *transcript.Transcript, info Info,
 	if opts.StagingRoot != "" {
 		stampPath = ""
 	}
-	if err := atomicfile.Write(stampPath, absPath, []byte(markdown)); err != nil {
+	written, err := WriteNote(stampPath, absPath, markdown, info.Domain, cfg)
+	if err != nil {
 		return nil, fmt.Errorf("write note: %w", err)
 	}
 
@@ -598,47 +671,13 @@ func CaptureFromParsed(t *transcript.Transcript, info Info,
 	if opts.StagingRoot != "" {
 		stagingDir := filepath.Join(opts.StagingRoot, info.Project)
 		commitMsg := fmt.Sprintf("session: %s/%s", info.Project, filepath.Base(absPath))
-		if commitErr := staging.Commit(stagingDir, absPath, commitMsg); commitErr != nil {
+		if commitErr := staging.CommitPaths(stagingDir, written, commitMsg); commitErr != nil {
 			log.Printf("warning: staging commit failed for %s: %v", absPath, commitErr)
 		}
 	}
 
-	// Probe context availability for effectiveness measurement
-	ctxAvail := probeContextAvailable(cfg.VaultPath, info.Project, idx)
-
 	// Update index
-	idx.Add(index.SessionEntry{
-		SessionID:        sessionID,
-		NotePath:         relPath,
-		Project:          info.Project,
-		Domain:           info.Domain,
-		Date:             frontmatterDate,
-		Iteration:        iteration,
-		Title:            noteData.Title,
-		Model:            info.Model,
-		Duration:         int(t.Stats.Duration.Minutes()),
-		CreatedAt:        time.Now(),
-		Summary:          noteData.Summary,
-		Decisions:        noteData.Decisions,
-		OpenThreads:      noteData.OpenThreads,
-		Tag:              noteData.Tag,
-		FilesChanged:     noteData.FilesChanged,
-		Commits:          commitSHAs(commits),
-		Branch:           info.Branch,
-		TranscriptPath:   transcriptPath,
-		Checkpoint:       opts.Checkpoint,
-		Source:           opts.Source,
-		ToolCounts:       t.Stats.ToolCounts,
-		ToolUses:         t.Stats.ToolUses,
-		TokensIn:         noteData.InputTokens,
-		TokensOut:        noteData.OutputTokens,
-		Messages:         noteData.Messages,
-		Corrections:      frictionCorrections(frictionResult),
-		FrictionScore:    frictionScore(frictionResult),
-		EstimatedCostUSD: noteData.EstimatedCostUSD,
-		ParentUUID:       t.Stats.ParentUUID,
-		Context:          ctxAvail,
-	})
+	idx.Add(entry)
 
 	// Save index only if we own it (not shared batch mode)
 	if opts.Index == nil {
@@ -657,6 +696,7 @@ func CaptureFromParsed(t *transcript.Transcript, info Info,
 		FrictionAlert:       frictionAlert,
 		EnrichmentAttempted: enrichmentAttempted,
 		EnrichmentApplied:   enrichmentApplied,
+		EnrichedBy:          enrichedBy,
 	}, nil
 }
 
@@ -743,6 +783,50 @@ func countTodayEntries(idx *index.Index, project, date string) int {
 	return count
 }
 
+// activityMix returns per-kind activity counts for the index, or nil when
+// no narrative was extracted.
+func activityMix(narr *narrative.Narrative) map[string]int {
+	if narr == nil {
+		return nil
+	}
+	return narrative.ActivityMix(narr.Segments)
+}
+
+// noteResolver links note names to their indexed paths for the markdown
+// flavor. Staging notes (absolute paths) are later mirrored to a different
+// vault location, so links from or to them fall back to bare names.
+func noteResolver(notePath string, targets map[string]string) flavor.Resolver {
+	if filepath.IsAbs(notePath) {
+		return nil
+	}
+	for name, p := range targets {
+		if filepath.IsAbs(p) {
+			delete(targets, name)
+		}
+	}
+	return flavor.RelativeResolver(notePath, targets)
+}
+
+// renderSessionNote renders through the vault's customized
+// Templates/session-note.tmpl when present, falling back to the built-in
+// layout when the template is absent, unchanged, or fails.
+func renderSessionNote(vaultPath string, d render.NoteData) string {
+	tmpl, err := render.LoadSessionTemplate(vaultPath)
+	if err != nil {
+		log.Printf("warning: %v; using built-in layout", err)
+		return render.SessionNote(d)
+	}
+	if tmpl == nil {
+		return render.SessionNote(d)
+	}
+	out, err := render.ExecuteSessionTemplate(tmpl, d)
+	if err != nil {
+		log.Printf("warning: session template: %v; using built-in layout", err)
+		return render.SessionNote(d)
+	}
+	return out
+}
+
 func filenameNoExt(path string) string {
 	base := filepath.Base(path)
 	ext := filepath.Ext(base)
@@ -784,3 +868,118 @@ func fileNonEmpty(path string) bool {
 	}
 	return info.Size() > 0
 }
+
+// trivial reports whether t is too short to be worth a note.
+func trivial(t *transcript.Transcript) bool {
+	return t.Stats.UserMessages < 2 && t.Stats.AssistantMessages < 2
+}
+
+// Enrich runs the LLM enrichment CaptureFromParsed would for a parsed
+// session, so batch callers can enrich concurrently ahead of a serialized
+// write and hand the result over in CaptureOpts.Enrichment. It returns
+// nil wherever Capture would not enrich — no provider, a trivial session,
+// or prose dialogue that subsumes enrichment — and when the call fails,
+// which is logged.
+func Enrich(ctx context.Context, provider llm.Provider, t *transcript.Transcript, info Info,
+	narr *narrative.Narrative, dialogue *prose.Dialogue, cfg config.Config) *enrichment.Result {
+	if provider == nil || trivial(t) || (dialogue != nil && prose.Render(dialogue) != "") {
+		return nil
+	}
+	return generateEnrichment(ctx, provider, t, narr, info.Project, info.SessionID, cfg.WithProjectOverlay(info.Project))
+}
+
+// generateEnrichment makes the enrichment call for t under the configured
+// per-call timeout, labelled for the usage ledger. Failures are logged
+// and return nil: enrichment never blocks a note.
+func generateEnrichment(ctx context.Context, provider llm.Provider, t *transcript.Transcript,
+	narr *narrative.Narrative, project, sessionID string, cfg config.Config) *enrichment.Result {
+	enrichInput := EnrichmentInput(t, narr, cfg)
+
+	timeout := time.Duration(cfg.Enrichment.TimeoutSeconds) * time.Second
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	// The timeout budgets each call; map-reduce makes several.
+	timeout *= time.Duration(enrichment.Calls(enrichInput))
+	enrichCtx, enrichCancel := context.WithTimeout(ctx, timeout)
+	defer enrichCancel()
+	enrichCtx = llm.WithUsageLabels(enrichCtx, llm.UsageLabels{
+		Purpose: llm.PurposeEnrichment,
+		Project: project,
+		Session: sessionID,
+	})
+
+	result, err := enrichment.Generate(enrichCtx, provider, enrichInput)
+	if errors.Is(err, llm.ErrCacheMiss) {
+		// A cache-only preview; the caller reports the miss.
+		return nil
+	}
+	if err != nil {
+		log.Printf("warning: enrichment failed: %v", err)
+		return nil
+	}
+	return result
+}
+
+// EnrichmentInput assembles the enrichment prompt input for t: transcript
+// text, files written, tool counts, the narrative's heuristic summary and
+// activities, and the compaction chunks for map-reduce. Shared by Capture
+// and vv enrich eval so evaluations exercise the production prompt.
+func EnrichmentInput(t *transcript.Transcript, narr *narrative.Narrative, cfg config.Config) enrichment.PromptInput {
+	var filesChanged []string
+	for f := range t.Stats.FilesWritten {
+		filesChanged = append(filesChanged, sanitize.CompressHome(f))
+	}
+	sort.Strings(filesChanged)
+
+	input := enrichment.PromptInput{
+		UserText:      transcript.UserText(t),
+		AssistantText: transcript.AssistantText(t),
+		FilesChanged:  filesChanged,
+		ToolCounts:    t.Stats.ToolCounts,
+		Duration:      int(t.Stats.Duration.Minutes()),
+		UserMessages:  t.Stats.UserMessages,
+		AsstMessages:  t.Stats.AssistantMessages,
+		Chunks:        enrichmentChunks(t, narr),
+		Chunking: enrichment.ChunkOptions{
+			Mode:       cfg.Enrichment.Chunked,
+			ChunkChars: cfg.Enrichment.ChunkChars,
+			MaxChunks:  cfg.Enrichment.MaxChunks,
+		},
+	}
+
+	// Pass narrative context to enrichment for refinement
+	if narr != nil {
+		input.NarrativeSummary = narr.Summary
+		input.NarrativeTag = narr.Tag
+		for _, seg := range narr.Segments {
+			for _, a := range seg.Activities {
+				input.Activities = append(input.Activities, a.Description)
+			}
+		}
+	}
+	return input
+}
+
+// enrichmentChunks splits t at compaction boundaries for map-reduce
+// enrichment, pairing each stretch with its narrative segment's opening
+// request and activities when a narrative was extracted.
+func enrichmentChunks(t *transcript.Transcript, narr *narrative.Narrative) []enrichment.Chunk {
+	raw := narrative.SegmentEntries(t.Entries)
+	chunks := make([]enrichment.Chunk, 0, len(raw))
+	for i, entries := range raw {
+		seg := &transcript.Transcript{Entries: entries}
+		c := enrichment.Chunk{
+			UserText:      transcript.UserText(seg),
+			AssistantText: transcript.AssistantText(seg),
+		}
+		if narr != nil && i < len(narr.Segments) {
+			c.UserRequest = narr.Segments[i].UserRequest
+			for _, a := range narr.Segments[i].Activities {
+				c.Activities = append(c.Activities, a.Description)
+			}
+		}
+		chunks = append(chunks, c)
+	}
+	return chunks
+}
@@ -4,9 +4,11 @@
 package session
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -15,7 +17,9 @@ import (
 	"github.com/suykerbuyk/vibe-vault/internal/index"
 	"github.com/suykerbuyk/vibe-vault/internal/narrative"
 	"github.com/suykerbuyk/vibe-vault/internal/prose"
+	"github.com/suykerbuyk/vibe-vault/internal/render"
 	"github.com/suykerbuyk/vibe-vault/internal/transcript"
+	"github.com/suykerbuyk/vibe-vault/internal/vaultcrypt"
 )
 
 func testConfig(t *testing.T) config.Config {
@@ -78,6 +82,80 @@ func TestCaptureFromParsed_Basic(t *testing.T) {
 	}
 }
 
+func TestCaptureFromParsed_VaultTemplate(t *testing.T) {
+	for _, tc := range []struct {
+		name, tmpl, want string
+	}{
+		{"custom", "---\nticket: none\n---\n\n# {{ .Title }}\n", "ticket: none"},
+		{"broken falls back", "{{ .NoSuchField }}", "## What Happened"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			os.MkdirAll(filepath.Join(cfg.VaultPath, "Templates"), 0o755)
+			os.WriteFile(filepath.Join(cfg.VaultPath, "Templates", "session-note.tmpl"), []byte(tc.tmpl), 0o644)
+
+			tr := &transcript.Transcript{Stats: transcript.Stats{
+				SessionID: "tmpl-1", UserMessages: 3, AssistantMessages: 3,
+				StartTime: time.Date(2026, 3, 8, 10, 0, 0, 0, time.UTC),
+			}}
+			info := Info{Project: "testproj", Domain: "personal", SessionID: "tmpl-1"}
+			idx := &index.Index{Entries: make(map[string]index.SessionEntry)}
+
+			result, err := CaptureFromParsed(tr, info, nil, nil, CaptureOpts{Index: idx}, cfg)
+			if err != nil {
+				t.Fatalf("CaptureFromParsed error: %v", err)
+			}
+			data, err := os.ReadFile(filepath.Join(cfg.VaultPath, result.NotePath))
+			if err != nil {
+				t.Fatalf("read note: %v", err)
+			}
+			if !strings.Contains(string(data), tc.want) {
+				t.Errorf("note missing %q:\n%s", tc.want, data)
+			}
+		})
+	}
+}
+
+func TestCaptureFromParsed_VaultFlavor(t *testing.T) {
+	for _, tc := range []struct {
+		flavor, prefix, absent string
+	}{
+		{"logseq", "date:: 2026-03-08\ntype:: session\n", "---\ndate:"},
+		{"markdown", "---\ndate: 2026-03-08\n", "[["},
+	} {
+		t.Run(tc.flavor, func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.VaultFlavor = tc.flavor
+			idx := &index.Index{Entries: make(map[string]index.SessionEntry)}
+			for i, id := range []string{"prev", "cur"} {
+				tr := &transcript.Transcript{Stats: transcript.Stats{
+					SessionID: id, UserMessages: 3, AssistantMessages: 3,
+					StartTime: time.Date(2026, 3, 8, 10+i, 0, 0, 0, time.UTC),
+				}}
+				info := Info{Project: "testproj", Domain: "personal", SessionID: id}
+				result, err := CaptureFromParsed(tr, info, nil, nil, CaptureOpts{Index: idx}, cfg)
+				if err != nil {
+					t.Fatalf("CaptureFromParsed error: %v", err)
+				}
+				if id != "cur" {
+					continue
+				}
+				data, err := os.ReadFile(filepath.Join(cfg.VaultPath, result.NotePath))
+				if err != nil {
+					t.Fatalf("read note: %v", err)
+				}
+				note := string(data)
+				if !strings.HasPrefix(note, tc.prefix) {
+					t.Errorf("note does not start with %q:\n%s", tc.prefix, note)
+				}
+				if strings.Contains(note, tc.absent) {
+					t.Errorf("note contains %q:\n%s", tc.absent, note)
+				}
+			}
+		})
+	}
+}
+
 func TestCaptureFromParsed_ZedSource(t *testing.T) {
 	cfg := testConfig(t)
 	os.MkdirAll(filepath.Join(cfg.VaultPath, "Projects", "myproj", "sessions"), 0o755)
@@ -953,3 +1031,203 @@ func TestCaptureFromParsed_CrashRecovery_OrphanFile(t *testing.T) {
 	}
 }
 
+
+func TestCaptureFromParsed_DryRunWritesNothing(t *testing.T) {
+	cfg := testConfig(t)
+
+	tr := &transcript.Transcript{
+		Stats: transcript.Stats{
+			SessionID:         "dry-1",
+			UserMessages:      3,
+			AssistantMessages: 3,
+			StartTime:         time.Date(2026, 3, 8, 10, 0, 0, 0, time.UTC),
+		},
+	}
+	info := Info{Project: "testproj", Domain: "personal", SessionID: "dry-1"}
+	idx := &index.Index{Entries: map[string]index.SessionEntry{
+		"dry-1": {SessionID: "dry-1", Project: "testproj", NotePath: "Projects/testproj/sessions/old.md", Iteration: 4},
+	}}
+
+	result, err := CaptureFromParsed(tr, info, nil, nil, CaptureOpts{Index: idx, Force: true, DryRun: true}, cfg)
+	if err != nil {
+		t.Fatalf("CaptureFromParsed error: %v", err)
+	}
+	if !strings.Contains(result.Markdown, "iteration: 4") {
+		t.Errorf("Markdown missing preserved iteration:\n%s", result.Markdown)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.VaultPath, result.NotePath)); !os.IsNotExist(err) {
+		t.Errorf("dry run wrote %s", result.NotePath)
+	}
+	if got := idx.Entries["dry-1"].NotePath; got != "Projects/testproj/sessions/old.md" {
+		t.Errorf("dry run updated index NotePath to %q", got)
+	}
+}
+
+func TestCaptureFromParsed_CheckpointOverwriteKeepsAnnotations(t *testing.T) {
+	cfg := testConfig(t)
+
+	tr := &transcript.Transcript{
+		Stats: transcript.Stats{
+			SessionID:         "annotated-1",
+			UserMessages:      3,
+			AssistantMessages: 3,
+			ToolUses:          2,
+			StartTime:         time.Date(2026, 3, 8, 10, 0, 0, 0, time.UTC),
+		},
+	}
+	info := Info{Project: "proj", Domain: "personal", SessionID: "annotated-1"}
+	idx := &index.Index{Entries: make(map[string]index.SessionEntry)}
+
+	first, err := CaptureFromParsed(tr, info, nil, nil, CaptureOpts{Index: idx, Checkpoint: true}, cfg)
+	if err != nil || first.Skipped {
+		t.Fatalf("checkpoint capture: %v %+v", err, first)
+	}
+
+	// Annotate the checkpoint note the way a user would in Obsidian.
+	path := filepath.Join(cfg.VaultPath, first.NotePath)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	note := strings.Replace(string(data), "\n---\n\n", "\nrating: 5\n---\n\n", 1)
+	note = strings.Replace(note, render.UserRegionStart+"\n", render.UserRegionStart+"\nRoot cause was the cache TTL.\n", 1)
+	if err := os.WriteFile(path, []byte(note), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tr.Stats.UserMessages = 5
+	final, err := CaptureFromParsed(tr, info, nil, nil, CaptureOpts{Index: idx}, cfg)
+	if err != nil || final.Skipped {
+		t.Fatalf("final capture: %v %+v", err, final)
+	}
+	got, err := os.ReadFile(filepath.Join(cfg.VaultPath, final.NotePath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"rating: 5\n", "Root cause was the cache TTL.\n" + render.UserRegionEnd, "messages: 8", "status: completed"} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("final note missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestCaptureFromParsed_LogseqOverwriteKeepsAnnotations(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.VaultFlavor = "logseq"
+
+	tr := &transcript.Transcript{
+		Stats: transcript.Stats{
+			SessionID:         "annotated-ls",
+			UserMessages:      3,
+			AssistantMessages: 3,
+			StartTime:         time.Date(2026, 3, 8, 10, 0, 0, 0, time.UTC),
+		},
+	}
+	info := Info{Project: "proj", Domain: "personal", SessionID: "annotated-ls"}
+	idx := &index.Index{Entries: make(map[string]index.SessionEntry)}
+
+	first, err := CaptureFromParsed(tr, info, nil, nil, CaptureOpts{Index: idx, Checkpoint: true}, cfg)
+	if err != nil || first.Skipped {
+		t.Fatalf("checkpoint capture: %v %+v", err, first)
+	}
+
+	// Annotate the page the way a user would in Logseq.
+	path := filepath.Join(cfg.VaultPath, first.NotePath)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	note := "rating:: 5\n" + string(data)
+	note = regexp.MustCompile(`(?m)^tags:: .*$`).ReplaceAllString(note, "$0, keeper")
+	note = strings.Replace(note, render.UserRegionStart+"\n", render.UserRegionStart+"\nRoot cause was the cache TTL.\n", 1)
+	if err := os.WriteFile(path, []byte(note), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tr.Stats.UserMessages = 5
+	final, err := CaptureFromParsed(tr, info, nil, nil, CaptureOpts{Index: idx}, cfg)
+	if err != nil || final.Skipped {
+		t.Fatalf("final capture: %v %+v", err, final)
+	}
+	got, err := os.ReadFile(filepath.Join(cfg.VaultPath, final.NotePath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"rating:: 5\n", ", keeper\n", "Root cause was the cache TTL.\n" + render.UserRegionEnd, "messages:: 8", "status:: completed"} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("final note missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestCaptureFromParsed_EncryptedNoteWritesStub(t *testing.T) {
+	cfg := testConfig(t)
+	keyFile := filepath.Join(t.TempDir(), "vault.key")
+	if err := vaultcrypt.GenerateKeyFile(keyFile); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Encryption = config.EncryptionConfig{KeyFile: keyFile, NoteDomains: []string{"work"}}
+
+	tr := &transcript.Transcript{
+		Stats: transcript.Stats{
+			SessionID:         "sealed-1",
+			UserMessages:      3,
+			AssistantMessages: 3,
+			ToolUses:          2,
+			StartTime:         time.Date(2026, 3, 9, 10, 0, 0, 0, time.UTC),
+		},
+	}
+	info := Info{Project: "client", Domain: "work", SessionID: "sealed-1"}
+	narr := &narrative.Narrative{Title: "Migrate billing tables", Summary: "Moved billing tables to the new schema"}
+	idx := &index.Index{Entries: make(map[string]index.SessionEntry)}
+
+	first, err := CaptureFromParsed(tr, info, narr, nil, CaptureOpts{Index: idx, Checkpoint: true, SkipEnrichment: true}, cfg)
+	if err != nil || first.Skipped {
+		t.Fatalf("checkpoint capture: %v %+v", err, first)
+	}
+	path := filepath.Join(cfg.VaultPath, first.NotePath)
+	stub, _ := os.ReadFile(path)
+	if !render.IsEncryptedStub(string(stub)) || strings.Contains(string(stub), "billing") {
+		t.Fatalf("note is not a content-free stub:\n%s", stub)
+	}
+	if !strings.Contains(string(stub), "project: client") {
+		t.Errorf("stub lost identifying frontmatter:\n%s", stub)
+	}
+	sealed, _ := os.ReadFile(path + render.EncryptedPayloadExt)
+	if !vaultcrypt.IsSealed(sealed) {
+		t.Fatal("payload missing or not sealed")
+	}
+
+	// Annotate the decrypted note and re-seal it, as vv reprocess --accept would.
+	note, err := ReadNote(path, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	note = strings.Replace(note, render.UserRegionStart+"\n", render.UserRegionStart+"\nCustomer asked for a rollback plan.\n", 1)
+	if _, err := WriteNote("", path, note, "work", cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	final, err := CaptureFromParsed(tr, info, narr, nil, CaptureOpts{Index: idx, SkipEnrichment: true}, cfg)
+	if err != nil || final.Skipped {
+		t.Fatalf("final capture: %v %+v", err, final)
+	}
+	got, err := ReadNote(filepath.Join(cfg.VaultPath, final.NotePath), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Migrate billing tables", "Customer asked for a rollback plan.", "status: completed"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("decrypted final note missing %q:\n%s", want, got)
+		}
+	}
+
+	// Without the key the note is unreadable and a new write is refused.
+	cfg.Encryption.KeyFile = filepath.Join(t.TempDir(), "missing.key")
+	if _, err := ReadNote(filepath.Join(cfg.VaultPath, final.NotePath), cfg); !errors.Is(err, vaultcrypt.ErrNoKey) {
+		t.Errorf("ReadNote without key: err = %v, want ErrNoKey", err)
+	}
+	if _, err := WriteNote("", path, note, "work", cfg); !errors.Is(err, vaultcrypt.ErrNoKey) {
+		t.Errorf("WriteNote without key: err = %v, want ErrNoKey", err)
+	}
+}
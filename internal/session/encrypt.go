@@ -0,0 +1,85 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suykerbuyk/vibe-vault/internal/atomicfile"
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/render"
+	"github.com/suykerbuyk/vibe-vault/internal/vaultcrypt"
+)
+
+// ReadNote returns a session note's markdown. For an encrypted note the
+// file at path is a stub; its payload is decrypted with the key from
+// cfg's [encryption] section.
+func ReadNote(path string, cfg config.Config) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if !render.IsEncryptedStub(string(data)) {
+		return string(data), nil
+	}
+	sealed, err := os.ReadFile(path + render.EncryptedPayloadExt)
+	if err != nil {
+		return "", fmt.Errorf("read encrypted payload: %w", err)
+	}
+	key, err := vaultcrypt.Load(cfg.Encryption.KeyFile, cfg.Encryption.PassphraseEnv)
+	if err == nil && key == nil {
+		err = vaultcrypt.ErrNoKey
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s is encrypted: %w", filepath.Base(path), err)
+	}
+	plain, err := key.Open(sealed)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return string(plain), nil
+}
+
+// WriteNote writes a session note to path, encrypting it when cfg opts
+// the note's domain or project in: the payload goes to path+".enc" and
+// an Obsidian-visible stub to path. A plaintext write removes any stale
+// payload. Returns every file written, for staging commits; stampPath
+// is passed through to atomicfile.Write.
+func WriteNote(stampPath, path, markdown, domain string, cfg config.Config) ([]string, error) {
+	payload := path + render.EncryptedPayloadExt
+	if !cfg.Encryption.EncryptNotes(domain) {
+		if err := atomicfile.Write(stampPath, path, []byte(markdown)); err != nil {
+			return nil, err
+		}
+		if err := os.Remove(payload); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("remove stale encrypted payload: %w", err)
+		}
+		return []string{path}, nil
+	}
+
+	// Never fall back to plaintext: a note opted into encryption with no
+	// key available is an error.
+	key, err := vaultcrypt.Load(cfg.Encryption.KeyFile, cfg.Encryption.PassphraseEnv)
+	if err == nil && key == nil {
+		err = fmt.Errorf("%w: set key_file or passphrase_env under [encryption]", vaultcrypt.ErrNoKey)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("note encryption is on for this session: %w", err)
+	}
+	sealed, err := key.Seal([]byte(markdown))
+	if err != nil {
+		return nil, fmt.Errorf("encrypt note: %w", err)
+	}
+	if err := atomicfile.Write(stampPath, payload, sealed); err != nil {
+		return nil, err
+	}
+	stub := render.EncryptedStub(markdown, filepath.Base(payload))
+	if err := atomicfile.Write(stampPath, path, []byte(stub)); err != nil {
+		return nil, err
+	}
+	return []string{payload, path}, nil
+}
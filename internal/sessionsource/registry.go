@@ -18,9 +18,9 @@ import (
 // goroutine; the registry retains the cancellation handle so a
 // subsequent Stop tears it down cleanly.
 //
-// Registry is safe for concurrent use, although in practice the
-// orchestrator (cmd/vv/main.go runZedWatch and the hook handler)
-// drives it from a single goroutine. The mutex is conservative.
+// Registry is safe for concurrent use. runZedWatch drives it from a
+// single goroutine; internal/daemon starts each source on its own
+// goroutine and stops them all from another on reload or shutdown.
 type Registry struct {
 	mu      sync.Mutex
 	sources map[string]*entry
@@ -26,19 +26,29 @@ import (
 //
 // Target ≤100ms warm; benchmarked in tests.
 func Commit(stagingDir, absPath, msg string) error {
+	if absPath == "" {
+		return fmt.Errorf("staging.Commit: absPath is empty")
+	}
+	return CommitPaths(stagingDir, []string{absPath}, msg)
+}
+
+// CommitPaths is Commit for several files landing together in one
+// commit, such as an encrypted note's stub and payload.
+func CommitPaths(stagingDir string, absPaths []string, msg string) error {
 	if stagingDir == "" {
 		return fmt.Errorf("staging.Commit: stagingDir is empty")
 	}
-	if absPath == "" {
+	if len(absPaths) == 0 {
 		return fmt.Errorf("staging.Commit: absPath is empty")
 	}
 	if msg == "" {
 		return fmt.Errorf("staging.Commit: msg is empty")
 	}
 
-	// `git add -- <absPath>` accepts absolute paths inside the repo.
-	if _, err := vaultsync.GitCommand(stagingDir, gitTimeout, "add", "--", absPath); err != nil {
-		return fmt.Errorf("git add %s: %w", absPath, err)
+	// `git add -- <absPath>...` accepts absolute paths inside the repo.
+	args := append([]string{"add", "--"}, absPaths...)
+	if _, err := vaultsync.GitCommand(stagingDir, gitTimeout, args...); err != nil {
+		return fmt.Errorf("git add %s: %w", strings.Join(absPaths, " "), err)
 	}
 
 	// Probe before commit: if nothing is staged (re-add of identical
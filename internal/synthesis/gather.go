@@ -14,6 +14,7 @@ import (
 	"github.com/suykerbuyk/vibe-vault/internal/config"
 	"github.com/suykerbuyk/vibe-vault/internal/index"
 	"github.com/suykerbuyk/vibe-vault/internal/noteparse"
+	"github.com/suykerbuyk/vibe-vault/internal/vaultcrypt"
 )
 
 const (
@@ -31,6 +32,9 @@ func GatherInput(notePath string, cwd string, cfg config.Config, idx *index.Inde
 	if err != nil {
 		return nil, fmt.Errorf("parse session note: %w", err)
 	}
+	if note.Sealed {
+		return nil, fmt.Errorf("session note is encrypted: %w", vaultcrypt.ErrNoKey)
+	}
 
 	diff := gatherGitDiff(note.Commits, cwd)
 	knowledgeMD := readFileCapped(filepath.Join(cfg.VaultPath, "Projects", note.Project, "knowledge.md"), maxKnowledgeBytes)
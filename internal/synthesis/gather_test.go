@@ -0,0 +1,63 @@
+package synthesis
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/suykerbuyk/vibe-vault/internal/config"
+	"github.com/suykerbuyk/vibe-vault/internal/render"
+	"github.com/suykerbuyk/vibe-vault/internal/vaultcrypt"
+)
+
+const encryptedSessionNote = `---
+date: 2026-03-01
+type: session
+project: proj
+session_id: enc-001
+summary: "Rotated the signing keys"
+tags: [vv-session, implementation]
+---
+
+# Rotated the signing keys
+
+## Key Decisions
+
+- Keep the old key for verification only
+`
+
+func TestGatherInput_EncryptedNote(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.VaultPath = t.TempDir()
+	notePath := filepath.Join(cfg.VaultPath, "Projects", "proj", "sessions", "2026-03-01-01.md")
+	os.MkdirAll(filepath.Dir(notePath), 0o755)
+
+	key, err := vaultcrypt.NewKey(bytes.Repeat([]byte{9}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := key.Seal([]byte(encryptedSessionNote))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := notePath + render.EncryptedPayloadExt
+	os.WriteFile(payload, sealed, 0o644)
+	os.WriteFile(notePath, []byte(render.EncryptedStub(encryptedSessionNote, filepath.Base(payload))), 0o644)
+
+	vaultcrypt.SetDefault(key)
+	t.Cleanup(func() { vaultcrypt.SetDefault(nil) })
+	in, err := GatherInput(notePath, t.TempDir(), cfg, nil)
+	if err != nil {
+		t.Fatalf("GatherInput: %v", err)
+	}
+	if in.SessionNote.Summary != "Rotated the signing keys" || len(in.SessionNote.Decisions) != 1 {
+		t.Errorf("synthesis saw the stub, not the note: %+v", in.SessionNote)
+	}
+
+	vaultcrypt.SetDefault(nil)
+	if _, err := GatherInput(notePath, t.TempDir(), cfg, nil); !errors.Is(err, vaultcrypt.ErrNoKey) {
+		t.Errorf("without a key: err = %v, want ErrNoKey", err)
+	}
+}
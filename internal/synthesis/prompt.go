@@ -3,6 +3,8 @@ package synthesis
 import (
 	"fmt"
 	"strings"
+
+	"github.com/suykerbuyk/vibe-vault/internal/llm"
 )
 
 const systemPrompt = `You are a session synthesis agent for a developer knowledge base. Your job is to analyze a completed coding session and produce structured JSON describing what should be updated in the project's living documents.
@@ -28,6 +30,11 @@ Rules:
 - "current_state" must only contain invariant bullets (counts, versions, IDs). Narrative and shipped-capability prose belongs in "features".
 `
 
+// PromptVersion fingerprints the synthesis prompt and reply schema.
+func PromptVersion() string {
+	return llm.PromptVersion(systemPrompt, resultSchema)
+}
+
 // buildUserPrompt assembles all input fields into a labeled prompt.
 func buildUserPrompt(input *Input) string {
 	var b strings.Builder
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/suykerbuyk/vibe-vault/internal/llm"
 )
@@ -18,15 +19,18 @@ func Synthesize(ctx context.Context, provider llm.Provider, input *Input) (*Resu
 
 	userPrompt := buildUserPrompt(input)
 
-	resp, err := provider.ChatCompletion(ctx, llm.Request{
+	resp, violations, err := llm.CompleteStructured(ctx, provider, llm.Request{
 		System:      systemPrompt,
 		UserPrompt:  userPrompt,
 		Temperature: 0.3,
-		JSONMode:    true,
-	})
+		Schema:      resultSchema,
+	}, checkAgainstInput(input))
 	if err != nil {
 		return nil, fmt.Errorf("LLM call: %w", err)
 	}
+	if len(violations) > 0 {
+		log.Printf("synthesis: reply off-schema after repair: %s", strings.Join(violations, "; "))
+	}
 
 	var result Result
 	if err := json.Unmarshal([]byte(resp.Content), &result); err != nil {
@@ -40,6 +44,121 @@ func Synthesize(ctx context.Context, provider llm.Provider, input *Input) (*Resu
 	return &result, nil
 }
 
+var minIndex = 0
+
+// resultSchema is the synthesis reply contract, enforced by
+// llm.CompleteStructured and sent as native structured output where the
+// provider supports it. The enums mirror the filters below, which still
+// salvage a reply that stays invalid after repair.
+var resultSchema = &llm.Schema{
+	Title: "session_synthesis",
+	Type:  "object",
+	Properties: map[string]*llm.Schema{
+		"learnings": {Type: "array", Items: &llm.Schema{
+			Type:     "object",
+			Required: []string{"section", "entry"},
+			Properties: map[string]*llm.Schema{
+				"section": {Type: "string", Enum: []string{"Decisions", "Patterns", "Learnings"}},
+				"entry":   {Type: "string"},
+			},
+		}},
+		"stale_entries": {Type: "array", Items: &llm.Schema{
+			Type:     "object",
+			Required: []string{"file", "section", "index"},
+			Properties: map[string]*llm.Schema{
+				"file":    {Type: "string", Enum: []string{"knowledge.md", "resume.md"}},
+				"section": {Type: "string"},
+				"index":   {Type: "integer", Minimum: &minIndex},
+				"entry":   {Type: "string"},
+				"reason":  {Type: "string"},
+			},
+		}},
+		"resume_update": {Type: "object", Nullable: true, Properties: map[string]*llm.Schema{
+			"current_state": {Type: "string"},
+			"open_threads":  {Type: "string"},
+			"features":      {Type: "string"},
+		}},
+		"task_updates": {Type: "array", Items: &llm.Schema{
+			Type:     "object",
+			Required: []string{"name", "action"},
+			Properties: map[string]*llm.Schema{
+				"name":   {Type: "string"},
+				"action": {Type: "string", Enum: []string{"complete", "update_status"}},
+				"status": {Type: "string"},
+				"reason": {Type: "string"},
+			},
+		}},
+		"reasoning": {Type: "string"},
+	},
+}
+
+// checkAgainstInput returns the validation rules that depend on what the
+// model was shown: stale entries must name an existing section and a
+// bullet index inside it, and task updates must name an active task.
+// Documents and task lists absent from the prompt are not checked.
+func checkAgainstInput(input *Input) func(string) []string {
+	docs := map[string]map[string]int{}
+	if input.KnowledgeMD != "" {
+		docs["knowledge.md"] = sectionBulletCounts(input.KnowledgeMD)
+	}
+	if input.ResumeMD != "" {
+		docs["resume.md"] = sectionBulletCounts(input.ResumeMD)
+	}
+	tasks := map[string]bool{}
+	for _, t := range input.TaskSummaries {
+		tasks[t.Name] = true
+	}
+
+	return func(content string) []string {
+		var result Result
+		if err := json.Unmarshal([]byte(content), &result); err != nil {
+			return []string{fmt.Sprintf("not valid JSON: %v", err)}
+		}
+		var errs []string
+		for i, e := range result.StaleEntries {
+			counts, ok := docs[e.File]
+			if !ok {
+				continue
+			}
+			n, ok := counts[e.Section]
+			switch {
+			case !ok:
+				errs = append(errs, fmt.Sprintf("$.stale_entries[%d].section: %s has no section %q", i, e.File, e.Section))
+			case e.Index >= n:
+				errs = append(errs, fmt.Sprintf("$.stale_entries[%d].index: section %q of %s has %d bullets, index %d is out of range", i, e.Section, e.File, n, e.Index))
+			}
+		}
+		if len(tasks) > 0 {
+			for i, u := range result.TaskUpdates {
+				if !tasks[u.Name] {
+					errs = append(errs, fmt.Sprintf("$.task_updates[%d].name: %q is not an active task", i, u.Name))
+				}
+			}
+		}
+		return errs
+	}
+}
+
+// sectionBulletCounts maps each "## " heading of md to its bullet count,
+// counting bullets the way numberBullets numbers them.
+func sectionBulletCounts(md string) map[string]int {
+	counts := map[string]int{}
+	section := ""
+	for _, line := range strings.Split(md, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "## "):
+			section = strings.TrimSpace(strings.TrimPrefix(trimmed, "## "))
+			counts[section] += 0
+		case strings.HasPrefix(trimmed, "# "):
+			section = ""
+		case section != "" && strings.HasPrefix(trimmed, "- "):
+			counts[section]++
+		}
+	}
+	return counts
+}
+
 var validSections = map[string]bool{
 	"Decisions": true,
 	"Patterns":  true,
@@ -3,6 +3,7 @@ package synthesis
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/suykerbuyk/vibe-vault/internal/llm"
@@ -153,3 +154,51 @@ func TestSynthesize_NegativeIndex(t *testing.T) {
 		t.Errorf("negative index should be dropped, got %d", len(result.StaleEntries))
 	}
 }
+
+// sequenceProvider returns replies in order and records each request.
+type sequenceProvider struct {
+	replies []string
+	reqs    []llm.Request
+}
+
+func (s *sequenceProvider) ChatCompletion(_ context.Context, req llm.Request) (*llm.Response, error) {
+	s.reqs = append(s.reqs, req)
+	return &llm.Response{Content: s.replies[len(s.reqs)-1]}, nil
+}
+
+func (s *sequenceProvider) Name() string { return "sequence" }
+
+func TestSynthesize_RepairsOutOfRangeIndex(t *testing.T) {
+	bad := `{"learnings":[],"stale_entries":[{"file":"knowledge.md","section":"Patterns","index":4,"entry":"old","reason":"gone"}],"resume_update":null,"task_updates":[{"name":"nope","action":"complete"}],"reasoning":""}`
+	good := `{"learnings":[],"stale_entries":[{"file":"knowledge.md","section":"Patterns","index":1,"entry":"old","reason":"gone"}],"resume_update":null,"task_updates":[],"reasoning":""}`
+	sp := &sequenceProvider{replies: []string{bad, good}}
+	input := &Input{
+		SessionNote:   &noteparse.Note{Summary: "test"},
+		KnowledgeMD:   "# Knowledge\n\n## Patterns\n\n- first\n- old pattern\n",
+		TaskSummaries: []TaskSummary{{Name: "real-task"}},
+	}
+
+	result, err := Synthesize(context.Background(), sp, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sp.reqs) != 2 {
+		t.Fatalf("calls = %d, want one repair", len(sp.reqs))
+	}
+	repair := sp.reqs[1].UserPrompt
+	for _, want := range []string{`section "Patterns" of knowledge.md has 2 bullets, index 4 is out of range`, `"nope" is not an active task`} {
+		if !strings.Contains(repair, want) {
+			t.Errorf("repair prompt missing %q", want)
+		}
+	}
+	if len(result.StaleEntries) != 1 || result.StaleEntries[0].Index != 1 {
+		t.Errorf("stale entries = %+v, want the repaired one", result.StaleEntries)
+	}
+}
+
+func TestResultSchema_RejectsUnknownSection(t *testing.T) {
+	errs := resultSchema.Validate([]byte(`{"learnings":[{"section":"Gotchas","entry":"x"}]}`))
+	if len(errs) != 1 || !strings.Contains(errs[0], "$.learnings[0].section") {
+		t.Errorf("violations = %v", errs)
+	}
+}
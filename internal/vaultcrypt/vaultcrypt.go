@@ -0,0 +1,261 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+// Package vaultcrypt seals transcript archives and session notes with
+// AES-256-GCM so they can live on a shared git remote.
+//
+// A sealed file is a small header followed by the GCM ciphertext:
+//
+//	"VVE1" | kind (1 byte) | [salt (16 bytes), passphrase kind only] | nonce (12 bytes) | ciphertext+tag
+//
+// The header is authenticated as additional data. Kind 1 uses a 32-byte
+// key file directly; kind 2 derives the key from a passphrase with
+// PBKDF2-SHA256 and the salt in the header.
+package vaultcrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Magic starts every sealed file.
+const Magic = "VVE1"
+
+const (
+	kindKeyFile    byte = 1
+	kindPassphrase byte = 2
+
+	keySize  = 32
+	saltSize = 16
+
+	// PBKDF2Iterations follows OWASP's 2023 guidance for PBKDF2-SHA256.
+	PBKDF2Iterations = 600_000
+)
+
+// ErrNoKey means encryption is configured but the key is unavailable:
+// the key file is missing or the passphrase variable is unset.
+var ErrNoKey = errors.New("encryption key not available")
+
+// Key seals and opens vault files. A passphrase key derives lazily, once
+// per salt, so loading one costs nothing until it is used.
+type Key struct {
+	source     string // for messages: "key file ~/..." or "passphrase from $VAR"
+	raw        []byte // key-file key
+	passphrase []byte
+
+	mu      sync.Mutex
+	salt    []byte            // salt new passphrase envelopes are sealed with
+	derived map[string][]byte // salt → derived key
+}
+
+// Load resolves the configured key. It returns (nil, nil) when neither
+// keyFile nor passphraseEnv is set, and an error wrapping ErrNoKey when
+// the configured source is unavailable. A key file takes precedence; it
+// holds 32 bytes raw, hex-encoded, or base64-encoded.
+func Load(keyFile, passphraseEnv string) (*Key, error) {
+	switch {
+	case keyFile != "":
+		data, err := os.ReadFile(keyFile)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: key file %s not found", ErrNoKey, keyFile)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read key file: %w", err)
+		}
+		raw, err := parseKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", keyFile, err)
+		}
+		return &Key{source: "key file " + keyFile, raw: raw}, nil
+	case passphraseEnv != "":
+		pass := os.Getenv(passphraseEnv)
+		if pass == "" {
+			return nil, fmt.Errorf("%w: $%s is not set", ErrNoKey, passphraseEnv)
+		}
+		return &Key{source: "passphrase from $" + passphraseEnv, passphrase: []byte(pass)}, nil
+	}
+	return nil, nil
+}
+
+// NewKey returns a key-file key for raw, which must be 32 bytes.
+func NewKey(raw []byte) (*Key, error) {
+	if len(raw) != keySize {
+		return nil, fmt.Errorf("key is %d bytes, want %d", len(raw), keySize)
+	}
+	return &Key{source: "key", raw: bytes.Clone(raw)}, nil
+}
+
+// GenerateKeyFile writes a new random key, hex-encoded, to path with
+// owner-only permissions. It refuses to overwrite an existing file.
+func GenerateKeyFile(path string) error {
+	raw := make([]byte, keySize)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(hex.EncodeToString(raw) + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func parseKey(data []byte) ([]byte, error) {
+	if len(data) == keySize {
+		return data, nil
+	}
+	s := strings.TrimSpace(string(data))
+	if b, err := hex.DecodeString(s); err == nil && len(b) == keySize {
+		return b, nil
+	}
+	if b, err := base64.StdEncoding.DecodeString(s); err == nil && len(b) == keySize {
+		return b, nil
+	}
+	return nil, fmt.Errorf("key must be %d bytes (raw, hex, or base64)", keySize)
+}
+
+// Source describes where the key came from.
+func (k *Key) Source() string { return k.source }
+
+// IsSealed reports whether data starts with a sealed-file header.
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(Magic))
+}
+
+// Seal encrypts plain into a self-describing envelope.
+func (k *Key) Seal(plain []byte) ([]byte, error) {
+	header := []byte(Magic)
+	var key []byte
+	if k.raw != nil {
+		header = append(header, kindKeyFile)
+		key = k.raw
+	} else {
+		salt, err := k.sealSalt()
+		if err != nil {
+			return nil, err
+		}
+		header = append(append(header, kindPassphrase), salt...)
+		if key, err = k.derive(salt); err != nil {
+			return nil, err
+		}
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	header = append(header, nonce...)
+	return aead.Seal(header, nonce, plain, header), nil
+}
+
+// Open authenticates and decrypts an envelope produced by Seal.
+func (k *Key) Open(sealed []byte) ([]byte, error) {
+	if !IsSealed(sealed) || len(sealed) < len(Magic)+1 {
+		return nil, errors.New("not a sealed vault file")
+	}
+	pos := len(Magic)
+	kind := sealed[pos]
+	pos++
+	var key []byte
+	switch kind {
+	case kindKeyFile:
+		if k.raw == nil {
+			return nil, fmt.Errorf("file was sealed with a key file, but %s is configured", k.source)
+		}
+		key = k.raw
+	case kindPassphrase:
+		if k.passphrase == nil {
+			return nil, fmt.Errorf("file was sealed with a passphrase, but %s is configured", k.source)
+		}
+		if len(sealed) < pos+saltSize {
+			return nil, errors.New("truncated sealed file")
+		}
+		var err error
+		if key, err = k.derive(sealed[pos : pos+saltSize]); err != nil {
+			return nil, err
+		}
+		pos += saltSize
+	default:
+		return nil, fmt.Errorf("unknown sealed file kind %d", kind)
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < pos+aead.NonceSize()+aead.Overhead() {
+		return nil, errors.New("truncated sealed file")
+	}
+	header := sealed[:pos+aead.NonceSize()]
+	plain, err := aead.Open(nil, sealed[pos:len(header)], sealed[len(header):], header)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: authentication failed (wrong key or corrupted file)")
+	}
+	return plain, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealSalt returns the salt this key seals passphrase envelopes with,
+// generated once so a run sealing many files derives only once.
+func (k *Key) sealSalt() ([]byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.salt == nil {
+		salt := make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		k.salt = salt
+	}
+	return k.salt, nil
+}
+
+func (k *Key) derive(salt []byte) ([]byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if key, ok := k.derived[string(salt)]; ok {
+		return key, nil
+	}
+	key, err := pbkdf2.Key(sha256.New, string(k.passphrase), salt, PBKDF2Iterations, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+	if k.derived == nil {
+		k.derived = make(map[string][]byte)
+	}
+	k.derived[string(salt)] = key
+	return key, nil
+}
+
+var defaultKey atomic.Pointer[Key]
+
+// SetDefault installs the key readers such as archive.Decompress use to
+// open sealed files. The CLI sets it once the config is loaded.
+func SetDefault(k *Key) { defaultKey.Store(k) }
+
+// Default returns the key installed by SetDefault, or nil.
+func Default() *Key { return defaultKey.Load() }
@@ -0,0 +1,114 @@
+// Copyright 2026 John Suykerbuyk <john@syketech.com>
+// SPDX-License-Identifier: Apache-2.0 OR MIT
+
+package vaultcrypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoad_KeyFileFormats(t *testing.T) {
+	dir := t.TempDir()
+	raw := bytes.Repeat([]byte{7}, 32)
+
+	genPath := filepath.Join(dir, "generated.key")
+	if err := GenerateKeyFile(genPath); err != nil {
+		t.Fatal(err)
+	}
+	if fi, _ := os.Stat(genPath); fi.Mode().Perm() != 0o600 {
+		t.Errorf("generated key mode = %v", fi.Mode().Perm())
+	}
+	if err := GenerateKeyFile(genPath); err == nil {
+		t.Error("GenerateKeyFile overwrote an existing key")
+	}
+
+	files := map[string][]byte{
+		"raw.key": raw,
+		"b64.key": []byte(base64.StdEncoding.EncodeToString(raw) + "\n"),
+	}
+	for name, data := range files {
+		os.WriteFile(filepath.Join(dir, name), data, 0o600)
+	}
+	for _, name := range []string{"raw.key", "b64.key", "generated.key"} {
+		k, err := Load(filepath.Join(dir, name), "")
+		if err != nil || k == nil {
+			t.Fatalf("Load(%s) = %v, %v", name, k, err)
+		}
+	}
+
+	os.WriteFile(filepath.Join(dir, "short.key"), []byte("abc"), 0o600)
+	if _, err := Load(filepath.Join(dir, "short.key"), ""); err == nil {
+		t.Error("Load accepted a short key")
+	}
+	if _, err := Load(filepath.Join(dir, "missing.key"), ""); !errors.Is(err, ErrNoKey) {
+		t.Errorf("missing key file: err = %v, want ErrNoKey", err)
+	}
+	if k, err := Load("", ""); k != nil || err != nil {
+		t.Errorf("unconfigured Load = %v, %v", k, err)
+	}
+}
+
+func TestSealOpen_KeyFile(t *testing.T) {
+	k, _ := NewKey(bytes.Repeat([]byte{1}, 32))
+	plain := []byte(`{"type":"user","message":"customer data"}`)
+	sealed, err := k.Seal(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSealed(sealed) || bytes.Contains(sealed, []byte("customer")) {
+		t.Fatal("sealed output is not an envelope or leaks plaintext")
+	}
+	got, err := k.Open(sealed)
+	if err != nil || !bytes.Equal(got, plain) {
+		t.Fatalf("Open = %q, %v", got, err)
+	}
+
+	sealed[len(sealed)-1] ^= 1
+	if _, err := k.Open(sealed); err == nil || !strings.Contains(err.Error(), "authentication failed") {
+		t.Errorf("tampered Open: err = %v", err)
+	}
+	other, _ := NewKey(bytes.Repeat([]byte{2}, 32))
+	sealed[len(sealed)-1] ^= 1
+	if _, err := other.Open(sealed); err == nil {
+		t.Error("Open with the wrong key succeeded")
+	}
+}
+
+func TestSealOpen_Passphrase(t *testing.T) {
+	t.Setenv("VV_TEST_PASSPHRASE", "correct horse battery staple")
+	k, err := Load("", "VV_TEST_PASSPHRASE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := k.Seal([]byte("one"))
+	b, _ := k.Seal([]byte("two"))
+
+	// A fresh key from the same passphrase opens both; the shared salt
+	// means it derives once.
+	fresh, _ := Load("", "VV_TEST_PASSPHRASE")
+	for want, sealed := range map[string][]byte{"one": a, "two": b} {
+		got, err := fresh.Open(sealed)
+		if err != nil || string(got) != want {
+			t.Errorf("Open = %q, %v; want %q", got, err, want)
+		}
+	}
+	if len(fresh.derived) != 1 {
+		t.Errorf("derived %d keys, want 1", len(fresh.derived))
+	}
+
+	keyFileKey, _ := NewKey(bytes.Repeat([]byte{1}, 32))
+	if _, err := keyFileKey.Open(a); err == nil || !strings.Contains(err.Error(), "passphrase") {
+		t.Errorf("key-file key on passphrase envelope: err = %v", err)
+	}
+
+	t.Setenv("VV_TEST_PASSPHRASE", "")
+	if _, err := Load("", "VV_TEST_PASSPHRASE"); !errors.Is(err, ErrNoKey) {
+		t.Errorf("unset passphrase: err = %v, want ErrNoKey", err)
+	}
+}
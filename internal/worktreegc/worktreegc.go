@@ -16,6 +16,7 @@ package worktreegc
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
@@ -82,6 +83,10 @@ type Options struct {
 	// ForceUncaptured, when true, reaps even if the worktree branch
 	// has commits not present on any candidate parent.
 	ForceUncaptured bool
+	// Progress, when non-nil, is called before each locked worktree is
+	// decided and once after the last, with the count decided so far
+	// and the total.
+	Progress func(done, total int)
 }
 
 // verdict is the internal liveness-probe outcome enum used by the
@@ -115,6 +120,13 @@ var probePID = func(pid int) verdict {
 // holder process is dead and whose branch is captured by an
 // authoritative parent. See package doc for the full algorithm.
 func Run(repoPath string, opts Options) (Result, error) {
+	return RunContext(context.Background(), repoPath, opts)
+}
+
+// RunContext is Run with cancellation. ctx is checked before each
+// worktree, never mid-reap; on cancellation the actions taken so far
+// are returned alongside ctx's error.
+func RunContext(ctx context.Context, repoPath string, opts Options) (Result, error) {
 	// 1. Resolve absolute git-common-dir. Main worktree returns relative
 	// `.git`; linked worktrees return absolute. filepath.Abs normalizes
 	// so the lockfile-path hash is identical across siblings.
@@ -172,12 +184,26 @@ func Run(repoPath string, opts Options) (Result, error) {
 	}
 
 	res := Result{}
+	total := 0
+	for _, block := range blocks {
+		if !block.Bare && !block.Detached && block.Locked != "" {
+			total++
+		}
+	}
+	decided := 0
 
 	// 6. Per-block decision loop.
 	for _, block := range blocks {
 		if block.Bare || block.Detached || block.Locked == "" {
 			continue
 		}
+		if opts.Progress != nil {
+			opts.Progress(decided, total)
+		}
+		if err := ctx.Err(); err != nil {
+			return res, fmt.Errorf("worktreegc: %w", err)
+		}
+		decided++
 
 		worktreeName := filepath.Base(block.Worktree)
 		marker := Marker{
@@ -308,6 +334,9 @@ func Run(repoPath string, opts Options) (Result, error) {
 		res.Actions = append(res.Actions, Action{Marker: marker, Verdict: VerdictReaped})
 		res.Reaped++
 	}
+	if opts.Progress != nil {
+		opts.Progress(total, total)
+	}
 
 	return res, nil
 }
@@ -4,6 +4,7 @@
 package worktreegc
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
@@ -381,3 +382,47 @@ func TestRun_CherryErrors_SurfacesInDetail(t *testing.T) {
 		t.Errorf("Detail = %q, want substring %q", res.Actions[0].Detail, want)
 	}
 }
+
+func TestRunContext_ProgressAndCancel(t *testing.T) {
+	repo := gitx.InitTestRepo(t)
+	for _, id := range []string{"7777777777777777", "8888888888888888"} {
+		wtPath := gitx.AddWorktree(t, repo, "agent-"+id, "worktree-agent-"+id)
+		gitx.LockWorktree(t, wtPath, "claude agent agent-"+id+" (pid 99999)")
+	}
+	withProbePID(t, func(int) verdict { return verdictDead })
+
+	// Cancel once the first worktree is decided; the second must not be.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls []string
+	res, err := RunContext(ctx, repo, Options{
+		DryRun: true,
+		Progress: func(done, total int) {
+			calls = append(calls, fmt.Sprintf("%d/%d", done, total))
+			if done == 1 {
+				cancel()
+			}
+		},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if len(res.Actions) != 1 || res.Actions[0].Verdict != VerdictWouldReap {
+		t.Errorf("actions = %+v, want the first worktree's would-reap only", res.Actions)
+	}
+	if got := strings.Join(calls, " "); got != "0/2 1/2" {
+		t.Errorf("progress = %q, want %q", got, "0/2 1/2")
+	}
+
+	// Uncancelled, the run ends with a final done == total report.
+	calls = nil
+	if _, err := RunContext(context.Background(), repo, Options{
+		DryRun:   true,
+		Progress: func(done, total int) { calls = append(calls, fmt.Sprintf("%d/%d", done, total)) },
+	}); err != nil {
+		t.Fatalf("RunContext: %v", err)
+	}
+	if got := strings.Join(calls, " "); got != "0/2 1/2 2/2" {
+		t.Errorf("progress = %q, want %q", got, "0/2 1/2 2/2")
+	}
+}
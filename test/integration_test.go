@@ -2,6 +2,7 @@ package test
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -332,6 +333,16 @@ func runVVWithStdin(t *testing.T, env []string, stdin string, args ...string) (s
 	return outBuf.String(), errBuf.String(), err
 }
 
+// sortMCPResponses orders responses by their numeric JSON-RPC id. vv mcp
+// runs tool calls concurrently, so responses may arrive in any order.
+func sortMCPResponses(responses []map[string]any) {
+	sort.SliceStable(responses, func(i, j int) bool {
+		a, _ := responses[i]["id"].(float64)
+		b, _ := responses[j]["id"].(float64)
+		return a < b
+	})
+}
+
 // --- Integration Test ---
 
 func TestIntegration(t *testing.T) {
@@ -703,6 +714,133 @@ func TestIntegration(t *testing.T) {
 		}
 	})
 
+	// 8b. archive train-dict, then archive with the dictionary
+	t.Run("archive_train_dict", func(t *testing.T) {
+		stdout := mustRunVV(t, env, "archive", "train-dict", "--level", "19")
+		assertContains(t, stdout, "trained dictionary v0001", "train-dict stdout")
+		assertContains(t, stdout, "smaller)", "train-dict savings report")
+
+		archiveDir := filepath.Join(stateDir, "archive")
+		if !fileExists(filepath.Join(archiveDir, "dicts", "v0001.dict")) {
+			t.Fatal("dictionary not stored under archive/dicts")
+		}
+
+		// Re-archive one session so it is written with the dictionary;
+		// training again adds a version rather than replacing v0001.
+		archived, _ := filepath.Glob(filepath.Join(archiveDir, "*.jsonl.zst"))
+		if len(archived) == 0 {
+			t.Fatal("no archives to re-create")
+		}
+		if err := os.Remove(archived[0]); err != nil {
+			t.Fatal(err)
+		}
+		stdout = mustRunVV(t, env, "archive", "--level", "19")
+		assertContains(t, stdout, "dictionary: v0001", "archive with dictionary")
+
+		stdout = mustRunVV(t, env, "archive", "train-dict")
+		assertContains(t, stdout, "trained dictionary v0002", "second train-dict")
+	})
+
+	t.Run("archive_pack", func(t *testing.T) {
+		archiveDir := filepath.Join(stateDir, "archive")
+		loose, _ := filepath.Glob(filepath.Join(archiveDir, "*.jsonl.zst"))
+		if len(loose) == 0 {
+			t.Fatal("no loose archives to pack")
+		}
+
+		stdout := mustRunVV(t, env, "archive", "pack")
+		assertContains(t, stdout, fmt.Sprintf("packed: %d", len(loose)), "pack stdout")
+		assertContains(t, stdout, "errors: 0", "pack stdout")
+		if left, _ := filepath.Glob(filepath.Join(archiveDir, "*.jsonl.zst")); len(left) != 0 {
+			t.Errorf("loose archives left after pack: %v", left)
+		}
+		if packs, _ := filepath.Glob(filepath.Join(archiveDir, "packs", "*.pack")); len(packs) == 0 {
+			t.Fatal("no pack files written")
+		}
+
+		// Packed sessions still count as archived.
+		stdout = mustRunVV(t, env, "archive")
+		assertContains(t, stdout, "archived: 0 ", "archive after pack")
+
+		stdout = mustRunVV(t, env, "archive", "verify")
+		assertContains(t, stdout, "loose: 0 ", "verify stdout")
+		assertContains(t, stdout, "all archives verified", "verify stdout")
+	})
+
+	t.Run("archive_restore_and_prune", func(t *testing.T) {
+		// The original is still in place, so restoring over it needs --force.
+		_, stderr, err := runVV(t, env, "archive", "restore", "session-aaa-001")
+		if err == nil {
+			t.Fatal("restore over an existing original should fail without --force")
+		}
+		assertContains(t, stderr, "already exists", "restore refusal")
+
+		restoreDir := t.TempDir()
+		stdout := mustRunVV(t, env, "archive", "restore", "session-aaa-001", "--to", restoreDir)
+		assertContains(t, stdout, "restored session-aaa-001", "restore stdout")
+		got, err := os.ReadFile(filepath.Join(restoreDir, "session-aaa-001.jsonl"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, _ := os.ReadFile(a1Path); string(got) != string(want) {
+			t.Error("restored transcript differs from the original")
+		}
+
+		// Retention defaults to keeping everything.
+		stdout = mustRunVV(t, env, "archive", "prune", "--dry-run")
+		assertContains(t, stdout, "dry run — originals to delete: 0", "prune dry-run")
+		assertContains(t, stdout, "archives to delete: 0", "prune dry-run")
+	})
+
+	t.Run("encryption_key_and_decrypt", func(t *testing.T) {
+		keyPath := filepath.Join(t.TempDir(), "vault.key")
+		stdout := mustRunVV(t, env, "config", "gen-encryption-key", keyPath)
+		assertContains(t, stdout, "[encryption]", "gen-encryption-key stdout")
+		if fi, err := os.Stat(keyPath); err != nil || fi.Mode().Perm() != 0o600 {
+			t.Fatalf("key file: %v, %v", fi, err)
+		}
+		_, stderr, err := runVV(t, env, "config", "gen-encryption-key", keyPath)
+		if err == nil {
+			t.Fatal("gen-encryption-key replaced an existing key")
+		}
+		assertContains(t, stderr, "already exists", "gen-encryption-key refusal")
+
+		_, stderr, err = runVV(t, env, "decrypt", a1Path)
+		if err == nil {
+			t.Fatal("decrypt of a plaintext transcript should fail")
+		}
+		assertContains(t, stderr, "is not encrypted", "decrypt refusal")
+	})
+
+	t.Run("daemon_status_and_unit", func(t *testing.T) {
+		denv := append(append([]string{}, env...), "XDG_RUNTIME_DIR="+t.TempDir())
+		_, stderr, err := runVV(t, denv, "daemon", "status")
+		if err == nil {
+			t.Fatal("daemon status should fail with no daemon running")
+		}
+		assertContains(t, stderr, "not running", "daemon status")
+
+		stdout := mustRunVV(t, denv, "daemon", "install", "--print")
+		assertContains(t, stdout, " daemon\n", "unit ExecStart")
+		assertContains(t, stdout, "ExecReload=/bin/kill -HUP $MAINPID", "unit reload")
+	})
+
+	t.Run("mcp_serve_http_and_install_url", func(t *testing.T) {
+		_, stderr, err := runVV(t, env, "mcp", "serve", "--http", "0.0.0.0:0")
+		if err == nil {
+			t.Fatal("tokenless mcp serve on 0.0.0.0 should fail")
+		}
+		assertContains(t, stderr, "http_token", "tokenless non-loopback refusal")
+
+		home := t.TempDir()
+		os.MkdirAll(filepath.Join(home, ".claude"), 0o755)
+		henv := buildEnvWithHome(xdgConfigHome, home)
+		mustRunVV(t, henv, "mcp", "install", "--claude-only", "--url", "http://127.0.0.1:8765/mcp")
+		settings := readFile(t, filepath.Join(home, ".claude", "settings.json"))
+		assertContains(t, settings, `"url": "http://127.0.0.1:8765/mcp"`, "remote MCP entry url")
+		assertContains(t, settings, `"type": "http"`, "remote MCP entry type")
+	})
+
 	// 9. stop_checkpoint_then_session_end
 	t.Run("stop_checkpoint_then_session_end", func(t *testing.T) {
 		stopTranscriptPath := writeFixture(t, fixtureDir, "session-stop-001.jsonl", readTestdata(t, "stop-session.jsonl"))
@@ -1511,6 +1649,59 @@ func TestIntegration(t *testing.T) {
 		}
 	})
 
+	// 11b. reprocess --diff previews and selectively applies
+	t.Run("reprocess_diff", func(t *testing.T) {
+		absPath := findSessionNoteByID(t, vaultPath, stateDir, "session-aaa-001")
+		orig := readFile(t, absPath)
+		_, body, ok := strings.Cut(orig, "\n# ")
+		if !ok {
+			t.Fatalf("note has no title:\n%s", orig)
+		}
+		edited := strings.Replace(orig, "session_id:", "reviewed: true\nrating: 5\nsession_id:", 1)
+		edited = strings.Replace(edited, "\n# "+body, "\n# "+body+"\n## Scratch\n\nhand-written\n", 1)
+		if err := os.WriteFile(absPath, []byte(edited), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		stdout := mustRunVV(t, env, "reprocess", "--diff", "--only-changed-sections", "--project", "myproject")
+		assertContains(t, stdout, "Diff preview", "reprocess --diff banner")
+		assertContains(t, stdout, "#frontmatter", "reprocess --diff section label")
+		assertContains(t, stdout, "-reviewed: true", "reprocess --diff hunk")
+		assertNotContains(t, stdout, "-rating: 5", "user-owned key carried into render")
+		assertContains(t, stdout, "notes changed per section:", "reprocess --diff summary")
+		if readFile(t, absPath) != edited {
+			t.Fatal("reprocess --diff without --accept rewrote the note")
+		}
+
+		// Stale the index entry; --accept must refresh it like a full
+		// reprocess does.
+		idx := readIndex(t, stateDir)
+		idx["session-aaa-001"]["title"] = "stale title"
+		idx["session-aaa-001"]["summary"] = "stale summary"
+		data, err := json.Marshal(idx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(stateDir, "session-index.json"), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		stdout = mustRunVV(t, env, "reprocess", "--diff", "--accept", "frontmatter", "--project", "myproject")
+		assertNotContains(t, stdout, "applied: 0,", "reprocess --accept applied count")
+		note := readFile(t, absPath)
+		assertNotContains(t, note, "reviewed: true", "accepted frontmatter")
+		assertContains(t, note, "rating: 5", "user-owned key kept")
+		assertContains(t, note, "## Scratch\n\nhand-written", "unaccepted section kept")
+
+		entry := readIndex(t, stateDir)["session-aaa-001"]
+		if entry["title"] == "stale title" || entry["summary"] == "stale summary" {
+			t.Errorf("index entry not refreshed after --accept: title %q, summary %q", entry["title"], entry["summary"])
+		}
+		if rel, _ := filepath.Rel(vaultPath, absPath); entry["note_path"] != filepath.ToSlash(rel) && entry["note_path"] != absPath {
+			t.Errorf("note_path = %v, want the accepted note %s", entry["note_path"], rel)
+		}
+	})
+
 	// 12. MCP server
 	t.Run("mcp", func(t *testing.T) {
 		// Build a sequence of JSON-RPC requests, newline-delimited
@@ -1544,6 +1735,7 @@ func TestIntegration(t *testing.T) {
 			}
 			responses = append(responses, resp)
 		}
+		sortMCPResponses(responses)
 
 		// Response 0: initialize — should have serverInfo
 		if r := responses[0]["result"].(map[string]any); r["serverInfo"] == nil {
@@ -1559,6 +1751,7 @@ func TestIntegration(t *testing.T) {
 			"vv_search_sessions",
 			"vv_get_knowledge",
 			"vv_get_session_detail",
+			"vv_get_file_history",
 			"vv_get_friction_trends",
 			"vv_get_effectiveness",
 			"vv_capture_session",
@@ -1573,6 +1766,7 @@ func TestIntegration(t *testing.T) {
 			"vv_bootstrap_context",
 			"vv_list_learnings",
 			"vv_get_learning",
+			"vv_adr",
 			"vv_get_iterations",
 			"vv_get_project_root",
 			"vv_set_commit_msg",
@@ -1737,6 +1931,7 @@ Body content for the reference-type sample learning.
 			}
 			responses = append(responses, resp)
 		}
+		sortMCPResponses(responses)
 
 		// Response 0: initialize — must have serverInfo.
 		initResult, initOK := responses[0]["result"].(map[string]any)
@@ -1916,6 +2111,7 @@ Body content for the reference-type sample learning.
 			}
 			responses = append(responses, resp)
 		}
+		sortMCPResponses(responses)
 
 		// Response 0: initialize — must have serverInfo.
 		initResult, initOK := responses[0]["result"].(map[string]any)
@@ -2078,31 +2274,37 @@ Body content for the reference-type sample learning.
 			t.Fatalf("marshal get request: %v", err)
 		}
 
-		iterRequests := strings.Join([]string{
-			`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test"}}}`,
-			`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
-			string(appendReq),
-			string(getReq),
-		}, "\n")
-
-		iterStdout, iterStderr, err := runVVWithStdin(t, provEnv, iterRequests, "mcp")
-		if err != nil {
-			t.Fatalf("vv mcp (iteration path) failed: %v\nstdout: %s\nstderr: %s",
-				err, iterStdout, iterStderr)
-		}
-
-		iterLines := strings.Split(strings.TrimSpace(iterStdout), "\n")
-		if len(iterLines) != 3 {
-			t.Fatalf("iteration path: expected 3 response lines, got %d:\n%s",
-				len(iterLines), iterStdout)
-		}
+		// Tool calls on one connection run concurrently, so the read goes
+		// in a second `vv mcp` run to be sure it sees the append.
 		var iterResponses []map[string]any
-		for i, line := range iterLines {
-			var resp map[string]any
-			if jerr := json.Unmarshal([]byte(line), &resp); jerr != nil {
-				t.Fatalf("iteration response %d: invalid JSON: %v\nline: %s", i, jerr, line)
+		var iterStderr string
+		for i, call := range [][]byte{appendReq, getReq} {
+			iterRequests := strings.Join([]string{
+				`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test"}}}`,
+				`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+				string(call),
+			}, "\n")
+
+			iterStdout, stderr, err := runVVWithStdin(t, provEnv, iterRequests, "mcp")
+			if err != nil {
+				t.Fatalf("vv mcp (iteration path) failed: %v\nstdout: %s\nstderr: %s",
+					err, iterStdout, stderr)
+			}
+			iterStderr += stderr
+
+			iterLines := strings.Split(strings.TrimSpace(iterStdout), "\n")
+			if len(iterLines) != 2 {
+				t.Fatalf("iteration path: expected 2 response lines, got %d:\n%s",
+					len(iterLines), iterStdout)
+			}
+			// Keep one initialize response: [initialize, append, get].
+			for _, line := range iterLines[i:] {
+				var resp map[string]any
+				if jerr := json.Unmarshal([]byte(line), &resp); jerr != nil {
+					t.Fatalf("iteration response: invalid JSON: %v\nline: %s", jerr, line)
+				}
+				iterResponses = append(iterResponses, resp)
 			}
-			iterResponses = append(iterResponses, resp)
 		}
 
 		// Response 1: vv_append_iteration success.
@@ -3202,7 +3404,7 @@ func callVaultTool(t *testing.T, tool mcp.Tool, args any) (string, error) {
 		}
 		params = raw
 	}
-	return tool.Handler(params)
+	return tool.Handler(context.Background(), params)
 }
 
 func TestIntegration_VaultRead_HappyPath(t *testing.T) {